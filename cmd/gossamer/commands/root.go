@@ -102,6 +102,10 @@ func NewRootCommand() (*cobra.Command, error) {
 				return fmt.Errorf("failed to parse role: %s", err)
 			}
 
+			if err := parsePruning(); err != nil {
+				return fmt.Errorf("failed to parse state pruning: %s", err)
+			}
+
 			if err := parseTelemetryURL(); err != nil {
 				return fmt.Errorf("failed to parse telemetry-url: %s", err.Error())
 			}
@@ -238,10 +242,17 @@ func addBaseConfigFlags(cmd *cobra.Command) error {
 		"retain-blocks"); err != nil {
 		return fmt.Errorf("failed to add --retain-blocks flag: %s", err)
 	}
+	if err := addUint32FlagBindViper(cmd,
+		"transaction-retention",
+		config.BaseConfig.TransactionRetention,
+		"Number of finalised blocks for which the indexed transactions are retained, 0 retains them forever",
+		"transaction-retention"); err != nil {
+		return fmt.Errorf("failed to add --transaction-retention flag: %s", err)
+	}
 	cmd.Flags().StringVar(&pruning,
 		"state-pruning",
 		string(config.BaseConfig.Pruning),
-		"State trie online pruning")
+		"State trie online pruning mode, one of: archive, full")
 	if err := addBoolFlagBindViper(cmd,
 		"prometheus-external",
 		config.BaseConfig.PrometheusExternal,
@@ -363,6 +374,14 @@ func addNetworkFlags(cmd *cobra.Command) error {
 		return fmt.Errorf("failed to add --persistent-peers flag: %s", err)
 	}
 
+	if err := addBoolFlagBindViper(cmd,
+		"reserved-only",
+		config.Network.ReservedOnly,
+		"Only connect to the persistent and reserved peers",
+		"network.reserved-only"); err != nil {
+		return fmt.Errorf("failed to add --reserved-only flag: %s", err)
+	}
+
 	if err := addDurationFlagBindViper(cmd,
 		"discovery-interval",
 		config.Network.DiscoveryInterval,
@@ -477,6 +496,38 @@ func addRPCFlags(cmd *cobra.Command) error {
 		return fmt.Errorf("failed to add --ws-unsafe-external flag: %s", err)
 	}
 
+	if err := addBoolFlagBindViper(cmd,
+		"rpc-single-port",
+		config.RPC.SinglePort,
+		"Serve HTTP-RPC and websocket connections on the HTTP-RPC port",
+		"rpc.single-port"); err != nil {
+		return fmt.Errorf("failed to add --rpc-single-port flag: %s", err)
+	}
+
+	if err := addUint32FlagBindViper(cmd,
+		"rpc-max-request-size",
+		config.RPC.MaxRequestSize,
+		"Maximum size of an RPC request in megabytes, 0 for no limit",
+		"rpc.max-request-size"); err != nil {
+		return fmt.Errorf("failed to add --rpc-max-request-size flag: %s", err)
+	}
+
+	if err := addUint32FlagBindViper(cmd,
+		"rpc-max-subscriptions-per-connection",
+		config.RPC.MaxSubscriptionsPerConnection,
+		"Maximum number of subscriptions of a websocket connection, 0 for no limit",
+		"rpc.max-subscriptions-per-connection"); err != nil {
+		return fmt.Errorf("failed to add --rpc-max-subscriptions-per-connection flag: %s", err)
+	}
+
+	if err := addUint32FlagBindViper(cmd,
+		"rpc-max-concurrent-requests-per-connection",
+		config.RPC.MaxConcurrentRequestsPerConnection,
+		"Maximum number of requests of a connection handled at the same time, 0 for no limit",
+		"rpc.max-concurrent-requests-per-connection"); err != nil {
+		return fmt.Errorf("failed to add --rpc-max-concurrent-requests-per-connection flag: %s", err)
+	}
+
 	// dummy flag to conform with the substrate cli
 	cmd.Flags().String("rpc-cors",
 		"",
@@ -537,6 +588,22 @@ func addCoreFlags(cmd *cobra.Command) error {
 		return fmt.Errorf("failed to add --sync flag: %s", err)
 	}
 
+	if err := addUint32FlagBindViper(cmd,
+		"offchain-workers",
+		config.Core.OffchainWorkers,
+		"Maximum number of offchain workers running concurrently, 0 disables offchain workers",
+		"core.offchain-workers"); err != nil {
+		return fmt.Errorf("failed to add --offchain-workers flag: %s", err)
+	}
+
+	if err := addStringFlagBindViper(cmd,
+		"sealing",
+		config.Core.Sealing,
+		"block sealing mode [babe | manual | instant]",
+		"core.sealing"); err != nil {
+		return fmt.Errorf("failed to add --sealing flag: %s", err)
+	}
+
 	return nil
 }
 
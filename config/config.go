@@ -29,6 +29,9 @@
 	DefaultRetainBlocks = uint32(512)
 	// DefaultPruning is the default pruning strategy
 	DefaultPruning = pruner.Archive
+	// DefaultTransactionRetention is the default number of blocks to retain indexed transactions,
+	// zero retains them forever
+	DefaultTransactionRetention = uint32(0)
 
 	// defaultAccount is the default account key
 	defaultAccount = "alice"
@@ -37,6 +40,11 @@
 	DefaultRole = common.AuthorityRole
 	// DefaultWasmInterpreter is the default wasm interpreter
 	DefaultWasmInterpreter = wazero.Name
+	// DefaultOffchainWorkers is the default maximum number of offchain workers running concurrently,
+	// zero disables offchain workers
+	DefaultOffchainWorkers = uint32(0)
+	// DefaultSealing is the default block sealing mode
+	DefaultSealing = BABESealing
 
 	// DefaultNetworkPort is the default network port
 	DefaultNetworkPort = uint16(7001)
@@ -53,6 +61,14 @@
 	DefaultRPCHost = "localhost"
 	// DefaultWSPort is the default WS port
 	DefaultWSPort = uint32(8546)
+	// DefaultRPCMaxRequestSize is the default maximum size of an RPC request in megabytes
+	DefaultRPCMaxRequestSize = uint32(15)
+	// DefaultRPCMaxSubscriptionsPerConnection is the default maximum number of subscriptions
+	// of a websocket connection
+	DefaultRPCMaxSubscriptionsPerConnection = uint32(1024)
+	// DefaultRPCMaxConcurrentRequestsPerConnection is the default maximum number of requests
+	// of a connection handled at the same time
+	DefaultRPCMaxConcurrentRequestsPerConnection = uint32(64)
 
 	// DefaultPprofListenAddress is the default pprof listen address
 	DefaultPprofListenAddress = "localhost:6060"
@@ -66,6 +82,16 @@
 	DefaultSyncMode = "full"
 )
 
+// Block sealing modes
+const (
+	// BABESealing authors blocks in the BABE slots of the node
+	BABESealing = "babe"
+	// ManualSealing authors blocks on demand through the engine RPC module
+	ManualSealing = "manual"
+	// InstantSealing authors a block as soon as a transaction enters the queue
+	InstantSealing = "instant"
+)
+
 // DefaultRPCModules the default RPC modules
 var DefaultRPCModules = []string{
 	"system",
@@ -78,6 +104,12 @@
 	"childstate",
 	"syncstate",
 	"payment",
+	"chainHead",
+	"chainSpec",
+	"transaction",
+	"transactionWatch",
+	"archive",
+	"babe",
 }
 
 // Config defines the configuration for the gossamer node
@@ -128,17 +160,18 @@ func (cfg *Config) ValidateBasic() error {
 
 // BaseConfig is to marshal/unmarshal toml global config vars
 type BaseConfig struct {
-	Name               string                      `mapstructure:"name,omitempty"`
-	ID                 string                      `mapstructure:"id,omitempty"`
-	BasePath           string                      `mapstructure:"base-path,omitempty"`
-	ChainSpec          string                      `mapstructure:"chain-spec,omitempty"`
-	LogLevel           string                      `mapstructure:"log-level,omitempty"`
-	PrometheusPort     uint32                      `mapstructure:"prometheus-port,omitempty"`
-	RetainBlocks       uint32                      `mapstructure:"retain-blocks,omitempty"`
-	Pruning            pruner.Mode                 `mapstructure:"pruning,omitempty"`
-	PrometheusExternal bool                        `mapstructure:"prometheus-external,omitempty"`
-	NoTelemetry        bool                        `mapstructure:"no-telemetry"`
-	TelemetryURLs      []genesis.TelemetryEndpoint `mapstructure:"telemetry-urls,omitempty"`
+	Name                 string                      `mapstructure:"name,omitempty"`
+	ID                   string                      `mapstructure:"id,omitempty"`
+	BasePath             string                      `mapstructure:"base-path,omitempty"`
+	ChainSpec            string                      `mapstructure:"chain-spec,omitempty"`
+	LogLevel             string                      `mapstructure:"log-level,omitempty"`
+	PrometheusPort       uint32                      `mapstructure:"prometheus-port,omitempty"`
+	RetainBlocks         uint32                      `mapstructure:"retain-blocks,omitempty"`
+	Pruning              pruner.Mode                 `mapstructure:"pruning,omitempty"`
+	TransactionRetention uint32                      `mapstructure:"transaction-retention,omitempty"`
+	PrometheusExternal   bool                        `mapstructure:"prometheus-external,omitempty"`
+	NoTelemetry          bool                        `mapstructure:"no-telemetry"`
+	TelemetryURLs        []genesis.TelemetryEndpoint `mapstructure:"telemetry-urls,omitempty"`
 }
 
 // SystemConfig represents the system configuration
@@ -159,6 +192,9 @@ type LogConfig struct {
 	Babe    string `mapstructure:"babe,omitempty"`
 	Grandpa string `mapstructure:"grandpa,omitempty"`
 	Wasmer  string `mapstructure:"wasmer,omitempty"`
+	// RuntimeTargets are the comma separated log levels of the runtime log targets
+	// and of their sub targets, such as 'runtime::staking=debug'
+	RuntimeTargets string `mapstructure:"runtime-targets,omitempty"`
 }
 
 // AccountConfig is to marshal/unmarshal account config vars
@@ -177,6 +213,7 @@ type NetworkConfig struct {
 	MinPeers          int           `mapstructure:"min-peers"`
 	MaxPeers          int           `mapstructure:"max-peers"`
 	PersistentPeers   []string      `mapstructure:"persistent-peers"`
+	ReservedOnly      bool          `mapstructure:"reserved-only"`
 	DiscoveryInterval time.Duration `mapstructure:"discovery-interval"`
 	PublicIP          string        `mapstructure:"public-ip"`
 	PublicDNS         string        `mapstructure:"public-dns"`
@@ -192,6 +229,8 @@ type CoreConfig struct {
 	WasmInterpreter  string             `mapstructure:"wasm-interpreter,omitempty"`
 	GrandpaInterval  time.Duration      `mapstructure:"grandpa-interval,omitempty"`
 	Sync             string             `mapstructure:"sync,omitempty"`
+	OffchainWorkers  uint32             `mapstructure:"offchain-workers"`
+	Sealing          string             `mapstructure:"sealing,omitempty"`
 }
 
 // StateConfig contains the configuration for the state.
@@ -210,6 +249,11 @@ type RPCConfig struct {
 	WSPort            uint32   `mapstructure:"ws-port,omitempty"`
 	WSExternal        bool     `mapstructure:"ws-external,omitempty"`
 	UnsafeWSExternal  bool     `mapstructure:"unsafe-ws-external,omitempty"`
+	SinglePort        bool     `mapstructure:"single-port,omitempty"`
+
+	MaxRequestSize                     uint32 `mapstructure:"max-request-size,omitempty"`
+	MaxSubscriptionsPerConnection      uint32 `mapstructure:"max-subscriptions-per-connection,omitempty"`
+	MaxConcurrentRequestsPerConnection uint32 `mapstructure:"max-concurrent-requests-per-connection,omitempty"`
 }
 
 // PprofConfig contains the configuration for Pprof.
@@ -237,6 +281,9 @@ func (b *BaseConfig) ValidateBasic() error {
 	if b.PrometheusPort == 0 {
 		return fmt.Errorf("prometheus port cannot be empty")
 	}
+	if !b.Pruning.IsValid() {
+		return fmt.Errorf("invalid pruning mode: %s", b.Pruning)
+	}
 	if uint32Max < b.RetainBlocks {
 		return fmt.Errorf(
 			"retain-blocks value overflows uint32 boundaries, must be less than or equal to: %d",
@@ -284,6 +331,11 @@ func (c *CoreConfig) ValidateBasic() error {
 	if c.WasmInterpreter != wazero.Name {
 		return fmt.Errorf("wasm-interpreter is invalid")
 	}
+	switch c.Sealing {
+	case "", BABESealing, ManualSealing, InstantSealing:
+	default:
+		return fmt.Errorf("sealing is invalid")
+	}
 
 	return nil
 }
@@ -303,7 +355,7 @@ func (r *RPCConfig) ValidateBasic() error {
 			return fmt.Errorf("host cannot be empty")
 		}
 	}
-	if r.IsWSEnabled() && r.WSPort == 0 {
+	if r.IsWSEnabled() && !r.SinglePort && r.WSPort == 0 {
 		return fmt.Errorf("ws port cannot be empty")
 	}
 
@@ -333,17 +385,18 @@ func (r *RPCConfig) IsWSEnabled() bool {
 func DefaultConfig() *Config {
 	return &Config{
 		BaseConfig: BaseConfig{
-			Name:               "Gossamer",
-			ID:                 "gssmr",
-			BasePath:           xdg.DataHome + "gossamer",
-			ChainSpec:          "",
-			LogLevel:           DefaultLogLevel,
-			PrometheusPort:     DefaultPrometheusPort,
-			RetainBlocks:       DefaultRetainBlocks,
-			Pruning:            DefaultPruning,
-			PrometheusExternal: false,
-			NoTelemetry:        false,
-			TelemetryURLs:      nil,
+			Name:                 "Gossamer",
+			ID:                   "gssmr",
+			BasePath:             xdg.DataHome + "gossamer",
+			ChainSpec:            "",
+			LogLevel:             DefaultLogLevel,
+			PrometheusPort:       DefaultPrometheusPort,
+			RetainBlocks:         DefaultRetainBlocks,
+			Pruning:              DefaultPruning,
+			TransactionRetention: DefaultTransactionRetention,
+			PrometheusExternal:   false,
+			NoTelemetry:          false,
+			TelemetryURLs:        nil,
 		},
 		Log: &LogConfig{
 			Core:    DefaultLogLevel,
@@ -368,6 +421,8 @@ func DefaultConfig() *Config {
 			WasmInterpreter:  DefaultWasmInterpreter,
 			GrandpaInterval:  DefaultDiscoveryInterval,
 			Sync:             DefaultSyncMode,
+			OffchainWorkers:  DefaultOffchainWorkers,
+			Sealing:          DefaultSealing,
 		},
 		Network: &NetworkConfig{
 			Port:              DefaultNetworkPort,
@@ -378,6 +433,7 @@ func DefaultConfig() *Config {
 			MinPeers:          DefaultMinPeers,
 			MaxPeers:          DefaultMaxPeers,
 			PersistentPeers:   nil,
+			ReservedOnly:      false,
 			DiscoveryInterval: DefaultDiscoveryInterval,
 			PublicIP:          "",
 			PublicDNS:         "",
@@ -397,6 +453,11 @@ func DefaultConfig() *Config {
 			WSPort:            DefaultWSPort,
 			WSExternal:        false,
 			UnsafeWSExternal:  false,
+			SinglePort:        false,
+
+			MaxRequestSize:                     DefaultRPCMaxRequestSize,
+			MaxSubscriptionsPerConnection:      DefaultRPCMaxSubscriptionsPerConnection,
+			MaxConcurrentRequestsPerConnection: DefaultRPCMaxConcurrentRequestsPerConnection,
 		},
 		Pprof: &PprofConfig{
 			Enabled:          false,
@@ -415,17 +476,18 @@ func DefaultConfig() *Config {
 func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 	return &Config{
 		BaseConfig: BaseConfig{
-			Name:               nodeSpec.Name,
-			ID:                 nodeSpec.ID,
-			BasePath:           xdg.DataHome + "gossamer",
-			ChainSpec:          "",
-			LogLevel:           DefaultLogLevel,
-			PrometheusPort:     uint32(9876),
-			RetainBlocks:       DefaultRetainBlocks,
-			Pruning:            DefaultPruning,
-			PrometheusExternal: false,
-			NoTelemetry:        false,
-			TelemetryURLs:      nil,
+			Name:                 nodeSpec.Name,
+			ID:                   nodeSpec.ID,
+			BasePath:             xdg.DataHome + "gossamer",
+			ChainSpec:            "",
+			LogLevel:             DefaultLogLevel,
+			PrometheusPort:       uint32(9876),
+			RetainBlocks:         DefaultRetainBlocks,
+			Pruning:              DefaultPruning,
+			TransactionRetention: DefaultTransactionRetention,
+			PrometheusExternal:   false,
+			NoTelemetry:          false,
+			TelemetryURLs:        nil,
 		},
 		Log: &LogConfig{
 			Core:    DefaultLogLevel,
@@ -450,6 +512,8 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 			WasmInterpreter:  DefaultWasmInterpreter,
 			GrandpaInterval:  DefaultDiscoveryInterval,
 			Sync:             DefaultSyncMode,
+			OffchainWorkers:  DefaultOffchainWorkers,
+			Sealing:          DefaultSealing,
 		},
 		Network: &NetworkConfig{
 			Port:              DefaultNetworkPort,
@@ -460,6 +524,7 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 			MinPeers:          DefaultMinPeers,
 			MaxPeers:          DefaultMaxPeers,
 			PersistentPeers:   nil,
+			ReservedOnly:      false,
 			DiscoveryInterval: DefaultDiscoveryInterval,
 			PublicIP:          "",
 			PublicDNS:         "",
@@ -479,6 +544,11 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 			WSPort:            DefaultWSPort,
 			WSExternal:        false,
 			UnsafeWSExternal:  false,
+			SinglePort:        false,
+
+			MaxRequestSize:                     DefaultRPCMaxRequestSize,
+			MaxSubscriptionsPerConnection:      DefaultRPCMaxSubscriptionsPerConnection,
+			MaxConcurrentRequestsPerConnection: DefaultRPCMaxConcurrentRequestsPerConnection,
 		},
 		Pprof: &PprofConfig{
 			Enabled:          false,
@@ -497,29 +567,31 @@ func DefaultConfigFromSpec(nodeSpec *genesis.Genesis) *Config {
 func Copy(c *Config) Config {
 	return Config{
 		BaseConfig: BaseConfig{
-			Name:               c.BaseConfig.Name,
-			ID:                 c.BaseConfig.ID,
-			BasePath:           c.BaseConfig.BasePath,
-			ChainSpec:          c.BaseConfig.ChainSpec,
-			LogLevel:           c.BaseConfig.LogLevel,
-			PrometheusPort:     c.PrometheusPort,
-			RetainBlocks:       c.RetainBlocks,
-			Pruning:            c.Pruning,
-			PrometheusExternal: c.PrometheusExternal,
-			NoTelemetry:        c.NoTelemetry,
-			TelemetryURLs:      c.TelemetryURLs,
+			Name:                 c.BaseConfig.Name,
+			ID:                   c.BaseConfig.ID,
+			BasePath:             c.BaseConfig.BasePath,
+			ChainSpec:            c.BaseConfig.ChainSpec,
+			LogLevel:             c.BaseConfig.LogLevel,
+			PrometheusPort:       c.PrometheusPort,
+			RetainBlocks:         c.RetainBlocks,
+			Pruning:              c.Pruning,
+			TransactionRetention: c.TransactionRetention,
+			PrometheusExternal:   c.PrometheusExternal,
+			NoTelemetry:          c.NoTelemetry,
+			TelemetryURLs:        c.TelemetryURLs,
 		},
 		Log: &LogConfig{
-			Core:    c.Log.Core,
-			Digest:  c.Log.Digest,
-			Sync:    c.Log.Sync,
-			Network: c.Log.Network,
-			RPC:     c.Log.RPC,
-			State:   c.Log.State,
-			Runtime: c.Log.Runtime,
-			Babe:    c.Log.Babe,
-			Grandpa: c.Log.Grandpa,
-			Wasmer:  c.Log.Wasmer,
+			Core:           c.Log.Core,
+			Digest:         c.Log.Digest,
+			Sync:           c.Log.Sync,
+			Network:        c.Log.Network,
+			RPC:            c.Log.RPC,
+			State:          c.Log.State,
+			Runtime:        c.Log.Runtime,
+			Babe:           c.Log.Babe,
+			Grandpa:        c.Log.Grandpa,
+			Wasmer:         c.Log.Wasmer,
+			RuntimeTargets: c.Log.RuntimeTargets,
 		},
 		Account: &AccountConfig{
 			Key:    c.Account.Key,
@@ -532,6 +604,8 @@ func Copy(c *Config) Config {
 			WasmInterpreter:  c.Core.WasmInterpreter,
 			GrandpaInterval:  c.Core.GrandpaInterval,
 			Sync:             c.Core.Sync,
+			OffchainWorkers:  c.Core.OffchainWorkers,
+			Sealing:          c.Core.Sealing,
 		},
 		Network: &NetworkConfig{
 			Port:              c.Network.Port,
@@ -542,6 +616,7 @@ func Copy(c *Config) Config {
 			MinPeers:          c.Network.MinPeers,
 			MaxPeers:          c.Network.MaxPeers,
 			PersistentPeers:   c.Network.PersistentPeers,
+			ReservedOnly:      c.Network.ReservedOnly,
 			DiscoveryInterval: c.Network.DiscoveryInterval,
 			PublicIP:          c.Network.PublicIP,
 			PublicDNS:         c.Network.PublicDNS,
@@ -561,6 +636,11 @@ func Copy(c *Config) Config {
 			WSPort:            c.RPC.WSPort,
 			WSExternal:        c.RPC.WSExternal,
 			UnsafeWSExternal:  c.RPC.UnsafeWSExternal,
+			SinglePort:        c.RPC.SinglePort,
+
+			MaxRequestSize:                     c.RPC.MaxRequestSize,
+			MaxSubscriptionsPerConnection:      c.RPC.MaxSubscriptionsPerConnection,
+			MaxConcurrentRequestsPerConnection: c.RPC.MaxConcurrentRequestsPerConnection,
 		},
 		Pprof: &PprofConfig{
 			Enabled:          c.Pprof.Enabled,
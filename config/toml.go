@@ -90,10 +90,15 @@ func WriteConfigFile(basePath string, config *Config) error {
 # Defaults to 512
 retain-blocks = {{ .BaseConfig.RetainBlocks }}
 
-# State trie online pruning mode
+# State trie online pruning mode, one of: archive, full
+# In full mode the state of the finalised blocks older than retain-blocks is deleted
 # Defaults to "archive"
 pruning = "{{ .BaseConfig.Pruning }}"
 
+# Number of finalised blocks for which the indexed transactions are retained
+# Defaults to 0, which retains them forever
+transaction-retention = {{ .BaseConfig.TransactionRetention }}
+
 # Disable connecting to the Substrate telemetry server
 # Defaults to false
 no-telemetry = {{ .BaseConfig.NoTelemetry }}
@@ -146,6 +151,11 @@ func WriteConfigFile(basePath string, config *Config) error {
 # Runtime module log level
 runtime = "{{ .Log.Runtime }}"
 
+# Comma separated log levels of the runtime log targets, overriding the runtime
+# module log level for these targets and their sub targets
+# For example: "runtime::staking=debug,runtime::system=trace"
+runtime-targets = "{{ .Log.RuntimeTargets }}"
+
 # BABE module log level
 babe = "{{ .Log.Babe }}"
 
@@ -201,6 +211,10 @@ func WriteConfigFile(basePath string, config *Config) error {
 # Comma separated list of peers to always keep connected to
 persistent-peers = "{{ StringsJoin .Network.PersistentPeers ", " }}"
 
+# Only connect to the persistent and reserved peers
+# Defaults to false
+reserved-only = {{ .Network.ReservedOnly }}
+
 # Interval to perform peer discovery in duration
 # Format: "10s", "1m", "1h"
 discovery-interval = "{{ .Network.DiscoveryInterval }}"
@@ -242,6 +256,14 @@ func WriteConfigFile(basePath string, config *Config) error {
 # Grandpa interval
 grandpa-interval = "{{ .Core.GrandpaInterval }}"
 
+# Maximum number of offchain workers running concurrently
+# Defaults to 0, which disables offchain workers
+offchain-workers = {{ .Core.OffchainWorkers }}
+
+# Block sealing mode, one of "babe", "manual" or "instant"
+# Defaults to "babe"
+sealing = "{{ .Core.Sealing }}"
+
 #######################################################
 ###            State Configuration Options          ###
 #######################################################
@@ -291,6 +313,22 @@ func WriteConfigFile(basePath string, config *Config) error {
 # Defaults to false
 unsafe-ws-external = {{ .RPC.UnsafeWSExternal }}
 
+# Serve HTTP-RPC and websocket connections on the HTTP-RPC port
+# Defaults to false
+single-port = {{ .RPC.SinglePort }}
+
+# Maximum size of an RPC request in megabytes, 0 for no limit
+# Defaults to 15
+max-request-size = {{ .RPC.MaxRequestSize }}
+
+# Maximum number of subscriptions of a websocket connection, 0 for no limit
+# Defaults to 1024
+max-subscriptions-per-connection = {{ .RPC.MaxSubscriptionsPerConnection }}
+
+# Maximum number of requests of a connection handled at the same time, 0 for no limit
+# Defaults to 64
+max-concurrent-requests-per-connection = {{ .RPC.MaxConcurrentRequestsPerConnection }}
+
 #######################################################
 ###            PPROF Configuration Options          ###
 #######################################################
@@ -97,6 +97,7 @@ type NetworkConfig struct {
 	MinPeers          int
 	MaxPeers          int
 	PersistentPeers   []string
+	ReservedOnly      bool
 	DiscoveryInterval time.Duration
 	PublicIP          string
 	PublicDNS         string
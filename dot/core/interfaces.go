@@ -33,6 +33,7 @@ type BlockState interface {
 	GetBlockStateRoot(bhash common.Hash) (common.Hash, error)
 	RangeInMemory(start, end common.Hash) ([]common.Hash, error)
 	GetBlockBody(hash common.Hash) (*types.Body, error)
+	SetIndexedTransactions(block *types.Block, operations []rtstorage.IndexOperation) error
 	HandleRuntimeChanges(newState *rtstorage.TrieState, in runtime.Instance, bHash common.Hash) error
 	GetRuntime(blockHash common.Hash) (instance runtime.Instance, err error)
 	StoreRuntime(blockHash common.Hash, runtime runtime.Instance)
@@ -67,11 +68,14 @@ type GrandpaState interface {
 
 // TransactionState is the interface for transaction state methods
 type TransactionState interface {
-	Push(vt *transaction.ValidTransaction) (common.Hash, error)
-	AddToPool(vt *transaction.ValidTransaction) common.Hash
+	AddToPool(vt *transaction.ValidTransaction) (common.Hash, error)
 	RemoveExtrinsic(ext types.Extrinsic)
-	RemoveExtrinsicFromPool(ext types.Extrinsic)
-	PendingInPool() []*transaction.ValidTransaction
+	RemoveExtrinsicByHash(hash common.Hash) (removed bool)
+	Pending() []*transaction.ValidTransaction
+	Oldest(limit int) []*transaction.ValidTransaction
+	UpdateValidity(hash common.Hash, validity *transaction.Validity) (updated bool)
+	SetBestBlockNumber(blockNumber uint)
+	NotifyStatus(ext types.Extrinsic, notification transaction.StatusNotification)
 	Exists(ext types.Extrinsic) bool
 }
 
@@ -4,6 +4,7 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ChainSafe/gossamer/dot/network"
@@ -42,7 +43,10 @@ func (s *Service) validateTransaction(head *types.Header, rt runtime.Instance,
 	vtx := transaction.NewValidTransaction(tx, validity)
 
 	// push to the transaction queue of BABE session
-	hash := s.transactionState.AddToPool(vtx)
+	hash, err := s.transactionState.AddToPool(vtx)
+	if err != nil {
+		return nil, fmt.Errorf("adding transaction to pool: %w", err)
+	}
 	logger.Tracef("added transaction with hash %s to pool", hash)
 
 	return validity, nil
@@ -73,10 +77,15 @@ func (s *Service) HandleTransactionMessage(peerID peer.ID, msg *network.Transact
 	if err != nil {
 		return false, err
 	}
+	rt = runtime.WithLimits(s.ctx, rt, runtime.ValidationCall)
 
 	allTxnsAreValid := true
 	for _, tx := range txs {
 		validity, err := s.validateTransaction(head, rt, tx)
+		if errors.Is(err, transaction.ErrTransactionExists) || errors.Is(err, transaction.ErrTooLowPriority) {
+			// the transaction is valid but already known or superseded, so it is not propagated
+			continue
+		}
 		if err != nil {
 			allTxnsAreValid = false
 			switch err.(type) {
@@ -111,5 +120,5 @@ func (s *Service) HandleTransactionMessage(peerID peer.ID, msg *network.Transact
 
 // TransactionsCount returns number for pending transactions in pool
 func (s *Service) TransactionsCount() int {
-	return len(s.transactionState.PendingInPool())
+	return len(s.transactionState.Pending())
 }
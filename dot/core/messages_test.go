@@ -84,8 +84,8 @@ func TestService_TransactionsCount(t *testing.T) {
 
 	txs := []*transaction.ValidTransaction{nil, nil}
 
-	mockTxnStateEmpty.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{})
-	mockTxnState.EXPECT().PendingInPool().Return(txs)
+	mockTxnStateEmpty.EXPECT().Pending().Return([]*transaction.ValidTransaction{})
+	mockTxnState.EXPECT().Pending().Return(txs)
 
 	tests := []struct {
 		name    string
@@ -372,7 +372,7 @@ type args struct {
 			}
 			if tt.mockTxnState != nil {
 				txnState := NewMockTransactionState(ctrl)
-				txnState.EXPECT().AddToPool(tt.mockTxnState.input).Return(tt.mockTxnState.hash)
+				txnState.EXPECT().AddToPool(tt.mockTxnState.input).Return(tt.mockTxnState.hash, nil)
 				s.transactionState = txnState
 			}
 			if tt.mockRuntime != nil {
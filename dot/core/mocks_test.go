@@ -208,6 +208,20 @@ func (mr *MockBlockStateMockRecorder) RangeInMemory(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RangeInMemory", reflect.TypeOf((*MockBlockState)(nil).RangeInMemory), arg0, arg1)
 }
 
+// SetIndexedTransactions mocks base method.
+func (m *MockBlockState) SetIndexedTransactions(arg0 *types.Block, arg1 []storage.IndexOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIndexedTransactions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetIndexedTransactions indicates an expected call of SetIndexedTransactions.
+func (mr *MockBlockStateMockRecorder) SetIndexedTransactions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIndexedTransactions", reflect.TypeOf((*MockBlockState)(nil).SetIndexedTransactions), arg0, arg1)
+}
+
 // StoreRuntime mocks base method.
 func (m *MockBlockState) StoreRuntime(arg0 common.Hash, arg1 runtime.Instance) {
 	m.ctrl.T.Helper()
@@ -350,11 +364,12 @@ func (m *MockTransactionState) EXPECT() *MockTransactionStateMockRecorder {
 }
 
 // AddToPool mocks base method.
-func (m *MockTransactionState) AddToPool(arg0 *transaction.ValidTransaction) common.Hash {
+func (m *MockTransactionState) AddToPool(arg0 *transaction.ValidTransaction) (common.Hash, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddToPool", arg0)
 	ret0, _ := ret[0].(common.Hash)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddToPool indicates an expected call of AddToPool.
@@ -377,33 +392,44 @@ func (mr *MockTransactionStateMockRecorder) Exists(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockTransactionState)(nil).Exists), arg0)
 }
 
-// PendingInPool mocks base method.
-func (m *MockTransactionState) PendingInPool() []*transaction.ValidTransaction {
+// NotifyStatus mocks base method.
+func (m *MockTransactionState) NotifyStatus(arg0 types.Extrinsic, arg1 transaction.StatusNotification) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PendingInPool")
+	m.ctrl.Call(m, "NotifyStatus", arg0, arg1)
+}
+
+// NotifyStatus indicates an expected call of NotifyStatus.
+func (mr *MockTransactionStateMockRecorder) NotifyStatus(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyStatus", reflect.TypeOf((*MockTransactionState)(nil).NotifyStatus), arg0, arg1)
+}
+
+// Oldest mocks base method.
+func (m *MockTransactionState) Oldest(arg0 int) []*transaction.ValidTransaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Oldest", arg0)
 	ret0, _ := ret[0].([]*transaction.ValidTransaction)
 	return ret0
 }
 
-// PendingInPool indicates an expected call of PendingInPool.
-func (mr *MockTransactionStateMockRecorder) PendingInPool() *gomock.Call {
+// Oldest indicates an expected call of Oldest.
+func (mr *MockTransactionStateMockRecorder) Oldest(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingInPool", reflect.TypeOf((*MockTransactionState)(nil).PendingInPool))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Oldest", reflect.TypeOf((*MockTransactionState)(nil).Oldest), arg0)
 }
 
-// Push mocks base method.
-func (m *MockTransactionState) Push(arg0 *transaction.ValidTransaction) (common.Hash, error) {
+// Pending mocks base method.
+func (m *MockTransactionState) Pending() []*transaction.ValidTransaction {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Push", arg0)
-	ret0, _ := ret[0].(common.Hash)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Pending")
+	ret0, _ := ret[0].([]*transaction.ValidTransaction)
+	return ret0
 }
 
-// Push indicates an expected call of Push.
-func (mr *MockTransactionStateMockRecorder) Push(arg0 any) *gomock.Call {
+// Pending indicates an expected call of Pending.
+func (mr *MockTransactionStateMockRecorder) Pending() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockTransactionState)(nil).Push), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockTransactionState)(nil).Pending))
 }
 
 // RemoveExtrinsic mocks base method.
@@ -418,16 +444,44 @@ func (mr *MockTransactionStateMockRecorder) RemoveExtrinsic(arg0 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExtrinsic", reflect.TypeOf((*MockTransactionState)(nil).RemoveExtrinsic), arg0)
 }
 
-// RemoveExtrinsicFromPool mocks base method.
-func (m *MockTransactionState) RemoveExtrinsicFromPool(arg0 types.Extrinsic) {
+// RemoveExtrinsicByHash mocks base method.
+func (m *MockTransactionState) RemoveExtrinsicByHash(arg0 common.Hash) bool {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "RemoveExtrinsicFromPool", arg0)
+	ret := m.ctrl.Call(m, "RemoveExtrinsicByHash", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// RemoveExtrinsicByHash indicates an expected call of RemoveExtrinsicByHash.
+func (mr *MockTransactionStateMockRecorder) RemoveExtrinsicByHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExtrinsicByHash", reflect.TypeOf((*MockTransactionState)(nil).RemoveExtrinsicByHash), arg0)
+}
+
+// SetBestBlockNumber mocks base method.
+func (m *MockTransactionState) SetBestBlockNumber(arg0 uint) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetBestBlockNumber", arg0)
+}
+
+// SetBestBlockNumber indicates an expected call of SetBestBlockNumber.
+func (mr *MockTransactionStateMockRecorder) SetBestBlockNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBestBlockNumber", reflect.TypeOf((*MockTransactionState)(nil).SetBestBlockNumber), arg0)
+}
+
+// UpdateValidity mocks base method.
+func (m *MockTransactionState) UpdateValidity(arg0 common.Hash, arg1 *transaction.Validity) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateValidity", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
 }
 
-// RemoveExtrinsicFromPool indicates an expected call of RemoveExtrinsicFromPool.
-func (mr *MockTransactionStateMockRecorder) RemoveExtrinsicFromPool(arg0 any) *gomock.Call {
+// UpdateValidity indicates an expected call of UpdateValidity.
+func (mr *MockTransactionStateMockRecorder) UpdateValidity(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExtrinsicFromPool", reflect.TypeOf((*MockTransactionState)(nil).RemoveExtrinsicFromPool), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateValidity", reflect.TypeOf((*MockTransactionState)(nil).UpdateValidity), arg0, arg1)
 }
 
 // MockNetwork is a mock of Network interface.
@@ -30,6 +30,10 @@
 	logger = log.NewFromGlobal(log.AddContext("pkg", "core"))
 )
 
+// revalidationBatchSize is the maximum number of transactions of the pool
+// re-validated after each block import
+const revalidationBatchSize = 128
+
 // QueryKeyValueChanges represents the key-value data inside a block storage
 type QueryKeyValueChanges map[string]string
 
@@ -57,6 +61,18 @@ type Service struct {
 	// Keystore
 	keys          *keystore.GlobalKeystore
 	onBlockImport BlockImportDigestHandler
+
+	// offchainWorkers limits the number of offchain workers running concurrently,
+	// it is nil if offchain workers are disabled.
+	offchainWorkers     chan struct{}
+	offchainWorkersLock sync.Mutex
+	offchainWorkersWG   sync.WaitGroup
+
+	// compilationCache is the cache the runtime codes are compiled in
+	compilationCache *wazero_runtime.CompilationCache
+
+	// logFilter filters the messages logged by the new runtime instances
+	logFilter *runtime.LogFilter
 }
 
 // Config holds the configuration for the core Service.
@@ -75,6 +91,18 @@ type Config struct {
 	CodeSubstitutes      map[common.Hash]string
 	CodeSubstitutedState CodeSubstitutedState
 	OnBlockImport        BlockImportDigestHandler
+
+	// OffchainWorkers is the maximum number of offchain workers running
+	// concurrently, zero disables offchain workers.
+	OffchainWorkers uint32
+
+	// CompilationCache is the cache the runtime codes are compiled in,
+	// they are compiled in memory if it is nil.
+	CompilationCache *wazero_runtime.CompilationCache
+
+	// LogFilter filters the messages logged by the new runtime instances, they
+	// are filtered by the log level of each instance if it is nil.
+	LogFilter *runtime.LogFilter
 }
 
 // NewService returns a new core service that connects the runtime, BABE
@@ -99,6 +127,12 @@ func NewService(cfg *Config) (*Service, error) {
 		codeSubstitutedState: cfg.CodeSubstitutedState,
 		onBlockImport:        cfg.OnBlockImport,
 		epochState:           cfg.EpochState,
+		compilationCache:     cfg.CompilationCache,
+		logFilter:            cfg.LogFilter,
+	}
+
+	if cfg.OffchainWorkers > 0 {
+		srv.offchainWorkers = make(chan struct{}, cfg.OffchainWorkers)
 	}
 
 	return srv, nil
@@ -110,13 +144,16 @@ func (s *Service) Start() error {
 	return nil
 }
 
-// Stop stops the core service
+// Stop stops the core service and waits for the running offchain workers to finish.
 func (s *Service) Stop() error {
 	s.lock.Lock()
-	defer s.lock.Unlock()
-
 	s.cancel()
 	close(s.blockAddCh)
+	s.lock.Unlock()
+
+	s.offchainWorkersLock.Lock()
+	defer s.offchainWorkersLock.Unlock()
+	s.offchainWorkersWG.Wait()
 	return nil
 }
 
@@ -269,6 +306,13 @@ func (s *Service) handleBlock(block *types.Block, state *rtstorage.TrieState) er
 		}
 	}
 
+	if operations := state.IndexOperations(); len(operations) > 0 {
+		err = s.blockState.SetIndexedTransactions(block, operations)
+		if err != nil {
+			return fmt.Errorf("setting indexed transactions: %w", err)
+		}
+	}
+
 	err = s.onBlockImport.HandleDigests(&block.Header)
 	if err != nil {
 		return fmt.Errorf("on block import handle: %w", err)
@@ -311,6 +355,8 @@ func (s *Service) handleBlock(block *types.Block, state *rtstorage.TrieState) er
 		s.blockAddCh <- block
 	}()
 
+	s.startOffchainWorker(&block.Header)
+
 	return nil
 }
 
@@ -335,10 +381,12 @@ func (s *Service) handleCodeSubstitution(hash common.Hash,
 	// this needs to create a new runtime instance, otherwise it will update
 	// the blocks that reference the current runtime version to use the code substition
 	cfg := wazero_runtime.Config{
-		Storage:     state,
-		Keystore:    rt.Keystore(),
-		NodeStorage: rt.NodeStorage(),
-		Network:     rt.NetworkService(),
+		Storage:          state,
+		Keystore:         rt.Keystore(),
+		NodeStorage:      rt.NodeStorage(),
+		Network:          rt.NetworkService(),
+		CompilationCache: s.compilationCache,
+		LogFilter:        s.logFilter,
 	}
 
 	if rt.Validator() {
@@ -359,6 +407,80 @@ func (s *Service) handleCodeSubstitution(hash common.Hash,
 	return nil
 }
 
+// startOffchainWorker spawns an offchain worker for the given imported block if offchain
+// workers are enabled, the node is synced and the block is the best block. The worker is
+// not spawned if the maximum number of offchain workers are already running.
+func (s *Service) startOffchainWorker(header *types.Header) {
+	if s.offchainWorkers == nil || !s.net.IsSynced() {
+		return
+	}
+
+	blockHash := header.Hash()
+	if s.blockState.BestBlockHash() != blockHash {
+		return
+	}
+
+	select {
+	case s.offchainWorkers <- struct{}{}:
+	default:
+		logger.Debugf("not running offchain worker for block %s: too many offchain workers running", blockHash)
+		return
+	}
+
+	s.offchainWorkersLock.Lock()
+	defer s.offchainWorkersLock.Unlock()
+	if s.ctx.Err() != nil {
+		<-s.offchainWorkers
+		return
+	}
+
+	s.offchainWorkersWG.Add(1)
+	go func() {
+		defer func() {
+			<-s.offchainWorkers
+			s.offchainWorkersWG.Done()
+		}()
+
+		err := s.runOffchainWorker(header)
+		if err != nil {
+			logger.Errorf("running offchain worker for block %s: %s", blockHash, err)
+		}
+	}()
+}
+
+// runOffchainWorker runs the offchain worker of the runtime for the given block, on a
+// handle of the runtime of the block using the state of the block.
+func (s *Service) runOffchainWorker(header *types.Header) error {
+	blockHash := header.Hash()
+	rt, err := s.blockState.GetRuntime(blockHash)
+	if err != nil {
+		return fmt.Errorf("getting runtime: %w", err)
+	}
+
+	version, err := rt.Version()
+	if err != nil {
+		return fmt.Errorf("getting runtime version: %w", err)
+	}
+
+	_, err = version.OffchainWorkerAPIVersion()
+	if errors.Is(err, runtime.ErrOffchainWorkerAPINotFound) {
+		logger.Tracef("runtime of block %s has no offchain worker", blockHash)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting offchain worker api version: %w", err)
+	}
+
+	state, err := s.storageState.TrieState(&header.StateRoot)
+	if err != nil {
+		return fmt.Errorf("getting trie state: %w", err)
+	}
+
+	// the offchain worker is canceled when the service stops
+	worker := runtime.WithLimits(s.ctx, rt, runtime.OffchainCall)
+	worker.SetContextStorage(state)
+	return worker.OffchainWorker(header)
+}
+
 // handleBlocksAsync handles a block asynchronously; the handling performed by this function
 // does not need to be completed before the next block can be imported.
 func (s *Service) handleBlocksAsync() {
@@ -417,8 +539,7 @@ func (s *Service) handleChainReorg(best, curr common.Hash) error {
 	}
 
 	// Check transaction validation on the best block.
-	bestBlockHash := s.blockState.BestBlockHash()
-	rt, err := s.blockState.GetRuntime(bestBlockHash)
+	rt, err := prepareRuntime(nil, s.storageState, s.blockState)
 	if err != nil {
 		return err
 	}
@@ -426,6 +547,7 @@ func (s *Service) handleChainReorg(best, curr common.Hash) error {
 	if rt == nil {
 		return ErrNilRuntime
 	}
+	rt = runtime.WithLimits(s.ctx, rt, runtime.ValidationCall)
 
 	// for each block in the previous chain, re-add its extrinsics back into the pool
 	for _, hash := range subchain {
@@ -447,6 +569,11 @@ func (s *Service) handleChainReorg(best, curr common.Hash) error {
 				continue
 			}
 
+			s.transactionState.NotifyStatus(ext, transaction.StatusNotification{
+				Status: transaction.Retracted,
+				Hash:   hash,
+			})
+
 			externalExt, err := s.buildExternalTransaction(rt, ext)
 			if err != nil {
 				return fmt.Errorf("building external transaction: %s", err)
@@ -455,20 +582,25 @@ func (s *Service) handleChainReorg(best, curr common.Hash) error {
 			transactionValidity, err := rt.ValidateTransaction(externalExt)
 			if err != nil {
 				logger.Debugf("failed to validate transaction for extrinsic %s: %s skipping in chain reorg", ext, err)
-				s.transactionState.RemoveExtrinsic(ext)
+				s.transactionState.RemoveExtrinsicByHash(ext.Hash())
 				continue
 			}
 			vtx := transaction.NewValidTransaction(ext, transactionValidity)
-			s.transactionState.AddToPool(vtx)
+			_, err = s.transactionState.AddToPool(vtx)
+			if err != nil {
+				logger.Debugf("failed to re-add transaction for extrinsic %s in chain reorg: %s", ext, err)
+			}
 		}
 	}
 
 	return nil
 }
 
-// maintainTransactionPool removes any transactions that were included in
-// the new block, revalidates the transactions in the pool, and moves
-// them to the queue if valid.
+// maintainTransactionPool removes any transactions that were included in the new block,
+// drops the transactions of the pool whose longevity is exceeded and revalidates a batch
+// of the transactions validated the longest time ago against the state of the best block.
+// Revalidated transactions are moved between the ready and future sets of the pool
+// according to the tags they now require.
 // See https://github.com/paritytech/substrate/blob/74804b5649eccfb83c90aec87bdca58e5d5c8789/client/transaction-pool/src/lib.rs#L545
 func (s *Service) maintainTransactionPool(block *types.Block, bestBlockHash common.Hash) error {
 	// remove extrinsics included in a block
@@ -476,6 +608,12 @@ func (s *Service) maintainTransactionPool(block *types.Block, bestBlockHash comm
 		s.transactionState.RemoveExtrinsic(ext)
 	}
 
+	bestHeader, err := s.blockState.GetHeader(bestBlockHash)
+	if err != nil {
+		return fmt.Errorf("getting best block header: %w", err)
+	}
+	s.transactionState.SetBestBlockNumber(bestHeader.Number)
+
 	stateRoot, err := s.storageState.GetStateRootFromBlock(&bestBlockHash)
 	if err != nil {
 		logger.Errorf("could not get state root from block %s: %w", bestBlockHash, err)
@@ -488,35 +626,32 @@ func (s *Service) maintainTransactionPool(block *types.Block, bestBlockHash comm
 		return err
 	}
 
-	// re-validate transactions in the pool and move them to the queue
-	txs := s.transactionState.PendingInPool()
-	for _, tx := range txs {
-		bestBlockHash := s.blockState.BestBlockHash()
-		rt, err := s.blockState.GetRuntime(bestBlockHash)
-		if err != nil {
-			return fmt.Errorf("failed to get runtime to re-validate transactions in pool: %s", err)
-		}
+	rt, err := s.blockState.GetRuntime(bestBlockHash)
+	if err != nil {
+		return fmt.Errorf("failed to get runtime to re-validate transactions in pool: %s", err)
+	}
+	rt = runtime.WithLimits(s.ctx, rt, runtime.ValidationCall)
+	rt.SetContextStorage(ts)
 
-		rt.SetContextStorage(ts)
+	// re-validate a batch of transactions of the pool
+	txs := s.transactionState.Oldest(revalidationBatchSize)
+	for _, tx := range txs {
 		externalExt, err := s.buildExternalTransaction(rt, tx.Extrinsic)
 		if err != nil {
-			return fmt.Errorf("building external transaction: %s", err)
+			logger.Debugf("failed to build external transaction for extrinsic %s: %s", tx.Extrinsic, err)
+			s.transactionState.RemoveExtrinsicByHash(tx.Extrinsic.Hash())
+			continue
 		}
 
 		txnValidity, err := rt.ValidateTransaction(externalExt)
 		if err != nil {
 			logger.Debugf("failed to validate transaction for extrinsic %s: %s", tx.Extrinsic, err)
-			s.transactionState.RemoveExtrinsic(tx.Extrinsic)
+			s.transactionState.RemoveExtrinsicByHash(tx.Extrinsic.Hash())
 			continue
 		}
 
-		tx = transaction.NewValidTransaction(tx.Extrinsic, txnValidity)
-
-		// Err is only thrown if tx is already in pool, in which case it still gets removed
-		h, _ := s.transactionState.Push(tx)
-
-		s.transactionState.RemoveExtrinsicFromPool(tx.Extrinsic)
-		logger.Tracef("moved transaction %s to queue", h)
+		s.transactionState.UpdateValidity(tx.Extrinsic.Hash(), txnValidity)
+		logger.Tracef("re-validated transaction %s", tx.Extrinsic.Hash())
 	}
 	return nil
 }
@@ -544,8 +679,7 @@ func (s *Service) HasKey(pubKeyStr, keystoreType string) (bool, error) {
 
 // DecodeSessionKeys executes the runtime DecodeSessionKeys and return the scale encoded keys
 func (s *Service) DecodeSessionKeys(encodedSessionKeys []byte) ([]byte, error) {
-	bestBlockHash := s.blockState.BestBlockHash()
-	rt, err := s.blockState.GetRuntime(bestBlockHash)
+	rt, err := prepareRuntime(nil, s.storageState, s.blockState)
 	if err != nil {
 		return nil, err
 	}
@@ -553,6 +687,18 @@ func (s *Service) DecodeSessionKeys(encodedSessionKeys []byte) ([]byte, error) {
 	return rt.DecodeSessionKeys(encodedSessionKeys)
 }
 
+// GenerateSessionKeys executes the runtime GenerateSessionKeys using the runtime of the best block.
+// The generated private keys are inserted into the node keystores and the scale encoded public keys
+// are returned.
+func (s *Service) GenerateSessionKeys() ([]byte, error) {
+	rt, err := prepareRuntime(nil, s.storageState, s.blockState)
+	if err != nil {
+		return nil, err
+	}
+
+	return rt.GenerateSessionKeys(nil)
+}
+
 // GetRuntimeVersion gets the current RuntimeVersion
 func (s *Service) GetRuntimeVersion(bhash *common.Hash) (
 	version runtime.Version, err error) {
@@ -591,6 +737,7 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 		return err
 	}
 
+	rt = runtime.WithLimits(s.ctx, rt, runtime.ValidationCall)
 	rt.SetContextStorage(ts)
 
 	externalExt, err := s.buildExternalTransaction(rt, ext)
@@ -605,11 +752,15 @@ func (s *Service) HandleSubmittedExtrinsic(ext types.Extrinsic) error {
 
 	// add transaction to pool
 	vtx := transaction.NewValidTransaction(ext, transactionValidity)
-	s.transactionState.AddToPool(vtx)
+	_, err = s.transactionState.AddToPool(vtx)
+	if err != nil {
+		return fmt.Errorf("adding transaction to pool: %w", err)
+	}
 
 	// broadcast transaction
 	msg := &network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}}
 	s.net.GossipMessage(msg)
+	s.transactionState.NotifyStatus(ext, transaction.StatusNotification{Status: transaction.Broadcast})
 	return nil
 }
 
@@ -450,7 +450,8 @@ func TestMaintainTransactionPoolLatestTxnQueue_EmptyBlock(t *testing.T) {
 		Extrinsic: types.Extrinsic(encExt),
 		Validity:  &transaction.Validity{Priority: 1},
 	}
-	_ = service.transactionState.AddToPool(tx)
+	_, err = service.transactionState.AddToPool(tx)
+	require.NoError(t, err)
 
 	// provides is a list of transaction hashes that depend on this tx, see:
 	// https://github.com/paritytech/substrate/blob/5420de3face1349a97eb954ae71c5b0b940c31de/core/sr-primitives/src/transaction_validity.rs#L195
@@ -500,7 +501,8 @@ func TestMaintainTransactionPoolLatestTxnQueue_BlockWithExtrinsics(t *testing.T)
 		Extrinsic: types.Extrinsic(encodedExtrinsic),
 		Validity:  &transaction.Validity{Priority: 1},
 	}
-	_ = service.transactionState.AddToPool(tx)
+	_, err = service.transactionState.AddToPool(tx)
+	require.NoError(t, err)
 
 	bestBlockHash := service.blockState.BestBlockHash()
 	err = service.maintainTransactionPool(&types.Block{
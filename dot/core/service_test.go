@@ -8,6 +8,7 @@
 	"context"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"testing"
 
@@ -464,6 +465,33 @@ func Test_Service_handleBlock(t *testing.T) {
 		execTest(t, service, &block, trieState, errTestDummyError)
 	})
 
+	t.Run("set_indexed_transactions_error", func(t *testing.T) {
+		t.Parallel()
+		trieState := rtstorage.NewTrieState(inmemory_trie.NewEmptyTrie())
+		trieState.IndexTransaction(0, 1, common.Hash{1})
+
+		testHeader := types.NewEmptyHeader()
+		block := types.NewBlock(*testHeader, *types.NewBody([]types.Extrinsic{[]byte{21}}))
+		block.Header.Number = 21
+
+		ctrl := gomock.NewController(t)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().StoreTrie(trieState, &block.Header).Return(nil)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().AddBlock(&block).Return(nil)
+		mockBlockState.EXPECT().SetIndexedTransactions(&block, []rtstorage.IndexOperation{
+			{Extrinsic: 0, Hash: common.Hash{1}, Size: 1},
+		}).Return(errTestDummyError)
+
+		service := &Service{
+			storageState: mockStorageState,
+			blockState:   mockBlockState,
+		}
+		err := service.handleBlock(&block, trieState)
+		assert.ErrorIs(t, err, errTestDummyError)
+		assert.EqualError(t, err, "setting indexed transactions: test dummy error")
+	})
+
 	t.Run("handle_runtime_changes_error", func(t *testing.T) {
 		t.Parallel()
 		trieState := rtstorage.NewTrieState(inmemory_trie.NewEmptyTrie())
@@ -640,14 +668,49 @@ func Test_Service_maintainTransactionPool(t *testing.T) {
 		runtimeMock.EXPECT().SetContextStorage(&rtstorage.TrieState{})
 
 		mockTxnState := NewMockTransactionState(ctrl)
-		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21}).Times(2)
-		mockTxnState.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{vt})
+		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21})
+		mockTxnState.EXPECT().SetBestBlockNumber(uint(21))
+		mockTxnState.EXPECT().Oldest(revalidationBatchSize).Return([]*transaction.ValidTransaction{vt})
+		mockTxnState.EXPECT().RemoveExtrinsicByHash(extrinsic.Hash()).Return(true)
 		mockBlockState := NewMockBlockState(ctrl)
-		runtimeBlockHashCall := mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
-		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).
-			Return(runtimeMock, nil).After(runtimeBlockHashCall)
-		mockBlockState.EXPECT().BestBlockHash().
-			Return(common.Hash{}).After(runtimeBlockHashCall)
+		mockBlockState.EXPECT().GetHeader(common.Hash{1}).Return(&block.Header, nil)
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{})
+
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(&common.Hash{1}).Return(&rtstorage.TrieState{}, nil)
+		mockStorageState.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&common.Hash{1}, nil)
+		service := &Service{
+			transactionState: mockTxnState,
+			blockState:       mockBlockState,
+			storageState:     mockStorageState,
+		}
+		err := service.maintainTransactionPool(&block, common.Hash{1})
+		require.NoError(t, err)
+	})
+
+	t.Run("build_external_transaction_err", func(t *testing.T) {
+		t.Parallel()
+		testHeader := types.NewEmptyHeader()
+		block := types.NewBlock(*testHeader, *types.NewBody([]types.Extrinsic{[]byte{21}}))
+		block.Header.Number = 21
+
+		extrinsic := types.Extrinsic{21}
+		vt := transaction.NewValidTransaction(extrinsic, &transaction.Validity{Propagate: true})
+
+		ctrl := gomock.NewController(t)
+		runtimeMock := NewMockInstance(ctrl)
+		runtimeMock.EXPECT().Version().Return(runtime.Version{}, errTestDummyError).Times(2)
+		runtimeMock.EXPECT().SetContextStorage(&rtstorage.TrieState{})
+
+		mockTxnState := NewMockTransactionState(ctrl)
+		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21})
+		mockTxnState.EXPECT().SetBestBlockNumber(uint(21))
+		mockTxnState.EXPECT().Oldest(revalidationBatchSize).Return([]*transaction.ValidTransaction{vt, vt})
+		mockTxnState.EXPECT().RemoveExtrinsicByHash(extrinsic.Hash()).Return(true).Times(2)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().GetHeader(common.Hash{1}).Return(&block.Header, nil)
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
 
 		mockStorageState := NewMockStorageState(ctrl)
 		mockStorageState.EXPECT().TrieState(&common.Hash{1}).Return(&rtstorage.TrieState{}, nil)
@@ -705,16 +768,14 @@ func Test_Service_maintainTransactionPool(t *testing.T) {
 		runtimeMock.EXPECT().SetContextStorage(&rtstorage.TrieState{})
 		mockTxnState := NewMockTransactionState(ctrl)
 		mockTxnState.EXPECT().RemoveExtrinsic(types.Extrinsic{21})
-		mockTxnState.EXPECT().PendingInPool().Return([]*transaction.ValidTransaction{vt})
-		mockTxnState.EXPECT().Push(tx).Return(common.Hash{}, nil)
-		mockTxnState.EXPECT().RemoveExtrinsicFromPool(types.Extrinsic{21})
+		mockTxnState.EXPECT().SetBestBlockNumber(uint(21))
+		mockTxnState.EXPECT().Oldest(revalidationBatchSize).Return([]*transaction.ValidTransaction{vt})
+		mockTxnState.EXPECT().UpdateValidity(ext.Hash(), tx.Validity).Return(true)
 
 		mockBlockStateOk := NewMockBlockState(ctrl)
-		runtimeBlockHashCall := mockBlockStateOk.EXPECT().BestBlockHash().Return(common.Hash{1})
-		mockBlockStateOk.EXPECT().GetRuntime(common.Hash{1}).
-			Return(runtimeMock, nil).After(runtimeBlockHashCall)
-		mockBlockStateOk.EXPECT().BestBlockHash().
-			Return(common.Hash{}).After(runtimeBlockHashCall)
+		mockBlockStateOk.EXPECT().GetHeader(common.Hash{1}).Return(&block.Header, nil)
+		mockBlockStateOk.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		mockBlockStateOk.EXPECT().BestBlockHash().Return(common.Hash{})
 
 		mockStorageState := NewMockStorageState(ctrl)
 		mockStorageState.EXPECT().TrieState(&common.Hash{1}).Return(&rtstorage.TrieState{}, nil)
@@ -797,6 +858,208 @@ func Test_Service_handleBlocksAsync(t *testing.T) {
 	})
 }
 
+func Test_Service_startOffchainWorker(t *testing.T) {
+	t.Parallel()
+
+	header := types.NewEmptyHeader()
+	header.Number = 1
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		service := &Service{}
+		service.startOffchainWorker(header)
+	})
+
+	t.Run("not_synced", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockNetwork := NewMockNetwork(ctrl)
+		mockNetwork.EXPECT().IsSynced().Return(false)
+		service := &Service{
+			net:             mockNetwork,
+			offchainWorkers: make(chan struct{}, 1),
+		}
+		service.startOffchainWorker(header)
+	})
+
+	t.Run("not_best_block", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockNetwork := NewMockNetwork(ctrl)
+		mockNetwork.EXPECT().IsSynced().Return(true)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		service := &Service{
+			net:             mockNetwork,
+			blockState:      mockBlockState,
+			offchainWorkers: make(chan struct{}, 1),
+		}
+		service.startOffchainWorker(header)
+	})
+
+	t.Run("too_many_workers", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockNetwork := NewMockNetwork(ctrl)
+		mockNetwork.EXPECT().IsSynced().Return(true)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(header.Hash())
+		service := &Service{
+			net:             mockNetwork,
+			blockState:      mockBlockState,
+			offchainWorkers: make(chan struct{}, 1),
+		}
+		service.offchainWorkers <- struct{}{}
+
+		service.startOffchainWorker(header)
+		assert.Len(t, service.offchainWorkers, 1)
+	})
+
+	t.Run("cancelled_context", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockNetwork := NewMockNetwork(ctrl)
+		mockNetwork.EXPECT().IsSynced().Return(true)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(header.Hash())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		service := &Service{
+			ctx:             ctx,
+			net:             mockNetwork,
+			blockState:      mockBlockState,
+			offchainWorkers: make(chan struct{}, 1),
+		}
+
+		service.startOffchainWorker(header)
+		assert.Len(t, service.offchainWorkers, 0)
+	})
+
+	t.Run("run_worker", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockNetwork := NewMockNetwork(ctrl)
+		mockNetwork.EXPECT().IsSynced().Return(true)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(header.Hash())
+		mockBlockState.EXPECT().GetRuntime(header.Hash()).Return(nil, errTestDummyError)
+		service := &Service{
+			ctx:             context.Background(),
+			net:             mockNetwork,
+			blockState:      mockBlockState,
+			offchainWorkers: make(chan struct{}, 1),
+		}
+
+		service.startOffchainWorker(header)
+		service.offchainWorkersWG.Wait()
+		assert.Len(t, service.offchainWorkers, 0)
+	})
+}
+
+func Test_Service_runOffchainWorker(t *testing.T) {
+	t.Parallel()
+
+	header := types.NewEmptyHeader()
+	header.Number = 1
+	header.StateRoot = common.Hash{2}
+
+	offchainWorkerVersion := runtime.Version{
+		APIItems: []runtime.APIItem{{
+			Name: [8]byte{0xf7, 0x8b, 0x27, 0x8b, 0xe5, 0x3f, 0x45, 0x4c},
+			Ver:  2,
+		}},
+	}
+
+	testCases := map[string]struct {
+		serviceBuilder func(ctrl *gomock.Controller) *Service
+		errWrapped     error
+		errMessage     string
+	}{
+		"get_runtime_error": {
+			serviceBuilder: func(ctrl *gomock.Controller) *Service {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetRuntime(header.Hash()).Return(nil, errTestDummyError)
+				return &Service{blockState: blockState}
+			},
+			errWrapped: errTestDummyError,
+			errMessage: "getting runtime: test dummy error",
+		},
+		"runtime_version_error": {
+			serviceBuilder: func(ctrl *gomock.Controller) *Service {
+				storedRuntime := NewMockInstance(ctrl)
+				storedRuntime.EXPECT().Version().Return(runtime.Version{}, errTestDummyError)
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetRuntime(header.Hash()).Return(storedRuntime, nil)
+				return &Service{blockState: blockState}
+			},
+			errWrapped: errTestDummyError,
+			errMessage: "getting runtime version: test dummy error",
+		},
+		"no_offchain_worker_api": {
+			serviceBuilder: func(ctrl *gomock.Controller) *Service {
+				storedRuntime := NewMockInstance(ctrl)
+				storedRuntime.EXPECT().Version().Return(runtime.Version{}, nil)
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetRuntime(header.Hash()).Return(storedRuntime, nil)
+				return &Service{blockState: blockState}
+			},
+		},
+		"trie_state_error": {
+			serviceBuilder: func(ctrl *gomock.Controller) *Service {
+				storedRuntime := NewMockInstance(ctrl)
+				storedRuntime.EXPECT().Version().Return(offchainWorkerVersion, nil)
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetRuntime(header.Hash()).Return(storedRuntime, nil)
+				storageState := NewMockStorageState(ctrl)
+				storageState.EXPECT().TrieState(&common.Hash{2}).Return(nil, errTestDummyError)
+				return &Service{
+					blockState:   blockState,
+					storageState: storageState,
+				}
+			},
+			errWrapped: errTestDummyError,
+			errMessage: "getting trie state: test dummy error",
+		},
+		"run_offchain_worker": {
+			serviceBuilder: func(ctrl *gomock.Controller) *Service {
+				trieState := rtstorage.NewTrieState(inmemory_trie.NewEmptyTrie())
+				storedRuntime := NewMockInstance(ctrl)
+				storedRuntime.EXPECT().Version().Return(offchainWorkerVersion, nil)
+				storedRuntime.EXPECT().SetContextStorage(trieState)
+				storedRuntime.EXPECT().OffchainWorker(header).Return(errTestDummyError)
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetRuntime(header.Hash()).Return(storedRuntime, nil)
+				storageState := NewMockStorageState(ctrl)
+				storageState.EXPECT().TrieState(&common.Hash{2}).Return(trieState, nil)
+				return &Service{
+					ctx:          context.Background(),
+					blockState:   blockState,
+					storageState: storageState,
+				}
+			},
+			errWrapped: errTestDummyError,
+			errMessage: "test dummy error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			service := testCase.serviceBuilder(ctrl)
+
+			err := service.runOffchainWorker(header)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
 func TestService_handleChainReorg(t *testing.T) {
 	t.Parallel()
 	execTest := func(t *testing.T, s *Service, prevHash common.Hash, currHash common.Hash, expErr error) {
@@ -892,11 +1155,14 @@ func TestService_handleChainReorg(t *testing.T) {
 		mockBlockState.EXPECT().RangeInMemory(testAncestorHash, testPrevHash).Return(testSubChain, nil)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
 		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(nil, errDummyErr)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(nil).Return(&rtstorage.TrieState{}, nil)
 
 		service := &Service{
-			blockState: mockBlockState,
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
 		}
-		execTest(t, service, testPrevHash, testCurrentHash, errDummyErr)
+		execTest(t, service, testPrevHash, testCurrentHash, fmt.Errorf("getting runtime: %w", errDummyErr))
 	})
 
 	t.Run("invalid_transaction", func(t *testing.T) {
@@ -929,10 +1195,20 @@ func TestService_handleChainReorg(t *testing.T) {
 		mockBlockState.EXPECT().GetBlockBody(testAncestorHash).Return(body, nil)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{})
 		mockTxnState := NewMockTransactionState(ctrl)
-		mockTxnState.EXPECT().RemoveExtrinsic(ext)
+		mockTxnState.EXPECT().NotifyStatus(ext, transaction.StatusNotification{
+			Status: transaction.Retracted,
+			Hash:   testAncestorHash,
+		})
+		mockTxnState.EXPECT().RemoveExtrinsicByHash(ext.Hash())
+
+		trieState := &rtstorage.TrieState{}
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
+		runtimeMockErr.EXPECT().SetContextStorage(trieState)
 
 		service := &Service{
 			blockState:       mockBlockState,
+			storageState:     mockStorageState,
 			transactionState: mockTxnState,
 		}
 
@@ -968,10 +1244,20 @@ func TestService_handleChainReorg(t *testing.T) {
 		mockBlockState.EXPECT().GetBlockBody(testAncestorHash).Return(body, nil)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{})
 		mockTxnStateOk := NewMockTransactionState(ctrl)
-		mockTxnStateOk.EXPECT().AddToPool(vtx).Return(common.Hash{})
+		mockTxnStateOk.EXPECT().NotifyStatus(ext, transaction.StatusNotification{
+			Status: transaction.Retracted,
+			Hash:   testAncestorHash,
+		})
+		mockTxnStateOk.EXPECT().AddToPool(vtx).Return(common.Hash{}, nil)
+
+		trieState := &rtstorage.TrieState{}
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
+		runtimeMockOk.EXPECT().SetContextStorage(trieState)
 
 		service := &Service{
 			blockState:       mockBlockState,
+			storageState:     mockStorageState,
 			transactionState: mockTxnStateOk,
 		}
 		execTest(t, service, testPrevHash, testCurrentHash, nil)
@@ -1099,7 +1385,7 @@ func TestService_DecodeSessionKeys(t *testing.T) {
 		res, err := s.DecodeSessionKeys(enc)
 		assert.ErrorIs(t, err, expErr)
 		if expErr != nil {
-			assert.EqualError(t, err, expErr.Error())
+			assert.EqualError(t, err, "getting runtime: "+expErr.Error())
 		}
 		assert.Equal(t, exp, res)
 	}
@@ -1109,11 +1395,16 @@ func TestService_DecodeSessionKeys(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		runtimeMock := NewMockInstance(ctrl)
 		runtimeMock.EXPECT().DecodeSessionKeys(testEncKeys).Return(testEncKeys, nil)
+		trieState := &rtstorage.TrieState{}
+		runtimeMock.EXPECT().SetContextStorage(trieState)
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
 		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
 		service := &Service{
-			blockState: mockBlockState,
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
 		}
 		execTest(t, service, testEncKeys, testEncKeys, nil)
 	})
@@ -1124,13 +1415,62 @@ func TestService_DecodeSessionKeys(t *testing.T) {
 		mockBlockState := NewMockBlockState(ctrl)
 		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
 		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(nil, errDummyErr)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(nil).Return(&rtstorage.TrieState{}, nil)
 		service := &Service{
-			blockState: mockBlockState,
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
 		}
 		execTest(t, service, testEncKeys, nil, errDummyErr)
 	})
 }
 
+func TestService_GenerateSessionKeys(t *testing.T) {
+	t.Parallel()
+	testKeys := []byte{1, 2, 3, 4}
+
+	t.Run("ok_case", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		runtimeMock := NewMockInstance(ctrl)
+		runtimeMock.EXPECT().GenerateSessionKeys(nil).Return(testKeys, nil)
+		trieState := &rtstorage.TrieState{}
+		runtimeMock.EXPECT().SetContextStorage(trieState)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(runtimeMock, nil)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+
+		keys, err := service.GenerateSessionKeys()
+		assert.NoError(t, err)
+		assert.Equal(t, testKeys, keys)
+	})
+
+	t.Run("err_case", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockBlockState := NewMockBlockState(ctrl)
+		mockBlockState.EXPECT().BestBlockHash().Return(common.Hash{1})
+		mockBlockState.EXPECT().GetRuntime(common.Hash{1}).Return(nil, errDummyErr)
+		mockStorageState := NewMockStorageState(ctrl)
+		mockStorageState.EXPECT().TrieState(nil).Return(&rtstorage.TrieState{}, nil)
+		service := &Service{
+			blockState:   mockBlockState,
+			storageState: mockStorageState,
+		}
+
+		keys, err := service.GenerateSessionKeys()
+		assert.ErrorIs(t, err, errDummyErr)
+		assert.EqualError(t, err, "getting runtime: dummy error for testing")
+		assert.Nil(t, keys)
+	})
+}
+
 func TestServiceGetRuntimeVersion(t *testing.T) {
 	t.Parallel()
 	rv := runtime.Version{
@@ -1358,7 +1698,9 @@ func TestServiceHandleSubmittedExtrinsic(t *testing.T) {
 
 		mockTxnState := NewMockTransactionState(ctrl)
 		mockTxnState.EXPECT().Exists(types.Extrinsic{}).MaxTimes(2)
-		mockTxnState.EXPECT().AddToPool(transaction.NewValidTransaction(ext, &transaction.Validity{Propagate: true}))
+		mockTxnState.EXPECT().AddToPool(transaction.NewValidTransaction(ext, &transaction.Validity{Propagate: true})).
+			Return(common.Hash{}, nil)
+		mockTxnState.EXPECT().NotifyStatus(ext, transaction.StatusNotification{Status: transaction.Broadcast})
 		mockNetState := NewMockNetwork(ctrl)
 		mockNetState.EXPECT().GossipMessage(&network.TransactionMessage{Extrinsics: []types.Extrinsic{ext}})
 		service := &Service{
@@ -24,6 +24,7 @@
 	babe "github.com/ChainSafe/gossamer/lib/babe"
 	grandpa "github.com/ChainSafe/gossamer/lib/grandpa"
 	keystore "github.com/ChainSafe/gossamer/lib/keystore"
+	manualseal "github.com/ChainSafe/gossamer/lib/manualseal"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -125,6 +126,21 @@ func (mr *MocknodeBuilderIfaceMockRecorder) createGRANDPAService(config, st, ks,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createGRANDPAService", reflect.TypeOf((*MocknodeBuilderIface)(nil).createGRANDPAService), config, st, ks, net, telemetryMailer)
 }
 
+// createManualSealService mocks base method.
+func (m *MocknodeBuilderIface) createManualSealService(config *config.Config, st *state.Service, ks KeyStore, cs *core.Service) (*manualseal.Service, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "createManualSealService", config, st, ks, cs)
+	ret0, _ := ret[0].(*manualseal.Service)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// createManualSealService indicates an expected call of createManualSealService.
+func (mr *MocknodeBuilderIfaceMockRecorder) createManualSealService(config, st, ks, cs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "createManualSealService", reflect.TypeOf((*MocknodeBuilderIface)(nil).createManualSealService), config, st, ks, cs)
+}
+
 // createNetworkService mocks base method.
 func (m *MocknodeBuilderIface) createNetworkService(config *config.Config, stateSrvc *state.Service, telemetryMailer Telemetry) (*network.Service, error) {
 	m.ctrl.T.Helper()
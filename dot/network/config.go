@@ -66,6 +66,7 @@ type Config struct {
 
 	// Service interfaces
 	BlockState         BlockState
+	StorageState       StorageState
 	Syncer             Syncer
 	WarpSyncProvider   WarpSyncProvider
 	TransactionHandler TransactionHandler
@@ -97,6 +98,8 @@ type Config struct {
 
 	// PersistentPeers is a list of multiaddrs which the node should remain connected to
 	PersistentPeers []string
+	// ReservedOnly only allows connections with the persistent and reserved peers
+	ReservedOnly bool
 
 	// NodeKey is the private hex encoded Ed25519 key to build the p2p identity
 	NodeKey string
@@ -17,6 +17,7 @@
 	errHandshakeTimeout          = errors.New("handshake timeout reached")
 	errInboundHanshakeExists     = errors.New("an inbound handshake already exists for given peer")
 	errInvalidRole               = errors.New("invalid role")
+	errPeerRefused               = errors.New("peer refused by the peer set of the protocol")
 	ErrFailedToReadEntireMessage = errors.New("failed to read entire message")
 	ErrNilStream                 = errors.New("nil stream")
 	ErrInvalidLEB128EncodedData  = errors.New("invalid LEB128 encoded data")
@@ -70,6 +70,14 @@ func init() {
 	connectTimeout       = time.Second * 5
 )
 
+// ids of the peer sets, each notifications protocol has its own set of slots
+const (
+	blockAnnounceSetID = iota
+	transactionsSetID
+	grandpaSetID
+	numPeerSets
+)
+
 // host wraps libp2p host with network host configuration and services
 type host struct {
 	ctx             context.Context
@@ -152,18 +160,20 @@ func newHost(ctx context.Context, cfg *Config) (*host, error) {
 
 	// We have tried to set maxInPeers and maxOutPeers such that number of peer
 	// connections remain between min peers and max peers
-	const reservedOnly = false
-	peerCfgSet := peerset.NewConfigSet(
-		//TODO: there is no any understanding of maxOutPeers and maxInPirs calculations.
-		// This needs to be explicitly mentioned
-
-		// maxInPeers is later used in peerstate only and defines available Incoming connection slots
-		uint32(cfg.MaxPeers-cfg.MinPeers),
-		// maxOutPeers is later used in peerstate only and defines available Outgoing connection slots
-		uint32(cfg.MaxPeers/2),
-		reservedOnly,
-		peerSetSlotAllocTime,
-	)
+	//TODO: there is no any understanding of maxOutPeers and maxInPirs calculations.
+	// This needs to be explicitly mentioned
+
+	// maxInPeers is later used in peerstate only and defines available Incoming connection slots
+	maxInPeers := uint32(cfg.MaxPeers - cfg.MinPeers)
+	// maxOutPeers is later used in peerstate only and defines available Outgoing connection slots
+	maxOutPeers := uint32(cfg.MaxPeers / 2)
+
+	// the block announces set is the default set, the transactions and grandpa
+	// notifications protocols have their own set with the same number of slots.
+	peerCfgSet := peerset.NewConfigSet(maxInPeers, maxOutPeers, cfg.ReservedOnly, peerSetSlotAllocTime)
+	for setID := transactionsSetID; setID < numPeerSets; setID++ {
+		peerCfgSet.AddSet(maxInPeers, maxOutPeers, cfg.ReservedOnly)
+	}
 
 	// create connection manager
 	cm, err := newConnManager(cfg.MaxPeers, peerCfgSet)
@@ -334,7 +344,7 @@ func (h *host) connect(p peer.AddrInfo) (err error) {
 func (h *host) bootstrap() {
 	for _, info := range h.persistentPeers {
 		h.p2pHost.Peerstore().AddAddrs(info.ID, info.Addrs, peerstore.PermanentAddrTTL)
-		h.cm.peerSetHandler.AddReservedPeer(0, info.ID)
+		h.addReservedPeer(info.ID)
 	}
 
 	for _, addrInfo := range h.bootnodes {
@@ -417,12 +427,19 @@ func (h *host) addReservedPeers(addrs ...string) error {
 			return err
 		}
 		h.p2pHost.Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, peerstore.PermanentAddrTTL)
-		h.cm.peerSetHandler.AddReservedPeer(0, addrInfo.ID)
+		h.addReservedPeer(addrInfo.ID)
 	}
 
 	return nil
 }
 
+// addReservedPeer adds the peer to the reserved peers of all the peer sets
+func (h *host) addReservedPeer(peerID peer.ID) {
+	for setID := 0; setID < numPeerSets; setID++ {
+		h.cm.peerSetHandler.AddReservedPeer(setID, peerID)
+	}
+}
+
 // removeReservedPeers will remove the given peers from the protected peers list
 func (h *host) removeReservedPeers(ids ...string) error {
 	for _, id := range ids {
@@ -430,7 +447,9 @@ func (h *host) removeReservedPeers(ids ...string) error {
 		if err != nil {
 			return err
 		}
-		h.cm.peerSetHandler.RemoveReservedPeer(0, peerID)
+		for setID := 0; setID < numPeerSets; setID++ {
+			h.cm.peerSetHandler.RemoveReservedPeer(setID, peerID)
+		}
 		h.p2pHost.ConnManager().Unprotect(peerID, "")
 	}
 
@@ -4,17 +4,36 @@
 package network
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/ChainSafe/gossamer/dot/network/messages"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
 
 	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
+var (
+	errNoStorageState           = errors.New("storage state is not available")
+	errInvalidBlockHash         = errors.New("invalid block hash")
+	errInvalidBlockNumber       = errors.New("invalid block number")
+	errBlockNotFinalised        = errors.New("block is not finalised")
+	errChangesTriesNotSupported = errors.New("changes tries are not supported")
+)
+
+// maxHeaderProofHeaders is the maximum number of headers linking a requested
+// header to its first justified descendant in a header proof
+const maxHeaderProofHeaders = 512
+
 // handleLightStream handles streams with the <protocol-id>/light/2 protocol ID
 func (s *Service) handleLightStream(stream libp2pnetwork.Stream) {
 	s.readStream(stream, s.decodeLightMessage, s.handleLightMsg, MaxBlockResponseSize)
@@ -47,29 +66,30 @@ func (s *Service) handleLightMsg(stream libp2pnetwork.Stream, msg messages.P2PMe
 		return nil
 	}
 
+	// a decoded request carries every kind of request, only the one with a block set is served
 	resp := NewLightResponse()
 	switch {
-	case lr.RemoteCallRequest != nil:
-		resp.RemoteCallResponse, err = remoteCallResp(lr.RemoteCallRequest)
-	case lr.RemoteHeaderRequest != nil:
-		resp.RemoteHeaderResponse, err = remoteHeaderResp(lr.RemoteHeaderRequest)
-	case lr.RemoteChangesRequest != nil:
+	case lr.RemoteCallRequest != nil && len(lr.RemoteCallRequest.Block) > 0:
+		resp.RemoteCallResponse, err = s.remoteCallResp(lr.RemoteCallRequest)
+	case lr.RemoteHeaderRequest != nil && len(lr.RemoteHeaderRequest.Block) > 0:
+		resp.RemoteHeaderResponse, err = s.remoteHeaderResp(lr.RemoteHeaderRequest)
+	case lr.RemoteChangesRequest != nil && lr.RemoteChangesRequest.FirstBlock != nil:
 		resp.RemoteChangesResponse, err = remoteChangeResp(lr.RemoteChangesRequest)
-	case lr.RemoteReadRequest != nil:
-		resp.RemoteReadResponse, err = remoteReadResp(lr.RemoteReadRequest)
-	case lr.RemoteReadChildRequest != nil:
-		resp.RemoteReadResponse, err = remoteReadChildResp(lr.RemoteReadChildRequest)
+	case lr.RemoteReadRequest != nil && len(lr.RemoteReadRequest.Block) > 0:
+		resp.RemoteReadResponse, err = s.remoteReadResp(lr.RemoteReadRequest)
+	case lr.RemoteReadChildRequest != nil && len(lr.RemoteReadChildRequest.Block) > 0:
+		resp.RemoteReadResponse, err = s.remoteReadChildResp(lr.RemoteReadChildRequest)
 	default:
 		logger.Warn("ignoring LightRequest without request data")
 		return nil
 	}
 
 	if err != nil {
+		logger.Debugf("cannot create response for light request %s: %s", lr, err)
 		return err
 	}
 
-	// TODO(arijit): Remove once we implement the internal APIs. Added to increase code coverage. (#1856)
-	logger.Debugf("LightResponse message: %s", resp)
+	logger.Tracef("LightResponse message: %s", resp)
 
 	err = s.host.writeToStream(stream, resp)
 	if err != nil {
@@ -335,12 +355,13 @@ func newRemoteReadResponse() *RemoteReadResponse {
 // RemoteHeaderResponse ...
 type RemoteHeaderResponse struct {
 	Header []*types.Header
-	proof  []byte
+	Proof  []byte
 }
 
 func newRemoteHeaderResponse() *RemoteHeaderResponse {
 	return &RemoteHeaderResponse{
 		Header: nil,
+		Proof:  []byte{},
 	}
 }
 
@@ -444,21 +465,217 @@ func (rr *RemoteReadResponse) String() string {
 
 // String formats a RemoteHeaderResponse as a string
 func (rh *RemoteHeaderResponse) String() string {
-	return fmt.Sprintf("Header =%+v Proof =%s", rh.Header, string(rh.proof))
+	return fmt.Sprintf("Header =%+v Proof =%s", rh.Header, string(rh.Proof))
+}
+
+// headerProof proves a header belongs to the finalised chain. It holds the headers
+// linking the requested header to the first descendant block with a justification,
+// followed by this justification.
+type headerProof struct {
+	Headers       []types.Header
+	Justification []byte
+}
+
+// lightRequestState returns the state of the block with the given encoded hash,
+// the trie nodes and values accessed being recorded with the given recorder
+func (s *Service) lightRequestState(block []byte, recorder triedb.TrieRecorder) (*storage.TrieState, error) {
+	if s.storageState == nil {
+		return nil, errNoStorageState
+	}
+
+	if len(block) != common.HashLength {
+		return nil, fmt.Errorf("%w: 0x%x", errInvalidBlockHash, block)
+	}
+	blockHash := common.BytesToHash(block)
+
+	stateRoot, err := s.storageState.GetStateRootFromBlock(&blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("getting state root of block %s: %w", blockHash, err)
+	}
+
+	trieState, err := s.storageState.RecordingTrieState(*stateRoot, recorder)
+	if err != nil {
+		return nil, fmt.Errorf("getting state of block %s: %w", blockHash, err)
+	}
+
+	return trieState, nil
+}
+
+// recordedLightProof returns the SCALE encoded trie nodes and values recorded, which
+// prove the presence or the absence of the keys read from the main trie and the child tries
+func recordedLightProof(recorder *triedb.Recorder[chash.H256]) ([]byte, error) {
+	records := recorder.Drain()
+	proofNodes := make([][]byte, len(records))
+	for i, record := range records {
+		proofNodes[i] = record.Data
+	}
+
+	return scale.Marshal(deduplicateProofNodes(proofNodes))
 }
 
-func remoteCallResp(_ *RemoteCallRequest) (*RemoteCallResponse, error) {
-	return &RemoteCallResponse{}, nil
+// remoteCallResp executes the runtime call at the requested block and returns
+// the proof of all the storage entries read during the execution
+func (s *Service) remoteCallResp(req *RemoteCallRequest) (*RemoteCallResponse, error) {
+	recorder := triedb.NewRecorder[chash.H256]()
+	trieState, err := s.lightRequestState(req.Block, recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := s.blockState.GetRuntime(common.BytesToHash(req.Block))
+	if err != nil {
+		return nil, fmt.Errorf("getting runtime: %w", err)
+	}
+
+	// changes made by the call are discarded
+	trieState.StartTransaction()
+	defer trieState.RollbackTransaction()
+
+	instance.SetContextStorage(trieState)
+	_, err = instance.Exec(req.Method, req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("executing %s: %w", req.Method, err)
+	}
+
+	proof, err := recordedLightProof(recorder)
+	if err != nil {
+		return nil, fmt.Errorf("encoding proof: %w", err)
+	}
+
+	return &RemoteCallResponse{Proof: proof}, nil
 }
+
+// remoteChangeResp always fails since changes tries are not supported
 func remoteChangeResp(_ *RemoteChangesRequest) (*RemoteChangesResponse, error) {
-	return &RemoteChangesResponse{}, nil
+	return nil, errChangesTriesNotSupported
+}
+
+// remoteHeaderResp returns the finalised header with the requested SCALE encoded
+// number along with the proof it belongs to the finalised chain
+func (s *Service) remoteHeaderResp(req *RemoteHeaderRequest) (*RemoteHeaderResponse, error) {
+	if len(req.Block) != 4 {
+		return nil, fmt.Errorf("%w: 0x%x", errInvalidBlockNumber, req.Block)
+	}
+
+	var number uint32
+	err := scale.Unmarshal(req.Block, &number)
+	if err != nil {
+		return nil, fmt.Errorf("decoding block number: %w", err)
+	}
+
+	finalised, err := s.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return nil, fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	if uint(number) > finalised.Number {
+		return nil, fmt.Errorf("%w: %d", errBlockNotFinalised, number)
+	}
+
+	header, err := s.blockState.GetHeaderByNumber(uint(number))
+	if err != nil {
+		return nil, fmt.Errorf("getting header by number %d: %w", number, err)
+	}
+
+	// the header is sent without proof if its first justified descendant is too far
+	var proof headerProof
+	last := min(finalised.Number, uint(number)+maxHeaderProofHeaders)
+	for current := uint(number); current <= last; current++ {
+		descendant := header
+		if current != uint(number) {
+			descendant, err = s.blockState.GetHeaderByNumber(current)
+			if err != nil {
+				return nil, fmt.Errorf("getting header by number %d: %w", current, err)
+			}
+			proof.Headers = append(proof.Headers, *descendant)
+		}
+
+		justification, err := s.blockState.GetJustification(descendant.Hash())
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("getting justification of block %s: %w", descendant.Hash(), err)
+		}
+
+		if len(justification) > 0 {
+			proof.Justification = justification
+			break
+		}
+	}
+
+	response := &RemoteHeaderResponse{
+		Header: []*types.Header{header},
+		Proof:  []byte{},
+	}
+
+	if proof.Justification != nil {
+		response.Proof, err = scale.Marshal(proof)
+		if err != nil {
+			return nil, fmt.Errorf("encoding header proof: %w", err)
+		}
+	}
+
+	return response, nil
 }
-func remoteHeaderResp(_ *RemoteHeaderRequest) (*RemoteHeaderResponse, error) {
-	return &RemoteHeaderResponse{}, nil
+
+// remoteReadChildResp returns the proof of the requested keys of a child trie,
+// which includes the proof of the child trie root in the main trie
+func (s *Service) remoteReadChildResp(req *RemoteReadChildRequest) (*RemoteReadResponse, error) {
+	recorder := triedb.NewRecorder[chash.H256]()
+	trieState, err := s.lightRequestState(req.Block, recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	// the storage key can be sent with or without the default child storage prefix
+	keyToChild := bytes.TrimPrefix(req.StorageKey, inmemory.ChildStorageKeyPrefix)
+	childStorageKey := append(bytes.Clone(inmemory.ChildStorageKeyPrefix), keyToChild...)
+
+	// the proof of a missing child trie is the proof of the absence of its root
+	if trieState.Get(childStorageKey) != nil {
+		for _, key := range req.Keys {
+			_, err = trieState.GetChildStorage(keyToChild, key)
+			if err != nil {
+				return nil, fmt.Errorf("reading child trie 0x%x: %w", keyToChild, err)
+			}
+		}
+	}
+
+	proof, err := recordedLightProof(recorder)
+	if err != nil {
+		return nil, fmt.Errorf("encoding proof: %w", err)
+	}
+
+	return &RemoteReadResponse{Proof: proof}, nil
 }
-func remoteReadChildResp(_ *RemoteReadChildRequest) (*RemoteReadResponse, error) {
-	return &RemoteReadResponse{}, nil
+
+// remoteReadResp returns the proof of the requested keys of the block state
+func (s *Service) remoteReadResp(req *RemoteReadRequest) (*RemoteReadResponse, error) {
+	recorder := triedb.NewRecorder[chash.H256]()
+	trieState, err := s.lightRequestState(req.Block, recorder)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range req.Keys {
+		_ = trieState.Get(key)
+	}
+
+	proof, err := recordedLightProof(recorder)
+	if err != nil {
+		return nil, fmt.Errorf("encoding proof: %w", err)
+	}
+
+	return &RemoteReadResponse{Proof: proof}, nil
 }
-func remoteReadResp(_ *RemoteReadRequest) (*RemoteReadResponse, error) {
-	return &RemoteReadResponse{}, nil
+
+func deduplicateProofNodes(proofNodes [][]byte) [][]byte {
+	seen := make(map[string]struct{}, len(proofNodes))
+	deduplicated := make([][]byte, 0, len(proofNodes))
+	for _, proofNode := range proofNodes {
+		if _, ok := seen[string(proofNode)]; ok {
+			continue
+		}
+		seen[string(proofNode)] = struct{}{}
+		deduplicated = append(deduplicated, proofNode)
+	}
+	return deduplicated
 }
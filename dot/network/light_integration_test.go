@@ -33,7 +33,7 @@ func TestEncodeLightRequest(t *testing.T) {
 
 func TestEncodeLightResponse(t *testing.T) {
 	t.Parallel()
-	exp := common.MustHexToBytes("0x00000000000000")
+	exp := common.MustHexToBytes("0x0000000000000000")
 
 	testLightResponse := NewLightResponse()
 	enc, err := testLightResponse.Encode()
@@ -127,35 +127,35 @@ func TestHandleLightMessage_Response(t *testing.T) {
 
 	// Testing remoteCallResp()
 	msg = &LightRequest{
-		RemoteCallRequest: &RemoteCallRequest{},
+		RemoteCallRequest: &RemoteCallRequest{Block: []byte{1}},
 	}
 	err = s.handleLightMsg(stream, msg)
 	require.Error(t, err, expectedErr, msg.String())
 
 	// Testing remoteHeaderResp()
 	msg = &LightRequest{
-		RemoteHeaderRequest: &RemoteHeaderRequest{},
+		RemoteHeaderRequest: &RemoteHeaderRequest{Block: []byte{1}},
 	}
 	err = s.handleLightMsg(stream, msg)
 	require.Error(t, err, expectedErr, msg.String())
 
 	// Testing remoteChangeResp()
 	msg = &LightRequest{
-		RemoteChangesRequest: &RemoteChangesRequest{},
+		RemoteChangesRequest: &RemoteChangesRequest{FirstBlock: &common.Hash{}},
 	}
 	err = s.handleLightMsg(stream, msg)
 	require.Error(t, err, expectedErr, msg.String())
 
 	// Testing remoteReadResp()
 	msg = &LightRequest{
-		RemoteReadRequest: &RemoteReadRequest{},
+		RemoteReadRequest: &RemoteReadRequest{Block: []byte{1}},
 	}
 	err = s.handleLightMsg(stream, msg)
 	require.Error(t, err, expectedErr, msg.String())
 
 	// Testing remoteReadChildResp()
 	msg = &LightRequest{
-		RemoteReadChildRequest: &RemoteReadChildRequest{},
+		RemoteReadChildRequest: &RemoteReadChildRequest{Block: []byte{1}},
 	}
 	err = s.handleLightMsg(stream, msg)
 	require.Error(t, err, expectedErr, msg.String())
@@ -0,0 +1,263 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func decodeLightProof(t *testing.T, encoded []byte) [][]byte {
+	t.Helper()
+
+	var proofNodes [][]byte
+	require.NoError(t, scale.Unmarshal(encoded, &proofNodes))
+	return proofNodes
+}
+
+// requireProvenValue requires the proof nodes to prove the value at the key of the
+// trie with the given root, a nil value proving the key does not exist
+func requireProvenValue(t *testing.T, proofNodes [][]byte, root common.Hash, key, value []byte) {
+	t.Helper()
+
+	proofDB, err := db.NewMemoryDBFromProof(proofNodes)
+	require.NoError(t, err)
+
+	proofTrie := triedb.NewStateTrie(root, proofDB, nil)
+	proven, err := triedb.GetWith(proofTrie.TrieDB, key, func(data []byte) []byte { return data })
+	require.NoError(t, err)
+	if value == nil {
+		require.Nil(t, proven)
+		return
+	}
+	require.NotNil(t, proven)
+	require.Equal(t, value, *proven)
+}
+
+func TestRemoteReadResp(t *testing.T) {
+	t.Parallel()
+
+	service, stateTrie, blockHash := newStateRequestTestService(t)
+	root := stateTrie.MustHash()
+
+	resp, err := service.remoteReadResp(&RemoteReadRequest{
+		Block: blockHash[:],
+		Keys:  [][]byte{[]byte("key-01"), []byte("key-42"), []byte("missing")},
+	})
+	require.NoError(t, err)
+
+	proofNodes := decodeLightProof(t, resp.Proof)
+	requireProvenValue(t, proofNodes, root, []byte("key-01"), []byte("value-01"))
+	requireProvenValue(t, proofNodes, root, []byte("key-42"), []byte("value-42"))
+	requireProvenValue(t, proofNodes, root, []byte("missing"), nil)
+
+	_, err = service.remoteReadResp(&RemoteReadRequest{Block: []byte{1, 2}})
+	require.ErrorIs(t, err, errInvalidBlockHash)
+}
+
+func TestRemoteReadChildResp(t *testing.T) {
+	t.Parallel()
+
+	service, stateTrie, blockHash := newStateRequestTestService(t)
+	root := stateTrie.MustHash()
+	childTrie, err := stateTrie.GetChild([]byte("child"))
+	require.NoError(t, err)
+	childRoot := childTrie.MustHash()
+
+	resp, err := service.remoteReadChildResp(&RemoteReadChildRequest{
+		Block:      blockHash[:],
+		StorageKey: []byte(":child_storage:default:child"),
+		Keys:       [][]byte{[]byte("child-key-07"), []byte("child-missing")},
+	})
+	require.NoError(t, err)
+
+	proofNodes := decodeLightProof(t, resp.Proof)
+	requireProvenValue(t, proofNodes, root, []byte(":child_storage:default:child"), childRoot[:])
+	requireProvenValue(t, proofNodes, childRoot, []byte("child-key-07"), []byte("child-value-07"))
+	requireProvenValue(t, proofNodes, childRoot, []byte("child-missing"), nil)
+
+	// the absence of a child trie is proven with the absence of its root
+	resp, err = service.remoteReadChildResp(&RemoteReadChildRequest{
+		Block:      blockHash[:],
+		StorageKey: []byte("missing"),
+		Keys:       [][]byte{[]byte("child-key-07")},
+	})
+	require.NoError(t, err)
+
+	proofNodes = decodeLightProof(t, resp.Proof)
+	requireProvenValue(t, proofNodes, root, []byte(":child_storage:default:missing"), nil)
+}
+
+func TestRemoteCallResp(t *testing.T) {
+	t.Parallel()
+
+	service, stateTrie, blockHash := newStateRequestTestService(t)
+	root := stateTrie.MustHash()
+	childTrie, err := stateTrie.GetChild([]byte("child"))
+	require.NoError(t, err)
+	childRoot := childTrie.MustHash()
+
+	ctrl := gomock.NewController(t)
+	instance := mocks.NewMockInstance(ctrl)
+	var callStorage runtime.Storage
+	instance.EXPECT().SetContextStorage(gomock.Any()).Do(func(s runtime.Storage) {
+		callStorage = s
+	})
+	instance.EXPECT().Exec("Core_version", []byte{1}).
+		DoAndReturn(func(string, []byte) ([]byte, error) {
+			require.Equal(t, []byte("value-03"), callStorage.Get([]byte("key-03")))
+			require.Nil(t, callStorage.Get([]byte("key-99")))
+			value, err := callStorage.GetChildStorage([]byte("child"), []byte("child-key-11"))
+			require.NoError(t, err)
+			require.Equal(t, []byte("child-value-11"), value)
+			require.NoError(t, callStorage.Put([]byte("key-04"), []byte("changed")))
+			return []byte{2}, nil
+		})
+
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().GetRuntime(blockHash).Return(instance, nil)
+	service.blockState = blockState
+
+	resp, err := service.remoteCallResp(&RemoteCallRequest{
+		Block:  blockHash[:],
+		Method: "Core_version",
+		Data:   []byte{1},
+	})
+	require.NoError(t, err)
+
+	proofNodes := decodeLightProof(t, resp.Proof)
+	requireProvenValue(t, proofNodes, root, []byte("key-03"), []byte("value-03"))
+	requireProvenValue(t, proofNodes, root, []byte("key-99"), nil)
+	requireProvenValue(t, proofNodes, root, []byte(":child_storage:default:child"), childRoot[:])
+	requireProvenValue(t, proofNodes, childRoot, []byte("child-key-11"), []byte("child-value-11"))
+
+	// the runtime call changes are discarded
+	require.Equal(t, []byte("value-04"), stateTrie.Get([]byte("key-04")))
+}
+
+func TestRemoteHeaderResp(t *testing.T) {
+	t.Parallel()
+
+	headers := make([]*types.Header, 4)
+	parentHash := common.Hash{}
+	for number := range headers {
+		headers[number] = types.NewHeader(parentHash, common.Hash{}, common.Hash{}, uint(number), nil)
+		parentHash = headers[number].Hash()
+	}
+	justification := []byte("justification")
+
+	newBlockState := func(ctrl *gomock.Controller) *MockBlockState {
+		blockState := NewMockBlockState(ctrl)
+		blockState.EXPECT().GetHighestFinalisedHeader().Return(headers[2], nil).AnyTimes()
+		blockState.EXPECT().GetHeaderByNumber(gomock.Any()).
+			DoAndReturn(func(number uint) (*types.Header, error) {
+				return headers[number], nil
+			}).AnyTimes()
+		blockState.EXPECT().GetJustification(gomock.Any()).
+			DoAndReturn(func(hash common.Hash) ([]byte, error) {
+				if hash == headers[2].Hash() {
+					return justification, nil
+				}
+				return nil, database.ErrNotFound
+			}).AnyTimes()
+		return blockState
+	}
+
+	encodedNumber := func(number uint32) []byte {
+		return scale.MustMarshal(number)
+	}
+
+	testCases := map[string]struct {
+		block          []byte
+		expectedHeader *types.Header
+		expectedProof  *headerProof
+		expectedErr    error
+	}{
+		"header_with_descendant_justification": {
+			block:          encodedNumber(1),
+			expectedHeader: headers[1],
+			expectedProof: &headerProof{
+				Headers:       []types.Header{*headers[2]},
+				Justification: justification,
+			},
+		},
+		"header_with_justification": {
+			block:          encodedNumber(2),
+			expectedHeader: headers[2],
+			expectedProof: &headerProof{
+				Headers:       []types.Header{},
+				Justification: justification,
+			},
+		},
+		"header_not_finalised": {
+			block:       encodedNumber(3),
+			expectedErr: errBlockNotFinalised,
+		},
+		"invalid_block_number": {
+			block:       []byte{1},
+			expectedErr: errInvalidBlockNumber,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			service := &Service{blockState: newBlockState(gomock.NewController(t))}
+			resp, err := service.remoteHeaderResp(&RemoteHeaderRequest{Block: testCase.block})
+			if testCase.expectedErr != nil {
+				require.ErrorIs(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []*types.Header{testCase.expectedHeader}, resp.Header)
+
+			decoded := headerProof{}
+			require.NoError(t, scale.Unmarshal(resp.Proof, &decoded))
+			require.Equal(t, testCase.expectedProof.Justification, decoded.Justification)
+			require.Len(t, decoded.Headers, len(testCase.expectedProof.Headers))
+			for idx := range decoded.Headers {
+				require.Equal(t, testCase.expectedProof.Headers[idx].Hash(), decoded.Headers[idx].Hash())
+			}
+		})
+	}
+
+	t.Run("justification_too_far", func(t *testing.T) {
+		t.Parallel()
+
+		finalised := types.NewHeader(common.Hash{}, common.Hash{}, common.Hash{}, maxHeaderProofHeaders+1, nil)
+		blockState := NewMockBlockState(gomock.NewController(t))
+		blockState.EXPECT().GetHighestFinalisedHeader().Return(finalised, nil)
+		blockState.EXPECT().GetHeaderByNumber(gomock.Any()).
+			DoAndReturn(func(number uint) (*types.Header, error) {
+				return types.NewHeader(common.Hash{}, common.Hash{}, common.Hash{}, number, nil), nil
+			}).Times(maxHeaderProofHeaders + 1)
+		blockState.EXPECT().GetJustification(gomock.Any()).
+			Return(nil, database.ErrNotFound).Times(maxHeaderProofHeaders + 1)
+
+		service := &Service{blockState: blockState}
+		resp, err := service.remoteHeaderResp(&RemoteHeaderRequest{Block: encodedNumber(0)})
+		require.NoError(t, err)
+		require.Equal(t, uint(0), resp.Header[0].Number)
+		require.Empty(t, resp.Proof)
+	})
+}
+
+func TestRemoteChangeResp(t *testing.T) {
+	t.Parallel()
+
+	_, err := remoteChangeResp(&RemoteChangesRequest{})
+	require.ErrorIs(t, err, errChangesTriesNotSupported)
+}
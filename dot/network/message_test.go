@@ -96,6 +96,29 @@ func TestEncodeBlockResponseMessage_WithBody(t *testing.T) {
 
 }
 
+func TestEncodeBlockResponseMessage_WithIndexedBody(t *testing.T) {
+	t.Parallel()
+
+	exp := common.MustHexToBytes("0x0a2a0a2000000000000000000000000000000000000000000000000000000000000000004203010203420104") //nolint:lll
+	bd := &types.BlockData{
+		Hash:        common.NewHash([]byte{0}),
+		IndexedBody: &[][]byte{{1, 2, 3}, {4}},
+	}
+
+	bm := &messages.BlockResponseMessage{
+		BlockData: []*types.BlockData{bd},
+	}
+
+	enc, err := bm.Encode()
+	require.NoError(t, err)
+	require.Equal(t, exp, enc)
+
+	act := new(messages.BlockResponseMessage)
+	err = act.Decode(enc)
+	require.NoError(t, err)
+	require.Equal(t, bm, act)
+}
+
 func TestEncodeBlockResponseMessage_WithAll(t *testing.T) {
 	t.Parallel()
 
@@ -49,6 +49,7 @@ func (s SyncDirection) String() string {
 	RequestedDataReceipt       = byte(4)
 	RequestedDataMessageQueue  = byte(8)
 	RequestedDataJustification = byte(16)
+	RequestedDataIndexedBody   = byte(32)
 	BootstrapRequestData       = RequestedDataHeader +
 		RequestedDataBody +
 		RequestedDataJustification
@@ -369,6 +370,10 @@ func blockDataToProtobuf(bd *types.BlockData) (*pb.BlockData, error) {
 		}
 	}
 
+	if bd.IndexedBody != nil {
+		p.IndexedBody = *bd.IndexedBody
+	}
+
 	return p, nil
 }
 
@@ -420,5 +425,9 @@ func protobufToBlockData(pbd *pb.BlockData) (*types.BlockData, error) {
 		bd.Justification = &[]byte{}
 	}
 
+	if pbd.IndexedBody != nil {
+		bd.IndexedBody = &pbd.IndexedBody
+	}
+
 	return bd, nil
 }
@@ -5,9 +5,11 @@
 
 import (
 	"fmt"
+	"strings"
 
 	pb "github.com/ChainSafe/gossamer/dot/network/proto"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	"google.golang.org/protobuf/proto"
 )
@@ -23,9 +25,14 @@ type StateRequest struct {
 }
 
 func (s *StateRequest) String() string {
-	return fmt.Sprintf("StateRequest Block=%s Start=[0x%x, 0x%x] NoProof=%v",
+	start := make([]string, len(s.Start))
+	for idx, key := range s.Start {
+		start[idx] = fmt.Sprintf("0x%x", key)
+	}
+
+	return fmt.Sprintf("StateRequest Block=%s Start=[%s] NoProof=%v",
 		s.Block.String(),
-		s.Start[0], s.Start[1],
+		strings.Join(start, ", "),
 		s.NoProof,
 	)
 }
@@ -54,17 +61,77 @@ func (s *StateRequest) Decode(in []byte) error {
 	return nil
 }
 
+var _ P2PMessage = (*StateResponse)(nil)
+
+// StateResponse contains the key-value entries of the state requested if the request
+// was made with NoProof, otherwise it only contains the SCALE encoded compact proof
+// of the trie nodes holding those entries, from which they are read
 type StateResponse struct {
 	Entries []KeyValueStateEntry
 	Proof   []byte
 }
 
+// KeyValueStateEntry is a batch of entries of a single trie, the main trie
+// has an empty StateRoot while child tries are identified by their root
 type KeyValueStateEntry struct {
 	StateRoot    common.Hash
 	StateEntries trie.Entries
 	Complete     bool
 }
 
+func (s *StateResponse) String() string {
+	total := 0
+	for _, entry := range s.Entries {
+		total += len(entry.StateEntries)
+	}
+
+	return fmt.Sprintf("StateResponse Tries=%d Entries=%d ProofSize=%d",
+		len(s.Entries), total, len(s.Proof))
+}
+
+// ProofNodes decodes the proof into the list of encoded trie nodes of the compact proof
+func (s *StateResponse) ProofNodes() (nodes [][]byte, err error) {
+	if len(s.Proof) == 0 {
+		return nil, nil
+	}
+
+	err = scale.Unmarshal(s.Proof, &nodes)
+	if err != nil {
+		return nil, fmt.Errorf("decoding state proof: %w", err)
+	}
+	return nodes, nil
+}
+
+func (s *StateResponse) Encode() ([]byte, error) {
+	message := &pb.StateResponse{
+		Entries: make([]*pb.KeyValueStateEntry, len(s.Entries)),
+		Proof:   s.Proof,
+	}
+
+	for idx, entry := range s.Entries {
+		var stateRoot []byte
+		if !entry.StateRoot.IsEmpty() {
+			stateRoot = entry.StateRoot.ToBytes()
+		}
+
+		stateEntries := make([]*pb.StateEntry, len(entry.StateEntries))
+		for entryIdx, stateEntry := range entry.StateEntries {
+			stateEntries[entryIdx] = &pb.StateEntry{
+				Key:   stateEntry.Key,
+				Value: stateEntry.Value,
+			}
+		}
+
+		message.Entries[idx] = &pb.KeyValueStateEntry{
+			StateRoot: stateRoot,
+			Entries:   stateEntries,
+			Complete:  entry.Complete,
+		}
+	}
+
+	return proto.Marshal(message)
+}
+
 func (s *StateResponse) Decode(in []byte) error {
 	decodedResponse := &pb.StateResponse{}
 	err := proto.Unmarshal(in, decodedResponse)
@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ChainSafe/gossamer/dot/network (interfaces: StorageState)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock_storage_state_test.go -package network . StorageState
+//
+
+// Package network is a generated GoMock package.
+package network
+
+import (
+	reflect "reflect"
+
+	common "github.com/ChainSafe/gossamer/lib/common"
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	triedb "github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStorageState is a mock of StorageState interface.
+type MockStorageState struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageStateMockRecorder
+}
+
+// MockStorageStateMockRecorder is the mock recorder for MockStorageState.
+type MockStorageStateMockRecorder struct {
+	mock *MockStorageState
+}
+
+// NewMockStorageState creates a new mock instance.
+func NewMockStorageState(ctrl *gomock.Controller) *MockStorageState {
+	mock := &MockStorageState{ctrl: ctrl}
+	mock.recorder = &MockStorageStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageState) EXPECT() *MockStorageStateMockRecorder {
+	return m.recorder
+}
+
+// GetStateRootFromBlock mocks base method.
+func (m *MockStorageState) GetStateRootFromBlock(arg0 *common.Hash) (*common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStateRootFromBlock", arg0)
+	ret0, _ := ret[0].(*common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStateRootFromBlock indicates an expected call of GetStateRootFromBlock.
+func (mr *MockStorageStateMockRecorder) GetStateRootFromBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStateRootFromBlock", reflect.TypeOf((*MockStorageState)(nil).GetStateRootFromBlock), arg0)
+}
+
+// RecordingTrieState mocks base method.
+func (m *MockStorageState) RecordingTrieState(arg0 common.Hash, arg1 triedb.TrieRecorder) (*storage.TrieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordingTrieState", arg0, arg1)
+	ret0, _ := ret[0].(*storage.TrieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordingTrieState indicates an expected call of RecordingTrieState.
+func (mr *MockStorageStateMockRecorder) RecordingTrieState(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordingTrieState", reflect.TypeOf((*MockStorageState)(nil).RecordingTrieState), arg0, arg1)
+}
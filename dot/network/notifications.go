@@ -58,6 +58,7 @@ type handshakeReader struct {
 
 type notificationsProtocol struct {
 	protocolID         protocol.ID
+	setID              int
 	getHandshake       HandshakeGetter
 	handshakeDecoder   HandshakeDecoder
 	handshakeValidator HandshakeValidator
@@ -65,10 +66,25 @@ type notificationsProtocol struct {
 	maxSize            uint64
 }
 
-func newNotificationsProtocol(protocolID protocol.ID, handshakeGetter HandshakeGetter,
+// peerSetID returns the id of the peer set of the notifications protocol with the given message type
+func peerSetID(messageType MessageType) (setID int, err error) {
+	switch messageType {
+	case blockAnnounceMsgType:
+		return blockAnnounceSetID, nil
+	case transactionMsgType:
+		return transactionsSetID, nil
+	case ConsensusMsgType:
+		return grandpaSetID, nil
+	default:
+		return 0, fmt.Errorf("%w: %d", errMessageTypeNotValid, messageType)
+	}
+}
+
+func newNotificationsProtocol(protocolID protocol.ID, setID int, handshakeGetter HandshakeGetter,
 	handshakeDecoder HandshakeDecoder, handshakeValidator HandshakeValidator, maxSize uint64) *notificationsProtocol {
 	return &notificationsProtocol{
 		protocolID:         protocolID,
+		setID:              setID,
 		getHandshake:       handshakeGetter,
 		handshakeValidator: handshakeValidator,
 		handshakeDecoder:   handshakeDecoder,
@@ -195,6 +211,10 @@ func (s *Service) handleHandshake(info *notificationsProtocol, stream network.St
 	// note: if this function is being called, it's being called via SetStreamHandler,
 	// ie it is an inbound stream and we only send the handshake over it.
 	// we do not send any other data over this stream, we would need to open a new outbound stream.
+	if info.peersData.isRefused(peer) {
+		return fmt.Errorf("%w: for peer id %s using protocol %s", errPeerRefused, peer, info.protocolID)
+	}
+
 	hsData := info.peersData.getInboundHandshakeData(peer)
 	if hsData != nil {
 		return fmt.Errorf("%w: for peer id %s", errInboundHanshakeExists, peer)
@@ -230,6 +250,27 @@ func (s *Service) handleHandshake(info *notificationsProtocol, stream network.St
 	return nil
 }
 
+// refuseStreams closes the streams of the notifications protocol with the peer and
+// refuses new ones until the peer is accepted again by the peer set of the protocol.
+func refuseStreams(info *notificationsProtocol, peerID peer.ID) {
+	info.peersData.setRefused(peerID, true)
+
+	hsData := info.peersData.getInboundHandshakeData(peerID)
+	if hsData != nil && hsData.stream != nil {
+		err := hsData.stream.Reset()
+		if err != nil {
+			logger.Warnf("failed to reset inbound stream: %s", err)
+		}
+	}
+	info.peersData.deleteInboundHandshakeData(peerID)
+
+	hsData = info.peersData.getOutboundHandshakeData(peerID)
+	if hsData != nil && hsData.stream != nil {
+		closeOutboundStream(info, peerID, hsData.stream)
+	}
+	info.peersData.deleteOutboundHandshakeData(peerID)
+}
+
 func closeOutboundStream(info *notificationsProtocol, peerID peer.ID, stream network.Stream) {
 	logger.Debugf(
 		"cleaning up outbound handshake data for protocol=%s, peer=%s",
@@ -251,6 +292,11 @@ func (s *Service) sendData(peer peer.ID, hs Handshake, info *notificationsProtoc
 		return
 	}
 
+	if info.peersData.isRefused(peer) {
+		logger.Tracef("not sending message to peer %s refused on protocol %s", peer, info.protocolID)
+		return
+	}
+
 	support, err := s.host.supportsProtocol(peer, info.protocolID)
 	if err != nil {
 		logger.Errorf("could not check if protocol %s is supported by peer %s: %s", info.protocolID, peer, err)
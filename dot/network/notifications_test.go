@@ -253,8 +253,9 @@ func Test_HandshakeTimeout(t *testing.T) {
 	testHandshakeDecoder := func([]byte) (Handshake, error) {
 		return nil, errors.New("unimplemented")
 	}
-	info := newNotificationsProtocol(nodeA.host.protocolID+blockAnnounceID, nodeA.getBlockAnnounceHandshake,
-		testHandshakeDecoder, nodeA.validateBlockAnnounceHandshake, maxBlockAnnounceNotificationSize)
+	info := newNotificationsProtocol(nodeA.host.protocolID+blockAnnounceID, blockAnnounceSetID,
+		nodeA.getBlockAnnounceHandshake, testHandshakeDecoder, nodeA.validateBlockAnnounceHandshake,
+		maxBlockAnnounceNotificationSize)
 
 	nodeB.host.p2pHost.SetStreamHandler(info.protocolID, func(stream libp2pnetwork.Stream) {
 		// should not respond to a handshake message
@@ -426,3 +427,56 @@ func TestBlockAnnounceHandshakeSize(t *testing.T) {
 
 	require.Equal(t, unsafe.Sizeof(BlockAnnounceHandshake{}), reflect.TypeOf(BlockAnnounceHandshake{}).Size())
 }
+
+func Test_peerSetID(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		messageType MessageType
+		setID       int
+		errWrapped  error
+	}{
+		"block_announce": {
+			messageType: blockAnnounceMsgType,
+			setID:       blockAnnounceSetID,
+		},
+		"transaction": {
+			messageType: transactionMsgType,
+			setID:       transactionsSetID,
+		},
+		"consensus": {
+			messageType: ConsensusMsgType,
+			setID:       grandpaSetID,
+		},
+		"unknown": {
+			messageType: 0,
+			errWrapped:  errMessageTypeNotValid,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			setID, err := peerSetID(testCase.messageType)
+			require.ErrorIs(t, err, testCase.errWrapped)
+			require.Equal(t, testCase.setID, setID)
+		})
+	}
+}
+
+func Test_refuseStreams(t *testing.T) {
+	t.Parallel()
+
+	const peerID = peer.ID("peer")
+	info := newNotificationsProtocol("/test/1", transactionsSetID, nil, nil, nil, maxMessageSize)
+	info.peersData.setInboundHandshakeData(peerID, newHandshakeData(true, true, nil))
+
+	refuseStreams(info, peerID)
+	require.True(t, info.peersData.isRefused(peerID))
+	require.Nil(t, info.peersData.getInboundHandshakeData(peerID))
+
+	info.peersData.setRefused(peerID, false)
+	require.False(t, info.peersData.isRefused(peerID))
+}
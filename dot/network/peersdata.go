@@ -16,6 +16,9 @@ type peersData struct {
 	inbound    map[peer.ID]*handshakeData
 	outboundMu sync.RWMutex
 	outbound   map[peer.ID]*handshakeData
+	// refused holds the connected peers refused by the peer set of the protocol
+	refusedMu sync.RWMutex
+	refused   map[peer.ID]struct{}
 }
 
 func newPeersData() *peersData {
@@ -23,6 +26,7 @@ func newPeersData() *peersData {
 		mutexes:  make(map[peer.ID]*sync.Mutex),
 		inbound:  make(map[peer.ID]*handshakeData),
 		outbound: make(map[peer.ID]*handshakeData),
+		refused:  make(map[peer.ID]struct{}),
 	}
 }
 
@@ -101,3 +105,20 @@ func (p *peersData) countOutboundStreams() (count int64) {
 	}
 	return count
 }
+
+func (p *peersData) setRefused(peerID peer.ID, refused bool) {
+	p.refusedMu.Lock()
+	defer p.refusedMu.Unlock()
+	if refused {
+		p.refused[peerID] = struct{}{}
+		return
+	}
+	delete(p.refused, peerID)
+}
+
+func (p *peersData) isRefused(peerID peer.ID) bool {
+	p.refusedMu.RLock()
+	defer p.refusedMu.RUnlock()
+	_, refused := p.refused[peerID]
+	return refused
+}
@@ -34,6 +34,7 @@
 	// the following are sub-protocols used by the node
 	SyncID          = "/sync/2"
 	WarpSyncID      = "/sync/warp"
+	StateID         = "/state/2"
 	lightID         = "/light/2"
 	blockAnnounceID = "/block-announces/1"
 	transactionsID  = "/transactions/1"
@@ -131,6 +132,7 @@ type Service struct {
 	syncer             Syncer
 	transactionHandler TransactionHandler
 	warpSyncProvider   WarpSyncProvider
+	storageState       StorageState
 
 	// Configuration options
 	noBootstrap bool
@@ -221,6 +223,7 @@ func NewService(cfg *Config) (*Service, error) {
 		noMDNS:                 cfg.NoMDNS,
 		syncer:                 cfg.Syncer,
 		warpSyncProvider:       cfg.WarpSyncProvider,
+		storageState:           cfg.StorageState,
 		notificationsProtocols: make(map[MessageType]*notificationsProtocol),
 		lightRequest:           make(map[peer.ID]struct{}),
 		telemetryInterval:      cfg.telemetryInterval,
@@ -268,6 +271,11 @@ func (s *Service) Start() error {
 	s.host.registerStreamHandler(s.host.protocolID+lightID, s.handleLightStream)
 	s.host.registerStreamHandler(protocol.ID(warpSyncProtocolId), s.handleWarpSyncStream)
 
+	if s.storageState != nil {
+		stateProtocolId := fmt.Sprintf("/%s%s", genesisHash, StateID)
+		s.host.registerStreamHandler(protocol.ID(stateProtocolId), s.handleStateStream)
+	}
+
 	// register block announce protocol
 	err := s.RegisterNotificationsProtocol(
 		s.host.protocolID+blockAnnounceID,
@@ -312,8 +320,9 @@ func (s *Service) Start() error {
 		for _, prtl := range s.notificationsProtocols {
 			prtl.peersData.setMutex(peerID)
 		}
-		const setID = 0
-		s.host.cm.peerSetHandler.Incoming(setID, peerID)
+		for setID := 0; setID < numPeerSets; setID++ {
+			s.host.cm.peerSetHandler.Incoming(setID, peerID)
+		}
 	}
 
 	// when a peer gets disconnected, we should clear all handshake data we have for it.
@@ -322,6 +331,7 @@ func (s *Service) Start() error {
 			prtl.peersData.deleteMutex(peerID)
 			prtl.peersData.deleteInboundHandshakeData(peerID)
 			prtl.peersData.deleteOutboundHandshakeData(peerID)
+			prtl.peersData.setRefused(peerID, false)
 		}
 	}
 
@@ -531,7 +541,12 @@ func (s *Service) RegisterNotificationsProtocol(
 		return errors.New("notifications protocol with message type already exists")
 	}
 
-	np := newNotificationsProtocol(protocolID, handshakeGetter, handshakeDecoder, handshakeValidator, maxSize)
+	setID, err := peerSetID(messageID)
+	if err != nil {
+		return fmt.Errorf("getting peer set id: %w", err)
+	}
+
+	np := newNotificationsProtocol(protocolID, setID, handshakeGetter, handshakeDecoder, handshakeValidator, maxSize)
 	s.notificationsProtocols[messageID] = np
 	decoder := createDecoder(np, handshakeDecoder, messageDecoder)
 	handlerWithValidate := s.createNotificationsMessageHandler(np, messageHandler, batchHandler)
@@ -748,8 +763,18 @@ func (s *Service) processMessage(msg peerset.Message) {
 		logger.Errorf("found empty peer id in peerset message")
 		return
 	}
+	// the connection with the peer is handled by the block announces set, the sets of the
+	// other notifications protocols only accept or refuse the streams of their protocol.
+	setID := msg.SetID()
 	switch msg.Status {
 	case peerset.Connect:
+		if setID != blockAnnounceSetID {
+			s.acceptPeer(setID, peerID)
+			if s.host.p2pHost.Network().Connectedness(peerID) == libp2pnetwork.Connected {
+				return
+			}
+		}
+
 		addrInfo := s.host.p2pHost.Peerstore().PeerInfo(peerID)
 		if len(addrInfo.Addrs) == 0 {
 			var err error
@@ -769,7 +794,16 @@ func (s *Service) processMessage(msg peerset.Message) {
 			return
 		}
 		logger.Debugf("connection successful with peer %s", peerID)
+	case peerset.Accept:
+		if setID != blockAnnounceSetID {
+			s.acceptPeer(setID, peerID)
+		}
 	case peerset.Drop, peerset.Reject:
+		if setID != blockAnnounceSetID {
+			s.refusePeer(setID, peerID)
+			return
+		}
+
 		err := s.host.closePeer(peerID)
 		if err != nil {
 			logger.Warnf("failed to close connection with peer %s: %s", peerID, err)
@@ -780,6 +814,33 @@ func (s *Service) processMessage(msg peerset.Message) {
 	}
 }
 
+// acceptPeer accepts the streams with the peer of the notifications protocols of the peer set
+func (s *Service) acceptPeer(setID int, peerID peer.ID) {
+	for _, prtl := range s.notificationsProtocolsOfSet(setID) {
+		prtl.peersData.setRefused(peerID, false)
+	}
+}
+
+// refusePeer closes and refuses the streams with the peer of the notifications protocols of the peer set
+func (s *Service) refusePeer(setID int, peerID peer.ID) {
+	for _, prtl := range s.notificationsProtocolsOfSet(setID) {
+		refuseStreams(prtl, peerID)
+		logger.Debugf("refused peer %s on protocol %s", peerID, prtl.protocolID)
+	}
+}
+
+func (s *Service) notificationsProtocolsOfSet(setID int) (protocols []*notificationsProtocol) {
+	s.notificationsMu.Lock()
+	defer s.notificationsMu.Unlock()
+
+	for _, prtl := range s.notificationsProtocols {
+		if prtl.setID == setID {
+			protocols = append(protocols, prtl)
+		}
+	}
+	return protocols
+}
+
 // startProcessingMsg function that listens to messages from the channel that belongs to PeerSet PeerSetHandler.
 func (s *Service) startProcessingMsg() {
 	msgCh := s.host.cm.peerSetHandler.Messages()
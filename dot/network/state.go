@@ -12,6 +12,9 @@
 	"github.com/ChainSafe/gossamer/dot/peerset"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
 )
 
 // BlockState interface for block state methods
@@ -19,6 +22,15 @@ type BlockState interface {
 	BestBlockHeader() (*types.Header, error)
 	GenesisHash() common.Hash
 	GetHighestFinalisedHeader() (*types.Header, error)
+	GetHeaderByNumber(num uint) (*types.Header, error)
+	GetJustification(hash common.Hash) ([]byte, error)
+	GetRuntime(blockHash common.Hash) (runtime.Instance, error)
+}
+
+// StorageState interface for storage state methods
+type StorageState interface {
+	GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error)
+	RecordingTrieState(root common.Hash, recorder triedb.TrieRecorder) (*storage.TrieState, error)
 }
 
 // Syncer is implemented by the syncing service
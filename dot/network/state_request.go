@@ -0,0 +1,230 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/proof"
+	libp2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// MaxStateResponseSize is the soft limit, in bytes, of the key-value entries
+// carried by a single state response
+const MaxStateResponseSize = 2 * 1024 * 1024
+
+var (
+	errTooManyStartKeys  = errors.New("too many start keys")
+	errInvalidChildStart = errors.New("start key is not a child storage key")
+)
+
+// handleStateStream handles streams with the <genesis-hash>/state/2 protocol ID
+func (s *Service) handleStateStream(stream libp2pnetwork.Stream) {
+	if stream == nil {
+		return
+	}
+
+	s.readStream(stream, decodeStateRequestMessage, s.handleStateRequestMessage, MaxBlockResponseSize)
+}
+
+func decodeStateRequestMessage(in []byte, _ peer.ID, _ bool) (messages.P2PMessage, error) {
+	msg := new(messages.StateRequest)
+	err := msg.Decode(in)
+	return msg, err
+}
+
+// handleStateRequestMessage handles inbound state request streams
+func (s *Service) handleStateRequestMessage(stream libp2pnetwork.Stream, msg messages.P2PMessage) error {
+	if msg == nil {
+		return nil
+	}
+
+	defer func() {
+		err := stream.Close()
+		if err != nil && err.Error() != ErrStreamReset.Error() {
+			logger.Warnf("failed to close stream: %s", err)
+		}
+	}()
+
+	req, ok := msg.(*messages.StateRequest)
+	if !ok {
+		logger.Debugf("received invalid message in state request handler: %v", msg)
+		return nil
+	}
+
+	resp, err := s.createStateResponse(req, MaxStateResponseSize)
+	if err != nil {
+		logger.Debugf("cannot create response for request %s: %s", req, err)
+		return nil
+	}
+
+	if err = s.host.writeToStream(stream, resp); err != nil {
+		logger.Debugf("failed to send StateResponse message to peer %s: %s", stream.Conn().RemotePeer(), err)
+		return err
+	}
+
+	return nil
+}
+
+// stateCollector accumulates state entries until the response size limit is reached
+type stateCollector struct {
+	size     int
+	limit    int
+	top      messages.KeyValueStateEntry
+	children []messages.KeyValueStateEntry
+}
+
+func (c *stateCollector) full() bool {
+	return c.size >= c.limit
+}
+
+// collect adds to the entry the keys of the given trie that are after the start key.
+// The start key itself is not included. It returns true if the end of the trie was reached
+func (c *stateCollector) collect(entry *messages.KeyValueStateEntry, tr trie.Trie, start []byte) (complete bool) {
+	for key := range tr.KeysFrom(start) {
+		if c.full() {
+			return false
+		}
+
+		value := tr.Get(key)
+		entry.StateEntries = append(entry.StateEntries, trie.Entry{Key: key, Value: value})
+		c.size += len(key) + len(value)
+	}
+
+	return true
+}
+
+// collectChild adds a new entry with the child trie keys after the start key
+func (c *stateCollector) collectChild(stateTrie trie.Trie, childStorageKey, start []byte) (complete bool, err error) {
+	if !bytes.HasPrefix(childStorageKey, inmemory.ChildStorageKeyPrefix) {
+		return false, fmt.Errorf("%w: 0x%x", errInvalidChildStart, childStorageKey)
+	}
+
+	childTrie, err := stateTrie.GetChild(childStorageKey[len(inmemory.ChildStorageKeyPrefix):])
+	if err != nil {
+		return false, fmt.Errorf("getting child trie: %w", err)
+	}
+
+	entry := messages.KeyValueStateEntry{
+		StateRoot: common.BytesToHash(stateTrie.Get(childStorageKey)),
+	}
+
+	complete = c.collect(&entry, childTrie, start)
+	entry.Complete = complete
+	c.children = append(c.children, entry)
+	return complete, nil
+}
+
+// createStateResponse collects the key-value entries of the requested block state that come after
+// the request start keys, including the entries of the child tries found along the way,
+// until the size limit is reached. The entries are sent if the request is made with NoProof,
+// otherwise only the compact proof of the trie nodes read while collecting them is sent,
+// from which the requester reads the entries.
+func (s *Service) createStateResponse(req *messages.StateRequest, limit int) (*messages.StateResponse, error) {
+	if len(req.Start) > 2 {
+		return nil, fmt.Errorf("%w: %d", errTooManyStartKeys, len(req.Start))
+	}
+
+	stateRoot, err := s.storageState.GetStateRootFromBlock(&req.Block)
+	if err != nil {
+		return nil, fmt.Errorf("getting state root of block %s: %w", req.Block, err)
+	}
+
+	recorder := triedb.NewRecorder[chash.H256]()
+	trieState, err := s.storageState.RecordingTrieState(*stateRoot, recorder)
+	if err != nil {
+		return nil, fmt.Errorf("getting state of block %s: %w", req.Block, err)
+	}
+	stateTrie := trieState.Trie()
+
+	collector := &stateCollector{limit: limit}
+
+	var topStart []byte
+	if len(req.Start) > 0 {
+		topStart = req.Start[0]
+	}
+
+	topComplete := false
+	childComplete := true
+	// resume the child trie we stopped in the last response
+	if len(req.Start) == 2 {
+		childComplete, err = collector.collectChild(stateTrie, req.Start[0], req.Start[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if childComplete {
+		topComplete = true
+		for key := range stateTrie.KeysFrom(topStart) {
+			if collector.full() {
+				topComplete = false
+				break
+			}
+
+			value := stateTrie.Get(key)
+			collector.top.StateEntries = append(collector.top.StateEntries, trie.Entry{Key: key, Value: value})
+			collector.size += len(key) + len(value)
+
+			if bytes.HasPrefix(key, inmemory.ChildStorageKeyPrefix) {
+				complete, err := collector.collectChild(stateTrie, key, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				if !complete {
+					topComplete = false
+					break
+				}
+			}
+		}
+	}
+	collector.top.Complete = topComplete
+
+	if req.NoProof {
+		return &messages.StateResponse{
+			Entries: append([]messages.KeyValueStateEntry{collector.top}, collector.children...),
+		}, nil
+	}
+
+	proof, err := compactStateProof(*stateRoot, recorder)
+	if err != nil {
+		return nil, fmt.Errorf("generating state proof: %w", err)
+	}
+
+	return &messages.StateResponse{Proof: proof}, nil
+}
+
+// compactStateProof returns the SCALE encoded compact proof of the recorded trie nodes and values
+func compactStateProof(stateRoot common.Hash, recorder *triedb.Recorder[chash.H256]) ([]byte, error) {
+	records := recorder.Drain()
+	recorded := make([][]byte, len(records))
+	for i, record := range records {
+		recorded[i] = record.Data
+	}
+
+	partialDB, err := db.NewMemoryDBFromProof(recorded)
+	if err != nil {
+		return nil, fmt.Errorf("building recorded database: %w", err)
+	}
+
+	compactProof, err := proof.EncodeCompact[chash.H256, runtime.BlakeTwo256](partialDB, chash.H256(stateRoot[:]))
+	if err != nil {
+		return nil, fmt.Errorf("encoding compact proof: %w", err)
+	}
+
+	return scale.Marshal(compactProof)
+}
@@ -0,0 +1,220 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package network
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/internal/database"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/proof"
+	"github.com/stretchr/testify/require"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func newStateRequestTestService(t *testing.T) (*Service, *inmemory.InMemoryTrie, common.Hash) {
+	t.Helper()
+
+	stateTrie := inmemory.NewEmptyTrie()
+	for i := 0; i < 50; i++ {
+		key := []byte(fmt.Sprintf("key-%02d", i))
+		require.NoError(t, stateTrie.Put(key, []byte(fmt.Sprintf("value-%02d", i))))
+	}
+
+	childTrie := inmemory.NewEmptyTrie()
+	for i := 0; i < 20; i++ {
+		key := []byte(fmt.Sprintf("child-key-%02d", i))
+		require.NoError(t, childTrie.Put(key, []byte(fmt.Sprintf("child-value-%02d", i))))
+	}
+	require.NoError(t, stateTrie.SetChild([]byte("child"), childTrie))
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+	require.NoError(t, stateTrie.WriteDirty(db))
+
+	stateRoot := stateTrie.MustHash()
+	blockHash := common.Hash{1, 2, 3}
+
+	ctrl := gomock.NewController(t)
+	storageState := NewMockStorageState(ctrl)
+	storageState.EXPECT().GetStateRootFromBlock(&blockHash).Return(&stateRoot, nil).AnyTimes()
+	storageState.EXPECT().RecordingTrieState(stateRoot, gomock.Any()).
+		DoAndReturn(func(root common.Hash, recorder triedb.TrieRecorder) (*storage.TrieState, error) {
+			return storage.NewTrieState(triedb.NewRecordingStateTrie(root, db, nil, recorder)), nil
+		}).AnyTimes()
+
+	return &Service{storageState: storageState}, stateTrie, blockHash
+}
+
+// decodeCompactStateProof decodes the compact proof of the response into
+// the database of the trie nodes and values proven against the state root
+func decodeCompactStateProof(t *testing.T, resp *messages.StateResponse, stateRoot common.Hash) *db.MemoryDB {
+	t.Helper()
+
+	compactProof, err := resp.ProofNodes()
+	require.NoError(t, err)
+
+	proofDB := db.NewEmptyMemoryDB()
+	root, err := proof.DecodeCompact[chash.H256, runtime.BlakeTwo256](proofDB, compactProof)
+	require.NoError(t, err)
+	require.Equal(t, chash.H256(stateRoot[:]), root)
+	return proofDB
+}
+
+func TestCreateStateResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("paged_responses_with_proof", func(t *testing.T) {
+		t.Parallel()
+
+		service, stateTrie, blockHash := newStateRequestTestService(t)
+		childTrie, err := stateTrie.GetChild([]byte("child"))
+		require.NoError(t, err)
+
+		topEntries := make(map[string][]byte)
+		childEntries := make(map[string][]byte)
+
+		req := &messages.StateRequest{Block: blockHash}
+		for responses := 0; ; responses++ {
+			require.Less(t, responses, 100, "state responses never completed")
+
+			// the entries proven are the ones sent without proof
+			resp, err := service.createStateResponse(req, 200)
+			require.NoError(t, err)
+			require.Empty(t, resp.Entries)
+			proofDB := decodeCompactStateProof(t, resp, stateTrie.MustHash())
+
+			req.NoProof = true
+			resp, err = service.createStateResponse(req, 200)
+			require.NoError(t, err)
+			require.NotEmpty(t, resp.Entries)
+			require.Empty(t, resp.Proof)
+
+			complete := true
+			lastKeys := [][]byte{}
+			if len(req.Start) == 2 && len(resp.Entries[0].StateEntries) == 0 {
+				lastKeys = append(lastKeys, req.Start[0])
+			}
+
+			for _, entry := range resp.Entries {
+				root := stateTrie.MustHash()
+				collected := topEntries
+				if !entry.StateRoot.IsEmpty() {
+					require.Equal(t, childTrie.MustHash(), entry.StateRoot)
+					root = entry.StateRoot
+					collected = childEntries
+				}
+
+				entryTrie := triedb.NewStateTrie(root, proofDB, nil)
+
+				for _, kv := range entry.StateEntries {
+					value, err := triedb.GetWith(entryTrie.TrieDB, kv.Key, func(data []byte) []byte { return data })
+					require.NoError(t, err)
+					require.NotNil(t, value)
+					require.Equal(t, kv.Value, *value)
+					collected[string(kv.Key)] = kv.Value
+				}
+
+				if !entry.Complete {
+					complete = false
+					if len(entry.StateEntries) > 0 {
+						lastKeys = append(lastKeys, entry.StateEntries[len(entry.StateEntries)-1].Key)
+					}
+				}
+			}
+
+			if complete {
+				break
+			}
+			req = &messages.StateRequest{Block: blockHash, Start: lastKeys}
+		}
+
+		require.Equal(t, stateTrie.Entries(), topEntries)
+		require.Equal(t, childTrie.Entries(), childEntries)
+	})
+
+	t.Run("no_proof", func(t *testing.T) {
+		t.Parallel()
+
+		service, stateTrie, blockHash := newStateRequestTestService(t)
+
+		resp, err := service.createStateResponse(&messages.StateRequest{
+			Block:   blockHash,
+			NoProof: true,
+		}, MaxStateResponseSize)
+		require.NoError(t, err)
+		require.Empty(t, resp.Proof)
+		require.Len(t, resp.Entries, 2)
+		require.True(t, resp.Entries[0].Complete)
+		require.True(t, resp.Entries[1].Complete)
+		require.Len(t, resp.Entries[0].StateEntries, len(stateTrie.Entries()))
+	})
+
+	t.Run("start_key_is_excluded", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, blockHash := newStateRequestTestService(t)
+
+		resp, err := service.createStateResponse(&messages.StateRequest{
+			Block:   blockHash,
+			Start:   [][]byte{[]byte("key-48")},
+			NoProof: true,
+		}, MaxStateResponseSize)
+		require.NoError(t, err)
+		require.Len(t, resp.Entries, 1)
+		require.Equal(t, []byte("key-49"), resp.Entries[0].StateEntries[0].Key)
+		require.Len(t, resp.Entries[0].StateEntries, 1)
+	})
+
+	t.Run("too_many_start_keys", func(t *testing.T) {
+		t.Parallel()
+
+		service := &Service{}
+		_, err := service.createStateResponse(&messages.StateRequest{
+			Start: [][]byte{{1}, {2}, {3}},
+		}, MaxStateResponseSize)
+		require.ErrorIs(t, err, errTooManyStartKeys)
+	})
+
+	t.Run("invalid_child_start_key", func(t *testing.T) {
+		t.Parallel()
+
+		service, _, blockHash := newStateRequestTestService(t)
+		_, err := service.createStateResponse(&messages.StateRequest{
+			Block: blockHash,
+			Start: [][]byte{[]byte("key-01"), []byte("key-02")},
+		}, MaxStateResponseSize)
+		require.ErrorIs(t, err, errInvalidChildStart)
+	})
+}
+
+func TestStateResponseEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	service, _, blockHash := newStateRequestTestService(t)
+	resp, err := service.createStateResponse(&messages.StateRequest{Block: blockHash}, MaxStateResponseSize)
+	require.NoError(t, err)
+
+	encoded, err := resp.Encode()
+	require.NoError(t, err)
+
+	decoded := new(messages.StateResponse)
+	require.NoError(t, decoded.Decode(encoded))
+	require.Equal(t, resp.Proof, decoded.Proof)
+	require.Equal(t, len(resp.Entries), len(decoded.Entries))
+	for idx := range resp.Entries {
+		require.Equal(t, resp.Entries[idx].StateRoot, decoded.Entries[idx].StateRoot)
+		require.Equal(t, resp.Entries[idx].Complete, decoded.Entries[idx].Complete)
+		require.Equal(t, resp.Entries[idx].StateEntries, decoded.Entries[idx].StateEntries)
+	}
+}
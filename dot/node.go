@@ -33,6 +33,7 @@
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/manualseal"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/services"
 )
@@ -67,6 +68,8 @@ type nodeBuilderIface interface {
 		telemetryMailer Telemetry) (network.Syncer, error)
 	createBABEService(config *cfg.Config, st *state.Service, ks KeyStore, cs *core.Service,
 		telemetryMailer Telemetry) (service *babe.Service, err error)
+	createManualSealService(config *cfg.Config, st *state.Service, ks KeyStore, cs *core.Service) (
+		*manualseal.Service, error)
 	createSystemService(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error)
 	createRPCService(params rpcServiceSettings) (*rpc.HTTPServer, error)
 }
@@ -390,6 +393,16 @@ func newNode(config *cfg.Config,
 	}
 	nodeSrvcs = append(nodeSrvcs, bp)
 
+	var sealing rpc.SealingAPI
+	if isManualSealing(config) {
+		manualSeal, err := builder.createManualSealService(config, stateSrvc, ks.Babe, coreSrvc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create manual seal service: %w", err)
+		}
+		nodeSrvcs = append(nodeSrvcs, manualSeal)
+		sealing = manualSeal
+	}
+
 	// check if rpc service is enabled
 	if enabled := config.RPC.IsRPCEnabled() || config.RPC.IsWSEnabled(); enabled {
 		var rpcSrvc *rpc.HTTPServer
@@ -400,6 +413,7 @@ func newNode(config *cfg.Config,
 			core:          coreSrvc,
 			network:       networkSrvc,
 			blockProducer: bp,
+			sealing:       sealing,
 			system:        sysSrvc,
 			blockFinality: fg,
 			syncer:        syncer.(rpc.SyncAPI),
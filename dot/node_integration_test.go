@@ -146,7 +146,7 @@ func TestNewNode(t *testing.T) {
 	m.EXPECT().createSystemService(systemInfo, gomock.AssignableToTypeOf(&state.Service{})).
 		DoAndReturn(func(cfg *types.SystemInfo, stateSrvc *state.Service) (*system.Service, error) {
 			gd, err := stateSrvc.Base.LoadGenesisData()
-			systemService := system.NewService(cfg, gd)
+			systemService := system.NewService(cfg, gd, nil)
 			return systemService, err
 		})
 	m.EXPECT().createNetworkService(initConfig, gomock.AssignableToTypeOf(&state.Service{}),
@@ -86,4 +86,9 @@
 	BadWarpProofValue Reputation = -(1 << 29)
 	// BadWarpProofReason is used when peer send invalid warp sync proof.
 	BadWarpProofReason = "Bad warp proof"
+
+	// BadStateResponseValue is used when peer send a state response that fails verification.
+	BadStateResponseValue Reputation = -(1 << 29)
+	// BadStateResponseReason is used when peer send a state response that fails verification.
+	BadStateResponseReason = "Bad state response"
 )
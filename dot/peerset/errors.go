@@ -10,6 +10,8 @@
 
 	ErrConfigSetIsEmpty = errors.New("config set is empty")
 
+	ErrUnknownSet = errors.New("unknown set")
+
 	ErrPeerDoesNotExist = errors.New("peer doesn't exist")
 
 	ErrPeerDisconnected = errors.New("node is already disconnected")
@@ -90,6 +90,7 @@ type action struct {
 	setID         int
 	reputation    ReputationChange
 	peers         peer.IDSlice
+	reservedOnly  bool
 	resultPeersCh chan peer.IDSlice
 }
 
@@ -125,6 +126,11 @@ type Message struct {
 	PeerID peer.ID
 }
 
+// SetID returns the id of the set the message is about.
+func (m Message) SetID() int {
+	return int(m.setID)
+}
+
 // Reputation represents reputation value of the node
 type Reputation int32
 
@@ -174,10 +180,10 @@ type PeerSet struct {
 	peerState *PeersState
 
 	reservedLock sync.RWMutex
-	reservedNode map[peer.ID]struct{}
-	// TODO: this will be useful for reserved only mode
-	// this is for future purpose if reserved-only flag is enabled (#1888).
-	isReservedOnly bool
+	// reservedNodes holds the reserved nodes of each set.
+	reservedNodes []map[peer.ID]struct{}
+	// reservedOnly is true for the sets only accepting and connecting to their reserved nodes.
+	reservedOnly []bool
 
 	// resultMsgCh is read by network.Service.
 	resultMsgCh chan Message
@@ -199,20 +205,19 @@ type config struct {
 	// maximum number of slot occupying nodes for outgoing connections.
 	maxOutPeers uint32
 
-	// TODO Use in future for reserved only peers
-	// if true, we only accept reservedNodes (#1888).
+	// if true, we only accept and connect to the reserved nodes of the set.
 	reservedOnly bool
 
 	// time duration for a peerSet to periodically call allocSlots.
 	periodicAllocTime time.Duration
 }
 
-// ConfigSet set of peerSet config.
+// ConfigSet set of peerSet config, the index of a config being the id of its set.
 type ConfigSet struct {
 	Set []*config
 }
 
-// NewConfigSet creates a new config set for the peerSet
+// NewConfigSet creates a new config set for the peerSet with a single set
 func NewConfigSet(maxInPeers, maxOutPeers uint32, reservedOnly bool, allocTime time.Duration) *ConfigSet {
 	set := &config{
 		maxInPeers:        maxInPeers,
@@ -222,12 +227,27 @@ func NewConfigSet(maxInPeers, maxOutPeers uint32, reservedOnly bool, allocTime t
 	}
 
 	return &ConfigSet{
-		// Why are we using an array of config in the set, when we are
-		// using just one config
 		Set: []*config{set},
 	}
 }
 
+// AddSet adds a set with its own slots to the config set and returns the id of the set.
+// Slots of all the sets are allocated with the period of the first set.
+func (c *ConfigSet) AddSet(maxInPeers, maxOutPeers uint32, reservedOnly bool) (setID int) {
+	var allocTime time.Duration
+	if len(c.Set) > 0 {
+		allocTime = c.Set[0].periodicAllocTime
+	}
+
+	c.Set = append(c.Set, &config{
+		maxInPeers:        maxInPeers,
+		maxOutPeers:       maxOutPeers,
+		reservedOnly:      reservedOnly,
+		periodicAllocTime: allocTime,
+	})
+	return len(c.Set) - 1
+}
+
 func newPeerSet(cfg *ConfigSet) (*PeerSet, error) {
 	if len(cfg.Set) == 0 {
 		return nil, ErrConfigSetIsEmpty
@@ -238,17 +258,21 @@ func newPeerSet(cfg *ConfigSet) (*PeerSet, error) {
 		return nil, err
 	}
 
-	// TODO: currently we only have one set, change this once we have more (#1886).
-	cfgSet := cfg.Set[0]
-	now := time.Now()
+	reservedNodes := make([]map[peer.ID]struct{}, len(cfg.Set))
+	reservedOnly := make([]bool, len(cfg.Set))
+	for setID, setCfg := range cfg.Set {
+		reservedNodes[setID] = make(map[peer.ID]struct{})
+		reservedOnly[setID] = setCfg.reservedOnly
+	}
 
+	now := time.Now()
 	ps := &PeerSet{
 		peerState:              peerState,
-		reservedNode:           make(map[peer.ID]struct{}),
-		isReservedOnly:         cfgSet.reservedOnly,
+		reservedNodes:          reservedNodes,
+		reservedOnly:           reservedOnly,
 		created:                now,
 		latestTimeUpdate:       now,
-		nextPeriodicAllocSlots: cfgSet.periodicAllocTime,
+		nextPeriodicAllocSlots: cfg.Set[0].periodicAllocTime,
 	}
 
 	return ps, nil
@@ -380,7 +404,7 @@ func (ps *PeerSet) allocSlots(setIdx int) error {
 	}
 
 	peerState := ps.peerState
-	for reservePeer := range ps.reservedNode {
+	for reservePeer := range ps.reservedNodes[setIdx] {
 		status := peerState.peerStatus(setIdx, reservePeer)
 		switch status {
 		case connectedPeer:
@@ -412,7 +436,7 @@ func (ps *PeerSet) allocSlots(setIdx int) error {
 	}
 
 	// nothing more to do if we're in reserved mode.
-	if ps.isReservedOnly {
+	if ps.reservedOnly[setIdx] {
 		return nil
 	}
 
@@ -458,14 +482,14 @@ func (ps *PeerSet) addReservedPeers(setID int, peers ...peer.ID) error {
 	defer ps.reservedLock.Unlock()
 
 	for _, peerID := range peers {
-		if _, ok := ps.reservedNode[peerID]; ok {
+		if _, ok := ps.reservedNodes[setID][peerID]; ok {
 			logger.Debugf("peer %s already exists in peerSet", peerID)
-			return nil
+			continue
 		}
 
 		ps.peerState.insertPeer(setID, peerID)
 
-		ps.reservedNode[peerID] = struct{}{}
+		ps.reservedNodes[setID][peerID] = struct{}{}
 		if err := ps.peerState.addNoSlotNode(setID, peerID); err != nil {
 			return fmt.Errorf("could not add to list of no-slot nodes: %w", err)
 		}
@@ -481,22 +505,21 @@ func (ps *PeerSet) removeReservedPeers(setID int, peers ...peer.ID) error {
 	defer ps.reservedLock.Unlock()
 
 	for _, peerID := range peers {
-		if _, ok := ps.reservedNode[peerID]; !ok {
+		if _, ok := ps.reservedNodes[setID][peerID]; !ok {
 			logger.Debugf("peer %s doesn't exist in the peerSet", peerID)
-			return nil
+			continue
 		}
 
-		delete(ps.reservedNode, peerID)
+		delete(ps.reservedNodes[setID], peerID)
 		if err := ps.peerState.removeNoSlotNode(setID, peerID); err != nil {
 			return fmt.Errorf("could not remove from the list of no-slot nodes: %w", err)
 		}
 
 		// nothing more to do if not in reservedOnly mode.
-		if !ps.isReservedOnly {
-			return nil
+		if !ps.reservedOnly[setID] {
+			continue
 		}
 
-		// reservedOnly mode is not yet implemented for future this code will help.
 		// If however the peerSet is in reserved-only mode, then non-reserved node peers needs to be
 		// disconnected.
 		if ps.peerState.peerStatus(setID, peerID) == connectedPeer {
@@ -524,13 +547,13 @@ func (ps *PeerSet) setReservedPeer(setID int, peers ...peer.ID) error {
 
 	for _, pid := range peers {
 		peerIDMap[pid] = struct{}{}
-		if _, ok := ps.reservedNode[pid]; ok {
+		if _, ok := ps.reservedNodes[setID][pid]; ok {
 			continue
 		}
 		toInsert = append(toInsert, pid)
 	}
 
-	for pid := range ps.reservedNode {
+	for pid := range ps.reservedNodes[setID] {
 		if _, ok := peerIDMap[pid]; ok {
 			continue
 		}
@@ -550,6 +573,36 @@ func (ps *PeerSet) setReservedPeer(setID int, peers ...peer.ID) error {
 	return nil
 }
 
+// setReservedOnly sets whether the given set only accepts and connects to its reserved nodes.
+// When enabled, the connected peers of the set which are not reserved are dropped.
+func (ps *PeerSet) setReservedOnly(setID int, reservedOnly bool) error {
+	ps.reservedLock.Lock()
+	defer ps.reservedLock.Unlock()
+
+	ps.reservedOnly[setID] = reservedOnly
+	if !reservedOnly {
+		return ps.allocSlots(setID)
+	}
+
+	for _, pid := range ps.peerState.sortedPeers(setID) {
+		if _, ok := ps.reservedNodes[setID][pid]; ok {
+			continue
+		}
+
+		err := ps.peerState.disconnect(setID, pid)
+		if err != nil {
+			return fmt.Errorf("cannot disconnect: %w", err)
+		}
+
+		ps.resultMsgCh <- Message{
+			Status: Drop,
+			setID:  uint64(setID),
+			PeerID: pid,
+		}
+	}
+	return nil
+}
+
 // addPeer checks peer existence in peerSet and if it does not insert the peer in to peerstate with
 // default reputation and notConnected status. Afterwards runs allocSlots that checks availability of outgoing slots
 // and put notConnected peers in to them
@@ -569,9 +622,9 @@ func (ps *PeerSet) addPeer(setID int, peers peer.IDSlice) error {
 
 func (ps *PeerSet) removePeer(setID int, peers ...peer.ID) error {
 	for _, pid := range peers {
-		if _, ok := ps.reservedNode[pid]; ok {
+		if _, ok := ps.reservedNodes[setID][pid]; ok {
 			logger.Debugf("peer %s is reserved and cannot be removed", pid)
-			return nil
+			continue
 		}
 
 		if status := ps.peerState.peerStatus(setID, pid); status == connectedPeer {
@@ -609,8 +662,8 @@ func (ps *PeerSet) incoming(setID int, peers ...peer.ID) error {
 	}
 
 	for _, pid := range peers {
-		if ps.isReservedOnly {
-			_, has := ps.reservedNode[pid]
+		if ps.reservedOnly[setID] {
+			_, has := ps.reservedNodes[setID][pid]
 			if !has {
 				ps.resultMsgCh <- Message{
 					Status: Reject,
@@ -754,6 +807,14 @@ func (ps *PeerSet) listenActionAllocSlots(ctx context.Context) {
 				return
 			}
 
+			if act.actionCall != reportPeer && (act.setID < 0 || act.setID >= ps.peerState.getSetLength()) {
+				logger.Errorf("failed to do action %s on peerSet: %s", act, ErrUnknownSet)
+				if act.actionCall == sortedPeers {
+					close(act.resultPeersCh)
+				}
+				continue
+			}
+
 			var err error
 			switch act.actionCall {
 			case addReservedPeer:
@@ -764,8 +825,7 @@ func (ps *PeerSet) listenActionAllocSlots(ctx context.Context) {
 				// TODO: this is not used yet, might required to implement RPC Call for this.
 				err = ps.setReservedPeer(act.setID, act.peers...)
 			case setReservedOnly:
-				// TODO: not yet implemented (#1888)
-				err = fmt.Errorf("not implemented yet")
+				err = ps.setReservedOnly(act.setID, act.reservedOnly)
 			case reportPeer:
 				err = ps.reportPeer(act.reputation, act.peers...)
 			case addToPeerSet:
@@ -4,6 +4,7 @@
 package peerset
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -301,7 +302,7 @@ func TestSetReservePeer(t *testing.T) {
 		checkMessageStatus(t, <-ps.resultMsgCh, Connect)
 	}
 
-	require.Len(t, ps.reservedNode, 2)
+	require.Len(t, ps.reservedNodes[testSetID], 2)
 
 	newRsrPeerSet := peer.IDSlice{reservedPeer, peer.ID("newRsrPeer")}
 	// add newRsrPeer but remove reservedPeer2
@@ -355,7 +356,8 @@ func checkReservedNodePeerExists(t *testing.T, ps *PeerSet, pid peer.ID) {
 	ps.Lock()
 	defer ps.Unlock()
 
-	_, exists := ps.reservedNode[pid]
+	const setID = 0
+	_, exists := ps.reservedNodes[setID][pid]
 	require.True(t, exists)
 }
 
@@ -404,5 +406,74 @@ func checkPeerSetReservedNodeCount(t *testing.T, ps *PeerSet, expectedCount int)
 	ps.reservedLock.RLock()
 	defer ps.reservedLock.RUnlock()
 
-	require.Equal(t, expectedCount, len(ps.reservedNode))
+	const setID = 0
+	require.Equal(t, expectedCount, len(ps.reservedNodes[setID]))
+}
+
+func TestMultipleSets(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewConfigSet(1, 0, false, allocTimeDuration)
+	reservedOnlySetID := cfg.AddSet(1, 0, true)
+	require.Equal(t, 1, reservedOnlySetID)
+
+	handler, err := NewPeerSetHandler(cfg)
+	require.NoError(t, err)
+	handler.Start(context.Background())
+	t.Cleanup(handler.Stop)
+	ps := handler.peerSet
+
+	const defaultSetID = 0
+	handler.AddReservedPeer(reservedOnlySetID, reservedPeer)
+	checkMessageStatus(t, <-ps.resultMsgCh, Connect)
+
+	// each set has its own slots and reserved peers
+	handler.Incoming(defaultSetID, incomingPeer, incoming2)
+	handler.Incoming(reservedOnlySetID, incomingPeer)
+
+	expectedMessages := []Message{
+		{Status: Accept, setID: defaultSetID, PeerID: incomingPeer},
+		{Status: Reject, setID: defaultSetID, PeerID: incoming2},
+		{Status: Reject, setID: uint64(reservedOnlySetID), PeerID: incomingPeer},
+	}
+	for _, expected := range expectedMessages {
+		require.Equal(t, expected, <-ps.resultMsgCh)
+	}
+
+	checkPeerStateSetNumIn(t, ps.peerState, defaultSetID, 1)
+	checkPeerStateSetNumIn(t, ps.peerState, reservedOnlySetID, 0)
+	require.Equal(t, connectedPeer, ps.peerState.peerStatus(reservedOnlySetID, reservedPeer))
+	require.Equal(t, unknownPeer, ps.peerState.peerStatus(defaultSetID, reservedPeer))
+
+	// actions on unknown sets are ignored
+	_, ok := <-handler.SortedPeers(2)
+	require.False(t, ok)
+}
+
+func TestSetReservedOnly(t *testing.T) {
+	const testSetID = 0
+
+	t.Parallel()
+	handler := newTestPeerSet(t, 2, 0, nil, []peer.ID{reservedPeer}, false)
+	ps := handler.peerSet
+	t.Cleanup(handler.Stop)
+
+	checkMessageStatus(t, <-ps.resultMsgCh, Connect)
+	handler.Incoming(testSetID, incomingPeer)
+	checkMessageStatus(t, <-ps.resultMsgCh, Accept)
+
+	// the non reserved peers are dropped when switching to reserved only
+	handler.SetReservedOnly(testSetID, true)
+	require.Equal(t, Message{Status: Drop, setID: testSetID, PeerID: incomingPeer}, <-ps.resultMsgCh)
+
+	handler.Incoming(testSetID, incomingPeer)
+	require.Equal(t, Message{Status: Reject, setID: testSetID, PeerID: incomingPeer}, <-ps.resultMsgCh)
+
+	// removing a reserved peer drops it in reserved only mode
+	handler.RemoveReservedPeer(testSetID, reservedPeer)
+	require.Equal(t, Message{Status: Drop, setID: testSetID, PeerID: reservedPeer}, <-ps.resultMsgCh)
+
+	handler.SetReservedOnly(testSetID, false)
+	handler.Incoming(testSetID, incomingPeer)
+	require.Equal(t, Message{Status: Accept, setID: testSetID, PeerID: incomingPeer}, <-ps.resultMsgCh)
 }
@@ -69,6 +69,14 @@ func (c *DotUpCodecRequest) Method() (string, error) {
 			return "", fmt.Errorf("rpc error method %s not found", m)
 		}
 		service, method := parts[0], parts[1]
+		// versioned methods such as chainHead_v1_follow map to chainHead.V1Follow
+		if len(parts) > 2 {
+			var upMethod string
+			for _, part := range parts[1:] {
+				upMethod += upperFirst(part)
+			}
+			return service + "." + upMethod, err
+		}
 		r, n := utf8.DecodeRuneInString(method) // get the first rune, and it's length
 		if unicode.IsLower(r) {
 			upMethod := service + "." + string(unicode.ToUpper(r)) + method[n:]
@@ -77,3 +85,11 @@ func (c *DotUpCodecRequest) Method() (string, error) {
 	}
 	return m, err
 }
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
@@ -37,6 +37,13 @@
 		),
 		expected: "chain.GetBlockHash",
 	},
+	{
+		rpcDataBody: fmt.Sprintf(
+			`{"jsonrpc":"2.0","method":"%s","params":[],"id":1}`,
+			"chainSpec_v1_genesisHash",
+		),
+		expected: "chainSpec.V1GenesisHash",
+	},
 }
 
 func TestAliasesMethodReplace(t *testing.T) {
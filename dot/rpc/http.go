@@ -9,6 +9,7 @@
 	"net/http"
 	"time"
 
+	"github.com/ChainSafe/gossamer/dot/rpc/json2"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/dot/rpc/subscription"
 	"github.com/ChainSafe/gossamer/internal/log"
@@ -36,6 +37,7 @@ type HTTPServerConfig struct {
 	NetworkAPI          NetworkAPI
 	CoreAPI             CoreAPI
 	BlockProducerAPI    BlockProducerAPI
+	SealingAPI          SealingAPI
 	BlockFinalityAPI    BlockFinalityAPI
 	TransactionQueueAPI TransactionStateAPI
 	RPCAPI              API
@@ -51,7 +53,17 @@ type HTTPServerConfig struct {
 	WSExternal          bool
 	WSUnsafeExternal    bool
 	WSPort              uint32
+	SinglePort          bool
 	Modules             []string
+
+	// MaxRequestSize is the maximum size of a request in bytes, 0 for no limit
+	MaxRequestSize int64
+	// MaxSubscriptionsPerConnection is the maximum number of subscriptions of a
+	// websocket connection, 0 for no limit
+	MaxSubscriptionsPerConnection uint32
+	// MaxConcurrentRequestsPerConnection is the maximum number of requests of a
+	// connection, or of a batch, handled at the same time, 0 for no limit
+	MaxConcurrentRequestsPerConnection uint32
 }
 
 func (h *HTTPServerConfig) rpcUnsafeEnabled() bool {
@@ -117,7 +129,21 @@ func (h *HTTPServer) RegisterModules(mods []string) {
 		case "syncstate":
 			srvc = modules.NewSyncStateModule(h.serverConfig.SyncStateAPI)
 		case "payment":
-			srvc = modules.NewPaymentModule(h.serverConfig.BlockAPI)
+			srvc = modules.NewPaymentModule(h.serverConfig.BlockAPI, h.serverConfig.StorageAPI)
+		case "chainHead":
+			srvc = modules.NewChainHeadModule()
+		case "chainSpec":
+			srvc = modules.NewChainSpecModule(h.serverConfig.SystemAPI, h.serverConfig.BlockAPI)
+		case "transaction":
+			srvc = modules.NewTransactionModule(h.logger, h.serverConfig.CoreAPI)
+		case "transactionWatch":
+			srvc = modules.NewTransactionWatchModule()
+		case "babe":
+			srvc = modules.NewBabeModule(h.serverConfig.BlockProducerAPI)
+		case "engine":
+			srvc = modules.NewEngineModule(h.serverConfig.SealingAPI, h.serverConfig.BlockAPI)
+		case "archive":
+			srvc = modules.NewArchiveModule(h.serverConfig.BlockAPI, h.serverConfig.StorageAPI)
 		default:
 			h.logger.Warn("Unrecognised module: " + mod)
 			continue
@@ -140,16 +166,25 @@ func (h *HTTPServer) Start() error {
 	h.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json")
 	h.rpcServer.RegisterCodec(NewDotUpCodec(), "application/json;charset=UTF-8")
 
-	h.logger.Infof("Starting HTTP Server on host %s and port %d...", h.serverConfig.Host, h.serverConfig.RPCPort)
-	r := mux.NewRouter()
-	r.Handle("/", h.rpcServer)
-
 	validate := validator.New()
 	// Add custom validator for `common.Hash`
 	validate.RegisterCustomTypeFunc(common.HashValidator, common.Hash{})
 
 	h.rpcServer.RegisterValidateRequestFunc(rpcValidator(h.serverConfig, validate))
 
+	rpcHandler := json2.NewBatchHandler(h.rpcServer,
+		h.serverConfig.MaxRequestSize, h.serverConfig.MaxConcurrentRequestsPerConnection)
+
+	r := mux.NewRouter()
+	if h.serverConfig.SinglePort {
+		h.logger.Infof("Starting HTTP and WebSocket Server on host %s and port %d...",
+			h.serverConfig.Host, h.serverConfig.RPCPort)
+		r.Handle("/", h.upgradeHandler(rpcHandler))
+	} else {
+		h.logger.Infof("Starting HTTP Server on host %s and port %d...", h.serverConfig.Host, h.serverConfig.RPCPort)
+		r.Handle("/", rpcHandler)
+	}
+
 	go func() {
 		server := &http.Server{
 			Addr:              fmt.Sprintf(":%d", h.serverConfig.RPCPort),
@@ -163,7 +198,7 @@ func (h *HTTPServer) Start() error {
 		}
 	}()
 
-	if !h.serverConfig.exposeWS() {
+	if h.serverConfig.SinglePort || !h.serverConfig.exposeWS() {
 		return nil
 	}
 
@@ -187,9 +222,21 @@ func (h *HTTPServer) Start() error {
 	return nil
 }
 
+// upgradeHandler serves websocket upgrade requests as websocket connections and passes
+// any other request to the given handler
+func (h *HTTPServer) upgradeHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Stop stops the server
 func (h *HTTPServer) Stop() error {
-	if h.serverConfig.exposeWS() {
+	if h.serverConfig.exposeWS() || h.serverConfig.SinglePort {
 		// close all channels and websocket connections
 		for _, conn := range h.wsConns {
 			for _, sub := range conn.Subscriptions {
@@ -260,6 +307,12 @@ func NewWSConn(conn *websocket.Conn, cfg *HTTPServerConfig) *subscription.WSConn
 		HTTP: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		MaxSubscriptions:      cfg.MaxSubscriptionsPerConnection,
+		MaxConcurrentRequests: cfg.MaxConcurrentRequestsPerConnection,
+	}
+
+	if cfg.MaxRequestSize > 0 {
+		conn.SetReadLimit(cfg.MaxRequestSize)
 	}
 	return c
 }
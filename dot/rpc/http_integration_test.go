@@ -22,7 +22,7 @@ func TestNewHTTPServer(t *testing.T) {
 	si := &types.SystemInfo{
 		SystemName: "gossamer",
 	}
-	sysAPI := system.NewService(si, nil)
+	sysAPI := system.NewService(si, nil, nil)
 	cfg := &HTTPServerConfig{
 		Modules:   []string{"system"},
 		RPCPort:   8545,
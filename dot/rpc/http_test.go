@@ -5,6 +5,7 @@
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -30,6 +31,7 @@
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/btcsuite/btcutil/base58"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -58,7 +60,9 @@ func TestRegisterModules(t *testing.T) {
 
 func TestUnsafeRPCProtection(t *testing.T) {
 	cfg := &HTTPServerConfig{
-		Modules:           []string{"system", "author", "chain", "state", "rpc", "grandpa", "dev", "syncstate"},
+		Modules: []string{
+			"system", "author", "chain", "state", "rpc", "grandpa", "dev", "syncstate", "engine", "babe",
+		},
 		RPCPort:           7878,
 		RPCAPI:            NewService(),
 		RPCUnsafeExternal: false,
@@ -238,6 +242,97 @@ func TestRPCExternalEnable_UnsafeExternalNotEnabled(t *testing.T) {
 	require.Equal(t, expected, string(resBody))
 }
 
+func TestHTTPServer_BatchRequest(t *testing.T) {
+	cfg := &HTTPServerConfig{
+		Modules: []string{"rpc"},
+		RPCPort: 7881,
+		RPCAPI:  NewService(),
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.NoError(t, err)
+
+	time.Sleep(time.Second)
+	defer s.Stop()
+
+	data := []byte(`[` +
+		`{"jsonrpc":"2.0","method":"rpc_methods","params":[],"id":1},` +
+		`{"jsonrpc":"2.0","method":"rpc_methods","params":[]},` +
+		`{"jsonrpc":"2.0","method":"unknown_method","params":[],"id":2}` +
+		`]`)
+
+	status, resBody := PostRequest(t, fmt.Sprintf("http://localhost:%v/", cfg.RPCPort), bytes.NewReader(data))
+	require.Equal(t, http.StatusOK, status)
+
+	var responses []struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code int `json:"code"`
+		} `json:"error"`
+	}
+	err = json.Unmarshal(resBody, &responses)
+	require.NoError(t, err)
+
+	// the notification is not answered
+	require.Len(t, responses, 2)
+	require.Equal(t, 1, responses[0].ID)
+	require.Nil(t, responses[0].Error)
+	require.NotEmpty(t, responses[0].Result)
+	require.Equal(t, 2, responses[1].ID)
+	require.NotNil(t, responses[1].Error)
+}
+
+func TestHTTPServer_SinglePort(t *testing.T) {
+	cfg := &HTTPServerConfig{
+		Modules:        []string{"rpc"},
+		Host:           "localhost",
+		RPCPort:        7882,
+		SinglePort:     true,
+		RPCAPI:         NewService(),
+		MaxRequestSize: 1024,
+	}
+
+	s := NewHTTPServer(cfg)
+	err := s.Start()
+	require.NoError(t, err)
+
+	time.Sleep(time.Second)
+	defer s.Stop()
+
+	url := fmt.Sprintf("http://localhost:%v/", cfg.RPCPort)
+	data := []byte(`{"jsonrpc":"2.0","method":"rpc_methods","params":[],"id":1}`)
+	status, _ := PostRequest(t, url, bytes.NewReader(data))
+	require.Equal(t, http.StatusOK, status)
+
+	tooLarge := bytes.Repeat([]byte(" "), 2048)
+	status, _ = PostRequest(t, url, bytes.NewReader(tooLarge))
+	require.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+	ws, res, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://localhost:%v/", cfg.RPCPort), nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	defer ws.Close()
+
+	batch := []byte(`[` +
+		`{"jsonrpc":"2.0","method":"rpc_methods","params":[],"id":1},` +
+		`{"jsonrpc":"2.0","method":"rpc_methods","params":[],"id":2}` +
+		`]`)
+	err = ws.WriteMessage(websocket.TextMessage, batch)
+	require.NoError(t, err)
+
+	var responses []struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+	}
+	err = ws.ReadJSON(&responses)
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	require.Equal(t, 1, responses[0].ID)
+	require.Equal(t, 2, responses[1].ID)
+}
+
 func PostRequest(t *testing.T, url string, data io.Reader) (int, []byte) {
 	t.Helper()
 
@@ -9,11 +9,13 @@
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 )
@@ -27,6 +29,9 @@ type StorageAPI interface {
 	Entries(root *common.Hash) (map[string][]byte, error)
 	GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error)
 	GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error)
+	GetKeysWithPrefixAfter(root *common.Hash, keyToChild, prefix, after []byte, limit uint) ([][]byte, error)
+	GetClosestDescendantMerkleValue(root *common.Hash, keyToChild, key []byte) ([]byte, error)
+	TrieState(root *common.Hash) (*storage.TrieState, error)
 	RegisterStorageObserver(observer state.Observer)
 	UnregisterStorageObserver(observer state.Observer)
 }
@@ -37,6 +42,7 @@ type BlockAPI interface {
 	BestBlockHash() common.Hash
 	GetBlockByHash(hash common.Hash) (*types.Block, error)
 	GetHashByNumber(blockNumber uint) (common.Hash, error)
+	GetHashesByNumber(blockNumber uint) ([]common.Hash, error)
 	GetFinalisedHash(uint64, uint64) (common.Hash, error)
 	GetHighestFinalisedHash() (common.Hash, error)
 	HasJustification(hash common.Hash) (bool, error)
@@ -49,6 +55,8 @@ type BlockAPI interface {
 	RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error)
 	UnregisterRuntimeUpdatedChannel(id uint32) bool
 	GetRuntime(blockHash common.Hash) (runtime runtime.Instance, err error)
+	PinBlock(hash common.Hash) error
+	UnpinBlock(hash common.Hash) error
 }
 
 // NetworkAPI interface for network state methods
@@ -70,14 +78,22 @@ type BlockProducerAPI interface {
 	Resume() error
 	EpochLength() uint64
 	SlotDuration() uint64
+	EpochAuthorship() (map[string]*babe.EpochAuthorship, error)
+}
+
+// SealingAPI is the interface for the manual and instant seal block production methods
+type SealingAPI interface {
+	CreateBlock(createEmpty, finalise bool, parentHash *common.Hash) (common.Hash, error)
+	FinaliseBlock(hash common.Hash) error
 }
 
 // TransactionStateAPI ...
 type TransactionStateAPI interface {
-	AddToPool(*transaction.ValidTransaction) common.Hash
+	AddToPool(*transaction.ValidTransaction) (common.Hash, error)
 	Pending() []*transaction.ValidTransaction
-	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status
-	FreeStatusNotifierChannel(ch chan transaction.Status)
+	RemoveExtrinsicByHash(hash common.Hash) (removed bool)
+	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.StatusNotification
+	FreeStatusNotifierChannel(ch chan transaction.StatusNotification)
 }
 
 // CoreAPI is the interface for the core methods
@@ -88,6 +104,7 @@ type CoreAPI interface {
 	HandleSubmittedExtrinsic(types.Extrinsic) error
 	GetMetadata(bhash *common.Hash) ([]byte, error)
 	DecodeSessionKeys(enc []byte) ([]byte, error)
+	GenerateSessionKeys() ([]byte, error)
 	GetReadProofAt(block common.Hash, keys [][]byte) (common.Hash, [][]byte, error)
 }
 
@@ -104,6 +121,8 @@ type SystemAPI interface {
 	Properties() map[string]interface{}
 	ChainType() string
 	ChainName() string
+	AddLogFilter(directives string) error
+	ResetLogFilter() error
 }
 
 // BlockFinalityAPI is the interface for handling block finalisation methods
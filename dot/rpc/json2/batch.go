@@ -0,0 +1,193 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package json2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+// BatchHandler serves JSON-RPC 2.0 batch requests by passing each request of the
+// batch array to the wrapped handler and writing the responses back as an array.
+// Requests which are not batches are passed to the wrapped handler untouched.
+type BatchHandler struct {
+	handler        http.Handler
+	maxRequestSize int64
+	maxConcurrency uint32
+}
+
+// NewBatchHandler returns a BatchHandler wrapping the given handler. Request bodies larger
+// than maxRequestSize bytes are refused and at most maxConcurrency requests of a batch are
+// handled at the same time. A zero value disables the corresponding limit.
+func NewBatchHandler(handler http.Handler, maxRequestSize int64, maxConcurrency uint32) *BatchHandler {
+	return &BatchHandler{
+		handler:        handler,
+		maxRequestSize: maxRequestSize,
+		maxConcurrency: maxConcurrency,
+	}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (b *BatchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		b.handler.ServeHTTP(w, r)
+		return
+	}
+
+	body := r.Body
+	if b.maxRequestSize > 0 {
+		body = http.MaxBytesReader(w, r.Body, b.maxRequestSize)
+	}
+	data, err := io.ReadAll(body)
+	_ = r.Body.Close()
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			writeErrorResponse(w, http.StatusRequestEntityTooLarge, json2.E_INVALID_REQ, "request is too large")
+			return
+		}
+		writeErrorResponse(w, http.StatusBadRequest, json2.E_PARSE, err.Error())
+		return
+	}
+
+	if !IsBatch(data) {
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		b.handler.ServeHTTP(w, r)
+		return
+	}
+
+	var requests []json.RawMessage
+	err = json.Unmarshal(data, &requests)
+	if err != nil {
+		writeErrorResponse(w, http.StatusOK, json2.E_PARSE, err.Error())
+		return
+	}
+	if len(requests) == 0 {
+		writeErrorResponse(w, http.StatusOK, json2.E_INVALID_REQ, "empty batch")
+		return
+	}
+
+	responses := b.serveBatch(r, requests)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if len(responses) == 0 {
+		// the batch only holds notifications
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	encoded, err := json.Marshal(responses)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, json2.E_INTERNAL, err.Error())
+		return
+	}
+	_, _ = w.Write(encoded)
+}
+
+// serveBatch handles each request of the batch and returns the non empty responses,
+// in the order of the requests.
+func (b *BatchHandler) serveBatch(r *http.Request, requests []json.RawMessage) (responses []json.RawMessage) {
+	results := make([]json.RawMessage, len(requests))
+
+	concurrency := len(requests)
+	if b.maxConcurrency > 0 && int(b.maxConcurrency) < concurrency {
+		concurrency = int(b.maxConcurrency)
+	}
+	semaphore := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, request json.RawMessage) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			results[i] = b.serveRequest(r, request)
+		}(i, request)
+	}
+	wg.Wait()
+
+	responses = make([]json.RawMessage, 0, len(results))
+	for _, result := range results {
+		if len(result) > 0 {
+			responses = append(responses, result)
+		}
+	}
+	return responses
+}
+
+// serveRequest handles a single request of a batch and returns its response, which is
+// empty for notifications.
+func (b *BatchHandler) serveRequest(r *http.Request, request json.RawMessage) json.RawMessage {
+	subRequest := r.Clone(r.Context())
+	subRequest.Body = io.NopCloser(bytes.NewReader(request))
+	subRequest.ContentLength = int64(len(request))
+
+	recorder := newResponseRecorder()
+	b.handler.ServeHTTP(recorder, subRequest)
+
+	response := bytes.TrimSpace(recorder.body.Bytes())
+	if len(response) == 0 {
+		return nil
+	}
+
+	if !json.Valid(response) {
+		// the wrapped handler refused the request before reaching the codec
+		encoded, err := json.Marshal(newErrorResponse(json2.E_INVALID_REQ, string(response)))
+		if err != nil {
+			return nil
+		}
+		return encoded
+	}
+	return response
+}
+
+// IsBatch returns true if the given JSON data is a batch array of requests.
+func IsBatch(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+func newErrorResponse(code json2.ErrorCode, message string) *serverResponse {
+	return &serverResponse{
+		Version: version,
+		Error: &json2.Error{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
+
+func writeErrorResponse(w http.ResponseWriter, status int, code json2.ErrorCode, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(newErrorResponse(code, message))
+}
+
+// responseRecorder is an http.ResponseWriter buffering the response of a single request
+// of a batch.
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{
+		header: make(http.Header),
+	}
+}
+
+func (r *responseRecorder) Header() http.Header { return r.header }
+
+func (r *responseRecorder) Write(data []byte) (int, error) { return r.body.Write(data) }
+
+func (*responseRecorder) WriteHeader(int) {}
@@ -0,0 +1,116 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package json2
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoHandler answers requests having an id with their id as result
+func echoHandler(t *testing.T) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request serverRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		require.NoError(t, err)
+
+		if request.Method == "refused" {
+			http.Error(w, "refused", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		if request.ID == nil {
+			return
+		}
+		err = json.NewEncoder(w).Encode(&serverResponse{
+			Version: version,
+			Result:  request.ID,
+			ID:      request.ID,
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestBatchHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body           string
+		maxRequestSize int64
+		maxConcurrency uint32
+		expectedStatus int
+		expectedBody   string
+	}{
+		"single_request": {
+			body:           `{"jsonrpc":"2.0","method":"m","id":1}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"jsonrpc":"2.0","result":1,"id":1}` + "\n",
+		},
+		"batch": {
+			body: `[{"jsonrpc":"2.0","method":"m","id":1},` +
+				`{"jsonrpc":"2.0","method":"m"},` +
+				`{"jsonrpc":"2.0","method":"m","id":"two"}]`,
+			maxConcurrency: 1,
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"jsonrpc":"2.0","result":1,"id":1},` +
+				`{"jsonrpc":"2.0","result":"two","id":"two"}]`,
+		},
+		"batch_of_notifications": {
+			body:           ` [{"jsonrpc":"2.0","method":"m"}]`,
+			expectedStatus: http.StatusNoContent,
+		},
+		"empty_batch": {
+			body:           `[]`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"jsonrpc":"2.0","error":{"code":-32600,"message":"empty batch","data":null},"id":null}` + "\n",
+		},
+		"invalid_batch": {
+			body:           `[{]`,
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"jsonrpc":"2.0","error":{"code":-32700,` +
+				`"message":"invalid character ']' looking for beginning of object key string","data":null},"id":null}` + "\n",
+		},
+		"refused_request_in_batch": {
+			body:           `[{"jsonrpc":"2.0","method":"refused","id":1}]`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"jsonrpc":"2.0","error":{"code":-32600,"message":"refused","data":null},"id":null}]`,
+		},
+		"request_too_large": {
+			body:           `{"jsonrpc":"2.0","method":"m","id":1}`,
+			maxRequestSize: 10,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody: `{"jsonrpc":"2.0","error":{"code":-32600,` +
+				`"message":"request is too large","data":null},"id":null}` + "\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := NewBatchHandler(echoHandler(t), testCase.maxRequestSize, testCase.maxConcurrency)
+
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(testCase.body))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			defer response.Body.Close()
+			body, err := io.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.expectedStatus, response.StatusCode)
+			assert.Equal(t, testCase.expectedBody, string(body))
+		})
+	}
+}
@@ -91,11 +91,12 @@ func (m *MockTransactionStateAPI) EXPECT() *MockTransactionStateAPIMockRecorder
 }
 
 // AddToPool mocks base method.
-func (m *MockTransactionStateAPI) AddToPool(arg0 *transaction.ValidTransaction) common.Hash {
+func (m *MockTransactionStateAPI) AddToPool(arg0 *transaction.ValidTransaction) (common.Hash, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddToPool", arg0)
 	ret0, _ := ret[0].(common.Hash)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddToPool indicates an expected call of AddToPool.
@@ -105,7 +106,7 @@ func (mr *MockTransactionStateAPIMockRecorder) AddToPool(arg0 any) *gomock.Call
 }
 
 // FreeStatusNotifierChannel mocks base method.
-func (m *MockTransactionStateAPI) FreeStatusNotifierChannel(arg0 chan transaction.Status) {
+func (m *MockTransactionStateAPI) FreeStatusNotifierChannel(arg0 chan transaction.StatusNotification) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "FreeStatusNotifierChannel", arg0)
 }
@@ -117,10 +118,10 @@ func (mr *MockTransactionStateAPIMockRecorder) FreeStatusNotifierChannel(arg0 an
 }
 
 // GetStatusNotifierChannel mocks base method.
-func (m *MockTransactionStateAPI) GetStatusNotifierChannel(arg0 types.Extrinsic) chan transaction.Status {
+func (m *MockTransactionStateAPI) GetStatusNotifierChannel(arg0 types.Extrinsic) chan transaction.StatusNotification {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetStatusNotifierChannel", arg0)
-	ret0, _ := ret[0].(chan transaction.Status)
+	ret0, _ := ret[0].(chan transaction.StatusNotification)
 	return ret0
 }
 
@@ -143,3 +144,17 @@ func (mr *MockTransactionStateAPIMockRecorder) Pending() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockTransactionStateAPI)(nil).Pending))
 }
+
+// RemoveExtrinsicByHash mocks base method.
+func (m *MockTransactionStateAPI) RemoveExtrinsicByHash(arg0 common.Hash) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveExtrinsicByHash", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// RemoveExtrinsicByHash indicates an expected call of RemoveExtrinsicByHash.
+func (mr *MockTransactionStateAPIMockRecorder) RemoveExtrinsicByHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExtrinsicByHash", reflect.TypeOf((*MockTransactionStateAPI)(nil).RemoveExtrinsicByHash), arg0)
+}
@@ -7,11 +7,13 @@
 	"github.com/ChainSafe/gossamer/dot/core"
 	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 )
@@ -25,6 +27,9 @@ type StorageAPI interface {
 	Entries(root *common.Hash) (map[string][]byte, error)
 	GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error)
 	GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error)
+	GetKeysWithPrefixAfter(root *common.Hash, keyToChild, prefix, after []byte, limit uint) ([][]byte, error)
+	GetClosestDescendantMerkleValue(root *common.Hash, keyToChild, key []byte) ([]byte, error)
+	TrieState(root *common.Hash) (*storage.TrieState, error)
 	RegisterStorageObserver(observer state.Observer)
 	UnregisterStorageObserver(observer state.Observer)
 }
@@ -35,6 +40,7 @@ type BlockAPI interface {
 	BestBlockHash() common.Hash
 	GetBlockByHash(hash common.Hash) (*types.Block, error)
 	GetHashByNumber(blockNumber uint) (common.Hash, error)
+	GetHashesByNumber(blockNumber uint) ([]common.Hash, error)
 	GetFinalisedHash(uint64, uint64) (common.Hash, error)
 	GetHighestFinalisedHash() (common.Hash, error)
 	HasJustification(hash common.Hash) (bool, error)
@@ -47,6 +53,8 @@ type BlockAPI interface {
 	RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error)
 	UnregisterRuntimeUpdatedChannel(id uint32) bool
 	GetRuntime(blockHash common.Hash) (instance runtime.Instance, err error)
+	PinBlock(hash common.Hash) error
+	UnpinBlock(hash common.Hash) error
 }
 
 // NetworkAPI interface for network state methods
@@ -68,11 +76,19 @@ type BlockProducerAPI interface {
 	Resume() error
 	EpochLength() uint64
 	SlotDuration() uint64
+	EpochAuthorship() (map[string]*babe.EpochAuthorship, error)
+}
+
+// SealingAPI is the interface for the manual and instant seal block production methods
+type SealingAPI interface {
+	CreateBlock(createEmpty, finalise bool, parentHash *common.Hash) (common.Hash, error)
+	FinaliseBlock(hash common.Hash) error
 }
 
 // TransactionStateAPI ...
 type TransactionStateAPI interface {
 	Pending() []*transaction.ValidTransaction
+	RemoveExtrinsicByHash(hash common.Hash) (removed bool)
 }
 
 // CoreAPI is the interface for the core methods
@@ -83,6 +99,7 @@ type CoreAPI interface {
 	HandleSubmittedExtrinsic(types.Extrinsic) error
 	GetMetadata(bhash *common.Hash) ([]byte, error)
 	DecodeSessionKeys(enc []byte) ([]byte, error)
+	GenerateSessionKeys() ([]byte, error)
 	GetReadProofAt(block common.Hash, keys [][]byte) (common.Hash, [][]byte, error)
 }
 
@@ -98,6 +115,8 @@ type SystemAPI interface {
 	Properties() map[string]interface{}
 	ChainType() string
 	ChainName() string
+	AddLogFilter(directives string) error
+	ResetLogFilter() error
 }
 
 // BlockFinalityAPI is the interface for handling block finalisation methods
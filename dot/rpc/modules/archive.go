@@ -0,0 +1,489 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+)
+
+const (
+	// archiveMaxQueriedItems is the number of items an archive_v1_storage call queries,
+	// the remaining items are discarded.
+	archiveMaxQueriedItems = 8
+	// archiveMaxDescendantResponses is the number of descendant items an archive_v1_storage
+	// call returns, the remaining ones are queried again using a pagination start key.
+	archiveMaxDescendantResponses = 5
+)
+
+// storage query types of archive_v1_storage
+const (
+	archiveQueryValue                        = "value"
+	archiveQueryHash                         = "hash"
+	archiveQueryClosestDescendantMerkleValue = "closestDescendantMerkleValue"
+	archiveQueryDescendantsValues            = "descendantsValues"
+	archiveQueryDescendantsHashes            = "descendantsHashes"
+)
+
+// storage diff types of archive_v1_storageDiff
+const (
+	archiveDiffAdded    = "added"
+	archiveDiffModified = "modified"
+	archiveDiffDeleted  = "deleted"
+)
+
+// ArchiveHashRequest holds the block hash parameter of the archive_v1 methods
+type ArchiveHashRequest struct {
+	Hash common.Hash `json:"hash"`
+}
+
+// ArchiveHeightRequest holds the block height parameter of archive_v1_hashByHeight
+type ArchiveHeightRequest struct {
+	Height uint `json:"height"`
+}
+
+// ArchiveCallRequest holds the parameters of archive_v1_call
+type ArchiveCallRequest struct {
+	Hash           common.Hash `json:"hash"`
+	Function       string      `json:"function"`
+	CallParameters string      `json:"callParameters"`
+}
+
+// ArchiveCallResponse is the outcome of a runtime call
+type ArchiveCallResponse struct {
+	Success bool   `json:"success"`
+	Value   string `json:"value,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ArchiveStorageQuery is a storage query of archive_v1_storage
+type ArchiveStorageQuery struct {
+	Key                string  `json:"key"`
+	Type               string  `json:"type"`
+	PaginationStartKey *string `json:"paginationStartKey"`
+}
+
+// ArchiveStorageRequest holds the parameters of archive_v1_storage
+type ArchiveStorageRequest struct {
+	Hash      common.Hash           `json:"hash"`
+	Items     []ArchiveStorageQuery `json:"items"`
+	ChildTrie *string               `json:"childTrie"`
+}
+
+// ArchiveStorageResult is a storage item returned by archive_v1_storage
+type ArchiveStorageResult struct {
+	Key                          string `json:"key"`
+	Value                        string `json:"value,omitempty"`
+	Hash                         string `json:"hash,omitempty"`
+	ClosestDescendantMerkleValue string `json:"closestDescendantMerkleValue,omitempty"`
+	ChildTrieKey                 string `json:"childTrieKey,omitempty"`
+}
+
+// ArchiveStorageResponse is the response of archive_v1_storage
+type ArchiveStorageResponse struct {
+	Result         []ArchiveStorageResult `json:"result"`
+	DiscardedItems uint                   `json:"discardedItems"`
+}
+
+// ArchiveStorageDiffItem is a storage diff query of archive_v1_storageDiff
+type ArchiveStorageDiffItem struct {
+	Key          string  `json:"key"`
+	ReturnType   string  `json:"returnType"`
+	ChildTrieKey *string `json:"childTrieKey"`
+}
+
+// ArchiveStorageDiffRequest holds the parameters of archive_v1_storageDiff
+type ArchiveStorageDiffRequest struct {
+	Hash         common.Hash              `json:"hash"`
+	Items        []ArchiveStorageDiffItem `json:"items"`
+	PreviousHash *common.Hash             `json:"previousHash"`
+}
+
+// ArchiveStorageDiffResult is a storage change returned by archive_v1_storageDiff
+type ArchiveStorageDiffResult struct {
+	Key          string `json:"key"`
+	Value        string `json:"value,omitempty"`
+	Hash         string `json:"hash,omitempty"`
+	Type         string `json:"type"`
+	ChildTrieKey string `json:"childTrieKey,omitempty"`
+}
+
+// ArchiveModule holds the RPC implementation of the archive_v1 methods of the new
+// JSON-RPC interface, which query the blocks and state of an archive node.
+type ArchiveModule struct {
+	blockAPI   BlockAPI
+	storageAPI StorageAPI
+}
+
+// NewArchiveModule returns a pointer to ArchiveModule
+func NewArchiveModule(blockAPI BlockAPI, storageAPI StorageAPI) *ArchiveModule {
+	return &ArchiveModule{
+		blockAPI:   blockAPI,
+		storageAPI: storageAPI,
+	}
+}
+
+// V1Body returns the hex encoded extrinsics of the block, or null if the block is unknown
+func (am *ArchiveModule) V1Body(_ *http.Request, req *ArchiveHashRequest, res *[]string) error {
+	block, err := am.blockAPI.GetBlockByHash(req.Hash)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting block: %w", err)
+	}
+
+	*res = make([]string, len(block.Body))
+	for i, extrinsic := range block.Body {
+		(*res)[i] = common.BytesToHex(extrinsic)
+	}
+	return nil
+}
+
+// V1Header returns the hex encoded SCALE header of the block, or null if the block is unknown
+func (am *ArchiveModule) V1Header(_ *http.Request, req *ArchiveHashRequest, res *interface{}) error {
+	header, err := am.blockAPI.GetHeader(req.Hash)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting header: %w", err)
+	}
+
+	encodedHeader, err := scale.Marshal(*header)
+	if err != nil {
+		return fmt.Errorf("encoding header: %w", err)
+	}
+
+	*res = common.BytesToHex(encodedHeader)
+	return nil
+}
+
+// V1FinalizedHeight returns the number of the highest finalised block
+func (am *ArchiveModule) V1FinalizedHeight(_ *http.Request, _ *EmptyRequest, res *uint) error {
+	finalisedHash, err := am.blockAPI.GetHighestFinalisedHash()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised hash: %w", err)
+	}
+
+	header, err := am.blockAPI.GetHeader(finalisedHash)
+	if err != nil {
+		return fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	*res = header.Number
+	return nil
+}
+
+// V1HashByHeight returns the hashes of the blocks at the given height. Only the finalised
+// block is returned for heights lower than or equal to the finalised height.
+func (am *ArchiveModule) V1HashByHeight(_ *http.Request, req *ArchiveHeightRequest, res *[]string) error {
+	hashes, err := am.blockAPI.GetHashesByNumber(req.Height)
+	if err != nil {
+		return fmt.Errorf("getting block hashes: %w", err)
+	}
+
+	*res = make([]string, len(hashes))
+	for i, hash := range hashes {
+		(*res)[i] = hash.String()
+	}
+	return nil
+}
+
+// V1Call calls the runtime function with the given parameters on the state of the block
+func (am *ArchiveModule) V1Call(r *http.Request, req *ArchiveCallRequest, res *ArchiveCallResponse) error {
+	callParameters, err := common.HexToBytes(req.CallParameters)
+	if err != nil {
+		return fmt.Errorf("decoding call parameters: %w", err)
+	}
+
+	rt, err := runtimeAt(r, am.blockAPI, am.storageAPI, req.Hash)
+	if err != nil {
+		return err
+	}
+
+	output, err := rt.Exec(req.Function, callParameters)
+	if err != nil {
+		*res = ArchiveCallResponse{Error: err.Error()}
+		return nil
+	}
+
+	*res = ArchiveCallResponse{
+		Success: true,
+		Value:   common.BytesToHex(output),
+	}
+	return nil
+}
+
+// V1Storage queries the storage of the block. At most 8 items are queried and at most 5
+// descendant items are returned per call, the remaining items are discarded and can be
+// queried again using the last returned key as pagination start key.
+func (am *ArchiveModule) V1Storage(_ *http.Request, req *ArchiveStorageRequest, res *ArchiveStorageResponse) error {
+	root, err := am.storageAPI.GetStateRootFromBlock(&req.Hash)
+	if err != nil {
+		return fmt.Errorf("getting state root: %w", err)
+	}
+
+	var childKey []byte
+	var childTrieKey string
+	if req.ChildTrie != nil {
+		childTrieKey = *req.ChildTrie
+		childKey, err = common.HexToBytes(childTrieKey)
+		if err != nil {
+			return fmt.Errorf("decoding child trie key: %w", err)
+		}
+	}
+
+	items := req.Items
+	if len(items) > archiveMaxQueriedItems {
+		res.DiscardedItems = uint(len(items) - archiveMaxQueriedItems)
+		items = items[:archiveMaxQueriedItems]
+	}
+
+	res.Result = []ArchiveStorageResult{}
+	descendantResponses := 0
+	for i, item := range items {
+		key, err := common.HexToBytes(item.Key)
+		if err != nil {
+			return fmt.Errorf("decoding storage key: %w", err)
+		}
+
+		switch item.Type {
+		case archiveQueryValue, archiveQueryHash:
+			value, err := am.getStorage(root, childKey, key)
+			if err != nil {
+				return err
+			}
+
+			if value != nil {
+				result, err := newArchiveStorageResult(key, value, item.Type == archiveQueryHash, childTrieKey)
+				if err != nil {
+					return err
+				}
+				res.Result = append(res.Result, result)
+			}
+		case archiveQueryClosestDescendantMerkleValue:
+			merkleValue, err := am.storageAPI.GetClosestDescendantMerkleValue(root, childKey, key)
+			if err != nil && !errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+				return fmt.Errorf("getting closest descendant Merkle value: %w", err)
+			}
+
+			if merkleValue != nil {
+				res.Result = append(res.Result, ArchiveStorageResult{
+					Key:                          item.Key,
+					ClosestDescendantMerkleValue: common.BytesToHex(merkleValue),
+					ChildTrieKey:                 childTrieKey,
+				})
+			}
+		case archiveQueryDescendantsValues, archiveQueryDescendantsHashes:
+			var startKey []byte
+			if item.PaginationStartKey != nil {
+				startKey, err = common.HexToBytes(*item.PaginationStartKey)
+				if err != nil {
+					return fmt.Errorf("decoding pagination start key: %w", err)
+				}
+			}
+
+			limit := uint(archiveMaxDescendantResponses - descendantResponses)
+			keys, err := am.storageAPI.GetKeysWithPrefixAfter(root, childKey, key, startKey, limit)
+			if err != nil && !errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+				return fmt.Errorf("getting descendant keys: %w", err)
+			}
+
+			for _, descendantKey := range keys {
+				value, err := am.getStorage(root, childKey, descendantKey)
+				if err != nil {
+					return err
+				}
+
+				result, err := newArchiveStorageResult(descendantKey, value,
+					item.Type == archiveQueryDescendantsHashes, childTrieKey)
+				if err != nil {
+					return err
+				}
+				res.Result = append(res.Result, result)
+			}
+
+			descendantResponses += len(keys)
+			if descendantResponses == archiveMaxDescendantResponses {
+				res.DiscardedItems += uint(len(items) - i - 1)
+				return nil
+			}
+		default:
+			return fmt.Errorf("%w: %s", ErrInvalidStorageQueryType, item.Type)
+		}
+	}
+
+	return nil
+}
+
+// V1StorageDiff returns the storage changes between the block and the previous block, which
+// defaults to the parent block. The changes of all the keys are returned if no item is given.
+func (am *ArchiveModule) V1StorageDiff(_ *http.Request, req *ArchiveStorageDiffRequest,
+	res *[]ArchiveStorageDiffResult) error {
+	previousHash := req.PreviousHash
+	if previousHash == nil {
+		header, err := am.blockAPI.GetHeader(req.Hash)
+		if err != nil {
+			return fmt.Errorf("getting header: %w", err)
+		}
+		previousHash = &header.ParentHash
+	}
+
+	root, err := am.storageAPI.GetStateRootFromBlock(&req.Hash)
+	if err != nil {
+		return fmt.Errorf("getting state root: %w", err)
+	}
+
+	previousRoot, err := am.storageAPI.GetStateRootFromBlock(previousHash)
+	if err != nil {
+		return fmt.Errorf("getting previous state root: %w", err)
+	}
+
+	items := req.Items
+	if len(items) == 0 {
+		items = []ArchiveStorageDiffItem{{Key: "0x", ReturnType: archiveQueryValue}}
+	}
+
+	*res = []ArchiveStorageDiffResult{}
+	for _, item := range items {
+		diff, err := am.storageDiff(root, previousRoot, item)
+		if err != nil {
+			return err
+		}
+		*res = append(*res, diff...)
+	}
+
+	return nil
+}
+
+func (am *ArchiveModule) storageDiff(root, previousRoot *common.Hash,
+	item ArchiveStorageDiffItem) ([]ArchiveStorageDiffResult, error) {
+	if item.ReturnType != archiveQueryValue && item.ReturnType != archiveQueryHash {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidStorageQueryType, item.ReturnType)
+	}
+
+	prefix, err := common.HexToBytes(item.Key)
+	if err != nil {
+		return nil, fmt.Errorf("decoding storage key: %w", err)
+	}
+
+	var childKey []byte
+	var childTrieKey string
+	if item.ChildTrieKey != nil {
+		childTrieKey = *item.ChildTrieKey
+		childKey, err = common.HexToBytes(childTrieKey)
+		if err != nil {
+			return nil, fmt.Errorf("decoding child trie key: %w", err)
+		}
+	}
+
+	keys, err := am.storageAPI.GetKeysWithPrefixAfter(root, childKey, prefix, nil, math.MaxUint)
+	if err != nil && !errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+		return nil, fmt.Errorf("getting keys: %w", err)
+	}
+
+	previousKeys, err := am.storageAPI.GetKeysWithPrefixAfter(previousRoot, childKey, prefix, nil, math.MaxUint)
+	if err != nil && !errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+		return nil, fmt.Errorf("getting previous keys: %w", err)
+	}
+
+	previousKeysSet := make(map[string]struct{}, len(previousKeys))
+	for _, key := range previousKeys {
+		previousKeysSet[string(key)] = struct{}{}
+	}
+
+	var diff []ArchiveStorageDiffResult
+	for _, key := range keys {
+		value, err := am.getStorage(root, childKey, key)
+		if err != nil {
+			return nil, err
+		}
+
+		diffType := archiveDiffAdded
+		if _, ok := previousKeysSet[string(key)]; ok {
+			delete(previousKeysSet, string(key))
+
+			previousValue, err := am.getStorage(previousRoot, childKey, key)
+			if err != nil {
+				return nil, err
+			}
+
+			if bytes.Equal(value, previousValue) {
+				continue
+			}
+			diffType = archiveDiffModified
+		}
+
+		result, err := newArchiveStorageResult(key, value, item.ReturnType == archiveQueryHash, childTrieKey)
+		if err != nil {
+			return nil, err
+		}
+
+		diff = append(diff, ArchiveStorageDiffResult{
+			Key:          result.Key,
+			Value:        result.Value,
+			Hash:         result.Hash,
+			Type:         diffType,
+			ChildTrieKey: childTrieKey,
+		})
+	}
+
+	// the remaining previous keys were deleted
+	for _, key := range previousKeys {
+		if _, ok := previousKeysSet[string(key)]; !ok {
+			continue
+		}
+
+		diff = append(diff, ArchiveStorageDiffResult{
+			Key:          common.BytesToHex(key),
+			Type:         archiveDiffDeleted,
+			ChildTrieKey: childTrieKey,
+		})
+	}
+
+	return diff, nil
+}
+
+// getStorage returns the value at the given key of the state trie, or of its child trie
+// at childKey if childKey is not nil
+func (am *ArchiveModule) getStorage(root *common.Hash, childKey, key []byte) ([]byte, error) {
+	var value []byte
+	var err error
+	if childKey != nil {
+		value, err = am.storageAPI.GetStorageFromChild(root, childKey, key)
+	} else {
+		value, err = am.storageAPI.GetStorage(root, key)
+	}
+
+	if err != nil && !errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+		return nil, fmt.Errorf("getting storage: %w", err)
+	}
+	return value, nil
+}
+
+func newArchiveStorageResult(key, value []byte, hashed bool, childTrieKey string) (ArchiveStorageResult, error) {
+	result := ArchiveStorageResult{
+		Key:          common.BytesToHex(key),
+		ChildTrieKey: childTrieKey,
+	}
+
+	if !hashed {
+		result.Value = common.BytesToHex(value)
+		return result, nil
+	}
+
+	valueHash, err := common.Blake2bHash(value)
+	if err != nil {
+		return ArchiveStorageResult{}, fmt.Errorf("hashing storage value: %w", err)
+	}
+	result.Hash = valueHash.String()
+	return result, nil
+}
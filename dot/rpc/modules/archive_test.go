@@ -0,0 +1,273 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestArchiveModule_V1Body(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		blockAPIBuilder func(ctrl *gomock.Controller) BlockAPI
+		res             []string
+		errWrapped      error
+		errMessage      string
+	}{
+		"unknown_block": {
+			blockAPIBuilder: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := mocks.NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetBlockByHash(common.Hash{1}).Return(nil, database.ErrNotFound)
+				return blockAPI
+			},
+		},
+		"get_block_error": {
+			blockAPIBuilder: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := mocks.NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetBlockByHash(common.Hash{1}).Return(nil, errTest)
+				return blockAPI
+			},
+			errWrapped: errTest,
+			errMessage: "getting block: test error",
+		},
+		"success": {
+			blockAPIBuilder: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := mocks.NewMockBlockAPI(ctrl)
+				block := &types.Block{Body: types.Body{{1, 2}, {3}}}
+				blockAPI.EXPECT().GetBlockByHash(common.Hash{1}).Return(block, nil)
+				return blockAPI
+			},
+			res: []string{"0x0102", "0x03"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := NewArchiveModule(testCase.blockAPIBuilder(ctrl), nil)
+
+			var res []string
+			err := module.V1Body(nil, &ArchiveHashRequest{Hash: common.Hash{1}}, &res)
+
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.res, res)
+		})
+	}
+}
+
+func TestArchiveModule_V1Header(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetHeader(common.Hash{1}).Return(nil, database.ErrNotFound)
+	blockAPI.EXPECT().GetHeader(common.Hash{2}).Return(types.NewEmptyHeader(), nil)
+	module := NewArchiveModule(blockAPI, nil)
+
+	var res interface{}
+	err := module.V1Header(nil, &ArchiveHashRequest{Hash: common.Hash{1}}, &res)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	err = module.V1Header(nil, &ArchiveHashRequest{Hash: common.Hash{2}}, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x"+
+		"0000000000000000000000000000000000000000000000000000000000000000"+
+		"00"+
+		"0000000000000000000000000000000000000000000000000000000000000000"+
+		"0000000000000000000000000000000000000000000000000000000000000000"+
+		"00", res)
+}
+
+func TestArchiveModule_V1FinalizedHeight(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetHighestFinalisedHash().Return(common.Hash{1}, nil)
+	blockAPI.EXPECT().GetHeader(common.Hash{1}).Return(&types.Header{Number: 21}, nil)
+	module := NewArchiveModule(blockAPI, nil)
+
+	var res uint
+	err := module.V1FinalizedHeight(nil, nil, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(21), res)
+}
+
+func TestArchiveModule_V1HashByHeight(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetHashesByNumber(uint(3)).Return([]common.Hash{{1}, {2}}, nil)
+	module := NewArchiveModule(blockAPI, nil)
+
+	var res []string
+	err := module.V1HashByHeight(nil, &ArchiveHeightRequest{Height: 3}, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{common.Hash{1}.String(), common.Hash{2}.String()}, res)
+}
+
+func TestArchiveModule_V1Storage(t *testing.T) {
+	t.Parallel()
+
+	root := common.Hash{9}
+	paginationStartKey := "0x0101"
+
+	testCases := map[string]struct {
+		storageAPIBuilder func(ctrl *gomock.Controller) StorageAPI
+		items             []ArchiveStorageQuery
+		res               ArchiveStorageResponse
+		errWrapped        error
+		errMessage        string
+	}{
+		"value_and_hash": {
+			storageAPIBuilder: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := mocks.NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&root, nil)
+				storageAPI.EXPECT().GetStorage(&root, []byte{1}).Return([]byte{2}, nil).Times(2)
+				storageAPI.EXPECT().GetStorage(&root, []byte{3}).Return(nil, nil)
+				return storageAPI
+			},
+			items: []ArchiveStorageQuery{
+				{Key: "0x01", Type: "value"},
+				{Key: "0x01", Type: "hash"},
+				{Key: "0x03", Type: "value"},
+			},
+			res: ArchiveStorageResponse{Result: []ArchiveStorageResult{
+				{Key: "0x01", Value: "0x02"},
+				{Key: "0x01", Hash: common.MustBlake2bHash([]byte{2}).String()},
+			}},
+		},
+		"closest_descendant_merkle_value": {
+			storageAPIBuilder: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := mocks.NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&root, nil)
+				storageAPI.EXPECT().GetClosestDescendantMerkleValue(&root, nil, []byte{1}).
+					Return([]byte{4, 5}, nil)
+				return storageAPI
+			},
+			items: []ArchiveStorageQuery{{Key: "0x01", Type: "closestDescendantMerkleValue"}},
+			res: ArchiveStorageResponse{Result: []ArchiveStorageResult{
+				{Key: "0x01", ClosestDescendantMerkleValue: "0x0405"},
+			}},
+		},
+		"descendants_pagination": {
+			storageAPIBuilder: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := mocks.NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&root, nil)
+				keys := [][]byte{{1, 2}, {1, 3}, {1, 4}, {1, 5}, {1, 6}}
+				storageAPI.EXPECT().GetKeysWithPrefixAfter(&root, nil, []byte{1}, []byte{1, 1}, uint(5)).
+					Return(keys, nil)
+				for _, key := range keys {
+					storageAPI.EXPECT().GetStorage(&root, key).Return([]byte{0}, nil)
+				}
+				return storageAPI
+			},
+			items: []ArchiveStorageQuery{
+				{Key: "0x01", Type: "descendantsValues", PaginationStartKey: &paginationStartKey},
+				{Key: "0x02", Type: "value"},
+			},
+			res: ArchiveStorageResponse{
+				Result: []ArchiveStorageResult{
+					{Key: "0x0102", Value: "0x00"},
+					{Key: "0x0103", Value: "0x00"},
+					{Key: "0x0104", Value: "0x00"},
+					{Key: "0x0105", Value: "0x00"},
+					{Key: "0x0106", Value: "0x00"},
+				},
+				DiscardedItems: 1,
+			},
+		},
+		"invalid_query_type": {
+			storageAPIBuilder: func(ctrl *gomock.Controller) StorageAPI {
+				storageAPI := mocks.NewMockStorageAPI(ctrl)
+				storageAPI.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&root, nil)
+				return storageAPI
+			},
+			items:      []ArchiveStorageQuery{{Key: "0x01", Type: "invalid"}},
+			res:        ArchiveStorageResponse{Result: []ArchiveStorageResult{}},
+			errWrapped: ErrInvalidStorageQueryType,
+			errMessage: "invalid storage query type: invalid",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := NewArchiveModule(nil, testCase.storageAPIBuilder(ctrl))
+
+			req := &ArchiveStorageRequest{Hash: common.Hash{1}, Items: testCase.items}
+			var res ArchiveStorageResponse
+			err := module.V1Storage(nil, req, &res)
+
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.res, res)
+		})
+	}
+}
+
+func TestArchiveModule_V1StorageDiff(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	root, previousRoot := common.Hash{8}, common.Hash{9}
+	parentHash := common.Hash{2}
+
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetHeader(common.Hash{1}).Return(&types.Header{ParentHash: parentHash}, nil)
+
+	storageAPI := mocks.NewMockStorageAPI(ctrl)
+	storageAPI.EXPECT().GetStateRootFromBlock(&common.Hash{1}).Return(&root, nil)
+	storageAPI.EXPECT().GetStateRootFromBlock(&parentHash).Return(&previousRoot, nil)
+	storageAPI.EXPECT().GetKeysWithPrefixAfter(&root, nil, []byte{}, nil, uint(math.MaxUint)).
+		Return([][]byte{{1}, {2}, {3}}, nil)
+	storageAPI.EXPECT().GetKeysWithPrefixAfter(&previousRoot, nil, []byte{}, nil, uint(math.MaxUint)).
+		Return([][]byte{{2}, {3}, {4}}, nil)
+	storageAPI.EXPECT().GetStorage(&root, []byte{1}).Return([]byte{1}, nil)
+	storageAPI.EXPECT().GetStorage(&root, []byte{2}).Return([]byte{2}, nil)
+	storageAPI.EXPECT().GetStorage(&previousRoot, []byte{2}).Return([]byte{2}, nil)
+	storageAPI.EXPECT().GetStorage(&root, []byte{3}).Return([]byte{3}, nil)
+	storageAPI.EXPECT().GetStorage(&previousRoot, []byte{3}).Return([]byte{0}, nil)
+
+	module := NewArchiveModule(blockAPI, storageAPI)
+
+	var res []ArchiveStorageDiffResult
+	err := module.V1StorageDiff(nil, &ArchiveStorageDiffRequest{Hash: common.Hash{1}}, &res)
+	assert.NoError(t, err)
+	expected := []ArchiveStorageDiffResult{
+		{Key: "0x01", Value: "0x01", Type: "added"},
+		{Key: "0x03", Value: "0x03", Type: "modified"},
+		{Key: "0x04", Type: "deleted"},
+	}
+	assert.Equal(t, expected, res)
+}
@@ -4,7 +4,9 @@
 package modules
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -15,7 +17,10 @@
 	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
-var ErrProvidedKeyDoesNotMatch = errors.New("generated public key does not equal provided public key")
+var (
+	ErrProvidedKeyDoesNotMatch = errors.New("generated public key does not equal provided public key")
+	ErrInvalidExtrinsicOrHash  = errors.New("expected exactly one of hash or extrinsic")
+)
 
 // AuthorModule holds a pointer to the API
 type AuthorModule struct {
@@ -47,6 +52,31 @@ type ExtrinsicOrHash struct {
 	Extrinsic []byte
 }
 
+// UnmarshalJSON decodes either a `{"hash": "0x..."}` or an `{"extrinsic": "0x..."}` object
+func (e *ExtrinsicOrHash) UnmarshalJSON(data []byte) error {
+	var value struct {
+		Hash      *common.Hash `json:"hash"`
+		Extrinsic *string      `json:"extrinsic"`
+	}
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case value.Hash != nil && value.Extrinsic == nil:
+		e.Hash = *value.Hash
+	case value.Extrinsic != nil && value.Hash == nil:
+		e.Extrinsic, err = common.HexToBytes(*value.Extrinsic)
+		if err != nil {
+			return fmt.Errorf("decoding extrinsic: %w", err)
+		}
+	default:
+		return ErrInvalidExtrinsicOrHash
+	}
+	return nil
+}
+
 // ExtrinsicOrHashRequest is a array of ExtrinsicOrHash
 type ExtrinsicOrHashRequest []ExtrinsicOrHash
 
@@ -59,8 +89,8 @@ type ExtrinsicOrHash struct {
 // RemoveExtrinsicsResponse is a array of hash used to Remove extrinsics
 type RemoveExtrinsicsResponse []common.Hash
 
-// KeyRotateResponse is a byte array used to rotate
-type KeyRotateResponse []byte
+// KeyRotateResponse is the hex encoded concatenation of the new session public keys
+type KeyRotateResponse string
 
 // HasSessionKeyResponse is the response to the RPC call author_hasSessionKeys
 type HasSessionKeyResponse bool
@@ -191,13 +221,34 @@ func (am *AuthorModule) PendingExtrinsics(r *http.Request, req *EmptyRequest, re
 	return nil
 }
 
-// RemoveExtrinsic Remove given extrinsic from the pool and temporarily ban it to prevent reimporting
-func (am *AuthorModule) RemoveExtrinsic(r *http.Request, _ *ExtrinsicOrHashRequest, _ *RemoveExtrinsicsResponse) error {
+// RemoveExtrinsic Remove given extrinsics, identified by hash or by their encoding, from the pool
+// and the queue and returns the hashes of the removed extrinsics
+func (am *AuthorModule) RemoveExtrinsic(r *http.Request, req *ExtrinsicOrHashRequest,
+	res *RemoveExtrinsicsResponse) error {
+	removed := RemoveExtrinsicsResponse{}
+	for _, extrinsicOrHash := range *req {
+		hash := extrinsicOrHash.Hash
+		if extrinsicOrHash.Extrinsic != nil {
+			hash = types.Extrinsic(extrinsicOrHash.Extrinsic).Hash()
+		}
+
+		if am.txStateAPI.RemoveExtrinsicByHash(hash) {
+			removed = append(removed, hash)
+		}
+	}
+
+	*res = removed
 	return nil
 }
 
 // RotateKeys Generate new session keys and returns the corresponding public keys
 func (am *AuthorModule) RotateKeys(r *http.Request, req *EmptyRequest, res *KeyRotateResponse) error {
+	keys, err := am.coreAPI.GenerateSessionKeys()
+	if err != nil {
+		return fmt.Errorf("generating session keys: %w", err)
+	}
+
+	*res = KeyRotateResponse(common.BytesToHex(keys))
 	return nil
 }
 
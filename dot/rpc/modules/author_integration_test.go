@@ -167,7 +167,7 @@ func TestAuthorModule_SubmitExtrinsic_Integration(t *testing.T) {
 	telemetryMock := NewMockTelemetry(ctrl)
 	telemetryMock.EXPECT().
 		SendMessage(
-			telemetry.NewTxpoolImport(0, 1),
+			telemetry.NewTxpoolImport(1, 0),
 		)
 
 	integrationTestController.stateSrv.Transaction = state.NewTransactionState(telemetryMock)
@@ -207,7 +207,7 @@ func TestAuthorModule_SubmitExtrinsic_Integration(t *testing.T) {
 	}
 
 	expectedHash := ExtrinsicHashResponse(expectedExtrinsic.Hash().String())
-	txOnPool := integrationTestController.stateSrv.Transaction.PendingInPool()
+	txOnPool := integrationTestController.stateSrv.Transaction.Pending()
 
 	// compare results
 	require.Len(t, txOnPool, 1)
@@ -247,7 +247,7 @@ func TestAuthorModule_SubmitExtrinsic_bad_proof(t *testing.T) {
 	err := auth.SubmitExtrinsic(nil, &Extrinsic{extHex}, res)
 	require.EqualError(t, err, "bad proof")
 
-	txOnPool := integrationTestController.stateSrv.Transaction.PendingInPool()
+	txOnPool := integrationTestController.stateSrv.Transaction.Pending()
 	require.Len(t, txOnPool, 0)
 }
 
@@ -272,7 +272,7 @@ func TestAuthorModule_SubmitExtrinsic_AlreadyInPool(t *testing.T) {
 	telemetryMock := NewMockTelemetry(ctrl)
 	telemetryMock.EXPECT().
 		SendMessage(
-			telemetry.NewTxpoolImport(0, 1),
+			telemetry.NewTxpoolImport(1, 0),
 		)
 
 	integrationTestController.stateSrv.Transaction = state.NewTransactionState(telemetryMock)
@@ -306,9 +306,10 @@ func TestAuthorModule_SubmitExtrinsic_AlreadyInPool(t *testing.T) {
 		},
 	}
 
-	integrationTestController.stateSrv.Transaction.AddToPool(expected)
+	_, err := integrationTestController.stateSrv.Transaction.AddToPool(expected)
+	require.NoError(t, err)
 
-	err := auth.SubmitExtrinsic(nil, &Extrinsic{extHex}, res)
+	err = auth.SubmitExtrinsic(nil, &Extrinsic{extHex}, res)
 	require.NoError(t, err)
 }
 
@@ -579,7 +580,7 @@ func TestAuthorModule_SubmitExtrinsic_WithVersion_V0929(t *testing.T) {
 	telemetryMock := NewMockTelemetry(ctrl)
 	telemetryMock.EXPECT().
 		SendMessage(
-			telemetry.NewTxpoolImport(0, 1),
+			telemetry.NewTxpoolImport(1, 0),
 		)
 
 	integrationTestController.stateSrv.Transaction = state.NewTransactionState(telemetryMock)
@@ -613,7 +614,7 @@ func TestAuthorModule_SubmitExtrinsic_WithVersion_V0929(t *testing.T) {
 	}
 
 	expectedHash := ExtrinsicHashResponse(expectedExtrinsic.Hash().String())
-	txOnPool := integrationTestController.stateSrv.Transaction.PendingInPool()
+	txOnPool := integrationTestController.stateSrv.Transaction.Pending()
 
 	// compare results
 	require.Len(t, txOnPool, 1)
@@ -4,6 +4,7 @@
 package modules
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -494,3 +495,148 @@ type args struct {
 		})
 	}
 }
+
+func TestAuthorModule_RemoveExtrinsic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	ext := types.Extrinsic("someExtrinsic")
+	otherHash := common.Hash{1}
+
+	mockTransactionStateAPI := mocks.NewMockTransactionStateAPI(ctrl)
+	mockTransactionStateAPI.EXPECT().RemoveExtrinsicByHash(ext.Hash()).Return(true)
+	mockTransactionStateAPI.EXPECT().RemoveExtrinsicByHash(otherHash).Return(false)
+
+	type args struct {
+		r   *http.Request
+		req *ExtrinsicOrHashRequest
+	}
+	tests := []struct {
+		name       string
+		txStateAPI TransactionStateAPI
+		args       args
+		wantRes    RemoveExtrinsicsResponse
+	}{
+		{
+			name: "no_extrinsics",
+			args: args{
+				req: &ExtrinsicOrHashRequest{},
+			},
+			wantRes: RemoveExtrinsicsResponse{},
+		},
+		{
+			name:       "extrinsic_and_unknown_hash",
+			txStateAPI: mockTransactionStateAPI,
+			args: args{
+				req: &ExtrinsicOrHashRequest{
+					{Extrinsic: ext},
+					{Hash: otherHash},
+				},
+			},
+			wantRes: RemoveExtrinsicsResponse{ext.Hash()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := &AuthorModule{
+				txStateAPI: tt.txStateAPI,
+			}
+			var res RemoveExtrinsicsResponse
+			err := am.RemoveExtrinsic(tt.args.r, tt.args.req, &res)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRes, res)
+		})
+	}
+}
+
+func TestExtrinsicOrHash_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data       string
+		expected   ExtrinsicOrHash
+		errMessage string
+	}{
+		"hash": {
+			data: `{"hash":"0x0100000000000000000000000000000000000000000000000000000000000000"}`,
+			expected: ExtrinsicOrHash{
+				Hash: common.Hash{1},
+			},
+		},
+		"extrinsic": {
+			data: `{"extrinsic":"0x0102"}`,
+			expected: ExtrinsicOrHash{
+				Extrinsic: []byte{1, 2},
+			},
+		},
+		"both": {
+			data:       `{"hash":"0x0100000000000000000000000000000000000000000000000000000000000000","extrinsic":"0x01"}`,
+			errMessage: ErrInvalidExtrinsicOrHash.Error(),
+		},
+		"neither": {
+			data:       `{}`,
+			errMessage: ErrInvalidExtrinsicOrHash.Error(),
+		},
+		"invalid_extrinsic": {
+			data:       `{"extrinsic":"0x0"}`,
+			errMessage: "decoding extrinsic: encoding/hex: odd length hex string: 0x0",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var extrinsicOrHash ExtrinsicOrHash
+			err := json.Unmarshal([]byte(tt.data), &extrinsicOrHash)
+			if tt.errMessage != "" {
+				assert.EqualError(t, err, tt.errMessage)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, extrinsicOrHash)
+		})
+	}
+}
+
+func TestAuthorModule_RotateKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockCoreAPIOk := mocks.NewMockCoreAPI(ctrl)
+	mockCoreAPIOk.EXPECT().GenerateSessionKeys().Return([]byte{1, 2, 3}, nil)
+
+	mockCoreAPIErr := mocks.NewMockCoreAPI(ctrl)
+	mockCoreAPIErr.EXPECT().GenerateSessionKeys().Return(nil, errors.New("some error"))
+
+	tests := []struct {
+		name    string
+		coreAPI CoreAPI
+		expErr  error
+		wantRes KeyRotateResponse
+	}{
+		{
+			name:    "ok",
+			coreAPI: mockCoreAPIOk,
+			wantRes: KeyRotateResponse("0x010203"),
+		},
+		{
+			name:    "generate_error",
+			coreAPI: mockCoreAPIErr,
+			expErr:  errors.New("generating session keys: some error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			am := &AuthorModule{
+				coreAPI: tt.coreAPI,
+			}
+			var res KeyRotateResponse
+			err := am.RotateKeys(nil, nil, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRes, res)
+		})
+	}
+}
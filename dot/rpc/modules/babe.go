@@ -0,0 +1,41 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/lib/babe"
+)
+
+// BabeModule is an RPC module providing access to BABE methods
+type BabeModule struct {
+	blockProducerAPI BlockProducerAPI
+}
+
+// NewBabeModule creates a new BABE module
+func NewBabeModule(blockProducerAPI BlockProducerAPI) *BabeModule {
+	return &BabeModule{
+		blockProducerAPI: blockProducerAPI,
+	}
+}
+
+// EpochAuthorship returns, for each key of the BABE keystore which is an authority of the
+// current epoch, the primary, secondary plain and secondary VRF slots it may claim
+func (bm *BabeModule) EpochAuthorship(_ *http.Request, _ *EmptyRequest,
+	res *map[string]*babe.EpochAuthorship) error {
+	if bm.blockProducerAPI == nil {
+		return errors.New("not a block producer")
+	}
+
+	authorships, err := bm.blockProducerAPI.EpochAuthorship()
+	if err != nil {
+		return fmt.Errorf("getting epoch authorship: %w", err)
+	}
+
+	*res = authorships
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBabeModule_EpochAuthorship(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	authorships := map[string]*babe.EpochAuthorship{
+		"5GrwvaEF5zXb26Fz9rcQpDWS57CtERHpNehXCPcNoHGKutQY": {
+			Primary:      []uint64{1},
+			Secondary:    []uint64{2, 3},
+			SecondaryVRF: []uint64{},
+		},
+	}
+
+	testCases := map[string]struct {
+		blockProducerAPIBuilder func(ctrl *gomock.Controller) BlockProducerAPI
+		res                     map[string]*babe.EpochAuthorship
+		errMessage              string
+	}{
+		"not_block_producer": {
+			blockProducerAPIBuilder: func(ctrl *gomock.Controller) BlockProducerAPI { return nil },
+			errMessage:              "not a block producer",
+		},
+		"epoch_authorship_error": {
+			blockProducerAPIBuilder: func(ctrl *gomock.Controller) BlockProducerAPI {
+				blockProducerAPI := mocks.NewMockBlockProducerAPI(ctrl)
+				blockProducerAPI.EXPECT().EpochAuthorship().Return(nil, errTest)
+				return blockProducerAPI
+			},
+			errMessage: "getting epoch authorship: test error",
+		},
+		"success": {
+			blockProducerAPIBuilder: func(ctrl *gomock.Controller) BlockProducerAPI {
+				blockProducerAPI := mocks.NewMockBlockProducerAPI(ctrl)
+				blockProducerAPI.EXPECT().EpochAuthorship().Return(authorships, nil)
+				return blockProducerAPI
+			},
+			res: authorships,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := NewBabeModule(testCase.blockProducerAPIBuilder(ctrl))
+
+			var res map[string]*babe.EpochAuthorship
+			err := module.EpochAuthorship(nil, nil, &res)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.res, res)
+		})
+	}
+}
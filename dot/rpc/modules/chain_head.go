@@ -0,0 +1,60 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"net/http"
+)
+
+// ChainHeadModule holds the RPC method names of the chainHead_v1 methods of the new JSON-RPC
+// interface. These methods operate on a chainHead_v1_follow subscription and are therefore
+// handled by the websocket handler.
+type ChainHeadModule struct{}
+
+// NewChainHeadModule returns a pointer to ChainHeadModule
+func NewChainHeadModule() *ChainHeadModule {
+	return &ChainHeadModule{}
+}
+
+// V1Follow handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*ChainHeadModule) V1Follow(_ *http.Request, _ *EmptyRequest, _ *string) error {
+	return ErrSubscriptionTransport
+}
+
+// V1Unfollow handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*ChainHeadModule) V1Unfollow(_ *http.Request, _ *EmptyRequest, _ *interface{}) error {
+	return ErrSubscriptionTransport
+}
+
+// V1Header handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*ChainHeadModule) V1Header(_ *http.Request, _ *EmptyRequest, _ *string) error {
+	return ErrSubscriptionTransport
+}
+
+// V1Body handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*ChainHeadModule) V1Body(_ *http.Request, _ *EmptyRequest, _ *interface{}) error {
+	return ErrSubscriptionTransport
+}
+
+// V1Storage handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*ChainHeadModule) V1Storage(_ *http.Request, _ *EmptyRequest, _ *interface{}) error {
+	return ErrSubscriptionTransport
+}
+
+// V1Call handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*ChainHeadModule) V1Call(_ *http.Request, _ *EmptyRequest, _ *interface{}) error {
+	return ErrSubscriptionTransport
+}
+
+// V1Unpin handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*ChainHeadModule) V1Unpin(_ *http.Request, _ *EmptyRequest, _ *interface{}) error {
+	return ErrSubscriptionTransport
+}
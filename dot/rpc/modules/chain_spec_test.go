@@ -0,0 +1,95 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestChainSpecModule_V1GenesisHash(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		blockAPIBuilder func(ctrl *gomock.Controller) BlockAPI
+		res             string
+		errWrapped      error
+		errMessage      string
+	}{
+		"get_hash_error": {
+			blockAPIBuilder: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := mocks.NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetHashByNumber(uint(0)).Return(common.Hash{}, errTest)
+				return blockAPI
+			},
+			errWrapped: errTest,
+			errMessage: "getting genesis hash: test error",
+		},
+		"success": {
+			blockAPIBuilder: func(ctrl *gomock.Controller) BlockAPI {
+				blockAPI := mocks.NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().GetHashByNumber(uint(0)).Return(common.Hash{1}, nil)
+				return blockAPI
+			},
+			res: "0x0100000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := NewChainSpecModule(nil, testCase.blockAPIBuilder(ctrl))
+
+			var res string
+			err := module.V1GenesisHash(nil, nil, &res)
+
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.res, res)
+		})
+	}
+}
+
+func TestChainSpecModule_V1ChainName(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	systemAPI := mocks.NewMockSystemAPI(ctrl)
+	systemAPI.EXPECT().ChainName().Return("Westend")
+	module := NewChainSpecModule(systemAPI, nil)
+
+	var res string
+	err := module.V1ChainName(nil, nil, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, "Westend", res)
+}
+
+func TestChainSpecModule_V1Properties(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	properties := map[string]interface{}{"tokenSymbol": "WND", "tokenDecimals": float64(12)}
+	systemAPI := mocks.NewMockSystemAPI(ctrl)
+	systemAPI.EXPECT().Properties().Return(properties)
+	module := NewChainSpecModule(systemAPI, nil)
+
+	var res interface{}
+	err := module.V1Properties(nil, nil, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, properties, res)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// EngineCreateBlockRequest holds the parameters of engine_createBlock
+type EngineCreateBlockRequest struct {
+	CreateEmpty bool
+	Finalise    bool
+	ParentHash  *common.Hash
+}
+
+// EngineImportedAux holds the import outcome of a created block
+type EngineImportedAux struct {
+	HeaderOnly                 bool `json:"headerOnly"`
+	ClearJustificationRequests bool `json:"clearJustificationRequests"`
+	NeedsJustification         bool `json:"needsJustification"`
+	BadJustification           bool `json:"badJustification"`
+	IsNewBest                  bool `json:"isNewBest"`
+}
+
+// EngineCreateBlockResponse is the response of engine_createBlock
+type EngineCreateBlockResponse struct {
+	Hash common.Hash       `json:"hash"`
+	Aux  EngineImportedAux `json:"aux"`
+}
+
+// EngineFinaliseBlockRequest holds the parameters of engine_finalizeBlock. The justification
+// is accepted for compatibility and ignored.
+type EngineFinaliseBlockRequest struct {
+	Hash          common.Hash
+	Justification *string
+}
+
+// EngineModule is an RPC module to author and finalise blocks on demand when the node runs
+// with manual or instant sealing
+type EngineModule struct {
+	sealingAPI SealingAPI
+	blockAPI   BlockAPI
+}
+
+// NewEngineModule creates a new engine module
+func NewEngineModule(sealingAPI SealingAPI, blockAPI BlockAPI) *EngineModule {
+	return &EngineModule{
+		sealingAPI: sealingAPI,
+		blockAPI:   blockAPI,
+	}
+}
+
+// CreateBlock authors a block on top of the given parent, or on top of the best block if no
+// parent is given, and imports it
+func (m *EngineModule) CreateBlock(_ *http.Request, req *EngineCreateBlockRequest,
+	res *EngineCreateBlockResponse) error {
+	if m.sealingAPI == nil {
+		return ErrSealingDisabled
+	}
+
+	hash, err := m.sealingAPI.CreateBlock(req.CreateEmpty, req.Finalise, req.ParentHash)
+	if err != nil {
+		return fmt.Errorf("creating block: %w", err)
+	}
+
+	*res = EngineCreateBlockResponse{
+		Hash: hash,
+		Aux: EngineImportedAux{
+			IsNewBest: m.blockAPI.BestBlockHash() == hash,
+		},
+	}
+	return nil
+}
+
+// FinalizeBlock finalises the block with the given hash and its ancestors
+func (m *EngineModule) FinalizeBlock(_ *http.Request, req *EngineFinaliseBlockRequest, res *bool) error {
+	if m.sealingAPI == nil {
+		return ErrSealingDisabled
+	}
+
+	err := m.sealingAPI.FinaliseBlock(req.Hash)
+	if err != nil {
+		return fmt.Errorf("finalising block: %w", err)
+	}
+
+	*res = true
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEngineModule_CreateBlock(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	parentHash := common.Hash{1}
+
+	testCases := map[string]struct {
+		buildModule func(ctrl *gomock.Controller) *EngineModule
+		res         EngineCreateBlockResponse
+		errWrapped  error
+		errMessage  string
+	}{
+		"sealing_disabled": {
+			buildModule: func(ctrl *gomock.Controller) *EngineModule {
+				return NewEngineModule(nil, mocks.NewMockBlockAPI(ctrl))
+			},
+			errWrapped: ErrSealingDisabled,
+			errMessage: "manual or instant sealing is not enabled",
+		},
+		"create_block_error": {
+			buildModule: func(ctrl *gomock.Controller) *EngineModule {
+				sealingAPI := mocks.NewMockSealingAPI(ctrl)
+				sealingAPI.EXPECT().CreateBlock(true, false, &parentHash).Return(common.Hash{}, errTest)
+				return NewEngineModule(sealingAPI, mocks.NewMockBlockAPI(ctrl))
+			},
+			errWrapped: errTest,
+			errMessage: "creating block: test error",
+		},
+		"new_best_block": {
+			buildModule: func(ctrl *gomock.Controller) *EngineModule {
+				sealingAPI := mocks.NewMockSealingAPI(ctrl)
+				sealingAPI.EXPECT().CreateBlock(true, false, &parentHash).Return(common.Hash{2}, nil)
+				blockAPI := mocks.NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().BestBlockHash().Return(common.Hash{2})
+				return NewEngineModule(sealingAPI, blockAPI)
+			},
+			res: EngineCreateBlockResponse{
+				Hash: common.Hash{2},
+				Aux:  EngineImportedAux{IsNewBest: true},
+			},
+		},
+		"fork_block": {
+			buildModule: func(ctrl *gomock.Controller) *EngineModule {
+				sealingAPI := mocks.NewMockSealingAPI(ctrl)
+				sealingAPI.EXPECT().CreateBlock(true, false, &parentHash).Return(common.Hash{2}, nil)
+				blockAPI := mocks.NewMockBlockAPI(ctrl)
+				blockAPI.EXPECT().BestBlockHash().Return(common.Hash{3})
+				return NewEngineModule(sealingAPI, blockAPI)
+			},
+			res: EngineCreateBlockResponse{Hash: common.Hash{2}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			module := testCase.buildModule(ctrl)
+
+			req := &EngineCreateBlockRequest{CreateEmpty: true, ParentHash: &parentHash}
+			var res EngineCreateBlockResponse
+			err := module.CreateBlock(nil, req, &res)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.res, res)
+		})
+	}
+}
+
+func TestEngineModule_FinalizeBlock(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	errTest := errors.New("test error")
+	sealingAPI := mocks.NewMockSealingAPI(ctrl)
+	sealingAPI.EXPECT().FinaliseBlock(common.Hash{1}).Return(errTest)
+	sealingAPI.EXPECT().FinaliseBlock(common.Hash{2}).Return(nil)
+	module := NewEngineModule(sealingAPI, nil)
+
+	var res bool
+	err := module.FinalizeBlock(nil, &EngineFinaliseBlockRequest{Hash: common.Hash{1}}, &res)
+	assert.ErrorIs(t, err, errTest)
+	assert.EqualError(t, err, "finalising block: test error")
+	assert.False(t, res)
+
+	err = module.FinalizeBlock(nil, &EngineFinaliseBlockRequest{Hash: common.Hash{2}}, &res)
+	assert.NoError(t, err)
+	assert.True(t, res)
+}
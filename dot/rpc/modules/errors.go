@@ -6,6 +6,9 @@
 import "errors"
 
 var (
-	ErrSubscriptionTransport = errors.New("subscriptions are not available on this transport")
-	ErrStartBlockHashEmpty   = errors.New("the start block hash cannot be an empty value")
+	ErrSubscriptionTransport   = errors.New("subscriptions are not available on this transport")
+	ErrStartBlockHashEmpty     = errors.New("the start block hash cannot be an empty value")
+	ErrInvalidOperationID      = errors.New("invalid operation id")
+	ErrInvalidStorageQueryType = errors.New("invalid storage query type")
+	ErrSealingDisabled         = errors.New("manual or instant sealing is not enabled")
 )
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/ChainSafe/gossamer/dot/rpc/modules (interfaces: StorageAPI,BlockAPI,NetworkAPI,BlockProducerAPI,TransactionStateAPI,CoreAPI,SystemAPI,BlockFinalityAPI,RuntimeStorageAPI,SyncStateAPI)
+// Source: github.com/ChainSafe/gossamer/dot/rpc/modules (interfaces: StorageAPI,BlockAPI,NetworkAPI,BlockProducerAPI,TransactionStateAPI,CoreAPI,SystemAPI,BlockFinalityAPI,RuntimeStorageAPI,SyncStateAPI,SealingAPI)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks/mocks.go -package mocks . StorageAPI,BlockAPI,NetworkAPI,BlockProducerAPI,TransactionStateAPI,CoreAPI,SystemAPI,BlockFinalityAPI,RuntimeStorageAPI,SyncStateAPI
+//	mockgen -destination=mocks/mocks.go -package mocks . StorageAPI,BlockAPI,NetworkAPI,BlockProducerAPI,TransactionStateAPI,CoreAPI,SystemAPI,BlockFinalityAPI,RuntimeStorageAPI,SyncStateAPI,SealingAPI
 //
 
 // Package mocks is a generated GoMock package.
@@ -15,10 +15,12 @@
 	core "github.com/ChainSafe/gossamer/dot/core"
 	state "github.com/ChainSafe/gossamer/dot/state"
 	types "github.com/ChainSafe/gossamer/dot/types"
+	babe "github.com/ChainSafe/gossamer/lib/babe"
 	common "github.com/ChainSafe/gossamer/lib/common"
 	ed25519 "github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	genesis "github.com/ChainSafe/gossamer/lib/genesis"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	transaction "github.com/ChainSafe/gossamer/lib/transaction"
 	trie "github.com/ChainSafe/gossamer/pkg/trie"
 	gomock "go.uber.org/mock/gomock"
@@ -62,6 +64,21 @@ func (mr *MockStorageAPIMockRecorder) Entries(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Entries", reflect.TypeOf((*MockStorageAPI)(nil).Entries), arg0)
 }
 
+// GetClosestDescendantMerkleValue mocks base method.
+func (m *MockStorageAPI) GetClosestDescendantMerkleValue(arg0 *common.Hash, arg1, arg2 []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosestDescendantMerkleValue", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosestDescendantMerkleValue indicates an expected call of GetClosestDescendantMerkleValue.
+func (mr *MockStorageAPIMockRecorder) GetClosestDescendantMerkleValue(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosestDescendantMerkleValue", reflect.TypeOf((*MockStorageAPI)(nil).GetClosestDescendantMerkleValue), arg0, arg1, arg2)
+}
+
 // GetKeysWithPrefix mocks base method.
 func (m *MockStorageAPI) GetKeysWithPrefix(arg0 *common.Hash, arg1 []byte) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -77,6 +94,21 @@ func (mr *MockStorageAPIMockRecorder) GetKeysWithPrefix(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeysWithPrefix", reflect.TypeOf((*MockStorageAPI)(nil).GetKeysWithPrefix), arg0, arg1)
 }
 
+// GetKeysWithPrefixAfter mocks base method.
+func (m *MockStorageAPI) GetKeysWithPrefixAfter(arg0 *common.Hash, arg1, arg2, arg3 []byte, arg4 uint) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeysWithPrefixAfter", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeysWithPrefixAfter indicates an expected call of GetKeysWithPrefixAfter.
+func (mr *MockStorageAPIMockRecorder) GetKeysWithPrefixAfter(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeysWithPrefixAfter", reflect.TypeOf((*MockStorageAPI)(nil).GetKeysWithPrefixAfter), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetStateRootFromBlock mocks base method.
 func (m *MockStorageAPI) GetStateRootFromBlock(arg0 *common.Hash) (*common.Hash, error) {
 	m.ctrl.T.Helper()
@@ -164,6 +196,21 @@ func (mr *MockStorageAPIMockRecorder) RegisterStorageObserver(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterStorageObserver", reflect.TypeOf((*MockStorageAPI)(nil).RegisterStorageObserver), arg0)
 }
 
+// TrieState mocks base method.
+func (m *MockStorageAPI) TrieState(arg0 *common.Hash) (*storage.TrieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrieState", arg0)
+	ret0, _ := ret[0].(*storage.TrieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrieState indicates an expected call of TrieState.
+func (mr *MockStorageAPIMockRecorder) TrieState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrieState", reflect.TypeOf((*MockStorageAPI)(nil).TrieState), arg0)
+}
+
 // UnregisterStorageObserver mocks base method.
 func (m *MockStorageAPI) UnregisterStorageObserver(arg0 state.Observer) {
 	m.ctrl.T.Helper()
@@ -296,6 +343,21 @@ func (mr *MockBlockAPIMockRecorder) GetHashByNumber(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashByNumber", reflect.TypeOf((*MockBlockAPI)(nil).GetHashByNumber), arg0)
 }
 
+// GetHashesByNumber mocks base method.
+func (m *MockBlockAPI) GetHashesByNumber(arg0 uint) ([]common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHashesByNumber", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHashesByNumber indicates an expected call of GetHashesByNumber.
+func (mr *MockBlockAPIMockRecorder) GetHashesByNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashesByNumber", reflect.TypeOf((*MockBlockAPI)(nil).GetHashesByNumber), arg0)
+}
+
 // GetHeader mocks base method.
 func (m *MockBlockAPI) GetHeader(arg0 common.Hash) (*types.Header, error) {
 	m.ctrl.T.Helper()
@@ -385,6 +447,20 @@ func (mr *MockBlockAPIMockRecorder) HasJustification(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasJustification", reflect.TypeOf((*MockBlockAPI)(nil).HasJustification), arg0)
 }
 
+// PinBlock mocks base method.
+func (m *MockBlockAPI) PinBlock(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinBlock indicates an expected call of PinBlock.
+func (mr *MockBlockAPIMockRecorder) PinBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinBlock", reflect.TypeOf((*MockBlockAPI)(nil).PinBlock), arg0)
+}
+
 // RangeInMemory mocks base method.
 func (m *MockBlockAPI) RangeInMemory(arg0, arg1 common.Hash) ([]common.Hash, error) {
 	m.ctrl.T.Helper()
@@ -415,6 +491,20 @@ func (mr *MockBlockAPIMockRecorder) RegisterRuntimeUpdatedChannel(arg0 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRuntimeUpdatedChannel", reflect.TypeOf((*MockBlockAPI)(nil).RegisterRuntimeUpdatedChannel), arg0)
 }
 
+// UnpinBlock mocks base method.
+func (m *MockBlockAPI) UnpinBlock(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinBlock indicates an expected call of UnpinBlock.
+func (mr *MockBlockAPIMockRecorder) UnpinBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinBlock", reflect.TypeOf((*MockBlockAPI)(nil).UnpinBlock), arg0)
+}
+
 // UnregisterRuntimeUpdatedChannel mocks base method.
 func (m *MockBlockAPI) UnregisterRuntimeUpdatedChannel(arg0 uint32) bool {
 	m.ctrl.T.Helper()
@@ -609,6 +699,21 @@ func (m *MockBlockProducerAPI) EXPECT() *MockBlockProducerAPIMockRecorder {
 	return m.recorder
 }
 
+// EpochAuthorship mocks base method.
+func (m *MockBlockProducerAPI) EpochAuthorship() (map[string]*babe.EpochAuthorship, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EpochAuthorship")
+	ret0, _ := ret[0].(map[string]*babe.EpochAuthorship)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EpochAuthorship indicates an expected call of EpochAuthorship.
+func (mr *MockBlockProducerAPIMockRecorder) EpochAuthorship() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EpochAuthorship", reflect.TypeOf((*MockBlockProducerAPI)(nil).EpochAuthorship))
+}
+
 // EpochLength mocks base method.
 func (m *MockBlockProducerAPI) EpochLength() uint64 {
 	m.ctrl.T.Helper()
@@ -702,6 +807,20 @@ func (mr *MockTransactionStateAPIMockRecorder) Pending() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockTransactionStateAPI)(nil).Pending))
 }
 
+// RemoveExtrinsicByHash mocks base method.
+func (m *MockTransactionStateAPI) RemoveExtrinsicByHash(arg0 common.Hash) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveExtrinsicByHash", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// RemoveExtrinsicByHash indicates an expected call of RemoveExtrinsicByHash.
+func (mr *MockTransactionStateAPIMockRecorder) RemoveExtrinsicByHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveExtrinsicByHash", reflect.TypeOf((*MockTransactionStateAPI)(nil).RemoveExtrinsicByHash), arg0)
+}
+
 // MockCoreAPI is a mock of CoreAPI interface.
 type MockCoreAPI struct {
 	ctrl     *gomock.Controller
@@ -740,6 +859,21 @@ func (mr *MockCoreAPIMockRecorder) DecodeSessionKeys(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeSessionKeys", reflect.TypeOf((*MockCoreAPI)(nil).DecodeSessionKeys), arg0)
 }
 
+// GenerateSessionKeys mocks base method.
+func (m *MockCoreAPI) GenerateSessionKeys() ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateSessionKeys")
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateSessionKeys indicates an expected call of GenerateSessionKeys.
+func (mr *MockCoreAPIMockRecorder) GenerateSessionKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockCoreAPI)(nil).GenerateSessionKeys))
+}
+
 // GetMetadata mocks base method.
 func (m *MockCoreAPI) GetMetadata(arg0 *common.Hash) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -852,6 +986,20 @@ func (m *MockSystemAPI) EXPECT() *MockSystemAPIMockRecorder {
 	return m.recorder
 }
 
+// AddLogFilter mocks base method.
+func (m *MockSystemAPI) AddLogFilter(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLogFilter", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddLogFilter indicates an expected call of AddLogFilter.
+func (mr *MockSystemAPIMockRecorder) AddLogFilter(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLogFilter", reflect.TypeOf((*MockSystemAPI)(nil).AddLogFilter), arg0)
+}
+
 // ChainName mocks base method.
 func (m *MockSystemAPI) ChainName() string {
 	m.ctrl.T.Helper()
@@ -894,6 +1042,20 @@ func (mr *MockSystemAPIMockRecorder) Properties() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Properties", reflect.TypeOf((*MockSystemAPI)(nil).Properties))
 }
 
+// ResetLogFilter mocks base method.
+func (m *MockSystemAPI) ResetLogFilter() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLogFilter")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLogFilter indicates an expected call of ResetLogFilter.
+func (mr *MockSystemAPIMockRecorder) ResetLogFilter() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLogFilter", reflect.TypeOf((*MockSystemAPI)(nil).ResetLogFilter))
+}
+
 // SystemName mocks base method.
 func (m *MockSystemAPI) SystemName() string {
 	m.ctrl.T.Helper()
@@ -1133,3 +1295,55 @@ func (mr *MockSyncStateAPIMockRecorder) GenSyncSpec(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenSyncSpec", reflect.TypeOf((*MockSyncStateAPI)(nil).GenSyncSpec), arg0)
 }
+
+// MockSealingAPI is a mock of SealingAPI interface.
+type MockSealingAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockSealingAPIMockRecorder
+}
+
+// MockSealingAPIMockRecorder is the mock recorder for MockSealingAPI.
+type MockSealingAPIMockRecorder struct {
+	mock *MockSealingAPI
+}
+
+// NewMockSealingAPI creates a new mock instance.
+func NewMockSealingAPI(ctrl *gomock.Controller) *MockSealingAPI {
+	mock := &MockSealingAPI{ctrl: ctrl}
+	mock.recorder = &MockSealingAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSealingAPI) EXPECT() *MockSealingAPIMockRecorder {
+	return m.recorder
+}
+
+// CreateBlock mocks base method.
+func (m *MockSealingAPI) CreateBlock(arg0, arg1 bool, arg2 *common.Hash) (common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlock", arg0, arg1, arg2)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBlock indicates an expected call of CreateBlock.
+func (mr *MockSealingAPIMockRecorder) CreateBlock(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlock", reflect.TypeOf((*MockSealingAPI)(nil).CreateBlock), arg0, arg1, arg2)
+}
+
+// FinaliseBlock mocks base method.
+func (m *MockSealingAPI) FinaliseBlock(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinaliseBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinaliseBlock indicates an expected call of FinaliseBlock.
+func (mr *MockSealingAPIMockRecorder) FinaliseBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinaliseBlock", reflect.TypeOf((*MockSealingAPI)(nil).FinaliseBlock), arg0)
+}
@@ -17,6 +17,7 @@
 	types "github.com/ChainSafe/gossamer/dot/types"
 	common "github.com/ChainSafe/gossamer/lib/common"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	trie "github.com/ChainSafe/gossamer/pkg/trie"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -59,6 +60,21 @@ func (mr *MockStorageAPIMockRecorder) Entries(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Entries", reflect.TypeOf((*MockStorageAPI)(nil).Entries), arg0)
 }
 
+// GetClosestDescendantMerkleValue mocks base method.
+func (m *MockStorageAPI) GetClosestDescendantMerkleValue(arg0 *common.Hash, arg1, arg2 []byte) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClosestDescendantMerkleValue", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClosestDescendantMerkleValue indicates an expected call of GetClosestDescendantMerkleValue.
+func (mr *MockStorageAPIMockRecorder) GetClosestDescendantMerkleValue(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClosestDescendantMerkleValue", reflect.TypeOf((*MockStorageAPI)(nil).GetClosestDescendantMerkleValue), arg0, arg1, arg2)
+}
+
 // GetKeysWithPrefix mocks base method.
 func (m *MockStorageAPI) GetKeysWithPrefix(arg0 *common.Hash, arg1 []byte) ([][]byte, error) {
 	m.ctrl.T.Helper()
@@ -74,6 +90,21 @@ func (mr *MockStorageAPIMockRecorder) GetKeysWithPrefix(arg0, arg1 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeysWithPrefix", reflect.TypeOf((*MockStorageAPI)(nil).GetKeysWithPrefix), arg0, arg1)
 }
 
+// GetKeysWithPrefixAfter mocks base method.
+func (m *MockStorageAPI) GetKeysWithPrefixAfter(arg0 *common.Hash, arg1, arg2, arg3 []byte, arg4 uint) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetKeysWithPrefixAfter", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetKeysWithPrefixAfter indicates an expected call of GetKeysWithPrefixAfter.
+func (mr *MockStorageAPIMockRecorder) GetKeysWithPrefixAfter(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetKeysWithPrefixAfter", reflect.TypeOf((*MockStorageAPI)(nil).GetKeysWithPrefixAfter), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetStateRootFromBlock mocks base method.
 func (m *MockStorageAPI) GetStateRootFromBlock(arg0 *common.Hash) (*common.Hash, error) {
 	m.ctrl.T.Helper()
@@ -161,6 +192,21 @@ func (mr *MockStorageAPIMockRecorder) RegisterStorageObserver(arg0 any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterStorageObserver", reflect.TypeOf((*MockStorageAPI)(nil).RegisterStorageObserver), arg0)
 }
 
+// TrieState mocks base method.
+func (m *MockStorageAPI) TrieState(arg0 *common.Hash) (*storage.TrieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrieState", arg0)
+	ret0, _ := ret[0].(*storage.TrieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrieState indicates an expected call of TrieState.
+func (mr *MockStorageAPIMockRecorder) TrieState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrieState", reflect.TypeOf((*MockStorageAPI)(nil).TrieState), arg0)
+}
+
 // UnregisterStorageObserver mocks base method.
 func (m *MockStorageAPI) UnregisterStorageObserver(arg0 state.Observer) {
 	m.ctrl.T.Helper()
@@ -293,6 +339,21 @@ func (mr *MockBlockAPIMockRecorder) GetHashByNumber(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashByNumber", reflect.TypeOf((*MockBlockAPI)(nil).GetHashByNumber), arg0)
 }
 
+// GetHashesByNumber mocks base method.
+func (m *MockBlockAPI) GetHashesByNumber(arg0 uint) ([]common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHashesByNumber", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHashesByNumber indicates an expected call of GetHashesByNumber.
+func (mr *MockBlockAPIMockRecorder) GetHashesByNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHashesByNumber", reflect.TypeOf((*MockBlockAPI)(nil).GetHashesByNumber), arg0)
+}
+
 // GetHeader mocks base method.
 func (m *MockBlockAPI) GetHeader(arg0 common.Hash) (*types.Header, error) {
 	m.ctrl.T.Helper()
@@ -382,6 +443,20 @@ func (mr *MockBlockAPIMockRecorder) HasJustification(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasJustification", reflect.TypeOf((*MockBlockAPI)(nil).HasJustification), arg0)
 }
 
+// PinBlock mocks base method.
+func (m *MockBlockAPI) PinBlock(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PinBlock indicates an expected call of PinBlock.
+func (mr *MockBlockAPIMockRecorder) PinBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinBlock", reflect.TypeOf((*MockBlockAPI)(nil).PinBlock), arg0)
+}
+
 // RangeInMemory mocks base method.
 func (m *MockBlockAPI) RangeInMemory(arg0, arg1 common.Hash) ([]common.Hash, error) {
 	m.ctrl.T.Helper()
@@ -412,6 +487,20 @@ func (mr *MockBlockAPIMockRecorder) RegisterRuntimeUpdatedChannel(arg0 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRuntimeUpdatedChannel", reflect.TypeOf((*MockBlockAPI)(nil).RegisterRuntimeUpdatedChannel), arg0)
 }
 
+// UnpinBlock mocks base method.
+func (m *MockBlockAPI) UnpinBlock(arg0 common.Hash) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpinBlock indicates an expected call of UnpinBlock.
+func (mr *MockBlockAPIMockRecorder) UnpinBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinBlock", reflect.TypeOf((*MockBlockAPI)(nil).UnpinBlock), arg0)
+}
+
 // UnregisterRuntimeUpdatedChannel mocks base method.
 func (m *MockBlockAPI) UnregisterRuntimeUpdatedChannel(arg0 uint32) bool {
 	m.ctrl.T.Helper()
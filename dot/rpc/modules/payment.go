@@ -26,18 +26,20 @@ type PaymentQueryInfoResponse struct {
 
 // PaymentModule holds all the RPC implementation of polkadot payment rpc api
 type PaymentModule struct {
-	blockAPI BlockAPI
+	blockAPI   BlockAPI
+	storageAPI StorageAPI
 }
 
 // NewPaymentModule returns a pointer to PaymentModule
-func NewPaymentModule(blockAPI BlockAPI) *PaymentModule {
+func NewPaymentModule(blockAPI BlockAPI, storageAPI StorageAPI) *PaymentModule {
 	return &PaymentModule{
-		blockAPI: blockAPI,
+		blockAPI:   blockAPI,
+		storageAPI: storageAPI,
 	}
 }
 
 // QueryInfo query the known data about the fee of an extrinsic at the given block
-func (p *PaymentModule) QueryInfo(_ *http.Request, req *PaymentQueryInfoRequest, res *PaymentQueryInfoResponse) error {
+func (p *PaymentModule) QueryInfo(r *http.Request, req *PaymentQueryInfoRequest, res *PaymentQueryInfoResponse) error {
 	var hash common.Hash
 	if req.Hash == nil {
 		hash = p.blockAPI.BestBlockHash()
@@ -45,7 +47,7 @@ func (p *PaymentModule) QueryInfo(_ *http.Request, req *PaymentQueryInfoRequest,
 		hash = *req.Hash
 	}
 
-	r, err := p.blockAPI.GetRuntime(hash)
+	rt, err := runtimeAt(r, p.blockAPI, p.storageAPI, hash)
 	if err != nil {
 		return err
 	}
@@ -55,7 +57,7 @@ func (p *PaymentModule) QueryInfo(_ *http.Request, req *PaymentQueryInfoRequest,
 		return err
 	}
 
-	encQueryInfo, err := r.PaymentQueryInfo(ext)
+	encQueryInfo, err := rt.PaymentQueryInfo(ext)
 	if err != nil {
 		return err
 	}
@@ -13,7 +13,9 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 	"go.uber.org/mock/gomock"
 
 	"github.com/stretchr/testify/assert"
@@ -45,6 +47,16 @@ func TestPaymentModule_QueryInfo(t *testing.T) {
 
 	blockErrorAPIMock2.EXPECT().GetRuntime(testHash).Return(nil, errors.New("GetRuntime error"))
 
+	stateRoot := common.Hash{3}
+	trieState := storage.NewTrieState(inmemory.NewEmptyTrie())
+	storageAPIMock := mocks.NewMockStorageAPI(ctrl)
+	storageAPIMock.EXPECT().GetStateRootFromBlock(&testHash).Return(&stateRoot, nil).Times(5)
+	storageAPIMock.EXPECT().TrieState(&stateRoot).Return(trieState, nil).Times(5)
+
+	runtimeMock.EXPECT().SetContextStorage(trieState).Times(3)
+	runtimeMock2.EXPECT().SetContextStorage(trieState)
+	runtimeErrorMock.EXPECT().SetContextStorage(trieState)
+
 	runtimeMock.EXPECT().PaymentQueryInfo(common.MustHexToBytes("0x0000")).Return(nil, nil).Times(2)
 	runtimeMock2.EXPECT().PaymentQueryInfo(common.MustHexToBytes("0x0000")).Return(&types.RuntimeDispatchInfo{
 		Weight:     uint64(21),
@@ -54,7 +66,7 @@ func TestPaymentModule_QueryInfo(t *testing.T) {
 	runtimeErrorMock.EXPECT().PaymentQueryInfo(common.MustHexToBytes("0x0000")).
 		Return(nil, errors.New("PaymentQueryInfo error"))
 
-	paymentModule := NewPaymentModule(blockAPIMock)
+	paymentModule := NewPaymentModule(blockAPIMock, storageAPIMock)
 	type fields struct {
 		blockAPI BlockAPI
 	}
@@ -149,13 +161,14 @@ type args struct {
 					Hash: &testHash,
 				},
 			},
-			expErr: errors.New("GetRuntime error"),
+			expErr: errors.New("getting runtime: GetRuntime error"),
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &PaymentModule{
-				blockAPI: tt.fields.blockAPI,
+				blockAPI:   tt.fields.blockAPI,
+				storageAPI: storageAPIMock,
 			}
 			res := PaymentQueryInfoResponse{}
 			err := p.QueryInfo(tt.args.in0, tt.args.req, &res)
@@ -4,7 +4,12 @@
 package modules
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 )
 
 var (
@@ -12,6 +17,8 @@
 	UnsafeMethods = []string{
 		"system_addReservedPeer",
 		"system_removeReservedPeer",
+		"system_addLogFilter",
+		"system_resetLogFilter",
 		"author_submitExtrinsic",
 		"author_removeExtrinsic",
 		"author_insertKey",
@@ -19,6 +26,10 @@
 		"state_getPairs",
 		"state_getKeysPaged",
 		"state_queryStorage",
+		"state_traceBlock",
+		"engine_createBlock",
+		"engine_finalizeBlock",
+		"babe_epochAuthorship",
 	}
 
 	// AliasesMethods is a map that links the original methods to their aliases
@@ -63,3 +74,34 @@ func IsUnsafe(name string) bool {
 
 	return false
 }
+
+// requestContext returns the context of the request, which is canceled once the client disconnects.
+func requestContext(r *http.Request) context.Context {
+	if r == nil {
+		return context.Background()
+	}
+	return r.Context()
+}
+
+// runtimeAt returns the runtime of the block executing its calls on the state of the block,
+// with the execution limits of the RPC calls. The calls are canceled once the client disconnects.
+func runtimeAt(r *http.Request, blockAPI BlockAPI, storageAPI StorageAPI, hash common.Hash) (
+	runtime.Instance, error) {
+	rt, err := blockAPI.GetRuntime(hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting runtime: %w", err)
+	}
+
+	stateRoot, err := storageAPI.GetStateRootFromBlock(&hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting state root: %w", err)
+	}
+
+	trieState, err := storageAPI.TrieState(stateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("getting trie state: %w", err)
+	}
+
+	rt.SetContextStorage(trieState)
+	return runtime.WithLimits(requestContext(r), rt, runtime.RPCCall), nil
+}
@@ -9,8 +9,10 @@
 	"net/http"
 	"strings"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 )
 
@@ -82,6 +84,17 @@ type StateStorageQueryAtRequest struct {
 	At   common.Hash `json:"at"`
 }
 
+// StateTraceBlockRequest holds json fields
+type StateTraceBlockRequest struct {
+	Block common.Hash `json:"block" validate:"required"`
+	// Targets are the comma separated targets of the traced events, the events
+	// of all the targets are traced if it is empty.
+	Targets string `json:"targets"`
+	// StorageKeys are the comma separated hex prefixes of the traced storage keys,
+	// the accesses to all the storage keys are traced if it is empty.
+	StorageKeys string `json:"storageKeys"`
+}
+
 // StateStorageKeysQuery field to store storage keys
 type StateStorageKeysQuery [][]byte
 
@@ -133,6 +146,41 @@ type StorageChangeSetResponse struct {
 	Changes [][2]*string `json:"changes"`
 }
 
+// StateTraceBlockResponse holds the trace of a block execution
+type StateTraceBlockResponse struct {
+	BlockTrace BlockTrace `json:"blockTrace"`
+}
+
+// BlockTrace is the trace of a block execution, its events being grouped in
+// the spans of the block initialization, its extrinsics and its finalization.
+type BlockTrace struct {
+	BlockHash      string       `json:"blockHash"`
+	ParentHash     string       `json:"parentHash"`
+	TracingTargets string       `json:"tracingTargets"`
+	StorageKeys    string       `json:"storageKeys"`
+	Spans          []TraceSpan  `json:"spans"`
+	Events         []TraceEvent `json:"events"`
+}
+
+// TraceSpan is a phase of a block execution
+type TraceSpan struct {
+	ID             uint64  `json:"id"`
+	Name           string  `json:"name"`
+	ExtrinsicIndex *uint32 `json:"extrinsicIndex,omitempty"`
+}
+
+// TraceEvent is a storage access or a runtime log message traced during a block execution
+type TraceEvent struct {
+	Target   string         `json:"target"`
+	Data     TraceEventData `json:"data"`
+	ParentID uint64         `json:"parentId"`
+}
+
+// TraceEventData holds the values of a traced event
+type TraceEventData struct {
+	StringValues map[string]string `json:"stringValues"`
+}
+
 // KeyValueOption struct holds json fields
 type KeyValueOption []byte
 
@@ -246,7 +294,7 @@ func (sm *StateModule) GetPairs(_ *http.Request, req *StatePairRequest, res *Sta
 }
 
 // Call makes a call to the runtime.
-func (sm *StateModule) Call(_ *http.Request, req *StateCallRequest, res *StateCallResponse) error {
+func (sm *StateModule) Call(r *http.Request, req *StateCallRequest, res *StateCallResponse) error {
 	var blockHash common.Hash
 	if req.Block == nil {
 		blockHash = sm.blockAPI.BestBlockHash()
@@ -254,14 +302,14 @@ func (sm *StateModule) Call(_ *http.Request, req *StateCallRequest, res *StateCa
 		blockHash = *req.Block
 	}
 
-	rt, err := sm.blockAPI.GetRuntime(blockHash)
+	request, err := common.HexToBytes(req.Params)
 	if err != nil {
-		return fmt.Errorf("get runtime: %w", err)
+		return fmt.Errorf("convert hex to bytes: %w", err)
 	}
 
-	request, err := common.HexToBytes(req.Params)
+	rt, err := runtimeAt(r, sm.blockAPI, sm.storageAPI, blockHash)
 	if err != nil {
-		return fmt.Errorf("convert hex to bytes: %w", err)
+		return err
 	}
 
 	response, err := rt.Exec(req.Method, request)
@@ -555,6 +603,112 @@ func (sm *StateModule) QueryStorageAt(
 
 func stringPtr(s string) *string { return &s }
 
+// TraceBlock executes again the block on the state of its parent block, and returns its storage accesses
+// and runtime log messages grouped by extrinsic. The state of the parent block must still be stored by
+// the node, such that only the recent blocks are traced unless the node is an archive node.
+func (sm *StateModule) TraceBlock(r *http.Request, req *StateTraceBlockRequest, res *StateTraceBlockResponse) error {
+	var targets []string
+	for _, target := range strings.Split(req.Targets, ",") {
+		target = strings.TrimSpace(target)
+		if target != "" {
+			targets = append(targets, target)
+		}
+	}
+
+	var keyPrefixes [][]byte
+	for _, hexPrefix := range strings.Split(req.StorageKeys, ",") {
+		hexPrefix = strings.TrimSpace(hexPrefix)
+		if hexPrefix == "" {
+			continue
+		}
+
+		prefix, err := common.HexToBytes(hexPrefix)
+		if err != nil {
+			return fmt.Errorf("converting hex storage key %s to bytes: %w", hexPrefix, err)
+		}
+		keyPrefixes = append(keyPrefixes, prefix)
+	}
+
+	block, err := sm.blockAPI.GetBlockByHash(req.Block)
+	if err != nil {
+		return fmt.Errorf("getting block: %w", err)
+	}
+
+	parent, err := sm.blockAPI.GetHeader(block.Header.ParentHash)
+	if err != nil {
+		return fmt.Errorf("getting parent header: %w", err)
+	}
+
+	trieState, err := sm.storageAPI.TrieState(&parent.StateRoot)
+	if err != nil {
+		return fmt.Errorf("getting parent state, which is only kept for all the blocks by archive nodes: %w", err)
+	}
+
+	rt, err := sm.blockAPI.GetRuntime(block.Header.ParentHash)
+	if err != nil {
+		return fmt.Errorf("getting runtime: %w", err)
+	}
+
+	tracer := storage.NewTracer(targets, keyPrefixes)
+	trieState.SetTracer(tracer)
+	defer trieState.SetTracer(nil)
+
+	// the block is executed by a handle such that the parent runtime keeps its own storage
+	rt = runtime.WithLimits(requestContext(r), rt, runtime.RPCCall)
+	rt.SetContextStorage(trieState)
+	_, err = rt.ExecuteBlock(block)
+	if err != nil {
+		return fmt.Errorf("executing block: %w", err)
+	}
+
+	*res = StateTraceBlockResponse{
+		BlockTrace: newBlockTrace(req, block, tracer.Events()),
+	}
+	return nil
+}
+
+// newBlockTrace returns the trace of the block execution, with a span for the block initialization,
+// a span for each extrinsic and a span for the block finalization.
+func newBlockTrace(req *StateTraceBlockRequest, block *types.Block, events []storage.TraceEvent) BlockTrace {
+	extrinsics := uint64(len(block.Body))
+	finalizationSpanID := extrinsics + 2
+
+	spans := make([]TraceSpan, 0, extrinsics+2)
+	spans = append(spans, TraceSpan{ID: 1, Name: "initialize_block"})
+	for i := uint64(0); i < extrinsics; i++ {
+		extrinsicIndex := uint32(i)
+		spans = append(spans, TraceSpan{ID: i + 2, Name: "apply_extrinsic", ExtrinsicIndex: &extrinsicIndex})
+	}
+	spans = append(spans, TraceSpan{ID: finalizationSpanID, Name: "finalize_block"})
+
+	traceEvents := make([]TraceEvent, len(events))
+	for i, event := range events {
+		// the events following the last extrinsic are part of the block finalization
+		parentID := finalizationSpanID
+		switch {
+		case event.Phase.Kind == storage.InitializationPhase:
+			parentID = 1
+		case event.Phase.Kind == storage.ApplyExtrinsicPhase && uint64(event.Phase.ExtrinsicIndex) < extrinsics:
+			parentID = uint64(event.Phase.ExtrinsicIndex) + 2
+		}
+
+		traceEvents[i] = TraceEvent{
+			Target:   event.Target,
+			Data:     TraceEventData{StringValues: event.Values},
+			ParentID: parentID,
+		}
+	}
+
+	return BlockTrace{
+		BlockHash:      req.Block.String(),
+		ParentHash:     block.Header.ParentHash.String(),
+		TracingTargets: req.Targets,
+		StorageKeys:    req.StorageKeys,
+		Spans:          spans,
+		Events:         traceEvents,
+	}
+}
+
 // SubscribeRuntimeVersion initialised a runtime version subscription and returns the current version
 // See dot/rpc/subscription
 func (sm *StateModule) SubscribeRuntimeVersion(
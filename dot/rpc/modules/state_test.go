@@ -16,6 +16,7 @@
 package modules
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
@@ -28,7 +29,10 @@
 	"github.com/ChainSafe/gossamer/lib/blocktree"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	mocksruntime "github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -292,6 +296,9 @@ func TestCall(t *testing.T) {
 
 	mockNetworkAPI := mocks.NewMockNetworkAPI(ctrl)
 	mockStorageAPI := mocks.NewMockStorageAPI(ctrl)
+	stateRoot := common.Hash{3}
+	mockStorageAPI.EXPECT().GetStateRootFromBlock(&testHash).Return(&stateRoot, nil)
+	mockStorageAPI.EXPECT().TrieState(&stateRoot).Return(storage.NewTrieState(inmemory_trie.NewEmptyTrie()), nil)
 	mockBlockAPI := mocks.NewMockBlockAPI(ctrl)
 	mockBlockAPI.EXPECT().BestBlockHash().Return(testHash)
 	mockBlockAPI.EXPECT().GetRuntime(testHash).Return(rt, nil)
@@ -1281,3 +1288,180 @@ type fields struct {
 		})
 	}
 }
+
+func TestStateModuleTraceBlock(t *testing.T) {
+	t.Parallel()
+	errTest := errors.New("test error")
+
+	parentHeader := &types.Header{Number: 1, StateRoot: common.Hash{1}}
+	block := &types.Block{
+		Header: types.Header{Number: 2, ParentHash: parentHeader.Hash()},
+		Body:   types.Body{{1, 2, 3}},
+	}
+	blockHash := common.Hash{2}
+
+	type fields struct {
+		storageAPIBuilder func(ctrl *gomock.Controller, trieState *storage.TrieState) *MockStorageAPI
+		blockAPIBuilder   func(ctrl *gomock.Controller, trieState *storage.TrieState) *MockBlockAPI
+	}
+
+	tests := map[string]struct {
+		fields           fields
+		request          *StateTraceBlockRequest
+		expectedError    error
+		expectedResponse StateTraceBlockResponse
+	}{
+		"invalid_storage_key": {
+			fields: fields{
+				storageAPIBuilder: func(ctrl *gomock.Controller, _ *storage.TrieState) *MockStorageAPI {
+					return NewMockStorageAPI(ctrl)
+				},
+				blockAPIBuilder: func(ctrl *gomock.Controller, _ *storage.TrieState) *MockBlockAPI {
+					return NewMockBlockAPI(ctrl)
+				},
+			},
+			request: &StateTraceBlockRequest{
+				Block:       blockHash,
+				StorageKeys: "0x01,xyz",
+			},
+			expectedError: errors.New("converting hex storage key xyz to bytes: " +
+				"could not byteify non 0x prefixed string: xyz"),
+		},
+		"block_not_found": {
+			fields: fields{
+				storageAPIBuilder: func(ctrl *gomock.Controller, _ *storage.TrieState) *MockStorageAPI {
+					return NewMockStorageAPI(ctrl)
+				},
+				blockAPIBuilder: func(ctrl *gomock.Controller, _ *storage.TrieState) *MockBlockAPI {
+					mockBlockAPI := NewMockBlockAPI(ctrl)
+					mockBlockAPI.EXPECT().GetBlockByHash(blockHash).Return(nil, errTest)
+					return mockBlockAPI
+				},
+			},
+			request:       &StateTraceBlockRequest{Block: blockHash},
+			expectedError: errors.New("getting block: test error"),
+		},
+		"parent_state_not_found": {
+			fields: fields{
+				storageAPIBuilder: func(ctrl *gomock.Controller, _ *storage.TrieState) *MockStorageAPI {
+					mockStorageAPI := NewMockStorageAPI(ctrl)
+					mockStorageAPI.EXPECT().TrieState(&common.Hash{1}).Return(nil, errTest)
+					return mockStorageAPI
+				},
+				blockAPIBuilder: func(ctrl *gomock.Controller, _ *storage.TrieState) *MockBlockAPI {
+					mockBlockAPI := NewMockBlockAPI(ctrl)
+					mockBlockAPI.EXPECT().GetBlockByHash(blockHash).Return(block, nil)
+					mockBlockAPI.EXPECT().GetHeader(block.Header.ParentHash).Return(parentHeader, nil)
+					return mockBlockAPI
+				},
+			},
+			request: &StateTraceBlockRequest{Block: blockHash},
+			expectedError: errors.New("getting parent state, which is only kept " +
+				"for all the blocks by archive nodes: test error"),
+		},
+		"traced": {
+			fields: fields{
+				storageAPIBuilder: func(ctrl *gomock.Controller, trieState *storage.TrieState) *MockStorageAPI {
+					mockStorageAPI := NewMockStorageAPI(ctrl)
+					mockStorageAPI.EXPECT().TrieState(&common.Hash{1}).Return(trieState, nil)
+					return mockStorageAPI
+				},
+				blockAPIBuilder: func(ctrl *gomock.Controller, trieState *storage.TrieState) *MockBlockAPI {
+					mockInstance := mocksruntime.NewMockInstance(ctrl)
+					mockInstance.EXPECT().SetContextStorage(trieState)
+					mockInstance.EXPECT().ExecuteBlock(block).DoAndReturn(func(*types.Block) ([]byte, error) {
+						// executes the block as the runtime, which stores the extrinsic index
+						trieState.StartTransaction()
+						_ = trieState.Put([]byte{1, 1}, []byte{1})
+						_ = trieState.Put([]byte(":extrinsic_index"), []byte{0, 0, 0, 0})
+						_ = trieState.Get([]byte{1, 1})
+						_ = trieState.Put([]byte{2, 2}, []byte{2})
+						_ = trieState.Put([]byte(":extrinsic_index"), []byte{1, 0, 0, 0})
+						_ = trieState.Delete([]byte(":extrinsic_index"))
+						_ = trieState.Delete([]byte{1, 1})
+						return nil, nil
+					})
+
+					mockBlockAPI := NewMockBlockAPI(ctrl)
+					mockBlockAPI.EXPECT().GetBlockByHash(blockHash).Return(block, nil)
+					mockBlockAPI.EXPECT().GetHeader(block.Header.ParentHash).Return(parentHeader, nil)
+					// the parent runtime storage is left untouched, only its handle executes the block
+					parentInstance := limitedInstance{
+						Instance: mocksruntime.NewMockInstance(ctrl),
+						handle:   mockInstance,
+					}
+					mockBlockAPI.EXPECT().GetRuntime(block.Header.ParentHash).Return(parentInstance, nil)
+					return mockBlockAPI
+				},
+			},
+			request: &StateTraceBlockRequest{
+				Block:       blockHash,
+				Targets:     "state",
+				StorageKeys: "0x01",
+			},
+			expectedResponse: StateTraceBlockResponse{
+				BlockTrace: BlockTrace{
+					BlockHash:      blockHash.String(),
+					ParentHash:     parentHeader.Hash().String(),
+					TracingTargets: "state",
+					StorageKeys:    "0x01",
+					Spans: []TraceSpan{
+						{ID: 1, Name: "initialize_block"},
+						{ID: 2, Name: "apply_extrinsic", ExtrinsicIndex: new(uint32)},
+						{ID: 3, Name: "finalize_block"},
+					},
+					Events: []TraceEvent{
+						{
+							Target:   "state",
+							Data:     TraceEventData{StringValues: map[string]string{"method": "Put", "key": "0x0101", "value": "0x01"}},
+							ParentID: 1,
+						},
+						{
+							Target:   "state",
+							Data:     TraceEventData{StringValues: map[string]string{"method": "Get", "key": "0x0101", "value": "0x01"}},
+							ParentID: 2,
+						},
+						{
+							Target:   "state",
+							Data:     TraceEventData{StringValues: map[string]string{"method": "Delete", "key": "0x0101"}},
+							ParentID: 3,
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			trieState := storage.NewTrieState(inmemory_trie.NewEmptyTrie())
+			sm := &StateModule{
+				storageAPI: tt.fields.storageAPIBuilder(ctrl, trieState),
+				blockAPI:   tt.fields.blockAPIBuilder(ctrl, trieState),
+			}
+
+			response := StateTraceBlockResponse{}
+			err := sm.TraceBlock(nil, tt.request, &response)
+			if tt.expectedError != nil {
+				assert.EqualError(t, err, tt.expectedError.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Nil(t, trieState.Tracer())
+			}
+			assert.Equal(t, tt.expectedResponse, response)
+		})
+	}
+}
+
+// limitedInstance is a runtime instance returning the handle for its limited calls.
+type limitedInstance struct {
+	runtime.Instance
+	handle runtime.Instance
+}
+
+func (l limitedInstance) WithLimits(context.Context, runtime.CallClass) runtime.Instance {
+	return l.handle
+}
@@ -289,3 +289,18 @@ func (sm *SystemModule) RemoveReservedPeer(r *http.Request, req *StringRequest,
 
 	return sm.networkAPI.RemoveReservedPeers(req.String)
 }
+
+// AddLogFilter adds the comma separated 'target=level' directives to the runtime log filter,
+// such as 'runtime::staking=debug'. The directives replace those of the same targets.
+func (sm *SystemModule) AddLogFilter(r *http.Request, req *StringRequest, res *[]byte) error {
+	if strings.TrimSpace(req.String) == "" {
+		return errors.New("cannot add an empty log filter")
+	}
+
+	return sm.systemAPI.AddLogFilter(req.String)
+}
+
+// ResetLogFilter restores the runtime log filter to the log levels of the node configuration
+func (sm *SystemModule) ResetLogFilter(r *http.Request, req *EmptyRequest, res *[]byte) error {
+	return sm.systemAPI.ResetLogFilter()
+}
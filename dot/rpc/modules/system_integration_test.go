@@ -249,7 +249,8 @@ func TestSystemModule_AccountNextIndex_StoragePending(t *testing.T) {
 		Validity:  new(transaction.Validity),
 	}
 	expectedPending := U64Response(uint64(4))
-	sys.txStateAPI.(*state.TransactionState).AddToPool(vtx)
+	_, err = sys.txStateAPI.(*state.TransactionState).AddToPool(vtx)
+	require.NoError(t, err)
 
 	err = sys.AccountNextIndex(nil, &req, res)
 	require.NoError(t, err)
@@ -286,9 +287,10 @@ func TestSystemModule_AccountNextIndex_Pending(t *testing.T) {
 		Validity:  new(transaction.Validity),
 	}
 	expectedPending := U64Response(uint64(4))
-	sys.txStateAPI.(*state.TransactionState).AddToPool(vtx)
+	_, err := sys.txStateAPI.(*state.TransactionState).AddToPool(vtx)
+	require.NoError(t, err)
 
-	err := sys.AccountNextIndex(nil, &req, res)
+	err = sys.AccountNextIndex(nil, &req, res)
 	require.NoError(t, err)
 	require.Equal(t, expectedPending, *res)
 }
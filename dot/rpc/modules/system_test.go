@@ -644,3 +644,77 @@ type args struct {
 		})
 	}
 }
+
+func TestSystemModule_AddLogFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockSystemAPI := mocks.NewMockSystemAPI(ctrl)
+	mockSystemAPI.EXPECT().AddLogFilter("runtime::staking=debug").Return(nil)
+
+	mockSystemAPIErr := mocks.NewMockSystemAPI(ctrl)
+	mockSystemAPIErr.EXPECT().AddLogFilter("runtime::staking=verbose").
+		Return(errors.New("invalid log directive"))
+
+	type args struct {
+		r   *http.Request
+		req *StringRequest
+	}
+	tests := []struct {
+		name      string
+		sysModule *SystemModule
+		args      args
+		expErr    error
+		exp       []byte
+	}{
+		{
+			name:      "OK",
+			sysModule: NewSystemModule(nil, mockSystemAPI, nil, nil, nil, nil, nil),
+			args: args{
+				req: &StringRequest{"runtime::staking=debug"},
+			},
+			exp: []byte(nil),
+		},
+		{
+			name:      "AddLogFilter Error",
+			sysModule: NewSystemModule(nil, mockSystemAPIErr, nil, nil, nil, nil, nil),
+			args: args{
+				req: &StringRequest{"runtime::staking=verbose"},
+			},
+			expErr: errors.New("invalid log directive"),
+		},
+		{
+			name:      "Empty StringRequest Error",
+			sysModule: NewSystemModule(nil, mockSystemAPI, nil, nil, nil, nil, nil),
+			args: args{
+				req: &StringRequest{" "},
+			},
+			expErr: errors.New("cannot add an empty log filter"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := tt.sysModule
+			res := []byte(nil)
+			err := sm.AddLogFilter(tt.args.r, tt.args.req, &res)
+			if tt.expErr != nil {
+				assert.EqualError(t, err, tt.expErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
+func TestSystemModule_ResetLogFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockSystemAPI := mocks.NewMockSystemAPI(ctrl)
+	mockSystemAPI.EXPECT().ResetLogFilter().Return(nil)
+
+	sm := NewSystemModule(nil, mockSystemAPI, nil, nil, nil, nil, nil)
+	res := []byte(nil)
+	err := sm.ResetLogFilter(nil, &EmptyRequest{}, &res)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(nil), res)
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// TransactionStopRequest is the request to stop broadcasting a transaction
+type TransactionStopRequest struct {
+	OperationID string
+}
+
+// TransactionModule holds the RPC implementation of the transaction_v1 methods
+// of the new JSON-RPC interface.
+type TransactionModule struct {
+	logger  *log.Logger
+	coreAPI CoreAPI
+
+	mutex           sync.Mutex
+	nextOperationID uint64
+	operations      map[string]types.Extrinsic
+}
+
+// NewTransactionModule returns a pointer to TransactionModule
+func NewTransactionModule(logger *log.Logger, coreAPI CoreAPI) *TransactionModule {
+	logger = logger.New(log.AddContext("service", "RPC"), log.AddContext("module", "transaction"))
+	return &TransactionModule{
+		logger:     logger,
+		coreAPI:    coreAPI,
+		operations: make(map[string]types.Extrinsic),
+	}
+}
+
+// V1Broadcast validates the given transaction and gossips it to the peers, returning the
+// identifier of the broadcast operation. As specified, the operation identifier is returned
+// even if the transaction is invalid, in which case it is not broadcasted.
+func (tm *TransactionModule) V1Broadcast(_ *http.Request, req *Extrinsic, res *string) error {
+	extBytes, err := common.HexToBytes(req.Data)
+	if err != nil {
+		return fmt.Errorf("decoding transaction: %w", err)
+	}
+	ext := types.Extrinsic(extBytes)
+
+	tm.mutex.Lock()
+	operationID := strconv.FormatUint(tm.nextOperationID, 10)
+	tm.nextOperationID++
+	tm.operations[operationID] = ext
+	tm.mutex.Unlock()
+
+	err = tm.coreAPI.HandleSubmittedExtrinsic(ext)
+	if err != nil {
+		tm.logger.Debugf("cannot broadcast transaction of operation %s: %s", operationID, err)
+	}
+
+	*res = operationID
+	return nil
+}
+
+// V1Stop stops the broadcast operation with the given identifier. The transaction
+// might still be included in a block if it was already broadcasted.
+func (tm *TransactionModule) V1Stop(_ *http.Request, req *TransactionStopRequest, _ *interface{}) error {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+
+	_, ok := tm.operations[req.OperationID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrInvalidOperationID, req.OperationID)
+	}
+	delete(tm.operations, req.OperationID)
+	return nil
+}
+
+// TransactionWatchModule holds the RPC method names of the transactionWatch_v1 subscription,
+// which is handled by the websocket handler.
+type TransactionWatchModule struct{}
+
+// NewTransactionWatchModule returns a pointer to TransactionWatchModule
+func NewTransactionWatchModule() *TransactionWatchModule {
+	return &TransactionWatchModule{}
+}
+
+// V1SubmitAndWatch handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*TransactionWatchModule) V1SubmitAndWatch(_ *http.Request, _ *Extrinsic, _ *string) error {
+	return ErrSubscriptionTransport
+}
+
+// V1Unwatch handled by websocket handler, but this func should remain
+// here so it's added to rpc_methods list
+func (*TransactionWatchModule) V1Unwatch(_ *http.Request, _ *StringRequest, _ *interface{}) error {
+	return ErrSubscriptionTransport
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package modules
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransactionModule_V1Broadcast(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	coreAPI := mocks.NewMockCoreAPI(ctrl)
+	module := NewTransactionModule(log.New(log.SetWriter(io.Discard)), coreAPI)
+
+	err := module.V1Broadcast(nil, &Extrinsic{Data: "0xzz"}, new(string))
+	assert.ErrorContains(t, err, "decoding transaction: ")
+
+	coreAPI.EXPECT().HandleSubmittedExtrinsic(types.Extrinsic{1, 2}).Return(nil)
+	var operationID string
+	err = module.V1Broadcast(nil, &Extrinsic{Data: "0x0102"}, &operationID)
+	require.NoError(t, err)
+	assert.Equal(t, "0", operationID)
+
+	// the operation id is returned even if the transaction is invalid
+	coreAPI.EXPECT().HandleSubmittedExtrinsic(types.Extrinsic{3}).Return(errors.New("invalid"))
+	err = module.V1Broadcast(nil, &Extrinsic{Data: "0x03"}, &operationID)
+	require.NoError(t, err)
+	assert.Equal(t, "1", operationID)
+}
+
+func TestTransactionModule_V1Stop(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	coreAPI := mocks.NewMockCoreAPI(ctrl)
+	coreAPI.EXPECT().HandleSubmittedExtrinsic(types.Extrinsic{1}).Return(nil)
+	module := NewTransactionModule(log.New(log.SetWriter(io.Discard)), coreAPI)
+
+	var operationID string
+	err := module.V1Broadcast(nil, &Extrinsic{Data: "0x01"}, &operationID)
+	require.NoError(t, err)
+
+	err = module.V1Stop(nil, &TransactionStopRequest{OperationID: operationID}, nil)
+	assert.NoError(t, err)
+
+	err = module.V1Stop(nil, &TransactionStopRequest{OperationID: operationID}, nil)
+	assert.ErrorIs(t, err, ErrInvalidOperationID)
+	assert.EqualError(t, err, "invalid operation id: 0")
+}
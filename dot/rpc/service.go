@@ -7,6 +7,7 @@
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -75,11 +76,27 @@ func (s *Service) BuildMethodNames(rcvr interface{}, name string) {
 			continue
 		}
 
-		s.rpcMethods = append(s.rpcMethods,
-			fmt.Sprintf("%s_%s%s", name, strings.ToLower(string(method.Name[0])), method.Name[1:]))
+		s.rpcMethods = append(s.rpcMethods, rpcMethodName(name, method.Name))
 	}
 }
 
+// versionedMethodRegex matches the Go method names of versioned RPC methods, such as V1Follow.
+var versionedMethodRegex = regexp.MustCompile(`^V([0-9]+)([A-Z].*)$`)
+
+// rpcMethodName returns the RPC method name of the given module name and Go method name,
+// for example chain_getBlock for GetBlock and chainHead_v1_follow for V1Follow.
+func rpcMethodName(name, methodName string) string {
+	lowerFirst := func(s string) string {
+		return strings.ToLower(s[:1]) + s[1:]
+	}
+
+	matches := versionedMethodRegex.FindStringSubmatch(methodName)
+	if matches != nil {
+		return fmt.Sprintf("%s_v%s_%s", name, matches[1], lowerFirst(matches[2]))
+	}
+	return fmt.Sprintf("%s_%s", name, lowerFirst(methodName))
+}
+
 // isExported returns true of a string is an exported (upper case) name.
 func isExported(name string) bool {
 	r, _ := utf8.DecodeRuneInString(name)
@@ -23,7 +23,7 @@ func TestNewService(t *testing.T) {
 }
 
 func TestService_Methods(t *testing.T) {
-	qtySystemMethods := 15
+	qtySystemMethods := 17
 	qtyRPCMethods := 1
 	qtyAuthorMethods := 8
 
@@ -44,6 +44,42 @@ func TestService_Methods(t *testing.T) {
 	require.Equal(t, qtySystemMethods+qtyRPCMethods+qtyAuthorMethods, len(m))
 }
 
+func Test_rpcMethodName(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		name       string
+		methodName string
+		expected   string
+	}{
+		"legacy_method": {
+			name:       "chain",
+			methodName: "GetBlockHash",
+			expected:   "chain_getBlockHash",
+		},
+		"versioned_method": {
+			name:       "chainHead",
+			methodName: "V1Follow",
+			expected:   "chainHead_v1_follow",
+		},
+		"versioned_camel_case_method": {
+			name:       "transactionWatch",
+			methodName: "V1SubmitAndWatch",
+			expected:   "transactionWatch_v1_submitAndWatch",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			methodName := rpcMethodName(testCase.name, testCase.methodName)
+			require.Equal(t, testCase.expected, methodName)
+		})
+	}
+}
+
 type mockService struct{}
 
 // MockServiceArrayRequest must be exported for ReadArray or tests will fail.
@@ -0,0 +1,1039 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+const (
+	chainHeadFollowEventMethod = "chainHead_v1_followEvent"
+
+	// maxChainHeadPinnedBlocks is the number of blocks a chainHead_v1_follow subscription
+	// can keep pinned before the subscription is stopped
+	maxChainHeadPinnedBlocks = 512
+)
+
+// storage query types of chainHead_v1_storage
+const (
+	storageQueryValue             = "value"
+	storageQueryHash              = "hash"
+	storageQueryDescendantsValues = "descendantsValues"
+	storageQueryDescendantsHashes = "descendantsHashes"
+)
+
+var (
+	errInvalidFollowSubscription = errors.New("invalid followSubscription")
+	errBlockNotPinned            = errors.New("block hash not pinned")
+	errTooManyPinnedBlocks       = errors.New("too many pinned blocks")
+	errUnknownStorageQueryType   = errors.New("unknown storage query type")
+	errNoRuntime                 = errors.New("no runtime found for block")
+)
+
+type chainHeadRuntimeSpec struct {
+	SpecName           string            `json:"specName"`
+	ImplName           string            `json:"implName"`
+	SpecVersion        uint32            `json:"specVersion"`
+	ImplVersion        uint32            `json:"implVersion"`
+	TransactionVersion uint32            `json:"transactionVersion"`
+	APIs               map[string]uint32 `json:"apis"`
+}
+
+type chainHeadRuntimeEvent struct {
+	Type  string                `json:"type"`
+	Spec  *chainHeadRuntimeSpec `json:"spec,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+func newChainHeadRuntimeEvent(version runtime.Version) *chainHeadRuntimeEvent {
+	apis := make(map[string]uint32, len(version.APIItems))
+	for _, apiItem := range version.APIItems {
+		apis["0x"+hex.EncodeToString(apiItem.Name[:])] = apiItem.Ver
+	}
+
+	return &chainHeadRuntimeEvent{
+		Type: "valid",
+		Spec: &chainHeadRuntimeSpec{
+			SpecName:           string(version.SpecName),
+			ImplName:           string(version.ImplName),
+			SpecVersion:        version.SpecVersion,
+			ImplVersion:        version.ImplVersion,
+			TransactionVersion: version.TransactionVersion,
+			APIs:               apis,
+		},
+	}
+}
+
+func newChainHeadInvalidRuntimeEvent(err error) *chainHeadRuntimeEvent {
+	return &chainHeadRuntimeEvent{
+		Type:  "invalid",
+		Error: err.Error(),
+	}
+}
+
+type chainHeadInitializedEvent struct {
+	Event                 string                 `json:"event"`
+	FinalizedBlockHashes  []string               `json:"finalizedBlockHashes"`
+	FinalizedBlockRuntime *chainHeadRuntimeEvent `json:"finalizedBlockRuntime,omitempty"`
+}
+
+type chainHeadNewBlockEvent struct {
+	Event           string                 `json:"event"`
+	BlockHash       string                 `json:"blockHash"`
+	ParentBlockHash string                 `json:"parentBlockHash"`
+	NewRuntime      *chainHeadRuntimeEvent `json:"newRuntime,omitempty"`
+}
+
+type chainHeadBestBlockChangedEvent struct {
+	Event         string `json:"event"`
+	BestBlockHash string `json:"bestBlockHash"`
+}
+
+type chainHeadFinalizedEvent struct {
+	Event                string   `json:"event"`
+	FinalizedBlockHashes []string `json:"finalizedBlockHashes"`
+	PrunedBlockHashes    []string `json:"prunedBlockHashes"`
+}
+
+type chainHeadOperationStarted struct {
+	Result         string `json:"result"`
+	OperationID    string `json:"operationId"`
+	DiscardedItems *uint  `json:"discardedItems,omitempty"`
+}
+
+type chainHeadOperationEvent struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type chainHeadOperationBodyDone struct {
+	Event       string   `json:"event"`
+	OperationID string   `json:"operationId"`
+	Value       []string `json:"value"`
+}
+
+type chainHeadOperationCallDone struct {
+	Event       string `json:"event"`
+	OperationID string `json:"operationId"`
+	Output      string `json:"output"`
+}
+
+type chainHeadStorageItem struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+	Hash  string `json:"hash,omitempty"`
+}
+
+type chainHeadOperationStorageItems struct {
+	Event       string                 `json:"event"`
+	OperationID string                 `json:"operationId"`
+	Items       []chainHeadStorageItem `json:"items"`
+}
+
+type chainHeadStorageQuery struct {
+	key       []byte
+	queryType string
+}
+
+// ChainHeadFollowListener reports the imported, best and finalised blocks to a chainHead_v1_follow
+// subscription and keeps the reported blocks pinned until they are unpinned
+type ChainHeadFollowListener struct {
+	wsconn        *WSConn
+	subID         uint32
+	withRuntime   bool
+	importedChan  chan *types.Block
+	finalizedChan chan *types.FinalisationInfo
+
+	mu     sync.Mutex
+	pinned map[common.Hash]struct{}
+	// unfinalized holds the parent hashes of the reported blocks which are not finalised yet
+	unfinalized     map[common.Hash]common.Hash
+	finalizedNumber uint
+	bestHash        common.Hash
+	nextOperationID uint64
+
+	done          chan struct{}
+	cancel        chan struct{}
+	cancelTimeout time.Duration
+}
+
+func (c *WSConn) initChainHeadFollow(reqID float64, params interface{}) (Listener, error) {
+	p, err := parseParams(params, 1, 1)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return nil, err
+	}
+
+	withRuntime, ok := p[0].(bool)
+	if !ok {
+		err = fmt.Errorf("%w: %T, expected type bool", errUnexpectedType, p[0])
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return nil, err
+	}
+
+	if c.BlockAPI == nil {
+		c.safeSendError(reqID, nil, errBlockAPINotSet.Error())
+		return nil, errBlockAPINotSet
+	}
+
+	listener := &ChainHeadFollowListener{
+		wsconn:        c,
+		withRuntime:   withRuntime,
+		importedChan:  c.BlockAPI.GetImportedBlockNotifierChannel(),
+		finalizedChan: c.BlockAPI.GetFinalisedNotifierChannel(),
+		pinned:        make(map[common.Hash]struct{}),
+		unfinalized:   make(map[common.Hash]common.Hash),
+		cancel:        make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
+		cancelTimeout: defaultCancelTimeout,
+	}
+
+	c.mu.Lock()
+	listener.subID = atomic.AddUint32(&c.qtyListeners, 1)
+	c.Subscriptions[listener.subID] = listener
+	c.mu.Unlock()
+
+	c.safeSend(newV2SubscriptionResponseJSON(listener.subID, reqID))
+	return listener, nil
+}
+
+// Listen reports the current finalised block and its descendants, then listens for imported
+// and finalised blocks
+func (l *ChainHeadFollowListener) Listen() {
+	go func() {
+		defer func() {
+			l.wsconn.BlockAPI.FreeImportedBlockNotifierChannel(l.importedChan)
+			l.wsconn.BlockAPI.FreeFinalisedNotifierChannel(l.finalizedChan)
+			l.unpinAll()
+			close(l.done)
+		}()
+
+		err := l.initialise()
+		if err != nil {
+			logger.Warnf("failed to initialise chainHead follow subscription: %s", err)
+			l.stop()
+			return
+		}
+
+		for {
+			select {
+			case <-l.cancel:
+				return
+			case block, ok := <-l.importedChan:
+				if !ok {
+					return
+				}
+
+				if block == nil {
+					continue
+				}
+				err = l.handleImportedBlock(&block.Header)
+			case info, ok := <-l.finalizedChan:
+				if !ok {
+					return
+				}
+
+				if info == nil {
+					continue
+				}
+				err = l.handleFinalisedBlock(&info.Header)
+			}
+
+			if err != nil {
+				logger.Warnf("stopping chainHead follow subscription: %s", err)
+				l.stop()
+				return
+			}
+		}
+	}()
+}
+
+// Stop to cancel the running goroutines to this listener
+func (l *ChainHeadFollowListener) Stop() error {
+	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
+}
+
+// stop removes the subscription and notifies the client that no more events will be sent
+func (l *ChainHeadFollowListener) stop() {
+	l.wsconn.removeSubscription(l.subID)
+	l.send(chainHeadOperationEvent{Event: "stop"})
+}
+
+func (l *ChainHeadFollowListener) send(event interface{}) {
+	l.wsconn.safeSend(newV2SubscriptionResponse(chainHeadFollowEventMethod, l.subID, event))
+}
+
+func (l *ChainHeadFollowListener) initialise() error {
+	blockAPI := l.wsconn.BlockAPI
+
+	finalizedHash, err := blockAPI.GetHighestFinalisedHash()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised hash: %w", err)
+	}
+
+	finalizedHeader, err := blockAPI.GetHeader(finalizedHash)
+	if err != nil {
+		return fmt.Errorf("getting finalised header: %w", err)
+	}
+
+	l.mu.Lock()
+	err = l.pin(finalizedHash)
+	l.finalizedNumber = finalizedHeader.Number
+	l.bestHash = finalizedHash
+	l.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	event := chainHeadInitializedEvent{
+		Event:                "initialized",
+		FinalizedBlockHashes: []string{finalizedHash.String()},
+	}
+	if l.withRuntime {
+		event.FinalizedBlockRuntime = l.blockRuntime(finalizedHash)
+	}
+	l.send(event)
+
+	bestHash := blockAPI.BestBlockHash()
+	hashes, err := blockAPI.RangeInMemory(finalizedHash, bestHash)
+	if err != nil {
+		return fmt.Errorf("getting blocks from finalised to best block: %w", err)
+	}
+
+	for _, hash := range hashes {
+		header, err := blockAPI.GetHeader(hash)
+		if err != nil {
+			return fmt.Errorf("getting header: %w", err)
+		}
+
+		err = l.reportNewBlock(header)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.reportBestBlock(bestHash)
+	return nil
+}
+
+func (l *ChainHeadFollowListener) handleImportedBlock(header *types.Header) error {
+	err := l.reportNewBlock(header)
+	if err != nil {
+		return err
+	}
+
+	l.reportBestBlock(l.wsconn.BlockAPI.BestBlockHash())
+	return nil
+}
+
+// reportNewBlock pins the block and sends a newBlock event for it, after reporting its
+// ancestors which were not reported yet
+func (l *ChainHeadFollowListener) reportNewBlock(header *types.Header) error {
+	hash := header.Hash()
+
+	l.mu.Lock()
+	_, reported := l.unfinalized[hash]
+	finalizedNumber := l.finalizedNumber
+	l.mu.Unlock()
+
+	if reported || header.Number <= finalizedNumber {
+		return nil
+	}
+
+	if header.Number-1 > finalizedNumber {
+		parent, err := l.wsconn.BlockAPI.GetHeader(header.ParentHash)
+		if err != nil {
+			return fmt.Errorf("getting parent header: %w", err)
+		}
+
+		err = l.reportNewBlock(parent)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	err := l.pin(hash)
+	if err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	l.unfinalized[hash] = header.ParentHash
+	l.mu.Unlock()
+
+	event := chainHeadNewBlockEvent{
+		Event:           "newBlock",
+		BlockHash:       hash.String(),
+		ParentBlockHash: header.ParentHash.String(),
+	}
+	if l.withRuntime {
+		event.NewRuntime = l.runtimeChange(header)
+	}
+	l.send(event)
+	return nil
+}
+
+// reportBestBlock sends a bestBlockChanged event if the best block changed and was reported
+func (l *ChainHeadFollowListener) reportBestBlock(bestHash common.Hash) {
+	l.mu.Lock()
+	_, reported := l.unfinalized[bestHash]
+	changed := reported && bestHash != l.bestHash
+	if changed {
+		l.bestHash = bestHash
+	}
+	l.mu.Unlock()
+
+	if changed {
+		l.send(chainHeadBestBlockChangedEvent{
+			Event:         "bestBlockChanged",
+			BestBlockHash: bestHash.String(),
+		})
+	}
+}
+
+func (l *ChainHeadFollowListener) handleFinalisedBlock(header *types.Header) error {
+	l.mu.Lock()
+	finalizedNumber := l.finalizedNumber
+	l.mu.Unlock()
+
+	if header.Number <= finalizedNumber {
+		return nil
+	}
+
+	// the finalised blocks are reported from the oldest to the newest one
+	headers := make([]*types.Header, header.Number-finalizedNumber)
+	for i := len(headers) - 1; i >= 0; i-- {
+		headers[i] = header
+		if i == 0 {
+			break
+		}
+
+		var err error
+		header, err = l.wsconn.BlockAPI.GetHeader(header.ParentHash)
+		if err != nil {
+			return fmt.Errorf("getting finalised header: %w", err)
+		}
+	}
+
+	for _, header := range headers {
+		err := l.reportNewBlock(header)
+		if err != nil {
+			return err
+		}
+	}
+
+	newFinalized := headers[len(headers)-1]
+	finalizedHashes := make([]string, len(headers))
+
+	l.mu.Lock()
+	for i, header := range headers {
+		hash := header.Hash()
+		finalizedHashes[i] = hash.String()
+		delete(l.unfinalized, hash)
+	}
+
+	// the reported blocks which are not descendants of the new finalised block are pruned
+	newFinalizedHash := newFinalized.Hash()
+	var pruned []common.Hash
+	for hash := range l.unfinalized {
+		if !l.descendsFrom(hash, newFinalizedHash) {
+			pruned = append(pruned, hash)
+		}
+	}
+
+	prunedHashes := make([]string, len(pruned))
+	for i, hash := range pruned {
+		prunedHashes[i] = hash.String()
+		delete(l.unfinalized, hash)
+	}
+	l.finalizedNumber = newFinalized.Number
+	_, bestStillReported := l.unfinalized[l.bestHash]
+	bestIsFinalized := l.bestHash == newFinalizedHash
+	l.mu.Unlock()
+
+	if !bestStillReported && !bestIsFinalized {
+		l.reportBestBlock(l.wsconn.BlockAPI.BestBlockHash())
+	}
+
+	l.send(chainHeadFinalizedEvent{
+		Event:                "finalized",
+		FinalizedBlockHashes: finalizedHashes,
+		PrunedBlockHashes:    prunedHashes,
+	})
+	return nil
+}
+
+// descendsFrom returns true if the reported block descends from the given block.
+// It must be called with the lock held.
+func (l *ChainHeadFollowListener) descendsFrom(hash, ancestor common.Hash) bool {
+	for {
+		parent, ok := l.unfinalized[hash]
+		if !ok {
+			return false
+		}
+
+		if parent == ancestor {
+			return true
+		}
+		hash = parent
+	}
+}
+
+// blockRuntime returns the runtime event of the runtime of the given block
+func (l *ChainHeadFollowListener) blockRuntime(hash common.Hash) *chainHeadRuntimeEvent {
+	rt, err := l.wsconn.BlockAPI.GetRuntime(hash)
+	if err != nil {
+		return newChainHeadInvalidRuntimeEvent(err)
+	}
+
+	if rt == nil {
+		return newChainHeadInvalidRuntimeEvent(errNoRuntime)
+	}
+
+	version, err := rt.Version()
+	if err != nil {
+		return newChainHeadInvalidRuntimeEvent(err)
+	}
+	return newChainHeadRuntimeEvent(version)
+}
+
+// runtimeChange returns the runtime event of the new runtime of the block, or nil if the
+// block did not update the runtime. The runtime instance of an imported block might not be
+// stored yet, so the version is read from the code in the block state.
+func (l *ChainHeadFollowListener) runtimeChange(header *types.Header) *chainHeadRuntimeEvent {
+	updated := false
+	for _, item := range header.Digest {
+		value, err := item.Value()
+		if err != nil {
+			continue
+		}
+
+		if _, ok := value.(types.RuntimeEnvironmentUpdated); ok {
+			updated = true
+			break
+		}
+	}
+
+	if !updated {
+		return nil
+	}
+
+	if l.wsconn.StorageAPI == nil {
+		return newChainHeadInvalidRuntimeEvent(errStorageNotSet)
+	}
+
+	code, err := l.wsconn.StorageAPI.GetStorage(&header.StateRoot, common.CodeKey)
+	if err != nil {
+		return newChainHeadInvalidRuntimeEvent(err)
+	}
+
+	version, err := wazero_runtime.GetRuntimeVersion(code)
+	if err != nil {
+		return newChainHeadInvalidRuntimeEvent(err)
+	}
+	return newChainHeadRuntimeEvent(version)
+}
+
+func (l *ChainHeadFollowListener) isPinned(hash common.Hash) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.pinned[hash]
+	return ok
+}
+
+// pin pins the block in the block state, holding off its pruning, unless the subscription
+// already pinned too many blocks. It must be called with the lock held.
+func (l *ChainHeadFollowListener) pin(hash common.Hash) error {
+	if len(l.pinned) >= maxChainHeadPinnedBlocks {
+		return errTooManyPinnedBlocks
+	}
+
+	err := l.wsconn.BlockAPI.PinBlock(hash)
+	if err != nil {
+		return fmt.Errorf("pinning block %s: %w", hash, err)
+	}
+	l.pinned[hash] = struct{}{}
+	return nil
+}
+
+// unpin unpins the given blocks if all of them are pinned
+func (l *ChainHeadFollowListener) unpin(hashes []common.Hash) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, hash := range hashes {
+		if _, ok := l.pinned[hash]; !ok {
+			return fmt.Errorf("%w: %s", errBlockNotPinned, hash)
+		}
+	}
+
+	for _, hash := range hashes {
+		l.unpinBlock(hash)
+	}
+	return nil
+}
+
+// unpinAll unpins the blocks still pinned once the subscription is over
+func (l *ChainHeadFollowListener) unpinAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for hash := range l.pinned {
+		l.unpinBlock(hash)
+	}
+}
+
+// unpinBlock unpins the block in the block state. It must be called with the lock held.
+func (l *ChainHeadFollowListener) unpinBlock(hash common.Hash) {
+	delete(l.pinned, hash)
+	err := l.wsconn.BlockAPI.UnpinBlock(hash)
+	if err != nil {
+		logger.Warnf("failed to unpin block %s: %s", hash, err)
+	}
+}
+
+// startOperation responds to the request with a new operation id and runs the operation in
+// the background, which reports its outcome with follow events
+func (l *ChainHeadFollowListener) startOperation(reqID float64, discardedItems *uint,
+	operation func(operationID string)) {
+	operationID := strconv.FormatUint(atomic.AddUint64(&l.nextOperationID, 1), 10)
+
+	l.wsconn.safeSend(newResultResponseJSON(chainHeadOperationStarted{
+		Result:         "started",
+		OperationID:    operationID,
+		DiscardedItems: discardedItems,
+	}, reqID))
+
+	go operation(operationID)
+}
+
+func (l *ChainHeadFollowListener) sendOperationError(operationID string, err error) {
+	l.send(chainHeadOperationEvent{
+		Event:       "operationError",
+		OperationID: operationID,
+		Error:       err.Error(),
+	})
+}
+
+// removeSubscription removes the subscription with the given id and returns it
+func (c *WSConn) removeSubscription(subID uint32) (Listener, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	listener, ok := c.Subscriptions[subID]
+	if ok {
+		delete(c.Subscriptions, subID)
+	}
+	return listener, ok
+}
+
+func parseV2SubscriptionID(p interface{}) (uint32, error) {
+	s, err := parseStringParam(p)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, errCannotParseID
+	}
+	return uint32(id), nil
+}
+
+func (c *WSConn) getChainHeadFollowListener(p interface{}) (*ChainHeadFollowListener, error) {
+	subID, err := parseV2SubscriptionID(p)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	listener, ok := c.Subscriptions[subID].(*ChainHeadFollowListener)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errInvalidFollowSubscription, subID)
+	}
+	return listener, nil
+}
+
+// getPinnedBlock returns the follow subscription and block hash given as first params, making
+// sure the block is pinned by the subscription
+func (c *WSConn) getPinnedBlock(params []interface{}) (*ChainHeadFollowListener, common.Hash, error) {
+	listener, err := c.getChainHeadFollowListener(params[0])
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+
+	hash, err := parseHashParam(params[1])
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+
+	if !listener.isPinned(hash) {
+		return nil, common.Hash{}, fmt.Errorf("%w: %s", errBlockNotPinned, hash)
+	}
+	return listener, hash, nil
+}
+
+func (c *WSConn) chainHeadUnfollow(reqID float64, params interface{}) {
+	p, err := parseParams(params, 1, 1)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	subID, err := parseV2SubscriptionID(p[0])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	listener, ok := c.removeSubscription(subID)
+	if ok {
+		err = listener.Stop()
+		if err != nil {
+			logger.Warnf("failed to stop listener goroutine (method=%s): %s", chainHeadV1Unfollow, err)
+		}
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
+
+func (c *WSConn) chainHeadHeader(reqID float64, params interface{}) {
+	p, err := parseParams(params, 2, 2)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	_, hash, err := c.getPinnedBlock(p)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	header, err := c.BlockAPI.GetHeader(hash)
+	if err != nil {
+		c.safeSendError(reqID, nil, err.Error())
+		return
+	}
+
+	encodedHeader, err := scale.Marshal(*header)
+	if err != nil {
+		c.safeSendError(reqID, nil, err.Error())
+		return
+	}
+
+	c.safeSend(newResultResponseJSON(common.BytesToHex(encodedHeader), reqID))
+}
+
+func (c *WSConn) chainHeadBody(reqID float64, params interface{}) {
+	p, err := parseParams(params, 2, 2)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	listener, hash, err := c.getPinnedBlock(p)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	listener.startOperation(reqID, nil, func(operationID string) {
+		block, err := c.BlockAPI.GetBlockByHash(hash)
+		if err != nil {
+			listener.sendOperationError(operationID, err)
+			return
+		}
+
+		extrinsics := make([]string, len(block.Body))
+		for i, extrinsic := range block.Body {
+			extrinsics[i] = common.BytesToHex(extrinsic)
+		}
+
+		listener.send(chainHeadOperationBodyDone{
+			Event:       "operationBodyDone",
+			OperationID: operationID,
+			Value:       extrinsics,
+		})
+	})
+}
+
+func (c *WSConn) chainHeadCall(reqID float64, params interface{}) {
+	p, err := parseParams(params, 4, 4)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	listener, hash, err := c.getPinnedBlock(p)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	function, err := parseStringParam(p[2])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	callParameters, err := parseBytesParam(p[3])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	listener.startOperation(reqID, nil, func(operationID string) {
+		rt, err := c.BlockAPI.GetRuntime(hash)
+		if err != nil {
+			listener.sendOperationError(operationID, err)
+			return
+		}
+
+		if rt == nil {
+			listener.sendOperationError(operationID, errNoRuntime)
+			return
+		}
+
+		if c.StorageAPI == nil {
+			listener.sendOperationError(operationID, errStorageNotSet)
+			return
+		}
+
+		stateRoot, err := c.StorageAPI.GetStateRootFromBlock(&hash)
+		if err != nil {
+			listener.sendOperationError(operationID, err)
+			return
+		}
+
+		trieState, err := c.StorageAPI.TrieState(stateRoot)
+		if err != nil {
+			listener.sendOperationError(operationID, err)
+			return
+		}
+
+		rt.SetContextStorage(trieState)
+		rt = runtime.WithLimits(c.connContext(), rt, runtime.RPCCall)
+		output, err := rt.Exec(function, callParameters)
+		if err != nil {
+			listener.sendOperationError(operationID, err)
+			return
+		}
+
+		listener.send(chainHeadOperationCallDone{
+			Event:       "operationCallDone",
+			OperationID: operationID,
+			Output:      common.BytesToHex(output),
+		})
+	})
+}
+
+func (c *WSConn) chainHeadStorage(reqID float64, params interface{}) {
+	p, err := parseParams(params, 3, 4)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	if c.StorageAPI == nil {
+		c.safeSendError(reqID, nil, errStorageNotSet.Error())
+		return
+	}
+
+	listener, hash, err := c.getPinnedBlock(p)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	queries, err := parseStorageQueries(p[2])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	var childKey []byte
+	if len(p) == 4 && p[3] != nil {
+		childKey, err = parseBytesParam(p[3])
+		if err != nil {
+			c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+			return
+		}
+	}
+
+	discardedItems := uint(0)
+	listener.startOperation(reqID, &discardedItems, func(operationID string) {
+		items, err := c.queryStorage(hash, childKey, queries)
+		if err != nil {
+			listener.sendOperationError(operationID, err)
+			return
+		}
+
+		if len(items) > 0 {
+			listener.send(chainHeadOperationStorageItems{
+				Event:       "operationStorageItems",
+				OperationID: operationID,
+				Items:       items,
+			})
+		}
+
+		listener.send(chainHeadOperationEvent{
+			Event:       "operationStorageDone",
+			OperationID: operationID,
+		})
+	})
+}
+
+func parseStorageQueries(p interface{}) ([]chainHeadStorageQuery, error) {
+	items, ok := p.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %T, expected type []interface{}", errUnexpectedType, p)
+	}
+
+	queries := make([]chainHeadStorageQuery, len(items))
+	for i, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %T, expected type map[string]interface{}", errUnexpectedType, item)
+		}
+
+		key, err := parseBytesParam(fields["key"])
+		if err != nil {
+			return nil, fmt.Errorf("parsing storage query key: %w", err)
+		}
+
+		queryType, err := parseStringParam(fields["type"])
+		if err != nil {
+			return nil, fmt.Errorf("parsing storage query type: %w", err)
+		}
+
+		switch queryType {
+		case storageQueryValue, storageQueryHash, storageQueryDescendantsValues, storageQueryDescendantsHashes:
+		default:
+			return nil, fmt.Errorf("%w: %s", errUnknownStorageQueryType, queryType)
+		}
+
+		queries[i] = chainHeadStorageQuery{key: key, queryType: queryType}
+	}
+
+	return queries, nil
+}
+
+// queryStorage returns the storage items of the block state, or of its child trie if childKey is not nil
+func (c *WSConn) queryStorage(hash common.Hash, childKey []byte,
+	queries []chainHeadStorageQuery) ([]chainHeadStorageItem, error) {
+	root, err := c.StorageAPI.GetStateRootFromBlock(&hash)
+	if err != nil {
+		return nil, fmt.Errorf("getting state root: %w", err)
+	}
+
+	getStorage := func(key []byte) ([]byte, error) {
+		if childKey != nil {
+			return c.StorageAPI.GetStorageFromChild(root, childKey, key)
+		}
+		return c.StorageAPI.GetStorage(root, key)
+	}
+
+	getKeysWithPrefix := func(prefix []byte) ([][]byte, error) {
+		if childKey == nil {
+			return c.StorageAPI.GetKeysWithPrefix(root, prefix)
+		}
+
+		child, err := c.StorageAPI.GetStorageChild(root, childKey)
+		if err != nil || child == nil {
+			return nil, err
+		}
+		return child.GetKeysWithPrefix(prefix), nil
+	}
+
+	var items []chainHeadStorageItem
+	for _, query := range queries {
+		keys := [][]byte{query.key}
+		if query.queryType == storageQueryDescendantsValues || query.queryType == storageQueryDescendantsHashes {
+			keys, err = getKeysWithPrefix(query.key)
+			if err != nil {
+				return nil, fmt.Errorf("getting keys with prefix: %w", err)
+			}
+		}
+
+		for _, key := range keys {
+			value, err := getStorage(key)
+			if err != nil {
+				return nil, fmt.Errorf("getting storage: %w", err)
+			}
+
+			if value == nil {
+				continue
+			}
+
+			item := chainHeadStorageItem{Key: common.BytesToHex(key)}
+			switch query.queryType {
+			case storageQueryValue, storageQueryDescendantsValues:
+				item.Value = common.BytesToHex(value)
+			case storageQueryHash, storageQueryDescendantsHashes:
+				valueHash, err := common.Blake2bHash(value)
+				if err != nil {
+					return nil, fmt.Errorf("hashing storage value: %w", err)
+				}
+				item.Hash = valueHash.String()
+			}
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+func (c *WSConn) chainHeadUnpin(reqID float64, params interface{}) {
+	p, err := parseParams(params, 2, 2)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	listener, err := c.getChainHeadFollowListener(p[0])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	// the second param is either a hash or a list of hashes
+	hashParams, ok := p[1].([]interface{})
+	if !ok {
+		hashParams = []interface{}{p[1]}
+	}
+
+	hashes := make([]common.Hash, len(hashParams))
+	for i, hashParam := range hashParams {
+		hashes[i], err = parseHashParam(hashParam)
+		if err != nil {
+			c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+			return
+		}
+	}
+
+	err = listener.unpin(hashes)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+//go:build integration
+
+package subscription
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/rpc/modules/mocks"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func requireNextMessage(t *testing.T, c *websocket.Conn, expected string) {
+	t.Helper()
+
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.JSONEq(t, expected, string(msg))
+}
+
+func TestChainHeadFollowListener(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	defer cancel()
+
+	go wsconn.HandleConn()
+
+	genesisHeader := &types.Header{Number: 0, Digest: types.NewDigest()}
+	genesisHash := genesisHeader.Hash()
+	blockHeader := &types.Header{ParentHash: genesisHash, Number: 1, Digest: types.NewDigest()}
+	blockHash := blockHeader.Hash()
+
+	importedChan := make(chan *types.Block)
+	finalizedChan := make(chan *types.FinalisationInfo)
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetImportedBlockNotifierChannel().Return(importedChan)
+	blockAPI.EXPECT().GetFinalisedNotifierChannel().Return(finalizedChan)
+	blockAPI.EXPECT().GetHighestFinalisedHash().Return(genesisHash, nil)
+	blockAPI.EXPECT().GetHeader(genesisHash).Return(genesisHeader, nil)
+	blockAPI.EXPECT().PinBlock(genesisHash).Return(nil)
+	blockAPI.EXPECT().BestBlockHash().Return(blockHash).AnyTimes()
+	blockAPI.EXPECT().RangeInMemory(genesisHash, blockHash).Return([]common.Hash{genesisHash, blockHash}, nil)
+	blockAPI.EXPECT().GetHeader(genesisHash).Return(genesisHeader, nil)
+	blockAPI.EXPECT().GetHeader(blockHash).Return(blockHeader, nil)
+	blockAPI.EXPECT().PinBlock(blockHash).Return(nil)
+	wsconn.BlockAPI = blockAPI
+
+	err := c.WriteMessage(websocket.TextMessage,
+		[]byte(`{"jsonrpc":"2.0","method":"chainHead_v1_follow","params":[false],"id":1}`))
+	require.NoError(t, err)
+
+	requireNextMessage(t, c, `{"jsonrpc":"2.0","result":"1","id":1}`)
+	requireNextMessage(t, c, fmt.Sprintf(`{"jsonrpc":"2.0","method":"chainHead_v1_followEvent","params":`+
+		`{"result":{"event":"initialized","finalizedBlockHashes":["%s"]},"subscription":"1"}}`, genesisHash))
+	requireNextMessage(t, c, fmt.Sprintf(`{"jsonrpc":"2.0","method":"chainHead_v1_followEvent","params":`+
+		`{"result":{"event":"newBlock","blockHash":"%s","parentBlockHash":"%s"},"subscription":"1"}}`,
+		blockHash, genesisHash))
+	requireNextMessage(t, c, fmt.Sprintf(`{"jsonrpc":"2.0","method":"chainHead_v1_followEvent","params":`+
+		`{"result":{"event":"bestBlockChanged","bestBlockHash":"%s"},"subscription":"1"}}`, blockHash))
+
+	blockAPI.EXPECT().GetBlockByHash(blockHash).
+		Return(&types.Block{Header: *blockHeader, Body: types.Body{{1, 2}}}, nil)
+	err = c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+		`{"jsonrpc":"2.0","method":"chainHead_v1_body","params":["1","%s"],"id":2}`, blockHash)))
+	require.NoError(t, err)
+
+	requireNextMessage(t, c, `{"jsonrpc":"2.0","result":{"result":"started","operationId":"1"},"id":2}`)
+	requireNextMessage(t, c, `{"jsonrpc":"2.0","method":"chainHead_v1_followEvent","params":`+
+		`{"result":{"event":"operationBodyDone","operationId":"1","value":["0x0102"]},"subscription":"1"}}`)
+
+	err = c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+		`{"jsonrpc":"2.0","method":"chainHead_v1_body","params":["1","%s"],"id":3}`, common.Hash{1})))
+	require.NoError(t, err)
+	requireNextMessage(t, c, fmt.Sprintf(`{"jsonrpc":"2.0","error":{"code":-32602,`+
+		`"message":"block hash not pinned: %s"},"id":3}`, common.Hash{1}))
+
+	finalizedChan <- &types.FinalisationInfo{Header: *blockHeader}
+	requireNextMessage(t, c, fmt.Sprintf(`{"jsonrpc":"2.0","method":"chainHead_v1_followEvent","params":`+
+		`{"result":{"event":"finalized","finalizedBlockHashes":["%s"],"prunedBlockHashes":[]},"subscription":"1"}}`,
+		blockHash))
+
+	blockAPI.EXPECT().UnpinBlock(genesisHash).Return(nil)
+	err = c.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+		`{"jsonrpc":"2.0","method":"chainHead_v1_unpin","params":["1",["%s"]],"id":4}`, genesisHash)))
+	require.NoError(t, err)
+	requireNextMessage(t, c, `{"jsonrpc":"2.0","result":null,"id":4}`)
+
+	// the blocks still pinned are unpinned once unfollowed
+	blockAPI.EXPECT().FreeImportedBlockNotifierChannel(importedChan)
+	blockAPI.EXPECT().FreeFinalisedNotifierChannel(finalizedChan)
+	blockAPI.EXPECT().UnpinBlock(blockHash).Return(nil)
+	err = c.WriteMessage(websocket.TextMessage,
+		[]byte(`{"jsonrpc":"2.0","method":"chainHead_v1_unfollow","params":["1"],"id":5}`))
+	require.NoError(t, err)
+	requireNextMessage(t, c, `{"jsonrpc":"2.0","result":null,"id":5}`)
+	require.Empty(t, wsconn.Subscriptions)
+}
+
+func TestTransactionWatchListener(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, c, cancel := setupWSConn(t)
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	defer cancel()
+
+	go wsconn.HandleConn()
+
+	extrinsic := types.Extrinsic{1, 2, 3}
+	blockHeader := &types.Header{Number: 1, Digest: types.NewDigest()}
+	blockHash := blockHeader.Hash()
+
+	importedChan := make(chan *types.Block)
+	finalizedChan := make(chan *types.FinalisationInfo)
+	blockAPI := mocks.NewMockBlockAPI(ctrl)
+	blockAPI.EXPECT().GetImportedBlockNotifierChannel().Return(importedChan)
+	blockAPI.EXPECT().GetFinalisedNotifierChannel().Return(finalizedChan)
+	coreAPI := mocks.NewMockCoreAPI(ctrl)
+	coreAPI.EXPECT().HandleSubmittedExtrinsic(extrinsic).Return(nil)
+	wsconn.BlockAPI = blockAPI
+	wsconn.CoreAPI = coreAPI
+
+	err := c.WriteMessage(websocket.TextMessage,
+		[]byte(`{"jsonrpc":"2.0","method":"transactionWatch_v1_submitAndWatch","params":["0x010203"],"id":1}`))
+	require.NoError(t, err)
+
+	requireNextMessage(t, c, `{"jsonrpc":"2.0","result":"1","id":1}`)
+	requireNextMessage(t, c, `{"jsonrpc":"2.0","method":"transactionWatch_v1_watchEvent","params":`+
+		`{"result":{"event":"validated"},"subscription":"1"}}`)
+
+	blockAPI.EXPECT().BestBlockHash().Return(blockHash)
+	importedChan <- &types.Block{Header: *blockHeader, Body: types.Body{{4}, extrinsic}}
+	requireNextMessage(t, c, fmt.Sprintf(`{"jsonrpc":"2.0","method":"transactionWatch_v1_watchEvent","params":`+
+		`{"result":{"event":"bestChainBlockIncluded","block":{"hash":"%s","index":1}},"subscription":"1"}}`,
+		blockHash))
+
+	listener := wsconn.Subscriptions[1].(*TransactionWatchListener)
+	blockAPI.EXPECT().GetHashByNumber(uint(1)).Return(blockHash, nil)
+	blockAPI.EXPECT().FreeImportedBlockNotifierChannel(importedChan)
+	blockAPI.EXPECT().FreeFinalisedNotifierChannel(finalizedChan)
+	finalizedChan <- &types.FinalisationInfo{Header: *blockHeader}
+	requireNextMessage(t, c, fmt.Sprintf(`{"jsonrpc":"2.0","method":"transactionWatch_v1_watchEvent","params":`+
+		`{"result":{"event":"finalized","block":{"hash":"%s","index":1}},"subscription":"1"}}`,
+		blockHash))
+
+	<-listener.done
+	require.Empty(t, wsconn.Subscriptions)
+}
@@ -8,29 +8,46 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/ChainSafe/gossamer/pkg/trie"
 )
 
 // StorageAPI is the interface for the storage state
 type StorageAPI interface {
+	GetStateRootFromBlock(bhash *common.Hash) (*common.Hash, error)
+	GetStorage(root *common.Hash, key []byte) ([]byte, error)
+	GetStorageFromChild(root *common.Hash, keyToChild, key []byte) ([]byte, error)
+	GetStorageChild(root *common.Hash, keyToChild []byte) (trie.Trie, error)
+	GetKeysWithPrefix(root *common.Hash, prefix []byte) ([][]byte, error)
+	TrieState(root *common.Hash) (*storage.TrieState, error)
 	RegisterStorageObserver(observer state.Observer)
 	UnregisterStorageObserver(observer state.Observer)
 }
 
 // BlockAPI is the interface for the block state
 type BlockAPI interface {
+	GetHeader(hash common.Hash) (*types.Header, error)
+	GetBlockByHash(hash common.Hash) (*types.Block, error)
+	GetHashByNumber(blockNumber uint) (common.Hash, error)
+	BestBlockHash() common.Hash
+	GetHighestFinalisedHash() (common.Hash, error)
+	RangeInMemory(start, end common.Hash) ([]common.Hash, error)
+	GetRuntime(blockHash common.Hash) (instance runtime.Instance, err error)
 	GetJustification(hash common.Hash) ([]byte, error)
 	GetImportedBlockNotifierChannel() chan *types.Block
 	FreeImportedBlockNotifierChannel(ch chan *types.Block)
 	GetFinalisedNotifierChannel() chan *types.FinalisationInfo
 	FreeFinalisedNotifierChannel(ch chan *types.FinalisationInfo)
 	RegisterRuntimeUpdatedChannel(ch chan<- runtime.Version) (uint32, error)
+	PinBlock(hash common.Hash) error
+	UnpinBlock(hash common.Hash) error
 }
 
 // TransactionStateAPI is the interface to get and free status notifier channels
 type TransactionStateAPI interface {
-	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status
-	FreeStatusNotifierChannel(ch chan transaction.Status)
+	GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.StatusNotification
+	FreeStatusNotifierChannel(ch chan transaction.StatusNotification)
 }
 
 // CoreAPI is the interface for the core methods
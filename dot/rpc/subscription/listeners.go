@@ -298,11 +298,9 @@ type ExtrinsicSubmitListener struct {
 	importedChan  chan *types.Block
 	importedHash  common.Hash
 	finalisedChan chan *types.FinalisationInfo
-	// txStatusChan is used to know when transaction/extrinsic becomes part of the
-	// ready queue or future queue.
-	// we are using transaction.PriorityQueue for ready queue and transaction.Pool
-	// for future queue.
-	txStatusChan  chan transaction.Status
+	// txStatusChan is used to know when the transaction/extrinsic changes status in
+	// the transaction pool, for instance when it becomes ready or is usurped.
+	txStatusChan  chan transaction.StatusNotification
 	done          chan struct{}
 	cancel        chan struct{}
 	cancelTimeout time.Duration
@@ -310,7 +308,7 @@ type ExtrinsicSubmitListener struct {
 
 // NewExtrinsicSubmitListener constructor to build new ExtrinsicSubmitListener
 func NewExtrinsicSubmitListener(conn *WSConn, extBytes []byte,
-	importedChan chan *types.Block, txStatusChan chan transaction.Status,
+	importedChan chan *types.Block, txStatusChan chan transaction.StatusNotification,
 	finalisedChan chan *types.FinalisationInfo) *ExtrinsicSubmitListener {
 	return &ExtrinsicSubmitListener{
 		wsconn:        conn,
@@ -372,12 +370,17 @@ func (l *ExtrinsicSubmitListener) Listen() {
 					resM["finalised"] = info.Header.Hash().String()
 					l.wsconn.safeSend(newSubscriptionResponse(authorExtrinsicUpdatesMethod, l.subID, resM))
 				}
-			case txStatus, ok := <-l.txStatusChan:
+			case notification, ok := <-l.txStatusChan:
 				if !ok {
 					return
 				}
 
-				l.wsconn.safeSend(newSubscriptionResponse(authorExtrinsicUpdatesMethod, l.subID, txStatus.String()))
+				if notification.Status == transaction.Retracted && notification.Hash == l.importedHash {
+					l.importedHash = common.Hash{}
+				}
+
+				l.wsconn.safeSend(newSubscriptionResponse(authorExtrinsicUpdatesMethod, l.subID,
+					newExtrinsicStatus(notification)))
 			}
 		}
 	}()
@@ -388,6 +391,19 @@ func (l *ExtrinsicSubmitListener) Stop() error {
 	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
 }
 
+// newExtrinsicStatus returns the subscription result of a transaction status notification,
+// which holds the hash of the notification for the statuses referring to a block or transaction.
+func newExtrinsicStatus(notification transaction.StatusNotification) interface{} {
+	switch notification.Status {
+	case transaction.InBlock, transaction.Retracted, transaction.Usurped, transaction.Finalized:
+		return map[string]interface{}{
+			notification.Status.String(): notification.Hash.String(),
+		}
+	default:
+		return notification.Status.String()
+	}
+}
+
 // RuntimeVersionListener to handle listening for Runtime Version
 type RuntimeVersionListener struct {
 	wsconn        WSConnAPI
@@ -180,7 +180,7 @@ func TestExtrinsicSubmitListener_Listen(t *testing.T) {
 
 	notifyImportedChan := make(chan *types.Block, 100)
 	notifyFinalizedChan := make(chan *types.FinalisationInfo, 100)
-	txStatusChan := make(chan transaction.Status)
+	txStatusChan := make(chan transaction.StatusNotification)
 
 	BlockAPI := mocks.NewMockBlockAPI(ctrl)
 	BlockAPI.EXPECT().FreeImportedBlockNotifierChannel(gomock.Any())
@@ -240,6 +240,24 @@ func TestExtrinsicSubmitListener_Listen(t *testing.T) {
 		newSubscriptionResponse(authorExtrinsicUpdatesMethod, esl.subID, resFinalised))
 	require.NoError(t, err)
 	require.Equal(t, string(expectedFinalizedBytes)+"\n", string(msg))
+
+	txStatusChan <- transaction.StatusNotification{Status: transaction.Ready}
+	_, msg, err = ws.ReadMessage()
+	require.NoError(t, err)
+	expectedReadyBytes, err := json.Marshal(
+		newSubscriptionResponse(authorExtrinsicUpdatesMethod, esl.subID, "ready"))
+	require.NoError(t, err)
+	require.Equal(t, string(expectedReadyBytes)+"\n", string(msg))
+
+	usurper := common.Hash{1}
+	txStatusChan <- transaction.StatusNotification{Status: transaction.Usurped, Hash: usurper}
+	_, msg, err = ws.ReadMessage()
+	require.NoError(t, err)
+	resUsurped := map[string]interface{}{"usurped": usurper.String()}
+	expectedUsurpedBytes, err := json.Marshal(
+		newSubscriptionResponse(authorExtrinsicUpdatesMethod, esl.subID, resUsurped))
+	require.NoError(t, err)
+	require.Equal(t, string(expectedUsurpedBytes)+"\n", string(msg))
 }
 
 func TestGrandpaJustification_Listen(t *testing.T) {
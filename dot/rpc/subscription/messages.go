@@ -3,6 +3,8 @@
 
 package subscription
 
+import "strconv"
+
 // BaseResponseJSON for base json response
 type BaseResponseJSON struct {
 	Jsonrpc string `json:"jsonrpc"`
@@ -69,3 +71,52 @@ func newBooleanResponseJSON(value bool, reqID float64) BooleanResponse {
 		ID:      reqID,
 	}
 }
+
+// InvalidParamsCode error code returned for invalid method parameters
+const InvalidParamsCode = -32602
+
+// TooManySubscriptionsCode error code returned when a connection reached its subscriptions limit
+const TooManySubscriptionsCode = -32006
+
+// v2SubscriptionResponse is a notification of the new JSON-RPC API, whose subscription ids are strings
+type v2SubscriptionResponse struct {
+	Jsonrpc string   `json:"jsonrpc"`
+	Method  string   `json:"method"`
+	Params  v2Params `json:"params"`
+}
+
+type v2Params struct {
+	Result         interface{} `json:"result"`
+	SubscriptionID string      `json:"subscription"`
+}
+
+func newV2SubscriptionResponse(method string, subID uint32, result interface{}) v2SubscriptionResponse {
+	return v2SubscriptionResponse{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params: v2Params{
+			Result:         result,
+			SubscriptionID: strconv.FormatUint(uint64(subID), 10),
+		},
+	}
+}
+
+// ResultResponseJSON for json responses of any result, including null
+type ResultResponseJSON struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	Result  interface{} `json:"result"`
+	ID      float64     `json:"id"`
+}
+
+func newResultResponseJSON(result interface{}, reqID float64) ResultResponseJSON {
+	return ResultResponseJSON{
+		Jsonrpc: "2.0",
+		Result:  result,
+		ID:      reqID,
+	}
+}
+
+// newV2SubscriptionResponseJSON builds the response of a new JSON-RPC API subscription
+func newV2SubscriptionResponseJSON(subID uint32, reqID float64) ResultResponseJSON {
+	return newResultResponseJSON(strconv.FormatUint(uint64(subID), 10), reqID)
+}
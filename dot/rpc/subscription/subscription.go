@@ -7,6 +7,8 @@
 	"errors"
 	"fmt"
 	"strconv"
+
+	"github.com/ChainSafe/gossamer/lib/common"
 )
 
 // RPC methods
@@ -19,15 +21,30 @@
 	stateSubscribeStorage          string = "state_subscribeStorage"
 	stateSubscribeRuntimeVersion   string = "state_subscribeRuntimeVersion"
 	grandpaSubscribeJustifications string = "grandpa_subscribeJustifications"
+
+	chainHeadV1Follow                string = "chainHead_v1_follow"
+	chainHeadV1Unfollow              string = "chainHead_v1_unfollow"
+	chainHeadV1Header                string = "chainHead_v1_header"
+	chainHeadV1Body                  string = "chainHead_v1_body"
+	chainHeadV1Call                  string = "chainHead_v1_call"
+	chainHeadV1Storage               string = "chainHead_v1_storage"
+	chainHeadV1Unpin                 string = "chainHead_v1_unpin"
+	transactionWatchV1SubmitAndWatch string = "transactionWatch_v1_submitAndWatch"
+	transactionWatchV1Unwatch        string = "transactionWatch_v1_unwatch"
 )
 
 type setupListener func(reqid float64, params interface{}) (Listener, error)
 
+// methodHandler handles a method that operates on an existing subscription and
+// responds to the request by itself
+type methodHandler func(reqID float64, params interface{})
+
 var (
 	errUknownParamSubscribeID = errors.New("invalid params format type")
 	errCannotParseID          = errors.New("could not parse param id")
 	errCannotFindListener     = errors.New("could not find listener")
 	errCannotFindUnsubsriber  = errors.New("could not find unsubsriber function")
+	errInvalidHashLength      = errors.New("invalid hash length")
 )
 
 func (c *WSConn) getSetupListener(method string) setupListener {
@@ -46,23 +63,50 @@ func (c *WSConn) getSetupListener(method string) setupListener {
 		return c.initRuntimeVersionListener
 	case grandpaSubscribeJustifications:
 		return c.initGrandpaJustificationListener
+	case chainHeadV1Follow:
+		return c.initChainHeadFollow
+	case transactionWatchV1SubmitAndWatch:
+		return c.initTransactionWatch
+	default:
+		return nil
+	}
+}
+
+func (c *WSConn) getMethodHandler(method string) methodHandler {
+	switch method {
+	case chainHeadV1Unfollow:
+		return c.chainHeadUnfollow
+	case chainHeadV1Header:
+		return c.chainHeadHeader
+	case chainHeadV1Body:
+		return c.chainHeadBody
+	case chainHeadV1Call:
+		return c.chainHeadCall
+	case chainHeadV1Storage:
+		return c.chainHeadStorage
+	case chainHeadV1Unpin:
+		return c.chainHeadUnpin
+	case transactionWatchV1Unwatch:
+		return c.transactionUnwatch
 	default:
 		return nil
 	}
 }
 
-func (c *WSConn) getUnsubListener(params interface{}) (Listener, error) {
-	subscribeID, err := parseSubscribeID(params)
+func (c *WSConn) getUnsubListener(params interface{}) (subscribeID uint32, listener Listener, err error) {
+	subscribeID, err = parseSubscribeID(params)
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
 
+	c.mu.Lock()
 	listener, ok := c.Subscriptions[subscribeID]
+	c.mu.Unlock()
 	if !ok {
-		return nil, fmt.Errorf("subscriber id %v: %w", subscribeID, errCannotFindListener)
+		return 0, nil, fmt.Errorf("subscriber id %v: %w", subscribeID, errCannotFindListener)
 	}
 
-	return listener, nil
+	return subscribeID, listener, nil
 }
 
 func parseSubscribeID(p interface{}) (uint32, error) {
@@ -91,3 +135,46 @@ func parseSubscribeID(p interface{}) (uint32, error) {
 
 	return id, nil
 }
+
+// parseParams checks the params are a list of at least minLen and at most maxLen elements
+func parseParams(p interface{}, minLen, maxLen int) ([]interface{}, error) {
+	params, ok := p.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %T, expected type []interface{}", errUnexpectedType, p)
+	}
+
+	if len(params) < minLen || len(params) > maxLen {
+		return nil, fmt.Errorf("%w: expected %d to %d params, got: %d",
+			errUnexpectedParamLen, minLen, maxLen, len(params))
+	}
+
+	return params, nil
+}
+
+func parseStringParam(p interface{}) (string, error) {
+	s, ok := p.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %T, expected type string", errUnexpectedType, p)
+	}
+	return s, nil
+}
+
+func parseHashParam(p interface{}) (common.Hash, error) {
+	b, err := parseBytesParam(p)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("%w: expected %d bytes, got: %d", errInvalidHashLength, common.HashLength, len(b))
+	}
+	return common.NewHash(b), nil
+}
+
+func parseBytesParam(p interface{}) ([]byte, error) {
+	s, err := parseStringParam(p)
+	if err != nil {
+		return nil, err
+	}
+	return common.HexToBytes(s)
+}
@@ -0,0 +1,236 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+)
+
+const transactionWatchEventMethod = "transactionWatch_v1_watchEvent"
+
+var errCoreAPINotSet = errors.New("error CoreAPI not set")
+
+type transactionWatchBlock struct {
+	Hash  string `json:"hash"`
+	Index uint   `json:"index"`
+}
+
+type transactionWatchEvent struct {
+	Event string                 `json:"event"`
+	Block *transactionWatchBlock `json:"block,omitempty"`
+	Error string                 `json:"error,omitempty"`
+}
+
+// TransactionWatchListener reports the progress of a transaction submitted with
+// transactionWatch_v1_submitAndWatch, until it is finalised
+type TransactionWatchListener struct {
+	wsconn        *WSConn
+	subID         uint32
+	extrinsic     types.Extrinsic
+	importedChan  chan *types.Block
+	finalizedChan chan *types.FinalisationInfo
+
+	// includedHash and includedNumber are the hash and number of the best chain block
+	// the transaction was last included in
+	includedHash   common.Hash
+	includedNumber uint
+	includedIndex  uint
+
+	done          chan struct{}
+	cancel        chan struct{}
+	cancelTimeout time.Duration
+}
+
+func (c *WSConn) initTransactionWatch(reqID float64, params interface{}) (Listener, error) {
+	p, err := parseParams(params, 1, 1)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return nil, err
+	}
+
+	// The passed parameter should be a HEX of a SCALE encoded extrinsic
+	extBytes, err := parseBytesParam(p[0])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return nil, err
+	}
+
+	if c.BlockAPI == nil {
+		c.safeSendError(reqID, nil, errBlockAPINotSet.Error())
+		return nil, errBlockAPINotSet
+	}
+
+	if c.CoreAPI == nil {
+		c.safeSendError(reqID, nil, errCoreAPINotSet.Error())
+		return nil, errCoreAPINotSet
+	}
+
+	listener := &TransactionWatchListener{
+		wsconn:        c,
+		extrinsic:     types.Extrinsic(extBytes),
+		importedChan:  c.BlockAPI.GetImportedBlockNotifierChannel(),
+		finalizedChan: c.BlockAPI.GetFinalisedNotifierChannel(),
+		cancel:        make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
+		cancelTimeout: defaultCancelTimeout,
+	}
+
+	c.mu.Lock()
+	listener.subID = atomic.AddUint32(&c.qtyListeners, 1)
+	c.Subscriptions[listener.subID] = listener
+	c.mu.Unlock()
+
+	c.safeSend(newV2SubscriptionResponseJSON(listener.subID, reqID))
+
+	err = c.CoreAPI.HandleSubmittedExtrinsic(extBytes)
+	if err != nil {
+		event := transactionWatchEvent{Event: "error", Error: err.Error()}
+		switch err.(type) {
+		case runtime.InvalidTransaction, runtime.UnknownTransaction:
+			event.Event = "invalid"
+		}
+
+		listener.send(event)
+		c.removeSubscription(listener.subID)
+		c.BlockAPI.FreeImportedBlockNotifierChannel(listener.importedChan)
+		c.BlockAPI.FreeFinalisedNotifierChannel(listener.finalizedChan)
+		return nil, fmt.Errorf("handling submitted extrinsic: %w", err)
+	}
+
+	listener.send(transactionWatchEvent{Event: "validated"})
+	return listener, nil
+}
+
+// Listen listens for the blocks including the transaction and for their finalisation
+func (l *TransactionWatchListener) Listen() {
+	go func() {
+		defer func() {
+			l.wsconn.BlockAPI.FreeImportedBlockNotifierChannel(l.importedChan)
+			l.wsconn.BlockAPI.FreeFinalisedNotifierChannel(l.finalizedChan)
+			close(l.done)
+		}()
+
+		for {
+			select {
+			case <-l.cancel:
+				return
+			case block, ok := <-l.importedChan:
+				if !ok {
+					return
+				}
+
+				if block == nil {
+					continue
+				}
+				l.handleImportedBlock(block)
+			case info, ok := <-l.finalizedChan:
+				if !ok {
+					return
+				}
+
+				if info == nil {
+					continue
+				}
+
+				if l.handleFinalisedBlock(&info.Header) {
+					l.wsconn.removeSubscription(l.subID)
+					return
+				}
+			}
+		}
+	}()
+}
+
+// Stop to cancel the running goroutines to this listener
+func (l *TransactionWatchListener) Stop() error {
+	return cancelWithTimeout(l.cancel, l.done, l.cancelTimeout)
+}
+
+func (l *TransactionWatchListener) send(event transactionWatchEvent) {
+	l.wsconn.safeSend(newV2SubscriptionResponse(transactionWatchEventMethod, l.subID, event))
+}
+
+func (l *TransactionWatchListener) handleImportedBlock(block *types.Block) {
+	hash := block.Header.Hash()
+	if hash != l.wsconn.BlockAPI.BestBlockHash() {
+		return
+	}
+
+	for i, extrinsic := range block.Body {
+		included, err := (&types.Body{extrinsic}).HasExtrinsic(l.extrinsic)
+		if err != nil {
+			logger.Debugf("failed to check block extrinsic: %s", err)
+			continue
+		}
+
+		if included {
+			l.includedHash = hash
+			l.includedNumber = block.Header.Number
+			l.includedIndex = uint(i)
+			l.send(transactionWatchEvent{
+				Event: "bestChainBlockIncluded",
+				Block: &transactionWatchBlock{Hash: hash.String(), Index: l.includedIndex},
+			})
+			return
+		}
+	}
+}
+
+// handleFinalisedBlock returns true once the block including the transaction is finalised
+func (l *TransactionWatchListener) handleFinalisedBlock(header *types.Header) bool {
+	if l.includedHash.IsEmpty() || header.Number < l.includedNumber {
+		return false
+	}
+
+	canonicalHash, err := l.wsconn.BlockAPI.GetHashByNumber(l.includedNumber)
+	if err != nil {
+		logger.Debugf("failed to get finalised block hash: %s", err)
+		return false
+	}
+
+	if canonicalHash != l.includedHash {
+		// the block including the transaction was pruned
+		l.includedHash = common.Hash{}
+		l.send(transactionWatchEvent{Event: "bestChainBlockIncluded"})
+		return false
+	}
+
+	l.send(transactionWatchEvent{
+		Event: "finalized",
+		Block: &transactionWatchBlock{Hash: l.includedHash.String(), Index: l.includedIndex},
+	})
+	return true
+}
+
+func (c *WSConn) transactionUnwatch(reqID float64, params interface{}) {
+	p, err := parseParams(params, 1, 1)
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	subID, err := parseV2SubscriptionID(p[0])
+	if err != nil {
+		c.safeSendError(reqID, big.NewInt(InvalidParamsCode), err.Error())
+		return
+	}
+
+	listener, ok := c.removeSubscription(subID)
+	if ok {
+		err = listener.Stop()
+		if err != nil {
+			logger.Warnf("failed to stop listener goroutine (method=%s): %s", transactionWatchV1Unwatch, err)
+		}
+	}
+
+	c.safeSend(newResultResponseJSON(nil, reqID))
+}
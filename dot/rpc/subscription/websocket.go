@@ -5,6 +5,7 @@
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,6 +16,7 @@
 	"sync"
 	"sync/atomic"
 
+	"github.com/ChainSafe/gossamer/dot/rpc/json2"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
@@ -38,6 +40,7 @@ type httpclient interface {
 	errEmptyMethod             = errors.New("empty method")
 	errStorageNotSet           = errors.New("error StorageAPI not set")
 	errBlockAPINotSet          = errors.New("error BlockAPI not set")
+	errTooManySubscriptions    = errors.New("too many subscriptions on the connection")
 )
 
 var logger = log.NewFromGlobal(log.AddContext("pkg", "rpc/subscription"))
@@ -55,87 +58,213 @@ type WSConn struct {
 	TxStateAPI    TransactionStateAPI
 	RPCHost       string
 	HTTP          httpclient
+
+	// MaxSubscriptions is the maximum number of subscriptions of the connection, 0 for no limit
+	MaxSubscriptions uint32
+	// MaxConcurrentRequests is the maximum number of RPC calls of the connection executed
+	// at the same time, 0 for no limit
+	MaxConcurrentRequests uint32
+
+	// ctx is canceled once the connection is closed, canceling the calls of the connection
+	ctx context.Context
 }
 
 // readWebsocketMessage will read and parse the message data to a string->interface{} data
-func (c *WSConn) readWebsocketMessage() (rawBytes []byte, wsMessage *websocketMessage, err error) {
+func (c *WSConn) readWebsocketMessage() (rawBytes []byte, err error) {
 	_, rawBytes, err = c.Wsconn.ReadMessage()
 	if err != nil {
-		return nil, nil, fmt.Errorf("%w: %s", errCannotReadFromWebsocket, err.Error())
+		return nil, fmt.Errorf("%w: %s", errCannotReadFromWebsocket, err.Error())
 	}
 
+	return rawBytes, nil
+}
+
+func parseWebsocketMessage(rawBytes []byte) (wsMessage *websocketMessage, err error) {
 	wsMessage = new(websocketMessage)
 	err = json.Unmarshal(rawBytes, wsMessage)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	if wsMessage.Method == "" {
-		return nil, nil, errEmptyMethod
+		return nil, errEmptyMethod
 	}
 
-	return rawBytes, wsMessage, nil
+	return wsMessage, nil
 }
 
 // HandleConn handles messages received on websocket connections
 func (c *WSConn) HandleConn() {
+	// requests limits the number of RPC calls of the connection executed at the same time
+	var requests chan struct{}
+	if c.MaxConcurrentRequests > 0 {
+		requests = make(chan struct{}, c.MaxConcurrentRequests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.mu.Lock()
+	c.ctx = ctx
+	c.mu.Unlock()
+
 	for {
-		rawBytes, wsMessage, err := c.readWebsocketMessage()
+		rawBytes, err := c.readWebsocketMessage()
 		if err != nil {
 			logger.Debugf("websocket failed to read message: %s", err)
-			if errors.Is(err, errCannotReadFromWebsocket) {
-				return
-			}
+			return
+		}
 
+		logger.Tracef("websocket message received: %s", string(rawBytes))
+
+		if json2.IsBatch(rawBytes) {
+			c.handleBatch(rawBytes, requests)
+			continue
+		}
+
+		wsMessage, err := parseWebsocketMessage(rawBytes)
+		if err != nil {
+			logger.Debugf("websocket failed to parse message: %s", err)
 			c.safeSendError(0, big.NewInt(InvalidRequestCode), InvalidRequestMessage)
 			continue
 		}
 
-		logger.Tracef("websocket message received: %s", string(rawBytes))
-		logger.Debugf("ws method %s called with params %v", wsMessage.Method, wsMessage.Params)
+		c.handleMessage(rawBytes, wsMessage, requests)
+	}
+}
 
-		if !strings.Contains(wsMessage.Method, "_unsubscribe") && !strings.Contains(wsMessage.Method, "_unwatch") {
-			setupListener := c.getSetupListener(wsMessage.Method)
+// handleBatch handles a batch array of requests. The RPC calls of the batch are executed
+// together and answered with a single batch response, while subscription requests are
+// answered individually.
+func (c *WSConn) handleBatch(rawBytes []byte, requests chan struct{}) {
+	var messages []json.RawMessage
+	err := json.Unmarshal(rawBytes, &messages)
+	if err != nil || len(messages) == 0 {
+		logger.Debugf("websocket failed to parse batch: %v", err)
+		c.safeSendError(0, big.NewInt(InvalidRequestCode), InvalidRequestMessage)
+		return
+	}
 
-			if setupListener == nil {
-				c.executeRPCCall(rawBytes)
-				continue
-			}
+	calls := make([]json.RawMessage, 0, len(messages))
+	for _, message := range messages {
+		wsMessage, err := parseWebsocketMessage(message)
+		if err != nil || c.isRPCCall(wsMessage.Method) {
+			// invalid requests are answered by the RPC server as part of the batch response
+			calls = append(calls, message)
+			continue
+		}
 
-			listener, err := setupListener(wsMessage.ID, wsMessage.Params)
-			if err != nil {
-				logger.Warnf("failed to create listener (method=%s): %s", wsMessage.Method, err)
-				continue
-			}
+		c.handleMessage(message, wsMessage, requests)
+	}
 
-			listener.Listen()
-			continue
+	if len(calls) == 0 {
+		return
+	}
+
+	batch, err := json.Marshal(calls)
+	if err != nil {
+		logger.Warnf("failed to encode batch: %s", err)
+		return
+	}
+	c.executeRPCCallAsync(batch, requests)
+}
+
+func (c *WSConn) handleMessage(rawBytes []byte, wsMessage *websocketMessage, requests chan struct{}) {
+	logger.Debugf("ws method %s called with params %v", wsMessage.Method, wsMessage.Params)
+
+	if handleMethod := c.getMethodHandler(wsMessage.Method); handleMethod != nil {
+		handleMethod(wsMessage.ID, wsMessage.Params)
+		return
+	}
+
+	if !isUnsubscribeMethod(wsMessage.Method) {
+		setupListener := c.getSetupListener(wsMessage.Method)
+
+		if setupListener == nil {
+			c.executeRPCCallAsync(rawBytes, requests)
+			return
 		}
 
-		listener, err := c.getUnsubListener(wsMessage.Params)
+		if c.MaxSubscriptions > 0 && c.subscriptionsCount() >= int(c.MaxSubscriptions) {
+			c.safeSendError(wsMessage.ID, big.NewInt(TooManySubscriptionsCode), errTooManySubscriptions.Error())
+			return
+		}
+
+		listener, err := setupListener(wsMessage.ID, wsMessage.Params)
 		if err != nil {
-			logger.Warnf("failed to get unsubscriber (method=%s): %s", wsMessage.Method, err)
+			logger.Warnf("failed to create listener (method=%s): %s", wsMessage.Method, err)
+			return
+		}
 
-			if errors.Is(err, errUknownParamSubscribeID) || errors.Is(err, errCannotFindUnsubsriber) {
-				c.safeSendError(wsMessage.ID, big.NewInt(InvalidRequestCode), InvalidRequestMessage)
-				continue
-			}
+		listener.Listen()
+		return
+	}
 
-			if errors.Is(err, errCannotParseID) || errors.Is(err, errCannotFindListener) {
-				c.safeSend(newBooleanResponseJSON(false, wsMessage.ID))
-				continue
-			}
+	subscribeID, listener, err := c.getUnsubListener(wsMessage.Params)
+	if err != nil {
+		logger.Warnf("failed to get unsubscriber (method=%s): %s", wsMessage.Method, err)
+
+		if errors.Is(err, errUknownParamSubscribeID) || errors.Is(err, errCannotFindUnsubsriber) {
+			c.safeSendError(wsMessage.ID, big.NewInt(InvalidRequestCode), InvalidRequestMessage)
+			return
 		}
 
-		err = listener.Stop()
-		if err != nil {
-			logger.Warnf("failed to stop listener goroutine (method=%s): %s", wsMessage.Method, err)
+		if errors.Is(err, errCannotParseID) || errors.Is(err, errCannotFindListener) {
 			c.safeSend(newBooleanResponseJSON(false, wsMessage.ID))
+			return
 		}
+	}
+
+	err = listener.Stop()
+	if err != nil {
+		logger.Warnf("failed to stop listener goroutine (method=%s): %s", wsMessage.Method, err)
+		c.safeSend(newBooleanResponseJSON(false, wsMessage.ID))
+	}
+
+	c.removeSubscription(subscribeID)
+	c.safeSend(newBooleanResponseJSON(true, wsMessage.ID))
+}
+
+// isRPCCall returns true if the method is neither a subscription nor a method operating
+// on a subscription, and is therefore executed by the RPC server
+func (c *WSConn) isRPCCall(method string) bool {
+	return c.getMethodHandler(method) == nil &&
+		!isUnsubscribeMethod(method) &&
+		c.getSetupListener(method) == nil
+}
+
+func isUnsubscribeMethod(method string) bool {
+	return strings.Contains(method, "_unsubscribe") || strings.Contains(method, "_unwatch")
+}
+
+// connContext returns the context of the connection, which is canceled once the connection is closed
+func (c *WSConn) connContext() context.Context {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
 
-		c.safeSend(newBooleanResponseJSON(true, wsMessage.ID))
-		continue
+func (c *WSConn) subscriptionsCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.Subscriptions)
+}
+
+// executeRPCCallAsync executes the RPC call in its own goroutine, waiting first for
+// the number of calls being executed to be below the limit of the connection
+func (c *WSConn) executeRPCCallAsync(data []byte, requests chan struct{}) {
+	if requests == nil {
+		go c.executeRPCCall(data)
+		return
 	}
+
+	requests <- struct{}{}
+	go func() {
+		defer func() { <-requests }()
+		c.executeRPCCall(data)
+	}()
 }
 
 func (c *WSConn) executeRPCCall(data []byte) {
@@ -441,7 +570,7 @@ func (c *WSConn) prepareRequest(b []byte) (*http.Request, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.RPCHost, buff)
+	req, err := http.NewRequestWithContext(c.connContext(), http.MethodPost, c.RPCHost, buff)
 	if err != nil {
 		logger.Warnf("failed request to rpc service: %s", err)
 		return nil, err
@@ -491,7 +491,8 @@ func TestWSConn_InitExtrinsicWatchTest(t *testing.T) {
 			if testCase.setBlocAPI {
 				wsconn.BlockAPI = modules.NewMockAnyBlockAPI(ctrl)
 				transactionStateAPI := NewMockTransactionStateAPI(ctrl)
-				transactionStateAPI.EXPECT().GetStatusNotifierChannel(gomock.Any()).Return(make(chan transaction.Status)).Times(1)
+				transactionStateAPI.EXPECT().GetStatusNotifierChannel(gomock.Any()).
+					Return(make(chan transaction.StatusNotification)).Times(1)
 				wsconn.TxStateAPI = transactionStateAPI
 			}
 
@@ -523,7 +524,8 @@ func TestWSConn_InitExtrinsicWatch(t *testing.T) {
 	wsconn.StorageAPI = modules.NewMockAnyStorageAPI(ctrl)
 	wsconn.BlockAPI = modules.NewMockAnyBlockAPI(ctrl)
 	transactionStateAPI := NewMockTransactionStateAPI(ctrl)
-	transactionStateAPI.EXPECT().GetStatusNotifierChannel(gomock.Any()).Return(make(chan transaction.Status)).Times(1)
+	transactionStateAPI.EXPECT().GetStatusNotifierChannel(gomock.Any()).
+		Return(make(chan transaction.StatusNotification)).Times(1)
 	wsconn.TxStateAPI = transactionStateAPI
 
 	// test initExtrinsicWatch with invalid transaction
@@ -728,3 +730,46 @@ func TestWSConn_CheckWebsocketInvalidData(t *testing.T) {
 		require.Equal(t, tt.expected, msg)
 	}
 }
+
+func TestWSConn_MaxSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	wsconn, c, cancel := setupWSConn(t)
+	defer cancel()
+
+	wsconn.Subscriptions = make(map[uint32]Listener)
+	wsconn.BlockAPI = modules.NewMockAnyBlockAPI(ctrl)
+	wsconn.MaxSubscriptions = 1
+
+	go wsconn.HandleConn()
+
+	err := c.WriteMessage(websocket.TextMessage,
+		[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":1}`))
+	require.NoError(t, err)
+	_, msg, err := c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","result":1,"id":1}`+"\n"), msg)
+
+	err = c.WriteMessage(websocket.TextMessage,
+		[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":2}`))
+	require.NoError(t, err)
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","error":{"code":-32006,`+
+		`"message":"too many subscriptions on the connection"},"id":2}`+"\n"), msg)
+
+	err = c.WriteMessage(websocket.TextMessage,
+		[]byte(`{"jsonrpc":"2.0","method":"chain_unsubscribeNewHeads","params":["1"],"id":3}`))
+	require.NoError(t, err)
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","result":true,"id":3}`+"\n"), msg)
+
+	// the subscription slot is freed by unsubscribing
+	err = c.WriteMessage(websocket.TextMessage,
+		[]byte(`{"jsonrpc":"2.0","method":"chain_subscribeNewHeads","params":[],"id":4}`))
+	require.NoError(t, err)
+	_, msg, err = c.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"jsonrpc":"2.0","result":2,"id":4}`+"\n"), msg)
+}
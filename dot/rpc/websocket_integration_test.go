@@ -70,12 +70,12 @@ func TestHTTPServer_ServeHTTP(t *testing.T) {
 	si := &types.SystemInfo{
 		SystemName: "gossamer",
 	}
-	sysAPI := system.NewService(si, nil)
+	sysAPI := system.NewService(si, nil, nil)
 	bAPI := modules.NewMockAnyBlockAPI(ctrl)
 	sAPI := modules.NewMockAnyStorageAPI(ctrl)
 
 	TxStateAPI := NewMockTransactionStateAPI(ctrl)
-	TxStateAPI.EXPECT().GetStatusNotifierChannel(gomock.Any()).Return(make(chan transaction.Status))
+	TxStateAPI.EXPECT().GetStatusNotifierChannel(gomock.Any()).Return(make(chan transaction.StatusNotification))
 
 	cfg := &HTTPServerConfig{
 		Modules:             []string{"system", "chain"},
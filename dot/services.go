@@ -6,6 +6,7 @@
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@
 	"github.com/ChainSafe/gossamer/dot/rpc"
 	"github.com/ChainSafe/gossamer/dot/rpc/modules"
 	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/sync"
 	"github.com/ChainSafe/gossamer/dot/system"
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -33,6 +35,7 @@
 	"github.com/ChainSafe/gossamer/lib/grandpa"
 	"github.com/ChainSafe/gossamer/lib/grandpa/warpsync"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/lib/manualseal"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
@@ -46,6 +49,7 @@ type BlockProducer interface {
 	Resume() error
 	EpochLength() uint64
 	SlotDuration() uint64
+	EpochAuthorship() (map[string]*babe.EpochAuthorship, error)
 }
 
 type rpcServiceSettings struct {
@@ -55,6 +59,7 @@ type rpcServiceSettings struct {
 	core          *core.Service
 	network       *network.Service
 	blockProducer BlockProducer
+	sealing       rpc.SealingAPI
 	system        *system.Service
 	blockFinality *grandpa.Service
 	syncer        rpc.SyncAPI
@@ -82,10 +87,16 @@ func (nodeBuilder) createStateService(config *cfg.Config) (*state.Service, error
 		return nil, fmt.Errorf("creating trie from genesis: %w", err)
 	}
 
+	// the runtime codes are compiled once in the base path, and
+	// loaded from there by the next runtime instances.
+	compilationCache := wazero_runtime.NewCompilationCache(
+		filepath.Join(config.BasePath, "wasm-cache"), wazero_runtime.DefaultCompilationCacheSize)
+
 	// create genesis runtime
 	rtCfg := wazero_runtime.Config{
-		LogLvl:  log.Critical,
-		Storage: rtstorage.NewTrieState(genTrie),
+		LogLvl:           log.Critical,
+		Storage:          rtstorage.NewTrieState(genTrie),
+		CompilationCache: compilationCache,
 	}
 
 	genesisRuntime, err := wazero_runtime.NewRuntimeFromGenesis(rtCfg)
@@ -104,11 +115,29 @@ func (nodeBuilder) createStateService(config *cfg.Config) (*state.Service, error
 		return nil, err
 	}
 
+	// the runtime instances share the log filter such that
+	// it can be changed with the system RPC module.
+	runtimeLogLevel, err := log.ParseLevel(config.Log.Runtime)
+	if err != nil {
+		return nil, fmt.Errorf("parsing runtime log level: %w", err)
+	}
+	runtimeLogDirectives, err := runtime.ParseLogDirectives(config.Log.RuntimeTargets)
+	if err != nil {
+		return nil, fmt.Errorf("parsing runtime log targets: %w", err)
+	}
+
 	stateConfig := state.Config{
-		Path:              config.BasePath,
-		LogLevel:          stateLogLevel,
-		Metrics:           metrics.NewIntervalConfig(config.PrometheusExternal),
-		GenesisBABEConfig: babeCfg,
+		Path:     config.BasePath,
+		LogLevel: stateLogLevel,
+		PrunerCfg: pruner.Config{
+			Mode:           config.Pruning,
+			RetainedBlocks: config.RetainBlocks,
+		},
+		Metrics:              metrics.NewIntervalConfig(config.PrometheusExternal),
+		GenesisBABEConfig:    babeCfg,
+		TransactionRetention: config.TransactionRetention,
+		CompilationCache:     compilationCache,
+		LogFilter:            runtime.NewLogFilter(runtimeLogLevel, runtimeLogDirectives),
 	}
 
 	stateSrvc := state.NewService(stateConfig)
@@ -188,14 +217,16 @@ func createRuntime(config *cfg.Config, ns runtime.NodeStorage, st *state.Service
 	switch config.Core.WasmInterpreter {
 	case wazero_runtime.Name:
 		rtCfg := wazero_runtime.Config{
-			Storage:     ts,
-			Keystore:    ks,
-			LogLvl:      runtimeLogLvl,
-			NodeStorage: ns,
-			Network:     net,
-			Transaction: st.Transaction,
-			Role:        config.Core.Role,
-			CodeHash:    codeHash,
+			Storage:          ts,
+			Keystore:         ks,
+			LogLvl:           runtimeLogLvl,
+			NodeStorage:      ns,
+			Network:          net,
+			Transaction:      st.Transaction,
+			Role:             config.Core.Role,
+			CodeHash:         codeHash,
+			CompilationCache: st.CompilationCache,
+			LogFilter:        st.LogFilter,
 		}
 
 		// create runtime executor
@@ -257,6 +288,8 @@ func (nodeBuilder) createBABEServiceWithBuilder(config *cfg.Config, st *state.Se
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse babe log level: %w", err)
 	}
+	// blocks are not authored in BABE slots with manual or instant sealing
+	authority := config.Core.BabeAuthority && !isManualSealing(config)
 	bcfg := &babe.ServiceConfig{
 		LogLvl:             babeLogLevel,
 		BlockState:         st.Block,
@@ -264,12 +297,13 @@ func (nodeBuilder) createBABEServiceWithBuilder(config *cfg.Config, st *state.Se
 		TransactionState:   st.Transaction,
 		EpochState:         st.Epoch,
 		BlockImportHandler: cs,
-		Authority:          config.Core.BabeAuthority,
+		Authority:          authority,
+		Keystore:           ks,
 		IsDev:              config.ID == "dev",
 		Telemetry:          telemetryMailer,
 	}
 
-	if config.Core.BabeAuthority {
+	if authority {
 		bcfg.Keypair = kps[0].(*sr25519.Keypair)
 	}
 
@@ -281,6 +315,42 @@ func (nodeBuilder) createBABEServiceWithBuilder(config *cfg.Config, st *state.Se
 	return bs, nil
 }
 
+// isManualSealing returns true if blocks are authored on demand or as soon as a transaction
+// enters the queue instead of in BABE slots
+func isManualSealing(config *cfg.Config) bool {
+	return config.Core.Sealing == cfg.ManualSealing || config.Core.Sealing == cfg.InstantSealing
+}
+
+func (nodeBuilder) createManualSealService(config *cfg.Config, st *state.Service, ks KeyStore,
+	cs *core.Service) (*manualseal.Service, error) {
+	logger.Infof("creating manual seal service with %s sealing...", config.Core.Sealing)
+
+	if ks.Name() != "babe" || ks.Type() != crypto.Sr25519Type {
+		return nil, ErrInvalidKeystoreType
+	}
+
+	kps := ks.Keypairs()
+	if len(kps) == 0 {
+		return nil, ErrNoKeysProvided
+	}
+
+	babeLogLevel, err := log.ParseLevel(config.Log.Babe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse babe log level: %w", err)
+	}
+
+	return manualseal.NewService(&manualseal.Config{
+		LogLvl:             babeLogLevel,
+		BlockState:         st.Block,
+		StorageState:       st.Storage,
+		TransactionState:   st.Transaction,
+		EpochState:         st.Epoch,
+		BlockImportHandler: cs,
+		Keypair:            kps[0].(*sr25519.Keypair),
+		Instant:            config.Core.Sealing == cfg.InstantSealing,
+	})
+}
+
 // Core Service
 
 // createCoreService creates the core service from the provided core configuration
@@ -318,6 +388,9 @@ func (nodeBuilder) createCoreService(config *cfg.Config, ks *keystore.GlobalKeys
 		CodeSubstitutes:      codeSubs,
 		CodeSubstitutedState: st.Base,
 		OnBlockImport:        digest.NewBlockImportHandler(st.Epoch, st.Grandpa),
+		OffchainWorkers:      config.Core.OffchainWorkers,
+		CompilationCache:     st.CompilationCache,
+		LogFilter:            st.LogFilter,
 	}
 
 	// create new core service
@@ -358,6 +431,7 @@ func (nodeBuilder) createNetworkService(config *cfg.Config, stateSrvc *state.Ser
 	networkConfig := network.Config{
 		LogLvl:            networkLogLevel,
 		BlockState:        stateSrvc.Block,
+		StorageState:      stateSrvc.Storage,
 		BasePath:          config.BasePath,
 		Roles:             config.Core.Role,
 		Port:              config.Network.Port,
@@ -368,6 +442,7 @@ func (nodeBuilder) createNetworkService(config *cfg.Config, stateSrvc *state.Ser
 		MinPeers:          config.Network.MinPeers,
 		MaxPeers:          config.Network.MaxPeers,
 		PersistentPeers:   config.Network.PersistentPeers,
+		ReservedOnly:      config.Network.ReservedOnly,
 		DiscoveryInterval: config.Network.DiscoveryInterval,
 		SlotDuration:      slotDuration,
 		PublicIP:          config.Network.PublicIP,
@@ -425,6 +500,7 @@ func (nodeBuilder) createRPCService(params rpcServiceSettings) (*rpc.HTTPServer,
 		CoreAPI:             params.core,
 		NodeStorage:         params.nodeStorage,
 		BlockProducerAPI:    params.blockProducer,
+		SealingAPI:          params.sealing,
 		BlockFinalityAPI:    params.blockFinality,
 		TransactionQueueAPI: params.state.Transaction,
 		RPCAPI:              rpcService,
@@ -439,7 +515,12 @@ func (nodeBuilder) createRPCService(params rpcServiceSettings) (*rpc.HTTPServer,
 		WSExternal:          params.config.RPC.WSExternal,
 		WSUnsafeExternal:    params.config.RPC.UnsafeWSExternal,
 		WSPort:              params.config.RPC.WSPort,
+		SinglePort:          params.config.RPC.SinglePort,
 		Modules:             params.config.RPC.Modules,
+
+		MaxRequestSize:                     int64(params.config.RPC.MaxRequestSize) * 1024 * 1024,
+		MaxSubscriptionsPerConnection:      params.config.RPC.MaxSubscriptionsPerConnection,
+		MaxConcurrentRequestsPerConnection: params.config.RPC.MaxConcurrentRequestsPerConnection,
 	}
 
 	return rpc.NewHTTPServer(rpcConfig), nil
@@ -452,18 +533,28 @@ func (nodeBuilder) createSystemService(cfg *types.SystemInfo, stateSrvc *state.S
 		return nil, err
 	}
 
-	return system.NewService(cfg, genesisData), nil
+	return system.NewService(cfg, genesisData, stateSrvc.LogFilter), nil
 }
 
 // createGRANDPAService creates a new GRANDPA service
 func (nodeBuilder) createGRANDPAService(config *cfg.Config, st *state.Service, ks KeyStore,
 	net *network.Service, telemetryMailer Telemetry) (*grandpa.Service, error) {
-	bestBlockHash := st.Block.BestBlockHash()
-	rt, err := st.Block.GetRuntime(bestBlockHash)
+	bestBlockHeader, err := st.Block.BestBlockHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	rt, err := st.Block.GetRuntime(bestBlockHeader.Hash())
 	if err != nil {
 		return nil, err
 	}
 
+	ts, err := st.Storage.TrieState(&bestBlockHeader.StateRoot)
+	if err != nil {
+		return nil, err
+	}
+	rt.SetContextStorage(ts)
+
 	ad, err := rt.GrandpaAuthorities()
 	if err != nil {
 		return nil, err
@@ -488,6 +579,7 @@ func (nodeBuilder) createGRANDPAService(config *cfg.Config, st *state.Service, k
 		LogLvl:       grandpaLogLevel,
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Voters:       voters,
 		Authority:    config.Core.GrandpaAuthority,
 		Network:      net,
@@ -503,7 +595,7 @@ func (nodeBuilder) createGRANDPAService(config *cfg.Config, st *state.Service, k
 }
 
 func (nodeBuilder) createBlockVerifier(st *state.Service) *babe.VerificationManager {
-	return babe.NewVerificationManager(st.Block, st.Slot, st.Epoch)
+	return babe.NewVerificationManager(st.Block, st.Storage, st.Slot, st.Epoch)
 }
 
 func (nodeBuilder) newSyncService(config *cfg.Config, st *state.Service, fg sync.FinalityGadget,
@@ -540,8 +632,11 @@ func (nodeBuilder) newSyncService(config *cfg.Config, st *state.Service, fg sync
 				blockRequestTimeout, network.MaxBlockResponseSize),
 			SyncRequestMaker: net.GetRequestResponseProtocol(network.SyncID,
 				blockRequestTimeout, network.MaxBlockResponseSize),
-			BlockState: st.Block,
-			Peers:      peersView,
+			StateRequestMaker: net.GetRequestResponseProtocol(network.StateID,
+				blockRequestTimeout, network.MaxBlockResponseSize),
+			BlockState:   st.Block,
+			StorageState: st.Storage,
+			Peers:        peersView,
 		}
 
 		warpSyncStrategy = sync.NewWarpSyncStrategy(warpSyncCfg)
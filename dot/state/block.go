@@ -11,6 +11,7 @@
 	"sync"
 	"time"
 
+	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/database"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
@@ -36,6 +37,8 @@
 	receiptPrefix       = []byte("rcp") // receiptPrefix + hash -> receipt
 	messageQueuePrefix  = []byte("mqp") // messageQueuePrefix + hash -> message queue
 	justificationPrefix = []byte("jcp") // justificationPrefix + hash -> justification
+	indexedBodyPrefix   = []byte("ibd") // indexedBodyPrefix + hash -> indexed transaction hashes
+	indexedTxPrefix     = []byte("itx") // indexedTxPrefix + indexed transaction hash -> indexed transaction
 	firstSlotNumberKey  = []byte("fsn") // firstSlotNumberKey -> First slot number
 
 	errNilBlockTree = errors.New("blocktree is nil")
@@ -76,6 +79,20 @@ type BlockState struct {
 	runtimeUpdateSubscriptions     map[uint32]chan<- runtime.Version
 
 	telemetry Telemetry
+	pruner    pruner.Pruner
+
+	// transactionRetention is the number of finalised blocks for which
+	// the indexed transactions are kept, zero keeps them forever.
+	transactionRetention uint32
+
+	// compilationCache is the cache new runtime codes are compiled in
+	compilationCache *wazero_runtime.CompilationCache
+
+	// logFilter filters the messages logged by the new runtime instances
+	logFilter *runtime.LogFilter
+
+	// pinned holds the blocks whose pruning is held off, guarded by lock
+	pinned map[common.Hash]*pinnedBlock
 }
 
 // NewBlockState will create a new BlockState backed by the database located at basePath
@@ -91,6 +108,7 @@ func NewBlockState(db database.Database, trs *Tries, telemetry Telemetry) (*Bloc
 		runtimeUpdateSubscriptions: make(map[uint32]chan<- runtime.Version),
 		telemetry:                  telemetry,
 		pause:                      make(chan struct{}),
+		pruner:                     &pruner.ArchiveNode{},
 	}
 
 	gh, err := bs.db.Get(headerHashKey(0))
@@ -127,6 +145,7 @@ func NewBlockStateFromGenesis(db database.Database, trs *Tries, header *types.He
 		lastFinalised:              header.Hash(),
 		telemetry:                  telemetryMailer,
 		pause:                      make(chan struct{}),
+		pruner:                     &pruner.ArchiveNode{},
 	}
 
 	if err := bs.setArrivalTime(header.Hash(), time.Now()); err != nil {
@@ -899,22 +918,23 @@ func (bs *BlockState) HandleRuntimeChanges(newState *rtstorage.TrieState,
 	}
 
 	rtCfg := wazero_runtime.Config{
-		Storage:     newState,
-		Keystore:    parentRuntimeInstance.Keystore(),
-		NodeStorage: parentRuntimeInstance.NodeStorage(),
-		Network:     parentRuntimeInstance.NetworkService(),
-		CodeHash:    currCodeHash,
+		Storage:          newState,
+		Keystore:         parentRuntimeInstance.Keystore(),
+		NodeStorage:      parentRuntimeInstance.NodeStorage(),
+		Network:          parentRuntimeInstance.NetworkService(),
+		CodeHash:         currCodeHash,
+		CompilationCache: bs.compilationCache,
+		LogFilter:        bs.logFilter,
 	}
 
 	if parentRuntimeInstance.Validator() {
 		rtCfg.Role = 4
 	}
 
-	instance, err := wazero_runtime.NewInstance(code, rtCfg)
-	if err != nil {
-		return err
-	}
-
+	// the new code is compiled ahead of time in the background, the calls
+	// to the runtime instance wait for the compilation to finish, and compile
+	// the code again if the background compilation failed.
+	instance := wazero_runtime.NewInstanceInBackground(code, rtCfg)
 	bs.StoreRuntime(bHash, instance)
 
 	err = bs.baseState.StoreCodeSubstitutedBlockHash(common.Hash{})
@@ -922,15 +942,20 @@ func (bs *BlockState) HandleRuntimeChanges(newState *rtstorage.TrieState,
 		return fmt.Errorf("failed to update code substituted block hash: %w", err)
 	}
 
-	newVersion, err := instance.Version()
-	if err != nil {
-		return err
-	}
-	go bs.notifyRuntimeUpdated(newVersion)
+	go func() {
+		newVersion, err := instance.Version()
+		if err != nil {
+			logger.Errorf("failed to instantiate runtime of block %s: %s", bHash, err)
+			return
+		}
+		bs.notifyRuntimeUpdated(newVersion)
+	}()
 	return nil
 }
 
-// GetRuntime gets the runtime instance pointer for the block hash given.
+// GetRuntime gets the runtime instance for the block hash given. Runtime instances
+// executing their calls on a pool of modules are returned as a new handle, with its own
+// context storage, such that the calls of the different callers run concurrently.
 func (bs *BlockState) GetRuntime(blockHash common.Hash) (instance runtime.Instance, err error) {
 	// we search primarily in the blocktree so we ensure the
 	// fork aware property while searching for a runtime, however
@@ -139,7 +139,8 @@ func (bs *BlockState) SetFinalisedHash(hash common.Hash, round, setID uint64) er
 		return fmt.Errorf("cannot finalise unknown block %s", hash)
 	}
 
-	if err := bs.handleFinalisedBlock(hash); err != nil {
+	finalisedHashes, err := bs.handleFinalisedBlock(hash)
+	if err != nil {
 		return fmt.Errorf("failed to set finalised subchain in db on finalisation: %w", err)
 	}
 
@@ -155,7 +156,8 @@ func (bs *BlockState) SetFinalisedHash(hash common.Hash, round, setID uint64) er
 		bs.notifyFinalized(hash, round, setID)
 	}
 
-	pruned := bs.bt.Prune(hash)
+	// the pruning of the pinned blocks is held off until they are unpinned
+	pruned := bs.holdPrunedPinned(bs.bt.Prune(hash))
 	for _, hash := range pruned {
 		blockHeader := bs.unfinalisedBlocks.delete(hash)
 		if blockHeader == nil {
@@ -171,6 +173,21 @@ func (bs *BlockState) SetFinalisedHash(hash common.Hash, round, setID uint64) er
 		return fmt.Errorf("failed to get finalised header, hash: %s, error: %s", hash, err)
 	}
 
+	// the abandoned forks must be pruned before the finalised blocks
+	// since they can share state trie nodes
+	if err := bs.pruner.PruneAbandoned(pruned); err != nil {
+		return fmt.Errorf("pruning abandoned forks state: %w", err)
+	}
+
+	pruningNumber := bs.finalisedPruningNumber(header.Number)
+	if err := bs.pruner.PruneFinalised(finalisedHashes, pruningNumber); err != nil {
+		return fmt.Errorf("pruning finalised blocks state: %w", err)
+	}
+
+	if err := bs.pruneIndexedBodies(pruned, header.Number, uint(len(finalisedHashes))); err != nil {
+		return fmt.Errorf("pruning indexed bodies: %w", err)
+	}
+
 	bs.telemetry.SendMessage(
 		telemetry.NewNotifyFinalized(
 			header.Hash(),
@@ -208,14 +225,16 @@ func (bs *BlockState) deleteFromTries(lastFinalised common.Hash) error {
 	return nil
 }
 
-func (bs *BlockState) handleFinalisedBlock(currentFinalizedHash common.Hash) error {
+// handleFinalisedBlock stores the newly finalised blocks in the database
+// and returns their hashes
+func (bs *BlockState) handleFinalisedBlock(currentFinalizedHash common.Hash) ([]common.Hash, error) {
 	if currentFinalizedHash == bs.lastFinalised {
-		return nil
+		return nil, nil
 	}
 
 	subchain, err := bs.RangeInMemory(bs.lastFinalised, currentFinalizedHash)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	batch := bs.db.NewBatch()
@@ -230,38 +249,38 @@ func (bs *BlockState) handleFinalisedBlock(currentFinalizedHash common.Hash) err
 
 		block := bs.unfinalisedBlocks.getBlock(subchainHash)
 		if block == nil {
-			return fmt.Errorf("failed to find block in unfinalised block map, block=%s", subchainHash)
+			return nil, fmt.Errorf("failed to find block in unfinalised block map, block=%s", subchainHash)
 		}
 
 		if err = bs.SetHeader(&block.Header); err != nil {
-			return err
+			return nil, err
 		}
 
 		if block.Header.Number == 1 {
 			slotNumber, err := block.Header.SlotNumber()
 			if err != nil {
-				return err
+				return nil, err
 			}
 
 			if err = bs.setFirstNonOriginSlotNumber(slotNumber); err != nil {
-				return err
+				return nil, err
 			}
 		}
 		if err = bs.SetBlockBody(subchainHash, &block.Body); err != nil {
-			return err
+			return nil, err
 		}
 
 		arrivalTime, err := bs.bt.GetArrivalTime(subchainHash)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if err = bs.setArrivalTime(subchainHash, arrivalTime); err != nil {
-			return err
+			return nil, err
 		}
 
 		if err = batch.Put(headerHashKey(uint64(block.Header.Number)), subchainHash.ToBytes()); err != nil {
-			return err
+			return nil, err
 		}
 
 		// delete from the unfinalisedBlockMap and delete reference to in-memory trie
@@ -280,5 +299,9 @@ func (bs *BlockState) handleFinalisedBlock(currentFinalizedHash common.Hash) err
 			blockHeader.Number, subchainHash)
 	}
 
-	return batch.Flush()
+	if err = batch.Flush(); err != nil {
+		return nil, err
+	}
+
+	return subchainExcludingLatestFinalized, nil
 }
@@ -0,0 +1,293 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+// indexedTransaction is the data of an indexed transaction,
+// shared by the indexed bodies of the blocks referencing it.
+type indexedTransaction struct {
+	References uint32
+	Data       []byte
+}
+
+// SetIndexedTransactions stores the transactions indexed by the runtime while executing the
+// block, given the index operations recorded in its trie state. The indexed transactions of
+// a block are only stored once, so calling it again for the same block does nothing.
+func (bs *BlockState) SetIndexedTransactions(block *types.Block, operations []rtstorage.IndexOperation) error {
+	if len(operations) == 0 {
+		return nil
+	}
+
+	blockHash := block.Header.Hash()
+
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	has, err := bs.db.Has(prefixKey(blockHash, indexedBodyPrefix))
+	if err != nil {
+		return fmt.Errorf("checking indexed body: %w", err)
+	} else if has {
+		return nil
+	}
+
+	// like Substrate, only the last operation of an extrinsic is applied
+	// and renewing data takes precedence over indexing new data.
+	inserted := make(map[uint32]rtstorage.IndexOperation)
+	renewed := make(map[uint32]common.Hash)
+	for _, operation := range operations {
+		if operation.Renew {
+			renewed[operation.Extrinsic] = operation.Hash
+		} else {
+			inserted[operation.Extrinsic] = operation
+		}
+	}
+
+	batch := bs.newIndexedBatch()
+	var indexedHashes []common.Hash
+	for i, extrinsic := range block.Body {
+		index := uint32(i)
+		if hash, ok := renewed[index]; ok {
+			transaction, err := batch.getIndexedTransaction(hash)
+			if errors.Is(err, database.ErrNotFound) {
+				logger.Debugf("cannot renew unknown indexed transaction %s of extrinsic %d in block %s",
+					hash, index, blockHash)
+				continue
+			} else if err != nil {
+				return fmt.Errorf("getting indexed transaction %s: %w", hash, err)
+			}
+
+			transaction.References++
+			err = batch.setIndexedTransaction(hash, transaction)
+			if err != nil {
+				return fmt.Errorf("setting indexed transaction %s: %w", hash, err)
+			}
+			indexedHashes = append(indexedHashes, hash)
+			continue
+		}
+
+		operation, ok := inserted[index]
+		if !ok {
+			continue
+		}
+
+		if int(operation.Size) > len(extrinsic) {
+			logger.Debugf("cannot index %d bytes of extrinsic %d of %d bytes in block %s",
+				operation.Size, index, len(extrinsic), blockHash)
+			continue
+		}
+
+		transaction, err := batch.getIndexedTransaction(operation.Hash)
+		if errors.Is(err, database.ErrNotFound) {
+			transaction = &indexedTransaction{
+				Data: extrinsic[len(extrinsic)-int(operation.Size):],
+			}
+		} else if err != nil {
+			return fmt.Errorf("getting indexed transaction %s: %w", operation.Hash, err)
+		}
+
+		transaction.References++
+		err = batch.setIndexedTransaction(operation.Hash, transaction)
+		if err != nil {
+			return fmt.Errorf("setting indexed transaction %s: %w", operation.Hash, err)
+		}
+		indexedHashes = append(indexedHashes, operation.Hash)
+	}
+
+	encodedHashes, err := scale.Marshal(indexedHashes)
+	if err != nil {
+		return fmt.Errorf("encoding indexed transaction hashes: %w", err)
+	}
+
+	err = batch.Put(prefixKey(blockHash, indexedBodyPrefix), encodedHashes)
+	if err != nil {
+		return fmt.Errorf("setting indexed body: %w", err)
+	}
+
+	return batch.Flush()
+}
+
+// GetIndexedBody returns the data of the transactions indexed by the block with the given hash,
+// in the order of the extrinsics indexing them. It returns nil if the block has no indexed body.
+func (bs *BlockState) GetIndexedBody(hash common.Hash) ([][]byte, error) {
+	bs.lock.RLock()
+	defer bs.lock.RUnlock()
+
+	hashes, err := bs.getIndexedTransactionHashes(hash)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting indexed transaction hashes: %w", err)
+	}
+
+	indexedBody := make([][]byte, len(hashes))
+	for i, transactionHash := range hashes {
+		transaction, err := bs.getIndexedTransaction(transactionHash)
+		if err != nil {
+			return nil, fmt.Errorf("getting indexed transaction %s: %w", transactionHash, err)
+		}
+		indexedBody[i] = transaction.Data
+	}
+
+	return indexedBody, nil
+}
+
+// pruneIndexedBodies deletes the indexed bodies of the abandoned blocks and of the
+// finalised blocks which fell out of the transaction retention period, given the
+// number of the finalised block and the count of newly finalised blocks.
+// It must be called with the block state lock held.
+func (bs *BlockState) pruneIndexedBodies(abandoned []common.Hash, finalisedNumber, newlyFinalised uint) error {
+	batch := bs.newIndexedBatch()
+	for _, hash := range abandoned {
+		err := batch.deleteIndexedBody(hash)
+		if err != nil {
+			return fmt.Errorf("deleting indexed body of abandoned block %s: %w", hash, err)
+		}
+	}
+
+	retention := uint(bs.transactionRetention)
+	if retention == 0 || newlyFinalised > finalisedNumber {
+		return batch.Flush()
+	}
+
+	for number := finalisedNumber - newlyFinalised + 1; number <= finalisedNumber; number++ {
+		if number <= retention {
+			continue
+		}
+
+		encodedHash, err := bs.db.Get(headerHashKey(uint64(number - retention)))
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("getting hash of block %d: %w", number-retention, err)
+		}
+
+		hash := common.NewHash(encodedHash)
+		err = batch.deleteIndexedBody(hash)
+		if err != nil {
+			return fmt.Errorf("deleting indexed body of block %s: %w", hash, err)
+		}
+	}
+
+	return batch.Flush()
+}
+
+// deleteIndexedBody deletes the indexed body of the block with the given hash,
+// and the indexed transactions no longer referenced by any block.
+// It must be called with the block state lock held.
+func (bs *BlockState) deleteIndexedBody(blockHash common.Hash) error {
+	batch := bs.newIndexedBatch()
+	err := batch.deleteIndexedBody(blockHash)
+	if err != nil {
+		return err
+	}
+	return batch.Flush()
+}
+
+// indexedBatch writes the indexed bodies and transactions in a single database batch.
+// The indexed transactions written are kept such that they are read back before the
+// batch is flushed, since several extrinsics or blocks may share an indexed transaction.
+type indexedBatch struct {
+	database.Batch
+	bs *BlockState
+	// written holds the indexed transactions written to the batch, nil if deleted.
+	written map[common.Hash]*indexedTransaction
+}
+
+func (bs *BlockState) newIndexedBatch() *indexedBatch {
+	return &indexedBatch{
+		Batch:   bs.db.NewBatch(),
+		bs:      bs,
+		written: make(map[common.Hash]*indexedTransaction),
+	}
+}
+
+// deleteIndexedBody deletes the indexed body of the block with the given hash,
+// and the indexed transactions no longer referenced by any block.
+func (b *indexedBatch) deleteIndexedBody(blockHash common.Hash) error {
+	hashes, err := b.bs.getIndexedTransactionHashes(blockHash)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting indexed transaction hashes: %w", err)
+	}
+
+	for _, hash := range hashes {
+		transaction, err := b.getIndexedTransaction(hash)
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("getting indexed transaction %s: %w", hash, err)
+		}
+
+		if transaction.References <= 1 {
+			b.written[hash] = nil
+			err = b.Del(prefixKey(hash, indexedTxPrefix))
+		} else {
+			transaction.References--
+			err = b.setIndexedTransaction(hash, transaction)
+		}
+		if err != nil {
+			return fmt.Errorf("dereferencing indexed transaction %s: %w", hash, err)
+		}
+	}
+
+	return b.Del(prefixKey(blockHash, indexedBodyPrefix))
+}
+
+func (b *indexedBatch) getIndexedTransaction(hash common.Hash) (*indexedTransaction, error) {
+	transaction, ok := b.written[hash]
+	if !ok {
+		return b.bs.getIndexedTransaction(hash)
+	} else if transaction == nil {
+		return nil, database.ErrNotFound
+	}
+	return transaction, nil
+}
+
+func (b *indexedBatch) setIndexedTransaction(hash common.Hash, transaction *indexedTransaction) error {
+	encoded, err := scale.Marshal(*transaction)
+	if err != nil {
+		return fmt.Errorf("encoding indexed transaction: %w", err)
+	}
+	b.written[hash] = transaction
+	return b.Put(prefixKey(hash, indexedTxPrefix), encoded)
+}
+
+func (bs *BlockState) getIndexedTransactionHashes(blockHash common.Hash) ([]common.Hash, error) {
+	data, err := bs.db.Get(prefixKey(blockHash, indexedBodyPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []common.Hash
+	err = scale.Unmarshal(data, &hashes)
+	if err != nil {
+		return nil, fmt.Errorf("decoding indexed transaction hashes: %w", err)
+	}
+	return hashes, nil
+}
+
+func (bs *BlockState) getIndexedTransaction(hash common.Hash) (*indexedTransaction, error) {
+	data, err := bs.db.Get(prefixKey(hash, indexedTxPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := new(indexedTransaction)
+	err = scale.Unmarshal(data, transaction)
+	if err != nil {
+		return nil, fmt.Errorf("decoding indexed transaction: %w", err)
+	}
+	return transaction, nil
+}
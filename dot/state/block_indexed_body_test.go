@@ -0,0 +1,149 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIndexedTestBlock(number uint, extrinsics ...types.Extrinsic) *types.Block {
+	return &types.Block{
+		Header: types.Header{
+			ParentHash: common.Hash{byte(number)},
+			Number:     number,
+			Digest:     types.NewDigest(),
+		},
+		Body: types.Body(extrinsics),
+	}
+}
+
+func TestBlockState_SetIndexedTransactions(t *testing.T) {
+	t.Parallel()
+
+	bs := newTestBlockState(t, newTriesEmpty())
+	hashA := common.Hash{0xa}
+	hashB := common.Hash{0xb}
+
+	block1 := newIndexedTestBlock(1, []byte{1, 2, 3, 4}, []byte{5, 6}, []byte{7, 8, 9}, []byte{9})
+	err := bs.SetIndexedTransactions(block1, []rtstorage.IndexOperation{
+		{Extrinsic: 0, Hash: hashA, Size: 2},
+		{Extrinsic: 1, Hash: hashB, Size: 3}, // larger than the extrinsic
+		{Extrinsic: 2, Hash: hashB, Size: 1},
+		{Extrinsic: 3, Hash: hashB, Size: 1}, // same data as the previous extrinsic
+	})
+	require.NoError(t, err)
+
+	indexedBody, err := bs.GetIndexedBody(block1.Header.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{{3, 4}, {9}, {9}}, indexedBody)
+	transaction, err := bs.getIndexedTransaction(hashB)
+	require.NoError(t, err)
+	assert.Equal(t, &indexedTransaction{References: 2, Data: []byte{9}}, transaction)
+
+	// setting the indexed transactions of the same block again does nothing
+	err = bs.SetIndexedTransactions(block1, []rtstorage.IndexOperation{
+		{Extrinsic: 0, Hash: hashA, Size: 2},
+	})
+	require.NoError(t, err)
+	transaction, err = bs.getIndexedTransaction(hashA)
+	require.NoError(t, err)
+	assert.Equal(t, &indexedTransaction{References: 1, Data: []byte{3, 4}}, transaction)
+
+	block2 := newIndexedTestBlock(2, []byte{0xff}, []byte{0xfe})
+	err = bs.SetIndexedTransactions(block2, []rtstorage.IndexOperation{
+		{Extrinsic: 0, Hash: common.Hash{0xc}, Renew: true}, // unknown transaction
+		{Extrinsic: 1, Hash: hashA, Renew: true},
+	})
+	require.NoError(t, err)
+
+	indexedBody, err = bs.GetIndexedBody(block2.Header.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{{3, 4}}, indexedBody)
+
+	transaction, err = bs.getIndexedTransaction(hashA)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), transaction.References)
+
+	indexedBody, err = bs.GetIndexedBody(common.Hash{0x99})
+	require.NoError(t, err)
+	assert.Nil(t, indexedBody)
+}
+
+func TestBlockState_pruneIndexedBodies(t *testing.T) {
+	t.Parallel()
+
+	bs := newTestBlockState(t, newTriesEmpty())
+	bs.transactionRetention = 2
+	hashA := common.Hash{0xa}
+	hashB := common.Hash{0xb}
+
+	block1 := newIndexedTestBlock(1, []byte{1, 2})
+	err := bs.SetIndexedTransactions(block1, []rtstorage.IndexOperation{
+		{Extrinsic: 0, Hash: hashA, Size: 2},
+	})
+	require.NoError(t, err)
+	err = bs.db.Put(headerHashKey(1), block1.Header.Hash().ToBytes())
+	require.NoError(t, err)
+
+	block2 := newIndexedTestBlock(2, []byte{3}, []byte{4})
+	err = bs.SetIndexedTransactions(block2, []rtstorage.IndexOperation{
+		{Extrinsic: 0, Hash: hashA, Renew: true},
+		{Extrinsic: 1, Hash: hashB, Size: 1},
+	})
+	require.NoError(t, err)
+	err = bs.db.Put(headerHashKey(2), block2.Header.Hash().ToBytes())
+	require.NoError(t, err)
+
+	abandoned := newIndexedTestBlock(3, []byte{5}, []byte{6})
+	err = bs.SetIndexedTransactions(abandoned, []rtstorage.IndexOperation{
+		{Extrinsic: 0, Hash: common.Hash{0xc}, Size: 1},
+		{Extrinsic: 1, Hash: hashA, Renew: true},
+	})
+	require.NoError(t, err)
+	transaction, err := bs.getIndexedTransaction(hashA)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), transaction.References)
+
+	// finalising block 3 prunes the abandoned block and block 1,
+	// which both dereference the transaction renewed by block 2
+	err = bs.pruneIndexedBodies([]common.Hash{abandoned.Header.Hash()}, 3, 1)
+	require.NoError(t, err)
+
+	indexedBody, err := bs.GetIndexedBody(abandoned.Header.Hash())
+	require.NoError(t, err)
+	assert.Nil(t, indexedBody)
+	_, err = bs.getIndexedTransaction(common.Hash{0xc})
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	indexedBody, err = bs.GetIndexedBody(block1.Header.Hash())
+	require.NoError(t, err)
+	assert.Nil(t, indexedBody)
+
+	// the transaction renewed by block 2 is kept
+	indexedBody, err = bs.GetIndexedBody(block2.Header.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{{1, 2}, {4}}, indexedBody)
+	transaction, err = bs.getIndexedTransaction(hashA)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), transaction.References)
+
+	// finalising block 4 prunes block 2
+	err = bs.pruneIndexedBodies(nil, 4, 1)
+	require.NoError(t, err)
+
+	indexedBody, err = bs.GetIndexedBody(block2.Header.Hash())
+	require.NoError(t, err)
+	assert.Nil(t, indexedBody)
+	_, err = bs.getIndexedTransaction(hashA)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+	_, err = bs.getIndexedTransaction(hashB)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// pinnedBlock is a block whose pruning is held off until it is unpinned.
+type pinnedBlock struct {
+	number uint
+	pins   uint
+	// pruned is true if the block was pruned while pinned,
+	// in which case it is pruned once unpinned.
+	pruned bool
+}
+
+// PinBlock holds off the pruning of the block and of its state until the block is unpinned.
+// A block pinned several times must be unpinned as many times.
+func (bs *BlockState) PinBlock(hash common.Hash) error {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	block, ok := bs.pinned[hash]
+	if ok {
+		block.pins++
+		return nil
+	}
+
+	header, err := bs.GetHeader(hash)
+	if err != nil {
+		return fmt.Errorf("getting header: %w", err)
+	}
+
+	if bs.pinned == nil {
+		bs.pinned = make(map[common.Hash]*pinnedBlock)
+	}
+	bs.pinned[hash] = &pinnedBlock{
+		number: header.Number,
+		pins:   1,
+	}
+	return nil
+}
+
+// UnpinBlock releases a pin of the block. The block is pruned once it is not
+// pinned anymore if it was pruned while pinned.
+func (bs *BlockState) UnpinBlock(hash common.Hash) error {
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	block, ok := bs.pinned[hash]
+	if !ok {
+		return nil
+	}
+
+	block.pins--
+	if block.pins > 0 {
+		return nil
+	}
+	delete(bs.pinned, hash)
+
+	if !block.pruned {
+		return nil
+	}
+
+	blockHeader := bs.unfinalisedBlocks.delete(hash)
+	if blockHeader != nil {
+		bs.tries.delete(blockHeader.StateRoot)
+	}
+
+	err := bs.pruner.PruneAbandoned([]common.Hash{hash})
+	if err != nil {
+		return fmt.Errorf("pruning abandoned block state: %w", err)
+	}
+
+	err = bs.deleteIndexedBody(hash)
+	if err != nil {
+		return fmt.Errorf("deleting indexed body: %w", err)
+	}
+
+	logger.Tracef("pruned unpinned block number %d with hash %s", block.number, hash)
+	return nil
+}
+
+// holdPrunedPinned marks the pinned blocks among the pruned blocks as pruned, and returns
+// the pruned blocks which are not pinned and can be pruned right away.
+// It must be called with the block state lock held.
+func (bs *BlockState) holdPrunedPinned(pruned []common.Hash) (unpinned []common.Hash) {
+	unpinned = make([]common.Hash, 0, len(pruned))
+	for _, hash := range pruned {
+		block, ok := bs.pinned[hash]
+		if ok {
+			block.pruned = true
+			continue
+		}
+		unpinned = append(unpinned, hash)
+	}
+	return unpinned
+}
+
+// finalisedPruningNumber returns the number the state of the finalised blocks is pruned from,
+// which is the finalised number lowered to the number of the oldest pinned block such that
+// the state of the pinned blocks is kept.
+// It must be called with the block state lock held.
+func (bs *BlockState) finalisedPruningNumber(finalisedNumber uint) uint {
+	for _, block := range bs.pinned {
+		finalisedNumber = min(finalisedNumber, block.number)
+	}
+	return finalisedNumber
+}
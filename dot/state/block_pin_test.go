@@ -0,0 +1,72 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package state
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockState_PinBlock(t *testing.T) {
+	t.Parallel()
+
+	bs := newTestBlockState(t, newTriesEmpty())
+
+	err := bs.PinBlock(common.Hash{1})
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	const withBranches = false
+	chain, _ := AddBlocksToState(t, bs, 4, withBranches)
+
+	digest := types.NewDigest()
+	preDigest, err := types.NewBabeSecondaryPlainPreDigest(0, 99).ToPreRuntimeDigest()
+	require.NoError(t, err)
+	err = digest.Add(*preDigest)
+	require.NoError(t, err)
+	fork := &types.Block{
+		Header: types.Header{
+			ParentHash: chain[0].Hash(),
+			Number:     chain[0].Number + 1,
+			Digest:     digest,
+		},
+		Body: types.Body{},
+	}
+	err = bs.AddBlock(fork)
+	require.NoError(t, err)
+	forkHash := fork.Header.Hash()
+
+	// the fork block is pinned twice
+	err = bs.PinBlock(forkHash)
+	require.NoError(t, err)
+	err = bs.PinBlock(forkHash)
+	require.NoError(t, err)
+	assert.Equal(t, fork.Header.Number, bs.finalisedPruningNumber(chain[len(chain)-1].Number))
+
+	err = bs.SetFinalisedHash(chain[len(chain)-1].Hash(), 1, 0)
+	require.NoError(t, err)
+
+	// the pruning of the fork block is held off until it is unpinned
+	_, err = bs.GetHeader(forkHash)
+	require.NoError(t, err)
+
+	err = bs.UnpinBlock(forkHash)
+	require.NoError(t, err)
+	_, err = bs.GetHeader(forkHash)
+	require.NoError(t, err)
+
+	err = bs.UnpinBlock(forkHash)
+	require.NoError(t, err)
+	_, err = bs.GetHeader(forkHash)
+	require.ErrorIs(t, err, database.ErrNotFound)
+	assert.Empty(t, bs.pinned)
+
+	// unpinning a block not pinned does nothing
+	err = bs.UnpinBlock(forkHash)
+	require.NoError(t, err)
+}
@@ -24,6 +24,7 @@
 	errUnfinalizedAncestor     = errors.New("unfinalized ancestor")
 
 	ErrNoNextAuthorityChange = errors.New("no next authority change")
+	ErrNoNextPause           = errors.New("no next pause")
 )
 
 var (
@@ -43,6 +44,8 @@ type GrandpaState struct {
 
 	forcedChanges        *orderedPendingChanges
 	scheduledChangeRoots *changeTree
+	scheduledPauses      *changeTree
+	scheduledResumes     *changeTree
 	telemetry            Telemetry
 }
 
@@ -55,6 +58,8 @@ func NewGrandpaStateFromGenesis(db database.Database, bs *BlockState,
 		blockState:           bs,
 		scheduledChangeRoots: new(changeTree),
 		forcedChanges:        new(orderedPendingChanges),
+		scheduledPauses:      new(changeTree),
+		scheduledResumes:     new(changeTree),
 		telemetry:            telemetry,
 	}
 
@@ -84,6 +89,8 @@ func NewGrandpaState(db database.Database, bs *BlockState, telemetry Telemetry)
 		blockState:           bs,
 		scheduledChangeRoots: new(changeTree),
 		forcedChanges:        new(orderedPendingChanges),
+		scheduledPauses:      new(changeTree),
+		scheduledResumes:     new(changeTree),
 		telemetry:            telemetry,
 	}
 }
@@ -102,11 +109,9 @@ func (s *GrandpaState) HandleGRANDPADigest(header *types.Header, digest types.Gr
 	case types.GrandpaOnDisabled:
 		return nil
 	case types.GrandpaPause:
-		logger.Warn("GRANDPA Pause consensus message not implemented yet")
-		return nil
+		return s.addPause(header, val)
 	case types.GrandpaResume:
-		logger.Warn("GRANDPA Resume consensus message not implemented yet")
-		return nil
+		return s.addResume(header, val)
 	default:
 		return fmt.Errorf("not supported digest")
 	}
@@ -155,8 +160,40 @@ func (s *GrandpaState) addScheduledChange(header *types.Header, sc types.Grandpa
 	return nil
 }
 
+func (s *GrandpaState) addPause(header *types.Header, p types.GrandpaPause) error {
+	pendingPause := &pendingChange{
+		announcingHeader: header,
+		delay:            p.Delay,
+	}
+
+	err := s.scheduledPauses.importChange(pendingPause, s.blockState.IsDescendantOf)
+	if err != nil {
+		return fmt.Errorf("cannot import scheduled pause: %w", err)
+	}
+
+	logger.Debugf("there are now %d possible scheduled pause roots", s.scheduledPauses.Len())
+	return nil
+}
+
+func (s *GrandpaState) addResume(header *types.Header, r types.GrandpaResume) error {
+	pendingResume := &pendingChange{
+		announcingHeader: header,
+		delay:            r.Delay,
+	}
+
+	err := s.scheduledResumes.importChange(pendingResume, s.blockState.IsDescendantOf)
+	if err != nil {
+		return fmt.Errorf("cannot import scheduled resume: %w", err)
+	}
+
+	logger.Debugf("there are now %d possible scheduled resume roots", s.scheduledResumes.Len())
+	return nil
+}
+
 // ApplyScheduledChanges will check the schedules changes in order to find a root
-// equal or behind the finalized number and will apply its authority set changes
+// equal or behind the finalized number and will apply its authority set changes.
+// Scheduled pauses enacted by the finalized block are applied as well, and resumes
+// which can no longer be enacted on the finalized chain are discarded.
 func (s *GrandpaState) ApplyScheduledChanges(finalizedHeader *types.Header) error {
 	finalizedHash := finalizedHeader.Hash()
 
@@ -165,6 +202,16 @@ func (s *GrandpaState) ApplyScheduledChanges(finalizedHeader *types.Header) erro
 		return fmt.Errorf("cannot prune non-descendant forced changes: %w", err)
 	}
 
+	err = s.applyScheduledPause(finalizedHeader)
+	if err != nil {
+		return fmt.Errorf("cannot apply scheduled pause: %w", err)
+	}
+
+	err = s.scheduledResumes.pruneStaleChanges(finalizedHash, finalizedHeader.Number, s.blockState.IsDescendantOf)
+	if err != nil {
+		return fmt.Errorf("cannot prune non-descendant scheduled resumes: %w", err)
+	}
+
 	if s.scheduledChangeRoots.Len() == 0 {
 		return nil
 	}
@@ -208,9 +255,78 @@ func (s *GrandpaState) ApplyScheduledChanges(finalizedHeader *types.Header) erro
 	return nil
 }
 
+// applyScheduledPause looks for a pause enacted at or behind the finalized block
+// and, if any, stores its effective block number as the next pause
+func (s *GrandpaState) applyScheduledPause(finalizedHeader *types.Header) error {
+	if s.scheduledPauses.Len() == 0 {
+		return nil
+	}
+
+	pauseToApply, err := s.scheduledPauses.findApplicable(finalizedHeader.Hash(),
+		finalizedHeader.Number, s.blockState.IsDescendantOf)
+	if err != nil {
+		return fmt.Errorf("cannot get applicable scheduled pause: %w", err)
+	}
+
+	if pauseToApply == nil {
+		return nil
+	}
+
+	err = s.SetNextPause(pauseToApply.change.effectiveNumber())
+	if err != nil {
+		return fmt.Errorf("cannot set next pause: %w", err)
+	}
+
+	logger.Debugf("applied grandpa pause: %s", pauseToApply.change)
+	return nil
+}
+
+// applyScheduledResume looks for a resume enacted by the imported block and,
+// if any, stores its effective block number as the next resume. Resumes are
+// enacted on import since finality is stalled while the authorities are paused,
+// and only for blocks on the best chain, which holds the finalised chain.
+func (s *GrandpaState) applyScheduledResume(importedBlockHeader *types.Header) error {
+	importedHash := importedBlockHeader.Hash()
+	resumeToApply, err := s.scheduledResumes.lookupChangeWhere(func(pcn *pendingChangeNode) (bool, error) {
+		if pcn.change.effectiveNumber() != importedBlockHeader.Number {
+			return false, nil
+		}
+
+		return s.blockState.IsDescendantOf(pcn.change.announcingHeader.Hash(), importedHash)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot find applicable scheduled resume: %w", err)
+	} else if resumeToApply == nil {
+		return nil
+	}
+
+	onBestChain, err := s.blockState.IsDescendantOf(importedHash, s.blockState.BestBlockHash())
+	if err != nil {
+		return fmt.Errorf("cannot check if imported block is on the best chain: %w", err)
+	} else if !onBestChain {
+		return nil
+	}
+
+	err = s.SetNextResume(resumeToApply.change.effectiveNumber())
+	if err != nil {
+		return fmt.Errorf("cannot set next resume: %w", err)
+	}
+
+	s.scheduledResumes.replaceRoot(resumeToApply)
+
+	logger.Debugf("applied grandpa resume: %s", resumeToApply.change)
+	return nil
+}
+
 // ApplyForcedChanges will check for if there is a scheduled forced change relative to the
-// imported block and then apply it otherwise nothing happens
+// imported block and then apply it otherwise nothing happens. Scheduled resumes enacted
+// by the imported block are applied as well.
 func (s *GrandpaState) ApplyForcedChanges(importedBlockHeader *types.Header) error {
+	err := s.applyScheduledResume(importedBlockHeader)
+	if err != nil {
+		return fmt.Errorf("cannot apply scheduled resume: %w", err)
+	}
+
 	forcedChange, err := s.forcedChanges.findApplicable(importedBlockHeader.Hash(),
 		importedBlockHeader.Number, s.blockState.IsDescendantOf)
 	if err != nil {
@@ -322,6 +438,29 @@ func (s *GrandpaState) NextGrandpaAuthorityChange(bestBlockHash common.Hash, bes
 	return next, nil
 }
 
+// NextGrandpaPause returns the block number of the next upcoming grandpa pause on the
+// chain of the given best block. It returns ErrNoNextPause if no pause is scheduled.
+func (s *GrandpaState) NextGrandpaPause(bestBlockHash common.Hash, bestBlockNumber uint) (
+	blockNumber uint, err error) {
+	pauseNode, err := s.scheduledPauses.lookupChangeWhere(func(pcn *pendingChangeNode) (bool, error) {
+		isDescendant, err := s.blockState.IsDescendantOf(pcn.change.announcingHeader.Hash(), bestBlockHash)
+		if err != nil {
+			return false, fmt.Errorf("cannot check ancestry: %w", err)
+		}
+
+		return isDescendant && pcn.change.effectiveNumber() <= bestBlockNumber, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot get pause on chain of %s: %w", bestBlockHash, err)
+	}
+
+	if pauseNode == nil {
+		return 0, ErrNoNextPause
+	}
+
+	return pauseNode.change.effectiveNumber(), nil
+}
+
 func authoritiesKey(setID uint64) []byte {
 	buf := make([]byte, 8)
 	binary.LittleEndian.PutUint64(buf, setID)
@@ -544,6 +683,12 @@ func precommitsKey(round, setID uint64) []byte {
 	return append(precommitsPrefix, k...)
 }
 
+func hasVotedKey(round, setID uint64) []byte {
+	hasVotedPrefix := []byte("hv")
+	k := roundAndSetIDToBytes(round, setID)
+	return append(hasVotedPrefix, k...)
+}
+
 func roundAndSetIDToBytes(round, setID uint64) []byte {
 	buf := make([]byte, 8)
 	binary.LittleEndian.PutUint64(buf, round)
@@ -604,6 +749,32 @@ func (s *GrandpaState) GetPrecommits(round, setID uint64) ([]types.GrandpaSigned
 	return pcs, nil
 }
 
+// SetHasVoted sets the votes cast by the node for a specific round and set ID in the database
+func (s *GrandpaState) SetHasVoted(round, setID uint64, hasVoted types.GrandpaHasVoted) error {
+	data, err := scale.Marshal(hasVoted)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Put(hasVotedKey(round, setID), data)
+}
+
+// GetHasVoted retrieves the votes cast by the node for a specific round and set ID from the database
+func (s *GrandpaState) GetHasVoted(round, setID uint64) (types.GrandpaHasVoted, error) {
+	data, err := s.db.Get(hasVotedKey(round, setID))
+	if err != nil {
+		return types.GrandpaHasVoted{}, err
+	}
+
+	var hasVoted types.GrandpaHasVoted
+	err = scale.Unmarshal(data, &hasVoted)
+	if err != nil {
+		return types.GrandpaHasVoted{}, err
+	}
+
+	return hasVoted, nil
+}
+
 // GetAuthoritiesChangesFromBlock retrieves blocks numbers where authority set changes happened
 func (s *GrandpaState) GetAuthoritiesChangesFromBlock(initialBlockNumber uint) ([]uint, error) {
 	blockNumbers := make([]uint, 0)
@@ -319,6 +319,56 @@ func (ct *changeTree) pruneChanges(hash common.Hash, isDescendantOf isDescendant
 	return nil
 }
 
+// pruneStaleChanges will remove changes which cannot be enacted anymore on the chain
+// of the hash argument, keeping descendant changes and ancestor changes whose effective
+// number is greater than the given number. This function updates the current state of
+// the change tree
+func (ct *changeTree) pruneStaleChanges(hash common.Hash, number uint, isDescendantOf isDescendantOfFunc) error {
+	var onBranchChanges []*pendingChangeNode
+
+	for _, root := range *ct {
+		changeHash := root.change.announcingHeader.Hash()
+
+		isDescendant, err := isDescendantOf(hash, changeHash)
+		if err != nil {
+			return fmt.Errorf("cannot verify ancestry: %w", err)
+		}
+
+		if isDescendant {
+			onBranchChanges = append(onBranchChanges, root)
+			continue
+		}
+
+		if root.change.effectiveNumber() <= number {
+			continue
+		}
+
+		isAncestor, err := isDescendantOf(changeHash, hash)
+		if err != nil {
+			return fmt.Errorf("cannot verify ancestry: %w", err)
+		}
+
+		if isAncestor {
+			onBranchChanges = append(onBranchChanges, root)
+		}
+	}
+
+	*ct = onBranchChanges
+	return nil
+}
+
+// replaceRoot removes the given root node from the tree, its children become roots
+func (ct *changeTree) replaceRoot(root *pendingChangeNode) {
+	roots := make([]*pendingChangeNode, 0, ct.Len()-1+len(root.nodes))
+	for _, node := range *ct {
+		if node != root {
+			roots = append(roots, node)
+		}
+	}
+
+	*ct = append(roots, root.nodes...)
+}
+
 func (ct *changeTree) pruneAll() {
 	*ct = []*pendingChangeNode{}
 }
@@ -124,6 +124,28 @@ func TestGrandpaState_LatestRound(t *testing.T) {
 	require.Equal(t, uint64(99), r)
 }
 
+func TestGrandpaState_HasVoted(t *testing.T) {
+	db := NewInMemoryDB(t)
+	gs, err := NewGrandpaStateFromGenesis(db, nil, testAuths, nil)
+	require.NoError(t, err)
+
+	_, err = gs.GetHasVoted(1, 0)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	hasVoted := types.GrandpaHasVoted{
+		Prevote: &types.GrandpaVote{Hash: common.Hash{1}, Number: 1},
+	}
+	err = gs.SetHasVoted(1, 0, hasVoted)
+	require.NoError(t, err)
+
+	stored, err := gs.GetHasVoted(1, 0)
+	require.NoError(t, err)
+	require.Equal(t, hasVoted, stored)
+
+	_, err = gs.GetHasVoted(1, 1)
+	require.ErrorIs(t, err, database.ErrNotFound)
+}
+
 func testBlockState(t *testing.T, db database.Database) *BlockState {
 	ctrl := gomock.NewController(t)
 	telemetryMock := NewMockTelemetry(ctrl)
@@ -1449,3 +1471,154 @@ func TestApplyScheduledChange(t *testing.T) {
 		})
 	}
 }
+
+func TestGrandpaState_ApplyScheduledPause(t *testing.T) {
+	t.Parallel()
+
+	keyring, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		pauseAnnouncingIndex int
+		pause                types.GrandpaPause
+		finalizedIndex       int
+		// when true the pause is announced on a fork of the finalized chain
+		onFork bool
+
+		expectedNextPause    uint
+		expectedPauseApplied bool
+	}{
+		"pause_not_enacted_yet": {
+			pauseAnnouncingIndex: 2, // in the chain headers slice the index 2 == block number 3
+			pause:                types.GrandpaPause{Delay: 4},
+			finalizedIndex:       5,
+			expectedNextPause:    7,
+		},
+		"pause_enacted_by_finalized_block": {
+			pauseAnnouncingIndex: 2,
+			pause:                types.GrandpaPause{Delay: 4},
+			finalizedIndex:       6,
+			expectedNextPause:    7,
+			expectedPauseApplied: true,
+		},
+		"pause_enacted_behind_finalized_block": {
+			pauseAnnouncingIndex: 2,
+			pause:                types.GrandpaPause{Delay: 0},
+			finalizedIndex:       8,
+			expectedNextPause:    3,
+			expectedPauseApplied: true,
+		},
+		"pause_on_non_finalized_fork": {
+			pauseAnnouncingIndex: 2,
+			pause:                types.GrandpaPause{Delay: 1},
+			finalizedIndex:       8,
+			onFork:               true,
+			expectedNextPause:    4,
+		},
+	}
+
+	for tname, tt := range tests {
+		tt := tt
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			db := NewInMemoryDB(t)
+			blockState := testBlockState(t, db)
+
+			gs, err := NewGrandpaStateFromGenesis(db, blockState, nil, nil)
+			require.NoError(t, err)
+
+			const sizeOfChain = 10
+			chainHeaders := issueBlocksWithBABEPrimary(t, keyring.KeyAlice, gs.blockState,
+				testGenesisHeader, sizeOfChain)
+
+			announcingHeader := chainHeaders[tt.pauseAnnouncingIndex]
+			if tt.onFork {
+				forkHeaders := issueBlocksWithBABEPrimary(t, keyring.KeyBob, gs.blockState,
+					chainHeaders[tt.pauseAnnouncingIndex-1], 3)
+				announcingHeader = forkHeaders[0]
+			}
+
+			digest := types.NewGrandpaConsensusDigest()
+			require.NoError(t, digest.SetValue(tt.pause))
+			err = gs.HandleGRANDPADigest(announcingHeader, digest)
+			require.NoError(t, err)
+
+			nextPause, err := gs.NextGrandpaPause(chainHeaders[sizeOfChain].Hash(), chainHeaders[sizeOfChain].Number)
+			if tt.onFork {
+				require.ErrorIs(t, err, ErrNoNextPause)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.expectedNextPause, nextPause)
+			}
+
+			err = gs.ApplyScheduledChanges(chainHeaders[tt.finalizedIndex])
+			require.NoError(t, err)
+
+			appliedPause, err := gs.GetNextPause()
+			if !tt.expectedPauseApplied {
+				require.ErrorIs(t, err, database.ErrNotFound)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedNextPause, appliedPause)
+			require.Zero(t, gs.scheduledPauses.Len())
+		})
+	}
+}
+
+func TestGrandpaState_ApplyScheduledResume(t *testing.T) {
+	t.Parallel()
+
+	keyring, err := keystore.NewSr25519Keyring()
+	require.NoError(t, err)
+
+	db := NewInMemoryDB(t)
+	blockState := testBlockState(t, db)
+
+	gs, err := NewGrandpaStateFromGenesis(db, blockState, nil, nil)
+	require.NoError(t, err)
+
+	const sizeOfChain = 10
+	chainHeaders := issueBlocksWithBABEPrimary(t, keyring.KeyAlice, gs.blockState,
+		testGenesisHeader, sizeOfChain)
+	// fork from block number 3 which does not contain the resume
+	forkHeaders := issueBlocksWithBABEPrimary(t, keyring.KeyBob, gs.blockState,
+		chainHeaders[2], 5)
+	// fork from block number 4 which contains the resume but is not the best chain
+	announcingForkHeaders := issueBlocksWithBABEPrimary(t, keyring.KeyCharlie, gs.blockState,
+		chainHeaders[3], 4)
+
+	digest := types.NewGrandpaConsensusDigest()
+	require.NoError(t, digest.SetValue(types.GrandpaResume{Delay: 3}))
+	// resume announced at block number 4 and enacted at block number 7
+	err = gs.HandleGRANDPADigest(chainHeaders[3], digest)
+	require.NoError(t, err)
+
+	// finalizing an ancestor of the announcing block keeps the resume
+	err = gs.ApplyScheduledChanges(chainHeaders[2])
+	require.NoError(t, err)
+	require.Equal(t, 1, gs.scheduledResumes.Len())
+
+	// importing a block at the effective number on another fork does not resume
+	err = gs.ApplyForcedChanges(forkHeaders[3])
+	require.NoError(t, err)
+	_, err = gs.GetNextResume()
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	// importing a block enacting the resume outside of the best chain does not resume
+	err = gs.ApplyForcedChanges(announcingForkHeaders[2])
+	require.NoError(t, err)
+	_, err = gs.GetNextResume()
+	require.ErrorIs(t, err, database.ErrNotFound)
+	require.Equal(t, 1, gs.scheduledResumes.Len())
+
+	err = gs.ApplyForcedChanges(chainHeaders[6])
+	require.NoError(t, err)
+
+	nextResume, err := gs.GetNextResume()
+	require.NoError(t, err)
+	require.Equal(t, uint(7), nextResume)
+	require.Zero(t, gs.scheduledResumes.Len())
+}
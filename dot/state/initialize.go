@@ -16,6 +16,7 @@
 	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
 )
 
 // Initialise initialises the genesis state of the DB using the given storage trie.
@@ -66,8 +67,14 @@ func (s *Service) Initialise(gen *genesis.Genesis, header *types.Header, t trie.
 		return fmt.Errorf("failed to write genesis values to database: %s", err)
 	}
 
+	// the hashed storage values are written at the key made of their hash from the start
+	if err = db.Put(common.HashedValueKeysMigratedKey, []byte{1}); err != nil {
+		return fmt.Errorf("failed to write hashed value keys migration flag: %w", err)
+	}
+
+	// the genesis trie nodes are lazily loaded from the database from now on
 	tries := NewTries()
-	tries.SetTrie(t)
+	tries.SetTrie(triedb.NewStateTrie(t.MustHash(), database.NewTable(db, storagePrefix), nil))
 
 	// create block state from genesis block
 	blockState, err := NewBlockStateFromGenesis(db, tries, header, s.Telemetry)
@@ -162,8 +169,10 @@ func (s *Service) CreateGenesisRuntime(t trie.Trie) (runtime.Instance, error) {
 
 	// create genesis runtime
 	rtCfg := wazero_runtime.Config{
-		LogLvl:  s.logLvl,
-		Storage: genTrie,
+		LogLvl:           s.logLvl,
+		Storage:          genTrie,
+		CompilationCache: s.CompilationCache,
+		LogFilter:        s.LogFilter,
 	}
 
 	r, err := wazero_runtime.NewRuntimeFromGenesis(rtCfg)
@@ -4,6 +4,7 @@
 package state
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"sync"
@@ -11,15 +12,20 @@
 	"github.com/ChainSafe/gossamer/dot/state/pruner"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/database"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/trie"
-	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
 	"github.com/ChainSafe/gossamer/pkg/trie/inmemory/proof"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
 )
 
 // storagePrefix storage key prefix.
 var storagePrefix = "storage"
+
+// prunerPrefix state pruner journal key prefix.
+var prunerPrefix = "pruner"
 var codeKey = common.CodeKey
 
 // ErrTrieDoesNotExist is returned when attempting to interact with a trie that is not stored in the StorageState
@@ -29,10 +35,25 @@ func errTrieDoesNotExist(hash common.Hash) error {
 	return fmt.Errorf("%w: %s", ErrTrieDoesNotExist, hash)
 }
 
+var errClosestDescendantNotSupported = errors.New("closest descendant lookup not supported by trie")
+
+// closestDescendantMerkleValuer is a trie able to look up the Merkle value
+// of the closest descendant node of a key.
+type closestDescendantMerkleValuer interface {
+	ClosestDescendantMerkleValue(key []byte) ([]byte, error)
+}
+
+// dirtyWriter is a trie writing its changes to a database.
+type dirtyWriter interface {
+	WriteDirty(db db.NewBatcher) error
+}
+
 // InmemoryStorageState is the struct that holds the trie, db and lock
 type InmemoryStorageState struct {
 	blockState *BlockState
 	tries      *Tries
+	// nodeCache is shared by the tries lazily loaded from the database
+	nodeCache *triedb.NodeCache[chash.H256]
 
 	db GetterPutterNewBatcher
 	sync.RWMutex
@@ -52,6 +73,7 @@ func NewStorageState(db database.Database, blockState *BlockState,
 	return &InmemoryStorageState{
 		blockState:   blockState,
 		tries:        tries,
+		nodeCache:    triedb.NewNodeCache[chash.H256](triedb.DefaultNodeCacheCapacity),
 		db:           storageTable,
 		observerList: []Observer{},
 		pruner:       &pruner.ArchiveNode{},
@@ -61,7 +83,6 @@ func NewStorageState(db database.Database, blockState *BlockState,
 // StoreTrie stores the given trie in the StorageState and writes it to the database
 func (s *InmemoryStorageState) StoreTrie(ts *storage.TrieState, header *types.Header) error {
 	root := ts.Trie().MustHash()
-	s.tries.softSet(root, ts.Trie())
 
 	if header != nil {
 		insertedNodeHashes, deletedNodeHashes, err := ts.GetChangedNodeHashes()
@@ -76,20 +97,45 @@ func (s *InmemoryStorageState) StoreTrie(ts *storage.TrieState, header *types.He
 		}
 	}
 
-	logger.Tracef("cached trie in storage state: %s", root)
-
 	// TODO: all trie related db operations should be done in pkg/trie
-	if inmemoryTrie, ok := ts.Trie().(*inmemory_trie.InMemoryTrie); ok {
-		if err := inmemoryTrie.WriteDirty(s.db); err != nil {
+	if writer, ok := ts.Trie().(dirtyWriter); ok {
+		if err := writer.WriteDirty(s.db); err != nil {
 			logger.Warnf("failed to write trie with root %s to database: %s", root, err)
 			return err
 		}
 	}
 
+	// the trie is shared once its nodes can be loaded from the database
+	s.tries.softSet(root, ts.Trie())
+	logger.Tracef("cached trie in storage state: %s", root)
+
 	go s.notifyAll(root)
 	return nil
 }
 
+// StoreTrieNodes writes the given trie nodes and values, keyed by their hash, to the database
+func (s *InmemoryStorageState) StoreTrieNodes(nodes map[common.Hash][]byte) error {
+	batch := s.db.NewBatch()
+	defer func() {
+		if err := batch.Close(); err != nil {
+			logger.Warnf("failed to close trie nodes batch: %s", err)
+		}
+	}()
+
+	for hash, data := range nodes {
+		err := batch.Put(hash[:], data)
+		if err != nil {
+			return fmt.Errorf("writing trie node %s: %w", hash, err)
+		}
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing trie nodes: %w", err)
+	}
+	return nil
+}
+
 // TrieState returns the TrieState for a given state root.
 // If no state root is provided, it returns the TrieState for the current chain head.
 func (s *InmemoryStorageState) TrieState(root *common.Hash) (*storage.TrieState, error) {
@@ -101,37 +147,52 @@ func (s *InmemoryStorageState) TrieState(root *common.Hash) (*storage.TrieState,
 		root = &sr
 	}
 
-	t := s.tries.get(*root)
-	if t == nil {
-		var err error
-		t, err = s.LoadFromDB(*root)
+	if s.tries.get(*root) == nil {
+		t, err := s.LoadFromDB(*root)
 		if err != nil {
 			return nil, fmt.Errorf("while loading from database: %w", err)
 		}
 
 		s.tries.softSet(*root, t)
-	} else if t.MustHash() != *root {
-		panic("trie does not have expected root")
 	}
 
-	// TODO: do we really need to create an snapshot here if TrieState handles
-	// the modifications?
-	nextTrie := t.(*inmemory_trie.InMemoryTrie).Snapshot()
-	next := storage.NewTrieState(nextTrie)
+	// the trie nodes are lazily loaded from the database so the
+	// memory used grows with the nodes accessed and modified.
+	next := storage.NewTrieState(s.newStateTrie(*root))
 
 	logger.Tracef("returning trie with root %s to be modified", root)
 	return next, nil
 }
 
-// LoadFromDB loads an encoded trie from the DB where the key is `root`
+// RecordingTrieState returns a TrieState for the given state root which records the
+// trie nodes and values accessed, such that they prove the keys read from the state.
+func (s *InmemoryStorageState) RecordingTrieState(root common.Hash, recorder triedb.TrieRecorder) (
+	*storage.TrieState, error) {
+	if root != trie.EmptyHash {
+		_, err := s.db.Get(root[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to find root key %s: %w", root, err)
+		}
+	}
+
+	return storage.NewTrieState(triedb.NewRecordingStateTrie(root, s.db, s.nodeCache, recorder)), nil
+}
+
+func (s *InmemoryStorageState) newStateTrie(root common.Hash) *triedb.StateTrie {
+	return triedb.NewStateTrie(root, s.db, s.nodeCache)
+}
+
+// LoadFromDB returns the trie with the given root, lazily loading its nodes from the DB
 func (s *InmemoryStorageState) LoadFromDB(root common.Hash) (trie.Trie, error) {
-	t := inmemory_trie.NewTrie(nil, s.db)
-	err := t.Load(s.db, root)
-	if err != nil {
-		return nil, err
+	if root != trie.EmptyHash {
+		_, err := s.db.Get(root[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to find root key %s: %w", root, err)
+		}
 	}
 
-	s.tries.softSet(t.MustHash(), t)
+	t := s.newStateTrie(root)
+	s.tries.softSet(root, t)
 	return t, nil
 }
 
@@ -145,16 +206,21 @@ func (s *InmemoryStorageState) loadTrie(root *common.Hash) (trie.Trie, error) {
 	}
 
 	t := s.tries.get(*root)
-	if t != nil {
-		return t, nil
+	if t == nil {
+		var err error
+		t, err = s.LoadFromDB(*root)
+		if err != nil {
+			return nil, fmt.Errorf("trie does not exist at root %s: %w", *root, err)
+		}
 	}
 
-	tr, err := s.LoadFromDB(*root)
-	if err != nil {
-		return nil, fmt.Errorf("trie does not exist at root %s: %w", *root, err)
+	// the lazily loaded tries cache their child tries and commit pending
+	// changes when read, so each caller gets its own instance and the
+	// shared one is never read concurrently.
+	if _, ok := t.(*triedb.StateTrie); ok {
+		return s.newStateTrie(*root), nil
 	}
-
-	return tr, nil
+	return t, nil
 }
 
 // ExistsStorage check if the key exists in the storage trie with the given storage hash
@@ -175,13 +241,12 @@ func (s *InmemoryStorageState) GetStorage(root *common.Hash, key []byte) ([]byte
 		root = &sr
 	}
 
-	t := s.tries.get(*root)
-	if t != nil {
-		val := t.Get(key)
-		return val, nil
+	t, err := s.loadTrie(root)
+	if err != nil {
+		return nil, err
 	}
 
-	return inmemory_trie.GetFromDB(s.db, *root, key)
+	return t.Get(key), nil
 }
 
 // GetStorageByBlockHash returns the value at the given key at the given block hash
@@ -269,6 +334,57 @@ func (s *InmemoryStorageState) GetStorageFromChild(root *common.Hash, keyToChild
 	return tr.GetFromChild(keyToChild, key)
 }
 
+// GetKeysWithPrefixAfter returns up to limit keys having the given prefix and greater than the
+// given key, in lexicographic order, from the trie with the given state root or from its child
+// trie at keyToChild if keyToChild is not nil
+func (s *InmemoryStorageState) GetKeysWithPrefixAfter(root *common.Hash, keyToChild, prefix, after []byte,
+	limit uint) ([][]byte, error) {
+	tr, err := s.loadTrieOrChild(root, keyToChild)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := tr.PrefixedKeys(prefix)
+	if after != nil && bytes.Compare(after, prefix) >= 0 {
+		keys = tr.KeysFrom(after)
+	}
+
+	var keysWithPrefix [][]byte
+	for key := range keys {
+		if uint(len(keysWithPrefix)) == limit || !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		keysWithPrefix = append(keysWithPrefix, key)
+	}
+	return keysWithPrefix, nil
+}
+
+// GetClosestDescendantMerkleValue returns the Merkle value of the closest trie node whose key
+// starts with the given key, from the trie with the given state root or from its child trie at
+// keyToChild if keyToChild is not nil. It returns nil if there is no such node.
+func (s *InmemoryStorageState) GetClosestDescendantMerkleValue(root *common.Hash, keyToChild,
+	key []byte) ([]byte, error) {
+	tr, err := s.loadTrieOrChild(root, keyToChild)
+	if err != nil {
+		return nil, err
+	}
+
+	merkleValuer, ok := tr.(closestDescendantMerkleValuer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", errClosestDescendantNotSupported, tr)
+	}
+	return merkleValuer.ClosestDescendantMerkleValue(key)
+}
+
+func (s *InmemoryStorageState) loadTrieOrChild(root *common.Hash, keyToChild []byte) (trie.Trie, error) {
+	tr, err := s.loadTrie(root)
+	if err != nil || keyToChild == nil {
+		return tr, err
+	}
+
+	return tr.GetChild(keyToChild)
+}
+
 // LoadCode returns the runtime code (located at :code)
 func (s *InmemoryStorageState) LoadCode(hash *common.Hash) ([]byte, error) {
 	return s.GetStorage(hash, codeKey)
@@ -4,15 +4,19 @@
 package state
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/database"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
 	"github.com/ChainSafe/gossamer/lib/common"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
 	"go.uber.org/mock/gomock"
 
 	"github.com/stretchr/testify/require"
@@ -103,6 +107,28 @@ func TestStorage_TrieState(t *testing.T) {
 	require.Equal(t, ts.Trie().MustHash(), ts3.Trie().MustHash())
 }
 
+func TestStorage_RecordingTrieState(t *testing.T) {
+	storage := newTestStorageState(t)
+	ts, err := storage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+	ts.Put([]byte("noot"), []byte("washere"))
+	ts.Put([]byte("noot2"), []byte("washere"))
+
+	root, err := ts.Trie().Hash()
+	require.NoError(t, err)
+	err = storage.StoreTrie(ts, nil)
+	require.NoError(t, err)
+
+	recorder := triedb.NewRecorder[chash.H256]()
+	recording, err := storage.RecordingTrieState(root, recorder)
+	require.NoError(t, err)
+	require.Equal(t, []byte("washere"), recording.Get([]byte("noot")))
+	require.NotEmpty(t, recorder.Drain())
+
+	_, err = storage.RecordingTrieState(common.Hash{1}, recorder)
+	require.ErrorIs(t, err, database.ErrNotFound)
+}
+
 func TestStorage_LoadFromDB(t *testing.T) {
 	storage := newTestStorageState(t)
 	ts, err := storage.TrieState(&trie.EmptyHash)
@@ -150,6 +176,38 @@ func TestStorage_LoadFromDB(t *testing.T) {
 	require.Equal(t, 5, len(entries))
 }
 
+func TestStorage_StoreTrieNodes(t *testing.T) {
+	storage := newTestStorageState(t)
+
+	tr := inmemory.NewEmptyTrie()
+	tr.SetVersion(trie.V1)
+	require.NoError(t, tr.Put([]byte("alpha"), make([]byte, 64)))
+	require.NoError(t, tr.Put([]byte("bravo"), []byte("value")))
+	root := tr.MustHash()
+
+	trieDB, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, trieDB.Close()) })
+	require.NoError(t, tr.WriteDirty(trieDB))
+
+	iter, err := trieDB.NewIterator()
+	require.NoError(t, err)
+	nodes := make(map[common.Hash][]byte)
+	for iter.First(); iter.Valid(); iter.Next() {
+		nodes[common.BytesToHash(iter.Key())] = bytes.Clone(iter.Value())
+	}
+	iter.Release()
+
+	err = storage.StoreTrieNodes(nodes)
+	require.NoError(t, err)
+
+	ts, err := storage.TrieState(&root)
+	require.NoError(t, err)
+	require.Equal(t, root, ts.Trie().MustHash())
+	require.Equal(t, make([]byte, 64), ts.Get([]byte("alpha")))
+	require.Equal(t, []byte("value"), ts.Get([]byte("bravo")))
+}
+
 func TestStorage_StoreTrie_NotSyncing(t *testing.T) {
 	storage := newTestStorageState(t)
 	ts, err := storage.TrieState(&trie.EmptyHash)
@@ -219,3 +277,38 @@ func TestGetStorageChildAndGetStorageFromChild(t *testing.T) {
 
 	require.Equal(t, []byte("voila"), value)
 }
+
+func TestStorage_GetKeysWithPrefixAfter(t *testing.T) {
+	storage := newTestStorageState(t)
+	ts, err := storage.TrieState(&trie.EmptyHash)
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "ab", "abc", "abd", "b"} {
+		require.NoError(t, ts.Put([]byte(key), []byte{1}))
+	}
+
+	root, err := ts.Trie().Hash()
+	require.NoError(t, err)
+	err = storage.StoreTrie(ts, nil)
+	require.NoError(t, err)
+
+	keys, err := storage.GetKeysWithPrefixAfter(&root, nil, []byte("ab"), nil, 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("ab"), []byte("abc")}, keys)
+
+	keys, err = storage.GetKeysWithPrefixAfter(&root, nil, []byte("ab"), []byte("abc"), 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{[]byte("abd")}, keys)
+
+	keys, err = storage.GetKeysWithPrefixAfter(&root, nil, []byte("a"), []byte("abd"), 10)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	merkleValue, err := storage.GetClosestDescendantMerkleValue(&root, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, root.ToBytes(), merkleValue)
+
+	merkleValue, err = storage.GetClosestDescendantMerkleValue(&root, nil, []byte("c"))
+	require.NoError(t, err)
+	require.Nil(t, merkleValue)
+}
@@ -32,7 +32,7 @@
 )
 
 // Tries is a thread safe map of root hash
-// to Trie. This structure is only used in combination with in-memory tries
+// to Trie, holding the tries of the blocks which are not finalised yet.
 type Tries struct {
 	rootToTrie    map[common.Hash]trie.Trie
 	mapMutex      sync.RWMutex
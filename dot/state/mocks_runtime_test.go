@@ -176,15 +176,18 @@ func (mr *MockInstanceMockRecorder) FinalizeBlock() *gomock.Call {
 }
 
 // GenerateSessionKeys mocks base method.
-func (m *MockInstance) GenerateSessionKeys() {
+func (m *MockInstance) GenerateSessionKeys(arg0 *[]byte) ([]byte, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "GenerateSessionKeys")
+	ret := m.ctrl.Call(m, "GenerateSessionKeys", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GenerateSessionKeys indicates an expected call of GenerateSessionKeys.
-func (mr *MockInstanceMockRecorder) GenerateSessionKeys() *gomock.Call {
+func (mr *MockInstanceMockRecorder) GenerateSessionKeys(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockInstance)(nil).GenerateSessionKeys))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateSessionKeys", reflect.TypeOf((*MockInstance)(nil).GenerateSessionKeys), arg0)
 }
 
 // GetCodeHash mocks base method.
@@ -332,15 +335,17 @@ func (mr *MockInstanceMockRecorder) NodeStorage() *gomock.Call {
 }
 
 // OffchainWorker mocks base method.
-func (m *MockInstance) OffchainWorker() {
+func (m *MockInstance) OffchainWorker(arg0 *types.Header) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "OffchainWorker")
+	ret := m.ctrl.Call(m, "OffchainWorker", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // OffchainWorker indicates an expected call of OffchainWorker.
-func (mr *MockInstanceMockRecorder) OffchainWorker() *gomock.Call {
+func (mr *MockInstanceMockRecorder) OffchainWorker(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainWorker", reflect.TypeOf((*MockInstance)(nil).OffchainWorker))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OffchainWorker", reflect.TypeOf((*MockInstance)(nil).OffchainWorker), arg0)
 }
 
 // PaymentQueryInfo mocks base method.
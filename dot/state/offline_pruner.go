@@ -121,10 +121,9 @@ func (p *OfflinePruner) SetBloomFilter() (err error) {
 
 	// loop from latest to last `retainBlockNum` blocks
 	for blockNum := header.Number; blockNum > 0 && blockNum >= latestBlockNum-uint(p.retainBlockNum); {
-		var tr *inmemory_trie.InMemoryTrie
-		loadedTrie, err := p.storageState.LoadFromDB(header.StateRoot)
-
-		tr = loadedTrie.(*inmemory_trie.InMemoryTrie)
+		// the whole trie is loaded to collect all its node hashes
+		tr := inmemory_trie.NewEmptyTrie()
+		err = tr.Load(p.storageState.db, header.StateRoot)
 		if err != nil {
 			return err
 		}
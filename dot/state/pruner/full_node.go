@@ -0,0 +1,507 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package pruner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+)
+
+var logger = log.NewFromGlobal(
+	log.AddContext("pkg", "pruner"),
+)
+
+var (
+	journalRecordPrefix = []byte("record")
+	blockHashesPrefix   = []byte("blocks")
+	canonicalPrefix     = []byte("canonical")
+	refCountPrefix      = []byte("refcount")
+	lastPrunedKey       = []byte("lastpruned")
+)
+
+// journalRecord holds the trie node hashes inserted and deleted by a block,
+// a node hash is listed once for every position it is inserted at or deleted from.
+type journalRecord struct {
+	BlockNumber        uint64
+	InsertedNodeHashes []common.Hash
+	DeletedNodeHashes  []common.Hash
+}
+
+// Database is the database used to store the journal and the state trie nodes
+type Database interface {
+	database.Reader
+	database.Writer
+	NewBatch() database.Batch
+}
+
+// FullNode prunes the state trie nodes of the finalised blocks older than the
+// retained blocks and of the blocks belonging to abandoned forks.
+//
+// A journal record is stored for every imported block with the node hashes it inserted
+// and deleted. Each node inserted is reference counted like the Substrate memory database:
+// it is referenced once for every position a journal record inserts it at, and these
+// references are kept by the canonical chain once the record is finalised and pruned.
+// A canonical reference of a node is released for every position the finalised block
+// deletes it from once its record is pruned, while the references held by the records
+// of abandoned forks are released when the fork is pruned.
+// A node is deleted from the database once it is not referenced anymore. The nodes written
+// before they were first journaled are not tracked and never deleted, since their number
+// of references is unknown.
+type FullNode struct {
+	mtx          sync.Mutex
+	journalDB    Database
+	nodesDB      Database
+	retainBlocks uint32
+}
+
+// NewFullNode creates a full node pruner storing its journal in journalDB and deleting
+// the state trie nodes from nodesDB.
+func NewFullNode(journalDB, nodesDB Database, retainBlocks uint32) *FullNode {
+	return &FullNode{
+		journalDB:    journalDB,
+		nodesDB:      nodesDB,
+		retainBlocks: retainBlocks,
+	}
+}
+
+// StoreJournalRecord stores the trie node hashes inserted and deleted by the block, given
+// the number of positions each of them is inserted at or deleted from, and references the
+// inserted nodes. It must be called before the inserted nodes are written to the database.
+func (p *FullNode) StoreJournalRecord(deletedNodeHashes, insertedNodeHashes map[common.Hash]uint32,
+	blockHash common.Hash, blockNum int64) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	has, err := p.journalDB.Has(journalRecordKey(blockHash))
+	if err != nil {
+		return fmt.Errorf("checking journal record for block %s: %w", blockHash, err)
+	}
+	if has {
+		return nil
+	}
+
+	// the insertions and deletions of a node by the block cancel each other out
+	record := journalRecord{BlockNumber: uint64(blockNum)}
+	inserted := make(map[common.Hash]uint32, len(insertedNodeHashes))
+	for nodeHash, count := range insertedNodeHashes {
+		deleted := deletedNodeHashes[nodeHash]
+		if count > deleted {
+			inserted[nodeHash] = count - deleted
+			record.InsertedNodeHashes = appendHash(record.InsertedNodeHashes, nodeHash, count-deleted)
+		}
+	}
+	for nodeHash, count := range deletedNodeHashes {
+		insertedCount := insertedNodeHashes[nodeHash]
+		if count > insertedCount {
+			record.DeletedNodeHashes = appendHash(record.DeletedNodeHashes, nodeHash, count-insertedCount)
+		}
+	}
+	sortHashes(record.InsertedNodeHashes)
+	sortHashes(record.DeletedNodeHashes)
+
+	batch := p.journalDB.NewBatch()
+	for nodeHash, count := range inserted {
+		refCount, tracked, err := p.refCount(nodeHash)
+		if err != nil {
+			return err
+		}
+
+		if !tracked {
+			// a node already written before being tracked is never deleted
+			// since its number of references is unknown
+			exists, err := p.nodesDB.Has(nodeHash[:])
+			if err != nil {
+				return fmt.Errorf("checking node %s: %w", nodeHash, err)
+			}
+			if exists {
+				continue
+			}
+		}
+
+		err = batch.Put(refCountKey(nodeHash), encodeUint32(refCount+count))
+		if err != nil {
+			return fmt.Errorf("referencing node %s: %w", nodeHash, err)
+		}
+	}
+
+	encodedRecord, err := scale.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encoding journal record: %w", err)
+	}
+
+	err = batch.Put(journalRecordKey(blockHash), encodedRecord)
+	if err != nil {
+		return fmt.Errorf("storing journal record: %w", err)
+	}
+
+	blockHashes, err := p.blockHashes(record.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	encodedHashes, err := scale.Marshal(append(blockHashes, blockHash))
+	if err != nil {
+		return fmt.Errorf("encoding block hashes: %w", err)
+	}
+
+	err = batch.Put(blockHashesKey(record.BlockNumber), encodedHashes)
+	if err != nil {
+		return fmt.Errorf("storing block hashes: %w", err)
+	}
+
+	_, err = p.lastPruned()
+	if errors.Is(err, database.ErrNotFound) && record.BlockNumber > 0 {
+		err = batch.Put(lastPrunedKey, encodeUint64(record.BlockNumber-1))
+	}
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("setting last pruned block number: %w", err)
+	}
+
+	return batch.Flush()
+}
+
+// PruneFinalised marks the given blocks as finalised and prunes the journal records of the
+// blocks older than the retained blocks from the finalised block number. The nodes deleted by
+// the finalised blocks and the nodes inserted by the other blocks are released.
+func (p *FullNode) PruneFinalised(finalisedHashes []common.Hash, finalisedNumber uint) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	batch := p.journalDB.NewBatch()
+	for _, blockHash := range finalisedHashes {
+		record, err := p.journalRecord(blockHash)
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		err = batch.Put(canonicalKey(record.BlockNumber), blockHash[:])
+		if err != nil {
+			return fmt.Errorf("marking block %s as finalised: %w", blockHash, err)
+		}
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing finalised blocks: %w", err)
+	}
+
+	if uint64(finalisedNumber) < uint64(p.retainBlocks) {
+		return nil
+	}
+	pruneTo := uint64(finalisedNumber) - uint64(p.retainBlocks)
+
+	lastPruned, err := p.lastPruned()
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("getting last pruned block number: %w", err)
+	}
+
+	for number := lastPruned + 1; number <= pruneTo; number++ {
+		err = p.pruneBlockNumber(number)
+		if err != nil {
+			return fmt.Errorf("pruning block number %d: %w", number, err)
+		}
+	}
+
+	return nil
+}
+
+// PruneAbandoned prunes the journal records of the given blocks, which belong to abandoned
+// forks, and releases the nodes they inserted.
+func (p *FullNode) PruneAbandoned(blockHashes []common.Hash) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	op := p.newPruneOperation()
+	for _, blockHash := range blockHashes {
+		record, err := p.journalRecord(blockHash)
+		if errors.Is(err, database.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		err = op.releaseAll(record.InsertedNodeHashes)
+		if err != nil {
+			return err
+		}
+
+		err = op.deleteRecord(blockHash, record.BlockNumber)
+		if err != nil {
+			return err
+		}
+	}
+
+	return op.flush()
+}
+
+func (p *FullNode) pruneBlockNumber(number uint64) error {
+	blockHashes, err := p.blockHashes(number)
+	if err != nil {
+		return err
+	}
+
+	encodedCanonicalHash, err := p.journalDB.Get(canonicalKey(number))
+	canonicalKnown := err == nil
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("getting finalised block: %w", err)
+	}
+	canonicalHash := common.BytesToHash(encodedCanonicalHash)
+
+	if !canonicalKnown && len(blockHashes) > 0 {
+		// without knowing which block was finalised, all the nodes are kept
+		logger.Debugf("no finalised block known at number %d, keeping its state", number)
+	}
+
+	op := p.newPruneOperation()
+	for _, blockHash := range blockHashes {
+		record, err := p.journalRecord(blockHash)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !canonicalKnown:
+		case blockHash == canonicalHash:
+			err = op.releaseAll(record.DeletedNodeHashes)
+		default:
+			err = op.releaseAll(record.InsertedNodeHashes)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = op.journalBatch.Del(journalRecordKey(blockHash))
+		if err != nil {
+			return fmt.Errorf("deleting journal record: %w", err)
+		}
+	}
+
+	err = op.journalBatch.Del(blockHashesKey(number))
+	if err != nil {
+		return fmt.Errorf("deleting block hashes: %w", err)
+	}
+
+	err = op.journalBatch.Del(canonicalKey(number))
+	if err != nil {
+		return fmt.Errorf("deleting finalised block: %w", err)
+	}
+
+	err = op.journalBatch.Put(lastPrunedKey, encodeUint64(number))
+	if err != nil {
+		return fmt.Errorf("setting last pruned block number: %w", err)
+	}
+
+	return op.flush()
+}
+
+func (p *FullNode) journalRecord(blockHash common.Hash) (record journalRecord, err error) {
+	encoded, err := p.journalDB.Get(journalRecordKey(blockHash))
+	if err != nil {
+		return record, fmt.Errorf("getting journal record of block %s: %w", blockHash, err)
+	}
+
+	err = scale.Unmarshal(encoded, &record)
+	if err != nil {
+		return record, fmt.Errorf("decoding journal record of block %s: %w", blockHash, err)
+	}
+	return record, nil
+}
+
+func (p *FullNode) blockHashes(number uint64) (blockHashes []common.Hash, err error) {
+	encoded, err := p.journalDB.Get(blockHashesKey(number))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting block hashes at number %d: %w", number, err)
+	}
+
+	err = scale.Unmarshal(encoded, &blockHashes)
+	if err != nil {
+		return nil, fmt.Errorf("decoding block hashes at number %d: %w", number, err)
+	}
+	return blockHashes, nil
+}
+
+func (p *FullNode) refCount(nodeHash common.Hash) (refCount uint32, tracked bool, err error) {
+	encoded, err := p.journalDB.Get(refCountKey(nodeHash))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, fmt.Errorf("getting reference count of node %s: %w", nodeHash, err)
+	}
+	return binary.LittleEndian.Uint32(encoded), true, nil
+}
+
+func (p *FullNode) lastPruned() (uint64, error) {
+	encoded, err := p.journalDB.Get(lastPrunedKey)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(encoded), nil
+}
+
+// pruneOperation accumulates the journal changes and the node deletions
+// so they are written at once
+type pruneOperation struct {
+	pruner       *FullNode
+	journalBatch database.Batch
+	nodesBatch   database.Batch
+	refCounts    map[common.Hash]uint32
+	blockHashes  map[uint64][]common.Hash
+}
+
+func (p *FullNode) newPruneOperation() *pruneOperation {
+	return &pruneOperation{
+		pruner:       p,
+		journalBatch: p.journalDB.NewBatch(),
+		nodesBatch:   p.nodesDB.NewBatch(),
+		refCounts:    make(map[common.Hash]uint32),
+		blockHashes:  make(map[uint64][]common.Hash),
+	}
+}
+
+// releaseAll releases a reference for each occurrence of a node, deleting the nodes
+// not referenced anymore. Untracked nodes are never deleted.
+func (op *pruneOperation) releaseAll(nodeHashes []common.Hash) error {
+	for _, nodeHash := range nodeHashes {
+		refCount, tracked := op.refCounts[nodeHash]
+		if !tracked {
+			var err error
+			refCount, tracked, err = op.pruner.refCount(nodeHash)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !tracked || refCount == 0 {
+			continue
+		}
+
+		if refCount > 1 {
+			op.refCounts[nodeHash] = refCount - 1
+			continue
+		}
+
+		op.refCounts[nodeHash] = 0
+		err := op.nodesBatch.Del(nodeHash[:])
+		if err != nil {
+			return fmt.Errorf("deleting node %s: %w", nodeHash, err)
+		}
+	}
+	return nil
+}
+
+func (op *pruneOperation) deleteRecord(blockHash common.Hash, blockNumber uint64) error {
+	err := op.journalBatch.Del(journalRecordKey(blockHash))
+	if err != nil {
+		return fmt.Errorf("deleting journal record: %w", err)
+	}
+
+	blockHashes, ok := op.blockHashes[blockNumber]
+	if !ok {
+		blockHashes, err = op.pruner.blockHashes(blockNumber)
+		if err != nil {
+			return err
+		}
+	}
+
+	remaining := make([]common.Hash, 0, len(blockHashes))
+	for _, hash := range blockHashes {
+		if hash != blockHash {
+			remaining = append(remaining, hash)
+		}
+	}
+	op.blockHashes[blockNumber] = remaining
+
+	if len(remaining) == 0 {
+		return op.journalBatch.Del(blockHashesKey(blockNumber))
+	}
+
+	encoded, err := scale.Marshal(remaining)
+	if err != nil {
+		return fmt.Errorf("encoding block hashes: %w", err)
+	}
+	return op.journalBatch.Put(blockHashesKey(blockNumber), encoded)
+}
+
+// flush writes the journal changes before deleting the nodes, so an interruption
+// can only leave unreferenced nodes in the database
+func (op *pruneOperation) flush() error {
+	for nodeHash, refCount := range op.refCounts {
+		var err error
+		if refCount == 0 {
+			err = op.journalBatch.Del(refCountKey(nodeHash))
+		} else {
+			err = op.journalBatch.Put(refCountKey(nodeHash), encodeUint32(refCount))
+		}
+		if err != nil {
+			return fmt.Errorf("updating reference count of node %s: %w", nodeHash, err)
+		}
+	}
+
+	err := op.journalBatch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing journal: %w", err)
+	}
+
+	err = op.nodesBatch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing deleted nodes: %w", err)
+	}
+	return nil
+}
+
+func journalRecordKey(blockHash common.Hash) []byte {
+	return append(bytes.Clone(journalRecordPrefix), blockHash[:]...)
+}
+
+func blockHashesKey(number uint64) []byte {
+	return append(bytes.Clone(blockHashesPrefix), encodeUint64(number)...)
+}
+
+func canonicalKey(number uint64) []byte {
+	return append(bytes.Clone(canonicalPrefix), encodeUint64(number)...)
+}
+
+func refCountKey(nodeHash common.Hash) []byte {
+	return append(bytes.Clone(refCountPrefix), nodeHash[:]...)
+}
+
+func encodeUint64(n uint64) []byte {
+	encoded := make([]byte, 8)
+	binary.BigEndian.PutUint64(encoded, n)
+	return encoded
+}
+
+func encodeUint32(n uint32) []byte {
+	encoded := make([]byte, 4)
+	binary.LittleEndian.PutUint32(encoded, n)
+	return encoded
+}
+
+func appendHash(hashes []common.Hash, hash common.Hash, count uint32) []common.Hash {
+	for ; count > 0; count-- {
+		hashes = append(hashes, hash)
+	}
+	return hashes
+}
+
+func sortHashes(hashes []common.Hash) {
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+}
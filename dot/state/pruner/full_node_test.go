@@ -0,0 +1,177 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package pruner
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFullNode(t *testing.T, retainBlocks uint32) (*FullNode, database.Table) {
+	t.Helper()
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	nodesDB := database.NewTable(db, "storage")
+	return NewFullNode(database.NewTable(db, "pruner"), nodesDB, retainBlocks), nodesDB
+}
+
+// hashCounts counts the occurrences of each hash
+func hashCounts(hashes ...common.Hash) map[common.Hash]uint32 {
+	counts := make(map[common.Hash]uint32, len(hashes))
+	for _, hash := range hashes {
+		counts[hash]++
+	}
+	return counts
+}
+
+// importBlock stores the journal record of the block and then writes the inserted nodes,
+// a node given several times is inserted or deleted at as many positions.
+func importBlock(t *testing.T, pruner *FullNode, nodesDB database.Table, blockHash common.Hash,
+	number int64, inserted, deleted []common.Hash) {
+	t.Helper()
+
+	require.NoError(t, pruner.StoreJournalRecord(hashCounts(deleted...), hashCounts(inserted...), blockHash, number))
+	for _, nodeHash := range inserted {
+		require.NoError(t, nodesDB.Put(nodeHash[:], []byte("node")))
+	}
+}
+
+func requireNodes(t *testing.T, nodesDB database.Table, exist bool, nodeHashes ...common.Hash) {
+	t.Helper()
+
+	for _, nodeHash := range nodeHashes {
+		has, err := nodesDB.Has(nodeHash[:])
+		require.NoError(t, err)
+		require.Equalf(t, exist, has, "node %s", nodeHash)
+	}
+}
+
+func TestFullNode(t *testing.T) {
+	t.Parallel()
+
+	genesisNode := common.Hash{0xff}
+	nodeA, nodeB, nodeC, nodeD := common.Hash{0xa}, common.Hash{0xb}, common.Hash{0xc}, common.Hash{0xd}
+	block1, block2, fork2, block3 := common.Hash{1}, common.Hash{2}, common.Hash{0x22}, common.Hash{3}
+
+	t.Run("finalised_blocks_older_than_retained_blocks", func(t *testing.T) {
+		t.Parallel()
+
+		pruner, nodesDB := newTestFullNode(t, 1)
+		require.NoError(t, nodesDB.Put(genesisNode[:], []byte("node")))
+
+		importBlock(t, pruner, nodesDB, block1, 1, []common.Hash{nodeA}, []common.Hash{genesisNode})
+		importBlock(t, pruner, nodesDB, block2, 2, []common.Hash{nodeB}, []common.Hash{nodeA})
+		importBlock(t, pruner, nodesDB, block3, 3, []common.Hash{nodeC}, []common.Hash{nodeB})
+
+		// the state of block 1 is retained, and the genesis node
+		// written before the journal started is never deleted
+		require.NoError(t, pruner.PruneFinalised([]common.Hash{block1, block2}, 2))
+		requireNodes(t, nodesDB, true, genesisNode, nodeA, nodeB, nodeC)
+
+		require.NoError(t, pruner.PruneFinalised([]common.Hash{block3}, 3))
+		requireNodes(t, nodesDB, false, nodeA)
+		requireNodes(t, nodesDB, true, nodeB, nodeC)
+
+		_, err := pruner.journalRecord(block2)
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("abandoned_fork", func(t *testing.T) {
+		t.Parallel()
+
+		pruner, nodesDB := newTestFullNode(t, 0)
+		importBlock(t, pruner, nodesDB, block1, 1, []common.Hash{nodeA}, nil)
+		importBlock(t, pruner, nodesDB, block2, 2, []common.Hash{nodeB, nodeD}, []common.Hash{nodeA})
+		// the fork shares nodeD with block 2
+		importBlock(t, pruner, nodesDB, fork2, 2, []common.Hash{nodeC, nodeD}, []common.Hash{nodeA})
+
+		require.NoError(t, pruner.PruneAbandoned([]common.Hash{fork2}))
+		requireNodes(t, nodesDB, false, nodeC)
+		requireNodes(t, nodesDB, true, nodeA, nodeB, nodeD)
+
+		blockHashes, err := pruner.blockHashes(2)
+		require.NoError(t, err)
+		require.Equal(t, []common.Hash{block2}, blockHashes)
+
+		require.NoError(t, pruner.PruneFinalised([]common.Hash{block1, block2}, 2))
+		requireNodes(t, nodesDB, false, nodeA)
+		requireNodes(t, nodesDB, true, nodeB, nodeD)
+	})
+
+	t.Run("fork_left_at_pruned_number", func(t *testing.T) {
+		t.Parallel()
+
+		pruner, nodesDB := newTestFullNode(t, 0)
+		importBlock(t, pruner, nodesDB, block1, 1, []common.Hash{nodeA}, nil)
+		importBlock(t, pruner, nodesDB, block2, 2, []common.Hash{nodeB}, nil)
+		importBlock(t, pruner, nodesDB, fork2, 2, []common.Hash{nodeC}, nil)
+
+		require.NoError(t, pruner.PruneFinalised([]common.Hash{block1, block2}, 2))
+		requireNodes(t, nodesDB, false, nodeC)
+		requireNodes(t, nodesDB, true, nodeA, nodeB)
+	})
+
+	t.Run("node_inserted_again", func(t *testing.T) {
+		t.Parallel()
+
+		pruner, nodesDB := newTestFullNode(t, 0)
+		require.NoError(t, nodesDB.Put(genesisNode[:], []byte("node")))
+
+		importBlock(t, pruner, nodesDB, block1, 1, []common.Hash{nodeA}, []common.Hash{genesisNode})
+		// the untracked genesis node is inserted again by block 2
+		importBlock(t, pruner, nodesDB, block2, 2, []common.Hash{genesisNode}, []common.Hash{nodeA})
+
+		require.NoError(t, pruner.PruneFinalised([]common.Hash{block1, block2}, 2))
+		requireNodes(t, nodesDB, true, genesisNode)
+		requireNodes(t, nodesDB, false, nodeA)
+	})
+
+	t.Run("node_shared_by_several_positions", func(t *testing.T) {
+		t.Parallel()
+
+		pruner, nodesDB := newTestFullNode(t, 0)
+		// two keys of block 1 share the value nodeA
+		importBlock(t, pruner, nodesDB, block1, 1, []common.Hash{nodeA, nodeA, nodeB}, nil)
+		// block 2 deletes one of the two keys
+		importBlock(t, pruner, nodesDB, block2, 2, []common.Hash{nodeC}, []common.Hash{nodeA, nodeB})
+		// the fork inserts nodeA at another position and is abandoned
+		importBlock(t, pruner, nodesDB, fork2, 2, []common.Hash{nodeA}, nil)
+
+		require.NoError(t, pruner.PruneAbandoned([]common.Hash{fork2}))
+		require.NoError(t, pruner.PruneFinalised([]common.Hash{block1, block2}, 2))
+		requireNodes(t, nodesDB, false, nodeB)
+		requireNodes(t, nodesDB, true, nodeA, nodeC)
+
+		// block 3 deletes the other key
+		importBlock(t, pruner, nodesDB, block3, 3, nil, []common.Hash{nodeA})
+		require.NoError(t, pruner.PruneFinalised([]common.Hash{block3}, 3))
+		requireNodes(t, nodesDB, false, nodeA)
+		requireNodes(t, nodesDB, true, nodeC)
+	})
+
+	t.Run("unknown_finalised_block_keeps_state", func(t *testing.T) {
+		t.Parallel()
+
+		pruner, nodesDB := newTestFullNode(t, 0)
+		importBlock(t, pruner, nodesDB, block1, 1, []common.Hash{nodeA}, nil)
+		importBlock(t, pruner, nodesDB, block2, 2, []common.Hash{nodeB}, []common.Hash{nodeA})
+
+		require.NoError(t, pruner.PruneFinalised(nil, 2))
+		requireNodes(t, nodesDB, true, nodeA, nodeB)
+	})
+}
+
+func TestMode_IsValid(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, Archive.IsValid())
+	require.True(t, Full.IsValid())
+	require.False(t, Mode("light").IsValid())
+}
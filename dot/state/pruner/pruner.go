@@ -10,6 +10,8 @@
 const (
 	// Archive pruner mode.
 	Archive = Mode("archive")
+	// Full pruner mode.
+	Full = Mode("full")
 )
 
 // Mode online pruning mode of historical state tries
@@ -18,7 +20,7 @@
 // IsValid checks whether the pruning mode is valid
 func (p Mode) IsValid() bool {
 	switch p {
-	case Archive:
+	case Archive, Full:
 		return true
 	default:
 		return false
@@ -33,15 +35,27 @@ type Config struct {
 
 // Pruner is implemented by FullNode and ArchiveNode.
 type Pruner interface {
-	StoreJournalRecord(deletedNodeHashes, insertedNodeHashes map[common.Hash]struct{},
+	StoreJournalRecord(deletedNodeHashes, insertedNodeHashes map[common.Hash]uint32,
 		blockHash common.Hash, blockNum int64) error
+	PruneFinalised(finalisedHashes []common.Hash, finalisedNumber uint) error
+	PruneAbandoned(blockHashes []common.Hash) error
 }
 
 // ArchiveNode is a no-op since we don't prune nodes in archive mode.
 type ArchiveNode struct{}
 
 // StoreJournalRecord for archive node doesn't do anything.
-func (*ArchiveNode) StoreJournalRecord(_, _ map[common.Hash]struct{},
+func (*ArchiveNode) StoreJournalRecord(_, _ map[common.Hash]uint32,
 	_ common.Hash, _ int64) error {
 	return nil
 }
+
+// PruneFinalised for archive node doesn't do anything.
+func (*ArchiveNode) PruneFinalised(_ []common.Hash, _ uint) error {
+	return nil
+}
+
+// PruneAbandoned for archive node doesn't do anything.
+func (*ArchiveNode) PruneAbandoned(_ []common.Hash) error {
+	return nil
+}
@@ -13,6 +13,9 @@
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/internal/metrics"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	wazero_runtime "github.com/ChainSafe/gossamer/lib/runtime/wazero"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 )
@@ -37,8 +40,11 @@ type Service struct {
 	closeCh           chan interface{}
 	genesisBABEConfig *types.BabeConfiguration
 
-	PrunerCfg pruner.Config
-	Telemetry Telemetry
+	PrunerCfg            pruner.Config
+	TransactionRetention uint32
+	Telemetry            Telemetry
+	CompilationCache     *wazero_runtime.CompilationCache
+	LogFilter            *runtime.LogFilter
 
 	// Below are for testing only.
 	BabeThresholdNumerator   uint64
@@ -58,6 +64,15 @@ type Config struct {
 	Telemetry         Telemetry
 	Metrics           metrics.IntervalConfig
 	GenesisBABEConfig *types.BabeConfiguration
+	// TransactionRetention is the number of finalised blocks for which
+	// the indexed transactions are kept, zero keeps them forever.
+	TransactionRetention uint32
+	// CompilationCache is the cache the runtime codes are compiled in,
+	// they are compiled in memory if it is nil.
+	CompilationCache *wazero_runtime.CompilationCache
+	// LogFilter filters the messages logged by the runtime instances, they are
+	// filtered by the log level of each instance if it is nil.
+	LogFilter *runtime.LogFilter
 }
 
 // NewService create a new instance of Service
@@ -65,16 +80,19 @@ func NewService(config Config) *Service {
 	logger.Patch(log.SetLevel(config.LogLevel))
 
 	return &Service{
-		dbPath:            config.Path,
-		logLvl:            config.LogLevel,
-		db:                nil,
-		isMemDB:           false,
-		Storage:           nil,
-		Block:             nil,
-		closeCh:           make(chan interface{}),
-		PrunerCfg:         config.PrunerCfg,
-		Telemetry:         config.Telemetry,
-		genesisBABEConfig: config.GenesisBABEConfig,
+		dbPath:               config.Path,
+		logLvl:               config.LogLevel,
+		db:                   nil,
+		isMemDB:              false,
+		Storage:              nil,
+		Block:                nil,
+		closeCh:              make(chan interface{}),
+		PrunerCfg:            config.PrunerCfg,
+		TransactionRetention: config.TransactionRetention,
+		Telemetry:            config.Telemetry,
+		CompilationCache:     config.CompilationCache,
+		LogFilter:            config.LogFilter,
+		genesisBABEConfig:    config.GenesisBABEConfig,
 	}
 }
 
@@ -128,6 +146,9 @@ func (s *Service) Start() (err error) {
 	if err != nil {
 		return fmt.Errorf("failed to create block state: %w", err)
 	}
+	s.Block.transactionRetention = s.TransactionRetention
+	s.Block.compilationCache = s.CompilationCache
+	s.Block.logFilter = s.LogFilter
 
 	// retrieve latest header
 	bestHeader, err := s.Block.GetHighestFinalisedHeader()
@@ -144,6 +165,20 @@ func (s *Service) Start() (err error) {
 		return fmt.Errorf("failed to create storage state: %w", err)
 	}
 
+	if s.PrunerCfg.Mode == pruner.Full {
+		statePruner := pruner.NewFullNode(database.NewTable(s.db, prunerPrefix),
+			database.NewTable(s.db, storagePrefix), s.PrunerCfg.RetainedBlocks)
+		s.Block.pruner = statePruner
+		s.Storage.pruner = statePruner
+		logger.Infof("state pruning enabled, retaining the state of the last %d finalised blocks",
+			s.PrunerCfg.RetainedBlocks)
+	}
+
+	err = s.migrateHashedValueKeys()
+	if err != nil {
+		return fmt.Errorf("migrating hashed storage values: %w", err)
+	}
+
 	// load current storage state trie into memory
 	_, err = s.Storage.LoadFromDB(stateRoot)
 	if err != nil {
@@ -170,6 +205,59 @@ func (s *Service) Start() (err error) {
 	return nil
 }
 
+// migrateHashedValueKeys moves the hashed storage values of the finalised and unfinalised block
+// states, which used to be stored at the key prefixed with the partial key of their node, to the
+// key made of their hash only that the lazily loaded tries look them up at. The older states are
+// not migrated, such that the migration only holds the nodes of the states the node serves.
+// It only runs once per database.
+func (s *Service) migrateHashedValueKeys() error {
+	migrated, err := s.db.Has(common.HashedValueKeysMigratedKey)
+	if err != nil {
+		return fmt.Errorf("checking migration flag: %w", err)
+	}
+	if migrated {
+		return nil
+	}
+
+	finalised, err := s.Block.GetHighestFinalisedHeader()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	stateRoots := []common.Hash{finalised.StateRoot}
+	for _, hash := range s.Block.GetNonFinalisedBlocks() {
+		header, err := s.Block.GetHeader(hash)
+		if err != nil {
+			return fmt.Errorf("getting header of block %s: %w", hash, err)
+		}
+		stateRoots = append(stateRoots, header.StateRoot)
+	}
+
+	logger.Infof("migrating the hashed storage values of %d block states...", len(stateRoots))
+	storageTable := database.NewTable(s.db, storagePrefix)
+	visited := make(map[common.Hash]struct{})
+	var moved uint
+	for _, stateRoot := range stateRoots {
+		// the states pruned from the database are skipped
+		has, err := storageTable.Has(stateRoot[:])
+		if err != nil {
+			return fmt.Errorf("checking state root %s: %w", stateRoot, err)
+		}
+		if !has {
+			continue
+		}
+
+		stateMoved, err := inmemory_trie.MigrateHashedValueKeys(storageTable, stateRoot, visited)
+		if err != nil {
+			return fmt.Errorf("migrating state with root %s: %w", stateRoot, err)
+		}
+		moved += stateMoved
+	}
+	logger.Infof("migrated %d hashed storage values", moved)
+
+	return s.db.Put(common.HashedValueKeysMigratedKey, []byte{1})
+}
+
 // Rewind rewinds the chain to the given block number.
 // If the given number of blocks is greater than the chain height, it will rewind to genesis.
 func (s *Service) Rewind(toBlock uint) error {
@@ -14,110 +14,195 @@
 	"github.com/ChainSafe/gossamer/lib/transaction"
 )
 
-// TransactionState represents the queue of transactions
+// TransactionState represents the pool of transactions, ordered by the tags they
+// require and provide
 type TransactionState struct {
-	queue *transaction.PriorityQueue
-	pool  *transaction.Pool
+	pool *transaction.Graph
 
 	// notifierChannels are used to notify transaction status. It maps a channel to
 	// hex string of the extrinsic it is supposed to notify about.
-	notifierChannels map[chan transaction.Status]string
+	notifierChannels map[chan transaction.StatusNotification]string
 	notifierLock     sync.RWMutex
 
+	// pushNotifierChannels are notified when a transaction is added to the pool
+	// while transactions can be included in the next block
+	pushNotifierChannels map[chan struct{}]struct{}
+	pushNotifierLock     sync.RWMutex
+
 	telemetry Telemetry
 }
 
 // NewTransactionState returns a new TransactionState
 func NewTransactionState(telemetry Telemetry) *TransactionState {
-	return &TransactionState{
-		queue:            transaction.NewPriorityQueue(),
-		pool:             transaction.NewPool(),
-		notifierChannels: make(map[chan transaction.Status]string),
-		telemetry:        telemetry,
+	s := &TransactionState{
+		notifierChannels:     make(map[chan transaction.StatusNotification]string),
+		pushNotifierChannels: make(map[chan struct{}]struct{}),
+		telemetry:            telemetry,
 	}
+	s.pool = transaction.NewGraph(s.NotifyStatus)
+	return s
 }
 
-// Push pushes a transaction to the queue, ordered by priority
+// Push adds a transaction back to the pool, for instance when it could not be included in a block
 func (s *TransactionState) Push(vt *transaction.ValidTransaction) (common.Hash, error) {
-	s.notifyStatus(vt.Extrinsic, transaction.Ready)
-	return s.queue.Push(vt)
+	hash, err := s.pool.Import(vt)
+	if err != nil {
+		return hash, err
+	}
+
+	s.notifyPush()
+	return hash, nil
 }
 
-// Pop removes and returns the head of the queue
+// Pop removes and returns the ready transaction with the highest priority
+// which can be included in the next block
 func (s *TransactionState) Pop() *transaction.ValidTransaction {
-	return s.queue.Pop()
+	return s.pool.Pop()
 }
 
 // PopWithTimer returns the next valid transaction from the queue.
 // When the timer expires, it returns `nil`.
 func (s *TransactionState) PopWithTimer(timerCh <-chan time.Time) (transaction *transaction.ValidTransaction) {
-	return s.queue.PopWithTimer(timerCh)
+	return s.pool.PopWithTimer(timerCh)
 }
 
-// Peek returns the head of the queue without removing it
+// Peek returns the next transaction to be popped without removing it
 func (s *TransactionState) Peek() *transaction.ValidTransaction {
-	return s.queue.Peek()
+	return s.pool.Peek()
 }
 
-// Pending returns the current transactions in the queue and pool
+// Pending returns the current transactions in the pool, ready and future
 func (s *TransactionState) Pending() []*transaction.ValidTransaction {
-	return append(s.queue.Pending(), s.pool.Transactions()...)
+	return s.pool.Pending()
+}
+
+// Ready returns the current ready transactions in the pool
+func (s *TransactionState) Ready() []*transaction.ValidTransaction {
+	return s.pool.Ready()
+}
+
+// Future returns the current transactions in the pool waiting for the tags they require
+func (s *TransactionState) Future() []*transaction.ValidTransaction {
+	return s.pool.Future()
 }
 
-// PendingInPool returns the current transactions in the pool
-func (s *TransactionState) PendingInPool() []*transaction.ValidTransaction {
-	return s.pool.Transactions()
+// Oldest returns at most limit transactions of the pool, by order of their last validation
+func (s *TransactionState) Oldest(limit int) []*transaction.ValidTransaction {
+	return s.pool.Oldest(limit)
 }
 
-// Exists returns true if an extrinsic is already in the pool or queue, false otherwise
+// Exists returns true if an extrinsic is already in the pool, false otherwise
 func (s *TransactionState) Exists(ext types.Extrinsic) bool {
-	hash := ext.Hash()
-	return s.pool.Get(hash) != nil || s.queue.Exists(hash)
+	return s.pool.Get(ext.Hash()) != nil
 }
 
-// RemoveExtrinsic removes an extrinsic from the queue and pool
+// RemoveExtrinsic removes an extrinsic included in a block from the pool. The tags it
+// provides are considered provided for the transactions depending on it.
 func (s *TransactionState) RemoveExtrinsic(ext types.Extrinsic) {
-	s.pool.Remove(ext.Hash())
-	s.queue.RemoveExtrinsic(ext)
+	s.pool.Prune(ext.Hash())
 }
 
-// RemoveExtrinsicFromPool removes an extrinsic from the pool
-func (s *TransactionState) RemoveExtrinsicFromPool(ext types.Extrinsic) {
-	s.pool.Remove(ext.Hash())
-}
+// RemoveExtrinsicByHash removes the extrinsic with the given hash from the pool and
+// notifies its watchers that it is invalid. It returns false if the extrinsic is not in the pool.
+func (s *TransactionState) RemoveExtrinsicByHash(hash common.Hash) (removed bool) {
+	tx := s.pool.Remove(hash)
+	if tx == nil {
+		return false
+	}
 
-// AddToPool adds a transaction to the pool
-func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) common.Hash {
-	s.notifyStatus(vt.Extrinsic, transaction.Future)
+	s.NotifyStatus(tx.Extrinsic, transaction.StatusNotification{Status: transaction.Invalid})
+	return true
+}
 
-	hash := s.pool.Insert(vt)
+// AddToPool adds a transaction to the pool, as ready if the tags it requires are provided
+// and as future otherwise.
+func (s *TransactionState) AddToPool(vt *transaction.ValidTransaction) (common.Hash, error) {
+	hash, err := s.pool.Import(vt)
+	if err != nil {
+		return hash, err
+	}
 
+	futureCount := len(s.pool.Future())
 	s.telemetry.SendMessage(
-		telemetry.NewTxpoolImport(uint(s.queue.Len()), uint(s.pool.Len())),
+		telemetry.NewTxpoolImport(uint(s.pool.Len()-futureCount), uint(futureCount)),
 	)
 
-	return hash
+	s.notifyPush()
+	return hash, nil
+}
+
+// UpdateValidity sets the validity of a transaction of the pool revalidated for the
+// current best block. It returns false if the transaction is not in the pool.
+func (s *TransactionState) UpdateValidity(hash common.Hash, validity *transaction.Validity) (updated bool) {
+	updated = s.pool.Update(hash, validity)
+	if updated {
+		s.notifyPush()
+	}
+	return updated
+}
+
+// SetBestBlockNumber sets the number of the best block, which the transactions added to the
+// pool are validated for. Transactions whose longevity is exceeded are dropped from the pool.
+func (s *TransactionState) SetBestBlockNumber(blockNumber uint) {
+	s.pool.SetBlockNumber(blockNumber)
 }
 
 // GetStatusNotifierChannel creates and returns a status notifier channel.
-func (s *TransactionState) GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.Status {
+func (s *TransactionState) GetStatusNotifierChannel(ext types.Extrinsic) chan transaction.StatusNotification {
 	s.notifierLock.Lock()
 	defer s.notifierLock.Unlock()
 
-	ch := make(chan transaction.Status, defaultBufferSize)
+	ch := make(chan transaction.StatusNotification, defaultBufferSize)
 	s.notifierChannels[ch] = ext.String()
 	return ch
 }
 
 // FreeStatusNotifierChannel deletes given status notifier channel from our map.
-func (s *TransactionState) FreeStatusNotifierChannel(ch chan transaction.Status) {
+func (s *TransactionState) FreeStatusNotifierChannel(ch chan transaction.StatusNotification) {
 	s.notifierLock.Lock()
 	defer s.notifierLock.Unlock()
 
 	delete(s.notifierChannels, ch)
 }
 
-func (s *TransactionState) notifyStatus(ext types.Extrinsic, status transaction.Status) {
+// GetPushNotifierChannel creates and returns a channel notified when a transaction is added
+// to the pool while transactions can be included in the next block. Notifications are dropped
+// while a previous one is still pending.
+func (s *TransactionState) GetPushNotifierChannel() chan struct{} {
+	s.pushNotifierLock.Lock()
+	defer s.pushNotifierLock.Unlock()
+
+	ch := make(chan struct{}, 1)
+	s.pushNotifierChannels[ch] = struct{}{}
+	return ch
+}
+
+// FreePushNotifierChannel deletes given push notifier channel from our map.
+func (s *TransactionState) FreePushNotifierChannel(ch chan struct{}) {
+	s.pushNotifierLock.Lock()
+	defer s.pushNotifierLock.Unlock()
+
+	delete(s.pushNotifierChannels, ch)
+}
+
+func (s *TransactionState) notifyPush() {
+	if s.pool.Peek() == nil {
+		return
+	}
+
+	s.pushNotifierLock.RLock()
+	defer s.pushNotifierLock.RUnlock()
+
+	for ch := range s.pushNotifierChannels {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// NotifyStatus notifies the watchers of the extrinsic of a status update
+func (s *TransactionState) NotifyStatus(ext types.Extrinsic, notification transaction.StatusNotification) {
 	s.notifierLock.Lock()
 	defer s.notifierLock.Unlock()
 
@@ -131,11 +216,11 @@ func (s *TransactionState) notifyStatus(ext types.Extrinsic, status transaction.
 			continue
 		}
 		wg.Add(1)
-		go func(ch chan transaction.Status) {
+		go func(ch chan transaction.StatusNotification) {
 			defer wg.Done()
 
 			select {
-			case ch <- status:
+			case ch <- notification:
 			default:
 			}
 		}(ch)
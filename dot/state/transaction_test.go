@@ -4,13 +4,13 @@
 package state
 
 import (
+	"math"
 	"math/rand"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/ChainSafe/gossamer/dot/types"
-	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/transaction"
 	"go.uber.org/mock/gomock"
 
@@ -47,28 +47,58 @@ func TestTransactionState_Pending(t *testing.T) {
 		},
 	}
 
-	hashes := make([]common.Hash, len(txs))
-	for i, tx := range txs {
-		h := ts.AddToPool(tx)
-		hashes[i] = h
+	for _, tx := range txs {
+		_, err := ts.AddToPool(tx)
+		require.NoError(t, err)
 	}
 
-	pendingPool := ts.PendingInPool()
-
-	sort.Slice(pendingPool, func(i, j int) bool {
-		return pendingPool[i].Extrinsic[0] < pendingPool[j].Extrinsic[0]
-	})
-	require.Equal(t, pendingPool, txs)
-
 	pending := ts.Pending()
 	sort.Slice(pending, func(i, j int) bool {
 		return pending[i].Extrinsic[0] < pending[j].Extrinsic[0]
 	})
 	require.Equal(t, pending, txs)
 
-	// queue should be empty
+	ready := ts.Ready()
+	sort.Slice(ready, func(i, j int) bool {
+		return ready[i].Extrinsic[0] < ready[j].Extrinsic[0]
+	})
+	require.Equal(t, ready, txs)
+	require.Empty(t, ts.Future())
+
+	// the transaction with the highest priority is popped first
 	head := ts.Peek()
-	require.Nil(t, head)
+	require.Equal(t, txs[3], head)
+}
+
+func TestTransactionState_Dependencies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).Times(2)
+
+	ts := NewTransactionState(telemetryMock)
+
+	second := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic{2},
+		Validity:  transaction.NewValidity(2, [][]byte{{1}}, [][]byte{{2}}, math.MaxUint64, true),
+	}
+	first := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic{1},
+		Validity:  transaction.NewValidity(1, nil, [][]byte{{1}}, math.MaxUint64, true),
+	}
+
+	_, err := ts.AddToPool(second)
+	require.NoError(t, err)
+	require.Equal(t, []*transaction.ValidTransaction{second}, ts.Future())
+	require.Nil(t, ts.Peek())
+
+	_, err = ts.AddToPool(first)
+	require.NoError(t, err)
+	require.Empty(t, ts.Future())
+
+	// the second transaction is only popped after the transaction it depends on
+	require.Equal(t, first, ts.Pop())
+	require.Equal(t, second, ts.Pop())
+	require.Nil(t, ts.Pop())
 }
 
 func TestTransactionState_NotifierChannels(t *testing.T) {
@@ -94,28 +124,32 @@ func TestTransactionState_NotifierChannels(t *testing.T) {
 	expectedFutureCount := rand.Intn(10) + 10
 	expectedReadyCount := rand.Intn(5) + 5
 
-	dummyTransactions := make([]*transaction.ValidTransaction, expectedFutureCount)
-
 	for i := 0; i < expectedFutureCount; i++ {
-		dummyTransactions[i] = &transaction.ValidTransaction{
+		// each transaction requires a tag no transaction provides
+		_, err := ts.AddToPool(&transaction.ValidTransaction{
 			Extrinsic: ext,
-			Validity:  transaction.NewValidity(0, [][]byte{{}}, [][]byte{{}}, 0, false),
-		}
-
-		ts.AddToPool(dummyTransactions[i])
+			Validity:  transaction.NewValidity(0, [][]byte{{byte(i)}}, nil, math.MaxUint64, false),
+		})
+		require.NoError(t, err)
+		ts.RemoveExtrinsic(ext)
 	}
 
 	for i := 0; i < expectedReadyCount; i++ {
-		ts.Push(dummyTransactions[i])
+		_, err := ts.Push(&transaction.ValidTransaction{
+			Extrinsic: ext,
+			Validity:  transaction.NewValidity(0, nil, nil, math.MaxUint64, false),
+		})
+		require.NoError(t, err)
+		ts.RemoveExtrinsic(ext)
 	}
 
 	close(notifierChannel)
 
-	for status := range notifierChannel {
-		if status.String() == transaction.Future.String() {
+	for notification := range notifierChannel {
+		if notification.Status == transaction.Future {
 			futureCount++
 		}
-		if status.String() == transaction.Ready.String() {
+		if notification.Status == transaction.Ready {
 			readyCount++
 		}
 	}
@@ -123,3 +157,66 @@ func TestTransactionState_NotifierChannels(t *testing.T) {
 	require.Equal(t, expectedFutureCount, futureCount)
 	require.Equal(t, expectedReadyCount, readyCount)
 }
+
+func TestTransactionState_PushNotifierChannel(t *testing.T) {
+	ts := NewTransactionState(nil)
+
+	pushNotifierChannel := ts.GetPushNotifierChannel()
+
+	vt := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic{1},
+		Validity:  transaction.NewValidity(0, nil, nil, math.MaxUint64, false),
+	}
+	_, err := ts.Push(vt)
+	require.NoError(t, err)
+
+	// a second push does not block on the pending notification
+	_, err = ts.Push(&transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic{2},
+		Validity:  transaction.NewValidity(0, nil, nil, math.MaxUint64, false),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, pushNotifierChannel, 1)
+	<-pushNotifierChannel
+
+	ts.FreePushNotifierChannel(pushNotifierChannel)
+	_, err = ts.Push(&transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic{3},
+		Validity:  transaction.NewValidity(0, nil, nil, math.MaxUint64, false),
+	})
+	require.NoError(t, err)
+	require.Empty(t, pushNotifierChannel)
+}
+
+func TestTransactionState_RemoveExtrinsicByHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any())
+
+	ts := NewTransactionState(telemetryMock)
+
+	queued := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic{1},
+		Validity:  transaction.NewValidity(0, nil, nil, math.MaxUint64, false),
+	}
+	_, err := ts.Push(queued)
+	require.NoError(t, err)
+
+	pooled := &transaction.ValidTransaction{
+		Extrinsic: types.Extrinsic{2},
+		Validity:  transaction.NewValidity(0, [][]byte{{1}}, nil, math.MaxUint64, false),
+	}
+	_, err = ts.AddToPool(pooled)
+	require.NoError(t, err)
+
+	notifierChannel := ts.GetStatusNotifierChannel(queued.Extrinsic)
+	defer ts.FreeStatusNotifierChannel(notifierChannel)
+
+	require.True(t, ts.RemoveExtrinsicByHash(queued.Extrinsic.Hash()))
+	require.Equal(t, transaction.StatusNotification{Status: transaction.Invalid}, <-notifierChannel)
+
+	require.True(t, ts.RemoveExtrinsicByHash(pooled.Extrinsic.Hash()))
+	require.False(t, ts.RemoveExtrinsicByHash(pooled.Extrinsic.Hash()))
+	require.Empty(t, ts.Pending())
+}
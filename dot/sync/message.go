@@ -424,5 +424,14 @@ func (s *SyncService) getBlockData(hash common.Hash, requestedData byte) (*types
 		}
 	}
 
+	if (requestedData&messages.RequestedDataIndexedBody)>>5 == 1 {
+		indexedBody, err := s.blockState.GetIndexedBody(hash)
+		if err != nil {
+			logger.Debugf("failed to get indexed body for block with hash %s: %s", hash, err)
+		} else if indexedBody != nil {
+			blockData.IndexedBody = &indexedBody
+		}
+	}
+
 	return blockData, nil
 }
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/ChainSafe/gossamer/dot/sync (interfaces: Telemetry,BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network,WarpSyncProofProvider)
+// Source: github.com/ChainSafe/gossamer/dot/sync (interfaces: Telemetry,BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network,WarpSyncProofProvider,WarpSyncStorageState)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks_test.go -package=sync . Telemetry,BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network,WarpSyncProofProvider
+//	mockgen -destination=mocks_test.go -package=sync . Telemetry,BlockState,StorageState,TransactionState,BabeVerifier,FinalityGadget,BlockImportHandler,Network,WarpSyncProofProvider,WarpSyncStorageState
 //
 
 // Package sync is a generated GoMock package.
@@ -247,6 +247,21 @@ func (mr *MockBlockStateMockRecorder) GetHighestFinalisedHeader() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestFinalisedHeader", reflect.TypeOf((*MockBlockState)(nil).GetHighestFinalisedHeader))
 }
 
+// GetIndexedBody mocks base method.
+func (m *MockBlockState) GetIndexedBody(arg0 common.Hash) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIndexedBody", arg0)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIndexedBody indicates an expected call of GetIndexedBody.
+func (mr *MockBlockStateMockRecorder) GetIndexedBody(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIndexedBody", reflect.TypeOf((*MockBlockState)(nil).GetIndexedBody), arg0)
+}
+
 // GetJustification mocks base method.
 func (m *MockBlockState) GetJustification(arg0 common.Hash) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -786,3 +801,69 @@ func (mr *MockWarpSyncProofProviderMockRecorder) Verify(arg0, arg1, arg2 any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockWarpSyncProofProvider)(nil).Verify), arg0, arg1, arg2)
 }
+
+// MockWarpSyncStorageState is a mock of WarpSyncStorageState interface.
+type MockWarpSyncStorageState struct {
+	ctrl     *gomock.Controller
+	recorder *MockWarpSyncStorageStateMockRecorder
+}
+
+// MockWarpSyncStorageStateMockRecorder is the mock recorder for MockWarpSyncStorageState.
+type MockWarpSyncStorageStateMockRecorder struct {
+	mock *MockWarpSyncStorageState
+}
+
+// NewMockWarpSyncStorageState creates a new mock instance.
+func NewMockWarpSyncStorageState(ctrl *gomock.Controller) *MockWarpSyncStorageState {
+	mock := &MockWarpSyncStorageState{ctrl: ctrl}
+	mock.recorder = &MockWarpSyncStorageStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWarpSyncStorageState) EXPECT() *MockWarpSyncStorageStateMockRecorder {
+	return m.recorder
+}
+
+// StoreTrie mocks base method.
+func (m *MockWarpSyncStorageState) StoreTrie(arg0 *storage.TrieState, arg1 *types.Header) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreTrie", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreTrie indicates an expected call of StoreTrie.
+func (mr *MockWarpSyncStorageStateMockRecorder) StoreTrie(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTrie", reflect.TypeOf((*MockWarpSyncStorageState)(nil).StoreTrie), arg0, arg1)
+}
+
+// StoreTrieNodes mocks base method.
+func (m *MockWarpSyncStorageState) StoreTrieNodes(arg0 map[common.Hash][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreTrieNodes", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreTrieNodes indicates an expected call of StoreTrieNodes.
+func (mr *MockWarpSyncStorageStateMockRecorder) StoreTrieNodes(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreTrieNodes", reflect.TypeOf((*MockWarpSyncStorageState)(nil).StoreTrieNodes), arg0)
+}
+
+// TrieState mocks base method.
+func (m *MockWarpSyncStorageState) TrieState(arg0 *common.Hash) (*storage.TrieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrieState", arg0)
+	ret0, _ := ret[0].(*storage.TrieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrieState indicates an expected call of TrieState.
+func (mr *MockWarpSyncStorageStateMockRecorder) TrieState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrieState", reflect.TypeOf((*MockWarpSyncStorageState)(nil).TrieState), arg0)
+}
@@ -64,6 +64,7 @@ type BlockState interface {
 	GetReceipt(common.Hash) ([]byte, error)
 	GetMessageQueue(common.Hash) ([]byte, error)
 	GetJustification(common.Hash) ([]byte, error)
+	GetIndexedBody(common.Hash) ([][]byte, error)
 	SetFinalisedHash(hash common.Hash, round uint64, setID uint64) error
 	SetJustification(hash common.Hash, data []byte) error
 	GetHashByNumber(blockNumber uint) (common.Hash, error)
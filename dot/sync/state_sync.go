@@ -0,0 +1,274 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/dot/types"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/proof"
+)
+
+var (
+	errEmptyStateResponse = errors.New("empty state response")
+	errMissingStateProof  = errors.New("missing state proof")
+	errInvalidStateProof  = errors.New("invalid state proof")
+	errStateProofMismatch = errors.New("state proof does not match state root")
+	errUnknownChildRoot   = errors.New("unknown child trie root")
+	errStoringStateNodes  = errors.New("storing state proof nodes")
+)
+
+// stateSyncStorage writes the trie nodes and values of the downloaded state
+type stateSyncStorage interface {
+	StoreTrieNodes(nodes map[common.Hash][]byte) error
+}
+
+// stateSync downloads the whole state of a target block using the state
+// request protocol. The trie nodes and values of the compact proof sent as
+// response, whose root must be the target state root, are written to the
+// storage as soon as the response is verified, so the downloaded state is
+// complete once the proofs cover the whole state.
+type stateSync struct {
+	target  *types.Header
+	storage stateSyncStorage
+
+	// lastKeys is the cursor used to request the next batch of entries,
+	// it contains the last key of the main trie and, if we are in the middle
+	// of a child trie, the last key of the child trie
+	lastKeys [][]byte
+	complete bool
+
+	importedKeys uint
+}
+
+func newStateSync(target *types.Header, storage stateSyncStorage) *stateSync {
+	return &stateSync{
+		target:   target,
+		storage:  storage,
+		lastKeys: [][]byte{},
+	}
+}
+
+func (s *stateSync) nextRequest() *messages.StateRequest {
+	start := make([][]byte, len(s.lastKeys))
+	copy(start, s.lastKeys)
+
+	return &messages.StateRequest{
+		Block:   s.target.Hash(),
+		Start:   start,
+		NoProof: false,
+	}
+}
+
+// process reads the entries proven by the compact proof of the state response and,
+// if the proof is valid, stores its trie nodes and values and moves the request cursor forward
+func (s *stateSync) process(response *messages.StateResponse) error {
+	if len(response.Proof) == 0 {
+		if len(response.Entries) == 0 {
+			return errEmptyStateResponse
+		}
+		return errMissingStateProof
+	}
+
+	compactProof, err := response.ProofNodes()
+	if err != nil {
+		return err
+	}
+
+	proofDB := make(proofNodes)
+	root, err := proof.DecodeCompact[chash.H256, runtime.BlakeTwo256](proofDB, compactProof)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errInvalidStateProof, err)
+	}
+
+	proofRoot := common.BytesToHash(root.Bytes())
+	if proofRoot != s.target.StateRoot {
+		return fmt.Errorf("%w: proof root %s", errStateProofMismatch, proofRoot)
+	}
+
+	reader := &stateProofReader{
+		db:         proofDB,
+		childRoots: make(map[common.Hash]struct{}),
+	}
+	entries, next, err := reader.readRange(s.target.StateRoot, s.lastKeys)
+	if err != nil {
+		return err
+	}
+
+	totalEntries := 0
+	for _, entry := range entries {
+		totalEntries += len(entry.StateEntries)
+	}
+
+	complete := next == nil
+	if !complete && totalEntries == 0 {
+		return errEmptyStateResponse
+	}
+
+	err = s.storage.StoreTrieNodes(proofDB)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errStoringStateNodes, err)
+	}
+
+	s.importedKeys += uint(totalEntries)
+	s.lastKeys = next
+	s.complete = complete
+	return nil
+}
+
+// proofNodes is the database the state proof is decoded into,
+// it holds the trie nodes and values of the proof by hash
+type proofNodes map[common.Hash][]byte
+
+func (p proofNodes) Get(key []byte) ([]byte, error) {
+	if len(key) != common.HashLength {
+		return nil, fmt.Errorf("expected %d bytes length key, given %d (%x)", common.HashLength, len(key), key)
+	}
+	return p[common.Hash(key)], nil
+}
+
+func (p proofNodes) Put(key, value []byte) error {
+	if len(key) != common.HashLength {
+		return fmt.Errorf("expected %d bytes length key, given %d (%x)", common.HashLength, len(key), key)
+	}
+	p[common.Hash(key)] = value
+	return nil
+}
+
+// stateProofReader reads the state entries proven by a decoded state proof
+type stateProofReader struct {
+	db db.DBGetter
+	// childRoots are the roots of the child tries already read,
+	// a child trie shared by several child storage keys is read once
+	childRoots map[common.Hash]struct{}
+}
+
+// readRange reads the entries of the state after the start keys until the end of the state
+// or the first entry missing from the proof, descending into the child tries found in the
+// main trie. The first entry returned holds the main trie entries and the next ones the
+// child trie entries. It also returns the start keys of the next request, nil if the whole
+// state was read.
+func (r *stateProofReader) readRange(stateRoot common.Hash, start [][]byte) (
+	entries []messages.KeyValueStateEntry, next [][]byte, err error) {
+	top := messages.KeyValueStateEntry{StateEntries: trie.Entries{}}
+	topTrie := triedb.NewStateTrie(stateRoot, r.db, nil)
+
+	var topStart []byte
+	if len(start) > 0 {
+		topStart = start[0]
+	}
+
+	// resume the child trie we stopped in the last response
+	if len(start) == 2 {
+		value, err := triedb.GetWith(topTrie.TrieDB, start[0], func(data []byte) []byte { return data })
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: reading child trie root: %s", errInvalidStateProof, err)
+		}
+		if value == nil || !bytes.HasPrefix(start[0], inmemory.ChildStorageKeyPrefix) {
+			return nil, nil, fmt.Errorf("%w: 0x%x", errUnknownChildRoot, start[0])
+		}
+
+		child, err := r.readChild(common.BytesToHash(*value), start[1])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entries = append(entries, child)
+		if !child.Complete {
+			return append([]messages.KeyValueStateEntry{top}, entries...),
+				[][]byte{start[0], lastKey(child, start[1])}, nil
+		}
+	}
+
+	for {
+		var childKey []byte
+		var childRoot common.Hash
+		topComplete := true
+		for item, err := range topTrie.EntriesFrom(topStart) {
+			if err != nil {
+				if !errors.Is(err, triedb.ErrIncompleteDB) {
+					return nil, nil, fmt.Errorf("reading main trie: %w", err)
+				}
+				topComplete = false
+				break
+			}
+
+			top.StateEntries = append(top.StateEntries, trie.Entry{Key: item.Key, Value: item.Value})
+			if bytes.HasPrefix(item.Key, inmemory.ChildStorageKeyPrefix) {
+				root := common.BytesToHash(item.Value)
+				if _, read := r.childRoots[root]; !read {
+					childKey, childRoot = item.Key, root
+					break
+				}
+			}
+		}
+
+		if childKey == nil {
+			top.Complete = topComplete
+			entries = append([]messages.KeyValueStateEntry{top}, entries...)
+			if topComplete {
+				return entries, nil, nil
+			}
+			return entries, [][]byte{lastKey(top, topStart)}, nil
+		}
+
+		child, err := r.readChild(childRoot, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entries = append(entries, child)
+		if !child.Complete {
+			return append([]messages.KeyValueStateEntry{top}, entries...),
+				[][]byte{childKey, lastKey(child, []byte{})}, nil
+		}
+		topStart = childKey
+	}
+}
+
+// readChild reads the entries of the child trie after the start key
+// until its end or the first entry missing from the proof
+func (r *stateProofReader) readChild(root common.Hash, start []byte) (messages.KeyValueStateEntry, error) {
+	r.childRoots[root] = struct{}{}
+
+	child := messages.KeyValueStateEntry{
+		StateRoot:    root,
+		StateEntries: trie.Entries{},
+		Complete:     true,
+	}
+
+	childTrie := triedb.NewStateTrie(root, r.db, nil)
+	for item, err := range childTrie.EntriesFrom(start) {
+		if err != nil {
+			// the child trie root is missing when the response stops right at its child storage key
+			if !errors.Is(err, triedb.ErrIncompleteDB) && !errors.Is(err, triedb.ErrInvalidStateRoot) {
+				return child, fmt.Errorf("reading child trie %s: %w", root, err)
+			}
+			child.Complete = false
+			break
+		}
+
+		child.StateEntries = append(child.StateEntries, trie.Entry{Key: item.Key, Value: item.Value})
+	}
+
+	return child, nil
+}
+
+// lastKey returns the key of the last entry read, or the start key if none was read
+func lastKey(entry messages.KeyValueStateEntry, start []byte) []byte {
+	if len(entry.StateEntries) == 0 {
+		return start
+	}
+	return entry.StateEntries[len(entry.StateEntries)-1].Key
+}
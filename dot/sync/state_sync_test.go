@@ -0,0 +1,258 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package sync
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/network/messages"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/proof"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStateTrie(t *testing.T) (*inmemory.InMemoryTrie, database.Database) {
+	t.Helper()
+
+	// values are long enough for every leaf to be a hashed node so that
+	// responses stop right after the last entry read
+	padding := strings.Repeat(".", 32)
+
+	// the main trie mixes values written before and after the switch to the V1 layout
+	stateTrie := inmemory.NewEmptyTrie()
+	for _, key := range []string{"alpha", "bravo", "charlie"} {
+		require.NoError(t, stateTrie.Put([]byte(key), []byte("value of "+key+padding)))
+	}
+	stateTrie.SetVersion(trie.V1)
+	for _, key := range []string{"delta", "echo"} {
+		require.NoError(t, stateTrie.Put([]byte(key), []byte("value of "+key+padding)))
+	}
+
+	childTrie := inmemory.NewEmptyTrie()
+	childTrie.SetVersion(trie.V1)
+	for _, key := range []string{"one", "two", "three"} {
+		require.NoError(t, childTrie.Put([]byte(key), []byte("child value of "+key+padding)))
+	}
+	require.NoError(t, stateTrie.SetChild([]byte("child"), childTrie))
+
+	db, err := database.NewPebble("", true)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	require.NoError(t, stateTrie.WriteDirty(db))
+	return stateTrie, db
+}
+
+// newTestStateResponse returns a state response with the compact proof
+// of the trie nodes and values accessed by the read function
+func newTestStateResponse(t *testing.T, stateDB database.Database, root common.Hash,
+	read func(stateTrie *triedb.StateTrie)) *messages.StateResponse {
+	t.Helper()
+
+	recorder := triedb.NewRecorder[chash.H256]()
+	read(triedb.NewRecordingStateTrie(root, stateDB, nil, recorder))
+
+	var recorded [][]byte
+	for _, record := range recorder.Drain() {
+		recorded = append(recorded, record.Data)
+	}
+	partialDB, err := db.NewMemoryDBFromProof(recorded)
+	require.NoError(t, err)
+
+	compactProof, err := proof.EncodeCompact[chash.H256, runtime.BlakeTwo256](partialDB, chash.H256(root[:]))
+	require.NoError(t, err)
+
+	encodedProof, err := scale.Marshal(compactProof)
+	require.NoError(t, err)
+
+	return &messages.StateResponse{Proof: encodedProof}
+}
+
+// testStateSyncStorage holds the trie nodes and values stored by the state download
+type testStateSyncStorage proofNodes
+
+func (s testStateSyncStorage) StoreTrieNodes(nodes map[common.Hash][]byte) error {
+	maps.Copy(s, nodes)
+	return nil
+}
+
+// requireStoredState checks the stored trie nodes and values hold the whole state of the trie
+func requireStoredState(t *testing.T, storage testStateSyncStorage, expected *inmemory.InMemoryTrie) {
+	t.Helper()
+
+	storedTrie := triedb.NewStateTrie(expected.MustHash(), proofNodes(storage), nil)
+	require.Equal(t, expected.Entries(), storedTrie.Entries())
+
+	for key, childTrie := range expected.GetChildTries() {
+		storedChild := triedb.NewStateTrie(key, proofNodes(storage), nil)
+		require.Equal(t, childTrie.Entries(), storedChild.Entries())
+	}
+}
+
+// readKeys reads at most limit entries of the trie after the start key
+func readKeys(t *testing.T, tr trie.Trie, start []byte, limit int) {
+	t.Helper()
+
+	read := 0
+	for key := range tr.KeysFrom(start) {
+		require.NotNil(t, tr.Get(key))
+		read++
+		if read == limit {
+			return
+		}
+	}
+}
+
+func readChildKeys(t *testing.T, stateTrie *triedb.StateTrie, start []byte, limit int) {
+	t.Helper()
+
+	childTrie, err := stateTrie.GetChild([]byte("child"))
+	require.NoError(t, err)
+	readKeys(t, childTrie, start, limit)
+}
+
+func TestStateSync(t *testing.T) {
+	t.Parallel()
+
+	stateTrie, db := newTestStateTrie(t)
+	root := stateTrie.MustHash()
+	target := types.NewHeader(common.Hash{1}, root, common.Hash{}, 10, nil)
+	childStorageKey := append(append([]byte{}, inmemory.ChildStorageKeyPrefix...), []byte("child")...)
+
+	t.Run("paged_download_with_child_trie", func(t *testing.T) {
+		t.Parallel()
+
+		storage := make(testStateSyncStorage)
+		stateSync := newStateSync(target, storage)
+		require.Empty(t, stateSync.nextRequest().Start)
+
+		// the child storage key comes first and the response stops in its child trie
+		firstResponse := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+			readKeys(t, stateTrie, nil, 1)
+			readChildKeys(t, stateTrie, nil, 1)
+		})
+		require.NoError(t, stateSync.process(firstResponse))
+		require.False(t, stateSync.complete)
+		require.Equal(t, [][]byte{childStorageKey, []byte("one")}, stateSync.nextRequest().Start)
+
+		secondResponse := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+			readChildKeys(t, stateTrie, []byte("one"), 10)
+			readKeys(t, stateTrie, childStorageKey, 2)
+		})
+		require.NoError(t, stateSync.process(secondResponse))
+		require.False(t, stateSync.complete)
+		require.Equal(t, [][]byte{[]byte("bravo")}, stateSync.nextRequest().Start)
+
+		lastResponse := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+			readKeys(t, stateTrie, []byte("bravo"), 10)
+		})
+		require.NoError(t, stateSync.process(lastResponse))
+		require.True(t, stateSync.complete)
+		require.Equal(t, uint(9), stateSync.importedKeys)
+
+		requireStoredState(t, storage, stateTrie)
+	})
+
+	t.Run("stopped_at_child_trie", func(t *testing.T) {
+		t.Parallel()
+
+		// the child trie of the last entry read is missing from the proof
+		// so the next request starts at the beginning of the child trie
+		stateSync := newStateSync(target, make(testStateSyncStorage))
+		response := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+			readKeys(t, stateTrie, nil, 1)
+		})
+		require.NoError(t, stateSync.process(response))
+		require.False(t, stateSync.complete)
+		require.Equal(t, [][]byte{childStorageKey, {}}, stateSync.nextRequest().Start)
+		require.Equal(t, uint(1), stateSync.importedKeys)
+	})
+
+	t.Run("proof_of_another_state", func(t *testing.T) {
+		t.Parallel()
+
+		otherTrie := inmemory.NewEmptyTrie()
+		require.NoError(t, otherTrie.Put([]byte("alpha"), []byte("forged")))
+		require.NoError(t, otherTrie.WriteDirty(db))
+		otherRoot := otherTrie.MustHash()
+
+		storage := make(testStateSyncStorage)
+		stateSync := newStateSync(target, storage)
+		response := newTestStateResponse(t, db, otherRoot, func(stateTrie *triedb.StateTrie) {
+			readKeys(t, stateTrie, nil, 1)
+		})
+
+		err := stateSync.process(response)
+		require.ErrorIs(t, err, errStateProofMismatch)
+		require.Empty(t, storage)
+		require.Zero(t, stateSync.importedKeys)
+	})
+
+	t.Run("invalid_proof", func(t *testing.T) {
+		t.Parallel()
+
+		stateSync := newStateSync(target, make(testStateSyncStorage))
+		encodedProof, err := scale.Marshal([][]byte{{1, 2, 3}})
+		require.NoError(t, err)
+
+		err = stateSync.process(&messages.StateResponse{Proof: encodedProof})
+		require.ErrorIs(t, err, errInvalidStateProof)
+	})
+
+	t.Run("unknown_child_trie", func(t *testing.T) {
+		t.Parallel()
+
+		stateSync := newStateSync(target, make(testStateSyncStorage))
+		stateSync.lastKeys = [][]byte{[]byte("alpha"), []byte("one")}
+		response := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+			require.NotNil(t, stateTrie.Get([]byte("alpha")))
+		})
+
+		err := stateSync.process(response)
+		require.ErrorIs(t, err, errUnknownChildRoot)
+	})
+
+	t.Run("no_entries_proven", func(t *testing.T) {
+		t.Parallel()
+
+		stateSync := newStateSync(target, make(testStateSyncStorage))
+		response := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+			require.Nil(t, stateTrie.Get([]byte("zulu")))
+		})
+
+		err := stateSync.process(response)
+		require.ErrorIs(t, err, errEmptyStateResponse)
+	})
+
+	t.Run("missing_proof", func(t *testing.T) {
+		t.Parallel()
+
+		stateSync := newStateSync(target, make(testStateSyncStorage))
+		err := stateSync.process(&messages.StateResponse{
+			Entries: []messages.KeyValueStateEntry{{
+				StateEntries: trie.Entries{{Key: []byte("alpha"), Value: stateTrie.Get([]byte("alpha"))}},
+			}},
+		})
+		require.ErrorIs(t, err, errMissingStateProof)
+	})
+
+	t.Run("empty_response", func(t *testing.T) {
+		t.Parallel()
+
+		stateSync := newStateSync(target, make(testStateSyncStorage))
+		err := stateSync.process(&messages.StateResponse{})
+		require.ErrorIs(t, err, errEmptyStateResponse)
+	})
+}
@@ -4,6 +4,7 @@
 package sync
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"time"
@@ -13,7 +14,9 @@
 	"github.com/ChainSafe/gossamer/dot/peerset"
 	"github.com/ChainSafe/gossamer/dot/types"
 	primitives "github.com/ChainSafe/gossamer/internal/primitives/consensus/grandpa"
+	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/grandpa/warpsync"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/libp2p/go-libp2p/core/peer"
 )
 
@@ -22,6 +25,7 @@
 const (
 	WarpProof = iota
 	TargetBlock
+	TargetState
 	Completed
 )
 
@@ -31,14 +35,23 @@ type WarpSyncProofProvider interface {
 		*warpsync.WarpSyncVerificationResult, error)
 }
 
+// WarpSyncStorageState is the storage state used to write the target block state
+type WarpSyncStorageState interface {
+	StoreTrieNodes(nodes map[common.Hash][]byte) error
+	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
+	StoreTrie(ts *rtstorage.TrieState, header *types.Header) error
+}
+
 type WarpSyncStrategy struct {
 	// Strategy dependencies and config
 	peers            *peerViewSet
 	badBlocks        []string
 	warpSyncReqMaker network.RequestMaker
 	syncReqMaker     network.RequestMaker
+	stateReqMaker    network.RequestMaker
 	warpSyncProvider WarpSyncProofProvider
 	blockState       BlockState
+	storageState     WarpSyncStorageState
 
 	// Warp sync state
 	startedAt       time.Time
@@ -48,6 +61,7 @@ type WarpSyncStrategy struct {
 	authorities     primitives.AuthorityList
 	lastBlock       *types.Header
 	result          types.BlockData
+	stateSync       *stateSync
 }
 
 type WarpSyncConfig struct {
@@ -55,8 +69,10 @@ type WarpSyncConfig struct {
 	BadBlocks            []string
 	WarpSyncRequestMaker network.RequestMaker
 	SyncRequestMaker     network.RequestMaker
+	StateRequestMaker    network.RequestMaker
 	WarpSyncProvider     WarpSyncProofProvider
 	BlockState           BlockState
+	StorageState         WarpSyncStorageState
 	Peers                *peerViewSet
 }
 
@@ -70,9 +86,11 @@ func NewWarpSyncStrategy(cfg *WarpSyncConfig) *WarpSyncStrategy {
 	return &WarpSyncStrategy{
 		warpSyncProvider: cfg.WarpSyncProvider,
 		blockState:       cfg.BlockState,
+		storageState:     cfg.StorageState,
 		badBlocks:        cfg.BadBlocks,
 		warpSyncReqMaker: cfg.WarpSyncRequestMaker,
 		syncReqMaker:     cfg.SyncRequestMaker,
+		stateReqMaker:    cfg.StateRequestMaker,
 		peers:            cfg.Peers,
 		setId:            0,
 		authorities:      authorities,
@@ -159,6 +177,16 @@ func (w *WarpSyncStrategy) NextActions() ([]*SyncTask, error) {
 			response:     &messages.BlockResponseMessage{},
 			requestMaker: w.syncReqMaker,
 		}
+	case TargetState:
+		// state responses are chained by their last keys so
+		// only a single request can be in flight
+		task = SyncTask{
+			request:      w.stateSync.nextRequest(),
+			response:     &messages.StateResponse{},
+			requestMaker: w.stateReqMaker,
+		}
+	default:
+		return nil, nil
 	}
 
 	return []*SyncTask{&task}, nil
@@ -202,6 +230,27 @@ func (w *WarpSyncStrategy) Process(results []*SyncTaskResult) (
 
 		if len(validRes) > 0 && validRes[0].responseData != nil && len(validRes[0].responseData) > 0 {
 			w.result = *validRes[0].responseData[0]
+
+			logger.Debugf("⏩ Warping, downloading state of target block #%d (%s)",
+				w.lastBlock.Number, w.lastBlock.Hash().String())
+			w.stateSync = newStateSync(w.lastBlock, w.storageState)
+			w.phase = TargetState
+		}
+
+	case TargetState:
+		logger.Debug("processing warp sync target state results")
+
+		repChanges, bans, err = w.validateStateResults(results)
+		if err != nil {
+			return false, repChanges, bans, err
+		}
+
+		if w.stateSync.complete {
+			err = w.storeTargetState()
+			if err != nil {
+				return false, nil, nil, err
+			}
+
 			w.phase = Completed
 		}
 	}
@@ -209,6 +258,74 @@ func (w *WarpSyncStrategy) Process(results []*SyncTaskResult) (
 	return w.IsSynced(), repChanges, bans, nil
 }
 
+// validateStateResults verifies the state responses, updating the state download
+// with the first valid one since every request depends on the previous response.
+// Only the peers which sent an invalid response are penalised.
+func (w *WarpSyncStrategy) validateStateResults(results []*SyncTaskResult) (
+	repChanges []Change, peersToBlock []peer.ID, err error) {
+
+	repChanges = make([]Change, 0)
+	peersToBlock = make([]peer.ID, 0)
+
+	for _, result := range results {
+		if !result.completed {
+			continue
+		}
+
+		response, ok := result.response.(*messages.StateResponse)
+		if !ok {
+			repChanges = append(repChanges, Change{
+				who: result.who,
+				rep: peerset.ReputationChange{
+					Value:  peerset.UnexpectedResponseValue,
+					Reason: peerset.UnexpectedResponseReason,
+				}})
+			peersToBlock = append(peersToBlock, result.who)
+			continue
+		}
+
+		err = w.stateSync.process(response)
+		if errors.Is(err, errStoringStateNodes) {
+			return repChanges, peersToBlock, err
+		}
+		if err != nil {
+			logger.Warnf("bad state response from %s: %s", result.who, err)
+
+			repChanges = append(repChanges, Change{
+				who: result.who,
+				rep: peerset.ReputationChange{
+					Value:  peerset.BadStateResponseValue,
+					Reason: peerset.BadStateResponseReason,
+				}})
+			peersToBlock = append(peersToBlock, result.who)
+			continue
+		}
+
+		break
+	}
+
+	return repChanges, peersToBlock, nil
+}
+
+// storeTargetState stores the downloaded state of the target block, whose trie nodes
+// and values are already in the database, in the storage state
+func (w *WarpSyncStrategy) storeTargetState() error {
+	// the trie nodes and values were written while downloading the state
+	targetState, err := w.storageState.TrieState(&w.lastBlock.StateRoot)
+	if err != nil {
+		return fmt.Errorf("loading target state: %w", err)
+	}
+
+	err = w.storageState.StoreTrie(targetState, w.lastBlock)
+	if err != nil {
+		return fmt.Errorf("storing target state: %w", err)
+	}
+
+	logger.Infof("⏩ Warping, stored state of target block #%d (%s) with %d keys",
+		w.lastBlock.Number, w.lastBlock.Hash().Short(), w.stateSync.importedKeys)
+	return nil
+}
+
 func (w *WarpSyncStrategy) validateWarpSyncResults(results []*SyncTaskResult) (
 	repChanges []Change, peersToBlock []peer.ID, result *warpsync.WarpSyncVerificationResult) {
 
@@ -278,6 +395,9 @@ func (w *WarpSyncStrategy) ShowMetrics() {
 	case TargetBlock:
 		logger.Infof("⏩ Warping, downloading target block #%d (%s)",
 			w.lastBlock.Number, w.lastBlock.Hash().String())
+	case TargetState:
+		logger.Infof("⏩ Warping, downloading target state of block #%d (%s), keys %d",
+			w.lastBlock.Number, w.lastBlock.Hash().Short(), w.stateSync.importedKeys)
 	}
 
 }
@@ -4,6 +4,7 @@
 package sync
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/dot/network"
@@ -12,6 +13,9 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/grandpa/warpsync"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -138,6 +142,7 @@ func TestWarpSyncNextActions(t *testing.T) {
 	tc := map[string]struct {
 		phase                WarpSyncPhase
 		lastBlock            *types.Header
+		stateSync            *stateSync
 		expectedRequestType  interface{}
 		expectedResponseType interface{}
 	}{
@@ -151,6 +156,12 @@ func TestWarpSyncNextActions(t *testing.T) {
 			expectedRequestType:  &messages.BlockRequestMessage{},
 			expectedResponseType: &messages.BlockResponseMessage{},
 		},
+		"target_state_phase": {
+			phase:                TargetState,
+			stateSync:            newStateSync(genesisHeader, nil),
+			expectedRequestType:  &messages.StateRequest{},
+			expectedResponseType: &messages.StateResponse{},
+		},
 	}
 
 	for name, c := range tc {
@@ -161,6 +172,7 @@ func TestWarpSyncNextActions(t *testing.T) {
 			})
 
 			strategy.phase = c.phase
+			strategy.stateSync = c.stateSync
 
 			tasks, err := strategy.NextActions()
 			require.NoError(t, err)
@@ -172,3 +184,106 @@ func TestWarpSyncNextActions(t *testing.T) {
 		})
 	}
 }
+
+func TestWarpSyncProcessTargetState(t *testing.T) {
+	t.Parallel()
+
+	stateTrie, db := newTestStateTrie(t)
+	root := stateTrie.MustHash()
+	target := types.NewHeader(common.Hash{1}, root, common.Hash{}, 10, nil)
+
+	validResponse := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+		readKeys(t, stateTrie, nil, 10)
+		readChildKeys(t, stateTrie, nil, 10)
+	})
+
+	otherTrie := inmemory.NewEmptyTrie()
+	require.NoError(t, otherTrie.Put([]byte("alpha"), []byte("forged")))
+	require.NoError(t, otherTrie.WriteDirty(db))
+	invalidResponse := newTestStateResponse(t, db, otherTrie.MustHash(), func(stateTrie *triedb.StateTrie) {
+		readKeys(t, stateTrie, nil, 10)
+	})
+
+	ctrl := gomock.NewController(t)
+
+	warpSyncProvider := NewMockWarpSyncProofProvider(ctrl)
+	warpSyncProvider.EXPECT().CurrentAuthorities().Return(nil, nil)
+
+	stored := make(testStateSyncStorage)
+	storageState := NewMockWarpSyncStorageState(ctrl)
+	storageState.EXPECT().StoreTrieNodes(gomock.Any()).DoAndReturn(stored.StoreTrieNodes)
+	storageState.EXPECT().TrieState(&root).
+		DoAndReturn(func(root *common.Hash) (*rtstorage.TrieState, error) {
+			return rtstorage.NewTrieState(triedb.NewStateTrie(*root, proofNodes(stored), nil)), nil
+		})
+	storageState.EXPECT().StoreTrie(gomock.Any(), target).
+		DoAndReturn(func(ts *rtstorage.TrieState, _ *types.Header) error {
+			require.Equal(t, root, ts.Trie().MustHash())
+			return nil
+		})
+
+	strategy := NewWarpSyncStrategy(&WarpSyncConfig{
+		WarpSyncProvider: warpSyncProvider,
+		StorageState:     storageState,
+	})
+	strategy.phase = TargetState
+	strategy.lastBlock = target
+	strategy.stateSync = newStateSync(target, storageState)
+
+	badPeer := peer.ID("bad")
+	goodPeer := peer.ID("good")
+
+	done, repChanges, bans, err := strategy.Process([]*SyncTaskResult{
+		{who: badPeer, completed: true, response: invalidResponse},
+		{who: goodPeer, completed: true, response: validResponse},
+	})
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Equal(t, []peer.ID{badPeer}, bans)
+	require.Equal(t, []Change{{
+		who: badPeer,
+		rep: peerset.ReputationChange{
+			Value:  peerset.BadStateResponseValue,
+			Reason: peerset.BadStateResponseReason,
+		},
+	}}, repChanges)
+	requireStoredState(t, stored, stateTrie)
+}
+
+func TestWarpSyncProcessTargetStateStorageError(t *testing.T) {
+	t.Parallel()
+
+	stateTrie, db := newTestStateTrie(t)
+	root := stateTrie.MustHash()
+	target := types.NewHeader(common.Hash{1}, root, common.Hash{}, 10, nil)
+
+	response := newTestStateResponse(t, db, root, func(stateTrie *triedb.StateTrie) {
+		readKeys(t, stateTrie, nil, 10)
+	})
+
+	ctrl := gomock.NewController(t)
+
+	warpSyncProvider := NewMockWarpSyncProofProvider(ctrl)
+	warpSyncProvider.EXPECT().CurrentAuthorities().Return(nil, nil)
+
+	errTest := errors.New("test error")
+	storageState := NewMockWarpSyncStorageState(ctrl)
+	storageState.EXPECT().StoreTrieNodes(gomock.Any()).Return(errTest)
+
+	strategy := NewWarpSyncStrategy(&WarpSyncConfig{
+		WarpSyncProvider: warpSyncProvider,
+		StorageState:     storageState,
+	})
+	strategy.phase = TargetState
+	strategy.lastBlock = target
+	strategy.stateSync = newStateSync(target, storageState)
+
+	// the peer sent a valid response so it is not penalised for the storage failure
+	done, repChanges, bans, err := strategy.Process([]*SyncTaskResult{
+		{who: peer.ID("peer"), completed: true, response: response},
+	})
+	require.ErrorIs(t, err, errTest)
+	require.False(t, done)
+	require.Empty(t, bans)
+	require.Empty(t, repChanges)
+}
@@ -4,21 +4,30 @@
 package system
 
 import (
+	"errors"
+
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 )
 
+// ErrNoLogFilter is returned when changing the runtime log filter of a service without one.
+var ErrNoLogFilter = errors.New("no runtime log filter")
+
 // Service struct to hold rpc service data
 type Service struct {
 	systemInfo  *types.SystemInfo
 	genesisData *genesis.Data
+	logFilter   *runtime.LogFilter
 }
 
-// NewService create a new instance of Service
-func NewService(si *types.SystemInfo, gd *genesis.Data) *Service {
+// NewService create a new instance of Service, logFilter being
+// the log filter shared by the runtime instances of the node.
+func NewService(si *types.SystemInfo, gd *genesis.Data, logFilter *runtime.LogFilter) *Service {
 	return &Service{
 		systemInfo:  si,
 		genesisData: gd,
+		logFilter:   logFilter,
 	}
 }
 
@@ -47,6 +56,23 @@ func (s *Service) Properties() map[string]interface{} {
 	return s.genesisData.Properties
 }
 
+// AddLogFilter adds the comma separated 'target=level' directives to the runtime log filter
+func (s *Service) AddLogFilter(directives string) error {
+	if s.logFilter == nil {
+		return ErrNoLogFilter
+	}
+	return s.logFilter.AddDirectives(directives)
+}
+
+// ResetLogFilter restores the runtime log filter to the log levels of the configuration
+func (s *Service) ResetLogFilter() error {
+	if s.logFilter == nil {
+		return ErrNoLogFilter
+	}
+	s.logFilter.Reset()
+	return nil
+}
+
 // Start implements Service interface
 func (*Service) Start() error {
 	return nil
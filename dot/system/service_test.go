@@ -7,7 +7,9 @@
 	"testing"
 
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/genesis"
+	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/stretchr/testify/require"
 )
 
@@ -51,6 +53,31 @@ func TestService_Stop(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestService_LogFilter(t *testing.T) {
+	svc := newTestService()
+
+	err := svc.AddLogFilter("runtime::staking=debug")
+	require.NoError(t, err)
+	require.True(t, svc.logFilter.Enabled("runtime::staking", log.Debug))
+
+	err = svc.AddLogFilter("runtime::staking=verbose")
+	require.ErrorIs(t, err, runtime.ErrInvalidLogDirective)
+
+	err = svc.ResetLogFilter()
+	require.NoError(t, err)
+	require.False(t, svc.logFilter.Enabled("runtime::staking", log.Debug))
+}
+
+func TestService_LogFilter_noLogFilter(t *testing.T) {
+	svc := NewService(&types.SystemInfo{}, &genesis.Data{}, nil)
+
+	err := svc.AddLogFilter("runtime::staking=debug")
+	require.ErrorIs(t, err, ErrNoLogFilter)
+
+	err = svc.ResetLogFilter()
+	require.ErrorIs(t, err, ErrNoLogFilter)
+}
+
 func newTestService() *Service {
 
 	sysInfo := &types.SystemInfo{
@@ -60,5 +87,5 @@ func newTestService() *Service {
 	genData := &genesis.Data{
 		Name: "gssmr",
 	}
-	return NewService(sysInfo, genData)
+	return NewService(sysInfo, genData, runtime.NewLogFilter(log.Info, nil))
 }
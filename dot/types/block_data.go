@@ -19,6 +19,8 @@ type BlockData struct {
 	Receipt       *[]byte
 	MessageQueue  *[]byte
 	Justification *[]byte
+	// IndexedBody is only exchanged in block responses, so it is not SCALE encoded.
+	IndexedBody *[][]byte `scale:"-"`
 }
 
 // NewEmptyBlockData Creates an empty blockData struct
@@ -60,5 +62,9 @@ func (bd *BlockData) String() string {
 		str = str + fmt.Sprintf("Justification=0x%x ", bd.Justification)
 	}
 
+	if bd.IndexedBody != nil {
+		str = str + fmt.Sprintf("IndexedBody=0x%x ", *bd.IndexedBody)
+	}
+
 	return str
 }
@@ -197,6 +197,15 @@ func (v GrandpaVote) String() string {
 	return fmt.Sprintf("hash=%s number=%d", v.Hash, v.Number)
 }
 
+// GrandpaHasVoted holds the votes cast by the node in a round, it is persisted
+// so the node does not cast different votes in the round after a restart.
+// A nil vote means the node did not vote in the subround.
+type GrandpaHasVoted struct {
+	Propose   *GrandpaVote
+	Prevote   *GrandpaVote
+	Precommit *GrandpaVote
+}
+
 // GrandpaEquivocation is used to create a proof of equivocation
 // https://github.com/paritytech/finality-grandpa/blob/19d251d0b0105d51a79d3c4532a9aae75a5035bd/src/lib.rs#L213 //nolint:lll
 type GrandpaEquivocation struct {
@@ -0,0 +1,176 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package babe
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+)
+
+// EpochAuthorship holds the slots of an epoch an authority key may claim, by claim type
+type EpochAuthorship struct {
+	Primary      []uint64 `json:"primary"`
+	Secondary    []uint64 `json:"secondary"`
+	SecondaryVRF []uint64 `json:"secondary_vrf"`
+}
+
+// EpochAuthorship returns the slots of the current epoch that each key of the BABE keystore
+// may claim, keyed by the SS58 address of the key. Keys which are not authorities of the
+// current epoch are left out.
+func (b *Service) EpochAuthorship() (map[string]*EpochAuthorship, error) {
+	bestBlock, err := b.blockState.BestBlockHeader()
+	if err != nil {
+		return nil, fmt.Errorf("getting best block header: %w", err)
+	}
+
+	epochFromBestBlock, err := b.epochState.GetEpochForBlock(bestBlock)
+	if err != nil {
+		return nil, fmt.Errorf("getting epoch for best block: %w", err)
+	}
+
+	startSlot, err := b.epochState.GetStartSlotForEpoch(epochFromBestBlock, bestBlock.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("getting start slot for epoch %d: %w", epochFromBestBlock, err)
+	}
+
+	// the current slot may be in an epoch following the epoch of the best block
+	epoch := epochFromBestBlock
+	currentSlot := getCurrentSlot(b.constants.slotDuration)
+	if currentSlot > startSlot {
+		epochsAhead := (currentSlot - startSlot) / b.constants.epochLength
+		epoch += epochsAhead
+		startSlot += epochsAhead * b.constants.epochLength
+	}
+
+	epochDataRaw, configData, err := b.getEpochAuthorshipData(epoch, epochFromBestBlock, bestBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold, err := CalculateThreshold(configData.C1, configData.C2, len(epochDataRaw.Authorities))
+	if err != nil {
+		return nil, fmt.Errorf("calculating threshold: %w", err)
+	}
+
+	authorships := make(map[string]*EpochAuthorship)
+	if b.keystore == nil {
+		return authorships, nil
+	}
+
+	for _, kp := range b.keystore.Keypairs() {
+		keypair, ok := kp.(*sr25519.Keypair)
+		if !ok {
+			continue
+		}
+
+		authorityIndex, ok := findAuthorityIndex(epochDataRaw.Authorities, keypair)
+		if !ok {
+			continue
+		}
+
+		descriptor := &epochDescriptor{
+			data: &epochData{
+				randomness:     epochDataRaw.Randomness,
+				authorityIndex: authorityIndex,
+				authorities:    epochDataRaw.Authorities,
+				threshold:      threshold,
+				allowedSlots:   types.AllowedSlots(configData.SecondarySlots),
+			},
+			epoch:     epoch,
+			startSlot: startSlot,
+			endSlot:   startSlot + b.constants.epochLength,
+		}
+
+		handler, err := newEpochHandler(descriptor, b.constants, nil, keypair)
+		if err != nil {
+			return nil, fmt.Errorf("creating epoch handler: %w", err)
+		}
+
+		authorship, err := newEpochAuthorship(handler.slotToPreRuntimeDigest)
+		if err != nil {
+			return nil, err
+		}
+		authorships[string(keypair.Public().Address())] = authorship
+	}
+
+	return authorships, nil
+}
+
+// getEpochAuthorshipData returns the epoch and configuration data of the epoch, using the data
+// meant for the epoch following the epoch of the best block if epochs were skipped.
+func (b *Service) getEpochAuthorshipData(epoch, epochFromBestBlock uint64, bestBlock *types.Header) (
+	*types.EpochDataRaw, *types.ConfigData, error) {
+	skipped, diff, err := checkIfEpochSkipped(epoch, epochFromBestBlock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("checking if epoch skipped: %w", err)
+	}
+
+	if skipped {
+		lastKnownEpoch := epoch - diff
+		epochDataRaw, err := b.epochState.GetSkippedEpochDataRaw(lastKnownEpoch+1, epoch, bestBlock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("finding skipped epoch data raw: %w", err)
+		}
+
+		configData, err := b.epochState.GetSkippedConfigData(lastKnownEpoch+1, epoch, bestBlock)
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting config data: %w", err)
+		}
+		return epochDataRaw, configData, nil
+	}
+
+	epochDataRaw, err := b.epochState.GetEpochDataRaw(epoch, bestBlock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting epoch data for epoch %d: %w", epoch, err)
+	}
+
+	configData, err := b.epochState.GetConfigData(epoch, bestBlock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting config data: %w", err)
+	}
+	return epochDataRaw, configData, nil
+}
+
+func findAuthorityIndex(authorities []types.AuthorityRaw, keypair *sr25519.Keypair) (uint32, bool) {
+	publicKey := keypair.Public().Encode()
+	for i, authority := range authorities {
+		if bytes.Equal(publicKey, authority.Key[:]) {
+			return uint32(i), true
+		}
+	}
+	return 0, false
+}
+
+func newEpochAuthorship(slotToPreRuntimeDigest map[uint64]*types.PreRuntimeDigest) (*EpochAuthorship, error) {
+	authorship := &EpochAuthorship{
+		Primary:      []uint64{},
+		Secondary:    []uint64{},
+		SecondaryVRF: []uint64{},
+	}
+
+	for slot, preRuntimeDigest := range slotToPreRuntimeDigest {
+		preDigest, err := types.DecodeBabePreDigest(preRuntimeDigest.Data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding pre-runtime digest of slot %d: %w", slot, err)
+		}
+
+		switch preDigest.(type) {
+		case types.BabePrimaryPreDigest:
+			authorship.Primary = append(authorship.Primary, slot)
+		case types.BabeSecondaryPlainPreDigest:
+			authorship.Secondary = append(authorship.Secondary, slot)
+		case types.BabeSecondaryVRFPreDigest:
+			authorship.SecondaryVRF = append(authorship.SecondaryVRF, slot)
+		}
+	}
+
+	for _, slots := range [][]uint64{authorship.Primary, authorship.Secondary, authorship.SecondaryVRF} {
+		sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	}
+	return authorship, nil
+}
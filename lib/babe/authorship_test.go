@@ -0,0 +1,110 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package babe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/crypto"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_EpochAuthorship(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	const slotDuration = 6 * time.Second
+	const epochLength = 20
+
+	alice := keyring.Alice().(*sr25519.Keypair)
+	bob := keyring.Bob().(*sr25519.Keypair)
+	charlie := keyring.Charlie().(*sr25519.Keypair)
+
+	ks := keystore.NewBasicKeystore("babe", crypto.Sr25519Type)
+	require.NoError(t, ks.Insert(alice))
+	require.NoError(t, ks.Insert(charlie))
+
+	epochDataRaw := &types.EpochDataRaw{
+		Randomness: [32]byte{1},
+		Authorities: []types.AuthorityRaw{
+			*types.NewAuthority(alice.Public(), 1).ToRaw(),
+			*types.NewAuthority(bob.Public(), 1).ToRaw(),
+		},
+	}
+	configData := &types.ConfigData{
+		C1:             1,
+		C2:             4,
+		SecondarySlots: uint8(types.PrimaryAndSecondaryPlainSlots),
+	}
+
+	bestBlock := &types.Header{Number: 3}
+	startSlot := getCurrentSlot(slotDuration)
+
+	blockState := NewMockBlockState(ctrl)
+	blockState.EXPECT().BestBlockHeader().Return(bestBlock, nil)
+	epochState := NewMockEpochState(ctrl)
+	epochState.EXPECT().GetEpochForBlock(bestBlock).Return(uint64(1), nil)
+	epochState.EXPECT().GetStartSlotForEpoch(uint64(1), bestBlock.Hash()).Return(startSlot, nil)
+	epochState.EXPECT().GetEpochDataRaw(uint64(1), bestBlock).Return(epochDataRaw, nil)
+	epochState.EXPECT().GetConfigData(uint64(1), bestBlock).Return(configData, nil)
+
+	service := &Service{
+		blockState: blockState,
+		epochState: epochState,
+		keystore:   ks,
+		constants: constants{
+			slotDuration: slotDuration,
+			epochLength:  epochLength,
+		},
+	}
+
+	authorships, err := service.EpochAuthorship()
+	require.NoError(t, err)
+
+	// charlie is not an authority of the epoch
+	require.Len(t, authorships, 1)
+	authorship := authorships[string(alice.Public().Address())]
+	require.NotNil(t, authorship)
+	assert.Empty(t, authorship.SecondaryVRF)
+
+	claimed := append(append([]uint64{}, authorship.Primary...), authorship.Secondary...)
+	for _, slot := range claimed {
+		assert.GreaterOrEqual(t, slot, startSlot)
+		assert.Less(t, slot, startSlot+epochLength)
+	}
+	assert.IsIncreasing(t, authorship.Secondary)
+	assert.NotEmpty(t, claimed)
+}
+
+func Test_newEpochAuthorship(t *testing.T) {
+	t.Parallel()
+
+	primary, err := types.NewBabePrimaryPreDigest(0, 3, [32]byte{}, [64]byte{}).ToPreRuntimeDigest()
+	require.NoError(t, err)
+	secondaryPlain, err := types.NewBabeSecondaryPlainPreDigest(0, 2).ToPreRuntimeDigest()
+	require.NoError(t, err)
+	secondaryVRF, err := types.NewBabeSecondaryVRFPreDigest(0, 5, [32]byte{}, [64]byte{}).ToPreRuntimeDigest()
+	require.NoError(t, err)
+
+	authorship, err := newEpochAuthorship(map[uint64]*types.PreRuntimeDigest{
+		4: primary,
+		3: primary,
+		2: secondaryPlain,
+		5: secondaryVRF,
+	})
+	require.NoError(t, err)
+
+	expected := &EpochAuthorship{
+		Primary:      []uint64{3, 4},
+		Secondary:    []uint64{2},
+		SecondaryVRF: []uint64{5},
+	}
+	assert.Equal(t, expected, authorship)
+}
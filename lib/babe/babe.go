@@ -40,6 +40,8 @@ type Service struct {
 
 	// BABE authority keypair
 	keypair *sr25519.Keypair // TODO: change to BABE keystore (#1864)
+	// keystore holds the BABE keys the epoch authorship is reported for
+	keystore Keystore
 
 	// State variables
 	sync.RWMutex
@@ -58,6 +60,7 @@ type ServiceConfig struct {
 	EpochState         EpochState
 	BlockImportHandler BlockImportHandler
 	Keypair            *sr25519.Keypair
+	Keystore           Keystore
 	AuthData           []types.Authority
 	IsDev              bool
 	Authority          bool
@@ -98,6 +101,7 @@ func (Builder) NewServiceIFace(cfg *ServiceConfig) (service *Service, err error)
 		storageState:       cfg.StorageState,
 		epochState:         cfg.EpochState,
 		keypair:            cfg.Keypair,
+		keystore:           cfg.Keystore,
 		transactionState:   cfg.TransactionState,
 		pause:              make(chan struct{}),
 		authority:          cfg.Authority,
@@ -140,6 +144,7 @@ func NewService(cfg *ServiceConfig) (*Service, error) {
 		storageState:       cfg.StorageState,
 		epochState:         cfg.EpochState,
 		keypair:            cfg.Keypair,
+		keystore:           cfg.Keystore,
 		transactionState:   cfg.TransactionState,
 		pause:              make(chan struct{}),
 		authority:          cfg.Authority,
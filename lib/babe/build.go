@@ -76,6 +76,12 @@ func NewBlockBuilder(
 	}
 }
 
+// BuildBlock builds and seals a block for the slot with the given parent. Transactions are
+// taken from the queue until two thirds of the slot duration have elapsed.
+func (b *BlockBuilder) BuildBlock(parent *types.Header, slot Slot, rt Runtime) (*types.Block, error) {
+	return b.buildBlock(parent, slot, rt)
+}
+
 func (b *BlockBuilder) buildBlock(parent *types.Header, slot Slot, rt Runtime) (*types.Block, error) {
 	logger.Tracef("build block with parent %s and slot: %s", parent, slot)
 
@@ -7,6 +7,7 @@
 	"encoding/json"
 
 	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/keystore"
 )
 
 // Runtime is the runtime interface for the babe package.
@@ -31,3 +32,8 @@ type ExtrinsicHandler interface {
 type Telemetry interface {
 	SendMessage(msg json.Marshaler)
 }
+
+// Keystore is the keystore holding the BABE keys of the node.
+type Keystore interface {
+	Keypairs() []keystore.KeyPair
+}
@@ -37,11 +37,12 @@ type onDisabledInfo struct {
 // VerificationManager deals with verification that a BABE block producer was authorized to produce a given block.
 // It tracks the BABE epoch data that is needed for verification.
 type VerificationManager struct {
-	lock       sync.Mutex
-	blockState BlockState
-	slotState  SlotState
-	epochState EpochState
-	epochInfo  map[uint64]*verifierInfo // map of epoch number -> info needed for verification
+	lock         sync.Mutex
+	blockState   BlockState
+	storageState StorageState
+	slotState    SlotState
+	epochState   EpochState
+	epochInfo    map[uint64]*verifierInfo // map of epoch number -> info needed for verification
 	// there may be different OnDisabled digests on different
 	// branches of the chain, so we need to keep track of all of them.
 	// map of epoch number -> block producer index -> block number and hash
@@ -49,13 +50,15 @@ type VerificationManager struct {
 }
 
 // NewVerificationManager returns a new NewVerificationManager
-func NewVerificationManager(blockState BlockState, slotState SlotState, epochState EpochState) *VerificationManager {
+func NewVerificationManager(blockState BlockState, storageState StorageState, slotState SlotState,
+	epochState EpochState) *VerificationManager {
 	return &VerificationManager{
-		epochState: epochState,
-		slotState:  slotState,
-		blockState: blockState,
-		epochInfo:  make(map[uint64]*verifierInfo),
-		onDisabled: make(map[uint64]map[uint32][]*onDisabledInfo),
+		epochState:   epochState,
+		slotState:    slotState,
+		blockState:   blockState,
+		storageState: storageState,
+		epochInfo:    make(map[uint64]*verifierInfo),
+		onDisabled:   make(map[uint64]map[uint32][]*onDisabledInfo),
 	}
 }
 
@@ -165,7 +168,7 @@ func (v *VerificationManager) VerifyBlock(header *types.Header) error {
 		return fmt.Errorf("getting verifier info: %w", err)
 	}
 
-	verifier := newVerifier(v.blockState, v.slotState, currentBlockEpoch, info, slotDuration)
+	verifier := newVerifier(v.blockState, v.storageState, v.slotState, currentBlockEpoch, info, slotDuration)
 	return verifier.verifyAuthorshipRight(header)
 }
 
@@ -196,6 +199,7 @@ func (v *VerificationManager) getVerifierInfo(epoch uint64, header *types.Header
 // verifier is a BABE verifier for a specific authority set, randomness, and threshold
 type verifier struct {
 	blockState     BlockState
+	storageState   StorageState
 	slotState      SlotState
 	epoch          uint64
 	authorities    []types.AuthorityRaw
@@ -206,10 +210,11 @@ type verifier struct {
 }
 
 // newVerifier returns a Verifier for the epoch described by the given descriptor
-func newVerifier(blockState BlockState, slotState SlotState,
+func newVerifier(blockState BlockState, storageState StorageState, slotState SlotState,
 	epoch uint64, info *verifierInfo, slotDuration time.Duration) *verifier {
 	return &verifier{
 		blockState:     blockState,
+		storageState:   storageState,
 		slotState:      slotState,
 		epoch:          epoch,
 		authorities:    info.authorities,
@@ -338,6 +343,16 @@ func (b *verifier) submitAndReportEquivocation(equivocationProof *types.BabeEqui
 		return fmt.Errorf("getting runtime: %w", err)
 	}
 
+	b.storageState.Lock()
+	defer b.storageState.Unlock()
+
+	trieState, err := b.storageState.TrieState(nil)
+	if err != nil {
+		return fmt.Errorf("getting best block trie state: %w", err)
+	}
+
+	runtimeInstance.SetContextStorage(trieState)
+
 	keyOwnershipProof, err := runtimeInstance.BabeGenerateKeyOwnershipProof(
 		equivocationProof.Slot, equivocationProof.Offender)
 	if err != nil {
@@ -33,7 +33,7 @@ func TestVerificationManager_OnDisabled_InvalidIndex(t *testing.T) {
 	require.NoError(t, err)
 
 	slotState := state.NewSlotState(db)
-	vm := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	vm := NewVerificationManager(babeService.blockState, babeService.storageState, slotState, babeService.epochState)
 
 	epochDescriptor, err := babeService.initiateEpoch(testEpochIndex)
 	require.NoError(t, err)
@@ -57,7 +57,7 @@ func TestVerificationManager_OnDisabled_NewDigest(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	vm := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	vm := NewVerificationManager(babeService.blockState, babeService.storageState, slotState, babeService.epochState)
 
 	epochDescriptor, err := babeService.initiateEpoch(testEpochIndex)
 	require.NoError(t, err)
@@ -102,7 +102,7 @@ func TestVerificationManager_OnDisabled_DuplicateDigest(t *testing.T) {
 	db, err := database.NewPebble(t.TempDir(), true)
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
-	vm := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	vm := NewVerificationManager(babeService.blockState, babeService.storageState, slotState, babeService.epochState)
 
 	vm.epochInfo[testEpochIndex] = &verifierInfo{
 		authorities: epochDescriptor.data.authorities,
@@ -159,7 +159,7 @@ func TestVerificationManager_VerifyBlock_Secondary(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	vm := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	vm := NewVerificationManager(babeService.blockState, babeService.storageState, slotState, babeService.epochState)
 
 	const epoch = 0
 	epochDescriptor, err := babeService.initiateEpoch(epoch)
@@ -219,7 +219,7 @@ func TestVerificationManager_VerifyBlock_CurrentEpoch(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	vm := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	vm := NewVerificationManager(babeService.blockState, babeService.storageState, slotState, babeService.epochState)
 
 	epochDescriptor, err := babeService.initiateEpoch(0)
 	require.NoError(t, err)
@@ -255,7 +255,8 @@ func TestVerificationManager_VerifyBlock_FutureEpoch(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	verificationManager := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	verificationManager := NewVerificationManager(babeService.blockState, babeService.storageState,
+		slotState, babeService.epochState)
 
 	const futureEpoch = uint64(2)
 	err = babeService.epochState.(*state.EpochState).SetEpochDataRaw(futureEpoch, &types.EpochDataRaw{
@@ -304,7 +305,8 @@ func TestVerificationManager_VerifyBlock_MultipleEpochs(t *testing.T) {
 	db, err := database.NewPebble(t.TempDir(), true)
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
-	verificationManager := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	verificationManager := NewVerificationManager(babeService.blockState, babeService.storageState,
+		slotState, babeService.epochState)
 
 	const epoch = uint64(0)
 	epochDescriptor, err := babeService.initiateEpoch(epoch)
@@ -410,7 +412,7 @@ func TestVerificationManager_VerifyBlock_InvalidBlockOverThreshold(t *testing.T)
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	vm := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	vm := NewVerificationManager(babeService.blockState, babeService.storageState, slotState, babeService.epochState)
 
 	const epoch = 0
 	epochDescriptor, err := babeService.initiateEpoch(epoch)
@@ -452,7 +454,8 @@ func TestVerificationManager_VerifyBlock_InvalidBlockAuthority(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	verificationManager := NewVerificationManager(babeServiceBob.blockState, slotState, babeServiceBob.epochState)
+	verificationManager := NewVerificationManager(babeServiceBob.blockState, babeServiceBob.storageState,
+		slotState, babeServiceBob.epochState)
 
 	epochDescriptor, err := babeService.initiateEpoch(testEpochIndex)
 	require.NoError(t, err)
@@ -501,7 +504,7 @@ func TestVerifyPrimarySlotWinner(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	verifier := newVerifier(babeService.blockState, slotState, testEpochIndex, &verifierInfo{
+	verifier := newVerifier(babeService.blockState, babeService.storageState, slotState, testEpochIndex, &verifierInfo{
 		authorities: epochDescriptor.data.authorities,
 		threshold:   epochDescriptor.data.threshold,
 		randomness:  epochDescriptor.data.randomness,
@@ -534,7 +537,7 @@ func TestVerifyAuthorshipRight(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	verifier := newVerifier(babeService.blockState, slotState, testEpochIndex, &verifierInfo{
+	verifier := newVerifier(babeService.blockState, babeService.storageState, slotState, testEpochIndex, &verifierInfo{
 		authorities: epochDescriptor.data.authorities,
 		threshold:   epochDescriptor.data.threshold,
 		randomness:  epochDescriptor.data.randomness,
@@ -552,7 +555,8 @@ func TestVerifyAuthorshipRight_Equivocation(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	verificationManager := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	verificationManager := NewVerificationManager(babeService.blockState, babeService.storageState,
+		slotState, babeService.epochState)
 
 	epochData, err := babeService.initiateEpoch(testEpochIndex)
 	require.NoError(t, err)
@@ -655,7 +659,7 @@ func TestVerifyForkBlocksWithRespectiveEpochData(t *testing.T) {
 
 	digestHandler.Start()
 
-	verificationManager := NewVerificationManager(stateService.Block, stateService.Slot, epochState)
+	verificationManager := NewVerificationManager(stateService.Block, stateService.Storage, stateService.Slot, epochState)
 
 	/*
 	* lets issue different blocks starting from genesis (a fork)
@@ -901,7 +905,8 @@ func TestVerifyBlockAfterSkippedEpochs(t *testing.T) {
 	require.NoError(t, err)
 	slotState := state.NewSlotState(db)
 
-	verificationManager := NewVerificationManager(babeService.blockState, slotState, babeService.epochState)
+	verificationManager := NewVerificationManager(babeService.blockState, babeService.storageState,
+		slotState, babeService.epochState)
 
 	firstBlockSlot := Slot{
 		start:    time.Unix(0, 0),
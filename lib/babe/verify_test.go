@@ -14,6 +14,7 @@
 	"github.com/ChainSafe/gossamer/lib/babe/mocks"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -71,6 +72,14 @@ func encodeAndHashHeader(t *testing.T, header *types.Header) common.Hash {
 	return hash
 }
 
+func newBestStorageStateMock(ctrl *gomock.Controller, trieState *rtstorage.TrieState) *MockStorageState {
+	storageState := NewMockStorageState(ctrl)
+	storageState.EXPECT().Lock()
+	storageState.EXPECT().Unlock()
+	storageState.EXPECT().TrieState(nil).Return(trieState, nil)
+	return storageState
+}
+
 func newTestVerifier(kp *sr25519.Keypair, blockState BlockState, slotState SlotState,
 	threshold *scale.Uint128, secSlots bool) *verifier {
 	authority := types.NewAuthority(kp.Public(), uint64(1))
@@ -80,7 +89,7 @@ func newTestVerifier(kp *sr25519.Keypair, blockState BlockState, slotState SlotS
 		threshold:      threshold,
 		secondarySlots: secSlots,
 	}
-	return newVerifier(blockState, slotState, 1, info, testSlotDuration)
+	return newVerifier(blockState, nil, slotState, 1, info, testSlotDuration)
 }
 
 func Test_getAuthorityIndex(t *testing.T) {
@@ -250,7 +259,7 @@ type args struct {
 	}{
 		{
 			name:     "Over threshold",
-			verifier: *newVerifier(mockBlockState, mockSlotState, 1, vi, time.Second),
+			verifier: *newVerifier(mockBlockState, nil, mockSlotState, 1, vi, time.Second),
 			args: args{
 				slot:      1,
 				vrfOutput: [32]byte{},
@@ -260,7 +269,7 @@ type args struct {
 		},
 		{
 			name:     "VRF not verified",
-			verifier: *newVerifier(mockBlockState, mockSlotState, 1, vi1, time.Second),
+			verifier: *newVerifier(mockBlockState, nil, mockSlotState, 1, vi1, time.Second),
 			args: args{
 				slot:      1,
 				vrfOutput: [32]byte{},
@@ -269,7 +278,7 @@ type args struct {
 		},
 		{
 			name:     "VRF verified",
-			verifier: *newVerifier(mockBlockState, mockSlotState, 1, vi1, time.Second),
+			verifier: *newVerifier(mockBlockState, nil, mockSlotState, 1, vi1, time.Second),
 			args: args{
 				slot:      1,
 				vrfOutput: output,
@@ -321,10 +330,10 @@ func Test_verifier_verifyPreRuntimeDigest(t *testing.T) {
 
 	mockSlotState := NewMockSlotState(nil)
 
-	v := newVerifier(mockBlockState, mockSlotState, 1, vi, testSlotDuration)
+	v := newVerifier(mockBlockState, nil, mockSlotState, 1, vi, testSlotDuration)
 
 	// Invalid
-	v2 := newVerifier(mockBlockState, mockSlotState, 13, vi, testSlotDuration)
+	v2 := newVerifier(mockBlockState, nil, mockSlotState, 13, vi, testSlotDuration)
 
 	// Above threshold case
 	vi1 := &verifierInfo{
@@ -332,7 +341,7 @@ func Test_verifier_verifyPreRuntimeDigest(t *testing.T) {
 		threshold:   &scale.Uint128{},
 	}
 
-	v1 := newVerifier(mockBlockState, mockSlotState, 1, vi1, testSlotDuration)
+	v1 := newVerifier(mockBlockState, nil, mockSlotState, 1, vi1, testSlotDuration)
 
 	// BabeSecondaryVRFPreDigest case
 	secVRFDigest := types.BabeSecondaryVRFPreDigest{
@@ -426,25 +435,25 @@ type args struct {
 		},
 		{
 			name:     "BabeSecondaryPlainPreDigest SecondarySlot false",
-			verifier: *newVerifier(mockBlockState, mockSlotState, 1, viSec, testSlotDuration),
+			verifier: *newVerifier(mockBlockState, nil, mockSlotState, 1, viSec, testSlotDuration),
 			args:     args{prd},
 			expErr:   ErrBadSlotClaim,
 		},
 		{
 			name:     "BabeSecondaryPlainPreDigest invalid claim",
-			verifier: *newVerifier(mockBlockState, mockSlotState, 1, viSec2, testSlotDuration),
+			verifier: *newVerifier(mockBlockState, nil, mockSlotState, 1, viSec2, testSlotDuration),
 			args:     args{prd},
 			expErr:   errors.New("invalid secondary slot claim"),
 		},
 		{
 			name:     "BabeSecondaryVRFPreDigest SecondarySlot false",
-			verifier: *newVerifier(mockBlockState, mockSlotState, 1, viVRFSec, testSlotDuration),
+			verifier: *newVerifier(mockBlockState, nil, mockSlotState, 1, viVRFSec, testSlotDuration),
 			args:     args{babePRD},
 			expErr:   ErrBadSlotClaim,
 		},
 		{
 			name:     "BabeSecondaryVRFPreDigest invalid claim",
-			verifier: *newVerifier(mockBlockState, mockSlotState, 1, viVRFSec2, testSlotDuration),
+			verifier: *newVerifier(mockBlockState, nil, mockSlotState, 1, viVRFSec2, testSlotDuration),
 			args:     args{babePRD},
 			expErr:   errors.New("invalid secondary slot claim"),
 		},
@@ -847,6 +856,9 @@ func Test_verifyBlockEquivocation(t *testing.T) {
 
 				mockBlockState.EXPECT().GetRuntime(defaultHeader.Hash()).Return(mockRuntimeInstance, nil)
 
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntimeInstance.EXPECT().SetContextStorage(trieState)
+
 				return &verifier{
 					authorities: []types.AuthorityRaw{
 						{
@@ -855,6 +867,7 @@ func Test_verifyBlockEquivocation(t *testing.T) {
 						},
 					},
 					blockState:   mockBlockState,
+					storageState: newBestStorageStateMock(ctrl, trieState),
 					slotState:    mockSlotState,
 					slotDuration: 6 * time.Second,
 				}
@@ -940,7 +953,10 @@ func Test_verifier_submitAndReportEquivocation(t *testing.T) {
 	mockBlockState := NewMockBlockState(ctrl)
 	mockSlotState := NewMockSlotState(nil)
 
-	verifier := newVerifier(mockBlockState, mockSlotState, 1, vi, testSlotDuration)
+	trieState := rtstorage.NewTrieState(nil)
+	mockStorageState := newBestStorageStateMock(ctrl, trieState)
+
+	verifier := newVerifier(mockBlockState, mockStorageState, mockSlotState, 1, vi, testSlotDuration)
 
 	const slot = uint64(1)
 	const authorityIndex = uint32(1)
@@ -990,6 +1006,7 @@ func Test_verifier_submitAndReportEquivocation(t *testing.T) {
 
 	mockBlockState.EXPECT().BestBlockHash().Return(firstHash).Times(2)
 	mockBlockState.EXPECT().GetRuntime(firstHash).Return(mockRuntime, nil)
+	mockRuntime.EXPECT().SetContextStorage(trieState)
 
 	err = verifier.submitAndReportEquivocation(&equivocationProof)
 	assert.NoError(t, err)
@@ -1068,13 +1085,16 @@ func Test_verifier_verifyAuthorshipRightEquivocatory(t *testing.T) {
 					Return(nil)
 
 				mockBlockState.EXPECT().GetRuntime(header.Hash()).Return(mockRuntime, nil)
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntime.EXPECT().SetContextStorage(trieState)
 				auth := types.NewAuthority(kp.Public(), uint64(1))
 				info := &verifierInfo{
 					authorities: []types.AuthorityRaw{*auth.ToRaw(), *auth.ToRaw()},
 					threshold:   scale.MaxUint128,
 				}
 
-				return newVerifier(mockBlockState, mockSlotState, 1, info, testSlotDuration)
+				mockStorageState := newBestStorageStateMock(ctrl, trieState)
+				return newVerifier(mockBlockState, mockStorageState, mockSlotState, 1, info, testSlotDuration)
 			},
 			expErr: func(h *types.Header) error {
 				return fmt.Errorf("%w for block header %s", ErrProducerEquivocated, h.Hash())
@@ -1129,6 +1149,8 @@ func Test_verifier_verifyAuthorshipRightEquivocatory(t *testing.T) {
 					Return(nil)
 
 				mockBlockState.EXPECT().GetRuntime(header.Hash()).Return(mockRuntime, nil)
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntime.EXPECT().SetContextStorage(trieState)
 				auth := types.NewAuthority(kp.Public(), uint64(1))
 				info := &verifierInfo{
 					authorities:    []types.AuthorityRaw{*auth.ToRaw(), *auth.ToRaw()},
@@ -1137,7 +1159,8 @@ func Test_verifier_verifyAuthorshipRightEquivocatory(t *testing.T) {
 					randomness:     Randomness{},
 				}
 
-				return newVerifier(mockBlockState, mockSlotState, 1, info, testSlotDuration)
+				mockStorageState := newBestStorageStateMock(ctrl, trieState)
+				return newVerifier(mockBlockState, mockStorageState, mockSlotState, 1, info, testSlotDuration)
 			},
 			expErr: func(h *types.Header) error {
 				return fmt.Errorf("%w for block header %s", ErrProducerEquivocated, h.Hash())
@@ -1199,6 +1222,8 @@ func Test_verifier_verifyAuthorshipRightEquivocatory(t *testing.T) {
 					Return(nil)
 
 				mockBlockState.EXPECT().GetRuntime(header.Hash()).Return(mockRuntime, nil)
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntime.EXPECT().SetContextStorage(trieState)
 
 				auth := types.NewAuthority(kp.Public(), uint64(1))
 				info := &verifierInfo{
@@ -1208,7 +1233,8 @@ func Test_verifier_verifyAuthorshipRightEquivocatory(t *testing.T) {
 					randomness:     Randomness{},
 				}
 
-				return newVerifier(mockBlockState, mockSlotState, 1, info, testSlotDuration)
+				mockStorageState := newBestStorageStateMock(ctrl, trieState)
+				return newVerifier(mockBlockState, mockStorageState, mockSlotState, 1, info, testSlotDuration)
 			},
 		},
 	}
@@ -1373,7 +1399,7 @@ func TestVerificationManager_VerifyBlock(t *testing.T) {
 				mockEpochStateGetEpochErr.EXPECT().GetEpochForBlock(testBlockHeaderEmpty).
 					Return(uint64(0), errTestGetEpoch)
 
-				return NewVerificationManager(mockBlockState,
+				return NewVerificationManager(mockBlockState, nil,
 					NewMockSlotState(nil), mockEpochStateGetEpochErr)
 			},
 			expErr: fmt.Errorf("getting epoch for block header: %w", errTestGetEpoch),
@@ -1398,7 +1424,7 @@ func TestVerificationManager_VerifyBlock(t *testing.T) {
 				mockEpochState.EXPECT().GetEpochDataRaw(uint64(1), testBlockHeaderEmpty).
 					Return(nil, errTestGetEpochData)
 
-				return NewVerificationManager(mockBlockState, NewMockSlotState(nil), mockEpochState)
+				return NewVerificationManager(mockBlockState, nil, NewMockSlotState(nil), mockEpochState)
 			},
 			header: testBlockHeaderEmpty,
 			expErr: fmt.Errorf("getting verifier info: "+
@@ -1426,7 +1452,7 @@ func TestVerificationManager_VerifyBlock(t *testing.T) {
 				mockEpochState.EXPECT().GetConfigData(uint64(1), testBlockHeaderEmpty).
 					Return(nil, errTestGetEpochData)
 
-				return NewVerificationManager(mockBlockState, NewMockSlotState(nil), mockEpochState)
+				return NewVerificationManager(mockBlockState, nil, NewMockSlotState(nil), mockEpochState)
 			},
 			header: testBlockHeaderEmpty,
 			expErr: fmt.Errorf("getting verifier info: "+
@@ -1466,7 +1492,7 @@ func TestVerificationManager_VerifyBlock(t *testing.T) {
 						[32]byte(kp.Public().Encode())).
 					Return(nil, nil)
 
-				return NewVerificationManager(mockBlockState, mockSlotState, mockEpochState)
+				return NewVerificationManager(mockBlockState, nil, mockSlotState, mockEpochState)
 			},
 			header: headerWithPreRuntimeDigest,
 		},
@@ -1562,27 +1588,27 @@ func TestVerificationManager_SetOnDisabled(t *testing.T) {
 		},
 	}
 
-	vm0 := NewVerificationManager(mockBlockStateEmpty, mockSlotState, mockEpochStateGetEpochErr)
-	vm1 := NewVerificationManager(mockBlockStateEmpty, mockSlotState, mockEpochStateGetEpochDataErr)
+	vm0 := NewVerificationManager(mockBlockStateEmpty, nil, mockSlotState, mockEpochStateGetEpochErr)
+	vm1 := NewVerificationManager(mockBlockStateEmpty, nil, mockSlotState, mockEpochStateGetEpochDataErr)
 	vm1.epochInfo[1] = info
 
-	vm2 := NewVerificationManager(mockBlockStateEmpty, mockSlotState, mockEpochStateIndexLenErr)
+	vm2 := NewVerificationManager(mockBlockStateEmpty, nil, mockSlotState, mockEpochStateIndexLenErr)
 	vm2.epochInfo[2] = info
 
-	vm3 := NewVerificationManager(mockBlockStateEmpty, mockSlotState, mockEpochStateSetDisabledProd)
+	vm3 := NewVerificationManager(mockBlockStateEmpty, nil, mockSlotState, mockEpochStateSetDisabledProd)
 	vm3.epochInfo[2] = info
 
-	vm4 := NewVerificationManager(mockBlockStateIsDescendantErr, mockSlotState, mockEpochStateOk)
+	vm4 := NewVerificationManager(mockBlockStateIsDescendantErr, nil, mockSlotState, mockEpochStateOk)
 	vm4.epochInfo[2] = info
 	vm4.onDisabled[2] = map[uint32][]*onDisabledInfo{}
 	vm4.onDisabled[2][0] = disabledInfo
 
-	vm5 := NewVerificationManager(mockBlockStateAuthorityDisabled, mockSlotState, mockEpochStateOk2)
+	vm5 := NewVerificationManager(mockBlockStateAuthorityDisabled, nil, mockSlotState, mockEpochStateOk2)
 	vm5.epochInfo[2] = info
 	vm5.onDisabled[2] = map[uint32][]*onDisabledInfo{}
 	vm5.onDisabled[2][0] = disabledInfo
 
-	vm6 := NewVerificationManager(mockBlockStateOk, mockSlotState, mockEpochStateOk3)
+	vm6 := NewVerificationManager(mockBlockStateOk, nil, mockSlotState, mockEpochStateOk3)
 	vm6.epochInfo[2] = info
 	vm6.onDisabled[2] = map[uint32][]*onDisabledInfo{}
 	vm6.onDisabled[2][0] = disabledInfo
@@ -22,13 +22,29 @@ func newHashToRuntime() *hashToRuntime {
 	}
 }
 
+// get returns the runtime instance of the block hash. A pooled instance is handed out
+// as a new handle on its executor, such that the callers can set their own context
+// storage and execute calls concurrently.
 func (h *hashToRuntime) get(hash Hash) (instance runtime.Instance) {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
-	return h.mapping[hash]
+
+	instance = h.mapping[hash]
+	pooled, ok := instance.(runtime.Pooled)
+	if !ok {
+		return instance
+	}
+	return pooled.Handle()
 }
 
+// set sets the runtime instance of the block hash. A handle of a pooled instance is stored
+// as its parent instance, such that the instances pruned on finalisation are compared by executor.
 func (h *hashToRuntime) set(hash Hash, instance runtime.Instance) {
+	pooled, ok := instance.(runtime.Pooled)
+	if ok {
+		instance = pooled.Parent()
+	}
+
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 	h.mapping[hash] = instance
@@ -137,6 +137,38 @@ func Test_hashToRuntime_set(t *testing.T) {
 	}
 }
 
+type pooledInstance struct {
+	*MockInstance
+	parent *pooledInstance
+}
+
+func (p *pooledInstance) Handle() runtime.Instance {
+	return &pooledInstance{MockInstance: p.MockInstance, parent: p}
+}
+
+func (p *pooledInstance) Parent() runtime.Instance {
+	if p.parent != nil {
+		return p.parent
+	}
+	return p
+}
+
+func Test_hashToRuntime_pooled(t *testing.T) {
+	t.Parallel()
+
+	htr := newHashToRuntime()
+	parent := &pooledInstance{MockInstance: NewMockInstance(nil)}
+	htr.set(common.Hash{1}, parent)
+
+	handle := htr.get(common.Hash{1})
+	assert.NotSame(t, parent, handle)
+	assert.Same(t, parent, handle.(*pooledInstance).parent)
+
+	// storing a handle stores its parent
+	htr.set(common.Hash{2}, handle)
+	assert.Same(t, parent, htr.mapping[common.Hash{2}])
+}
+
 func Test_hashToRuntime_delete(t *testing.T) {
 	t.Parallel()
 
@@ -24,4 +24,7 @@
 	PruningKey = []byte("prune")
 	// CodeSubstitutedBlock is the storage key to store block hash of substituted (if there is currently code substituted)
 	CodeSubstitutedBlock = []byte("code_substituted_block")
+	// HashedValueKeysMigratedKey is the db location of the flag set once the hashed storage values are
+	// stored at the key made of their hash only.
+	HashedValueKeysMigratedKey = []byte("hashed_value_keys_migrated")
 )
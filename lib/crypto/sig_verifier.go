@@ -5,8 +5,9 @@
 
 import (
 	"errors"
+	"runtime"
 	"sync"
-	"time"
+	"sync/atomic"
 )
 
 var ErrSignatureVerificationFailed = errors.New("failed to verify signature")
@@ -14,7 +15,7 @@
 // SigVerifyFunc verifies a signature given a public key and a message
 type SigVerifyFunc func(pubkey, sig, msg []byte) (err error)
 
-// SignatureInfo ...
+// SignatureInfo holds a signature to verify with its public key, message and verification function
 type SignatureInfo struct {
 	PubKey     []byte
 	Sign       []byte
@@ -22,16 +23,19 @@ type SignatureInfo struct {
 	VerifyFunc SigVerifyFunc
 }
 
-// SignatureVerifier ...
+// SignatureVerifier verifies the signatures of a batch in the background, on a pool of workers
 type SignatureVerifier struct {
-	batch   []*SignatureInfo
+	// pending holds the signatures added before the batch is started
+	pending []*SignatureInfo
 	init    bool // Indicates whether the batch processing is started.
-	invalid bool // Set to true if any signature verification fails.
+	// invalid is set to true if any signature verification fails, it is not guarded by
+	// the mutex since the workers set it while Add may hold the mutex waiting for them.
+	invalid atomic.Bool
 	logger  Erroer
-	closeCh chan struct{}
+	// workers limits the number of signatures verified at the same time
+	workers chan struct{}
 	sync.RWMutex
-	sync.Once
-	sync.WaitGroup
+	wg sync.WaitGroup
 }
 
 // NewSignatureVerifier initialises SignatureVerifier which does background verification of signatures.
@@ -40,114 +44,93 @@ type SignatureVerifier struct {
 // Signatures can be added to the batch using Add().
 func NewSignatureVerifier(logger Erroer) *SignatureVerifier {
 	return &SignatureVerifier{
-		batch:   make([]*SignatureInfo, 0),
-		init:    false,
-		invalid: false,
 		logger:  logger,
-		RWMutex: sync.RWMutex{},
-		closeCh: make(chan struct{}),
+		workers: make(chan struct{}, runtime.NumCPU()),
 	}
 }
 
-// Start signature verification in batch.
+// Start signature verification in batch. The signatures added before are verified first.
 func (sv *SignatureVerifier) Start() {
-	// Update the init state.
 	sv.Lock()
-	sv.init = true
-	sv.Unlock()
+	defer sv.Unlock()
 
-	sv.WaitGroup.Add(1)
+	if sv.init {
+		return
+	}
+	sv.init = true
 
-	go func() {
-		defer sv.Done()
-		for {
-			select {
-			case <-sv.closeCh:
-				return
-			default:
-				signature := sv.Remove()
-				if signature == nil {
-					continue
-				}
-				err := signature.VerifyFunc(signature.PubKey, signature.Sign, signature.Msg)
-				if err != nil {
-					sv.logger.Errorf("[ext_crypto_start_batch_verify_version_1]: %s", err)
-					sv.Invalid()
-					return
-				}
-			}
-		}
-	}()
+	for _, signature := range sv.pending {
+		sv.verify(signature)
+	}
+	sv.pending = nil
 }
 
-// IsStarted ...
+// IsStarted returns true if the batch is started and not finished yet
 func (sv *SignatureVerifier) IsStarted() bool {
 	sv.RLock()
 	defer sv.RUnlock()
 	return sv.init
 }
 
-// IsInvalid ...
+// IsInvalid returns true if a signature of the batch failed verification
 func (sv *SignatureVerifier) IsInvalid() bool {
-	sv.RLock()
-	defer sv.RUnlock()
-	return sv.invalid
+	return sv.invalid.Load()
 }
 
-// Invalid ...
+// Invalid marks the batch as invalid
 func (sv *SignatureVerifier) Invalid() {
-	sv.RLock()
-	defer sv.RUnlock()
-	sv.invalid = true
+	sv.invalid.Store(true)
 }
 
-// Add ...
+// Add adds a signature to the batch, which is verified as soon as a worker is available.
+// It blocks while all the workers are busy.
 func (sv *SignatureVerifier) Add(s *SignatureInfo) {
-	if sv.IsInvalid() {
+	sv.Lock()
+	defer sv.Unlock()
+
+	if sv.invalid.Load() {
 		return
 	}
 
-	sv.Lock()
-	defer sv.Unlock()
-	sv.batch = append(sv.batch, s)
+	if !sv.init {
+		sv.pending = append(sv.pending, s)
+		return
+	}
+	sv.verify(s)
 }
 
-// Remove returns the first signature from the batch. Returns nil if batch is empty.
-func (sv *SignatureVerifier) Remove() *SignatureInfo {
-	sv.Lock()
-	defer sv.Unlock()
-	if len(sv.batch) == 0 {
-		return nil
-	}
-	sign := sv.batch[0]
-	sv.batch = sv.batch[1:len(sv.batch)]
-	return sign
+// verify verifies the signature on a worker, it must be called with the lock held.
+func (sv *SignatureVerifier) verify(s *SignatureInfo) {
+	sv.wg.Add(1)
+	sv.workers <- struct{}{}
+	go func() {
+		defer func() {
+			<-sv.workers
+			sv.wg.Done()
+		}()
+
+		err := s.VerifyFunc(s.PubKey, s.Sign, s.Msg)
+		if err != nil {
+			sv.logger.Errorf("[ext_crypto_start_batch_verify_version_1]: %s", err)
+			sv.Invalid()
+		}
+	}()
 }
 
-// Reset reset the signature verifier for reuse.
+// Reset waits for the signatures being verified and resets the signature verifier for reuse.
 func (sv *SignatureVerifier) Reset() {
+	sv.wg.Wait()
+
 	sv.Lock()
 	defer sv.Unlock()
 	sv.init = false
-	sv.batch = make([]*SignatureInfo, 0)
-	sv.invalid = false
-	sv.closeCh = make(chan struct{})
+	sv.pending = nil
+	sv.invalid.Store(false)
 }
 
 // Finish waits till batch is finished. Returns true if all the signatures are valid, Otherwise returns false.
 func (sv *SignatureVerifier) Finish() bool {
-	for {
-		time.Sleep(100 * time.Millisecond)
-		sv.Lock()
-		if sv.invalid || len(sv.batch) == 0 {
-			close(sv.closeCh)
-			sv.Unlock()
-			break
-		}
-		sv.RUnlock()
-	}
-	// Wait till start function to finish and then reset it.
-	sv.Wait()
+	sv.wg.Wait()
 	isInvalid := sv.IsInvalid()
 	sv.Reset()
 	return !isInvalid
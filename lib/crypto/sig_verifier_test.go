@@ -124,3 +124,50 @@ func TestVerifySignature(t *testing.T) {
 	}
 
 }
+
+func TestSignatureVerifier_WorkerPool(t *testing.T) {
+	t.Parallel()
+
+	message := []byte("message")
+	keypair, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+	signature, err := keypair.Sign(message)
+	require.NoError(t, err)
+
+	valid := &crypto.SignatureInfo{
+		PubKey:     keypair.Public().Encode(),
+		Sign:       signature,
+		Msg:        message,
+		VerifyFunc: ed25519.VerifySignature,
+	}
+	invalid := &crypto.SignatureInfo{
+		PubKey:     keypair.Public().Encode(),
+		Sign:       signature,
+		Msg:        []byte("other message"),
+		VerifyFunc: ed25519.VerifySignature,
+	}
+
+	// more signatures than workers, with failures while workers are busy
+	const signatures = 256
+	signVerify := crypto.NewSignatureVerifier(log.New(log.SetWriter(io.Discard)))
+
+	signVerify.Start()
+	for i := 0; i < signatures; i++ {
+		signVerify.Add(valid)
+	}
+	require.True(t, signVerify.Finish())
+
+	signVerify.Start()
+	for i := 0; i < signatures; i++ {
+		if i%16 == 0 {
+			signVerify.Add(invalid)
+			continue
+		}
+		signVerify.Add(valid)
+	}
+	require.False(t, signVerify.Finish())
+
+	// the verifier is reset once finished
+	require.False(t, signVerify.IsStarted())
+	require.False(t, signVerify.IsInvalid())
+}
@@ -0,0 +1,783 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package grandpa
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/telemetry"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+
+	finality_grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
+)
+
+// the finality-grandpa types instantiated with the block hash, block number,
+// signature and authority ID types used by the voter.
+type (
+	finalityVoter              = finality_grandpa.Voter[hash.H256, uint32, [64]byte, string]
+	finalityEnvironment        = finality_grandpa.Environment[hash.H256, uint32, [64]byte, string]
+	finalityHashNumber         = finality_grandpa.HashNumber[hash.H256, uint32]
+	finalityMessage            = finality_grandpa.Message[hash.H256, uint32]
+	finalitySignedMessage      = finality_grandpa.SignedMessage[hash.H256, uint32, [64]byte, string]
+	finalitySignedMessageError = finality_grandpa.SignedMessageError[hash.H256, uint32, [64]byte, string]
+	finalityCommit             = finality_grandpa.Commit[hash.H256, uint32, [64]byte, string]
+	finalityHistoricalVotes    = finality_grandpa.HistoricalVotes[hash.H256, uint32, [64]byte, string]
+	finalityRoundState         = finality_grandpa.RoundState[hash.H256, uint32]
+	finalityRoundData          = finality_grandpa.RoundData[hash.H256, uint32, [64]byte, string]
+	finalityBestChain          = finality_grandpa.BestChain[hash.H256, uint32]
+	finalityBestChainOutput    = finality_grandpa.BestChainOutput[hash.H256, uint32]
+	finalityOutput             = finality_grandpa.Output[hash.H256, uint32]
+	finalityPrevote            = finality_grandpa.Prevote[hash.H256, uint32]
+	finalityPrecommit          = finality_grandpa.Precommit[hash.H256, uint32]
+	finalityPrimaryPropose     = finality_grandpa.PrimaryPropose[hash.H256, uint32]
+	finalityHasVoted           = finality_grandpa.HasVoted[hash.H256, uint32]
+)
+
+var _ finalityEnvironment = (*environment)(nil)
+
+var errNotDescendant = errors.New("block is not a descendant of base")
+
+// roundIncomingCapacity is the number of votes buffered for a round before
+// the votes are dropped, allowing some duplicates for each vote of a voter.
+const roundIncomingCapacity = 1024
+
+// roundChannels are the channels of a voting round.
+type roundChannels struct {
+	incoming chan finalitySignedMessageError
+	// done is closed when the round is concluded or the environment is stopped.
+	done chan struct{}
+}
+
+// environment is the finality-grandpa voter environment of an authority set.
+// It is backed by the block and grandpa states, the network and the keypair
+// of the grandpa service.
+type environment struct {
+	grandpa *Service
+	setID   uint64
+	voters  []Voter
+	// localID is the authority ID of the node, nil if the node is not a voter of the set.
+	localID *string
+
+	mtx sync.Mutex
+	// rounds holds the channels of the rounds not concluded yet.
+	rounds map[uint64]*roundChannels
+	// concluded is the highest concluded round number.
+	concluded uint64
+	stopCh    chan struct{}
+	stopped   bool
+}
+
+func newEnvironment(grandpa *Service, setID uint64, voters []Voter) *environment {
+	var localID *string
+	if grandpa.authority {
+		publicKey := grandpa.publicKeyBytes()
+		for _, voter := range voters {
+			if voter.PublicKeyBytes() == publicKey {
+				id := authorityIDToString(publicKey)
+				localID = &id
+				break
+			}
+		}
+	}
+
+	return &environment{
+		grandpa: grandpa,
+		setID:   setID,
+		voters:  voters,
+		localID: localID,
+		rounds:  make(map[uint64]*roundChannels),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// stop closes the channels of all the rounds. It must be called once the voter is stopped.
+func (e *environment) stop() {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.stopped {
+		return
+	}
+	e.stopped = true
+	close(e.stopCh)
+
+	for round, channels := range e.rounds {
+		close(channels.done)
+		close(channels.incoming)
+		delete(e.rounds, round)
+	}
+}
+
+// voterSet returns the finality-grandpa voter set of the authority set.
+func (e *environment) voterSet() (*finality_grandpa.VoterSet[string], error) {
+	idsAndWeights := make([]finality_grandpa.IDWeight[string], len(e.voters))
+	for i, voter := range e.voters {
+		idsAndWeights[i] = finality_grandpa.IDWeight[string]{
+			ID:     authorityIDToString(voter.PublicKeyBytes()),
+			Weight: 1,
+		}
+	}
+
+	voterSet := finality_grandpa.NewVoterSet(idsAndWeights)
+	if voterSet == nil {
+		return nil, fmt.Errorf("%w: set id %d", errInvalidVoterSet, e.setID)
+	}
+	return voterSet, nil
+}
+
+// roundChannels returns the channels of the round, creating them if
+// needed. It returns nil if the round is concluded.
+// It must be called with the environment mutex held.
+func (e *environment) roundChannels(round uint64) *roundChannels {
+	channels, ok := e.rounds[round]
+	if ok {
+		return channels
+	}
+
+	if e.stopped || round <= e.concluded {
+		return nil
+	}
+
+	channels = &roundChannels{
+		incoming: make(chan finalitySignedMessageError, roundIncomingCapacity),
+		done:     make(chan struct{}),
+	}
+	e.rounds[round] = channels
+	return channels
+}
+
+// importMessage sends a signed message to the voting round, the message
+// is buffered until the round starts if the round has not started yet.
+func (e *environment) importMessage(round uint64, message finalitySignedMessage) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	channels := e.roundChannels(round)
+	if channels == nil {
+		logger.Debugf("ignoring message for concluded round %d and set id %d", round, e.setID)
+		return
+	}
+
+	select {
+	case channels.incoming <- finalitySignedMessageError{SignedMessage: message}:
+	default:
+		logger.Warnf("dropping message for round %d and set id %d: too many pending messages", round, e.setID)
+	}
+}
+
+// importGlobalMessage sends a commit or a catch up to the voter,
+// it returns early if the environment is stopped.
+func (e *environment) importGlobalMessage(globalIn chan<- finality_grandpa.GlobalInItem,
+	message finality_grandpa.CommunicationIn) {
+	select {
+	case globalIn <- finality_grandpa.GlobalInItem{CommunicationIn: message}:
+	case <-e.stopCh:
+	}
+}
+
+// Ancestry returns the ancestry of a block up to but not including the base hash,
+// in reverse order from the block's parent.
+func (e *environment) Ancestry(base, block hash.H256) ([]hash.H256, error) {
+	baseHash := common.NewHash(base.Bytes())
+	baseHeader, err := e.grandpa.blockState.GetHeader(baseHash)
+	if err != nil {
+		return nil, fmt.Errorf("getting base header: %w", err)
+	}
+
+	header, err := e.grandpa.blockState.GetHeader(common.NewHash(block.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("getting block header: %w", err)
+	}
+
+	var ancestry []hash.H256
+	for {
+		if header.Number <= baseHeader.Number {
+			return nil, fmt.Errorf("%w: block %s and base %s", errNotDescendant, block, base)
+		}
+
+		if header.ParentHash == baseHash {
+			return ancestry, nil
+		}
+
+		ancestry = append(ancestry, hash.H256(header.ParentHash.ToBytes()))
+		header, err = e.grandpa.blockState.GetHeader(header.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("getting ancestor header: %w", err)
+		}
+	}
+}
+
+// IsEqualOrDescendantOf returns true if the block is the base or a descendant of the base.
+func (e *environment) IsEqualOrDescendantOf(base, block hash.H256) bool {
+	if base == block {
+		return true
+	}
+
+	isDescendant, err := e.grandpa.blockState.IsDescendantOf(
+		common.NewHash(base.Bytes()), common.NewHash(block.Bytes()))
+	if err != nil {
+		logger.Debugf("checking if block %s is a descendant of %s: %s", block, base, err)
+		return false
+	}
+	return isDescendant
+}
+
+// BestChainContaining returns a channel producing the best block whose chain contains the base.
+func (e *environment) BestChainContaining(base hash.H256) finalityBestChain {
+	bestChain := make(chan finalityBestChainOutput, 1)
+	target, err := e.bestChainContaining(common.NewHash(base.Bytes()))
+	bestChain <- finalityBestChainOutput{Value: target, Error: err}
+	return bestChain
+}
+
+// bestChainContaining returns the head of the longest chain containing the base, limited
+// to the block enacting the next authority set change. It returns nil if the base is unknown.
+func (e *environment) bestChainContaining(base common.Hash) (*finalityHashNumber, error) {
+	baseHeader, err := e.grandpa.blockState.GetHeader(base)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("getting base header: %w", err)
+	}
+
+	target := baseHeader
+	for _, leaf := range e.grandpa.blockState.Leaves() {
+		isDescendant, err := e.grandpa.blockState.IsDescendantOf(base, leaf)
+		if err != nil {
+			return nil, fmt.Errorf("checking if leaf %s is a descendant of base: %w", leaf, err)
+		}
+
+		if !isDescendant {
+			continue
+		}
+
+		leafHeader, err := e.grandpa.blockState.GetHeader(leaf)
+		if err != nil {
+			return nil, fmt.Errorf("getting leaf header: %w", err)
+		}
+
+		if leafHeader.Number > target.Number {
+			target = leafHeader
+		}
+	}
+
+	// the voters must not vote past the block enacting the next authority set change
+	// nor past the block enacting the next pause
+	limit, hasLimit := uint(0), false
+	nextChange, err := e.grandpa.grandpaState.NextGrandpaAuthorityChange(target.Hash(), target.Number)
+	if err != nil && !errors.Is(err, state.ErrNoNextAuthorityChange) {
+		return nil, fmt.Errorf("getting next grandpa authority change: %w", err)
+	} else if err == nil {
+		limit, hasLimit = nextChange, true
+	}
+
+	nextPause, err := e.grandpa.grandpaState.NextGrandpaPause(target.Hash(), target.Number)
+	if err != nil && !errors.Is(err, state.ErrNoNextPause) {
+		return nil, fmt.Errorf("getting next grandpa pause: %w", err)
+	} else if err == nil && (!hasLimit || nextPause < limit) {
+		limit, hasLimit = nextPause, true
+	}
+
+	if hasLimit {
+		for target.Number > limit && target.Number > baseHeader.Number {
+			target, err = e.grandpa.blockState.GetHeader(target.ParentHash)
+			if err != nil {
+				return nil, fmt.Errorf("getting ancestor header: %w", err)
+			}
+		}
+	}
+
+	return &finalityHashNumber{
+		Hash:   hash.H256(target.Hash().ToBytes()),
+		Number: uint32(target.Number),
+	}, nil
+}
+
+// RoundData returns the data necessary to participate in the round.
+func (e *environment) RoundData(round uint64, outgoing finalityOutput) finalityRoundData {
+	e.grandpa.setRound(round)
+
+	e.mtx.Lock()
+	channels := e.roundChannels(round)
+	concluded := channels == nil
+	if concluded {
+		// the round is concluded or the environment is stopped, no more messages are imported
+		channels = &roundChannels{
+			incoming: make(chan finalitySignedMessageError),
+			done:     make(chan struct{}),
+		}
+		close(channels.done)
+		close(channels.incoming)
+	}
+	e.mtx.Unlock()
+
+	go e.sendMessages(round, outgoing, channels.done)
+
+	roundData := finalityRoundData{
+		VoterID:        e.localID,
+		PrevoteTimer:   finality_grandpa.NewTimer(time.After(2 * e.grandpa.interval)),
+		PrecommitTimer: finality_grandpa.NewTimer(time.After(4 * e.grandpa.interval)),
+		Incoming:       channels.incoming,
+	}
+
+	if !concluded {
+		hasVoted, err := e.restoreVotes(round)
+		if err != nil {
+			// the node does not vote in the round rather than risking an equivocation
+			logger.Errorf("restoring votes cast in round %d and set id %d: %s", round, e.setID, err)
+			roundData.VoterID = nil
+		}
+		roundData.HasVoted = hasVoted
+	}
+	return roundData
+}
+
+// restoreVotes sends again the votes cast by the node in the round before a restart,
+// importing them back into the round, and returns them so the round resumes after them.
+func (e *environment) restoreVotes(round uint64) (finalityHasVoted, error) {
+	if e.localID == nil {
+		return finalityHasVoted{}, nil
+	}
+
+	hasVoted, err := e.hasVoted(round)
+	if err != nil {
+		return finalityHasVoted{}, err
+	}
+
+	votesCast := []struct {
+		vote  *Vote
+		stage Subround
+	}{
+		{hasVoted.Propose, primaryProposal},
+		{hasVoted.Prevote, prevote},
+		{hasVoted.Precommit, precommit},
+	}
+
+	var restored finalityHasVoted
+	for _, voteCast := range votesCast {
+		if voteCast.vote == nil {
+			continue
+		}
+
+		message, err := voteToMessage(*voteCast.vote, voteCast.stage)
+		if err != nil {
+			return finalityHasVoted{}, err
+		}
+
+		err = e.sendMessage(round, message)
+		if err != nil {
+			return finalityHasVoted{}, fmt.Errorf("sending %s vote: %w", voteCast.stage, err)
+		}
+
+		targetHash, targetNumber := hash.H256(voteCast.vote.Hash.ToBytes()), voteCast.vote.Number
+		switch voteCast.stage {
+		case primaryProposal:
+			restored.Propose = &finalityPrimaryPropose{TargetHash: targetHash, TargetNumber: targetNumber}
+		case prevote:
+			restored.Prevote = &finalityPrevote{TargetHash: targetHash, TargetNumber: targetNumber}
+		case precommit:
+			restored.Precommit = &finalityPrecommit{TargetHash: targetHash, TargetNumber: targetNumber}
+		}
+	}
+	return restored, nil
+}
+
+// sendMessages signs and gossips the messages of the round until the round is done.
+func (e *environment) sendMessages(round uint64, outgoing finalityOutput, done <-chan struct{}) {
+	for {
+		select {
+		case message := <-outgoing:
+			err := e.sendMessage(round, message)
+			if err != nil {
+				logger.Errorf("sending message for round %d and set id %d: %s", round, e.setID, err)
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// sendMessage signs and gossips the message if the node is a voter
+// of the set, and imports it back into the round.
+func (e *environment) sendMessage(round uint64, message finalityMessage) error {
+	if e.localID == nil {
+		return nil
+	}
+
+	vote, stage, err := messageToVote(message)
+	if err != nil {
+		return err
+	}
+
+	_, voteMessage, err := e.grandpa.signVote(vote, stage, round, e.setID)
+	if err != nil {
+		return fmt.Errorf("signing vote: %w", err)
+	}
+
+	consensusMessage, err := voteMessage.ToConsensusMessage()
+	if err != nil {
+		return fmt.Errorf("transforming vote into consensus message: %w", err)
+	}
+
+	logger.Debugf("sending vote message: %s", voteMessage)
+	e.grandpa.network.GossipMessage(consensusMessage)
+
+	e.importMessage(round, finalitySignedMessage{
+		Message:   message,
+		Signature: voteMessage.Message.Signature,
+		ID:        *e.localID,
+	})
+	return nil
+}
+
+// RoundCommitTimer returns a timer delaying the broadcast of a commit
+// by a random duration, to minimise the number of commits sent.
+func (*environment) RoundCommitTimer() finality_grandpa.Timer {
+	delay := time.Duration(rand.Int63n(1000)) * time.Millisecond //nolint:gosec
+	return finality_grandpa.NewTimer(time.After(delay))
+}
+
+// Proposed notes that a primary proposal was made in the round.
+func (e *environment) Proposed(round uint64, propose finalityPrimaryPropose) error {
+	logger.Debugf("proposed block %s (#%d) in round %d and set id %d",
+		propose.TargetHash, propose.TargetNumber, round, e.setID)
+	return e.noteVoted(round, primaryProposal, propose.TargetHash, propose.TargetNumber)
+}
+
+// Prevoted notes that we prevoted in the round.
+func (e *environment) Prevoted(round uint64, vote finalityPrevote) error {
+	logger.Debugf("prevoted block %s (#%d) in round %d and set id %d",
+		vote.TargetHash, vote.TargetNumber, round, e.setID)
+	return e.noteVoted(round, prevote, vote.TargetHash, vote.TargetNumber)
+}
+
+// Precommitted notes that we precommitted in the round.
+func (e *environment) Precommitted(round uint64, vote finalityPrecommit) error {
+	logger.Debugf("precommitted block %s (#%d) in round %d and set id %d",
+		vote.TargetHash, vote.TargetNumber, round, e.setID)
+	return e.noteVoted(round, precommit, vote.TargetHash, vote.TargetNumber)
+}
+
+// hasVoted returns the votes cast by the node in the round.
+func (e *environment) hasVoted(round uint64) (types.GrandpaHasVoted, error) {
+	hasVoted, err := e.grandpa.grandpaState.GetHasVoted(round, e.setID)
+	if errors.Is(err, database.ErrNotFound) {
+		return types.GrandpaHasVoted{}, nil
+	} else if err != nil {
+		return types.GrandpaHasVoted{}, fmt.Errorf("getting votes cast in round %d: %w", round, err)
+	}
+	return hasVoted, nil
+}
+
+// noteVoted persists the vote cast by the node in the subround of the round, so the
+// round resumes after it on restart. A vote already persisted for the subround is kept.
+func (e *environment) noteVoted(round uint64, stage Subround, targetHash hash.H256, targetNumber uint32) error {
+	if e.localID == nil {
+		return nil
+	}
+
+	hasVoted, err := e.hasVoted(round)
+	if err != nil {
+		return err
+	}
+
+	vote := NewVote(common.NewHash(targetHash.Bytes()), targetNumber)
+	switch stage {
+	case primaryProposal:
+		if hasVoted.Propose != nil {
+			return nil
+		}
+		hasVoted.Propose = vote
+	case prevote:
+		if hasVoted.Prevote != nil {
+			return nil
+		}
+		hasVoted.Prevote = vote
+	case precommit:
+		if hasVoted.Precommit != nil {
+			return nil
+		}
+		hasVoted.Precommit = vote
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedSubround, stage)
+	}
+
+	err = e.grandpa.grandpaState.SetHasVoted(round, e.setID, hasVoted)
+	if err != nil {
+		return fmt.Errorf("setting votes cast in round %d: %w", round, err)
+	}
+	return nil
+}
+
+// Completed stores the votes of the completed round, allowing to answer catch up requests.
+func (e *environment) Completed(round uint64, _ finalityRoundState, _ finalityHashNumber,
+	votes finalityHistoricalVotes) error {
+	logger.Debugf("completed round %d with set id %d", round, e.setID)
+
+	err := e.storeVotes(round, votes)
+	if err != nil {
+		return fmt.Errorf("storing votes: %w", err)
+	}
+
+	err = e.grandpa.grandpaState.SetLatestRound(round)
+	if err != nil {
+		return fmt.Errorf("setting latest round: %w", err)
+	}
+	return nil
+}
+
+// Concluded stores the votes of the concluded round and stops importing messages for it.
+func (e *environment) Concluded(round uint64, _ finalityRoundState, _ finalityHashNumber,
+	votes finalityHistoricalVotes) error {
+	logger.Debugf("concluded round %d with set id %d", round, e.setID)
+
+	err := e.storeVotes(round, votes)
+	if err != nil {
+		return fmt.Errorf("storing votes: %w", err)
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	channels, ok := e.rounds[round]
+	if ok {
+		close(channels.done)
+		close(channels.incoming)
+		delete(e.rounds, round)
+	}
+
+	if round > e.concluded {
+		e.concluded = round
+	}
+	return nil
+}
+
+// storeVotes stores the prevotes and precommits seen in the round.
+func (e *environment) storeVotes(round uint64, votes finalityHistoricalVotes) error {
+	var prevotes, precommits []SignedVote
+	for _, signedMessage := range votes.Seen() {
+		vote, stage, err := messageToVote(signedMessage.Message)
+		if err != nil {
+			return err
+		}
+
+		signedVote := SignedVote{
+			Vote:        *vote,
+			Signature:   signedMessage.Signature,
+			AuthorityID: authorityIDFromString(signedMessage.ID),
+		}
+
+		switch stage {
+		case prevote:
+			prevotes = append(prevotes, signedVote)
+		case precommit:
+			precommits = append(precommits, signedVote)
+		}
+	}
+
+	err := e.grandpa.grandpaState.SetPrevotes(round, e.setID, prevotes)
+	if err != nil {
+		return fmt.Errorf("setting prevotes: %w", err)
+	}
+
+	err = e.grandpa.grandpaState.SetPrecommits(round, e.setID, precommits)
+	if err != nil {
+		return fmt.Errorf("setting precommits: %w", err)
+	}
+	return nil
+}
+
+// FinalizeBlock stores the justification of the block and finalises it.
+func (e *environment) FinalizeBlock(blockHash hash.H256, number uint32, round uint64,
+	commit finalityCommit) error {
+	highestFinalised, err := e.grandpa.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
+	if uint(number) <= highestFinalised.Number {
+		logger.Debugf("ignoring finalisation of block #%d, block #%d is already finalised",
+			number, highestFinalised.Number)
+		return nil
+	}
+
+	justification, precommits, err := e.encodeJustification(round, commit)
+	if err != nil {
+		return fmt.Errorf("encoding justification: %w", err)
+	}
+
+	finalisedHash := common.NewHash(blockHash.Bytes())
+	err = e.grandpa.blockState.SetJustification(finalisedHash, justification)
+	if err != nil {
+		return fmt.Errorf("setting justification: %w", err)
+	}
+
+	err = e.grandpa.grandpaState.SetPrecommits(round, e.setID, precommits)
+	if err != nil {
+		return fmt.Errorf("setting precommits: %w", err)
+	}
+
+	err = e.grandpa.blockState.SetFinalisedHash(finalisedHash, round, e.setID)
+	if err != nil {
+		return fmt.Errorf("setting finalised hash: %w", err)
+	}
+
+	logger.Debugf("finalised block %s (#%d) in round %d and set id %d", finalisedHash, number, round, e.setID)
+	e.grandpa.telemetry.SendMessage(telemetry.NewAfgFinalizedBlocksUpTo(finalisedHash, fmt.Sprint(number)))
+	return nil
+}
+
+// encodeJustification encodes the justification of the commit, including the headers
+// linking the precommit targets to the commit target. It returns the encoded
+// justification and the precommits of the commit.
+func (e *environment) encodeJustification(round uint64, commit finalityCommit) (
+	encoded []byte, precommits []SignedVote, err error) {
+	target := common.NewHash(commit.TargetHash.Bytes())
+	precommits = make([]SignedVote, len(commit.Precommits))
+	visited := make(map[common.Hash]struct{})
+	var voteAncestries []types.Header
+
+	for i, signedPrecommit := range commit.Precommits {
+		precommits[i] = SignedVote{
+			Vote: Vote{
+				Hash:   common.NewHash(signedPrecommit.Precommit.TargetHash.Bytes()),
+				Number: signedPrecommit.Precommit.TargetNumber,
+			},
+			Signature:   signedPrecommit.Signature,
+			AuthorityID: authorityIDFromString(signedPrecommit.ID),
+		}
+
+		current := precommits[i].Vote.Hash
+		for current != target {
+			header, err := e.grandpa.blockState.GetHeader(current)
+			if err != nil {
+				return nil, nil, fmt.Errorf("getting precommit ancestor header: %w", err)
+			}
+
+			if header.Number <= uint(commit.TargetNumber) {
+				return nil, nil, fmt.Errorf("%w: precommit for block %s and commit target %s",
+					errNotDescendant, precommits[i].Vote.Hash, target)
+			}
+
+			if _, ok := visited[current]; !ok {
+				visited[current] = struct{}{}
+				voteAncestries = append(voteAncestries, *header)
+			}
+			current = header.ParentHash
+		}
+	}
+
+	encoded, err = scale.Marshal(*newJustification(round, target, commit.TargetNumber, precommits))
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding justification: %w", err)
+	}
+
+	encodedVoteAncestries, err := scale.Marshal(voteAncestries)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encoding vote ancestries: %w", err)
+	}
+
+	return append(encoded, encodedVoteAncestries...), precommits, nil
+}
+
+// PrevoteEquivocation reports the prevote equivocation to the runtime.
+func (e *environment) PrevoteEquivocation(round uint64,
+	equivocation finality_grandpa.Equivocation[string, finalityPrevote, [64]byte]) {
+	logger.Warnf("prevote equivocation of %s in round %d and set id %d",
+		authorityIDFromString(equivocation.Identity), round, e.setID)
+
+	authorityID := authorityIDFromString(equivocation.Identity)
+	first := newSignedVote(equivocation.First.Vote.TargetHash, equivocation.First.Vote.TargetNumber,
+		equivocation.First.Signature, authorityID)
+	second := newSignedVote(equivocation.Second.Vote.TargetHash, equivocation.Second.Vote.TargetNumber,
+		equivocation.Second.Signature, authorityID)
+
+	err := e.grandpa.reportEquivocation(round, e.setID, prevote, first, second)
+	if err != nil {
+		logger.Errorf("reporting prevote equivocation: %s", err)
+	}
+}
+
+// PrecommitEquivocation reports the precommit equivocation to the runtime.
+func (e *environment) PrecommitEquivocation(round uint64,
+	equivocation finality_grandpa.Equivocation[string, finalityPrecommit, [64]byte]) {
+	logger.Warnf("precommit equivocation of %s in round %d and set id %d",
+		authorityIDFromString(equivocation.Identity), round, e.setID)
+
+	authorityID := authorityIDFromString(equivocation.Identity)
+	first := newSignedVote(equivocation.First.Vote.TargetHash, equivocation.First.Vote.TargetNumber,
+		equivocation.First.Signature, authorityID)
+	second := newSignedVote(equivocation.Second.Vote.TargetHash, equivocation.Second.Vote.TargetNumber,
+		equivocation.Second.Signature, authorityID)
+
+	err := e.grandpa.reportEquivocation(round, e.setID, precommit, first, second)
+	if err != nil {
+		logger.Errorf("reporting precommit equivocation: %s", err)
+	}
+}
+
+// newSignedVote returns the signed vote of the finality-grandpa vote target.
+func newSignedVote(targetHash hash.H256, targetNumber uint32, signature [64]byte,
+	authorityID ed25519.PublicKeyBytes) *SignedVote {
+	return &SignedVote{
+		Vote:        *NewVote(common.NewHash(targetHash.Bytes()), targetNumber),
+		Signature:   signature,
+		AuthorityID: authorityID,
+	}
+}
+
+// messageToVote returns the vote and the subround of the finality-grandpa message.
+func messageToVote(message finalityMessage) (*Vote, Subround, error) {
+	value, err := message.Value()
+	if err != nil {
+		return nil, 0, fmt.Errorf("getting message value: %w", err)
+	}
+
+	switch value := value.(type) {
+	case finalityPrevote:
+		return NewVote(common.NewHash(value.TargetHash.Bytes()), value.TargetNumber), prevote, nil
+	case finalityPrecommit:
+		return NewVote(common.NewHash(value.TargetHash.Bytes()), value.TargetNumber), precommit, nil
+	case finalityPrimaryPropose:
+		return NewVote(common.NewHash(value.TargetHash.Bytes()), value.TargetNumber), primaryProposal, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: %T", ErrUnsupportedSubround, value)
+	}
+}
+
+// voteToMessage returns the finality-grandpa message of the vote in the subround.
+func voteToMessage(vote Vote, stage Subround) (finalityMessage, error) {
+	targetHash := hash.H256(vote.Hash.ToBytes())
+	switch stage {
+	case prevote:
+		return finality_grandpa.NewMessage(finalityPrevote{TargetHash: targetHash, TargetNumber: vote.Number}), nil
+	case precommit:
+		return finality_grandpa.NewMessage(finalityPrecommit{TargetHash: targetHash, TargetNumber: vote.Number}), nil
+	case primaryProposal:
+		return finality_grandpa.NewMessage(finalityPrimaryPropose{TargetHash: targetHash, TargetNumber: vote.Number}), nil
+	default:
+		return finalityMessage{}, fmt.Errorf("%w: %s", ErrUnsupportedSubround, stage)
+	}
+}
+
+// authorityIDToString returns the finality-grandpa authority ID of the public key.
+func authorityIDToString(publicKey ed25519.PublicKeyBytes) string {
+	return string(publicKey[:])
+}
+
+// authorityIDFromString returns the public key of the finality-grandpa authority ID.
+func authorityIDFromString(id string) (publicKey ed25519.PublicKeyBytes) {
+	copy(publicKey[:], id)
+	return publicKey
+}
@@ -0,0 +1,548 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package grandpa
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/dot/state"
+	"github.com/ChainSafe/gossamer/dot/telemetry"
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	finality_grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
+)
+
+// newTestChain returns a chain of headers of the given length starting from the genesis header.
+func newTestChain(t *testing.T, length uint) []*types.Header {
+	t.Helper()
+
+	headers := []*types.Header{testGenesisHeader}
+	for number := uint(1); number < length; number++ {
+		headers = append(headers, &types.Header{
+			ParentHash: headers[number-1].Hash(),
+			Number:     number,
+			Digest:     types.NewDigest(),
+		})
+	}
+	return headers
+}
+
+func toH256(h common.Hash) hash.H256 {
+	return hash.H256(h.ToBytes())
+}
+
+func Test_environment_Ancestry(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 4)
+
+	testCases := map[string]struct {
+		base             *types.Header
+		block            *types.Header
+		blockStateGetter func(ctrl *gomock.Controller) BlockState
+		ancestry         []hash.H256
+		errWrapped       error
+		errMessage       string
+	}{
+		"direct_child": {
+			base:  chain[0],
+			block: chain[1],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[0].Hash()).Return(chain[0], nil)
+				blockState.EXPECT().GetHeader(chain[1].Hash()).Return(chain[1], nil)
+				return blockState
+			},
+		},
+		"descendant": {
+			base:  chain[0],
+			block: chain[3],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				for _, header := range chain {
+					blockState.EXPECT().GetHeader(header.Hash()).Return(header, nil)
+				}
+				return blockState
+			},
+			ancestry: []hash.H256{toH256(chain[2].Hash()), toH256(chain[1].Hash())},
+		},
+		"same_block": {
+			base:  chain[1],
+			block: chain[1],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[1].Hash()).Return(chain[1], nil).Times(2)
+				return blockState
+			},
+			errWrapped: errNotDescendant,
+			errMessage: "block is not a descendant of base: block " + toH256(chain[1].Hash()).String() +
+				" and base " + toH256(chain[1].Hash()).String(),
+		},
+		"unknown_block": {
+			base:  chain[0],
+			block: chain[1],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[0].Hash()).Return(chain[0], nil)
+				blockState.EXPECT().GetHeader(chain[1].Hash()).Return(nil, database.ErrNotFound)
+				return blockState
+			},
+			errWrapped: database.ErrNotFound,
+			errMessage: "getting block header: pebble: not found",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := &environment{
+				grandpa: &Service{blockState: testCase.blockStateGetter(ctrl)},
+			}
+
+			ancestry, err := env.Ancestry(toH256(testCase.base.Hash()), toH256(testCase.block.Hash()))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.ancestry, ancestry)
+		})
+	}
+}
+
+func Test_environment_BestChainContaining(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 4)
+	fork := &types.Header{
+		ParentHash: chain[1].Hash(),
+		Number:     2,
+		StateRoot:  common.Hash{1},
+		Digest:     types.NewDigest(),
+	}
+
+	testCases := map[string]struct {
+		base               *types.Header
+		blockStateGetter   func(ctrl *gomock.Controller) BlockState
+		grandpaStateGetter func(ctrl *gomock.Controller) GrandpaState
+		output             *finalityHashNumber
+	}{
+		"unknown_base": {
+			base: chain[0],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[0].Hash()).Return(nil, database.ErrNotFound)
+				return blockState
+			},
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				return NewMockGrandpaState(ctrl)
+			},
+		},
+		"longest_descendant_leaf": {
+			base: chain[1],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[1].Hash()).Return(chain[1], nil)
+				blockState.EXPECT().Leaves().Return([]common.Hash{fork.Hash(), chain[3].Hash()})
+				blockState.EXPECT().IsDescendantOf(chain[1].Hash(), fork.Hash()).Return(true, nil)
+				blockState.EXPECT().GetHeader(fork.Hash()).Return(fork, nil)
+				blockState.EXPECT().IsDescendantOf(chain[1].Hash(), chain[3].Hash()).Return(true, nil)
+				blockState.EXPECT().GetHeader(chain[3].Hash()).Return(chain[3], nil)
+				return blockState
+			},
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().NextGrandpaAuthorityChange(chain[3].Hash(), uint(3)).
+					Return(uint(0), state.ErrNoNextAuthorityChange)
+				grandpaState.EXPECT().NextGrandpaPause(chain[3].Hash(), uint(3)).
+					Return(uint(0), state.ErrNoNextPause)
+				return grandpaState
+			},
+			output: &finalityHashNumber{Hash: toH256(chain[3].Hash()), Number: 3},
+		},
+		"limited_by_authority_change": {
+			base: chain[0],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[0].Hash()).Return(chain[0], nil)
+				blockState.EXPECT().Leaves().Return([]common.Hash{chain[3].Hash()})
+				blockState.EXPECT().IsDescendantOf(chain[0].Hash(), chain[3].Hash()).Return(true, nil)
+				blockState.EXPECT().GetHeader(chain[3].Hash()).Return(chain[3], nil)
+				blockState.EXPECT().GetHeader(chain[2].Hash()).Return(chain[2], nil)
+				blockState.EXPECT().GetHeader(chain[1].Hash()).Return(chain[1], nil)
+				return blockState
+			},
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().NextGrandpaAuthorityChange(chain[3].Hash(), uint(3)).
+					Return(uint(1), nil)
+				grandpaState.EXPECT().NextGrandpaPause(chain[3].Hash(), uint(3)).
+					Return(uint(0), state.ErrNoNextPause)
+				return grandpaState
+			},
+			output: &finalityHashNumber{Hash: toH256(chain[1].Hash()), Number: 1},
+		},
+		"limited_by_pause": {
+			base: chain[0],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[0].Hash()).Return(chain[0], nil)
+				blockState.EXPECT().Leaves().Return([]common.Hash{chain[3].Hash()})
+				blockState.EXPECT().IsDescendantOf(chain[0].Hash(), chain[3].Hash()).Return(true, nil)
+				blockState.EXPECT().GetHeader(chain[3].Hash()).Return(chain[3], nil)
+				blockState.EXPECT().GetHeader(chain[2].Hash()).Return(chain[2], nil)
+				return blockState
+			},
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().NextGrandpaAuthorityChange(chain[3].Hash(), uint(3)).
+					Return(uint(3), nil)
+				grandpaState.EXPECT().NextGrandpaPause(chain[3].Hash(), uint(3)).
+					Return(uint(2), nil)
+				return grandpaState
+			},
+			output: &finalityHashNumber{Hash: toH256(chain[2].Hash()), Number: 2},
+		},
+		"no_descendant_leaf": {
+			base: chain[2],
+			blockStateGetter: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHeader(chain[2].Hash()).Return(chain[2], nil)
+				blockState.EXPECT().Leaves().Return([]common.Hash{fork.Hash()})
+				blockState.EXPECT().IsDescendantOf(chain[2].Hash(), fork.Hash()).Return(false, nil)
+				return blockState
+			},
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().NextGrandpaAuthorityChange(chain[2].Hash(), uint(2)).
+					Return(uint(0), state.ErrNoNextAuthorityChange)
+				grandpaState.EXPECT().NextGrandpaPause(chain[2].Hash(), uint(2)).
+					Return(uint(0), state.ErrNoNextPause)
+				return grandpaState
+			},
+			output: &finalityHashNumber{Hash: toH256(chain[2].Hash()), Number: 2},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := &environment{
+				grandpa: &Service{
+					blockState:   testCase.blockStateGetter(ctrl),
+					grandpaState: testCase.grandpaStateGetter(ctrl),
+				},
+			}
+
+			output := <-env.BestChainContaining(toH256(testCase.base.Hash()))
+
+			require.NoError(t, output.Error)
+			assert.Equal(t, testCase.output, output.Value)
+		})
+	}
+}
+
+func Test_environment_FinalizeBlock(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 3)
+	const round, setID = uint64(5), uint64(1)
+
+	precommit := SignedVote{
+		Vote:        *NewVoteFromHeader(chain[2]),
+		Signature:   testSignature,
+		AuthorityID: testAuthorityID,
+	}
+	commit := finalityCommit{
+		TargetHash:   toH256(chain[1].Hash()),
+		TargetNumber: 1,
+		Precommits: []finality_grandpa.SignedPrecommit[hash.H256, uint32, [64]byte, string]{{
+			Precommit: finalityPrecommit{TargetHash: toH256(chain[2].Hash()), TargetNumber: 2},
+			Signature: testSignature,
+			ID:        authorityIDToString(testAuthorityID),
+		}},
+	}
+
+	encodedJustification, err := scale.Marshal(*newJustification(round, chain[1].Hash(), 1,
+		[]SignedVote{precommit}))
+	require.NoError(t, err)
+	encodedAncestries, err := scale.Marshal([]types.Header{*chain[2]})
+	require.NoError(t, err)
+	justification := append(encodedJustification, encodedAncestries...)
+
+	testCases := map[string]struct {
+		serviceGetter func(ctrl *gomock.Controller) *Service
+	}{
+		"already_finalised": {
+			serviceGetter: func(ctrl *gomock.Controller) *Service {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHighestFinalisedHeader().Return(chain[2], nil)
+				return &Service{blockState: blockState}
+			},
+		},
+		"finalise": {
+			serviceGetter: func(ctrl *gomock.Controller) *Service {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHighestFinalisedHeader().Return(chain[0], nil)
+				blockState.EXPECT().GetHeader(chain[2].Hash()).Return(chain[2], nil)
+				blockState.EXPECT().SetJustification(chain[1].Hash(), justification).Return(nil)
+				blockState.EXPECT().SetFinalisedHash(chain[1].Hash(), round, setID).Return(nil)
+
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().SetPrecommits(round, setID, []SignedVote{precommit}).Return(nil)
+
+				telemetryMock := NewMockTelemetry(ctrl)
+				telemetryMock.EXPECT().SendMessage(telemetry.NewAfgFinalizedBlocksUpTo(chain[1].Hash(), "1"))
+
+				return &Service{
+					blockState:   blockState,
+					grandpaState: grandpaState,
+					telemetry:    telemetryMock,
+				}
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := &environment{
+				grandpa: testCase.serviceGetter(ctrl),
+				setID:   setID,
+			}
+
+			err := env.FinalizeBlock(toH256(chain[1].Hash()), 1, round, commit)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_environment_importMessage(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	grandpaState := NewMockGrandpaState(ctrl)
+	grandpaState.EXPECT().SetPrevotes(uint64(2), uint64(0), nil).Return(nil)
+	grandpaState.EXPECT().SetPrecommits(uint64(2), uint64(0), nil).Return(nil)
+
+	service := &Service{
+		grandpaState: grandpaState,
+		state:        NewState(nil, 0, 0),
+	}
+	env := newEnvironment(service, 0, nil)
+
+	message, err := voteToMessage(*testVote, precommit)
+	require.NoError(t, err)
+	signedMessage := finalitySignedMessage{
+		Message:   message,
+		Signature: testSignature,
+		ID:        authorityIDToString(testAuthorityID),
+	}
+
+	// messages are buffered until the round starts
+	env.importMessage(2, signedMessage)
+	roundData := env.RoundData(2, make(finalityOutput))
+	require.Equal(t, finalitySignedMessageError{SignedMessage: signedMessage}, <-roundData.Incoming)
+	require.Equal(t, uint64(2), service.GetRound())
+
+	// messages of concluded rounds are dropped
+	err = env.Concluded(2, finalityRoundState{}, finalityHashNumber{},
+		finality_grandpa.NewHistoricalVotes[hash.H256, uint32, [64]byte, string]())
+	require.NoError(t, err)
+
+	env.importMessage(2, signedMessage)
+	_, ok := <-roundData.Incoming
+	require.False(t, ok)
+	require.Empty(t, env.rounds)
+}
+
+func Test_environment_noteVoted(t *testing.T) {
+	t.Parallel()
+
+	const round, setID = uint64(2), uint64(1)
+	localID := authorityIDToString(testAuthorityID)
+	previousVote := NewVote(common.Hash{0xa}, 1)
+
+	testCases := map[string]struct {
+		localID            *string
+		grandpaStateGetter func(ctrl *gomock.Controller) GrandpaState
+		errWrapped         error
+		errMessage         string
+	}{
+		"not_a_voter": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				return NewMockGrandpaState(ctrl)
+			},
+		},
+		"first_vote": {
+			localID: &localID,
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetHasVoted(round, setID).
+					Return(types.GrandpaHasVoted{}, database.ErrNotFound)
+				grandpaState.EXPECT().SetHasVoted(round, setID, types.GrandpaHasVoted{Prevote: testVote}).
+					Return(nil)
+				return grandpaState
+			},
+		},
+		"already_voted": {
+			localID: &localID,
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetHasVoted(round, setID).
+					Return(types.GrandpaHasVoted{Prevote: previousVote}, nil)
+				return grandpaState
+			},
+		},
+		"get_has_voted_error": {
+			localID: &localID,
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetHasVoted(round, setID).
+					Return(types.GrandpaHasVoted{}, errTestError)
+				return grandpaState
+			},
+			errWrapped: errTestError,
+			errMessage: "getting votes cast in round 2: test dummy error",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			env := &environment{
+				grandpa: &Service{grandpaState: testCase.grandpaStateGetter(ctrl)},
+				setID:   setID,
+				localID: testCase.localID,
+			}
+
+			err := env.Prevoted(round, finalityPrevote{
+				TargetHash:   toH256(testVote.Hash),
+				TargetNumber: testVote.Number,
+			})
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_environment_RoundData_restoresVotes(t *testing.T) {
+	t.Parallel()
+
+	const round, setID = uint64(2), uint64(1)
+	proposeVote := NewVote(common.Hash{0xa}, 1)
+	prevoteVote := NewVote(common.Hash{0xb}, 2)
+
+	keypair, err := ed25519.GenerateKeypair()
+	require.NoError(t, err)
+	localID := authorityIDToString(keypair.Public().(*ed25519.PublicKey).AsBytes())
+
+	testCases := map[string]struct {
+		grandpaStateGetter func(ctrl *gomock.Controller) GrandpaState
+		voterID            *string
+		hasVoted           finalityHasVoted
+		restored           []*Vote
+	}{
+		"not_voted": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetHasVoted(round, setID).
+					Return(types.GrandpaHasVoted{}, database.ErrNotFound)
+				return grandpaState
+			},
+			voterID: &localID,
+		},
+		"voted_before_restart": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetHasVoted(round, setID).
+					Return(types.GrandpaHasVoted{Propose: proposeVote, Prevote: prevoteVote}, nil)
+				return grandpaState
+			},
+			voterID: &localID,
+			hasVoted: finalityHasVoted{
+				Propose: &finalityPrimaryPropose{TargetHash: toH256(proposeVote.Hash), TargetNumber: 1},
+				Prevote: &finalityPrevote{TargetHash: toH256(prevoteVote.Hash), TargetNumber: 2},
+			},
+			restored: []*Vote{proposeVote, prevoteVote},
+		},
+		"get_has_voted_error": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetHasVoted(round, setID).
+					Return(types.GrandpaHasVoted{}, errTestError)
+				return grandpaState
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			network := NewMockNetwork(ctrl)
+			network.EXPECT().GossipMessage(gomock.Any()).Times(len(testCase.restored))
+
+			service := &Service{
+				grandpaState: testCase.grandpaStateGetter(ctrl),
+				network:      network,
+				keypair:      keypair,
+				state:        NewState(nil, 0, 0),
+			}
+			env := newEnvironment(service, setID, nil)
+			env.localID = &localID
+			t.Cleanup(env.stop)
+
+			roundData := env.RoundData(round, make(finalityOutput))
+			assert.Equal(t, testCase.voterID, roundData.VoterID)
+			assert.Equal(t, testCase.hasVoted, roundData.HasVoted)
+
+			// the votes cast before the restart are imported back into the round
+			for _, expected := range testCase.restored {
+				imported := <-roundData.Incoming
+				vote, _, err := messageToVote(imported.SignedMessage.Message)
+				require.NoError(t, err)
+				assert.Equal(t, expected, vote)
+				assert.Equal(t, localID, imported.SignedMessage.ID)
+			}
+		})
+	}
+}
+
+func Test_messageToVote(t *testing.T) {
+	t.Parallel()
+
+	for _, stage := range []Subround{prevote, precommit, primaryProposal} {
+		message, err := voteToMessage(*testVote, stage)
+		require.NoError(t, err)
+
+		vote, gotStage, err := messageToVote(message)
+		require.NoError(t, err)
+		assert.Equal(t, testVote, vote)
+		assert.Equal(t, stage, gotStage)
+	}
+}
@@ -89,4 +89,5 @@
 	errRoundOutOfBounds         = errors.New("round out of bounds")
 	errRoundsMismatch           = errors.New("rounds mismatch")
 	errInvalidEquivocationStage = errors.New("invalid stage for equivocating")
+	errInvalidVoterSet          = errors.New("invalid voter set")
 )
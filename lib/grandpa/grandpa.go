@@ -4,20 +4,18 @@
 package grandpa
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
-	"sync/atomic"
 	"time"
 
-	"github.com/ChainSafe/gossamer/dot/state"
 	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/database"
 	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/internal/primitives/core/hash"
 	"github.com/ChainSafe/gossamer/lib/blocktree"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
@@ -25,7 +23,8 @@
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"golang.org/x/exp/maps"
+
+	finality_grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
 )
 
 const (
@@ -50,31 +49,25 @@ type Service struct {
 	cancel         context.CancelFunc
 	blockState     BlockState
 	grandpaState   GrandpaState
+	storageState   StorageState
 	keypair        *ed25519.Keypair // TODO: change to grandpa keystore (#1870)
-	mapLock        sync.Mutex
 	chanLock       sync.Mutex
 	roundLock      sync.Mutex
-	authority      bool          // run the service as an authority (ie participate in voting)
-	paused         atomic.Value  // the service will be paused if it is waiting for catch up responses
-	resumed        chan struct{} // this channel will be closed when the service resumes
+	authority      bool // run the service as an authority (ie participate in voting)
 	messageHandler *MessageHandler
 	network        Network
 	interval       time.Duration
 
 	// current state information
-	state *State // current state
-	// map[ed25519.PublicKeyBytes]*SignedVote - pre-votes for the current round
-	prevotes *sync.Map
-	// map[ed25519.PublicKeyBytes]*SignedVote - pre-commits for the current round
-	precommits      *sync.Map
-	pvEquivocations map[ed25519.PublicKeyBytes][]*SignedVote // equivocatory votes for current pre-vote stage
-	pcEquivocations map[ed25519.PublicKeyBytes][]*SignedVote // equivocatory votes for current pre-commit stage
-	tracker         *tracker                                 // tracker of vote messages we may need in the future
-	head            *types.Header                            // most recently finalised block
-
-	// historical information
-	preVotedBlock      map[uint64]*Vote // map of round number -> pre-voted block
-	bestFinalCandidate map[uint64]*Vote // map of round number -> best final candidate
+	state   *State   // current state
+	tracker *tracker // tracker of vote messages we may need in the future
+
+	// voter of the current authority set
+	voterWG   sync.WaitGroup // waits for the voter to be stopped
+	voterLock sync.Mutex
+	voter     *finalityVoter
+	env       *environment
+	globalIn  chan finality_grandpa.GlobalInItem
 
 	// channels for communication with other services
 	finalisedCh     chan *types.FinalisationInfo
@@ -90,6 +83,7 @@ type Config struct {
 	LogLvl       log.Level
 	BlockState   BlockState
 	GrandpaState GrandpaState
+	StorageState StorageState
 	Network      Network
 	Voters       []Voter
 	Keypair      *ed25519.Keypair
@@ -111,12 +105,6 @@ func NewService(cfg *Config) (*Service, error) {
 		"creating service with authority=%t, pub=%s and voter set %s",
 		cfg.Authority, pub, Voters(cfg.Voters))
 
-	// get latest finalised header
-	head, err := cfg.BlockState.GetFinalisedHeader(0, 0)
-	if err != nil {
-		return nil, err
-	}
-
 	setID, err := cfg.GrandpaState.GetCurrentSetID()
 	if err != nil {
 		return nil, err
@@ -137,26 +125,19 @@ func NewService(cfg *Config) (*Service, error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	s := &Service{
-		ctx:                ctx,
-		cancel:             cancel,
-		state:              NewState(cfg.Voters, setID, round),
-		blockState:         cfg.BlockState,
-		grandpaState:       cfg.GrandpaState,
-		keypair:            cfg.Keypair,
-		authority:          cfg.Authority,
-		prevotes:           new(sync.Map),
-		precommits:         new(sync.Map),
-		pvEquivocations:    make(map[ed25519.PublicKeyBytes][]*SignedVote),
-		pcEquivocations:    make(map[ed25519.PublicKeyBytes][]*SignedVote),
-		preVotedBlock:      make(map[uint64]*Vote),
-		bestFinalCandidate: make(map[uint64]*Vote),
-		head:               head,
-		resumed:            make(chan struct{}),
-		network:            cfg.Network,
-		finalisedCh:        finalisedCh,
-		interval:           cfg.Interval,
-		telemetry:          cfg.Telemetry,
-		neighborMsgChan:    neighborMsgChan,
+		ctx:             ctx,
+		cancel:          cancel,
+		state:           NewState(cfg.Voters, setID, round),
+		blockState:      cfg.BlockState,
+		grandpaState:    cfg.GrandpaState,
+		storageState:    cfg.StorageState,
+		keypair:         cfg.Keypair,
+		authority:       cfg.Authority,
+		network:         cfg.Network,
+		finalisedCh:     finalisedCh,
+		interval:        cfg.Interval,
+		telemetry:       cfg.Telemetry,
+		neighborMsgChan: neighborMsgChan,
 	}
 
 	s.neighborTracker = newNeighborTracker(s, neighborMsgChan)
@@ -167,27 +148,20 @@ func NewService(cfg *Config) (*Service, error) {
 
 	s.messageHandler = NewMessageHandler(s, s.blockState, cfg.Telemetry)
 	s.tracker = newTracker(s.blockState, s.messageHandler)
-	s.paused.Store(false)
 	return s, nil
 }
 
 // Start begins the GRANDPA finality service
 func (s *Service) Start() error {
 	s.neighborTracker.Start()
-
-	// if we're not an authority, we don't need to worry about the voting process.
-	// the grandpa service is only used to verify incoming block justifications
-	if !s.authority {
-		return nil
-	}
-
 	s.tracker.start()
 
+	// if we're not an authority, the voter only observes the rounds
+	// and finalises blocks from the commit messages received.
+	s.voterWG.Add(1)
 	go func() {
-		err := s.initiate()
-		if err != nil {
-			panic(fmt.Sprintf("running grandpa service: %s", err))
-		}
+		defer s.voterWG.Done()
+		s.initiate()
 	}()
 
 	return nil
@@ -199,15 +173,12 @@ func (s *Service) Stop() error {
 	defer s.chanLock.Unlock()
 
 	s.cancel()
+	s.voterWG.Wait()
 	s.blockState.FreeFinalisedNotifierChannel(s.finalisedCh)
 
 	s.neighborTracker.Stop()
 	close(s.neighborTracker.neighborMsgChan)
 
-	if !s.authority {
-		return nil
-	}
-
 	s.tracker.stop()
 	return nil
 }
@@ -235,6 +206,9 @@ func (s *Service) updateAuthorities() error {
 		return fmt.Errorf("cannot get current set id: %w", err)
 	}
 
+	s.roundLock.Lock()
+	defer s.roundLock.Unlock()
+
 	// set ID hasn't changed, do nothing
 	if currSetID == s.state.setID {
 		return nil
@@ -284,793 +258,250 @@ func (s *Service) sendTelemetryAuthoritySet() {
 	)
 }
 
-func (s *Service) initiateRound() error {
-	// if there is an authority change, execute it
-	err := s.updateAuthorities()
-	if err != nil {
-		return fmt.Errorf("cannot update authorities while initiating the round: %w", err)
-	}
-
-	round, setID, err := s.blockState.GetHighestRoundAndSetID()
-	if err != nil {
-		return fmt.Errorf("cannot get highest round and set id: %w", err)
-	}
-
-	if round > s.state.round && setID == s.state.setID {
-		logger.Debugf(
-			"found block finalised in higher round, updating our round to be %d...",
-			round)
-		s.state.round = round
-		roundGauge.Set(float64(s.state.round))
-		err = s.grandpaState.SetLatestRound(round)
+// initiate runs the grandpa voter of the current authority set until the service is stopped,
+// restarting it with the new authority set on a set ID change or after a voter error.
+func (s *Service) initiate() {
+	for {
+		err := s.runVoter()
 		if err != nil {
-			return err
+			logger.Errorf("running voter: %s", err)
 		}
-	}
 
-	if setID > s.state.setID {
-		logger.Debugf("found block finalised in higher setID, updating our setID to be %d...", setID)
-		s.state.setID = setID
-		s.state.round = round
-	}
-
-	s.head, err = s.blockState.GetFinalisedHeader(round, setID)
-	if err != nil {
-		logger.Criticalf("failed to get finalised header for round %d: %s", round, err)
-		return err
-	}
-
-	// there was a setID change, or the node was started from genesis
-	if s.state.round == 0 {
-		s.chanLock.Lock()
-		s.mapLock.Lock()
-		s.preVotedBlock[0] = NewVoteFromHeader(s.head)
-		s.bestFinalCandidate[0] = NewVoteFromHeader(s.head)
-		s.mapLock.Unlock()
-		s.chanLock.Unlock()
-	}
-
-	// make sure no votes can be validated while we are incrementing rounds
-	s.roundLock.Lock()
-	defer s.roundLock.Unlock()
-
-	s.state.round++
-	logger.Debugf("incrementing grandpa round, next round will be %d", s.state.round)
-	s.prevotes = new(sync.Map)
-	s.precommits = new(sync.Map)
-	s.pvEquivocations = make(map[ed25519.PublicKeyBytes][]*SignedVote)
-	s.pcEquivocations = make(map[ed25519.PublicKeyBytes][]*SignedVote)
-
-	return nil
-}
-
-// initiate initates the grandpa service to begin voting in sequential rounds
-func (s *Service) initiate() error {
-	finalisationHandler := newFinalisationHandler(s)
-	errorCh, err := finalisationHandler.Start()
-	if err != nil {
-		return fmt.Errorf("starting finalisation handler: %w", err)
-	}
-
-	for {
 		select {
 		case <-s.ctx.Done():
-			return finalisationHandler.Stop()
-		case err := <-errorCh:
-			return err
+			return
+		case <-time.After(s.interval):
 		}
 	}
 }
 
-func (s *Service) handleIsPrimary() (bool, error) {
-	// derive primary
-	primary := s.derivePrimary()
-
-	// if primary, broadcast the best final candidate from the previous round
-	// otherwise, do nothing
-	if !bytes.Equal(primary.Key.Encode(), s.keypair.Public().Encode()) {
-		return false, nil
-	}
-
-	if s.head.Number > 0 {
-		s.primaryBroadcastCommitMessage()
-	}
-
-	best, err := s.blockState.BestBlockHeader()
-	if err != nil {
-		return false, err
-	}
-
-	pv := &Vote{
-		Hash:   best.Hash(),
-		Number: uint32(best.Number),
-	}
-
-	// send primary prevote message to network
-	spv, primProposal, err := s.createSignedVoteAndVoteMessage(pv, primaryProposal)
-	if err != nil {
-		return false, fmt.Errorf("failed to create primary proposal message: %w", err)
-	}
-
-	s.prevotes.Store(s.publicKeyBytes(), spv)
-
-	msg, err := primProposal.ToConsensusMessage()
+// runVoter runs the grandpa voter of the current authority set until the
+// service is stopped or the authority set changes.
+func (s *Service) runVoter() error {
+	paused, err := s.isPaused()
 	if err != nil {
-		return false, fmt.Errorf("failed to encode finalisation message: %w", err)
+		return fmt.Errorf("checking if grandpa is paused: %w", err)
 	}
 
-	s.network.GossipMessage(msg)
-	return true, nil
-}
-
-// broadcast commit message from the previous round to the network
-// ignore errors, since it's not critical to broadcast
-func (s *Service) primaryBroadcastCommitMessage() {
-	cm, err := s.newCommitMessage(s.head, s.state.round-1, s.state.setID)
-	if err != nil {
-		return
+	if paused {
+		logger.Trace("grandpa is paused, waiting for it to be resumed")
+		return nil
 	}
 
-	// send finalised block from previous round to network
-	msg, err := cm.ToConsensusMessage()
+	err = s.updateAuthorities()
 	if err != nil {
-		logger.Warnf("failed to encode finalisation message: %s", err)
+		return fmt.Errorf("updating authorities: %w", err)
 	}
 
-	s.network.GossipMessage(msg)
-}
-
-func (s *Service) checkRoundCompletable() (bool, error) {
-	// check if the current round contains a finalized block
-	has, err := s.blockState.HasFinalisedBlock(s.state.round, s.state.setID)
+	setID := s.GetSetID()
+	env := newEnvironment(s, setID, s.GetVoters())
+	voterSet, err := env.voterSet()
 	if err != nil {
-		return false, fmt.Errorf("checking for finalised block in block state: %w", err)
-	}
-
-	if has {
-		logger.Debugf("block was finalised for round %d", s.state.round)
-		return true, nil
+		return fmt.Errorf("creating voter set: %w", err)
 	}
 
-	// a block was finalised, seems like we missed some messages
+	// resume from the round of the highest finalised block if it was
+	// finalised by the current set, otherwise start the set from round 1.
 	highestRound, highestSetID, err := s.blockState.GetHighestRoundAndSetID()
 	if err != nil {
-		return false, fmt.Errorf("getting highest round and set id: %w", err)
+		return fmt.Errorf("getting highest round and set id: %w", err)
 	}
 
-	if highestRound > s.state.round {
-		logger.Debugf("block was finalised for round %d and set id %d",
-			highestRound, highestSetID)
-		return true, nil
-	}
-
-	// a block was finalised, seems like we missed some messages
-	if highestSetID > s.state.setID {
-		logger.Debugf("block was finalised for round %d and set id %d",
-			highestRound, highestSetID)
-		return true, nil
+	var lastRound uint64
+	if highestSetID == setID {
+		lastRound = highestRound
 	}
 
-	return false, nil
-}
-
-func (s *Service) retrieveBestFinalCandidate() (bestFinalCandidate *types.GrandpaVote,
-	precommitCount uint64, err error) {
-
-	bestFinalCandidate, err = s.getBestFinalCandidate()
+	highestFinalised, err := s.blockState.GetHighestFinalisedHeader()
 	if err != nil {
-		return nil, 0, fmt.Errorf("getting best final candidate: %w", err)
-	}
-
-	if bestFinalCandidate.Number < uint32(s.head.Number) {
-		return nil, 0, fmt.Errorf("%w: candidate number %d, latest finalized block number %d",
-			errBeforeFinalizedBlock, bestFinalCandidate.Number, s.head.Number)
-	}
-
-	precommitCount, err = s.getTotalVotesForBlock(bestFinalCandidate.Hash, precommit)
-	if err != nil {
-		return nil, 0, fmt.Errorf("getting total votes for block %s: %w",
-			bestFinalCandidate.Hash.Short(), err)
-	}
-
-	return bestFinalCandidate, precommitCount, nil
-}
-
-// attemptToFinalize check if we should finalize the current round
-func (s *Service) attemptToFinalize() (isFinalizable bool, err error) {
-	bestFinalCandidate, precommitCount, err := s.retrieveBestFinalCandidate()
-	if err != nil {
-		return false, fmt.Errorf("getting best final candidate: %w", err)
-	}
-
-	// once we reach the threshold we should stop sending precommit messages to other peers
-	if bestFinalCandidate.Number < uint32(s.head.Number) || precommitCount <= s.state.threshold() {
-		return false, nil
-	}
-
-	err = s.finalise()
-	if err != nil {
-		return false, fmt.Errorf("finalising: %w", err)
-	}
-
-	// if we haven't received a finalisation message for this block yet, broadcast a finalisation message
-	votes := s.getDirectVotes(precommit)
-	logger.Debugf("block was finalised for round %d and set id %d. "+
-		"Head hash is %s, %d direct votes for bfc and %d total votes for bfc",
-		s.state.round, s.state.setID, s.head.Hash(), votes[*bestFinalCandidate], precommitCount)
-
-	return true, nil
-}
-
-func (s *Service) sendPrecommitMessage(voteMessage *VoteMessage) (err error) {
-	logger.Debugf("sending pre-commit message %s...", voteMessage.Message)
-
-	consensusMessage, err := voteMessage.ToConsensusMessage()
-	if err != nil {
-		return fmt.Errorf("transforming pre-commit into consensus message: %w", err)
-	}
-
-	s.network.GossipMessage(consensusMessage)
-	logger.Tracef("sent pre-commit message: %v", consensusMessage)
-	return nil
-}
-
-func (s *Service) sendPrevoteMessage(vm *VoteMessage) (err error) {
-	logger.Debugf("sending pre-vote message %s...", vm)
-
-	consensusMessage, err := vm.ToConsensusMessage()
-	if err != nil {
-		return fmt.Errorf("transforming pre-vote into consensus message: %w", err)
-	}
-
-	s.network.GossipMessage(consensusMessage)
-	logger.Tracef("sent pre-vote message: %v", consensusMessage)
-	return nil
-}
-
-func (s *Service) loadVote(key ed25519.PublicKeyBytes, stage Subround) (*SignedVote, bool) {
-	var (
-		v   interface{}
-		has bool
-	)
-
-	switch stage {
-	case prevote, primaryProposal:
-		v, has = s.prevotes.Load(key)
-	case precommit:
-		v, has = s.precommits.Load(key)
-	}
-
-	if !has {
-		return nil, false
-	}
-
-	return v.(*SignedVote), true
-}
-
-func (s *Service) deleteVote(key ed25519.PublicKeyBytes, stage Subround) {
-	switch stage {
-	case prevote, primaryProposal:
-		s.prevotes.Delete(key)
-	case precommit:
-		s.precommits.Delete(key)
-	}
-}
-
-// determinePreVote determines what block is our pre-voted block for the current round
-func (s *Service) determinePreVote() (*Vote, error) {
-	var vote *Vote
-
-	bestBlockHeader, err := s.blockState.BestBlockHeader()
-	if err != nil {
-		return nil, fmt.Errorf("cannot get best block header: %w", err)
-	}
-
-	// if we receive a vote message from the primary with a
-	// block that's greater than or equal to the current pre-voted block
-	// and greater than the best final candidate from the last round, we choose that.
-	// otherwise, we simply choose the head of our chain.
-	primary := s.derivePrimary()
-	prm, has := s.loadVote(primary.PublicKeyBytes(), prevote)
-	if has && prm.Vote.Number >= uint32(s.head.Number) {
-		vote = &prm.Vote
-	} else {
-		vote = NewVoteFromHeader(bestBlockHeader)
-	}
-
-	nextChange, err := s.grandpaState.NextGrandpaAuthorityChange(bestBlockHeader.Hash(), bestBlockHeader.Number)
-	if errors.Is(err, state.ErrNoNextAuthorityChange) {
-		return vote, nil
-	} else if err != nil {
-		return nil, fmt.Errorf("cannot get next grandpa authority change: %w", err)
-	}
-
-	if uint(vote.Number) > nextChange {
-		header, err := s.blockState.GetHeaderByNumber(nextChange)
-		if err != nil {
-			return nil, err
-		}
-		vote = NewVoteFromHeader(header)
-	}
-
-	return vote, nil
-}
-
-// determinePreCommit determines what block is our pre-committed block for the current round
-func (s *Service) determinePreCommit() (*Vote, error) {
-	// the pre-committed block is simply the pre-voted block (GRANDPA-GHOST)
-	pvb, err := s.getPreVotedBlock()
-	if err != nil {
-		return nil, err
-	}
-
-	s.mapLock.Lock()
-	s.preVotedBlock[s.state.round] = &pvb
-	s.mapLock.Unlock()
-
-	nextChange, err := s.grandpaState.NextGrandpaAuthorityChange(pvb.Hash, uint(pvb.Number))
-	if errors.Is(err, state.ErrNoNextAuthorityChange) {
-		return &pvb, nil
-	} else if err != nil {
-		return nil, fmt.Errorf("cannot get next grandpa authority change: %w", err)
-	}
-
-	if uint(pvb.Number) > nextChange {
-		header, err := s.blockState.GetHeaderByNumber(nextChange)
-		if err != nil {
-			return nil, err
-		}
-
-		pvb = *NewVoteFromHeader(header)
-	}
-	return &pvb, nil
-}
-
-// finalise finalises the round by setting the best final candidate for this round
-func (s *Service) finalise() error {
-	// get best final candidate
-	bfc, err := s.getBestFinalCandidate()
-	if err != nil {
-		return err
-	}
-
-	pv, err := s.getPreVotedBlock()
-	if err != nil {
-		return err
-	}
-
-	s.mapLock.Lock()
-	defer s.mapLock.Unlock()
-
-	s.preVotedBlock[s.state.round] = &pv
-
-	// set best final candidate
-	s.bestFinalCandidate[s.state.round] = bfc
-
-	// create prevote justification ie. list of all signed prevotes for the bfc
-	pvs, err := s.createJustification(bfc.Hash, prevote)
-	if err != nil {
-		return err
-	}
-
-	// create precommit justification ie. list of all signed precommits for the bfc
-	pcs, err := s.createJustification(bfc.Hash, precommit)
-	if err != nil {
-		return err
-	}
-
-	pcj, err := scale.Marshal(*newJustification(s.state.round, bfc.Hash, bfc.Number, pcs))
-	if err != nil {
-		return err
-	}
-
-	if err = s.blockState.SetJustification(bfc.Hash, pcj); err != nil {
-		return err
-	}
-
-	if err = s.grandpaState.SetPrevotes(s.state.round, s.state.setID, pvs); err != nil {
-		return err
-	}
-
-	if err = s.grandpaState.SetPrecommits(s.state.round, s.state.setID, pcs); err != nil {
-		return err
-	}
-
-	s.head, err = s.blockState.GetHeader(bfc.Hash)
-	if err != nil {
-		return err
+		return fmt.Errorf("getting highest finalised header: %w", err)
 	}
 
-	// set finalised head for round in db
-	if err = s.blockState.SetFinalisedHash(bfc.Hash, s.state.round, s.state.setID); err != nil {
-		return err
+	base := finalityHashNumber{
+		Hash:   hash.H256(highestFinalised.Hash().ToBytes()),
+		Number: uint32(highestFinalised.Number),
 	}
 
-	return s.grandpaState.SetLatestRound(s.state.round)
-}
+	logger.Debugf("starting voter for set id %d from round %d and base %s (#%d)",
+		setID, lastRound, highestFinalised.Hash(), highestFinalised.Number)
 
-// createJustification collects the signed precommits received for this round and turns them into
-// a justification by adding all signed precommits that are for the best finalised candidate or
-// a descendent of the bfc
-func (s *Service) createJustification(bfc common.Hash, stage Subround) ([]SignedVote, error) {
-	var (
-		spc  *sync.Map
-		err  error
-		just []SignedVote
-		eqv  map[ed25519.PublicKeyBytes][]*SignedVote
-	)
+	globalIn := make(chan finality_grandpa.GlobalInItem)
+	voter, commits := finality_grandpa.NewVoter[hash.H256, uint32, [64]byte, string](
+		env, *voterSet, globalIn, lastRound, nil, base, base)
 
-	switch stage {
-	case prevote:
-		spc = s.prevotes
-		eqv = s.pvEquivocations
-	case precommit:
-		spc = s.precommits
-		eqv = s.pcEquivocations
-	default:
-		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSubround, stage)
-	}
+	s.voterLock.Lock()
+	s.voter = voter
+	s.env = env
+	s.globalIn = globalIn
+	s.voterLock.Unlock()
 
-	spc.Range(func(_, value interface{}) bool {
-		pc := value.(*SignedVote)
-		var isDescendant bool
-
-		isDescendant, err = s.blockState.IsDescendantOf(bfc, pc.Vote.Hash)
-		if err != nil {
-			return false
-		}
-
-		if !isDescendant {
-			return true
-		}
-
-		just = append(just, *pc)
-		return true
-	})
+	voterErr := make(chan error, 1)
+	go func() {
+		voterErr <- voter.Start()
+	}()
+	go s.sendCommits(env, commits)
 
-	if err != nil {
-		return nil, err
-	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
 
-	for _, votes := range eqv {
-		for _, vote := range votes {
-			just = append(just, *vote)
+	for {
+		select {
+		case <-s.ctx.Done():
+			return s.stopVoter(voter, env)
+		case err := <-voterErr:
+			stopErr := s.stopVoter(voter, env)
+			if stopErr != nil {
+				logger.Warnf("stopping voter: %s", stopErr)
+			}
+			return err
+		case <-s.finalisedCh:
+		case <-ticker.C:
 		}
-	}
-
-	return just, nil
-}
 
-// derivePrimary returns the primary for the current round
-func (s *Service) derivePrimary() Voter {
-	return s.state.voters[s.state.round%uint64(len(s.state.voters))]
-}
-
-// getBestFinalCandidate calculates the set of blocks that are less than or equal to the pre-voted block in height,
-// with >2/3 pre-commit votes, then returns the block with the highest number from this set.
-func (s *Service) getBestFinalCandidate() (*Vote, error) {
-	prevoted, err := s.getPreVotedBlock()
-	if err != nil {
-		return nil, err
-	}
-
-	// get all blocks with >2/3 pre-commits
-	blocks, err := s.getPossibleSelectedBlocks(precommit, s.state.threshold())
-	if err != nil {
-		return nil, err
-	}
-
-	// if there are no blocks with >2/3 pre-commits, just return the pre-voted block
-	// TODO: is this correct? the spec implies that it should return nil, but discussions have suggested
-	// that we return the prevoted block. (#1815)
-	if len(blocks) == 0 {
-		return &prevoted, nil
-	}
-
-	// if there are multiple blocks, get the one with the highest number
-	// that is also an ancestor of the prevoted block (or is the prevoted block)
-	bfc := &Vote{
-		Hash:   s.blockState.GenesisHash(),
-		Number: 0,
-	}
-
-	for h, n := range blocks {
-		// check if the current block is an ancestor of prevoted block
-		isDescendant, err := s.blockState.IsDescendantOf(h, prevoted.Hash)
+		outdated, err := s.voterOutdated(setID)
 		if err != nil {
-			return nil, err
-		}
-
-		if !isDescendant {
-			// find common ancestor, implicitly has >2/3 votes
-			pred, err := s.blockState.LowestCommonAncestor(h, prevoted.Hash)
-			if err != nil {
-				return nil, err
+			stopErr := s.stopVoter(voter, env)
+			if stopErr != nil {
+				logger.Warnf("stopping voter: %s", stopErr)
 			}
-
-			v, err := NewVoteFromHash(pred, s.blockState)
-			if err != nil {
-				return nil, err
-			}
-
-			n = v.Number
-			h = pred
+			return err
 		}
 
-		// choose block with highest number
-		if n > bfc.Number {
-			bfc = &Vote{
-				Hash:   h,
-				Number: n,
-			}
+		if outdated {
+			return s.stopVoter(voter, env)
 		}
 	}
-
-	return bfc, nil
 }
 
-// getPreVotedBlock returns the current pre-voted block B. also known as GRANDPA-GHOST.
-// the pre-voted block is the block with the highest block number in the set of all the blocks with
-// total votes >2/3 the total number of voters, where the total votes is determined by getTotalVotesForBlock.
-func (s *Service) getPreVotedBlock() (Vote, error) {
-	blocks, err := s.getPossibleSelectedBlocks(prevote, s.state.threshold())
+// voterOutdated returns true if the voter of the given set must be stopped, which
+// happens once the block enacting an authority set change or a pause is finalised.
+func (s *Service) voterOutdated(setID uint64) (bool, error) {
+	currentSetID, err := s.grandpaState.GetCurrentSetID()
 	if err != nil {
-		return Vote{}, err
-	}
-
-	// if there are no blocks with >=2/3 voters, then just pick the highest voted block
-	if len(blocks) == 0 {
-		return s.getGrandpaGHOST()
+		return false, fmt.Errorf("getting current set id: %w", err)
 	}
 
-	// if there is one block, return it
-	if len(blocks) == 1 {
-		for h, n := range blocks {
-			return Vote{
-				Hash:   h,
-				Number: n,
-			}, nil
-		}
+	if currentSetID != setID {
+		logger.Debugf("authority set changed from set id %d to %d, restarting voter", setID, currentSetID)
+		return true, nil
 	}
 
-	// if there are multiple, find the one with the highest number and return it
-	highest := Vote{
-		Hash:   s.head.Hash(),
-		Number: uint32(s.head.Number),
+	paused, err := s.isPaused()
+	if err != nil {
+		return false, fmt.Errorf("checking if grandpa is paused: %w", err)
 	}
 
-	for h, n := range blocks {
-		if n > highest.Number {
-			highest = Vote{
-				Hash:   h,
-				Number: n,
-			}
-		}
+	if paused {
+		logger.Infof("grandpa paused, stopping voter of set id %d", setID)
+		return true, nil
 	}
 
-	return highest, nil
+	return false, nil
 }
 
-// getGrandpaGHOST returns the block with the most votes. if there are multiple blocks with the same number
-// of votes, it picks the one with the highest number.
-func (s *Service) getGrandpaGHOST() (Vote, error) {
-	threshold := s.state.threshold()
-
-	var (
-		blocks map[common.Hash]uint32
-		err    error
-	)
-
-	for {
-		blocks, err = s.getPossibleSelectedBlocks(prevote, threshold)
-		if err != nil {
-			return Vote{}, err
-		}
-
-		if len(blocks) > 0 || threshold == 0 {
-			break
-		}
-
-		threshold--
-	}
-
-	if len(blocks) == 0 {
-		return Vote{}, ErrNoGHOST
-	}
-
-	// if there are multiple, find the one with the highest number and return it
-	highest := Vote{
-		Hash:   s.head.Hash(),
-		Number: uint32(s.head.Number),
+// isPaused returns true if the last pause enacted on the finalised chain was not
+// followed by a resume, in which case the authorities must not vote.
+func (s *Service) isPaused() (bool, error) {
+	pause, err := s.grandpaState.GetNextPause()
+	if errors.Is(err, database.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("getting next pause: %w", err)
 	}
 
-	for h, n := range blocks {
-		if n > highest.Number {
-			highest = Vote{
-				Hash:   h,
-				Number: n,
-			}
-		}
+	resume, err := s.grandpaState.GetNextResume()
+	if errors.Is(err, database.ErrNotFound) {
+		return true, nil
+	} else if err != nil {
+		return false, fmt.Errorf("getting next resume: %w", err)
 	}
 
-	return highest, nil
+	return resume <= pause, nil
 }
 
-// getPossibleSelectedBlocks returns blocks with total votes >threshold in a map of block hash -> block number.
-// if there are no blocks that have >threshold direct votes,
-// this function will find ancestors of those blocks that do have >threshold votes.
-// note that by voting for a block, all of its ancestor blocks
-// are automatically voted for.
-// thus, if there are no blocks with >threshold total votes,
-// but the sum of votes for blocks A and B is >threshold, then this function returns
-// the first common ancestor of A and B.
-// in general, this function will return the highest block on each chain with >threshold votes.
-func (s *Service) getPossibleSelectedBlocks(stage Subround, threshold uint64) (map[common.Hash]uint32, error) {
-	// get blocks that were directly voted for
-	votes := s.getDirectVotes(stage)
-	blocks := make(map[common.Hash]uint32)
-
-	// check if any of them have >threshold votes
-	for v := range votes {
-		total, err := s.getTotalVotesForBlock(v.Hash, stage)
-		if err != nil {
-			return nil, err
-		}
+// stopVoter stops the voter and its environment.
+func (s *Service) stopVoter(voter *finalityVoter, env *environment) error {
+	s.voterLock.Lock()
+	s.voter = nil
+	s.env = nil
+	s.globalIn = nil
+	s.voterLock.Unlock()
 
-		if total > threshold {
-			blocks[v.Hash] = v.Number
-		}
-	}
-
-	// since we want to select the block with the highest number that has >threshold votes,
-	// we can return here since their ancestors won't have a higher number.
-	if len(blocks) != 0 {
-		return blocks, nil
-	}
-
-	// no block has >threshold direct votes, check for votes for ancestors recursively
-	var err error
-	va := s.getVotes(stage)
-
-	for v := range votes {
-		blocks, err = s.getPossibleSelectedAncestors(va, v.Hash, blocks, stage, threshold)
-		if err != nil {
-			return nil, err
-		}
+	err := voter.Stop()
+	env.stop()
+	if err != nil {
+		return fmt.Errorf("stopping voter: %w", err)
 	}
-
-	return blocks, nil
+	return nil
 }
 
-// getPossibleSelectedAncestors recursively searches for ancestors with >2/3 votes
-// it returns a map of block hash -> number, such that the blocks in the map have >2/3 votes
-func (s *Service) getPossibleSelectedAncestors(votes []Vote, curr common.Hash,
-	selected map[common.Hash]uint32, stage Subround,
-	threshold uint64) (map[common.Hash]uint32, error) {
-	for _, v := range votes {
-		if v.Hash == curr {
+// sendCommits gossips the commits produced by the voter, if we are a voter of the set.
+func (s *Service) sendCommits(env *environment, commits <-chan finality_grandpa.CommunicationOut) {
+	for communicationOut := range commits {
+		commit, ok := communicationOut.Value().(finality_grandpa.CommunicationOutCommit[
+			hash.H256, uint32, [64]byte, string])
+		if !ok {
+			logger.Warnf("unexpected voter output %T", communicationOut.Value())
 			continue
 		}
 
-		// find common ancestor, check if votes for it is >threshold or not
-		pred, err := s.blockState.LowestCommonAncestor(v.Hash, curr)
-		if errors.Is(err, blocktree.ErrNodeNotFound) {
+		if env.localID == nil {
 			continue
-		} else if err != nil {
-			return nil, err
-		}
-
-		if pred == curr {
-			return selected, nil
-		}
-
-		total, err := s.getTotalVotesForBlock(pred, stage)
-		if err != nil {
-			return nil, err
 		}
 
-		if total > threshold {
-			var h *types.Header
-			h, err = s.blockState.GetHeader(pred)
-			if err != nil {
-				return nil, err
-			}
-
-			selected[pred] = uint32(h.Number)
-		} else {
-			selected, err = s.getPossibleSelectedAncestors(votes, pred, selected, stage, threshold)
-			if err != nil {
-				return nil, err
-			}
+		compactCommit := commit.Commit.CompactCommit()
+		commitMessage := &CommitMessage{
+			Round: commit.Number,
+			SetID: env.setID,
+			Vote:  *NewVote(common.NewHash(compactCommit.TargetHash.Bytes()), compactCommit.TargetNumber),
 		}
-	}
-
-	return selected, nil
-}
-
-// getTotalVotesForBlock returns the total number of observed votes for a block B in a subround, which is equal
-// to the direct votes for B and B's descendants plus the total number of equivocating voters
-func (s *Service) getTotalVotesForBlock(hash common.Hash, stage Subround) (uint64, error) {
-	// observed votes for block
-	dv, err := s.getVotesForBlock(hash, stage)
-	if err != nil {
-		return 0, err
-	}
-
-	// equivocatory votes
-	var ev int
-	if stage == prevote {
-		ev = len(s.pvEquivocations)
-	} else {
-		ev = len(s.pcEquivocations)
-	}
-
-	return dv + uint64(ev), nil
-}
-
-// getVotesForBlock returns the number of observed votes for a block B.
-// The set of all observed votes by v in the sub-round stage of round r for block B is
-// equal to all of the observed direct votes cast for block B and all of the B's descendants
-func (s *Service) getVotesForBlock(hash common.Hash, stage Subround) (uint64, error) {
-	votes := s.getDirectVotes(stage)
-
-	// B will be counted as in it's own subchain, so don't need to start with B's vote count
-	votesForBlock := uint64(0)
-
-	for v, c := range votes {
-		// check if the current block is a descendant of B
-		isDescendant, err := s.blockState.IsDescendantOf(hash, v.Hash)
-		if errors.Is(err, blocktree.ErrStartNodeNotFound) || errors.Is(err, blocktree.ErrEndNodeNotFound) {
-			continue
-		} else if err != nil {
-			return 0, err
+		for i, precommit := range compactCommit.Precommits {
+			commitMessage.Precommits = append(commitMessage.Precommits,
+				*NewVote(common.NewHash(precommit.TargetHash.Bytes()), precommit.TargetNumber))
+			commitMessage.AuthData = append(commitMessage.AuthData, AuthData{
+				Signature:   compactCommit.AuthData[i].Signature,
+				AuthorityID: authorityIDFromString(compactCommit.AuthData[i].ID),
+			})
 		}
 
-		if !isDescendant {
+		consensusMessage, err := commitMessage.ToConsensusMessage()
+		if err != nil {
+			logger.Errorf("transforming commit into consensus message: %s", err)
 			continue
 		}
 
-		votesForBlock += c
+		logger.Debugf("sending commit message: %s", commitMessage)
+		s.network.GossipMessage(consensusMessage)
 	}
+}
 
-	return votesForBlock, nil
+// currentVoter returns the running voter and its environment, nil if no voter is running.
+func (s *Service) currentVoter() (*finalityVoter, *environment, chan finality_grandpa.GlobalInItem) {
+	s.voterLock.Lock()
+	defer s.voterLock.Unlock()
+	return s.voter, s.env, s.globalIn
 }
 
-// getDirectVotes returns a map of Votes to direct vote counts
-func (s *Service) getDirectVotes(stage Subround) map[Vote]uint64 {
-	votes := make(map[Vote]uint64)
+// setRound updates the current round number if the given round is higher.
+func (s *Service) setRound(round uint64) {
+	s.roundLock.Lock()
+	defer s.roundLock.Unlock()
 
-	var src *sync.Map
-	if stage == prevote {
-		src = s.prevotes
-	} else {
-		src = s.precommits
+	if round > s.state.round {
+		s.state.round = round
+		roundGauge.Set(float64(round))
 	}
-
-	src.Range(func(_, value interface{}) bool {
-		sv := value.(*SignedVote)
-		votes[sv.Vote]++
-		return true
-	})
-
-	return votes
-}
-
-// getVotes returns all the current votes as an array
-func (s *Service) getVotes(stage Subround) []Vote {
-	votes := s.getDirectVotes(stage)
-	return maps.Keys(votes)
 }
 
 // GetSetID returns the current setID
 func (s *Service) GetSetID() uint64 {
+	s.roundLock.Lock()
+	defer s.roundLock.Unlock()
 	return s.state.setID
 }
 
 // GetRound return the current round number
 func (s *Service) GetRound() uint64 {
-	// Tim: I don't think we need to lock in this case.  Reading an int will
-	// not produce a concurrent read/write panic
 	s.roundLock.Lock()
 	defer s.roundLock.Unlock()
 	return s.state.round
@@ -1078,60 +509,37 @@ func (s *Service) GetRound() uint64 {
 
 // GetVoters returns the list of current grandpa.Voters
 func (s *Service) GetVoters() Voters {
+	s.roundLock.Lock()
+	defer s.roundLock.Unlock()
 	return s.state.voters
 }
 
 // PreVotes returns the current prevotes to the current round
 func (s *Service) PreVotes() []ed25519.PublicKeyBytes {
-	s.mapLock.Lock()
-	defer s.mapLock.Unlock()
-
-	votes := make([]ed25519.PublicKeyBytes, 0, s.lenVotes(prevote)+len(s.pvEquivocations))
-
-	s.prevotes.Range(func(k interface{}, _ interface{}) bool {
-		b := k.(ed25519.PublicKeyBytes)
-		votes = append(votes, b)
-		return true
-	})
+	voter, _, _ := s.currentVoter()
+	if voter == nil {
+		return nil
+	}
 
-	votes = append(votes, maps.Keys(s.pvEquivocations)...)
-	return votes
+	return toPublicKeys(voter.VoterState().Get().BestRound.RoundState.PrevoteIDs)
 }
 
 // PreCommits returns the current precommits to the current round
 func (s *Service) PreCommits() []ed25519.PublicKeyBytes {
-	s.mapLock.Lock()
-	defer s.mapLock.Unlock()
-
-	votes := make([]ed25519.PublicKeyBytes, 0, s.lenVotes(precommit)+len(s.pcEquivocations))
-
-	s.precommits.Range(func(k interface{}, _ interface{}) bool {
-		b := k.(ed25519.PublicKeyBytes)
-		votes = append(votes, b)
-		return true
-	})
+	voter, _, _ := s.currentVoter()
+	if voter == nil {
+		return nil
+	}
 
-	votes = append(votes, maps.Keys(s.pvEquivocations)...)
-	return votes
+	return toPublicKeys(voter.VoterState().Get().BestRound.RoundState.PrecommitIDs)
 }
 
-func (s *Service) lenVotes(stage Subround) int {
-	var count int
-
-	switch stage {
-	case prevote, primaryProposal:
-		s.prevotes.Range(func(_, _ interface{}) bool {
-			count++
-			return true
-		})
-	case precommit:
-		s.precommits.Range(func(_, _ interface{}) bool {
-			count++
-			return true
-		})
+func toPublicKeys(ids []string) []ed25519.PublicKeyBytes {
+	publicKeys := make([]ed25519.PublicKeyBytes, len(ids))
+	for i, id := range ids {
+		publicKeys[i] = authorityIDFromString(id)
 	}
-
-	return count
+	return publicKeys
 }
 
 func (s *Service) handleVoteMessage(from peer.ID, vote *VoteMessage) (err error) {
@@ -1143,12 +551,24 @@ func (s *Service) handleVoteMessage(from peer.ID, vote *VoteMessage) (err error)
 		return fmt.Errorf("validating vote message: %w", err)
 	}
 
-	threshold := s.state.threshold() + 1
-	logger.Debugf(
-		"validated vote message %v from %s, round %d, subround %d, "+
-			"prevote count %d, precommit count %d, votes needed %d",
-		grandpaVote, vote.Message.AuthorityID, vote.Round, vote.Message.Stage,
-		s.lenVotes(prevote), s.lenVotes(precommit), threshold)
+	_, env, _ := s.currentVoter()
+	if env == nil || env.setID != vote.SetID {
+		return fmt.Errorf("%w: no voter running for set id %d", ErrSetIDMismatch, vote.SetID)
+	}
+
+	message, err := voteToMessage(*grandpaVote, vote.Message.Stage)
+	if err != nil {
+		return fmt.Errorf("converting vote: %w", err)
+	}
+
+	logger.Debugf("validated vote message %v from %s, round %d, subround %d",
+		grandpaVote, vote.Message.AuthorityID, vote.Round, vote.Message.Stage)
+
+	env.importMessage(vote.Round, finalitySignedMessage{
+		Message:   message,
+		Signature: vote.Message.Signature,
+		ID:        authorityIDToString(vote.Message.AuthorityID),
+	})
 	return nil
 }
 
@@ -1179,7 +599,8 @@ func (s *Service) handleCommitMessage(commitMessage *CommitMessage) error {
 		),
 	)
 
-	has, err := s.blockState.HasFinalisedBlock(commitMessage.Round, s.state.setID)
+	setID := s.GetSetID()
+	has, err := s.blockState.HasFinalisedBlock(commitMessage.Round, setID)
 	if err != nil {
 		return fmt.Errorf("checking for a finalized block in the block state: %w", err)
 	}
@@ -1189,7 +610,7 @@ func (s *Service) handleCommitMessage(commitMessage *CommitMessage) error {
 	}
 
 	// check justification here
-	err = verifyCommitMessageJustification(*commitMessage, s.state.setID,
+	err = verifyCommitMessageJustification(*commitMessage, setID,
 		s.state.threshold(), s.authorityKeySet(), s.blockState)
 	if err != nil {
 		if errors.Is(err, blocktree.ErrStartNodeNotFound) {
@@ -1199,22 +620,37 @@ func (s *Service) handleCommitMessage(commitMessage *CommitMessage) error {
 		return fmt.Errorf("verifying commit message justification: %w", err)
 	}
 
-	err = s.blockState.SetFinalisedHash(commitMessage.Vote.Hash, commitMessage.Round, s.state.setID)
-	if err != nil {
-		return fmt.Errorf("setting finalised hash: %w", err)
+	_, env, globalIn := s.currentVoter()
+	if env == nil || env.setID != commitMessage.SetID {
+		return fmt.Errorf("%w: no voter running for set id %d", ErrSetIDMismatch, commitMessage.SetID)
 	}
 
-	preCommitSigned, err := compactToJustification(commitMessage.Precommits, commitMessage.AuthData)
-	if err != nil {
-		return fmt.Errorf("compacting justification: %w", err)
+	compactCommit := finality_grandpa.CompactCommit[hash.H256, uint32, [64]byte, string]{
+		TargetHash:   hash.H256(commitMessage.Vote.Hash.ToBytes()),
+		TargetNumber: commitMessage.Vote.Number,
+		Precommits:   make([]finalityPrecommit, len(commitMessage.Precommits)),
+		AuthData:     make(finality_grandpa.MultiAuthData[[64]byte, string], len(commitMessage.AuthData)),
 	}
-
-	err = s.grandpaState.SetPrecommits(commitMessage.Round, commitMessage.SetID, preCommitSigned)
-	if err != nil {
-		return fmt.Errorf("setting precommits: %w", err)
+	for i, precommit := range commitMessage.Precommits {
+		compactCommit.Precommits[i] = finalityPrecommit{
+			TargetHash:   hash.H256(precommit.Hash.ToBytes()),
+			TargetNumber: precommit.Number,
+		}
+		compactCommit.AuthData[i].Signature = commitMessage.AuthData[i].Signature
+		compactCommit.AuthData[i].ID = authorityIDToString(commitMessage.AuthData[i].AuthorityID)
 	}
 
-	// TODO: re-add catch-up logic (#1531)
+	round := commitMessage.Round
+	env.importGlobalMessage(globalIn, finality_grandpa.NewCommunicationIn[hash.H256, uint32, [64]byte, string](
+		finality_grandpa.CommunicationInCommit[hash.H256, uint32, [64]byte, string]{
+			Number:        round,
+			CompactCommit: compactCommit,
+			Callback: func(outcome finality_grandpa.CommitProcessingOutcome) {
+				if _, ok := outcome.Value().(finality_grandpa.CommitProcessingOutcomeBad); ok {
+					logger.Debugf("voter rejected commit for round %d and set id %d", round, env.setID)
+				}
+			},
+		}))
 	return nil
 }
 
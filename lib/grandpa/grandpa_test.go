@@ -0,0 +1,100 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package grandpa
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_Service_isPaused(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		grandpaStateGetter func(ctrl *gomock.Controller) GrandpaState
+		paused             bool
+		errWrapped         error
+		errMessage         string
+	}{
+		"no_pause": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetNextPause().Return(uint(0), database.ErrNotFound)
+				return grandpaState
+			},
+		},
+		"get_next_pause_error": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetNextPause().Return(uint(0), errTest)
+				return grandpaState
+			},
+			errWrapped: errTest,
+			errMessage: "getting next pause: test error",
+		},
+		"pause_without_resume": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetNextPause().Return(uint(10), nil)
+				grandpaState.EXPECT().GetNextResume().Return(uint(0), database.ErrNotFound)
+				return grandpaState
+			},
+			paused: true,
+		},
+		"get_next_resume_error": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetNextPause().Return(uint(10), nil)
+				grandpaState.EXPECT().GetNextResume().Return(uint(0), errTest)
+				return grandpaState
+			},
+			errWrapped: errTest,
+			errMessage: "getting next resume: test error",
+		},
+		"resumed_after_pause": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetNextPause().Return(uint(10), nil)
+				grandpaState.EXPECT().GetNextResume().Return(uint(15), nil)
+				return grandpaState
+			},
+		},
+		"paused_after_previous_resume": {
+			grandpaStateGetter: func(ctrl *gomock.Controller) GrandpaState {
+				grandpaState := NewMockGrandpaState(ctrl)
+				grandpaState.EXPECT().GetNextPause().Return(uint(20), nil)
+				grandpaState.EXPECT().GetNextResume().Return(uint(15), nil)
+				return grandpaState
+			},
+			paused: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			service := &Service{
+				grandpaState: testCase.grandpaStateGetter(ctrl),
+			}
+
+			paused, err := service.isPaused()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.paused, paused)
+		})
+	}
+}
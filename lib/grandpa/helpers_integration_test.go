@@ -113,6 +113,7 @@ func setupGrandpa(t *testing.T, kp *ed25519.Keypair) *Service {
 	cfg := &Config{
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Voters:       newTestVoters(t),
 		Keypair:      kp,
 		LogLvl:       log.Info,
@@ -177,6 +178,7 @@ func newTestService(t *testing.T, keypair *ed25519.Keypair) (*Service, *state.Se
 	cfg := &Config{
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Voters:       newTestVoters(t),
 		Authority:    true,
 		Network:      net,
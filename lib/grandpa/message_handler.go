@@ -7,6 +7,7 @@
 	"bytes"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/internal/database"
@@ -27,6 +28,10 @@ type MessageHandler struct {
 	grandpa    *Service
 	blockState BlockState
 	telemetry  Telemetry
+
+	catchUpLock sync.Mutex
+	// lastCatchUpRequest is the last catch up request sent, to avoid requesting the same round twice.
+	lastCatchUpRequest CatchUpRequest
 }
 
 // NewMessageHandler returns a new MessageHandler
@@ -81,12 +86,56 @@ func (h *MessageHandler) handleMessage(from peer.ID, m GrandpaMessage) (network.
 func (h *MessageHandler) handleNeighbourMessage(packet *NeighbourPacketV1, from peer.ID) {
 	logger.Debugf("handling neighbour message from peer %v with set id %v and round %v",
 		from.ShortString(), packet.SetID, packet.Round)
+
+	if h.grandpa.authority {
+		err := h.requestCatchUp(packet, from)
+		if err != nil {
+			logger.Warnf("requesting catch up from peer %s: %s", from, err)
+		}
+	}
+
 	h.grandpa.neighborMsgChan <- neighborData{
 		peer:        from,
 		neighborMsg: packet,
 	}
 }
 
+// catchUpThreshold is the number of rounds a peer must be ahead of us to request a catch up from it.
+const catchUpThreshold = 2
+
+// requestCatchUp sends a catch up request to the peer if it is voting in the
+// same authority set and at least catchUpThreshold rounds ahead of us.
+func (h *MessageHandler) requestCatchUp(packet *NeighbourPacketV1, from peer.ID) error {
+	setID, round := h.grandpa.GetSetID(), h.grandpa.GetRound()
+	if packet.SetID != setID || packet.Round < round+catchUpThreshold {
+		return nil
+	}
+
+	request := newCatchUpRequest(packet.Round-1, setID)
+
+	h.catchUpLock.Lock()
+	defer h.catchUpLock.Unlock()
+
+	if h.lastCatchUpRequest.SetID == request.SetID && h.lastCatchUpRequest.Round >= request.Round {
+		return nil
+	}
+
+	message, err := request.ToConsensusMessage()
+	if err != nil {
+		return fmt.Errorf("converting catch up request to consensus message: %w", err)
+	}
+
+	logger.Debugf("sending catch up request for round %d and set id %d to peer %s",
+		request.Round, request.SetID, from)
+	err = h.grandpa.network.SendMessage(from, message)
+	if err != nil {
+		return fmt.Errorf("sending catch up request: %w", err)
+	}
+
+	h.lastCatchUpRequest = *request
+	return nil
+}
+
 func (h *MessageHandler) handleCatchUpRequest(msg *CatchUpRequest) (*ConsensusMessage, error) {
 	if !h.grandpa.authority {
 		return nil, nil
@@ -95,11 +144,11 @@ func (h *MessageHandler) handleCatchUpRequest(msg *CatchUpRequest) (*ConsensusMe
 	logger.Debugf("received catch up request for round %d and set id %d",
 		msg.Round, msg.SetID)
 
-	if msg.SetID != h.grandpa.state.setID {
+	if msg.SetID != h.grandpa.GetSetID() {
 		return nil, ErrSetIDMismatch
 	}
 
-	if msg.Round >= h.grandpa.state.round {
+	if msg.Round >= h.grandpa.GetRound() {
 		return nil, ErrInvalidCatchUpRound
 	}
 
@@ -133,22 +182,12 @@ func (h *MessageHandler) handleCatchUpResponse(msg *CatchUpResponse) error {
 		return err
 	}
 
-	// TODO: re-add catch-up logic (#1531)
-	if true {
-		return nil
-	}
-
-	// if we aren't currently expecting a catch up response, return
-	if !h.grandpa.paused.Load().(bool) {
-		logger.Debug("not currently paused, ignoring catch up response")
-		return nil
-	}
-
-	if msg.SetID != h.grandpa.state.setID {
+	_, env, globalIn := h.grandpa.currentVoter()
+	if env == nil || msg.SetID != env.setID {
 		return ErrSetIDMismatch
 	}
 
-	if msg.Round != h.grandpa.state.round-1 {
+	if msg.Round <= h.grandpa.GetRound() {
 		return ErrInvalidCatchUpResponseRound
 	}
 
@@ -169,27 +208,46 @@ func (h *MessageHandler) handleCatchUpResponse(msg *CatchUpResponse) error {
 		return err
 	}
 
-	// set prevotes and precommits in db
-	if err = h.grandpa.grandpaState.SetPrevotes(msg.Round, msg.SetID, msg.PreVoteJustification); err != nil {
-		return err
-	}
-
-	if err = h.grandpa.grandpaState.SetPrecommits(msg.Round, msg.SetID, msg.PreCommitJustification); err != nil {
-		return err
+	catchUp := finality_grandpa.CatchUp[hash.H256, uint32, [64]byte, string]{
+		RoundNumber: msg.Round,
+		Prevotes: make([]finality_grandpa.SignedPrevote[hash.H256, uint32, [64]byte, string],
+			len(msg.PreVoteJustification)),
+		Precommits: make([]finality_grandpa.SignedPrecommit[hash.H256, uint32, [64]byte, string],
+			len(msg.PreCommitJustification)),
+		BaseHash:   hash.H256(msg.Hash.ToBytes()),
+		BaseNumber: msg.Number,
+	}
+	for i, signedVote := range msg.PreVoteJustification {
+		catchUp.Prevotes[i] = finality_grandpa.SignedPrevote[hash.H256, uint32, [64]byte, string]{
+			Prevote: finalityPrevote{
+				TargetHash:   hash.H256(signedVote.Vote.Hash.ToBytes()),
+				TargetNumber: signedVote.Vote.Number,
+			},
+			Signature: signedVote.Signature,
+			ID:        authorityIDToString(signedVote.AuthorityID),
+		}
 	}
-
-	// update state and signal to grandpa we are ready to initiate
-	head, err := h.grandpa.blockState.GetHeader(msg.Hash)
-	if err != nil {
-		return err
+	for i, signedVote := range msg.PreCommitJustification {
+		catchUp.Precommits[i] = finality_grandpa.SignedPrecommit[hash.H256, uint32, [64]byte, string]{
+			Precommit: finalityPrecommit{
+				TargetHash:   hash.H256(signedVote.Vote.Hash.ToBytes()),
+				TargetNumber: signedVote.Vote.Number,
+			},
+			Signature: signedVote.Signature,
+			ID:        authorityIDToString(signedVote.AuthorityID),
+		}
 	}
 
-	h.grandpa.head = head
-	h.grandpa.state.round = msg.Round
-	close(h.grandpa.resumed)
-	h.grandpa.resumed = make(chan struct{})
-	h.grandpa.paused.Store(false)
-	logger.Debugf("caught up to round; unpaused service and grandpa state round is %d", h.grandpa.state.round)
+	round := msg.Round
+	env.importGlobalMessage(globalIn, finality_grandpa.NewCommunicationIn[hash.H256, uint32, [64]byte, string](
+		finality_grandpa.CommunicationInCatchUp[hash.H256, uint32, [64]byte, string]{
+			CatchUp: catchUp,
+			Callback: func(outcome finality_grandpa.CatchUpProcessingOutcome) {
+				if _, ok := outcome.Value().(finality_grandpa.CatchUpProcessingOutcomeGood); ok {
+					logger.Debugf("caught up to round %d and set id %d", round, env.setID)
+				}
+			},
+		}))
 	return nil
 }
 
@@ -19,6 +19,8 @@
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+
+	finality_grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
 )
 
 var testHeader = &types.Header{
@@ -186,6 +188,7 @@ func TestMessageHandler_VoteMessage(t *testing.T) {
 
 	gs.state.round = round
 	gs.state.setID = setID
+	gs.env = newEnvironment(gs, setID, gs.state.voters)
 
 	createdSigned, vm := createAndSignVoteMessage(t, charlieAuthority, round, setID, v, precommit)
 
@@ -198,9 +201,10 @@ func TestMessageHandler_VoteMessage(t *testing.T) {
 	require.Nil(t, out)
 
 	charlieAuthorityPublicKeyBytes := charlieAuthority.Public().(*ed25519.PublicKey).AsBytes()
-	gotSignedVote, ok := gs.loadVote(charlieAuthorityPublicKeyBytes, precommit)
-	require.True(t, ok)
-	require.Equal(t, createdSigned, gotSignedVote)
+	imported := <-gs.env.rounds[round].incoming
+	require.Equal(t, authorityIDToString(charlieAuthorityPublicKeyBytes), imported.SignedMessage.ID)
+	require.Equal(t, createdSigned.Signature, imported.SignedMessage.Signature)
+	require.Equal(t, v.Hash, common.NewHash(imported.SignedMessage.Message.Target().Hash.Bytes()))
 }
 
 func TestMessageHandler_NeighbourMessage(t *testing.T) {
@@ -304,9 +308,11 @@ func TestMessageHandler_CommitMessage_NoCatchUpRequest_ValidSig(t *testing.T) {
 	err = st.Grandpa.SetPrecommits(round, gs.state.setID, just)
 	require.NoError(t, err)
 
-	fm, err := gs.newCommitMessage(gs.head, round, gs.state.setID)
+	fm, err := gs.newCommitMessage(testGenesisHeader, round, gs.state.setID)
 	require.NoError(t, err)
 	fm.Vote = *NewVote(testHash, uint32(round))
+	gs.env = newEnvironment(gs, gs.state.setID, gs.state.voters)
+	gs.globalIn = make(chan finality_grandpa.GlobalInItem, 1)
 
 	digest := types.NewDigest()
 	prd, err := types.NewBabeSecondaryPlainPreDigest(0, 1).ToPreRuntimeDigest()
@@ -329,9 +335,8 @@ func TestMessageHandler_CommitMessage_NoCatchUpRequest_ValidSig(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, out)
 
-	hash, err := st.Block.GetFinalisedHash(fm.Round, gs.state.setID)
-	require.NoError(t, err)
-	require.Equal(t, fm.Vote.Hash, hash)
+	imported := <-gs.globalIn
+	require.NotNil(t, imported.CommunicationIn)
 }
 
 func TestMessageHandler_CommitMessage_NoCatchUpRequest_MinVoteError(t *testing.T) {
@@ -388,10 +393,12 @@ func TestMessageHandler_CommitMessage_WithCatchUpRequest(t *testing.T) {
 	err = st.Grandpa.SetPrecommits(77, gs.state.setID, just)
 	require.NoError(t, err)
 
-	fm, err := gs.newCommitMessage(gs.head, 77, gs.state.setID)
+	fm, err := gs.newCommitMessage(testGenesisHeader, 77, gs.state.setID)
 	require.NoError(t, err)
 
 	gs.state.voters = gs.state.voters[:1]
+	gs.env = newEnvironment(gs, gs.state.setID, gs.state.voters)
+	gs.globalIn = make(chan finality_grandpa.GlobalInItem, 1)
 
 	ctrl := gomock.NewController(t)
 	telemetryMock := NewMockTelemetry(ctrl)
@@ -694,9 +701,12 @@ func TestMessageHandler_HandleCatchUpResponse(t *testing.T) {
 	telemetryMock := NewMockTelemetry(ctrl)
 
 	h := NewMessageHandler(gs, st.Block, telemetryMock)
+	gs.env = newEnvironment(gs, gs.state.setID, gs.state.voters)
+	gs.globalIn = make(chan finality_grandpa.GlobalInItem, 1)
 
+	// the catch up response is for a round ahead of ours
 	round := uint64(1)
-	gs.state.round = round + 1
+	gs.state.round = round - 1
 
 	pvJust := buildTestJustification(t, int(gs.state.threshold()), round, gs.state.setID, kr, prevote)
 	pcJust := buildTestJustification(t, int(gs.state.threshold()), round, gs.state.setID, kr, precommit)
@@ -712,7 +722,10 @@ func TestMessageHandler_HandleCatchUpResponse(t *testing.T) {
 	out, err := h.handleMessage("", msg)
 	require.NoError(t, err)
 	require.Nil(t, out)
-	require.Equal(t, round+1, gs.state.round)
+
+	imported := <-gs.globalIn
+	require.NotNil(t, imported.CommunicationIn)
+	require.NoError(t, imported.Error)
 }
 
 func Test_getEquivocatoryVoters(t *testing.T) {
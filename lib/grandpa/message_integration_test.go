@@ -38,13 +38,13 @@ func TestCommitMessageEncode(t *testing.T) {
 	err = st.Grandpa.SetPrecommits(77, gs.state.setID, just)
 	require.NoError(t, err)
 
-	fm, err := gs.newCommitMessage(gs.head, 77, 0)
+	fm, err := gs.newCommitMessage(testGenesisHeader, 77, 0)
 	require.NoError(t, err)
 	precommits, authData := justificationToCompact(just)
 
 	expected := CommitMessage{
 		Round:      77,
-		Vote:       *NewVoteFromHeader(gs.head),
+		Vote:       *NewVoteFromHeader(testGenesisHeader),
 		Precommits: precommits,
 		AuthData:   authData,
 	}
@@ -131,13 +131,13 @@ func TestCommitMessageToConsensusMessage(t *testing.T) {
 	err = st.Grandpa.SetPrecommits(77, gs.state.setID, just)
 	require.NoError(t, err)
 
-	fm, err := gs.newCommitMessage(gs.head, 77, 0)
+	fm, err := gs.newCommitMessage(testGenesisHeader, 77, 0)
 	require.NoError(t, err)
 	precommits, authData := justificationToCompact(just)
 
 	expected := &CommitMessage{
 		Round:      77,
-		Vote:       *NewVoteFromHeader(gs.head),
+		Vote:       *NewVoteFromHeader(testGenesisHeader),
 		Precommits: precommits,
 		AuthData:   authData,
 	}
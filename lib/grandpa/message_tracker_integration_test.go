@@ -48,12 +48,15 @@ func TestMessageTracker_ProcessMessage(t *testing.T) {
 	require.NoError(t, err)
 
 	gs := setupGrandpa(t, kr.Bob().(*ed25519.Keypair))
-	defer gs.cancel()
 
 	state.AddBlocksToState(t, gs.blockState.(*state.BlockState), 3, false)
 
 	err = gs.Start()
 	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := gs.Stop()
+		require.NoError(t, err)
+	})
 
 	time.Sleep(time.Second) // wait for round to initiate
 
@@ -93,13 +96,7 @@ func TestMessageTracker_ProcessMessage(t *testing.T) {
 	require.NoError(t, err)
 
 	time.Sleep(time.Second)
-	expectedVote := &Vote{
-		Hash:   msg.Message.BlockHash,
-		Number: msg.Message.Number,
-	}
-	pv, has := gs.prevotes.Load(kr.Alice().Public().(*ed25519.PublicKey).AsBytes())
-	require.True(t, has)
-	require.Equal(t, expectedVote, &pv.(*SignedVote).Vote)
+	require.Contains(t, gs.PreVotes(), authorityID)
 }
 
 func TestMessageTracker_MapInsideMap(t *testing.T) {
@@ -138,6 +135,7 @@ func TestMessageTracker_SendMessage(t *testing.T) {
 
 	state.AddBlocksToState(t, gs.blockState.(*state.BlockState), 3, false)
 	gs.tracker = newTracker(gs.blockState, gs.messageHandler)
+	gs.env = newEnvironment(gs, gs.state.setID, gs.state.voters)
 	gs.tracker.start()
 	defer gs.tracker.stop()
 
@@ -156,8 +154,11 @@ func TestMessageTracker_SendMessage(t *testing.T) {
 		Digest:     digest,
 	}
 
+	// round 0 is concluded from the start, the vote is for the first round
+	const round = 1
+	gs.setRound(round)
 	aliceAuthority := kr.Alice().(*ed25519.Keypair)
-	aliceSignedVote, aliceVoteMessage := createAndSignVoteMessage(t, aliceAuthority, gs.state.round,
+	aliceSignedVote, aliceVoteMessage := createAndSignVoteMessage(t, aliceAuthority, round,
 		gs.state.setID, NewVoteFromHeader(next), prevote)
 
 	const expectedErr = "validating vote: block does not exist"
@@ -179,8 +180,11 @@ func TestMessageTracker_SendMessage(t *testing.T) {
 	// was included in the block tree
 	time.Sleep(2 * time.Second)
 
-	aliceAuthorityPublicBytes := aliceAuthority.Public().(*ed25519.PublicKey).AsBytes()
-	gotSignedVote, ok := gs.loadVote(aliceAuthorityPublicBytes, prevote)
-	require.True(t, ok)
-	require.Equal(t, aliceSignedVote, gotSignedVote)
+	gs.env.mtx.Lock()
+	channels := gs.env.rounds[round]
+	gs.env.mtx.Unlock()
+	require.NotNil(t, channels)
+	imported := <-channels.incoming
+	require.Equal(t, authorityIDToString(authorityID), imported.SignedMessage.ID)
+	require.Equal(t, aliceSignedVote.Signature, imported.SignedMessage.Signature)
 }
@@ -8,7 +8,6 @@
 import (
 	"container/list"
 	"fmt"
-	"sync"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/dot/telemetry"
@@ -22,6 +21,8 @@
 	"go.uber.org/mock/gomock"
 
 	"github.com/stretchr/testify/require"
+
+	finality_grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
 )
 
 // getMessageFromVotesMapping returns the vote message
@@ -78,7 +79,6 @@ func TestMessageTracker_handleTick_commitMessage(t *testing.T) {
 					grandpaState: grandpaStateMock,
 					blockState:   blockStateMock,
 					network:      networkMock,
-					prevotes:     new(sync.Map),
 				}
 				messageHandler := NewMessageHandler(grandpaService, blockStateMock, nil)
 				grandpaService.messageHandler = messageHandler
@@ -117,13 +117,7 @@ func TestMessageTracker_handleTick_commitMessage(t *testing.T) {
 					HasFinalisedBlock(commitMessageRound, serviceStateSetID).
 					Return(false, nil)
 
-				blockStateMock.EXPECT().
-					SetFinalisedHash(testHash, commitMessageRound, serviceStateSetID).
-					Return(nil)
-
 				grandpaStateMock := NewMockGrandpaState(ctrl)
-				grandpaStateMock.EXPECT().
-					SetPrecommits(commitMessageRound, uint64(0), []types.GrandpaSignedVote{})
 
 				telemetryMock := NewMockTelemetry(ctrl)
 
@@ -142,8 +136,9 @@ func TestMessageTracker_handleTick_commitMessage(t *testing.T) {
 					grandpaState: grandpaStateMock,
 					blockState:   blockStateMock,
 					network:      networkMock,
-					prevotes:     new(sync.Map),
+					globalIn:     make(chan finality_grandpa.GlobalInItem, 1),
 				}
+				grandpaService.env = newEnvironment(grandpaService, serviceStateSetID, nil)
 				messageHandler := NewMessageHandler(grandpaService, blockStateMock, nil)
 				grandpaService.messageHandler = messageHandler
 				grandpaService.tracker = newTracker(blockStateMock, messageHandler)
@@ -242,16 +237,14 @@ func TestMessageTracker_handleTick_voteMessage(t *testing.T) {
 
 			if tt.voteRound < tt.serviceRound {
 				blockStateMock.EXPECT().
-					GetFinalisedHeader(tt.voteRound, setID).
+					HasHeader(testGenesisHeader.Hash()).
+					Return(true, nil)
+				blockStateMock.EXPECT().
+					GetHighestFinalisedHeader().
 					Return(testGenesisHeader, nil)
-
-				grandpaStateMock.EXPECT().
-					GetPrecommits(tt.voteRound, setID).
-					Return([]types.GrandpaSignedVote{}, nil)
-
-				var notificationMessage NotificationsMessage = &ConsensusMessage{}
-				networkMock.EXPECT().
-					SendMessage(fakePeerID, gomock.AssignableToTypeOf(notificationMessage))
+				blockStateMock.EXPECT().
+					IsDescendantOf(testGenesisHeader.Hash(), testGenesisHeader.Hash()).
+					Return(true, nil)
 			}
 
 			grandpaService := &Service{
@@ -265,8 +258,8 @@ func TestMessageTracker_handleTick_voteMessage(t *testing.T) {
 				grandpaState: grandpaStateMock,
 				blockState:   blockStateMock,
 				network:      networkMock,
-				prevotes:     new(sync.Map),
 			}
+			grandpaService.env = newEnvironment(grandpaService, setID, grandpaService.state.voters)
 
 			messageHandler := NewMessageHandler(grandpaService, blockStateMock, telemetryMock)
 			grandpaService.tracker = newTracker(blockStateMock, messageHandler)
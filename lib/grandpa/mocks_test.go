@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/ChainSafe/gossamer/lib/grandpa (interfaces: BlockState,GrandpaState,Network)
+// Source: github.com/ChainSafe/gossamer/lib/grandpa (interfaces: BlockState,GrandpaState,StorageState,Network)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks_test.go -package grandpa . BlockState,GrandpaState,Network
+//	mockgen -destination=mocks_test.go -package grandpa . BlockState,GrandpaState,StorageState,Network
 //
 
 // Package grandpa is a generated GoMock package.
@@ -16,6 +16,7 @@
 	types "github.com/ChainSafe/gossamer/dot/types"
 	common "github.com/ChainSafe/gossamer/lib/common"
 	runtime "github.com/ChainSafe/gossamer/lib/runtime"
+	storage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	peer "github.com/libp2p/go-libp2p/core/peer"
 	protocol "github.com/libp2p/go-libp2p/core/protocol"
 	gomock "go.uber.org/mock/gomock"
@@ -335,6 +336,20 @@ func (mr *MockBlockStateMockRecorder) IsDescendantOf(arg0, arg1 any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDescendantOf", reflect.TypeOf((*MockBlockState)(nil).IsDescendantOf), arg0, arg1)
 }
 
+// Leaves mocks base method.
+func (m *MockBlockState) Leaves() []common.Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Leaves")
+	ret0, _ := ret[0].([]common.Hash)
+	return ret0
+}
+
+// Leaves indicates an expected call of Leaves.
+func (mr *MockBlockStateMockRecorder) Leaves() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Leaves", reflect.TypeOf((*MockBlockState)(nil).Leaves))
+}
+
 // LowestCommonAncestor mocks base method.
 func (m *MockBlockState) LowestCommonAncestor(arg0, arg1 common.Hash) (common.Hash, error) {
 	m.ctrl.T.Helper()
@@ -446,6 +461,21 @@ func (mr *MockGrandpaStateMockRecorder) GetCurrentSetID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentSetID", reflect.TypeOf((*MockGrandpaState)(nil).GetCurrentSetID))
 }
 
+// GetHasVoted mocks base method.
+func (m *MockGrandpaState) GetHasVoted(arg0, arg1 uint64) (types.GrandpaHasVoted, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHasVoted", arg0, arg1)
+	ret0, _ := ret[0].(types.GrandpaHasVoted)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHasVoted indicates an expected call of GetHasVoted.
+func (mr *MockGrandpaStateMockRecorder) GetHasVoted(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHasVoted", reflect.TypeOf((*MockGrandpaState)(nil).GetHasVoted), arg0, arg1)
+}
+
 // GetLatestRound mocks base method.
 func (m *MockGrandpaState) GetLatestRound() (uint64, error) {
 	m.ctrl.T.Helper()
@@ -461,6 +491,36 @@ func (mr *MockGrandpaStateMockRecorder) GetLatestRound() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestRound", reflect.TypeOf((*MockGrandpaState)(nil).GetLatestRound))
 }
 
+// GetNextPause mocks base method.
+func (m *MockGrandpaState) GetNextPause() (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextPause")
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextPause indicates an expected call of GetNextPause.
+func (mr *MockGrandpaStateMockRecorder) GetNextPause() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextPause", reflect.TypeOf((*MockGrandpaState)(nil).GetNextPause))
+}
+
+// GetNextResume mocks base method.
+func (m *MockGrandpaState) GetNextResume() (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextResume")
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextResume indicates an expected call of GetNextResume.
+func (mr *MockGrandpaStateMockRecorder) GetNextResume() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextResume", reflect.TypeOf((*MockGrandpaState)(nil).GetNextResume))
+}
+
 // GetPrecommits mocks base method.
 func (m *MockGrandpaState) GetPrecommits(arg0, arg1 uint64) ([]types.GrandpaSignedVote, error) {
 	m.ctrl.T.Helper()
@@ -521,6 +581,35 @@ func (mr *MockGrandpaStateMockRecorder) NextGrandpaAuthorityChange(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextGrandpaAuthorityChange", reflect.TypeOf((*MockGrandpaState)(nil).NextGrandpaAuthorityChange), arg0, arg1)
 }
 
+// NextGrandpaPause mocks base method.
+func (m *MockGrandpaState) NextGrandpaPause(arg0 common.Hash, arg1 uint) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextGrandpaPause", arg0, arg1)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextGrandpaPause indicates an expected call of NextGrandpaPause.
+func (mr *MockGrandpaStateMockRecorder) NextGrandpaPause(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextGrandpaPause", reflect.TypeOf((*MockGrandpaState)(nil).NextGrandpaPause), arg0, arg1)
+}
+
+// SetHasVoted mocks base method.
+func (m *MockGrandpaState) SetHasVoted(arg0, arg1 uint64, arg2 types.GrandpaHasVoted) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHasVoted", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetHasVoted indicates an expected call of SetHasVoted.
+func (mr *MockGrandpaStateMockRecorder) SetHasVoted(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHasVoted", reflect.TypeOf((*MockGrandpaState)(nil).SetHasVoted), arg0, arg1, arg2)
+}
+
 // SetLatestRound mocks base method.
 func (m *MockGrandpaState) SetLatestRound(arg0 uint64) error {
 	m.ctrl.T.Helper()
@@ -563,6 +652,44 @@ func (mr *MockGrandpaStateMockRecorder) SetPrevotes(arg0, arg1, arg2 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrevotes", reflect.TypeOf((*MockGrandpaState)(nil).SetPrevotes), arg0, arg1, arg2)
 }
 
+// MockStorageState is a mock of StorageState interface.
+type MockStorageState struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageStateMockRecorder
+}
+
+// MockStorageStateMockRecorder is the mock recorder for MockStorageState.
+type MockStorageStateMockRecorder struct {
+	mock *MockStorageState
+}
+
+// NewMockStorageState creates a new mock instance.
+func NewMockStorageState(ctrl *gomock.Controller) *MockStorageState {
+	mock := &MockStorageState{ctrl: ctrl}
+	mock.recorder = &MockStorageStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorageState) EXPECT() *MockStorageStateMockRecorder {
+	return m.recorder
+}
+
+// TrieState mocks base method.
+func (m *MockStorageState) TrieState(arg0 *common.Hash) (*storage.TrieState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrieState", arg0)
+	ret0, _ := ret[0].(*storage.TrieState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrieState indicates an expected call of TrieState.
+func (mr *MockStorageStateMockRecorder) TrieState(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrieState", reflect.TypeOf((*MockStorageState)(nil).TrieState), arg0)
+}
+
 // MockNetwork is a mock of Network interface.
 type MockNetwork struct {
 	ctrl     *gomock.Controller
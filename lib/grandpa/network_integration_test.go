@@ -10,6 +10,7 @@
 
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	finality_grandpa "github.com/ChainSafe/gossamer/pkg/finality-grandpa"
 	"go.uber.org/mock/gomock"
 
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -54,7 +55,7 @@ func TestHandleNetworkMessage(t *testing.T) {
 	err = st.Grandpa.SetPrecommits(77, gs.state.setID, just)
 	require.NoError(t, err)
 
-	fm, err := gs.newCommitMessage(gs.head, 77, 0)
+	fm, err := gs.newCommitMessage(testGenesisHeader, 77, 0)
 	require.NoError(t, err)
 
 	cm, err := fm.ToConsensusMessage()
@@ -66,10 +67,13 @@ func TestHandleNetworkMessage(t *testing.T) {
 
 	h := NewMessageHandler(gs, st.Block, telemetryMock)
 	gs.messageHandler = h
+	gs.env = newEnvironment(gs, gs.state.setID, gs.state.voters)
+	gs.globalIn = make(chan finality_grandpa.GlobalInItem, 1)
 
 	propagate, err := gs.handleNetworkMessage(peer.ID(""), cm)
 	require.NoError(t, err)
 	require.True(t, propagate)
+	require.Len(t, gs.globalIn, 1)
 
 	neighbourMsg := &NeighbourPacketV1{}
 	cm, err = neighbourMsg.ToConsensusMessage()
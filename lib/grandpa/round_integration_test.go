@@ -6,79 +6,99 @@
 package grandpa
 
 import (
-	"context"
+	"bytes"
 	"fmt"
 	"math/rand"
 	"sync"
-	"sync/atomic"
 	"testing"
 	"time"
 
-	"github.com/ChainSafe/gossamer/dot/network"
 	"github.com/ChainSafe/gossamer/dot/state"
-	"github.com/ChainSafe/gossamer/dot/telemetry"
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/keystore"
+	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/libp2p/go-libp2p/core/peer"
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
-func TestGrandpa_DifferentChains(t *testing.T) {
-	// this asserts that all validators finalise the same block if they all see the
-	// same pre-votes and pre-commits, even if their chains are different lengths (+/-1 block)
-	kr, err := keystore.NewEd25519Keyring()
-	require.NoError(t, err)
+// newTestVoterServices returns grandpa services voting with the given keypairs, whose
+// gossiped votes are handled by the other services and whose first commits are recorded.
+func newTestVoterServices(t *testing.T, keypairs []*ed25519.Keypair) (
+	services []*Service, commits func() []*CommitMessage) {
+	t.Helper()
 
-	gss := make([]*Service, len(kr.Keys))
-	prevotes := new(sync.Map)
-	precommits := new(sync.Map)
+	ctrl := gomock.NewController(t)
+	voters := make([]Voter, len(keypairs))
+	for idx, kp := range keypairs {
+		voters[idx] = Voter{Key: *kp.Public().(*ed25519.PublicKey), ID: uint64(idx)}
+	}
 
-	for i, gs := range gss {
-		gs = setupGrandpa(t, kr.Keys[i])
-		gss[i] = gs
+	var commitsMutex sync.Mutex
+	producedCommits := make([]*CommitMessage, len(keypairs))
+	services = make([]*Service, len(keypairs))
 
-		r := uint(rand.Intn(2)) // 0 or 1
-		state.AddBlocksToState(t, gs.blockState.(*state.BlockState), 4+r, false)
-		pv, err := gs.determinePreVote()
-		require.NoError(t, err)
-		prevotes.Store(gs.publicKeyBytes(), &SignedVote{
-			Vote: *pv,
-		})
-	}
+	for idx, kp := range keypairs {
+		idx := idx
+		st := newTestState(t)
 
-	// only want to add prevotes for a node that has a block that exists on its chain
-	for _, gs := range gss {
-		prevotes.Range(func(key, prevote interface{}) bool {
-			k := key.(ed25519.PublicKeyBytes)
-			pv := prevote.(*SignedVote)
-			err = gs.validateVote(&pv.Vote)
-			if err == nil {
-				gs.prevotes.Store(k, pv)
+		telemetryMock := NewMockTelemetry(ctrl)
+		telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+		// the network mock works like a wire between the services
+		networkMock := NewMockNetwork(ctrl)
+		networkMock.EXPECT().RegisterNotificationsProtocol(gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+		networkMock.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+		networkMock.EXPECT().GossipMessage(gomock.Any()).DoAndReturn(func(msg NotificationsMessage) {
+			consensusMessage, ok := msg.(*ConsensusMessage)
+			require.True(t, ok, "expecting *ConsensusMessage, got %T", msg)
+
+			message, err := decodeMessage(consensusMessage)
+			require.NoError(t, err)
+
+			switch message := message.(type) {
+			case *VoteMessage:
+				for neighbourIdx, neighbour := range services {
+					if neighbourIdx != idx {
+						_ = neighbour.handleVoteMessage(peer.ID(fmt.Sprint(idx)), message)
+					}
+				}
+			case *CommitMessage:
+				commitsMutex.Lock()
+				if producedCommits[idx] == nil {
+					producedCommits[idx] = message
+				}
+				commitsMutex.Unlock()
 			}
-			return true
-		})
-	}
+		}).AnyTimes()
 
-	for _, gs := range gss {
-		pc, err := gs.determinePreCommit()
-		require.NoError(t, err)
-		precommits.Store(gs.publicKeyBytes(), &SignedVote{
-			Vote: *pc,
+		// gossamer gossips a prevote and waits 2 * interval to
+		// precommit, keep the rounds short for the test.
+		const subroundInterval = 100 * time.Millisecond
+		service, err := NewService(&Config{
+			BlockState:   st.Block,
+			GrandpaState: st.Grandpa,
+			Voters:       voters,
+			Keypair:      kp,
+			Authority:    true,
+			Network:      networkMock,
+			Interval:     subroundInterval,
+			Telemetry:    telemetryMock,
 		})
-		err = gs.finalise()
 		require.NoError(t, err)
+		services[idx] = service
 	}
 
-	t.Log(gss[0].blockState.(*state.BlockState).BlocktreeAsString())
-	finalised := gss[0].head.Hash()
-
-	for _, gs := range gss[:1] {
-		require.Equal(t, finalised, gs.head.Hash())
+	commits = func() []*CommitMessage {
+		commitsMutex.Lock()
+		defer commitsMutex.Unlock()
+		return append([]*CommitMessage(nil), producedCommits...)
 	}
+	return services, commits
 }
 
 func TestPlayGrandpaRound(t *testing.T) {
@@ -89,11 +109,10 @@ func TestPlayGrandpaRound(t *testing.T) {
 
 	tests := map[string]struct {
 		voters          []*ed25519.Keypair
-		whoEquivocates  map[int]struct{}
-		defineBlockTree func(t *testing.T, blockState BlockState, neighbourServices []*Service)
+		defineBlockTree func(t *testing.T, blockState *state.BlockState, neighbourServices []*Service)
 	}{
 		// this asserts that all validators finalise the same block if they all see the
-		// same pre-votes and pre-commits, even if their chains are different lengths
+		// same pre-votes and pre-commits
 		"base_case": {
 			voters: []*ed25519.Keypair{
 				ed25519Keyring.Alice().(*ed25519.Keypair),
@@ -102,13 +121,12 @@ func TestPlayGrandpaRound(t *testing.T) {
 				ed25519Keyring.Ian().(*ed25519.Keypair),
 				ed25519Keyring.George().(*ed25519.Keypair),
 			},
-			defineBlockTree: func(t *testing.T, blockState BlockState, _ []*Service) {
+			defineBlockTree: func(t *testing.T, blockState *state.BlockState, _ []*Service) {
 				const withBranches = false
 				const baseLength = 4
-				state.AddBlocksToState(t, blockState.(*state.BlockState), baseLength, withBranches)
+				state.AddBlocksToState(t, blockState, baseLength, withBranches)
 			},
 		},
-
 		"varying_chain": {
 			voters: []*ed25519.Keypair{
 				ed25519Keyring.Alice().(*ed25519.Keypair),
@@ -116,14 +134,13 @@ func TestPlayGrandpaRound(t *testing.T) {
 				ed25519Keyring.Charlie().(*ed25519.Keypair),
 				ed25519Keyring.Ian().(*ed25519.Keypair),
 			},
-			defineBlockTree: func(t *testing.T, blockState BlockState, neighbourServices []*Service) {
+			defineBlockTree: func(t *testing.T, blockState *state.BlockState, neighbourServices []*Service) {
 				const diff = 5
-				rand := uint(rand.Intn(diff))
+				rand := uint(rand.Intn(diff)) //nolint:gosec
 
 				const withBranches = false
 				const baseLength = 4
-				headers, _ := state.AddBlocksToState(t, blockState.(*state.BlockState),
-					baseLength+rand, withBranches)
+				headers, _ := state.AddBlocksToState(t, blockState, baseLength+rand, withBranches)
 
 				// sync the created blocks with the neighbour services
 				// letting them know about those blocks
@@ -133,33 +150,12 @@ func TestPlayGrandpaRound(t *testing.T) {
 							Header: *header,
 							Body:   types.Body{},
 						}
-						neighbourService.blockState.(*state.BlockState).AddBlock(block)
+						err := neighbourService.blockState.(*state.BlockState).AddBlock(block)
+						require.NoError(t, err)
 					}
 				}
 			},
 		},
-
-		"with_equivocations": {
-			voters: []*ed25519.Keypair{
-				ed25519Keyring.Alice().(*ed25519.Keypair),
-				ed25519Keyring.Bob().(*ed25519.Keypair),
-				ed25519Keyring.Charlie().(*ed25519.Keypair),
-				ed25519Keyring.Dave().(*ed25519.Keypair),
-				ed25519Keyring.Ian().(*ed25519.Keypair),
-			},
-			// alice and charlie equivocates
-			// it is a map as it is easy to check
-			whoEquivocates: map[int]struct{}{
-				3: {},
-				4: {},
-			},
-			defineBlockTree: func(t *testing.T, blockState BlockState, _ []*Service) {
-				// this creates a tree with 2 branches starting at depth 2
-				branches := map[uint]int{2: 1}
-				const baseLength = 4
-				state.AddBlocksToStateWithFixedBranches(t, blockState.(*state.BlockState), baseLength, branches)
-			},
-		},
 	}
 
 	for tname, tt := range tests {
@@ -167,163 +163,52 @@ func TestPlayGrandpaRound(t *testing.T) {
 		t.Run(tname, func(t *testing.T) {
 			t.Parallel()
 
-			ctrl := gomock.NewController(t)
-			grandpaServices := make([]*Service, len(tt.voters))
-			grandpaVoters := make([]types.GrandpaVoter, len(tt.voters))
-
-			for idx, kp := range tt.voters {
-				grandpaVoters[idx] = types.GrandpaVoter{
-					Key: *kp.Public().(*ed25519.PublicKey),
-				}
-			}
-
-			for idx := range tt.voters {
-				// gossamer gossips a prevote/precommit message and then waits `subroundInterval` * 4
-				// to issue another prevote/precommit message
-				const subroundInterval = 100 * time.Millisecond
-				ctx, cancel := context.WithCancel(context.Background())
-				defer cancel()
-
-				st := newTestState(t)
-				grandpaServices[idx] = &Service{
-					ctx:          ctx,
-					cancel:       cancel,
-					paused:       atomic.Value{},
-					blockState:   st.Block,
-					grandpaState: st.Grandpa,
-					interval:     subroundInterval,
-					state: &State{
-						round:  1,
-						setID:  0,
-						voters: grandpaVoters,
-					},
-					head:               testGenesisHeader,
-					authority:          true,
-					keypair:            tt.voters[idx],
-					prevotes:           new(sync.Map),
-					precommits:         new(sync.Map),
-					preVotedBlock:      make(map[uint64]*Vote),
-					bestFinalCandidate: make(map[uint64]*Vote),
-					pvEquivocations:    make(map[ed25519.PublicKeyBytes][]*SignedVote),
-					pcEquivocations:    make(map[ed25519.PublicKeyBytes][]*SignedVote),
-				}
-				grandpaServices[idx].paused.Store(false)
-			}
-
-			neighbourServices := make([][]*Service, len(grandpaServices))
-			for idx := range grandpaServices {
-				neighbours := make([]*Service, len(grandpaServices)-1)
-				copy(neighbours, grandpaServices[:idx])
-				copy(neighbours[idx:], grandpaServices[idx+1:])
-				neighbourServices[idx] = neighbours
-			}
-
-			producedCommitMessages := make([]*CommitMessage, len(grandpaServices))
+			grandpaServices, commits := newTestVoterServices(t, tt.voters)
 			for idx, grandpaService := range grandpaServices {
-				idx := idx
-				neighbours := neighbourServices[idx]
-				tt.defineBlockTree(t, grandpaServices[idx].blockState, neighbours)
-
-				// if the service is an equivocator it should send a different vote
-				// into the same round to all its neighbour peers
-				serviceNetworkMock := func(serviceIdx int, neighbours []*Service,
-					equivocateVote *VoteMessage) func(any) {
-					return func(arg0 any) {
-						consensusMessage, ok := arg0.(*network.ConsensusMessage)
-						require.True(t, ok, "expecting *network.ConsensusMessage, got %T", arg0)
-
-						message, err := decodeMessage(consensusMessage)
-						require.NoError(t, err)
-
-						switch msg := message.(type) {
-						case *VoteMessage:
-							for _, neighbour := range neighbours {
-								neighbour.handleVoteMessage(peer.ID(fmt.Sprint(serviceIdx)), msg)
-								if equivocateVote != nil {
-									neighbour.handleVoteMessage(peer.ID(fmt.Sprint(serviceIdx)), equivocateVote)
-								}
-							}
-						case *CommitMessage:
-							producedCommitMessages[serviceIdx] = msg
-						}
-					}
-				}
+				neighbours := make([]*Service, 0, len(grandpaServices)-1)
+				neighbours = append(neighbours, grandpaServices[:idx]...)
+				neighbours = append(neighbours, grandpaServices[idx+1:]...)
+				tt.defineBlockTree(t, grandpaService.blockState.(*state.BlockState), neighbours)
+			}
 
-				// In this test it is not important to assert the arguments
-				// to the telemetry SendMessage mocked func
-				// the TestSendingVotesInRightStage does it properly
-				telemetryMock := NewMockTelemetry(ctrl)
-				grandpaService.telemetry = telemetryMock
-				telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
-
-				// if the voter is an equivocator then we issue a vote
-				// to another block into the same round and set id
-				var equivocatedVoteMessage *VoteMessage
-				_, isEquivocator := tt.whoEquivocates[idx]
-				if isEquivocator {
-					leaves := grandpaService.blockState.(*state.BlockState).Leaves()
-
-					vote, err := NewVoteFromHash(leaves[1], grandpaService.blockState)
+			for _, grandpaService := range grandpaServices {
+				err := grandpaService.Start()
+				require.NoError(t, err)
+				t.Cleanup(func() {
+					err := grandpaService.Stop()
 					require.NoError(t, err)
+				})
+			}
 
-					_, vmsg, err := grandpaService.createSignedVoteAndVoteMessage(
-						vote, prevote)
+			bestBlockHash := grandpaServices[0].blockState.BestBlockHash()
+			require.Eventually(t, func() bool {
+				for _, grandpaService := range grandpaServices {
+					finalised, err := grandpaService.blockState.GetHighestFinalisedHeader()
 					require.NoError(t, err)
-
-					equivocatedVoteMessage = vmsg
+					if finalised.Hash() != bestBlockHash {
+						return false
+					}
 				}
 
-				// The network mock works like a wire between the running
-				// services, and it is not important to assert the arguments
-				// the TestSendingVotesInRightStage does it properly
-				mockNet := NewMockNetwork(ctrl)
-				grandpaService.network = mockNet
-				mockNet.EXPECT().
-					GossipMessage(gomock.Any()).
-					DoAndReturn(serviceNetworkMock(idx, neighbours, equivocatedVoteMessage)).
-					AnyTimes()
-			}
-
-			runfinalisationServices(t, grandpaServices)
-
-			var latestHash common.Hash = grandpaServices[0].head.Hash()
-			for _, grandpaService := range grandpaServices[1:] {
-				serviceFinalizedHash := grandpaService.head.Hash()
-				eql := serviceFinalizedHash == latestHash
-				if !eql {
-					t.Errorf("miss match service finalized hash\n\texpecting %s\n\tgot%s\n",
-						latestHash, serviceFinalizedHash)
+				for _, commitMessage := range commits() {
+					if commitMessage == nil {
+						return false
+					}
 				}
-				latestHash = serviceFinalizedHash
-			}
+				return true
+			}, 10*time.Second, 50*time.Millisecond)
 
-			latestCommit := producedCommitMessages[0]
-			for _, commitMessage := range producedCommitMessages[1:] {
-				require.NotNil(t, commitMessage)
+			producedCommits := commits()
+			latestCommit := producedCommits[0]
+			for _, commitMessage := range producedCommits[1:] {
 				require.GreaterOrEqual(t, len(commitMessage.Precommits), len(tt.voters)/2)
 				require.GreaterOrEqual(t, len(commitMessage.AuthData), len(tt.voters)/2)
 
-				require.Equal(t, latestCommit.Round, commitMessage.Round)
 				require.Equal(t, latestCommit.SetID, commitMessage.SetID)
 				require.Equal(t, latestCommit.Vote, commitMessage.Vote)
 				latestCommit = commitMessage
 			}
-
-			// assert that the services who got an equivocator vote
-			// stored that information in the map properly
-			if len(tt.whoEquivocates) > 0 {
-				for idx, grandpaService := range grandpaServices {
-					// who equivocates does not take itself in to account
-					_, isEquivocator := tt.whoEquivocates[idx]
-					if isEquivocator {
-						require.LessOrEqual(t, len(grandpaService.pvEquivocations), len(tt.whoEquivocates)-1,
-							"%s does not have enough equivocations", grandpaService.publicKeyBytes())
-					} else {
-						require.LessOrEqual(t, len(grandpaService.pvEquivocations), len(tt.whoEquivocates),
-							"%s does not have enough equivocations", grandpaService.publicKeyBytes())
-					}
-				}
-			}
+			require.Equal(t, bestBlockHash, latestCommit.Vote.Hash)
 		})
 	}
 }
@@ -331,437 +216,300 @@ func TestPlayGrandpaRound(t *testing.T) {
 func TestPlayGrandpaRoundMultipleRounds(t *testing.T) {
 	t.Parallel()
 
-	ctrl := gomock.NewController(t)
-
 	ed25519Keyring, err := keystore.NewEd25519Keyring()
 	require.NoError(t, err)
 
-	voters := []*ed25519.Keypair{
+	grandpaServices, _ := newTestVoterServices(t, []*ed25519.Keypair{
 		ed25519Keyring.Alice().(*ed25519.Keypair),
 		ed25519Keyring.Bob().(*ed25519.Keypair),
 		ed25519Keyring.Charlie().(*ed25519.Keypair),
 		ed25519Keyring.Dave().(*ed25519.Keypair),
 		ed25519Keyring.Ian().(*ed25519.Keypair),
+	})
+
+	// the blocks created are the same for every service since
+	// their headers only depend on the parent hash and number
+	growChains := func(length uint) {
+		for _, grandpaService := range grandpaServices {
+			const withBranches = false
+			state.AddBlocksToState(t, grandpaService.blockState.(*state.BlockState), length, withBranches)
+		}
 	}
 
-	grandpaVoters := make([]types.GrandpaVoter, 0, len(voters))
-	for _, kp := range voters {
-		grandpaVoters = append(grandpaVoters, types.GrandpaVoter{
-			Key: *kp.Public().(*ed25519.PublicKey),
+	const baseLength = 4
+	growChains(baseLength)
+
+	for _, grandpaService := range grandpaServices {
+		err := grandpaService.Start()
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			err := grandpaService.Stop()
+			require.NoError(t, err)
 		})
 	}
 
-	grandpaServices := make([]*Service, len(voters))
-	for idx := range voters {
-		const subroundInterval = 100 * time.Millisecond
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-
-		st := newTestState(t)
-		grandpaServices[idx] = &Service{
-			ctx:          ctx,
-			cancel:       cancel,
-			paused:       atomic.Value{},
-			blockState:   st.Block,
-			grandpaState: st.Grandpa,
-			interval:     subroundInterval,
-			state: &State{
-				round:  1,
-				setID:  0,
-				voters: grandpaVoters,
-			},
-			head:               testGenesisHeader,
-			authority:          true,
-			keypair:            voters[idx],
-			preVotedBlock:      make(map[uint64]*Vote),
-			bestFinalCandidate: make(map[uint64]*Vote),
-		}
-		grandpaServices[idx].paused.Store(false)
+	const totalRounds = 5
+	for currentRound := 1; currentRound <= totalRounds; currentRound++ {
+		bestBlockHash := grandpaServices[0].blockState.BestBlockHash()
+		require.Eventually(t, func() bool {
+			for _, grandpaService := range grandpaServices {
+				finalised, err := grandpaService.blockState.GetHighestFinalisedHeader()
+				require.NoError(t, err)
+				if finalised.Hash() != bestBlockHash {
+					return false
+				}
+			}
+			return true
+		}, 10*time.Second, 50*time.Millisecond, "best block not finalised in round %d", currentRound)
 
-		const withBranches = false
-		const baseLength = 4
-		state.AddBlocksToState(t,
-			grandpaServices[idx].blockState.(*state.BlockState),
-			baseLength, withBranches)
+		growChains(1)
 	}
+}
 
-	neighbourServices := make([][]*Service, len(grandpaServices))
-	for idx := range grandpaServices {
-		neighbours := make([]*Service, len(grandpaServices)-1)
-		copy(neighbours, grandpaServices[:idx])
-		copy(neighbours[idx:], grandpaServices[idx+1:])
-		neighbourServices[idx] = neighbours
-	}
+func TestService_Stop_ShouldHaltVoter(t *testing.T) {
+	t.Parallel()
 
-	const totalRounds = 10
-	for currentRound := 1; currentRound <= totalRounds; currentRound++ {
-		for _, grandpaService := range grandpaServices {
-			grandpaService.state.round = uint64(currentRound)
-			grandpaService.prevotes = new(sync.Map)
-			grandpaService.precommits = new(sync.Map)
-			grandpaService.pvEquivocations = make(map[ed25519.PublicKeyBytes][]*SignedVote)
-			grandpaService.pcEquivocations = make(map[ed25519.PublicKeyBytes][]*SignedVote)
-		}
+	ed25519Keyring, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
 
-		// every grandpa service should produce a commit message
-		// indicating that it achieved a finalisation in the round
-		producedCommitMessages := make([]*CommitMessage, len(grandpaServices))
-		for idx, grandpaService := range grandpaServices {
-			idx := idx
-			neighbours := neighbourServices[idx]
+	grandpaServices, _ := newTestVoterServices(t, []*ed25519.Keypair{
+		ed25519Keyring.Alice().(*ed25519.Keypair),
+	})
+	grandpaService := grandpaServices[0]
 
-			serviceNetworkMock := func(serviceIdx int, neighbours []*Service) func(any) {
-				return func(arg0 any) {
-					consensusMessage, ok := arg0.(*network.ConsensusMessage)
-					require.True(t, ok, "expecting *network.ConsensusMessage, got %T", arg0)
+	err = grandpaService.Start()
+	require.NoError(t, err)
 
-					message, err := decodeMessage(consensusMessage)
-					require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		voter, _, _ := grandpaService.currentVoter()
+		return voter != nil
+	}, 5*time.Second, 10*time.Millisecond)
 
-					switch msg := message.(type) {
-					case *VoteMessage:
-						for _, neighbour := range neighbours {
-							neighbour.handleVoteMessage(peer.ID(fmt.Sprint(serviceIdx)), msg)
-						}
-					case *CommitMessage:
-						producedCommitMessages[serviceIdx] = msg
-					}
-				}
-			}
+	// the voter is stopped once the service is stopped
+	err = grandpaService.Stop()
+	require.NoError(t, err)
 
-			// In this test it is not important to assert the arguments
-			// to the telemetry SendMessage mocked func
-			// the TestSendingVotesInRightStage does it properly
-			telemetryMock := NewMockTelemetry(ctrl)
-			grandpaService.telemetry = telemetryMock
-			telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
-
-			// The network mock works like a wire between the running
-			// services, and it is not important to assert the arguments
-			// the TestSendingVotesInRightStage does it properly
-			mockNet := NewMockNetwork(ctrl)
-			grandpaService.network = mockNet
-			mockNet.EXPECT().
-				GossipMessage(gomock.Any()).
-				Do(serviceNetworkMock(idx, neighbours)).
-				AnyTimes()
-		}
+	voter, env, globalIn := grandpaService.currentVoter()
+	require.Nil(t, voter)
+	require.Nil(t, env)
+	require.Nil(t, globalIn)
+}
 
-		// for each grandpa service we should start the finalisation and voting round
-		// engines and waits for them to reach finalisation
-		runfinalisationServices(t, grandpaServices)
-
-		const setID uint64 = 0
-		assertSamefinalisationAndChainGrowth(t, grandpaServices,
-			uint64(currentRound), setID)
-
-		latestCommit := producedCommitMessages[0]
-		for _, commitMessage := range producedCommitMessages[1:] {
-			require.NotNil(t, commitMessage)
-			require.GreaterOrEqual(t, len(commitMessage.Precommits), len(voters)/2)
-			require.GreaterOrEqual(t, len(commitMessage.AuthData), len(voters)/2)
-
-			require.Equal(t, commitMessage.Round, uint64(currentRound))
-			require.Equal(t, latestCommit.Round, commitMessage.Round)
-			require.Equal(t, latestCommit.SetID, commitMessage.SetID)
-			require.Equal(t, latestCommit.Vote, commitMessage.Vote)
-			latestCommit = commitMessage
+// requireFinalised waits until every service finalised the block with the given hash.
+func requireFinalised(t *testing.T, services []*Service, blockHash common.Hash) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		for _, service := range services {
+			finalised, err := service.blockState.GetHighestFinalisedHeader()
+			require.NoError(t, err)
+			if finalised.Hash() != blockHash {
+				return false
+			}
 		}
-	}
+		return true
+	}, 10*time.Second, 50*time.Millisecond)
 }
 
-// runfinalisationServices is designed to handle many grandpa services and starts, for each service,
-// the finalisation engine and the voting round engine which will take care of reach finalisation
-func runfinalisationServices(t *testing.T, grandpaServices []*Service) {
+// newTestLaggingVoter returns the services of four voters where only the first three
+// are started, which is enough to finalise blocks. The fourth service is started once
+// the best block is finalised so its voter is left behind in the first round.
+func newTestLaggingVoter(t *testing.T) (
+	services []*Service, lagging *Service, commits func() []*CommitMessage) {
 	t.Helper()
 
-	finalisationHandlers := make([]*finalisationHandler, len(grandpaServices))
-	for idx, grandpaService := range grandpaServices {
-		handler := newFinalisationHandler(grandpaService)
-		handler.firstRun = false
-		finalisationHandlers[idx] = handler
-	}
-
-	handlersWg := new(sync.WaitGroup)
-	handlersWg.Add(len(finalisationHandlers))
+	ed25519Keyring, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
 
-	for _, handler := range finalisationHandlers {
-		go func(t *testing.T, handler *finalisationHandler) {
-			defer handlersWg.Done()
+	services, commits = newTestVoterServices(t, []*ed25519.Keypair{
+		ed25519Keyring.Alice().(*ed25519.Keypair),
+		ed25519Keyring.Bob().(*ed25519.Keypair),
+		ed25519Keyring.Charlie().(*ed25519.Keypair),
+		ed25519Keyring.Dave().(*ed25519.Keypair),
+	})
 
-			// passing the ready channel as nil since the first run is false
-			// and we ensure the method fh.newServices() is being called
-			err := handler.runEphemeralServices(nil)
-			assert.NoError(t, err)
-		}(t, handler)
+	for _, service := range services {
+		const withBranches = false
+		const baseLength = 4
+		state.AddBlocksToState(t, service.blockState.(*state.BlockState), baseLength, withBranches)
 	}
 
-	handlersWg.Wait()
-}
-
-// assertChainGrowth ensure that each service reach the same finalisation result
-// and that the result belongs to the same chain as the previously finalized block
-func assertSamefinalisationAndChainGrowth(t *testing.T, services []*Service, currentRount, setID uint64) {
-	finalizedHeaderCurrentRound := make([]*types.Header, len(services))
-	for idx, grandpaService := range services {
-		finalizedHeader, err := grandpaService.blockState.GetFinalisedHeader(
-			currentRount, setID)
+	startService := func(service *Service) {
+		err := service.Start()
 		require.NoError(t, err)
-		require.NotNil(t, finalizedHeader, "round %d does not contain an header", currentRount)
-		finalizedHeaderCurrentRound[idx] = finalizedHeader
-	}
-
-	var latestFinalized common.Hash = finalizedHeaderCurrentRound[0].Hash()
-	for _, finalizedHead := range finalizedHeaderCurrentRound[1:] {
-		eq := finalizedHead.Hash() == latestFinalized
-		if !eq {
-			t.Errorf("miss match finalized hash\n\texpected %s\n\tgot%s\n",
-				latestFinalized, finalizedHead)
-		}
-		latestFinalized = finalizedHead.Hash()
+		t.Cleanup(func() {
+			err := service.Stop()
+			require.NoError(t, err)
+		})
 	}
 
-	previousRound := currentRount - 1
-	// considering that we start from round 1
-	// there is nothing to compare before
-	if previousRound == 0 {
-		return
+	online, lagging := services[:3], services[3]
+	for _, service := range online {
+		startService(service)
 	}
+	requireFinalised(t, online, online[0].blockState.BestBlockHash())
 
-	for _, grandpaService := range services {
-		previouslyFinalized, err := grandpaService.blockState.
-			GetFinalisedHeader(previousRound, setID)
-		require.NoError(t, err)
+	startService(lagging)
+	require.Eventually(t, func() bool {
+		voter, _, _ := lagging.currentVoter()
+		return voter != nil
+	}, 5*time.Second, 10*time.Millisecond)
 
-		descendant, err := grandpaService.blockState.IsDescendantOf(
-			previouslyFinalized.Hash(), latestFinalized)
-		require.NoError(t, err)
-		require.True(t, descendant)
-	}
+	return online, lagging, commits
 }
 
-func TestSendingVotesInRightStage(t *testing.T) {
+func TestVoter_FinalisesBlockFromCommit(t *testing.T) {
 	t.Parallel()
 
-	ed25519Keyring, err := keystore.NewEd25519Keyring()
+	online, lagging, commits := newTestLaggingVoter(t)
+
+	var commitMessage *CommitMessage
+	require.Eventually(t, func() bool {
+		commitMessage = commits()[0]
+		return commitMessage != nil
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// the lagging voter cannot complete its round without the votes
+	// of the other voters, the block is finalised by the commit
+	_, err := lagging.messageHandler.handleMessage(peer.ID("0"), commitMessage)
 	require.NoError(t, err)
 
-	bobAuthority := ed25519Keyring.Bob().(*ed25519.Keypair)
-	votersPublicKeys := []*ed25519.Keypair{
-		ed25519Keyring.Alice().(*ed25519.Keypair),
-		bobAuthority,
-		ed25519Keyring.Charlie().(*ed25519.Keypair),
-		ed25519Keyring.Dave().(*ed25519.Keypair),
-	}
+	requireFinalised(t, []*Service{lagging}, commitMessage.Vote.Hash)
 
-	grandpaVoters := make([]types.GrandpaVoter, len(votersPublicKeys))
-	for idx, pk := range votersPublicKeys {
-		grandpaVoters[idx] = types.GrandpaVoter{
-			Key: *pk.Public().(*ed25519.PublicKey),
-		}
-	}
+	justification, err := lagging.blockState.GetJustification(commitMessage.Vote.Hash)
+	require.NoError(t, err)
+	var decoded Justification
+	err = scale.NewDecoder(bytes.NewReader(justification)).Decode(&decoded)
+	require.NoError(t, err)
+	require.Equal(t, commitMessage.Round, decoded.Round)
+	require.Equal(t, commitMessage.Vote.Hash, decoded.Commit.Hash)
+	require.Len(t, decoded.Commit.Precommits, len(commitMessage.Precommits))
 
-	ctrl := gomock.NewController(t)
-	mockedGrandpaState := NewMockGrandpaState(ctrl)
-	mockedGrandpaState.EXPECT().
-		NextGrandpaAuthorityChange(testGenesisHeader.Hash(), testGenesisHeader.Number).
-		Return(uint(0), state.ErrNoNextAuthorityChange).
-		AnyTimes()
-	mockedGrandpaState.EXPECT().
-		SetPrevotes(uint64(1), uint64(0), gomock.AssignableToTypeOf([]types.GrandpaSignedVote{})).
-		Return(nil)
-	mockedGrandpaState.EXPECT().
-		SetPrecommits(uint64(1), uint64(0), gomock.AssignableToTypeOf([]types.GrandpaSignedVote{})).
-		Return(nil)
-	mockedGrandpaState.EXPECT().
-		SetLatestRound(uint64(1)).
-		Return(nil)
-	mockedGrandpaState.EXPECT().
-		GetPrecommits(uint64(1), uint64(0)).
-		Return([]types.GrandpaSignedVote{}, nil)
-
-	mockedState := NewMockBlockState(ctrl)
-	mockedState.EXPECT().
-		GenesisHash().
-		Return(testGenesisHeader.Hash()).
-		Times(2)
-	// since the next 3 function has been called based on the amount of time we wait until we get enough
-	// prevotes is hard to define a corret amount of times this function shoud be called
-	mockedState.EXPECT().
-		HasFinalisedBlock(uint64(1), uint64(0)).
-		Return(false, nil).
-		AnyTimes()
-	mockedState.EXPECT().
-		HasHeader(testGenesisHeader.Hash()).
-		Return(true, nil).
-		Times(4)
-	mockedState.EXPECT().
-		GetHighestRoundAndSetID().
-		Return(uint64(0), uint64(0), nil).
-		AnyTimes()
-	mockedState.EXPECT().
-		IsDescendantOf(testGenesisHeader.Hash(), testGenesisHeader.Hash()).
-		Return(true, nil).
-		AnyTimes()
-
-	mockedState.EXPECT().
-		BestBlockHeader().
-		Return(testGenesisHeader, nil).
-		Times(2)
-
-	// we cannot assert the bytes since some votes is defined while playing grandpa round
-	mockedState.EXPECT().
-		SetJustification(testGenesisHeader.Hash(), gomock.AssignableToTypeOf([]byte{})).
-		Return(nil)
-	mockedState.EXPECT().
-		GetHeader(testGenesisHeader.Hash()).
-		Return(testGenesisHeader, nil)
-	mockedState.EXPECT().
-		SetFinalisedHash(testGenesisHeader.Hash(), uint64(1), uint64(0)).
-		Return(nil)
+	require.Equal(t, online[0].blockState.BestBlockHash(), commitMessage.Vote.Hash)
+}
 
-	expectedFinalizedTelemetryMessage := telemetry.NewAfgFinalizedBlocksUpTo(
-		testGenesisHeader.Hash(),
-		fmt.Sprint(testGenesisHeader.Number),
-	)
-	expectedAlicePrevoteTelemetryMessage := telemetry.NewAfgReceivedPrevote(
-		testGenesisHeader.Hash(),
-		fmt.Sprint(testGenesisHeader.Number),
-		grandpaVoters[0].PublicKeyBytes().String(),
-	)
-	expectedCharliePrevoteTelemetryMessage := telemetry.NewAfgReceivedPrevote(
-		testGenesisHeader.Hash(),
-		fmt.Sprint(testGenesisHeader.Number),
-		grandpaVoters[2].PublicKeyBytes().String(),
-	)
-	expectedAlicePrecommitTelemetryMessage := telemetry.NewAfgReceivedPrecommit(
-		testGenesisHeader.Hash(),
-		fmt.Sprint(testGenesisHeader.Number),
-		grandpaVoters[0].PublicKeyBytes().String(),
-	)
-	expectedCharliePrecommitTelemetryMessage := telemetry.NewAfgReceivedPrecommit(
-		testGenesisHeader.Hash(),
-		fmt.Sprint(testGenesisHeader.Number),
-		grandpaVoters[2].PublicKeyBytes().String(),
-	)
-
-	mockedTelemetry := NewMockTelemetry(ctrl)
-	mockedTelemetry.EXPECT().
-		SendMessage(expectedAlicePrevoteTelemetryMessage)
-	mockedTelemetry.EXPECT().
-		SendMessage(expectedCharliePrevoteTelemetryMessage)
-	mockedTelemetry.EXPECT().
-		SendMessage(expectedAlicePrecommitTelemetryMessage)
-	mockedTelemetry.EXPECT().
-		SendMessage(expectedCharliePrecommitTelemetryMessage)
-	mockedTelemetry.EXPECT().
-		SendMessage(expectedFinalizedTelemetryMessage)
-
-	mockedNet := NewMockNetwork(ctrl)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// gossamer gossips a prevote/precommit message and then waits `subroundInterval` * 4
-	// to issue another prevote/precommit message
-	const subroundInterval = time.Second
-	grandpa := &Service{
-		ctx:          ctx,
-		cancel:       cancel,
-		paused:       atomic.Value{},
-		network:      mockedNet,
-		blockState:   mockedState,
-		grandpaState: mockedGrandpaState,
-		interval:     subroundInterval,
-		state: &State{
-			round:  1,
-			setID:  0,
-			voters: grandpaVoters,
-		},
-		head:               testGenesisHeader,
-		authority:          true,
-		keypair:            bobAuthority,
-		prevotes:           new(sync.Map),
-		precommits:         new(sync.Map),
-		preVotedBlock:      make(map[uint64]*Vote),
-		bestFinalCandidate: make(map[uint64]*Vote),
-		telemetry:          mockedTelemetry,
+func TestVoter_CatchUp(t *testing.T) {
+	t.Parallel()
+
+	online, lagging, _ := newTestLaggingVoter(t)
+	services := append(append([]*Service{}, online...), lagging)
+
+	// the blocks created are the same for every service since
+	// their headers only depend on the parent hash and number
+	growChains := func() {
+		for _, service := range services {
+			const withBranches = false
+			state.AddBlocksToState(t, service.blockState.(*state.BlockState), 1, withBranches)
+		}
 	}
-	grandpa.paused.Store(false)
 
-	expectedVote := NewVote(testGenesisHeader.Hash(), uint32(testGenesisHeader.Number))
-	_, expectedPrimaryProposal, err := grandpa.createSignedVoteAndVoteMessage(expectedVote, primaryProposal)
-	require.NoError(t, err)
+	// finalise a block in a round after the first one
+	growChains()
+	requireFinalised(t, online, online[0].blockState.BestBlockHash())
 
-	primaryProposal, err := expectedPrimaryProposal.ToConsensusMessage()
+	round, setID, err := online[0].blockState.GetHighestRoundAndSetID()
 	require.NoError(t, err)
-	mockedNet.EXPECT().
-		GossipMessage(primaryProposal)
+	require.Greater(t, round, lagging.GetRound())
 
-	// first of all we should determine our precommit based on our chain view
-	_, expectedPrevoteMessage, err := grandpa.createSignedVoteAndVoteMessage(expectedVote, prevote)
-	require.NoError(t, err)
+	// the lagging voter requests a catch up to the last round finalising a block
+	var response NotificationsMessage
+	require.Eventually(t, func() bool {
+		response, err = online[0].messageHandler.handleMessage(peer.ID("3"), newCatchUpRequest(round, setID))
+		return err == nil
+	}, 10*time.Second, 50*time.Millisecond)
 
-	pv, err := expectedPrevoteMessage.ToConsensusMessage()
+	catchUpResponse, err := decodeMessage(response.(*ConsensusMessage))
 	require.NoError(t, err)
-	mockedNet.EXPECT().
-		GossipMessage(pv).
-		AnyTimes()
-
-	// after receive enough prevotes our node should define a precommit message and send it
-	_, expectedPrecommitMessage, err := grandpa.createSignedVoteAndVoteMessage(expectedVote, precommit)
+	_, err = lagging.messageHandler.handleMessage(peer.ID("0"), catchUpResponse)
 	require.NoError(t, err)
 
-	pc, err := expectedPrecommitMessage.ToConsensusMessage()
-	require.NoError(t, err)
-	mockedNet.EXPECT().
-		GossipMessage(pc).
-		AnyTimes()
+	require.Eventually(t, func() bool {
+		return lagging.GetRound() > round
+	}, 10*time.Second, 50*time.Millisecond)
 
-	wg := sync.WaitGroup{}
-	wg.Add(1)
+	// the lagging voter votes with the other voters again
+	growChains()
+	requireFinalised(t, services, online[0].blockState.BestBlockHash())
+}
 
-	go func() {
-		defer wg.Done()
-		finalisationHandler := newFinalisationHandler(grandpa)
-		finalisationHandler.firstRun = false
+func TestVoter_RestartSendsVotesCast(t *testing.T) {
+	t.Parallel()
 
-		// passing the ready channel as nil since the first run is false
-		// and we ensure the method fh.newServices() is being called
-		err := finalisationHandler.runEphemeralServices(nil)
-		require.NoError(t, err)
-	}()
+	ed25519Keyring, err := keystore.NewEd25519Keyring()
+	require.NoError(t, err)
+	keypair := ed25519Keyring.Alice().(*ed25519.Keypair)
 
-	time.Sleep(grandpa.interval * 5)
+	ctrl := gomock.NewController(t)
+	st := newTestState(t)
 
-	// given that we are BOB and we already had predetermined our prevote in a set
-	// of 4 authorities (ALICE, BOB, CHARLIE and DAVE) then we only need 2 more prevotes
-	_, aliceVoteMessage := createAndSignVoteMessage(t, votersPublicKeys[0], 1, 0, expectedVote, prevote)
-	grandpa.handleVoteMessage(peer.ID("alice"), aliceVoteMessage)
+	const withBranches = false
+	const baseLength = 4
+	state.AddBlocksToState(t, st.Block, baseLength, withBranches)
 
-	_, charlieVoteMessage := createAndSignVoteMessage(t, votersPublicKeys[2], 1, 0, expectedVote, prevote)
+	// the prevote cast in the first round before the restart
+	genesisHash := st.Block.GenesisHash()
+	votedBefore := NewVote(genesisHash, 0)
+	err = st.Grandpa.SetHasVoted(1, 0, types.GrandpaHasVoted{Prevote: votedBefore})
 	require.NoError(t, err)
-	grandpa.handleVoteMessage(peer.ID("charlie"), charlieVoteMessage)
 
-	// given that we are BOB and we already had predetermined the precommit given the prevotes
-	// we only need 2 more precommit messages
-	_, alicePrecommitMessage := createAndSignVoteMessage(t, votersPublicKeys[0], 1, 0, expectedVote, precommit)
+	telemetryMock := NewMockTelemetry(ctrl)
+	telemetryMock.EXPECT().SendMessage(gomock.Any()).AnyTimes()
+
+	prevotes := make(chan *VoteMessage, 16)
+	networkMock := NewMockNetwork(ctrl)
+	networkMock.EXPECT().RegisterNotificationsProtocol(gomock.Any(), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+	networkMock.EXPECT().SendMessage(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	networkMock.EXPECT().GossipMessage(gomock.Any()).DoAndReturn(func(msg NotificationsMessage) {
+		message, err := decodeMessage(msg.(*ConsensusMessage))
+		require.NoError(t, err)
+
+		voteMessage, ok := message.(*VoteMessage)
+		if ok && voteMessage.Message.Stage == prevote {
+			select {
+			case prevotes <- voteMessage:
+			default:
+			}
+		}
+	}).AnyTimes()
+
+	service, err := NewService(&Config{
+		BlockState:   st.Block,
+		GrandpaState: st.Grandpa,
+		Voters:       []Voter{{Key: *keypair.Public().(*ed25519.PublicKey), ID: 0}},
+		Keypair:      keypair,
+		Authority:    true,
+		Network:      networkMock,
+		Interval:     100 * time.Millisecond,
+		Telemetry:    telemetryMock,
+	})
 	require.NoError(t, err)
-	grandpa.handleVoteMessage(peer.ID("alice"), alicePrecommitMessage)
 
-	_, charliePrecommitMessage := createAndSignVoteMessage(t, votersPublicKeys[2], 1, 0, expectedVote, precommit)
+	err = service.Start()
 	require.NoError(t, err)
-	grandpa.handleVoteMessage(peer.ID("charlie"), charliePrecommitMessage)
+	t.Cleanup(func() {
+		err := service.Stop()
+		require.NoError(t, err)
+	})
 
-	commitMessage := &CommitMessage{
-		Round:      1,
-		Vote:       *NewVoteFromHeader(testGenesisHeader),
-		Precommits: []types.GrandpaVote{},
-		AuthData:   []AuthData{},
+	// the voter sends the prevote cast before the restart instead of voting the best block
+	firstPrevote := <-prevotes
+	require.Equal(t, uint64(1), firstPrevote.Round)
+	require.Equal(t, votedBefore.Hash, firstPrevote.Message.BlockHash)
+
+	// no other prevote is cast in the first round
+	for nextPrevote := range prevotes {
+		if nextPrevote.Round > 1 {
+			break
+		}
+		require.Equal(t, votedBefore.Hash, nextPrevote.Message.BlockHash)
 	}
-	expectedGossipCommitMessage, err := commitMessage.ToConsensusMessage()
-	require.NoError(t, err)
-	mockedNet.EXPECT().
-		GossipMessage(expectedGossipCommitMessage)
 
-	wg.Wait()
+	// the best block is finalised in the following rounds
+	requireFinalised(t, []*Service{service}, st.Block.BestBlockHash())
+
+	hasVoted, err := st.Grandpa.GetHasVoted(1, 0)
+	require.NoError(t, err)
+	require.Equal(t, votedBefore, hasVoted.Prevote)
 }
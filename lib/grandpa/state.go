@@ -11,6 +11,7 @@
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 )
 
 // BlockState is the interface required by GRANDPA into the block state
@@ -38,6 +39,7 @@ type BlockState interface {
 	BestBlockHash() common.Hash
 	GetRuntime(blockHash common.Hash) (instance runtime.Instance, err error)
 	GetJustification(hash common.Hash) ([]byte, error)
+	Leaves() []common.Hash
 }
 
 // GrandpaState is the interface required by grandpa into the grandpa state
@@ -51,10 +53,20 @@ type GrandpaState interface {
 	SetPrecommits(round, setID uint64, data []SignedVote) error
 	GetPrevotes(round, setID uint64) ([]SignedVote, error)
 	GetPrecommits(round, setID uint64) ([]SignedVote, error)
+	SetHasVoted(round, setID uint64, hasVoted types.GrandpaHasVoted) error
+	GetHasVoted(round, setID uint64) (types.GrandpaHasVoted, error)
 	NextGrandpaAuthorityChange(bestBlockHash common.Hash, bestBlockNumber uint) (blockHeight uint, err error)
+	NextGrandpaPause(bestBlockHash common.Hash, bestBlockNumber uint) (blockHeight uint, err error)
+	GetNextPause() (blockNumber uint, err error)
+	GetNextResume() (blockNumber uint, err error)
 	GetAuthoritiesChangesFromBlock(blockNumber uint) ([]uint, error)
 }
 
+// StorageState is the interface required by GRANDPA into the storage state
+type StorageState interface {
+	TrieState(root *common.Hash) (*rtstorage.TrieState, error)
+}
+
 // Network is the interface required by GRANDPA for the network
 type Network interface {
 	GossipMessage(msg network.NotificationsMessage)
@@ -51,11 +51,16 @@ func (s *Service) sendTelemetryVoteMessage(vm *VoteMessage) {
 }
 
 func (s *Service) createSignedVoteAndVoteMessage(vote *Vote, stage Subround) (*SignedVote, *VoteMessage, error) {
+	return s.signVote(vote, stage, s.GetRound(), s.GetSetID())
+}
+
+// signVote signs the vote for the given stage, round and set ID.
+func (s *Service) signVote(vote *Vote, stage Subround, round, setID uint64) (*SignedVote, *VoteMessage, error) {
 	msg, err := scale.Marshal(FullVote{
 		Stage: stage,
 		Vote:  *vote,
-		Round: s.state.round,
-		SetID: s.state.setID,
+		Round: round,
+		SetID: setID,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -82,15 +87,15 @@ func (s *Service) createSignedVoteAndVoteMessage(vote *Vote, stage Subround) (*S
 	}
 
 	vm := &VoteMessage{
-		Round:   s.state.round,
-		SetID:   s.state.setID,
+		Round:   round,
+		SetID:   setID,
 		Message: *sm,
 	}
 
 	return pc, vm, nil
 }
 
-// validateVoteMessage validates a VoteMessage and adds it to the current votes
+// validateVoteMessage validates a VoteMessage
 // it returns the resulting vote if validated, error otherwise
 func (s *Service) validateVoteMessage(from peer.ID, m *VoteMessage) (*Vote, error) {
 	// make sure round does not increment while VoteMessage is being validated
@@ -134,35 +139,7 @@ func (s *Service) validateVoteMessage(from peer.ID, m *VoteMessage) (*Vote, erro
 			errRoundOutOfBounds, m.Round, minRoundAccepted, maxRoundAccepted)
 	}
 
-	if m.Round < s.state.round {
-		// message round is lagging by 1
-		// peer doesn't know round was finalised, send out another commit message
-		header, err := s.blockState.GetFinalisedHeader(m.Round, m.SetID)
-		if err != nil {
-			return nil, fmt.Errorf("getting finalised header: %w", err)
-		}
-
-		// TODO: should we use `m.SetID` or `s.state.setID`?
-		cm, err := s.newCommitMessage(header, m.Round, s.state.setID)
-		if err != nil {
-			return nil, fmt.Errorf("creating commit message: %w", err)
-		}
-
-		// send finalised block from previous round to network
-		msg, err := cm.ToConsensusMessage()
-		if err != nil {
-			return nil, fmt.Errorf("converting commit message to consensus message: %w", err)
-		}
-
-		if err = s.network.SendMessage(from, msg); err != nil {
-			logger.Warnf("failed to send CommitMessage: %s", err)
-		}
-
-		// TODO: get justification if your round is lower, or just do catch-up? (#1815)
-		return nil, fmt.Errorf("%w: received round %d but state round is %d",
-			errRoundsMismatch, m.Round, s.state.round)
-	} else if m.Round > s.state.round {
-
+	if m.Round > s.state.round {
 		// Message round is higher by 1 than the round of our state,
 		// we may be lagging behind, so store the message in the tracker
 		// for processing later in the coming few milliseconds.
@@ -171,8 +148,7 @@ func (s *Service) validateVoteMessage(from peer.ID, m *VoteMessage) (*Vote, erro
 			errRoundsMismatch, m.Round, s.state.round)
 	}
 
-	// check for equivocation ie. multiple votes within one subround
-	voter, err := s.state.pubkeyToVoter(pk)
+	_, err = s.state.pubkeyToVoter(pk)
 	if err != nil {
 		return nil, fmt.Errorf("transforming public key into a voter: %w", err)
 	}
@@ -196,86 +172,12 @@ func (s *Service) validateVoteMessage(from peer.ID, m *VoteMessage) (*Vote, erro
 		return nil, fmt.Errorf("validating vote: %w", err)
 	}
 
-	just := &SignedVote{
-		Vote:        *vote,
-		Signature:   m.Message.Signature,
-		AuthorityID: pk.AsBytes(),
-	}
-
-	err = s.checkAndReportEquivocation(voter, just, m.Message.Stage)
-	if err != nil {
-		return nil, fmt.Errorf("checking for equivocation: %w", err)
-	}
-
-	switch m.Message.Stage {
-	case prevote, primaryProposal:
-		s.prevotes.Store(pk.AsBytes(), just)
-	case precommit:
-		s.precommits.Store(pk.AsBytes(), just)
-	}
-
 	return vote, nil
 }
 
-// checkAndReportEquivocation checks if the vote is an equivocatory vote.
-// If it is an equivocatory vote, the error `ErrEquivocation` is returned, the service's votes and
-// equivocations are updated and the equivocation is reported to the runtime.
-func (s *Service) checkAndReportEquivocation(voter *Voter, vote *SignedVote, stage Subround) error {
-	v := voter.Key.AsBytes()
-
-	// save justification, since equivocatory vote may still be used in justification
-	var eq map[ed25519.PublicKeyBytes][]*SignedVote
-
-	switch stage {
-	case prevote, primaryProposal:
-		eq = s.pvEquivocations
-	case precommit:
-		eq = s.pcEquivocations
-	}
-
-	s.mapLock.Lock()
-	defer s.mapLock.Unlock()
-
-	_, has := eq[v]
-	if has {
-		// if the voter has already equivocated, every vote in that round is an equivocatory vote
-		eq[v] = append(eq[v], vote)
-		return fmt.Errorf("%w: voter %s",
-			ErrEquivocation, v)
-	}
-
-	existingVote, has := s.loadVote(v, stage)
-	if !has {
-		return nil
-	}
-
-	if has && existingVote.Vote.Hash != vote.Vote.Hash {
-		// the voter has already voted, all their votes are now equivocatory
-		eq[v] = []*SignedVote{existingVote, vote}
-		s.deleteVote(v, stage)
-
-		err := s.reportEquivocation(stage, existingVote, vote)
-		if err != nil {
-			logger.Errorf("reporting equivocation: %s", err)
-		}
-		return fmt.Errorf("%w: voter %s has existing vote %s and new vote %s",
-			ErrEquivocation, v, existingVote.Vote.Hash, vote.Vote.Hash)
-	}
-
-	return nil
-}
-
-func (s *Service) reportEquivocation(stage Subround, existingVote *SignedVote, currentVote *SignedVote) error {
-	setID, err := s.grandpaState.GetCurrentSetID()
-	if err != nil {
-		return fmt.Errorf("getting authority set id: %w", err)
-	}
-
-	round, err := s.grandpaState.GetLatestRound()
-	if err != nil {
-		return fmt.Errorf("getting latest round: %w", err)
-	}
-
+// reportEquivocation reports the equivocation of a voter in the given round and set ID to the runtime.
+func (s *Service) reportEquivocation(round, setID uint64, stage Subround,
+	existingVote *SignedVote, currentVote *SignedVote) error {
 	pubKey := existingVote.AuthorityID
 
 	bestBlockHash := s.blockState.BestBlockHash()
@@ -284,6 +186,13 @@ func (s *Service) reportEquivocation(stage Subround, existingVote *SignedVote, c
 		return fmt.Errorf("getting runtime: %w", err)
 	}
 
+	trieState, err := s.storageState.TrieState(nil)
+	if err != nil {
+		return fmt.Errorf("getting best block trie state: %w", err)
+	}
+
+	runtime.SetContextStorage(trieState)
+
 	opaqueKeyOwnershipProof, err := runtime.GrandpaGenerateKeyOwnershipProof(setID, pubKey)
 	if err != nil {
 		return fmt.Errorf("getting key ownership proof: %w", err)
@@ -345,8 +254,13 @@ func (s *Service) validateVote(v *Vote) error {
 		return ErrBlockDoesNotExist
 	}
 
+	highestFinalised, err := s.blockState.GetHighestFinalisedHeader()
+	if err != nil {
+		return fmt.Errorf("getting highest finalised header: %w", err)
+	}
+
 	// check if the block is an eventual descendant of a previously finalised block
-	isDescendant, err := s.blockState.IsDescendantOf(s.head.Hash(), v.Hash)
+	isDescendant, err := s.blockState.IsDescendantOf(highestFinalised.Hash(), v.Hash)
 	if err != nil {
 		return err
 	}
@@ -16,130 +16,6 @@
 	"github.com/stretchr/testify/require"
 )
 
-func TestCheckForEquivocation_NoEquivocation(t *testing.T) {
-	t.Parallel()
-	st := newTestState(t)
-	net := newTestNetwork(t)
-
-	cfg := &Config{
-		BlockState:   st.Block,
-		GrandpaState: st.Grandpa,
-		Network:      net,
-		Interval:     time.Second,
-	}
-
-	gs, err := NewService(cfg)
-	require.NoError(t, err)
-	state.AddBlocksToState(t, st.Block, 3, false)
-
-	h, err := st.Block.BestBlockHeader()
-	require.NoError(t, err)
-
-	vote := NewVoteFromHeader(h)
-	require.NoError(t, err)
-
-	for _, v := range newTestVoters(t) {
-		err = gs.checkAndReportEquivocation(&v, &SignedVote{
-			Vote: *vote,
-		}, prevote)
-		require.NoError(t, err)
-	}
-}
-
-func TestCheckForEquivocation_WithEquivocation(t *testing.T) {
-	t.Parallel()
-	st := newTestState(t)
-	net := newTestNetwork(t)
-
-	cfg := &Config{
-		BlockState:   st.Block,
-		GrandpaState: st.Grandpa,
-		Network:      net,
-		Interval:     time.Second,
-	}
-
-	gs, err := NewService(cfg)
-	require.NoError(t, err)
-
-	branches := map[uint]int{6: 1}
-	state.AddBlocksToStateWithFixedBranches(t, st.Block, 8, branches)
-	leaves := gs.blockState.(*state.BlockState).Leaves()
-
-	vote1, err := NewVoteFromHash(leaves[0], st.Block)
-	require.NoError(t, err)
-
-	voters := newTestVoters(t)
-	voter := voters[0]
-
-	gs.prevotes.Store(voter.Key.AsBytes(), &SignedVote{
-		Vote: *vote1,
-	})
-
-	vote2, err := NewVoteFromHash(leaves[1], st.Block)
-	require.NoError(t, err)
-
-	err = gs.checkAndReportEquivocation(&voter, &SignedVote{
-		Vote: *vote2,
-	}, prevote)
-	require.ErrorIs(t, err, ErrEquivocation)
-
-	require.Equal(t, 0, gs.lenVotes(prevote))
-	require.Equal(t, 1, len(gs.pvEquivocations))
-	require.Equal(t, 2, len(gs.pvEquivocations[voter.Key.AsBytes()]))
-}
-
-func TestCheckForEquivocation_WithExistingEquivocation(t *testing.T) {
-	t.Parallel()
-	st := newTestState(t)
-	net := newTestNetwork(t)
-
-	cfg := &Config{
-		BlockState:   st.Block,
-		GrandpaState: st.Grandpa,
-		Network:      net,
-		Interval:     time.Second,
-	}
-
-	gs, err := NewService(cfg)
-	require.NoError(t, err)
-
-	branches := map[uint]int{6: 1}
-	state.AddBlocksToStateWithFixedBranches(t, st.Block, 8, branches)
-	leaves := gs.blockState.(*state.BlockState).Leaves()
-
-	vote1, err := NewVoteFromHash(leaves[1], gs.blockState)
-	require.NoError(t, err)
-
-	voters := newTestVoters(t)
-	voter := voters[0]
-
-	gs.prevotes.Store(voter.Key.AsBytes(), &SignedVote{
-		Vote: *vote1,
-	})
-
-	vote2, err := NewVoteFromHash(leaves[0], gs.blockState)
-	require.NoError(t, err)
-
-	err = gs.checkAndReportEquivocation(&voter, &SignedVote{
-		Vote: *vote2,
-	}, prevote)
-	require.ErrorIs(t, err, ErrEquivocation)
-
-	require.Equal(t, 0, gs.lenVotes(prevote))
-	require.Equal(t, 1, len(gs.pvEquivocations))
-
-	vote3 := vote1
-
-	err = gs.checkAndReportEquivocation(&voter, &SignedVote{
-		Vote: *vote3,
-	}, prevote)
-	require.ErrorIs(t, err, ErrEquivocation)
-
-	require.Equal(t, 0, gs.lenVotes(prevote))
-	require.Equal(t, 1, len(gs.pvEquivocations))
-	require.Equal(t, 3, len(gs.pvEquivocations[voter.Key.AsBytes()]))
-}
-
 func TestValidateMessage_Valid(t *testing.T) {
 	t.Parallel()
 	st := newTestState(t)
@@ -151,6 +27,7 @@ func TestValidateMessage_Valid(t *testing.T) {
 	cfg := &Config{
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Voters:       newTestVoters(t),
 		Network:      net,
 		Interval:     time.Second,
@@ -184,6 +61,7 @@ func TestValidateMessage_InvalidSignature(t *testing.T) {
 	cfg := &Config{
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Network:      net,
 		Interval:     time.Second,
 	}
@@ -219,6 +97,7 @@ func TestValidateMessage_SetIDMismatch(t *testing.T) {
 	cfg := &Config{
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Network:      net,
 		Interval:     time.Second,
 	}
@@ -241,50 +120,6 @@ func TestValidateMessage_SetIDMismatch(t *testing.T) {
 	require.Equal(t, err, ErrSetIDMismatch)
 }
 
-func TestValidateMessage_Equivocation(t *testing.T) {
-	t.Parallel()
-	st := newTestState(t)
-	net := newTestNetwork(t)
-
-	kr, err := keystore.NewEd25519Keyring()
-	require.NoError(t, err)
-
-	cfg := &Config{
-		BlockState:   st.Block,
-		GrandpaState: st.Grandpa,
-		Voters:       newTestVoters(t),
-		Network:      net,
-		Interval:     time.Second,
-	}
-
-	gs, err := NewService(cfg)
-	require.NoError(t, err)
-
-	branches := map[uint]int{6: 1}
-	state.AddBlocksToStateWithFixedBranches(t, st.Block, 8, branches)
-	leaves := gs.blockState.(*state.BlockState).Leaves()
-
-	voteA, err := NewVoteFromHash(leaves[0], st.Block)
-	require.NoError(t, err)
-	voteB, err := NewVoteFromHash(leaves[1], st.Block)
-	require.NoError(t, err)
-
-	voters := newTestVoters(t)
-	voter := voters[0]
-
-	gs.prevotes.Store(voter.Key.AsBytes(), &SignedVote{
-		Vote: *voteA,
-	})
-
-	gs.keypair = kr.Alice().(*ed25519.Keypair)
-	_, msg, err := gs.createSignedVoteAndVoteMessage(voteB, prevote)
-	require.NoError(t, err)
-	gs.keypair = kr.Bob().(*ed25519.Keypair)
-
-	_, err = gs.validateVoteMessage("", msg)
-	require.ErrorIs(t, err, ErrEquivocation)
-}
-
 func TestValidateMessage_BlockDoesNotExist(t *testing.T) {
 	t.Parallel()
 	st := newTestState(t)
@@ -296,6 +131,7 @@ func TestValidateMessage_BlockDoesNotExist(t *testing.T) {
 	cfg := &Config{
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Voters:       newTestVoters(t),
 		Network:      net,
 		Interval:     time.Second,
@@ -332,6 +168,7 @@ func TestValidateMessage_IsNotDescendant(t *testing.T) {
 	cfg := &Config{
 		BlockState:   st.Block,
 		GrandpaState: st.Grandpa,
+		StorageState: st.Storage,
 		Voters:       newTestVoters(t),
 		Network:      net,
 		Interval:     time.Second,
@@ -345,11 +182,16 @@ func TestValidateMessage_IsNotDescendant(t *testing.T) {
 	state.AddBlocksToStateWithFixedBranches(t, st.Block, 8, branches)
 	leaves := gs.blockState.(*state.BlockState).Leaves()
 
-	gs.head, err = gs.blockState.GetHeader(leaves[0])
+	err = st.Block.SetFinalisedHash(leaves[0], 1, 0)
+	require.NoError(t, err)
+
+	finalised, err := st.Block.GetHeader(leaves[0])
 	require.NoError(t, err)
 
+	// the blocks not descending from the finalised block are pruned,
+	// so vote for an ancestor of the finalised block instead.
 	gs.keypair = kr.Alice().(*ed25519.Keypair)
-	vote, err := NewVoteFromHash(leaves[1], gs.blockState)
+	vote, err := NewVoteFromHash(finalised.ParentHash, gs.blockState)
 	require.NoError(t, err)
 
 	_, msg, err := gs.createSignedVoteAndVoteMessage(vote, prevote)
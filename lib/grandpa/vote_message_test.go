@@ -9,6 +9,7 @@
 
 	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/lib/common"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
@@ -52,6 +53,8 @@ func TestService_reportEquivocation(t *testing.T) {
 		Equivocation: *equivocationVote,
 	}
 	type args struct {
+		round        uint64
+		setID        uint64
 		stage        Subround
 		existingVote *SignedVote
 		currentVote  *SignedVote
@@ -64,64 +67,57 @@ type args struct {
 		expErr         error
 		expErrMsg      string
 	}{
-		{
-			name: "get_setID_error",
-			serviceBuilder: func(ctrl *gomock.Controller) *Service {
-				mockGrandpaStateSetIDErr := NewMockGrandpaState(ctrl)
-				mockGrandpaStateSetIDErr.EXPECT().GetCurrentSetID().Return(uint64(0), errTestError)
-				return &Service{grandpaState: mockGrandpaStateSetIDErr}
-			},
-			expErr:    errTestError,
-			expErrMsg: "getting authority set id: test dummy error",
-		},
-		{
-			name: "get_latest_round_error",
-			serviceBuilder: func(ctrl *gomock.Controller) *Service {
-				mockGrandpaStateRoundErr := NewMockGrandpaState(ctrl)
-				mockGrandpaStateRoundErr.EXPECT().GetCurrentSetID().Return(uint64(1), nil)
-				mockGrandpaStateRoundErr.EXPECT().GetLatestRound().Return(uint64(0), errTestError)
-				return &Service{grandpaState: mockGrandpaStateRoundErr}
-			},
-			expErr:    errTestError,
-			expErrMsg: "getting latest round: test dummy error",
-		},
 		{
 			name: "get_runtime_error",
 			serviceBuilder: func(ctrl *gomock.Controller) *Service {
-				mockGrandpaStateOk := NewMockGrandpaState(ctrl)
-				mockGrandpaStateOk.EXPECT().GetCurrentSetID().Return(uint64(1), nil)
-				mockGrandpaStateOk.EXPECT().GetLatestRound().Return(uint64(1), nil)
 				mockBlockStateGetRuntimeErr := NewMockBlockState(ctrl)
 				mockBlockStateGetRuntimeErr.EXPECT().BestBlockHash().Return(dummyHash)
 				mockBlockStateGetRuntimeErr.EXPECT().GetRuntime(dummyHash).Return(nil, errTestError)
 				return &Service{
-					grandpaState: mockGrandpaStateOk,
-					blockState:   mockBlockStateGetRuntimeErr,
+					blockState: mockBlockStateGetRuntimeErr,
 				}
 			},
-			args:      args{existingVote: signedVote},
+			args:      args{round: 1, setID: 1, existingVote: signedVote},
 			expErr:    errTestError,
 			expErrMsg: "getting runtime: test dummy error",
 		},
+		{
+			name: "get_trie_state_error",
+			serviceBuilder: func(ctrl *gomock.Controller) *Service {
+				mockBlockStateTrieStateErr := NewMockBlockState(ctrl)
+				mockBlockStateTrieStateErr.EXPECT().BestBlockHash().Return(dummyHash)
+				mockBlockStateTrieStateErr.EXPECT().GetRuntime(dummyHash).Return(NewMockInstance(ctrl), nil)
+				mockStorageStateTrieStateErr := NewMockStorageState(ctrl)
+				mockStorageStateTrieStateErr.EXPECT().TrieState(nil).Return(nil, errTestError)
+				return &Service{
+					blockState:   mockBlockStateTrieStateErr,
+					storageState: mockStorageStateTrieStateErr,
+				}
+			},
+			args:      args{round: 1, setID: 1, existingVote: signedVote},
+			expErr:    errTestError,
+			expErrMsg: "getting best block trie state: test dummy error",
+		},
 		{
 			name: "get_key_ownership_proof_error",
 			serviceBuilder: func(ctrl *gomock.Controller) *Service {
 				mockRuntimeInstanceGenerateProofErr := NewMockInstance(ctrl)
 				mockRuntimeInstanceGenerateProofErr.EXPECT().GrandpaGenerateKeyOwnershipProof(uint64(1), testAuthorityID).
 					Return(types.GrandpaOpaqueKeyOwnershipProof{}, errTestError)
-				mockGrandpaStateOk := NewMockGrandpaState(ctrl)
-				mockGrandpaStateOk.EXPECT().GetCurrentSetID().Return(uint64(1), nil)
-				mockGrandpaStateOk.EXPECT().GetLatestRound().Return(uint64(1), nil)
 				mockBlockStateGenerateProofErr := NewMockBlockState(ctrl)
 				mockBlockStateGenerateProofErr.EXPECT().BestBlockHash().Return(dummyHash)
 				mockBlockStateGenerateProofErr.EXPECT().GetRuntime(dummyHash).
 					Return(mockRuntimeInstanceGenerateProofErr, nil)
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntimeInstanceGenerateProofErr.EXPECT().SetContextStorage(trieState)
+				mockStorageState := NewMockStorageState(ctrl)
+				mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
 				return &Service{
-					grandpaState: mockGrandpaStateOk,
 					blockState:   mockBlockStateGenerateProofErr,
+					storageState: mockStorageState,
 				}
 			},
-			args:      args{existingVote: signedVote},
+			args:      args{round: 1, setID: 1, existingVote: signedVote},
 			expErr:    errTestError,
 			expErrMsg: "getting key ownership proof: test dummy error",
 		},
@@ -131,19 +127,22 @@ type args struct {
 				mockRuntimeInstanceReportEquivocationErr := NewMockInstance(ctrl)
 				mockRuntimeInstanceReportEquivocationErr.EXPECT().GrandpaGenerateKeyOwnershipProof(uint64(1), testAuthorityID).
 					Return(keyOwnershipProof, nil)
-				mockGrandpaStateOk := NewMockGrandpaState(ctrl)
-				mockGrandpaStateOk.EXPECT().GetCurrentSetID().Return(uint64(1), nil)
-				mockGrandpaStateOk.EXPECT().GetLatestRound().Return(uint64(1), nil)
 				mockBlockStateReportEquivocationErr := NewMockBlockState(ctrl)
 				mockBlockStateReportEquivocationErr.EXPECT().BestBlockHash().Return(dummyHash)
 				mockBlockStateReportEquivocationErr.EXPECT().GetRuntime(dummyHash).
 					Return(mockRuntimeInstanceReportEquivocationErr, nil)
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntimeInstanceReportEquivocationErr.EXPECT().SetContextStorage(trieState)
+				mockStorageState := NewMockStorageState(ctrl)
+				mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
 				return &Service{
-					grandpaState: mockGrandpaStateOk,
 					blockState:   mockBlockStateReportEquivocationErr,
+					storageState: mockStorageState,
 				}
 			},
 			args: args{
+				round:        1,
+				setID:        1,
 				stage:        primaryProposal,
 				existingVote: signedVote,
 				currentVote:  signedVote2,
@@ -160,19 +159,22 @@ type args struct {
 				mockRuntimeInstanceReportEquivocationErr.EXPECT().
 					GrandpaSubmitReportEquivocationUnsignedExtrinsic(equivocationProof, keyOwnershipProof).
 					Return(errTestError)
-				mockGrandpaStateOk := NewMockGrandpaState(ctrl)
-				mockGrandpaStateOk.EXPECT().GetCurrentSetID().Return(uint64(1), nil)
-				mockGrandpaStateOk.EXPECT().GetLatestRound().Return(uint64(1), nil)
 				mockBlockStateReportEquivocationErr := NewMockBlockState(ctrl)
 				mockBlockStateReportEquivocationErr.EXPECT().BestBlockHash().Return(dummyHash)
 				mockBlockStateReportEquivocationErr.EXPECT().GetRuntime(dummyHash).
 					Return(mockRuntimeInstanceReportEquivocationErr, nil)
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntimeInstanceReportEquivocationErr.EXPECT().SetContextStorage(trieState)
+				mockStorageState := NewMockStorageState(ctrl)
+				mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
 				return &Service{
-					grandpaState: mockGrandpaStateOk,
 					blockState:   mockBlockStateReportEquivocationErr,
+					storageState: mockStorageState,
 				}
 			},
 			args: args{
+				round:        1,
+				setID:        1,
 				stage:        prevote,
 				existingVote: signedVote,
 				currentVote:  signedVote2,
@@ -189,18 +191,21 @@ type args struct {
 				mockRuntimeInstanceOk.EXPECT().
 					GrandpaSubmitReportEquivocationUnsignedExtrinsic(equivocationProof, keyOwnershipProof).
 					Return(nil)
-				mockGrandpaStateOk := NewMockGrandpaState(ctrl)
-				mockGrandpaStateOk.EXPECT().GetCurrentSetID().Return(uint64(1), nil)
-				mockGrandpaStateOk.EXPECT().GetLatestRound().Return(uint64(1), nil)
 				mockBlockStateOk := NewMockBlockState(ctrl)
 				mockBlockStateOk.EXPECT().BestBlockHash().Return(dummyHash)
 				mockBlockStateOk.EXPECT().GetRuntime(dummyHash).Return(mockRuntimeInstanceOk, nil)
+				trieState := rtstorage.NewTrieState(nil)
+				mockRuntimeInstanceOk.EXPECT().SetContextStorage(trieState)
+				mockStorageState := NewMockStorageState(ctrl)
+				mockStorageState.EXPECT().TrieState(nil).Return(trieState, nil)
 				return &Service{
-					grandpaState: mockGrandpaStateOk,
 					blockState:   mockBlockStateOk,
+					storageState: mockStorageState,
 				}
 			},
 			args: args{
+				round:        1,
+				setID:        1,
 				stage:        prevote,
 				existingVote: signedVote,
 				currentVote:  signedVote2,
@@ -213,7 +218,7 @@ type args struct {
 			t.Parallel()
 			ctrl := gomock.NewController(t)
 			service := tt.serviceBuilder(ctrl)
-			err := service.reportEquivocation(tt.args.stage, tt.args.existingVote, tt.args.currentVote)
+			err := service.reportEquivocation(tt.args.round, tt.args.setID, tt.args.stage, tt.args.existingVote, tt.args.currentVote)
 			assert.ErrorIs(t, err, tt.expErr)
 			if tt.expErr != nil {
 				assert.EqualError(t, err, tt.expErrMsg)
@@ -0,0 +1,35 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package manualseal
+
+import (
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+)
+
+// BlockState is the interface for the block state methods used to author and finalise blocks
+type BlockState interface {
+	babe.BlockState
+	SetFinalisedHash(hash common.Hash, round, setID uint64) error
+	GetHighestRoundAndSetID() (uint64, uint64, error)
+}
+
+// TransactionState is the interface for the transaction queue methods
+type TransactionState interface {
+	babe.TransactionState
+	Pop() *transaction.ValidTransaction
+	GetPushNotifierChannel() chan struct{}
+	FreePushNotifierChannel(ch chan struct{})
+}
+
+// EpochState is the interface for the epoch methods used to build the BABE pre-runtime digest
+type EpochState interface {
+	GetSlotDuration() (time.Duration, error)
+	GetEpochForBlock(header *types.Header) (uint64, error)
+	GetEpochDataRaw(epoch uint64, header *types.Header) (*types.EpochDataRaw, error)
+}
@@ -0,0 +1,449 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ChainSafe/gossamer/lib/manualseal (interfaces: BlockState,TransactionState,EpochState)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mocks_test.go -package manualseal . BlockState,TransactionState,EpochState
+//
+
+// Package manualseal is a generated GoMock package.
+package manualseal
+
+import (
+	reflect "reflect"
+	time "time"
+
+	types "github.com/ChainSafe/gossamer/dot/types"
+	common "github.com/ChainSafe/gossamer/lib/common"
+	runtime "github.com/ChainSafe/gossamer/lib/runtime"
+	transaction "github.com/ChainSafe/gossamer/lib/transaction"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockState is a mock of BlockState interface.
+type MockBlockState struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockStateMockRecorder
+}
+
+// MockBlockStateMockRecorder is the mock recorder for MockBlockState.
+type MockBlockStateMockRecorder struct {
+	mock *MockBlockState
+}
+
+// NewMockBlockState creates a new mock instance.
+func NewMockBlockState(ctrl *gomock.Controller) *MockBlockState {
+	mock := &MockBlockState{ctrl: ctrl}
+	mock.recorder = &MockBlockStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockState) EXPECT() *MockBlockStateMockRecorder {
+	return m.recorder
+}
+
+// AddBlock mocks base method.
+func (m *MockBlockState) AddBlock(arg0 *types.Block) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBlock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBlock indicates an expected call of AddBlock.
+func (mr *MockBlockStateMockRecorder) AddBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBlock", reflect.TypeOf((*MockBlockState)(nil).AddBlock), arg0)
+}
+
+// BestBlockHash mocks base method.
+func (m *MockBlockState) BestBlockHash() common.Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BestBlockHash")
+	ret0, _ := ret[0].(common.Hash)
+	return ret0
+}
+
+// BestBlockHash indicates an expected call of BestBlockHash.
+func (mr *MockBlockStateMockRecorder) BestBlockHash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BestBlockHash", reflect.TypeOf((*MockBlockState)(nil).BestBlockHash))
+}
+
+// BestBlockHeader mocks base method.
+func (m *MockBlockState) BestBlockHeader() (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BestBlockHeader")
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BestBlockHeader indicates an expected call of BestBlockHeader.
+func (mr *MockBlockStateMockRecorder) BestBlockHeader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BestBlockHeader", reflect.TypeOf((*MockBlockState)(nil).BestBlockHeader))
+}
+
+// FreeImportedBlockNotifierChannel mocks base method.
+func (m *MockBlockState) FreeImportedBlockNotifierChannel(arg0 chan *types.Block) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FreeImportedBlockNotifierChannel", arg0)
+}
+
+// FreeImportedBlockNotifierChannel indicates an expected call of FreeImportedBlockNotifierChannel.
+func (mr *MockBlockStateMockRecorder) FreeImportedBlockNotifierChannel(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreeImportedBlockNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).FreeImportedBlockNotifierChannel), arg0)
+}
+
+// GenesisHash mocks base method.
+func (m *MockBlockState) GenesisHash() common.Hash {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenesisHash")
+	ret0, _ := ret[0].(common.Hash)
+	return ret0
+}
+
+// GenesisHash indicates an expected call of GenesisHash.
+func (mr *MockBlockStateMockRecorder) GenesisHash() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenesisHash", reflect.TypeOf((*MockBlockState)(nil).GenesisHash))
+}
+
+// GetBlockByHash mocks base method.
+func (m *MockBlockState) GetBlockByHash(arg0 common.Hash) (*types.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockByHash", arg0)
+	ret0, _ := ret[0].(*types.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockByHash indicates an expected call of GetBlockByHash.
+func (mr *MockBlockStateMockRecorder) GetBlockByHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByHash", reflect.TypeOf((*MockBlockState)(nil).GetBlockByHash), arg0)
+}
+
+// GetBlockByNumber mocks base method.
+func (m *MockBlockState) GetBlockByNumber(arg0 uint) (*types.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockByNumber", arg0)
+	ret0, _ := ret[0].(*types.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockByNumber indicates an expected call of GetBlockByNumber.
+func (mr *MockBlockStateMockRecorder) GetBlockByNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByNumber", reflect.TypeOf((*MockBlockState)(nil).GetBlockByNumber), arg0)
+}
+
+// GetBlockHashesBySlot mocks base method.
+func (m *MockBlockState) GetBlockHashesBySlot(arg0 uint64) ([]common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockHashesBySlot", arg0)
+	ret0, _ := ret[0].([]common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockHashesBySlot indicates an expected call of GetBlockHashesBySlot.
+func (mr *MockBlockStateMockRecorder) GetBlockHashesBySlot(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockHashesBySlot", reflect.TypeOf((*MockBlockState)(nil).GetBlockHashesBySlot), arg0)
+}
+
+// GetHeader mocks base method.
+func (m *MockBlockState) GetHeader(arg0 common.Hash) (*types.Header, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHeader", arg0)
+	ret0, _ := ret[0].(*types.Header)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHeader indicates an expected call of GetHeader.
+func (mr *MockBlockStateMockRecorder) GetHeader(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHeader", reflect.TypeOf((*MockBlockState)(nil).GetHeader), arg0)
+}
+
+// GetHighestRoundAndSetID mocks base method.
+func (m *MockBlockState) GetHighestRoundAndSetID() (uint64, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHighestRoundAndSetID")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetHighestRoundAndSetID indicates an expected call of GetHighestRoundAndSetID.
+func (mr *MockBlockStateMockRecorder) GetHighestRoundAndSetID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestRoundAndSetID", reflect.TypeOf((*MockBlockState)(nil).GetHighestRoundAndSetID))
+}
+
+// GetImportedBlockNotifierChannel mocks base method.
+func (m *MockBlockState) GetImportedBlockNotifierChannel() chan *types.Block {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportedBlockNotifierChannel")
+	ret0, _ := ret[0].(chan *types.Block)
+	return ret0
+}
+
+// GetImportedBlockNotifierChannel indicates an expected call of GetImportedBlockNotifierChannel.
+func (mr *MockBlockStateMockRecorder) GetImportedBlockNotifierChannel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportedBlockNotifierChannel", reflect.TypeOf((*MockBlockState)(nil).GetImportedBlockNotifierChannel))
+}
+
+// GetRuntime mocks base method.
+func (m *MockBlockState) GetRuntime(arg0 common.Hash) (runtime.Instance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuntime", arg0)
+	ret0, _ := ret[0].(runtime.Instance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuntime indicates an expected call of GetRuntime.
+func (mr *MockBlockStateMockRecorder) GetRuntime(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntime", reflect.TypeOf((*MockBlockState)(nil).GetRuntime), arg0)
+}
+
+// GetSlotForBlock mocks base method.
+func (m *MockBlockState) GetSlotForBlock(arg0 common.Hash) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSlotForBlock", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSlotForBlock indicates an expected call of GetSlotForBlock.
+func (mr *MockBlockStateMockRecorder) GetSlotForBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSlotForBlock", reflect.TypeOf((*MockBlockState)(nil).GetSlotForBlock), arg0)
+}
+
+// IsDescendantOf mocks base method.
+func (m *MockBlockState) IsDescendantOf(arg0, arg1 common.Hash) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDescendantOf", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDescendantOf indicates an expected call of IsDescendantOf.
+func (mr *MockBlockStateMockRecorder) IsDescendantOf(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDescendantOf", reflect.TypeOf((*MockBlockState)(nil).IsDescendantOf), arg0, arg1)
+}
+
+// NumberIsFinalised mocks base method.
+func (m *MockBlockState) NumberIsFinalised(arg0 uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumberIsFinalised", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NumberIsFinalised indicates an expected call of NumberIsFinalised.
+func (mr *MockBlockStateMockRecorder) NumberIsFinalised(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumberIsFinalised", reflect.TypeOf((*MockBlockState)(nil).NumberIsFinalised), arg0)
+}
+
+// SetFinalisedHash mocks base method.
+func (m *MockBlockState) SetFinalisedHash(arg0 common.Hash, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFinalisedHash", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFinalisedHash indicates an expected call of SetFinalisedHash.
+func (mr *MockBlockStateMockRecorder) SetFinalisedHash(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFinalisedHash", reflect.TypeOf((*MockBlockState)(nil).SetFinalisedHash), arg0, arg1, arg2)
+}
+
+// StoreRuntime mocks base method.
+func (m *MockBlockState) StoreRuntime(arg0 common.Hash, arg1 runtime.Instance) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "StoreRuntime", arg0, arg1)
+}
+
+// StoreRuntime indicates an expected call of StoreRuntime.
+func (mr *MockBlockStateMockRecorder) StoreRuntime(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreRuntime", reflect.TypeOf((*MockBlockState)(nil).StoreRuntime), arg0, arg1)
+}
+
+// MockTransactionState is a mock of TransactionState interface.
+type MockTransactionState struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionStateMockRecorder
+}
+
+// MockTransactionStateMockRecorder is the mock recorder for MockTransactionState.
+type MockTransactionStateMockRecorder struct {
+	mock *MockTransactionState
+}
+
+// NewMockTransactionState creates a new mock instance.
+func NewMockTransactionState(ctrl *gomock.Controller) *MockTransactionState {
+	mock := &MockTransactionState{ctrl: ctrl}
+	mock.recorder = &MockTransactionStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionState) EXPECT() *MockTransactionStateMockRecorder {
+	return m.recorder
+}
+
+// FreePushNotifierChannel mocks base method.
+func (m *MockTransactionState) FreePushNotifierChannel(arg0 chan struct{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "FreePushNotifierChannel", arg0)
+}
+
+// FreePushNotifierChannel indicates an expected call of FreePushNotifierChannel.
+func (mr *MockTransactionStateMockRecorder) FreePushNotifierChannel(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreePushNotifierChannel", reflect.TypeOf((*MockTransactionState)(nil).FreePushNotifierChannel), arg0)
+}
+
+// GetPushNotifierChannel mocks base method.
+func (m *MockTransactionState) GetPushNotifierChannel() chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPushNotifierChannel")
+	ret0, _ := ret[0].(chan struct{})
+	return ret0
+}
+
+// GetPushNotifierChannel indicates an expected call of GetPushNotifierChannel.
+func (mr *MockTransactionStateMockRecorder) GetPushNotifierChannel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPushNotifierChannel", reflect.TypeOf((*MockTransactionState)(nil).GetPushNotifierChannel))
+}
+
+// Pop mocks base method.
+func (m *MockTransactionState) Pop() *transaction.ValidTransaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pop")
+	ret0, _ := ret[0].(*transaction.ValidTransaction)
+	return ret0
+}
+
+// Pop indicates an expected call of Pop.
+func (mr *MockTransactionStateMockRecorder) Pop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pop", reflect.TypeOf((*MockTransactionState)(nil).Pop))
+}
+
+// PopWithTimer mocks base method.
+func (m *MockTransactionState) PopWithTimer(arg0 <-chan time.Time) *transaction.ValidTransaction {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PopWithTimer", arg0)
+	ret0, _ := ret[0].(*transaction.ValidTransaction)
+	return ret0
+}
+
+// PopWithTimer indicates an expected call of PopWithTimer.
+func (mr *MockTransactionStateMockRecorder) PopWithTimer(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PopWithTimer", reflect.TypeOf((*MockTransactionState)(nil).PopWithTimer), arg0)
+}
+
+// Push mocks base method.
+func (m *MockTransactionState) Push(arg0 *transaction.ValidTransaction) (common.Hash, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Push", arg0)
+	ret0, _ := ret[0].(common.Hash)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Push indicates an expected call of Push.
+func (mr *MockTransactionStateMockRecorder) Push(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Push", reflect.TypeOf((*MockTransactionState)(nil).Push), arg0)
+}
+
+// MockEpochState is a mock of EpochState interface.
+type MockEpochState struct {
+	ctrl     *gomock.Controller
+	recorder *MockEpochStateMockRecorder
+}
+
+// MockEpochStateMockRecorder is the mock recorder for MockEpochState.
+type MockEpochStateMockRecorder struct {
+	mock *MockEpochState
+}
+
+// NewMockEpochState creates a new mock instance.
+func NewMockEpochState(ctrl *gomock.Controller) *MockEpochState {
+	mock := &MockEpochState{ctrl: ctrl}
+	mock.recorder = &MockEpochStateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEpochState) EXPECT() *MockEpochStateMockRecorder {
+	return m.recorder
+}
+
+// GetEpochDataRaw mocks base method.
+func (m *MockEpochState) GetEpochDataRaw(arg0 uint64, arg1 *types.Header) (*types.EpochDataRaw, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpochDataRaw", arg0, arg1)
+	ret0, _ := ret[0].(*types.EpochDataRaw)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEpochDataRaw indicates an expected call of GetEpochDataRaw.
+func (mr *MockEpochStateMockRecorder) GetEpochDataRaw(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpochDataRaw", reflect.TypeOf((*MockEpochState)(nil).GetEpochDataRaw), arg0, arg1)
+}
+
+// GetEpochForBlock mocks base method.
+func (m *MockEpochState) GetEpochForBlock(arg0 *types.Header) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEpochForBlock", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEpochForBlock indicates an expected call of GetEpochForBlock.
+func (mr *MockEpochStateMockRecorder) GetEpochForBlock(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEpochForBlock", reflect.TypeOf((*MockEpochState)(nil).GetEpochForBlock), arg0)
+}
+
+// GetSlotDuration mocks base method.
+func (m *MockEpochState) GetSlotDuration() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSlotDuration")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSlotDuration indicates an expected call of GetSlotDuration.
+func (mr *MockEpochStateMockRecorder) GetSlotDuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSlotDuration", reflect.TypeOf((*MockEpochState)(nil).GetSlotDuration))
+}
@@ -0,0 +1,330 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package manualseal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+)
+
+var logger = log.NewFromGlobal(log.AddContext("pkg", "manualseal"))
+
+var (
+	errNoKeypair    = errors.New("no BABE keypair provided")
+	errNotAuthority = errors.New("keypair is not a BABE authority")
+)
+
+// Config is the configuration of the manual seal service
+type Config struct {
+	LogLvl             log.Level
+	BlockState         BlockState
+	StorageState       babe.StorageState
+	TransactionState   TransactionState
+	EpochState         EpochState
+	BlockImportHandler babe.BlockImportHandler
+	Keypair            *sr25519.Keypair
+	// Instant authors a block as soon as a transaction is pushed to the queue
+	Instant bool
+}
+
+// Service authors blocks on demand, or as soon as a transaction enters the queue when instant
+// sealing is enabled, and finalises them on demand. Blocks are built by the BABE block builder
+// with a secondary plain pre-runtime digest for the slot following the slot of their parent,
+// such that the chain does not depend on the wall clock.
+type Service struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	blockState         BlockState
+	storageState       babe.StorageState
+	transactionState   TransactionState
+	epochState         EpochState
+	blockImportHandler babe.BlockImportHandler
+	keypair            *sr25519.Keypair
+	instant            bool
+	slotDuration       time.Duration
+
+	// lock serialises the block authoring and finalisation
+	lock sync.Mutex
+}
+
+// NewService creates a new manual seal service
+func NewService(cfg *Config) (*Service, error) {
+	if cfg.Keypair == nil {
+		return nil, errNoKeypair
+	}
+
+	logger.Patch(log.SetLevel(cfg.LogLvl))
+
+	slotDuration, err := cfg.EpochState.GetSlotDuration()
+	if err != nil {
+		return nil, fmt.Errorf("getting slot duration: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		ctx:                ctx,
+		cancel:             cancel,
+		blockState:         cfg.BlockState,
+		storageState:       cfg.StorageState,
+		transactionState:   cfg.TransactionState,
+		epochState:         cfg.EpochState,
+		blockImportHandler: cfg.BlockImportHandler,
+		keypair:            cfg.Keypair,
+		instant:            cfg.Instant,
+		slotDuration:       slotDuration,
+	}, nil
+}
+
+// Start starts authoring a block for every transaction pushed to the queue if instant
+// sealing is enabled
+func (s *Service) Start() error {
+	if !s.instant {
+		return nil
+	}
+
+	pushNotifierChannel := s.transactionState.GetPushNotifierChannel()
+	s.wg.Add(1)
+	go func() {
+		defer func() {
+			s.transactionState.FreePushNotifierChannel(pushNotifierChannel)
+			s.wg.Done()
+		}()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-pushNotifierChannel:
+				_, err := s.CreateBlock(false, false, nil)
+				if err != nil {
+					logger.Errorf("failed to create block: %s", err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops the service
+func (s *Service) Stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// CreateBlock authors a block on top of the given parent, or on top of the best block if the
+// parent hash is nil, and imports it. The block includes the queued transactions unless
+// createEmpty is true, and it is finalised if finalise is true.
+func (s *Service) CreateBlock(createEmpty, finalise bool, parentHash *common.Hash) (common.Hash, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	parent, err := s.getParent(parentHash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	slotNumber, err := s.nextSlotNumber(parent)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	authorityIndex, err := s.getAuthorityIndex(parent, slotNumber)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	preRuntimeDigest, err := types.NewBabeSecondaryPlainPreDigest(authorityIndex, slotNumber).ToPreRuntimeDigest()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("creating pre-runtime digest: %w", err)
+	}
+
+	block, err := s.buildAndImportBlock(parent, slotNumber, authorityIndex, preRuntimeDigest, createEmpty)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	hash := block.Header.Hash()
+	logger.Infof("sealed block #%d with hash %s and slot %d", block.Header.Number, hash, slotNumber)
+
+	if finalise {
+		err = s.finaliseBlock(hash)
+		if err != nil {
+			return common.Hash{}, err
+		}
+	}
+
+	return hash, nil
+}
+
+// FinaliseBlock finalises the block with the given hash and its ancestors
+func (s *Service) FinaliseBlock(hash common.Hash) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.finaliseBlock(hash)
+}
+
+func (s *Service) finaliseBlock(hash common.Hash) error {
+	round, setID, err := s.blockState.GetHighestRoundAndSetID()
+	if err != nil {
+		return fmt.Errorf("getting highest round and set id: %w", err)
+	}
+
+	// finalise in the next round so finality notifications are sent
+	err = s.blockState.SetFinalisedHash(hash, round+1, setID)
+	if err != nil {
+		return fmt.Errorf("finalising block: %w", err)
+	}
+
+	logger.Infof("finalised block with hash %s", hash)
+	return nil
+}
+
+func (s *Service) getParent(parentHash *common.Hash) (*types.Header, error) {
+	if parentHash == nil {
+		parent, err := s.blockState.BestBlockHeader()
+		if err != nil {
+			return nil, fmt.Errorf("getting best block header: %w", err)
+		}
+		return parent, nil
+	}
+
+	parent, err := s.blockState.GetHeader(*parentHash)
+	if err != nil {
+		return nil, fmt.Errorf("getting parent header: %w", err)
+	}
+	return parent, nil
+}
+
+// nextSlotNumber returns the slot following the slot of the parent, or the current slot if
+// the parent is the genesis block.
+func (s *Service) nextSlotNumber(parent *types.Header) (uint64, error) {
+	if parent.Number == 0 {
+		return uint64(time.Now().UnixNano()) / uint64(s.slotDuration.Nanoseconds()), nil
+	}
+
+	parentSlotNumber, err := s.blockState.GetSlotForBlock(parent.Hash())
+	if err != nil {
+		return 0, fmt.Errorf("getting parent slot: %w", err)
+	}
+	return parentSlotNumber + 1, nil
+}
+
+// getAuthorityIndex returns the index of our keypair in the authorities of the epoch of the
+// slot.
+func (s *Service) getAuthorityIndex(parent *types.Header, slotNumber uint64) (uint32, error) {
+	preRuntimeDigest, err := types.NewBabeSecondaryPlainPreDigest(0, slotNumber).ToPreRuntimeDigest()
+	if err != nil {
+		return 0, fmt.Errorf("creating pre-runtime digest: %w", err)
+	}
+
+	digest := types.NewDigest()
+	err = digest.Add(*preRuntimeDigest)
+	if err != nil {
+		return 0, fmt.Errorf("adding pre-runtime digest: %w", err)
+	}
+
+	header := types.NewHeader(parent.Hash(), common.Hash{}, common.Hash{}, parent.Number+1, digest)
+	epoch, err := s.epochState.GetEpochForBlock(header)
+	if err != nil {
+		return 0, fmt.Errorf("getting epoch: %w", err)
+	}
+
+	epochData, err := s.epochState.GetEpochDataRaw(epoch, parent)
+	if err != nil {
+		return 0, fmt.Errorf("getting epoch data for epoch %d: %w", epoch, err)
+	}
+
+	publicKey := s.keypair.Public().Encode()
+	for i, authority := range epochData.Authorities {
+		if bytes.Equal(publicKey, authority.Key[:]) {
+			return uint32(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: epoch %d", errNotAuthority, epoch)
+}
+
+func (s *Service) buildAndImportBlock(parent *types.Header, slotNumber uint64, authorityIndex uint32,
+	preRuntimeDigest *types.PreRuntimeDigest, createEmpty bool) (*types.Block, error) {
+	s.storageState.Lock()
+	defer s.storageState.Unlock()
+
+	ts, err := s.storageState.TrieState(&parent.StateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("getting parent trie state: %w", err)
+	}
+
+	rt, err := s.blockState.GetRuntime(parent.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("getting runtime: %w", err)
+	}
+	rt.SetContextStorage(ts)
+
+	transactions := &blockTransactions{
+		TransactionState: s.transactionState,
+		empty:            createEmpty,
+	}
+	builder := babe.NewBlockBuilder(s.keypair, transactions, s.blockState, authorityIndex, preRuntimeDigest)
+
+	// the timestamp inherent has to match the slot for the runtime to accept the block
+	slotStart := time.Unix(0, int64(slotNumber)*s.slotDuration.Nanoseconds())
+	slot := babe.NewSlot(slotStart, s.slotDuration, slotNumber)
+	block, err := builder.BuildBlock(parent, *slot, rt)
+	if err != nil {
+		return nil, fmt.Errorf("building block: %w", err)
+	}
+
+	err = s.blockImportHandler.HandleBlockProduced(block, ts)
+	if err != nil {
+		return nil, fmt.Errorf("importing block: %w", err)
+	}
+
+	return block, nil
+}
+
+// blockTransactions hands the transactions queued when the block extrinsics are applied to the
+// block builder without waiting for the slot to end. Transactions pushed back to the queue by
+// the block builder are left for the next block.
+type blockTransactions struct {
+	TransactionState
+	empty   bool
+	drained bool
+	queued  []*transaction.ValidTransaction
+}
+
+func (b *blockTransactions) PopWithTimer(<-chan time.Time) *transaction.ValidTransaction {
+	if b.empty {
+		return nil
+	}
+
+	if !b.drained {
+		b.drained = true
+		for tx := b.TransactionState.Pop(); tx != nil; tx = b.TransactionState.Pop() {
+			b.queued = append(b.queued, tx)
+		}
+	}
+
+	if len(b.queued) == 0 {
+		return nil
+	}
+
+	tx := b.queued[0]
+	b.queued = b.queued[1:]
+	return tx
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package manualseal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
+	"github.com/ChainSafe/gossamer/lib/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestService_FinaliseBlock(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		blockStateBuilder func(ctrl *gomock.Controller) BlockState
+		errWrapped        error
+		errMessage        string
+	}{
+		"get_highest_round_error": {
+			blockStateBuilder: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHighestRoundAndSetID().Return(uint64(0), uint64(0), errTest)
+				return blockState
+			},
+			errWrapped: errTest,
+			errMessage: "getting highest round and set id: test error",
+		},
+		"set_finalised_hash_error": {
+			blockStateBuilder: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHighestRoundAndSetID().Return(uint64(1), uint64(2), nil)
+				blockState.EXPECT().SetFinalisedHash(common.Hash{1}, uint64(2), uint64(2)).Return(errTest)
+				return blockState
+			},
+			errWrapped: errTest,
+			errMessage: "finalising block: test error",
+		},
+		"success": {
+			blockStateBuilder: func(ctrl *gomock.Controller) BlockState {
+				blockState := NewMockBlockState(ctrl)
+				blockState.EXPECT().GetHighestRoundAndSetID().Return(uint64(1), uint64(2), nil)
+				blockState.EXPECT().SetFinalisedHash(common.Hash{1}, uint64(2), uint64(2)).Return(nil)
+				return blockState
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			service := &Service{blockState: testCase.blockStateBuilder(ctrl)}
+
+			err := service.FinaliseBlock(common.Hash{1})
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func TestService_nextSlotNumber(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	const slotDuration = 6 * time.Second
+	blockState := NewMockBlockState(ctrl)
+	parent := &types.Header{Number: 3}
+	blockState.EXPECT().GetSlotForBlock(parent.Hash()).Return(uint64(100), nil)
+
+	service := &Service{blockState: blockState, slotDuration: slotDuration}
+
+	slotNumber, err := service.nextSlotNumber(parent)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(101), slotNumber)
+
+	currentSlotNumber := uint64(time.Now().UnixNano()) / uint64(slotDuration.Nanoseconds())
+	slotNumber, err = service.nextSlotNumber(&types.Header{})
+	require.NoError(t, err)
+	assert.InDelta(t, currentSlotNumber, slotNumber, 1)
+}
+
+func TestService_getAuthorityIndex(t *testing.T) {
+	t.Parallel()
+
+	keypair, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+	otherKeypair, err := sr25519.GenerateKeypair()
+	require.NoError(t, err)
+
+	var key, otherKey [sr25519.PublicKeyLength]byte
+	copy(key[:], keypair.Public().Encode())
+	copy(otherKey[:], otherKeypair.Public().Encode())
+
+	parent := &types.Header{Number: 3}
+
+	testCases := map[string]struct {
+		authorities []types.AuthorityRaw
+		index       uint32
+		errWrapped  error
+		errMessage  string
+	}{
+		"authority": {
+			authorities: []types.AuthorityRaw{{Key: otherKey}, {Key: key}},
+			index:       1,
+		},
+		"not_authority": {
+			authorities: []types.AuthorityRaw{{Key: otherKey}},
+			errWrapped:  errNotAuthority,
+			errMessage:  "keypair is not a BABE authority: epoch 2",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			epochState := NewMockEpochState(ctrl)
+			epochState.EXPECT().GetEpochForBlock(gomock.Any()).DoAndReturn(
+				func(header *types.Header) (uint64, error) {
+					slotNumber, err := header.SlotNumber()
+					require.NoError(t, err)
+					assert.Equal(t, uint64(101), slotNumber)
+					assert.Equal(t, parent.Hash(), header.ParentHash)
+					return 2, nil
+				})
+			epochState.EXPECT().GetEpochDataRaw(uint64(2), parent).
+				Return(&types.EpochDataRaw{Authorities: testCase.authorities}, nil)
+
+			service := &Service{epochState: epochState, keypair: keypair}
+
+			index, err := service.getAuthorityIndex(parent, 101)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.index, index)
+		})
+	}
+}
+
+func Test_blockTransactions_PopWithTimer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+
+		transactions := &blockTransactions{
+			TransactionState: NewMockTransactionState(ctrl),
+			empty:            true,
+		}
+		assert.Nil(t, transactions.PopWithTimer(nil))
+	})
+
+	t.Run("queued_transactions", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+
+		first := &transaction.ValidTransaction{Extrinsic: types.Extrinsic{1}}
+		second := &transaction.ValidTransaction{Extrinsic: types.Extrinsic{2}}
+
+		transactionState := NewMockTransactionState(ctrl)
+		gomock.InOrder(
+			transactionState.EXPECT().Pop().Return(first),
+			transactionState.EXPECT().Pop().Return(second),
+			transactionState.EXPECT().Pop().Return(nil),
+		)
+
+		transactions := &blockTransactions{TransactionState: transactionState}
+		assert.Equal(t, first, transactions.PopWithTimer(nil))
+		assert.Equal(t, second, transactions.PopWithTimer(nil))
+		// transactions pushed back to the queue are not popped again
+		assert.Nil(t, transactions.PopWithTimer(nil))
+	})
+}
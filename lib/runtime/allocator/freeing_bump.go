@@ -339,9 +339,17 @@ type FreeingBumpHeapAllocator struct {
 	poisoned               bool
 	lastObservedMemorySize uint64
 	stats                  AllocationStats
+	// maxPages is the maximum number of pages the allocator grows the memory to
+	maxPages uint32
 }
 
 func NewFreeingBumpHeapAllocator(heapBase uint32) *FreeingBumpHeapAllocator {
+	return NewFreeingBumpHeapAllocatorWithMaxPages(heapBase, MaxWasmPages)
+}
+
+// NewFreeingBumpHeapAllocatorWithMaxPages returns an allocator growing the memory to at most
+// maxPages pages, the allocations needing more pages fail with ErrAllocatorOutOfSpace.
+func NewFreeingBumpHeapAllocatorWithMaxPages(heapBase, maxPages uint32) *FreeingBumpHeapAllocator {
 	alignedHeapBase := (heapBase + Aligment - 1) / Aligment * Aligment
 	return &FreeingBumpHeapAllocator{
 		originalHeapBase:       alignedHeapBase,
@@ -349,6 +357,7 @@ func NewFreeingBumpHeapAllocator(heapBase uint32) *FreeingBumpHeapAllocator {
 		freeLists:              NewFreeLists(),
 		poisoned:               false,
 		lastObservedMemorySize: 0,
+		maxPages:               min(maxPages, MaxWasmPages),
 		stats: AllocationStats{
 			bytesAllocated:     0,
 			bytesAllocatedPeak: 0,
@@ -418,7 +427,7 @@ func (f *FreeingBumpHeapAllocator) Allocate(mem runtime.Memory, size uint32) (pt
 		headerPtr = value.headerPtr
 	case Nil:
 		// Corresponding free list is empty. Allocate a new item
-		newPtr, err := bump(&f.bumper, order.size()+HeaderSize, mem)
+		newPtr, err := bump(&f.bumper, order.size()+HeaderSize, mem, f.maxPages)
 		if err != nil {
 			return 0, fmt.Errorf("bumping: %w", err)
 		}
@@ -507,7 +516,7 @@ func (f *FreeingBumpHeapAllocator) Deallocate(mem runtime.Memory, ptr uint32) (e
 	return nil
 }
 
-func bump(bumper *uint32, size uint32, mem runtime.Memory) (uint32, error) {
+func bump(bumper *uint32, size uint32, mem runtime.Memory, maxPages uint32) (uint32, error) {
 	requiredSize := uint64(*bumper) + uint64(size)
 
 	if requiredSize > mem.Size() {
@@ -521,19 +530,19 @@ func bump(bumper *uint32, size uint32, mem runtime.Memory) (uint32, error) {
 			panic(fmt.Sprintf("page size cannot fit into uint32, current memory size: %d", mem.Size()))
 		}
 
-		if currentPages >= MaxWasmPages {
-			return 0, fmt.Errorf("%w: current pages %d greater than max wasm pages %d",
-				ErrAllocatorOutOfSpace, currentPages, MaxWasmPages)
+		if currentPages >= maxPages {
+			return 0, fmt.Errorf("%w: current pages %d greater than max pages %d",
+				ErrAllocatorOutOfSpace, currentPages, maxPages)
 		}
 
-		if requiredPages > MaxWasmPages {
-			return 0, fmt.Errorf("%w: required pages %d greater than max wasm pages %d",
-				ErrAllocatorOutOfSpace, requiredPages, MaxWasmPages)
+		if requiredPages > maxPages {
+			return 0, fmt.Errorf("%w: required pages %d greater than max pages %d",
+				ErrAllocatorOutOfSpace, requiredPages, maxPages)
 		}
 
 		// ideally we want to double our current number of pages,
 		// as long as it's less than the double absolute max we can have
-		nextPages := min(currentPages*2, MaxWasmPages)
+		nextPages := min(currentPages*2, maxPages)
 		// ... but if even more pages are required then try to allocate that many
 		nextPages = max(nextPages, requiredPages)
 
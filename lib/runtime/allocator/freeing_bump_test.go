@@ -197,6 +197,22 @@ func TestShouldNotAllocateIfFull(t *testing.T) {
 	require.ErrorIs(t, err, ErrCannotGrowLinearMemory)
 }
 
+func TestShouldNotGrowBeyondMaxPages(t *testing.T) {
+	mem := NewMemoryInstanceWithPages(t, 1)
+	heap := NewFreeingBumpHeapAllocatorWithMaxPages(0, 2)
+
+	// the memory is doubled up to the max pages of the allocator
+	ptr1, err := heap.Allocate(mem, PageSize)
+	require.NoError(t, err)
+	require.Equal(t, uint32(HeaderSize), ptr1)
+	require.Equal(t, uint64(2*PageSize), mem.Size())
+
+	ptr2, err := heap.Allocate(mem, PageSize)
+	require.Zero(t, ptr2)
+	require.ErrorIs(t, err, ErrAllocatorOutOfSpace)
+	require.Equal(t, uint64(2*PageSize), mem.Size())
+}
+
 func TestShouldAllocateMaxPossibleAllocationSize(t *testing.T) {
 	mem := NewMemoryInstanceWithPages(t, 1)
 	heap := NewFreeingBumpHeapAllocator(0)
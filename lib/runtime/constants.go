@@ -62,10 +62,14 @@
 	BlockBuilderFinalizeBlock = "BlockBuilder_finalize_block"
 	// DecodeSessionKeys is the runtime API call SessionKeys_decode_session_keys
 	DecodeSessionKeys = "SessionKeys_decode_session_keys"
+	// GenerateSessionKeys is the runtime API call SessionKeys_generate_session_keys
+	GenerateSessionKeys = "SessionKeys_generate_session_keys"
 	// TransactionPaymentAPIQueryInfo returns information of a given extrinsic
 	TransactionPaymentAPIQueryInfo = "TransactionPaymentApi_query_info"
 	// TransactionPaymentCallAPIQueryCallInfo returns call query call info
 	TransactionPaymentCallAPIQueryCallInfo = "TransactionPaymentCallApi_query_call_info"
 	// TransactionPaymentCallAPIQueryCallFeeDetails returns call query call fee details
 	TransactionPaymentCallAPIQueryCallFeeDetails = "TransactionPaymentCallApi_query_call_fee_details"
+	// OffchainWorkerAPIOffchainWorker is the runtime API call OffchainWorkerApi_offchain_worker
+	OffchainWorkerAPIOffchainWorker = "OffchainWorkerApi_offchain_worker"
 )
@@ -0,0 +1,165 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxModules is the default maximum number of modules instantiated by an executor.
+const DefaultMaxModules = 4
+
+var (
+	// ErrExecutorClosed is returned when executing a call with a closed executor.
+	ErrExecutorClosed = errors.New("executor is closed")
+	// ErrNoContextStorage is returned when executing a call with a handle whose context storage is not set.
+	ErrNoContextStorage = errors.New("context storage of the handle is not set")
+)
+
+// Module is a module instantiated from the code of a runtime, executing a single call at a time.
+type Module interface {
+	// Exec executes the runtime function with the given runtime context,
+	// the call is aborted once ctx is done.
+	Exec(ctx context.Context, rtCtx *Context, function string, data []byte) ([]byte, error)
+	Close() error
+}
+
+// NewModuleFunc instantiates a module from the code of a runtime.
+type NewModuleFunc func() (Module, error)
+
+// Executor dispatches the calls to a runtime concurrently, on a bounded pool
+// of modules instantiated from the same runtime code. Each call runs with its own
+// context, so the calls do not share their storage or allocator.
+type Executor struct {
+	newModule NewModuleFunc
+	// idle holds the modules not executing a call
+	idle chan Module
+	// slots bounds the number of instantiated modules
+	slots chan struct{}
+
+	mutex   sync.Mutex
+	modules []Module
+	closed  bool
+	calls   sync.WaitGroup
+}
+
+// NewExecutor returns an executor instantiating at most maxModules modules with newModule,
+// using DefaultMaxModules if maxModules is zero. The first module is instantiated right away
+// such that errors in the runtime code are returned here.
+func NewExecutor(maxModules uint, newModule NewModuleFunc) (*Executor, error) {
+	if maxModules == 0 {
+		maxModules = DefaultMaxModules
+	}
+
+	executor := &Executor{
+		newModule: newModule,
+		idle:      make(chan Module, maxModules),
+		slots:     make(chan struct{}, maxModules),
+	}
+
+	executor.slots <- struct{}{}
+	module, err := executor.instantiate()
+	if err != nil {
+		return nil, err
+	}
+	executor.idle <- module
+
+	return executor, nil
+}
+
+// Exec executes the runtime function with the given runtime context on an idle module. A new
+// module is instantiated if all the modules are busy and the pool is not full, otherwise it
+// blocks until a module finishes its call or ctx is done.
+func (e *Executor) Exec(ctx context.Context, rtCtx *Context, function string, data []byte) ([]byte, error) {
+	e.mutex.Lock()
+	if e.closed {
+		e.mutex.Unlock()
+		return nil, ErrExecutorClosed
+	}
+	e.calls.Add(1)
+	e.mutex.Unlock()
+	defer e.calls.Done()
+
+	module, err := e.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		e.idle <- module
+	}()
+
+	return module.Exec(ctx, rtCtx, function, data)
+}
+
+func (e *Executor) acquire(ctx context.Context) (Module, error) {
+	select {
+	case module := <-e.idle:
+		return module, nil
+	default:
+	}
+
+	select {
+	case module := <-e.idle:
+		return module, nil
+	case e.slots <- struct{}{}:
+		return e.instantiate()
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for a module: %w", ctx.Err())
+	}
+}
+
+// instantiate instantiates a new module, a slot must be reserved for the module by the caller.
+func (e *Executor) instantiate() (Module, error) {
+	module, err := e.newModule()
+	if err != nil {
+		<-e.slots
+		return nil, fmt.Errorf("instantiating module: %w", err)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.modules = append(e.modules, module)
+	return module, nil
+}
+
+// Close waits for the calls being executed and closes the modules of the executor.
+// It returns ErrExecutorClosed if the executor was already closed.
+func (e *Executor) Close() error {
+	e.mutex.Lock()
+	if e.closed {
+		e.mutex.Unlock()
+		return ErrExecutorClosed
+	}
+	e.closed = true
+	e.mutex.Unlock()
+
+	e.calls.Wait()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	var errs []error
+	for _, module := range e.modules {
+		err := module.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("closing module: %w", err))
+		}
+	}
+	e.modules = nil
+
+	return errors.Join(errs...)
+}
+
+// Pooled is implemented by the instances executing their calls with an Executor.
+type Pooled interface {
+	// Handle returns an instance executing its calls with the same executor, having
+	// its own context storage such that it can be used concurrently with this instance.
+	// The storage of the handle must be set before executing calls.
+	Handle() Instance
+	// Parent returns the instance the handle was created from, or the
+	// instance itself if it is not a handle.
+	Parent() Instance
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingModule returns the name of the called function once released
+type blockingModule struct {
+	running *atomic.Int32
+	release <-chan struct{}
+	closed  bool
+}
+
+func (m *blockingModule) Exec(_ context.Context, _ *Context, function string, _ []byte) ([]byte, error) {
+	m.running.Add(1)
+	defer m.running.Add(-1)
+	<-m.release
+	return []byte(function), nil
+}
+
+func (m *blockingModule) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestExecutor(t *testing.T) {
+	t.Parallel()
+
+	const maxModules = 2
+	running := new(atomic.Int32)
+	release := make(chan struct{})
+	var (
+		modulesMutex sync.Mutex
+		modules      []*blockingModule
+	)
+	executor, err := NewExecutor(maxModules, func() (Module, error) {
+		modulesMutex.Lock()
+		defer modulesMutex.Unlock()
+		module := &blockingModule{running: running, release: release}
+		modules = append(modules, module)
+		return module, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, modules, 1)
+
+	const calls = 5
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := executor.Exec(context.Background(), &Context{}, "function", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("function"), result)
+		}()
+	}
+
+	// the calls beyond the size of the pool wait for a module
+	assert.Eventually(t, func() bool { return running.Load() == maxModules }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Len(t, modules, maxModules)
+
+	err = executor.Close()
+	require.NoError(t, err)
+	for _, module := range modules {
+		assert.True(t, module.closed)
+	}
+
+	_, err = executor.Exec(context.Background(), &Context{}, "function", nil)
+	assert.ErrorIs(t, err, ErrExecutorClosed)
+	assert.ErrorIs(t, executor.Close(), ErrExecutorClosed)
+}
+
+func TestExecutor_Exec_contextDone(t *testing.T) {
+	t.Parallel()
+
+	running := new(atomic.Int32)
+	release := make(chan struct{})
+	executor, err := NewExecutor(1, func() (Module, error) {
+		return &blockingModule{running: running, release: release}, nil
+	})
+	require.NoError(t, err)
+
+	go func() {
+		_, err := executor.Exec(context.Background(), &Context{}, "function", nil)
+		assert.NoError(t, err)
+	}()
+	require.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, time.Millisecond)
+
+	// the call waiting for the busy module gives up once its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = executor.Exec(ctx, &Context{}, "function", nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	err = executor.Close()
+	require.NoError(t, err)
+}
+
+func TestNewExecutor_error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	executor, err := NewExecutor(0, func() (Module, error) {
+		return nil, errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+	assert.Nil(t, executor)
+}
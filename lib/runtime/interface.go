@@ -41,8 +41,8 @@ type Instance interface {
 		keyOwnershipProof types.OpaqueKeyOwnershipProof,
 	) error
 	RandomSeed()
-	OffchainWorker()
-	GenerateSessionKeys()
+	OffchainWorker(header *types.Header) error
+	GenerateSessionKeys(seed *[]byte) ([]byte, error)
 	GrandpaGenerateKeyOwnershipProof(authSetID uint64, authorityID ed25519.PublicKeyBytes) (
 		types.GrandpaOpaqueKeyOwnershipProof, error)
 	GrandpaSubmitReportEquivocationUnsignedExtrinsic(
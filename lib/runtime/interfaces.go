@@ -43,6 +43,12 @@ type Transactional interface {
 	RollbackTransaction()
 }
 
+// TransactionIndex storage interface.
+type TransactionIndex interface {
+	IndexTransaction(extrinsic, size uint32, hash common.Hash)
+	RenewTransaction(extrinsic uint32, hash common.Hash)
+}
+
 // Runtime storage interface.
 type Runtime interface {
 	LoadCode() []byte
@@ -54,6 +60,7 @@ type Storage interface {
 	Trie
 	ChildTrie
 	Transactional
+	TransactionIndex
 	Runtime
 }
 
@@ -71,5 +78,5 @@ type BasicStorage interface {
 
 // TransactionState interface for adding transactions to pool
 type TransactionState interface {
-	AddToPool(vt *transaction.ValidTransaction) common.Hash
+	AddToPool(vt *transaction.ValidTransaction) (common.Hash, error)
 }
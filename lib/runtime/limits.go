@@ -0,0 +1,101 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrCallTimeout is returned when a runtime call exceeds the timeout of its call class.
+	ErrCallTimeout = errors.New("runtime call timed out")
+	// ErrCallCanceled is returned when a runtime call is canceled by its caller.
+	ErrCallCanceled = errors.New("runtime call canceled")
+	// ErrMemoryLimitExceeded is returned when a runtime call needs more memory than
+	// allowed by its call class.
+	ErrMemoryLimitExceeded = errors.New("runtime call memory limit exceeded")
+)
+
+// MaxMemoryPages is the maximum number of pages of a wasm memory, 4GiB.
+const MaxMemoryPages = 65536
+
+// CallClass is the class of a runtime call, which determines its execution limits.
+type CallClass uint8
+
+const (
+	// BlockImportCall is a call executing, building or importing blocks.
+	BlockImportCall CallClass = iota
+	// RPCCall is a call requested by a RPC client.
+	RPCCall
+	// OffchainCall is a call running an offchain worker.
+	OffchainCall
+	// ValidationCall is a call validating a transaction.
+	ValidationCall
+)
+
+func (c CallClass) String() string {
+	switch c {
+	case BlockImportCall:
+		return "block import"
+	case RPCCall:
+		return "RPC"
+	case OffchainCall:
+		return "offchain"
+	case ValidationCall:
+		return "validation"
+	default:
+		return fmt.Sprintf("unknown call class %d", uint8(c))
+	}
+}
+
+// Limits are the execution limits of a runtime call.
+type Limits struct {
+	// Timeout is the maximum duration of the call, including the time waiting
+	// for a module to execute it. The call has no deadline if it is zero.
+	Timeout time.Duration
+	// MaxMemoryPages is the maximum number of wasm pages the allocator of the
+	// call grows the memory to, MaxMemoryPages is used if it is zero.
+	MaxMemoryPages uint32
+}
+
+// DefaultLimits are the execution limits of each call class. The blocks are imported whatever
+// their execution time since the chain cannot progress otherwise, while the calls on behalf of
+// RPC clients, offchain workers and transactions are bounded in time and memory.
+var DefaultLimits = map[CallClass]Limits{
+	BlockImportCall: {
+		MaxMemoryPages: MaxMemoryPages,
+	},
+	RPCCall: {
+		Timeout:        30 * time.Second,
+		MaxMemoryPages: 8192,
+	},
+	OffchainCall: {
+		Timeout:        2 * time.Minute,
+		MaxMemoryPages: 8192,
+	},
+	ValidationCall: {
+		Timeout:        10 * time.Second,
+		MaxMemoryPages: 8192,
+	},
+}
+
+// Limited is implemented by the instances enforcing execution limits on their calls.
+type Limited interface {
+	// WithLimits returns an instance executing its calls with the limits of the call class,
+	// the calls are canceled once the context is done.
+	WithLimits(ctx context.Context, class CallClass) Instance
+}
+
+// WithLimits returns an instance executing its calls with the given context and the limits of
+// the call class, or the instance itself if it does not enforce execution limits.
+func WithLimits(ctx context.Context, instance Instance, class CallClass) Instance {
+	limited, ok := instance.(Limited)
+	if !ok {
+		return instance
+	}
+	return limited.WithLimits(ctx, class)
+}
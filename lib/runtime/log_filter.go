@@ -0,0 +1,136 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+)
+
+// ErrInvalidLogDirective is returned when parsing a log directive which is not 'level' or 'target=level'.
+var ErrInvalidLogDirective = errors.New("invalid log directive")
+
+// LogFilter filters the messages logged by the runtime by their target and level. It is shared
+// by the runtime instances of the node and its directives can be changed while they run.
+type LogFilter struct {
+	mutex sync.RWMutex
+	// directives are the levels of the targets and of their sub targets, the
+	// empty target holds the level of the targets without directive.
+	directives map[string]log.Level
+	// initialDirectives are the directives restored by Reset
+	initialDirectives map[string]log.Level
+	maxLevel          log.Level
+}
+
+// NewLogFilter returns a filter logging the messages up to the given level, and the messages
+// of the targets of the directives up to their level. A directive of a target applies to its
+// sub targets as well, such that 'runtime' applies to 'runtime::staking'.
+func NewLogFilter(level log.Level, directives map[string]log.Level) *LogFilter {
+	initialDirectives := map[string]log.Level{"": level}
+	for target, targetLevel := range directives {
+		initialDirectives[target] = targetLevel
+	}
+
+	filter := &LogFilter{}
+	filter.setDirectives(initialDirectives)
+	filter.initialDirectives = initialDirectives
+	return filter
+}
+
+// ParseLogDirectives parses a comma separated list of directives 'target=level', a directive
+// with only a level being the level of the targets without directive.
+func ParseLogDirectives(s string) (directives map[string]log.Level, err error) {
+	directives = make(map[string]log.Level)
+	for _, directive := range strings.Split(s, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+
+		var target, level string
+		parts := strings.SplitN(directive, "=", 2)
+		if len(parts) == 1 {
+			level = parts[0]
+		} else {
+			target = strings.TrimSpace(parts[0])
+			level = parts[1]
+			if target == "" {
+				return nil, fmt.Errorf("%w: %s: empty target", ErrInvalidLogDirective, directive)
+			}
+		}
+
+		directives[target], err = log.ParseLevel(strings.TrimSpace(level))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLogDirective, directive, err)
+		}
+	}
+	return directives, nil
+}
+
+// AddDirectives parses the comma separated directives and adds them to the filter,
+// replacing the directives of the same targets.
+func (f *LogFilter) AddDirectives(s string) error {
+	added, err := ParseLogDirectives(s)
+	if err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	directives := make(map[string]log.Level, len(f.directives)+len(added))
+	for target, level := range f.directives {
+		directives[target] = level
+	}
+	for target, level := range added {
+		directives[target] = level
+	}
+	f.setDirectives(directives)
+	return nil
+}
+
+// Reset restores the directives the filter was created with.
+func (f *LogFilter) Reset() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.setDirectives(f.initialDirectives)
+}
+
+func (f *LogFilter) setDirectives(directives map[string]log.Level) {
+	f.directives = directives
+	f.maxLevel = log.Critical
+	for _, level := range directives {
+		f.maxLevel = max(f.maxLevel, level)
+	}
+}
+
+// Enabled returns true if the messages of the target are logged at the given level.
+func (f *LogFilter) Enabled(target string, level log.Level) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if level > f.maxLevel {
+		return false
+	}
+
+	matched := ""
+	for directive := range f.directives {
+		if len(directive) > len(matched) &&
+			(target == directive || strings.HasPrefix(target, directive+"::")) {
+			matched = directive
+		}
+	}
+	return level <= f.directives[matched]
+}
+
+// MaxLevel returns the highest level of the messages logged by the filter,
+// such that the runtime does not produce the messages above it.
+func (f *LogFilter) MaxLevel() log.Level {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.maxLevel
+}
@@ -0,0 +1,151 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package runtime
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseLogDirectives(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		directives map[string]log.Level
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			directives: map[string]log.Level{},
+		},
+		"targets": {
+			s: "runtime::staking=debug, runtime::system = trace",
+			directives: map[string]log.Level{
+				"runtime::staking": log.Debug,
+				"runtime::system":  log.Trace,
+			},
+		},
+		"default_level": {
+			s: "warn,runtime=info",
+			directives: map[string]log.Level{
+				"":        log.Warn,
+				"runtime": log.Info,
+			},
+		},
+		"empty_target": {
+			s:          "=debug",
+			errWrapped: ErrInvalidLogDirective,
+			errMessage: "invalid log directive: =debug: empty target",
+		},
+		"invalid_level": {
+			s:          "runtime=verbose",
+			errWrapped: ErrInvalidLogDirective,
+			errMessage: "invalid log directive: runtime=verbose: level is not recognised: verbose",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			directives, err := ParseLogDirectives(testCase.s)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.directives, directives)
+		})
+	}
+}
+
+func TestLogFilter_Enabled(t *testing.T) {
+	t.Parallel()
+
+	filter := NewLogFilter(log.Info, map[string]log.Level{
+		"runtime":          log.Warn,
+		"runtime::staking": log.Debug,
+	})
+
+	testCases := map[string]struct {
+		target  string
+		level   log.Level
+		enabled bool
+	}{
+		"default_level": {
+			target:  "babe",
+			level:   log.Info,
+			enabled: true,
+		},
+		"above_default_level": {
+			target: "babe",
+			level:  log.Debug,
+		},
+		"target_level": {
+			target:  "runtime",
+			level:   log.Warn,
+			enabled: true,
+		},
+		"above_target_level": {
+			target: "runtime",
+			level:  log.Info,
+		},
+		"sub_target_level": {
+			target:  "runtime::staking::rewards",
+			level:   log.Debug,
+			enabled: true,
+		},
+		"longest_target_matched": {
+			target:  "runtime::staking",
+			level:   log.Debug,
+			enabled: true,
+		},
+		"target_prefix_not_matched": {
+			target:  "runtimes",
+			level:   log.Info,
+			enabled: true,
+		},
+		"above_max_level": {
+			target: "runtime::staking",
+			level:  log.Trace,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			enabled := filter.Enabled(testCase.target, testCase.level)
+			assert.Equal(t, testCase.enabled, enabled)
+		})
+	}
+}
+
+func TestLogFilter_AddDirectives(t *testing.T) {
+	t.Parallel()
+
+	filter := NewLogFilter(log.Info, map[string]log.Level{"runtime": log.Warn})
+	assert.Equal(t, log.Info, filter.MaxLevel())
+
+	err := filter.AddDirectives("runtime::staking=trace,runtime=error")
+	require.NoError(t, err)
+	assert.Equal(t, log.Trace, filter.MaxLevel())
+	assert.True(t, filter.Enabled("runtime::staking", log.Trace))
+	assert.False(t, filter.Enabled("runtime", log.Warn))
+
+	err = filter.AddDirectives("runtime::staking=verbose")
+	require.ErrorIs(t, err, ErrInvalidLogDirective)
+	assert.True(t, filter.Enabled("runtime::staking", log.Trace))
+
+	filter.Reset()
+	assert.Equal(t, log.Info, filter.MaxLevel())
+	assert.False(t, filter.Enabled("runtime::staking", log.Trace))
+	assert.True(t, filter.Enabled("runtime", log.Warn))
+}
@@ -165,9 +165,27 @@ func (_m *Instance) FinalizeBlock() (*types.Header, error) {
 	return r0, r1
 }
 
-// GenerateSessionKeys provides a mock function with given fields:
-func (_m *Instance) GenerateSessionKeys() {
-	_m.Called()
+// GenerateSessionKeys provides a mock function with given fields: seed
+func (_m *Instance) GenerateSessionKeys(seed *[]byte) ([]byte, error) {
+	ret := _m.Called(seed)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(*[]byte) []byte); ok {
+		r0 = rf(seed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*[]byte) error); ok {
+		r1 = rf(seed)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetCodeHash provides a mock function with given fields:
@@ -352,9 +370,18 @@ func (_m *Instance) NodeStorage() runtime.NodeStorage {
 	return r0
 }
 
-// OffchainWorker provides a mock function with given fields:
-func (_m *Instance) OffchainWorker() {
-	_m.Called()
+// OffchainWorker provides a mock function with given fields: header
+func (_m *Instance) OffchainWorker(header *types.Header) error {
+	ret := _m.Called(header)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Header) error); ok {
+		r0 = rf(header)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // PaymentQueryInfo provides a mock function with given fields: ext
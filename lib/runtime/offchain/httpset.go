@@ -7,9 +7,12 @@
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 type contextKey string
@@ -60,16 +63,70 @@ func (b requestIDBuffer) put(i int16) error {
 	}
 }
 
+// HTTPError is the error of an offchain http request operation, as defined by the host API
+type HTTPError byte
+
+const (
+	// HTTPErrorDeadlineReached is returned when the deadline of the operation is reached
+	HTTPErrorDeadlineReached HTTPError = iota
+	// HTTPErrorIO is returned when sending the request or receiving the response failed,
+	// in which case the request is removed from the set
+	HTTPErrorIO
+	// HTTPErrorInvalid is returned when the request is unknown or when the operation
+	// is not allowed in the current state of the request
+	HTTPErrorInvalid
+)
+
+func (e HTTPError) Error() string {
+	switch e {
+	case HTTPErrorDeadlineReached:
+		return "deadline reached"
+	case HTTPErrorIO:
+		return "io error"
+	case HTTPErrorInvalid:
+		return "invalid request"
+	default:
+		return fmt.Sprintf("unknown http error %d", byte(e))
+	}
+}
+
+// RequestStatus is the status of a request returned by HTTPSet.Wait, either finished
+// with the status code of the response or failed with an HTTPError
+type RequestStatus struct {
+	Finished   bool
+	StatusCode uint16
+	Error      HTTPError
+}
+
+// MarshalSCALE encodes the status as the HttpRequestStatus enum of the host API
+func (s RequestStatus) MarshalSCALE() ([]byte, error) {
+	if s.Finished {
+		return []byte{3, byte(s.StatusCode), byte(s.StatusCode >> 8)}, nil
+	}
+	return []byte{byte(s.Error)}, nil
+}
+
 // Request holds the request object and update the invalid and waiting status whenever
 // the request starts or is waiting to be read
 type Request struct {
 	Request *http.Request
+
+	cancel context.CancelFunc
+	// dispatched is true once the request is sent, which happens on the first
+	// body write or when waiting for the response
+	dispatched bool
+	// bodyWriter streams the chunks of the request body until the body is finished
+	bodyWriter *io.PipeWriter
+	// done is closed once the response headers, or an error, are received
+	done     chan struct{}
+	response *http.Response
+	err      error
 }
 
 // AddHeader adds a new HTTP header into request property, only if request is valid
 func (r *Request) AddHeader(name, value string) error {
 	invalid, ok := r.Request.Context().Value(invalidKey).(bool)
-	if ok && invalid {
+	if ok && invalid || r.dispatched {
 		return errRequestInvalid
 	}
 
@@ -82,20 +139,68 @@ func (r *Request) AddHeader(name, value string) error {
 	return nil
 }
 
+// dispatch sends the request in the background, streaming the body written to the
+// returned writer if withBody is true
+func (r *Request) dispatch(client *http.Client, withBody bool) {
+	r.dispatched = true
+	r.done = make(chan struct{})
+	if withBody {
+		bodyReader, bodyWriter := io.Pipe()
+		r.Request.Body = bodyReader
+		r.bodyWriter = bodyWriter
+	}
+
+	go func() {
+		defer close(r.done)
+		r.response, r.err = client.Do(r.Request) //nolint:bodyclose // closed when the request is removed
+	}()
+}
+
+// finishBody dispatches the request if needed and ends its body
+func (r *Request) finishBody(client *http.Client) {
+	if !r.dispatched {
+		r.dispatch(client, false)
+		return
+	}
+	if r.bodyWriter != nil {
+		_ = r.bodyWriter.Close()
+		r.bodyWriter = nil
+	}
+}
+
+func (r *Request) close() {
+	r.cancel()
+	if r.bodyWriter != nil {
+		_ = r.bodyWriter.CloseWithError(errRequestInvalid)
+	}
+	if r.done == nil {
+		return
+	}
+	select {
+	case <-r.done:
+		if r.response != nil {
+			_ = r.response.Body.Close()
+		}
+	default:
+	}
+}
+
 // HTTPSet holds a pool of concurrent http request calls
 type HTTPSet struct {
 	*sync.Mutex
 	reqs   map[int16]*Request
 	idBuff requestIDBuffer
+	client *http.Client
 }
 
 // NewHTTPSet creates a offchain http set that can be used
 // by runtime as HTTP clients, the max concurrent requests is 1000
 func NewHTTPSet() *HTTPSet {
 	return &HTTPSet{
-		new(sync.Mutex),
-		make(map[int16]*Request),
-		newIntBuffer(maxConcurrentRequests),
+		Mutex:  new(sync.Mutex),
+		reqs:   make(map[int16]*Request),
+		idBuff: newIntBuffer(maxConcurrentRequests),
+		client: new(http.Client),
 	}
 }
 
@@ -114,30 +219,35 @@ func (p *HTTPSet) StartRequest(method, uri string) (int16, error) {
 		return 0, errRequestIDNotAvailable
 	}
 
-	req, err := http.NewRequest(method, uri, nil)
-	req.Header = make(http.Header)
-
-	ctx := context.WithValue(req.Context(), waitingKey, false)
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, waitingKey, false)
 	ctx = context.WithValue(ctx, invalidKey, false)
 
-	req = req.WithContext(ctx)
-
+	req, err := http.NewRequestWithContext(ctx, method, uri, nil)
 	if err != nil {
+		cancel()
+		_ = p.idBuff.put(id)
 		return 0, err
 	}
+	req.Header = make(http.Header)
 
 	p.reqs[id] = &Request{
 		Request: req,
+		cancel:  cancel,
 	}
 
 	return id, nil
 }
 
-// Remove just remove a expecific request from reqs
+// Remove just remove a expecific request from reqs, aborting it if it is in progress
 func (p *HTTPSet) Remove(id int16) error {
 	p.Lock()
 	defer p.Unlock()
 
+	req, ok := p.reqs[id]
+	if ok {
+		req.close()
+	}
 	delete(p.reqs, id)
 
 	return p.idBuff.put(id)
@@ -150,3 +260,203 @@ func (p *HTTPSet) Get(id int16) *Request {
 
 	return p.reqs[id]
 }
+
+// WriteBody writes a chunk of the body of the given request, sending the request on the
+// first write. An empty chunk ends the body. If the deadline is reached, the chunk may
+// have been partially written so the request is removed.
+func (p *HTTPSet) WriteBody(id int16, chunk []byte, deadline *time.Time) error {
+	p.Lock()
+	req := p.reqs[id]
+	if req == nil || (req.dispatched && req.bodyWriter == nil) {
+		p.Unlock()
+		return HTTPErrorInvalid
+	}
+	if !req.dispatched {
+		req.dispatch(p.client, true)
+	}
+	if len(chunk) == 0 {
+		req.finishBody(p.client)
+		p.Unlock()
+		return nil
+	}
+	bodyWriter := req.bodyWriter
+	p.Unlock()
+
+	written := make(chan error, 1)
+	go func() {
+		_, err := bodyWriter.Write(chunk)
+		written <- err
+	}()
+
+	timer, stop := deadlineTimer(deadline)
+	defer stop()
+
+	select {
+	case err := <-written:
+		if err != nil {
+			_ = p.Remove(id)
+			return HTTPErrorIO
+		}
+		return nil
+	case <-timer:
+		_ = p.Remove(id)
+		return HTTPErrorDeadlineReached
+	}
+}
+
+// Wait ends the body of the given requests and waits for their responses until the
+// deadline is reached. It returns the status of each request, in the order of the ids.
+func (p *HTTPSet) Wait(ids []int16, deadline *time.Time) []RequestStatus {
+	statuses := make([]RequestStatus, len(ids))
+	requests := make([]*Request, len(ids))
+
+	p.Lock()
+	for i, id := range ids {
+		requests[i] = p.reqs[id]
+		if requests[i] != nil {
+			requests[i].finishBody(p.client)
+		}
+	}
+	p.Unlock()
+
+	timer, stop := deadlineTimer(deadline)
+	defer stop()
+
+	for i, req := range requests {
+		if req == nil {
+			statuses[i] = RequestStatus{Error: HTTPErrorInvalid}
+			continue
+		}
+
+		select {
+		case <-req.done:
+		case <-timer:
+			// the deadline is reached for all the requests still in progress
+			select {
+			case <-req.done:
+			default:
+				statuses[i] = RequestStatus{Error: HTTPErrorDeadlineReached}
+				continue
+			}
+		}
+
+		if req.err != nil {
+			_ = p.Remove(ids[i])
+			statuses[i] = RequestStatus{Error: HTTPErrorIO}
+			continue
+		}
+		statuses[i] = RequestStatus{
+			Finished:   true,
+			StatusCode: uint16(req.response.StatusCode),
+		}
+	}
+
+	return statuses
+}
+
+// ResponseHeaders returns the headers of the response of the given request as name and
+// value pairs, or no header if the response is not received yet.
+func (p *HTTPSet) ResponseHeaders(id int16) (headers [][2][]byte) {
+	response := p.response(id)
+	if response == nil {
+		return [][2][]byte{}
+	}
+
+	names := make([]string, 0, len(response.Header))
+	for name := range response.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers = make([][2][]byte, 0, len(names))
+	for _, name := range names {
+		for _, value := range response.Header[name] {
+			// header names are case insensitive and lower case in http/2
+			headers = append(headers, [2][]byte{[]byte(strings.ToLower(name)), []byte(value)})
+		}
+	}
+	return headers
+}
+
+// ReadBody reads the next bytes of the response body of the given request into the buffer,
+// waiting for the response first if needed. It returns 0 once the body is fully read, in
+// which case the request is removed. If the deadline is reached the request is removed.
+func (p *HTTPSet) ReadBody(id int16, buffer []byte, deadline *time.Time) (n int, err error) {
+	statuses := p.Wait([]int16{id}, deadline)
+	if !statuses[0].Finished {
+		if statuses[0].Error == HTTPErrorDeadlineReached {
+			_ = p.Remove(id)
+		}
+		return 0, statuses[0].Error
+	}
+
+	response := p.response(id)
+	if response == nil {
+		return 0, HTTPErrorInvalid
+	}
+
+	type readResult struct {
+		n   int
+		err error
+	}
+	read := make(chan readResult, 1)
+	go func() {
+		// read into a separate buffer since the read may outlive the deadline
+		chunk := make([]byte, len(buffer))
+		var n int
+		var err error
+		for n == 0 && err == nil && len(chunk) > 0 {
+			n, err = response.Body.Read(chunk)
+		}
+		copy(buffer, chunk[:n])
+		read <- readResult{n: n, err: err}
+	}()
+
+	timer, stop := deadlineTimer(deadline)
+	defer stop()
+
+	select {
+	case result := <-read:
+		switch {
+		case result.n > 0:
+			return result.n, nil
+		case result.err == nil, errors.Is(result.err, io.EOF):
+			_ = p.Remove(id)
+			return 0, nil
+		default:
+			_ = p.Remove(id)
+			return 0, HTTPErrorIO
+		}
+	case <-timer:
+		_ = p.Remove(id)
+		return 0, HTTPErrorDeadlineReached
+	}
+}
+
+// response returns the response of the given request, or nil if it is not received yet
+func (p *HTTPSet) response(id int16) *http.Response {
+	p.Lock()
+	req := p.reqs[id]
+	dispatched := req != nil && req.dispatched
+	p.Unlock()
+
+	if !dispatched {
+		return nil
+	}
+	select {
+	case <-req.done:
+		return req.response
+	default:
+		return nil
+	}
+}
+
+// deadlineTimer returns a channel receiving once the deadline is reached, or never if
+// there is no deadline, and the function releasing the timer.
+func deadlineTimer(deadline *time.Time) (timer <-chan time.Time, stop func()) {
+	if deadline == nil {
+		return nil, func() {}
+	}
+	t := time.NewTimer(time.Until(*deadline))
+	return t.C, func() { t.Stop() }
+}
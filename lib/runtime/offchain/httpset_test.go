@@ -6,9 +6,14 @@
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,7 +69,7 @@ func TestOffchainRequest_AddHeader(t *testing.T) {
 		headerK, headerV string
 	}{
 		"should_return_invalid_request": {
-			offReq: Request{invalidReq},
+			offReq: Request{Request: invalidReq},
 			err:    errRequestInvalid,
 		},
 		"should_add_header": {
@@ -101,3 +106,121 @@ func TestOffchainRequest_AddHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPSet_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		w.Header().Set("X-Echo-Header", r.Header.Get("X-Test"))
+		w.WriteHeader(http.StatusCreated)
+		_, err = w.Write(body)
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	set := NewHTTPSet()
+	id, err := set.StartRequest(http.MethodPost, server.URL)
+	require.NoError(t, err)
+
+	err = set.Get(id).AddHeader("X-Test", "value")
+	require.NoError(t, err)
+
+	for _, chunk := range []string{"hello ", "world"} {
+		err = set.WriteBody(id, []byte(chunk), nil)
+		require.NoError(t, err)
+	}
+
+	// headers cannot be added once the request is sent
+	err = set.Get(id).AddHeader("X-Late", "value")
+	require.ErrorIs(t, err, errRequestInvalid)
+
+	err = set.WriteBody(id, nil, nil)
+	require.NoError(t, err)
+	err = set.WriteBody(id, []byte("more"), nil)
+	require.ErrorIs(t, err, HTTPErrorInvalid)
+
+	deadline := time.Now().Add(5 * time.Second)
+	statuses := set.Wait([]int16{id, id + 1}, &deadline)
+	expectedStatuses := []RequestStatus{
+		{Finished: true, StatusCode: http.StatusCreated},
+		{Error: HTTPErrorInvalid},
+	}
+	require.Equal(t, expectedStatuses, statuses)
+
+	headers := set.ResponseHeaders(id)
+	assert.Contains(t, headers, [2][]byte{[]byte("x-echo-header"), []byte("value")})
+
+	var body []byte
+	buffer := make([]byte, 4)
+	for {
+		n, err := set.ReadBody(id, buffer, &deadline)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+		body = append(body, buffer[:n]...)
+	}
+	assert.Equal(t, "hello world", string(body))
+
+	// the request is removed once its body is fully read
+	assert.Nil(t, set.Get(id))
+	_, err = set.ReadBody(id, buffer, nil)
+	assert.ErrorIs(t, err, HTTPErrorInvalid)
+}
+
+func TestHTTPSet_Wait(t *testing.T) {
+	t.Parallel()
+
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-unblock
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(func() {
+		close(unblock)
+		server.Close()
+	})
+
+	set := NewHTTPSet()
+	slowID, err := set.StartRequest(http.MethodGet, server.URL+"/slow")
+	require.NoError(t, err)
+	fastID, err := set.StartRequest(http.MethodGet, server.URL+"/fast")
+	require.NoError(t, err)
+	failingID, err := set.StartRequest(http.MethodGet, "http://127.0.0.1:0")
+	require.NoError(t, err)
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	statuses := set.Wait([]int16{slowID, fastID, failingID}, &deadline)
+
+	expectedStatuses := []RequestStatus{
+		{Error: HTTPErrorDeadlineReached},
+		{Finished: true, StatusCode: http.StatusOK},
+		{Error: HTTPErrorIO},
+	}
+	assert.Equal(t, expectedStatuses, statuses)
+	assert.NotNil(t, set.Get(slowID))
+	assert.Nil(t, set.Get(failingID))
+
+	err = set.Remove(slowID)
+	require.NoError(t, err)
+}
+
+func TestRequestStatus_MarshalSCALE(t *testing.T) {
+	t.Parallel()
+
+	statuses := []RequestStatus{
+		{Error: HTTPErrorDeadlineReached},
+		{Error: HTTPErrorIO},
+		{Error: HTTPErrorInvalid},
+		{Finished: true, StatusCode: 404},
+	}
+
+	encoded, err := scale.Marshal(statuses)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4 << 2, 0, 1, 2, 3, 0x94, 0x01}, encoded)
+}
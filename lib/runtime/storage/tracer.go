@@ -0,0 +1,196 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"sync"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+)
+
+// StorageTarget is the target of the traced storage accesses
+const StorageTarget = "state"
+
+// extrinsicIndexKey is the key the runtime stores the index of the extrinsic being applied at,
+// it is deleted once all the extrinsics of the block are applied.
+var extrinsicIndexKey = []byte(":extrinsic_index")
+
+// PhaseKind is the kind of a phase of the block execution
+type PhaseKind uint8
+
+const (
+	// InitializationPhase is the initialization of the block, before its extrinsics are applied
+	InitializationPhase PhaseKind = iota
+	// ApplyExtrinsicPhase is the application of an extrinsic of the block
+	ApplyExtrinsicPhase
+	// FinalizationPhase is the finalization of the block, once its extrinsics are applied
+	FinalizationPhase
+)
+
+// Phase is the phase of the block execution an event is traced in
+type Phase struct {
+	Kind PhaseKind
+	// ExtrinsicIndex is the index of the extrinsic applied in the ApplyExtrinsicPhase
+	ExtrinsicIndex uint32
+}
+
+// TraceEvent is a storage access or a log message traced during the execution of a block
+type TraceEvent struct {
+	Phase  Phase
+	Target string
+	Values map[string]string
+}
+
+// Tracer records the storage accesses and the log messages of the runtime while executing a
+// block, along with the phase of the block execution they happen in. The phase is tracked
+// with the index of the extrinsic being applied, which is stored by the runtime.
+type Tracer struct {
+	mtx         sync.Mutex
+	targets     []string
+	keyPrefixes [][]byte
+	phase       Phase
+	events      []TraceEvent
+}
+
+// NewTracer returns a tracer recording the events of the given targets and of their sub targets,
+// such that 'runtime' records 'runtime::staking', and the storage accesses of the keys starting
+// with one of the key prefixes. All the events are recorded if targets is empty, and all the
+// storage accesses if keyPrefixes is empty. The storage accesses are recorded with the
+// StorageTarget target, those of a child trie are filtered by its child storage key.
+func NewTracer(targets []string, keyPrefixes [][]byte) *Tracer {
+	return &Tracer{
+		targets:     targets,
+		keyPrefixes: keyPrefixes,
+	}
+}
+
+// Enabled returns true if the events of the target are recorded, it returns false for a nil tracer.
+func (t *Tracer) Enabled(target string) bool {
+	if t == nil {
+		return false
+	}
+
+	if len(t.targets) == 0 {
+		return true
+	}
+
+	for _, tracedTarget := range t.targets {
+		if target == tracedTarget || strings.HasPrefix(target, tracedTarget+"::") {
+			return true
+		}
+	}
+	return false
+}
+
+// TraceLog records a log message of the runtime if its target is traced
+func (t *Tracer) TraceLog(target string, level log.Level, message string) {
+	if !t.Enabled(target) {
+		return
+	}
+
+	t.record(target, map[string]string{
+		"level":   level.String(),
+		"message": message,
+	})
+}
+
+// Events returns the recorded events in their order of occurrence
+func (t *Tracer) Events() []TraceEvent {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	events := make([]TraceEvent, len(t.events))
+	copy(events, t.events)
+	return events
+}
+
+func (t *Tracer) record(target string, values map[string]string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.events = append(t.events, TraceEvent{
+		Phase:  t.phase,
+		Target: target,
+		Values: values,
+	})
+}
+
+// traceStorage records an access to the key of the state trie, the key being a key prefix if
+// isPrefix is true. The access to the extrinsic index key moves the tracer to the next phase.
+func (t *Tracer) traceStorage(method string, key, value []byte, isPrefix bool) {
+	if t == nil {
+		return
+	}
+
+	if t.Enabled(StorageTarget) && t.tracesKey(key, isPrefix) {
+		values := map[string]string{
+			"method": method,
+			"key":    common.BytesToHex(key),
+		}
+		if value != nil {
+			values["value"] = common.BytesToHex(value)
+		}
+		t.record(StorageTarget, values)
+	}
+
+	if !bytes.Equal(key, extrinsicIndexKey) || isPrefix {
+		return
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	switch {
+	case method == "Put" && len(value) == 4:
+		t.phase = Phase{Kind: ApplyExtrinsicPhase, ExtrinsicIndex: binary.LittleEndian.Uint32(value)}
+	case method == "Delete":
+		t.phase = Phase{Kind: FinalizationPhase}
+	}
+}
+
+// traceChildStorage records an access to the key of the child trie located at keyToChild,
+// the access being to the whole child trie if the key is nil.
+func (t *Tracer) traceChildStorage(method string, keyToChild, key, value []byte) {
+	if !t.Enabled(StorageTarget) || !t.tracesKey(childStorageKey(keyToChild), false) {
+		return
+	}
+
+	values := map[string]string{
+		"method":   method,
+		"childKey": common.BytesToHex(keyToChild),
+	}
+	if key != nil {
+		values["key"] = common.BytesToHex(key)
+	}
+	if value != nil {
+		values["value"] = common.BytesToHex(value)
+	}
+	t.record(StorageTarget, values)
+}
+
+// tracesKey returns true if the key starts with one of the traced key prefixes. A key being a
+// prefix is traced as well if one of the traced key prefixes starts with it.
+func (t *Tracer) tracesKey(key []byte, isPrefix bool) bool {
+	if len(t.keyPrefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range t.keyPrefixes {
+		if bytes.HasPrefix(key, prefix) || (isPrefix && bytes.HasPrefix(prefix, key)) {
+			return true
+		}
+	}
+	return false
+}
+
+func childStorageKey(keyToChild []byte) []byte {
+	key := make([]byte, len(inmemory.ChildStorageKeyPrefix)+len(keyToChild))
+	copy(key, inmemory.ChildStorageKeyPrefix)
+	copy(key[len(inmemory.ChildStorageKeyPrefix):], keyToChild)
+	return key
+}
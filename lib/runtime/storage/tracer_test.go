@@ -0,0 +1,144 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/log"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrieState_Tracer(t *testing.T) {
+	t.Parallel()
+
+	ts := NewTrieState(inmemory_trie.NewEmptyTrie())
+	ts.SetVersion(trie.V0)
+	tracer := NewTracer(nil, [][]byte{{1}})
+	ts.SetTracer(tracer)
+	assert.Equal(t, tracer, ts.Tracer())
+
+	// block initialization
+	ts.StartTransaction()
+	err := ts.Put([]byte{1, 1}, []byte{11})
+	require.NoError(t, err)
+	err = ts.Put(extrinsicIndexKey, []byte{0, 0, 0, 0})
+	require.NoError(t, err)
+
+	// first extrinsic
+	value := ts.Get([]byte{1, 1})
+	assert.Equal(t, []byte{11}, value)
+	err = ts.Put([]byte{2, 2}, []byte{22})
+	require.NoError(t, err)
+	err = ts.Put(extrinsicIndexKey, []byte{1, 0, 0, 0})
+	require.NoError(t, err)
+
+	// second extrinsic
+	err = ts.ClearPrefix([]byte{1})
+	require.NoError(t, err)
+	err = ts.Delete(extrinsicIndexKey)
+	require.NoError(t, err)
+
+	// block finalization
+	err = ts.SetChildStorage([]byte("child"), []byte{1}, []byte{1})
+	require.NoError(t, err)
+	_ = ts.Get([]byte{1, 2})
+
+	expected := []TraceEvent{
+		{
+			Phase:  Phase{Kind: InitializationPhase},
+			Target: StorageTarget,
+			Values: map[string]string{"method": "Put", "key": "0x0101", "value": "0x0b"},
+		},
+		{
+			Phase:  Phase{Kind: ApplyExtrinsicPhase},
+			Target: StorageTarget,
+			Values: map[string]string{"method": "Get", "key": "0x0101", "value": "0x0b"},
+		},
+		{
+			Phase:  Phase{Kind: ApplyExtrinsicPhase, ExtrinsicIndex: 1},
+			Target: StorageTarget,
+			Values: map[string]string{"method": "ClearPrefix", "key": "0x01"},
+		},
+		{
+			Phase:  Phase{Kind: FinalizationPhase},
+			Target: StorageTarget,
+			Values: map[string]string{"method": "Get", "key": "0x0102"},
+		},
+	}
+	assert.Equal(t, expected, tracer.Events())
+
+	ts.SetTracer(nil)
+	_ = ts.Get([]byte{1, 1})
+	assert.Nil(t, ts.Tracer())
+	assert.Len(t, tracer.Events(), len(expected))
+}
+
+func TestTracer_Enabled(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		tracer  *Tracer
+		target  string
+		enabled bool
+	}{
+		"nil_tracer": {
+			target: StorageTarget,
+		},
+		"all_targets": {
+			tracer:  NewTracer(nil, nil),
+			target:  "runtime::staking",
+			enabled: true,
+		},
+		"traced_target": {
+			tracer:  NewTracer([]string{"runtime", StorageTarget}, nil),
+			target:  StorageTarget,
+			enabled: true,
+		},
+		"traced_sub_target": {
+			tracer:  NewTracer([]string{"runtime"}, nil),
+			target:  "runtime::staking",
+			enabled: true,
+		},
+		"target_prefix_not_traced": {
+			tracer: NewTracer([]string{"runtime"}, nil),
+			target: "runtimes",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			enabled := testCase.tracer.Enabled(testCase.target)
+			assert.Equal(t, testCase.enabled, enabled)
+		})
+	}
+}
+
+func TestTracer_TraceLog(t *testing.T) {
+	t.Parallel()
+
+	ts := NewTrieState(inmemory_trie.NewEmptyTrie())
+	tracer := NewTracer([]string{"runtime"}, nil)
+	ts.SetTracer(tracer)
+
+	tracer.TraceLog("runtime::staking", log.Debug, "staking")
+	tracer.TraceLog("babe", log.Info, "babe")
+	err := ts.Put([]byte{1}, []byte{1})
+	require.NoError(t, err)
+	err = ts.SetChildStorage([]byte("child"), []byte{1}, []byte{1})
+	require.NoError(t, err)
+
+	expected := []TraceEvent{{
+		Phase:  Phase{Kind: InitializationPhase},
+		Target: "runtime::staking",
+		Values: map[string]string{"level": "DEBUG", "message": "staking"},
+	}}
+	assert.Equal(t, expected, tracer.Events())
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+import (
+	"slices"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// IndexOperation is a transaction index operation requested by the runtime
+// while executing the extrinsics of a block.
+type IndexOperation struct {
+	// Extrinsic is the index of the extrinsic in the block body.
+	Extrinsic uint32
+	// Hash is the hash of the indexed data.
+	Hash common.Hash
+	// Size is the size of the indexed data, which is the end of the extrinsic.
+	// It is zero for renew operations.
+	Size uint32
+	// Renew is true if the operation renews data indexed in a previous block.
+	Renew bool
+}
+
+// IndexTransaction records that the last size bytes of the extrinsic
+// at the given index of the block body are indexed under the given hash.
+func (t *TrieState) IndexTransaction(extrinsic, size uint32, hash common.Hash) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.indexOperations = append(t.indexOperations, IndexOperation{
+		Extrinsic: extrinsic,
+		Hash:      hash,
+		Size:      size,
+	})
+}
+
+// RenewTransaction records that the data indexed under the given hash
+// is renewed by the extrinsic at the given index of the block body.
+func (t *TrieState) RenewTransaction(extrinsic uint32, hash common.Hash) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.indexOperations = append(t.indexOperations, IndexOperation{
+		Extrinsic: extrinsic,
+		Hash:      hash,
+		Renew:     true,
+	})
+}
+
+// IndexOperations returns the transaction index operations recorded
+// since the trie state was created.
+func (t *TrieState) IndexOperations() []IndexOperation {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+
+	return slices.Clone(t.indexOperations)
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrieState_IndexOperations(t *testing.T) {
+	t.Parallel()
+
+	ts := NewTrieState(inmemory_trie.NewEmptyTrie())
+	assert.Empty(t, ts.IndexOperations())
+
+	ts.IndexTransaction(1, 32, common.Hash{1})
+	ts.RenewTransaction(2, common.Hash{2})
+
+	// index operations are kept when rolling back storage transactions
+	ts.StartTransaction()
+	ts.IndexTransaction(3, 16, common.Hash{3})
+	ts.RollbackTransaction()
+
+	expected := []IndexOperation{
+		{Extrinsic: 1, Hash: common.Hash{1}, Size: 32},
+		{Extrinsic: 2, Hash: common.Hash{2}, Renew: true},
+		{Extrinsic: 3, Hash: common.Hash{3}, Size: 16},
+	}
+	operations := ts.IndexOperations()
+	assert.Equal(t, expected, operations)
+
+	// the returned operations are a copy
+	operations[0].Size = 0
+	assert.Equal(t, expected, ts.IndexOperations())
+}
@@ -23,9 +23,12 @@
 // If the execution of the call is successful, the changes will be applied to
 // the current `state`
 type TrieState struct {
-	mtx          sync.RWMutex
-	state        trie.Trie
-	transactions *list.List
+	mtx             sync.RWMutex
+	state           trie.Trie
+	transactions    *list.List
+	indexOperations []IndexOperation
+	// tracer records the storage accesses while tracing a block, it is nil otherwise
+	tracer *Tracer
 }
 
 // NewTrieState initialises and returns a new TrieState instance
@@ -96,6 +99,20 @@ func (t *TrieState) CommitTransaction() {
 	}
 }
 
+// SetTracer sets the tracer recording the storage accesses, a nil tracer disables the tracing
+func (t *TrieState) SetTracer(tracer *Tracer) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.tracer = tracer
+}
+
+// Tracer returns the tracer recording the storage accesses, or nil if they are not traced
+func (t *TrieState) Tracer() *Tracer {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	return t.tracer
+}
+
 // Trie returns the TrieState's underlying trie
 func (t *TrieState) Trie() trie.Trie {
 	t.mtx.RLock()
@@ -108,6 +125,7 @@ func (t *TrieState) Trie() trie.Trie {
 func (t *TrieState) Put(key, value []byte) (err error) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceStorage("Put", key, value, false)
 
 	// If we have running transactions we apply the change there,
 	// if not, we apply the changes directly on our state trie
@@ -124,6 +142,12 @@ func (t *TrieState) Get(key []byte) []byte {
 	t.mtx.RLock()
 	defer t.mtx.RUnlock()
 
+	value := t.get(key)
+	t.tracer.traceStorage("Get", key, value, false)
+	return value
+}
+
+func (t *TrieState) get(key []byte) []byte {
 	// If we find the key or it is deleted return from latest transaction
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		val, deleted := currentTx.get(string(key))
@@ -160,6 +184,7 @@ func (t *TrieState) Has(key []byte) bool {
 func (t *TrieState) Delete(key []byte) (err error) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceStorage("Delete", key, nil, false)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		t.getCurrentTransaction().delete(string(key))
@@ -214,6 +239,7 @@ func (t *TrieState) NextKey(key []byte) []byte {
 func (t *TrieState) ClearPrefix(prefix []byte) error {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceStorage("ClearPrefix", prefix, nil, true)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		keysOnState := make([]string, 0)
@@ -234,6 +260,7 @@ func (t *TrieState) ClearPrefixLimit(prefix []byte, limit uint32) (
 	loops uint32, deleted uint32, allDeleted bool, err error) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceStorage("ClearPrefixLimit", prefix, nil, true)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		keysOnState := make([]string, 0)
@@ -276,6 +303,7 @@ func (t *TrieState) TrieEntries() map[string][]byte {
 func (t *TrieState) SetChildStorage(keyToChild, key, value []byte) error {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceChildStorage("Put", keyToChild, key, value)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		keyToChildStr := string(keyToChild)
@@ -304,6 +332,14 @@ func (t *TrieState) GetChildStorage(keyToChild, key []byte) ([]byte, error) {
 	t.mtx.RLock()
 	defer t.mtx.RUnlock()
 
+	value, err := t.getChildStorage(keyToChild, key)
+	if err == nil {
+		t.tracer.traceChildStorage("Get", keyToChild, key, value)
+	}
+	return value, err
+}
+
+func (t *TrieState) getChildStorage(keyToChild, key []byte) ([]byte, error) {
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		val, deleted := currentTx.getFromChild(string(keyToChild), string(key))
 		if val != nil || deleted {
@@ -319,6 +355,7 @@ func (t *TrieState) GetChildStorage(keyToChild, key []byte) ([]byte, error) {
 func (t *TrieState) DeleteChild(keyToChild []byte) error {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceChildStorage("DeleteChild", keyToChild, nil, nil)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		currentTx.delete(string(keyToChild))
@@ -333,6 +370,7 @@ func (t *TrieState) DeleteChildLimit(key []byte, limit *[]byte) (
 	deleted uint32, allDeleted bool, err error) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceChildStorage("DeleteChildLimit", key, nil, nil)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		deleteLimit := -1
@@ -400,6 +438,7 @@ func (t *TrieState) DeleteChildLimit(key []byte, limit *[]byte) (
 func (t *TrieState) ClearChildStorage(keyToChild, key []byte) error {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceChildStorage("Delete", keyToChild, key, nil)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		keyToChildStr := string(keyToChild)
@@ -420,6 +459,7 @@ func (t *TrieState) ClearChildStorage(keyToChild, key []byte) error {
 func (t *TrieState) ClearPrefixInChild(keyToChild, prefix []byte) error {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceChildStorage("ClearPrefix", keyToChild, prefix, nil)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		child, err := t.state.GetChild(keyToChild)
@@ -455,6 +495,7 @@ func (t *TrieState) ClearPrefixInChild(keyToChild, prefix []byte) error {
 func (t *TrieState) ClearPrefixInChildWithLimit(keyToChild, prefix []byte, limit uint32) (uint32, uint32, bool, error) {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
+	t.tracer.traceChildStorage("ClearPrefixLimit", keyToChild, prefix, nil)
 
 	if currentTx := t.getCurrentTransaction(); currentTx != nil {
 		child, err := t.state.GetChild(keyToChild)
@@ -604,9 +645,10 @@ func (t *TrieState) LoadCodeHash() (common.Hash, error) {
 	return common.Blake2bHash(code)
 }
 
-// GetChangedNodeHashes returns the two sets of hashes for all nodes
-// inserted and deleted in the state trie since the last block produced (trie snapshot).
-func (t *TrieState) GetChangedNodeHashes() (inserted, deleted map[common.Hash]struct{}, err error) {
+// GetChangedNodeHashes returns the hashes of all nodes inserted and deleted in the state trie
+// since the last block produced (trie snapshot), with the number of times each of them was
+// inserted or deleted.
+func (t *TrieState) GetChangedNodeHashes() (inserted, deleted map[common.Hash]uint32, err error) {
 	t.mtx.RLock()
 	defer t.mtx.RUnlock()
 
@@ -62,4 +62,7 @@ type Context struct {
 	SigVerifier     *crypto.SignatureVerifier
 	OffchainHTTPSet *offchain.HTTPSet
 	Version         *Version
+	// MaxMemoryPages is the maximum number of wasm pages the allocator grows the memory to
+	MaxMemoryPages uint32
+	LogFilter      *LogFilter
 }
@@ -32,7 +32,8 @@ type Version struct {
 }
 
 var (
-	ErrDecodingVersionField = errors.New("decoding version field")
+	ErrDecodingVersionField      = errors.New("decoding version field")
+	ErrOffchainWorkerAPINotFound = errors.New("offchainWorkerAPI not found")
 )
 
 // TaggedTransactionQueueVersion returns the TaggedTransactionQueue API version
@@ -49,6 +50,20 @@ func (v Version) TaggedTransactionQueueVersion() (txQueueVersion uint32, err err
 	return 0, errors.New("taggedTransactionQueueAPI not found")
 }
 
+// OffchainWorkerAPIVersion returns the OffchainWorkerApi API version
+func (v Version) OffchainWorkerAPIVersion() (offchainWorkerVersion uint32, err error) {
+	encodedOffchainWorkerAPI, err := common.Blake2b8([]byte("OffchainWorkerApi"))
+	if err != nil {
+		return 0, fmt.Errorf("getting blake2b8: %s", err)
+	}
+	for _, apiItem := range v.APIItems {
+		if apiItem.Name == encodedOffchainWorkerAPI {
+			return apiItem.Ver, nil
+		}
+	}
+	return 0, ErrOffchainWorkerAPINotFound
+}
+
 // DecodeVersion scale decodes the encoded version data.
 // For older version data with missing fields (such as `transaction_version`)
 // the missing field is set to its zero value (such as `0`).
@@ -205,3 +205,22 @@ func Test_Version_Scale(t *testing.T) {
 		})
 	}
 }
+
+func Test_Version_OffchainWorkerAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	offchainWorkerAPIName := [8]byte{0xf7, 0x8b, 0x27, 0x8b, 0xe5, 0x3f, 0x45, 0x4c}
+
+	version := Version{
+		APIItems: []APIItem{
+			{Name: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, Ver: 1},
+			{Name: offchainWorkerAPIName, Ver: 2},
+		},
+	}
+	apiVersion, err := version.OffchainWorkerAPIVersion()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), apiVersion)
+
+	_, err = Version{}.OffchainWorkerAPIVersion()
+	assert.ErrorIs(t, err, ErrOffchainWorkerAPINotFound)
+}
@@ -0,0 +1,230 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero_runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/tetratelabs/wazero"
+)
+
+// DefaultCompilationCacheSize is the default maximum size in bytes of the compilation cache.
+const DefaultCompilationCacheSize = 1 << 30
+
+const wazeroModulePath = "github.com/tetratelabs/wazero"
+
+// CompilationCache is a cache of the compiled runtime codes stored on disk and shared
+// by the instances of the node. Each runtime code is compiled in its own directory, keyed
+// by the wazero version and the code hash. The least recently used directories are removed
+// once the size of the cache exceeds its maximum size.
+type CompilationCache struct {
+	directory string
+	version   string
+	maxSize   int64
+	// opened is the number of instances using the compiled code of each
+	// code hash, the compiled codes in use are not removed from the cache.
+	opened map[common.Hash]uint
+	mutex  sync.Mutex
+}
+
+// NewCompilationCache returns a compilation cache stored in the given directory,
+// with a maximum size of maxSize bytes.
+func NewCompilationCache(directory string, maxSize int64) *CompilationCache {
+	return &CompilationCache{
+		directory: directory,
+		version:   wazeroVersion(),
+		maxSize:   maxSize,
+		opened:    make(map[common.Hash]uint),
+	}
+}
+
+// wazeroVersion returns the version of the wazero module the node is built with.
+func wazeroVersion() string {
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, module := range buildInfo.Deps {
+		if module.Path != wazeroModulePath {
+			continue
+		}
+		if module.Replace != nil {
+			return module.Replace.Version
+		}
+		return module.Version
+	}
+	return "unknown"
+}
+
+func (c *CompilationCache) codeDirectory(codeHash common.Hash) string {
+	return filepath.Join(c.directory, c.version, codeHash.String())
+}
+
+// codeHash returns the given code hash, or the hash of the runtime code if it is empty.
+func (*CompilationCache) codeHash(code []byte, codeHash common.Hash) (common.Hash, error) {
+	if !codeHash.IsEmpty() {
+		return codeHash, nil
+	}
+
+	codeHash, err := common.Blake2bHash(code)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("hashing runtime code: %w", err)
+	}
+	return codeHash, nil
+}
+
+// open returns the wazero compilation cache of the runtime code hash, and marks the runtime
+// code as recently used. The compilation cache must be closed with close once unused.
+func (c *CompilationCache) open(codeHash common.Hash) (wazero.CompilationCache, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	directory := c.codeDirectory(codeHash)
+	err := os.MkdirAll(directory, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("creating compilation cache directory: %w", err)
+	}
+
+	now := time.Now()
+	err = os.Chtimes(directory, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("marking compilation cache directory as used: %w", err)
+	}
+
+	cache, err := wazero.NewCompilationCacheWithDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	c.opened[codeHash]++
+	return cache, nil
+}
+
+// close closes the wazero compilation cache opened for the runtime code hash,
+// such that its compiled code can be removed from the cache.
+func (c *CompilationCache) close(codeHash common.Hash, cache wazero.CompilationCache) error {
+	c.mutex.Lock()
+	c.opened[codeHash]--
+	if c.opened[codeHash] == 0 {
+		delete(c.opened, codeHash)
+	}
+	c.mutex.Unlock()
+
+	return cache.Close(context.Background())
+}
+
+type cachedCode struct {
+	directory string
+	size      int64
+	lastUsed  time.Time
+}
+
+// evict removes the compiled codes of the other wazero versions and the least recently
+// used compiled codes until the size of the cache is below its maximum size. The compiled
+// code of the given code hash and the compiled codes opened by instances are kept.
+func (c *CompilationCache) evict(codeHash common.Hash) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	versions, err := os.ReadDir(c.directory)
+	if err != nil {
+		return fmt.Errorf("reading compilation cache directory: %w", err)
+	}
+
+	for _, version := range versions {
+		if version.Name() == c.version {
+			continue
+		}
+		err = os.RemoveAll(filepath.Join(c.directory, version.Name()))
+		if err != nil {
+			return fmt.Errorf("removing compiled codes of wazero version %s: %w", version.Name(), err)
+		}
+	}
+
+	versionDirectory := filepath.Join(c.directory, c.version)
+	entries, err := os.ReadDir(versionDirectory)
+	if err != nil {
+		return fmt.Errorf("reading compilation cache directory: %w", err)
+	}
+
+	keptDirectories := map[string]struct{}{
+		c.codeDirectory(codeHash): {},
+	}
+	for openedCodeHash := range c.opened {
+		keptDirectories[c.codeDirectory(openedCodeHash)] = struct{}{}
+	}
+
+	var totalSize int64
+	codes := make([]cachedCode, 0, len(entries))
+	for _, entry := range entries {
+		code := cachedCode{directory: filepath.Join(versionDirectory, entry.Name())}
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("getting compiled code information: %w", err)
+		}
+		code.lastUsed = info.ModTime()
+
+		code.size, err = directorySize(code.directory)
+		if err != nil {
+			return fmt.Errorf("getting compiled code size: %w", err)
+		}
+
+		totalSize += code.size
+		_, kept := keptDirectories[code.directory]
+		if !kept {
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].lastUsed.Before(codes[j].lastUsed)
+	})
+
+	for _, code := range codes {
+		if totalSize <= c.maxSize {
+			break
+		}
+
+		err = os.RemoveAll(code.directory)
+		if err != nil {
+			return fmt.Errorf("removing compiled code: %w", err)
+		}
+		totalSize -= code.size
+		logger.Debugf("removed compiled code %s from the compilation cache", filepath.Base(code.directory))
+	}
+
+	return nil
+}
+
+func directorySize(directory string) (size int64, err error) {
+	err = filepath.WalkDir(directory, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero_runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompilationCache_open(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCompilationCache(t.TempDir(), DefaultCompilationCacheSize)
+	codeHash := common.Hash{1}
+
+	wazeroCache, err := cache.open(codeHash)
+	require.NoError(t, err)
+
+	info, err := os.Stat(cache.codeDirectory(codeHash))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, map[common.Hash]uint{codeHash: 1}, cache.opened)
+
+	err = cache.close(codeHash, wazeroCache)
+	require.NoError(t, err)
+	assert.Empty(t, cache.opened)
+}
+
+func TestCompilationCache_evict(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	cache := NewCompilationCache(directory, 25)
+
+	// writeCode writes a compiled code of the given size last used at the given time
+	writeCode := func(codeDirectory string, size int, lastUsed time.Time) {
+		err := os.MkdirAll(codeDirectory, 0o700)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(codeDirectory, "module"), make([]byte, size), 0o600)
+		require.NoError(t, err)
+		err = os.Chtimes(codeDirectory, lastUsed, lastUsed)
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	oldVersionDirectory := filepath.Join(directory, "v0.0.0", common.Hash{1}.String())
+	writeCode(oldVersionDirectory, 1, now)
+	writeCode(cache.codeDirectory(common.Hash{1}), 10, now.Add(-3*time.Hour))
+	writeCode(cache.codeDirectory(common.Hash{2}), 10, now.Add(-2*time.Hour))
+	writeCode(cache.codeDirectory(common.Hash{3}), 10, now.Add(-time.Hour))
+	writeCode(cache.codeDirectory(common.Hash{4}), 10, now)
+
+	// the least recently used code is kept when it is the current code
+	err := cache.evict(common.Hash{1})
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, filepath.Dir(oldVersionDirectory))
+	assert.DirExists(t, cache.codeDirectory(common.Hash{1}))
+	assert.NoDirExists(t, cache.codeDirectory(common.Hash{2}))
+	assert.NoDirExists(t, cache.codeDirectory(common.Hash{3}))
+	assert.DirExists(t, cache.codeDirectory(common.Hash{4}))
+}
+
+func TestCompilationCache_evict_opened(t *testing.T) {
+	t.Parallel()
+
+	cache := NewCompilationCache(t.TempDir(), 0)
+
+	openedCache, err := cache.open(common.Hash{1})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		err := cache.close(common.Hash{1}, openedCache)
+		require.NoError(t, err)
+	})
+	err = os.WriteFile(filepath.Join(cache.codeDirectory(common.Hash{1}), "module"), make([]byte, 10), 0o600)
+	require.NoError(t, err)
+
+	closedCache, err := cache.open(common.Hash{2})
+	require.NoError(t, err)
+	err = cache.close(common.Hash{2}, closedCache)
+	require.NoError(t, err)
+
+	// the compiled code still opened by an instance is kept
+	err = cache.evict(common.Hash{3})
+	require.NoError(t, err)
+
+	assert.DirExists(t, cache.codeDirectory(common.Hash{1}))
+	assert.NoDirExists(t, cache.codeDirectory(common.Hash{2}))
+}
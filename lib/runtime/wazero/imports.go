@@ -8,12 +8,14 @@
 	"context"
 	"crypto/rand"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
 	"reflect"
 	"time"
 
+	"github.com/ChainSafe/gossamer/dot/types"
 	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -21,6 +23,8 @@
 	"github.com/ChainSafe/gossamer/lib/crypto/secp256k1"
 	"github.com/ChainSafe/gossamer/lib/crypto/sr25519"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
+	rtstorage "github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	inmemory_trie "github.com/ChainSafe/gossamer/pkg/trie/inmemory"
@@ -34,6 +38,13 @@
 		log.AddContext("module", "wazero"),
 	)
 
+	// runtimeLogger logs the messages of the runtime, which are filtered
+	// by the log filter of the runtime context instead of its level.
+	runtimeLogger = log.NewFromGlobal(
+		log.AddContext("pkg", "runtime"),
+		log.SetLevel(log.Trace),
+	)
+
 	emptyByteVectorEncoded []byte = scale.MustMarshal([]byte{})
 	noneEncoded            []byte = []byte{0x00}
 	allZeroesBytes                = [32]byte{}
@@ -91,26 +102,75 @@ func mustWrite(m api.Module, allocator runtime.Allocator, data []byte) (pointerS
 	return pointerSize
 }
 
-func ext_logging_log_version_1(_ context.Context, m api.Module, level int32, targetData, msgData uint64) {
+// runtimeLogLevels are the node log levels of the runtime log levels, from error to trace
+var runtimeLogLevels = [...]log.Level{log.Critical, log.Warn, log.Info, log.Debug, log.Trace}
+
+func ext_logging_log_version_1(ctx context.Context, m api.Module, level int32, targetData, msgData uint64) {
 	target := string(read(m, targetData))
+	if level < 0 || int(level) >= len(runtimeLogLevels) {
+		msg := string(read(m, msgData))
+		runtimeLogger.Errorf("level=%d target=%s message=%s", int(level), target, msg)
+		return
+	}
+
+	// the message is only read from the memory if it is logged or traced
+	logLevel := runtimeLogLevels[level]
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	tracer := storageTracer(rtCtx)
+	traced := tracer.Enabled(target)
+	logged := rtCtx.LogFilter == nil || rtCtx.LogFilter.Enabled(target, logLevel)
+	if !traced && !logged {
+		return
+	}
+
 	msg := string(read(m, msgData))
+	if traced {
+		tracer.TraceLog(target, logLevel, msg)
+	}
+	if !logged {
+		return
+	}
 
 	line := fmt.Sprintf("target=%s message=%s", target, msg)
 
-	switch int(level) {
-	case 0:
-		logger.Critical(line)
-	case 1:
-		logger.Warn(line)
-	case 2:
-		logger.Info(line)
-	case 3:
-		logger.Debug(line)
-	case 4:
-		logger.Trace(line)
+	switch logLevel {
+	case log.Critical:
+		runtimeLogger.Critical(line)
+	case log.Warn:
+		runtimeLogger.Warn(line)
+	case log.Info:
+		runtimeLogger.Info(line)
+	case log.Debug:
+		runtimeLogger.Debug(line)
 	default:
-		logger.Errorf("level=%d target=%s message=%s", int(level), target, msg)
+		runtimeLogger.Trace(line)
+	}
+}
+
+// ext_logging_max_level_version_1 returns the highest log level of the messages logged, from
+// 0 for none to 5 for trace, such that the runtime does not produce the messages above it.
+// All the messages are produced while tracing a block, since they are recorded by the tracer.
+func ext_logging_max_level_version_1(ctx context.Context) int32 {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if storageTracer(rtCtx) != nil {
+		return int32(log.Trace)
+	}
+
+	if rtCtx.LogFilter == nil {
+		return int32(log.Debug)
+	}
+
+	// the runtime errors are logged at the critical level
+	return int32(max(rtCtx.LogFilter.MaxLevel(), log.Error))
+}
+
+// storageTracer returns the tracer of the runtime storage, or nil if the block execution is not traced
+func storageTracer(rtCtx *runtime.Context) *rtstorage.Tracer {
+	trieState, ok := rtCtx.Storage.(*rtstorage.TrieState)
+	if !ok {
+		return nil
 	}
+	return trieState.Tracer()
 }
 
 func ext_crypto_ecdsa_generate_version_1(ctx context.Context, m api.Module, _ uint32, _ uint64) uint32 {
@@ -305,8 +365,8 @@ func ext_crypto_ed25519_verify_version_1(ctx context.Context, m api.Module, sig
 	if sigVerifier.IsStarted() {
 		signature := crypto.SignatureInfo{
 			PubKey:     pubKey.Encode(),
-			Sign:       signature,
-			Msg:        message,
+			Sign:       bytes.Clone(signature),
+			Msg:        bytes.Clone(message),
 			VerifyFunc: ed25519.VerifySignature,
 		}
 		sigVerifier.Add(&signature)
@@ -416,7 +476,7 @@ func ext_crypto_ecdsa_verify_version_2(ctx context.Context, m api.Module, sig ui
 	if sigVerifier.IsStarted() {
 		signature := crypto.SignatureInfo{
 			PubKey:     pub.Encode(),
-			Sign:       signature,
+			Sign:       bytes.Clone(signature),
 			Msg:        hash[:],
 			VerifyFunc: secp256k1.VerifySignature,
 		}
@@ -662,14 +722,7 @@ func ext_crypto_sr25519_sign_version_1(ctx context.Context, m api.Module, keyTyp
 	return mustWrite(m, rtCtx.Allocator, scale.MustMarshal(&fixedSig))
 }
 
-func ext_crypto_sr25519_verify_version_1(ctx context.Context, m api.Module, sig uint32, msg uint64, key uint32) uint32 {
-	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
-	if rtCtx == nil {
-		panic("nil runtime context")
-	}
-
-	sigVerifier := rtCtx.SigVerifier
-
+func ext_crypto_sr25519_verify_version_1(_ context.Context, m api.Module, sig uint32, msg uint64, key uint32) uint32 {
 	message := read(m, msg)
 	signature, ok := m.Memory().Read(sig, 64)
 	if !ok {
@@ -690,17 +743,8 @@ func ext_crypto_sr25519_verify_version_1(ctx context.Context, m api.Module, sig
 		"pub=%s message=0x%x signature=0x%x",
 		pub.Hex(), message, signature)
 
-	if sigVerifier.IsStarted() {
-		signature := crypto.SignatureInfo{
-			PubKey:     pub.Encode(),
-			Sign:       signature,
-			Msg:        message,
-			VerifyFunc: sr25519.VerifySignature,
-		}
-		sigVerifier.Add(&signature)
-		return 1
-	}
-
+	// the deprecated verification never fails the call so it is not added
+	// to the batch, even when batch verification is started.
 	ok, err = pub.VerifyDeprecated(message, signature)
 	if err != nil || !ok {
 		message := validateSignatureFail
@@ -755,8 +799,8 @@ func ext_crypto_sr25519_verify_version_2(ctx context.Context, m api.Module, sig
 	if sigVerifier.IsStarted() {
 		signature := crypto.SignatureInfo{
 			PubKey:     pub.Encode(),
-			Sign:       signature,
-			Msg:        message,
+			Sign:       bytes.Clone(signature),
+			Msg:        bytes.Clone(message),
 			VerifyFunc: sr25519.VerifySignature,
 		}
 		sigVerifier.Add(&signature)
@@ -777,15 +821,35 @@ func ext_crypto_sr25519_verify_version_2(ctx context.Context, m api.Module, sig
 	return 1
 }
 
-func ext_crypto_start_batch_verify_version_1(_ context.Context, _ api.Module) {
-	// TODO: fix and re-enable signature verification (#1405)
-	// beginBatchVerify(context)
+// ext_crypto_start_batch_verify_version_1 starts a batch in which the signatures passed to the
+// verify functions are verified in the background, the verify functions returning 1 right away.
+func ext_crypto_start_batch_verify_version_1(ctx context.Context, _ api.Module) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	if rtCtx.SigVerifier.IsStarted() {
+		panic("batch verification is already started")
+	}
+	rtCtx.SigVerifier.Start()
 }
 
-func ext_crypto_finish_batch_verify_version_1(_ context.Context, _ api.Module) uint32 {
-	// TODO: fix and re-enable signature verification (#1405)
-	// return finishBatchVerify(context)
-	return 1
+// ext_crypto_finish_batch_verify_version_1 waits for the signatures of the batch to be verified
+// and returns 1 if they are all valid, 0 otherwise.
+func ext_crypto_finish_batch_verify_version_1(ctx context.Context, _ api.Module) uint32 {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	if !rtCtx.SigVerifier.IsStarted() {
+		panic("batch verification is not started")
+	}
+	if rtCtx.SigVerifier.Finish() {
+		return 1
+	}
+	return 0
 }
 
 func ext_trie_blake2_256_root_version_1(ctx context.Context, m api.Module, dataSpan uint64) uint32 {
@@ -1832,23 +1896,43 @@ func ext_offchain_random_seed_version_1(ctx context.Context, m api.Module) uint3
 	return ptr
 }
 
-// TODO Investigate via https://github.com/ChainSafe/gossamer/issues/3986
+// ext_offchain_submit_transaction_version_1 adds the given extrinsic to the transaction pool, once
+// validated by the runtime as a local transaction at the block of the offchain worker.
 func ext_offchain_submit_transaction_version_1(ctx context.Context, m api.Module, data uint64) uint64 {
 	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
 	if rtCtx == nil {
 		panic("nil runtime context")
 	}
 
-	// OK case
-	ret, err := write(m, rtCtx.Allocator, []byte{0})
+	result := []byte{0}
+	extrinsic := types.Extrinsic(bytes.Clone(read(m, data)))
+	pool, _ := ctx.Value(offchainTransactionPoolKey).(*offchainTransactionPool)
+	switch {
+	case rtCtx.Transaction == nil:
+		logger.Error("cannot submit transaction: no transaction pool available")
+		result = []byte{1}
+	case pool == nil:
+		logger.Error("cannot submit transaction: not called from an offchain worker")
+		result = []byte{1}
+	default:
+		err := pool.submit(ctx, rtCtx, extrinsic)
+		if err != nil {
+			logger.Errorf("cannot submit transaction: %s", err)
+			result = []byte{1}
+		}
+	}
+
+	ret, err := write(m, rtCtx.Allocator, result)
 	if err != nil {
 		panic(err)
 	}
 	return ret
 }
 
+// ext_offchain_timestamp_version_1 returns the current timestamp in milliseconds, which is
+// the unit of the deadlines of the offchain http operations and of sleep_until.
 func ext_offchain_timestamp_version_1(_ context.Context, _ api.Module) uint64 {
-	now := time.Now().Unix()
+	now := time.Now().UnixMilli()
 	return uint64(now)
 }
 
@@ -1916,13 +2000,18 @@ func ext_offchain_http_request_add_header_version_1(
 	result := scale.NewResult(nil, nil)
 	resultMode := scale.OK
 
-	err := offchainReq.AddHeader(string(name), string(value))
-	if err != nil {
-		logger.Errorf("failed to add request header: %s", err)
+	if offchainReq == nil {
+		logger.Errorf("failed to add request header: request %d not found", reqID)
 		resultMode = scale.Err
+	} else {
+		err := offchainReq.AddHeader(string(name), string(value))
+		if err != nil {
+			logger.Errorf("failed to add request header: %s", err)
+			resultMode = scale.Err
+		}
 	}
 
-	err = result.Set(resultMode, nil)
+	err := result.Set(resultMode, nil)
 	if err != nil {
 		logger.Errorf("failed to set the result data: %s", err)
 		return uint64(0)
@@ -1943,6 +2032,397 @@ func ext_offchain_http_request_add_header_version_1(
 	return ptr
 }
 
+func ext_offchain_http_request_write_body_version_1(
+	ctx context.Context, m api.Module, reqID uint32, chunkSpan, deadlineSpan uint64) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	chunk := bytes.Clone(read(m, chunkSpan))
+	deadline, err := readOffchainDeadline(m, deadlineSpan)
+	if err != nil {
+		logger.Errorf("failed to decode deadline: %s", err)
+		return uint64(0)
+	}
+
+	err = rtCtx.OffchainHTTPSet.WriteBody(int16(reqID), chunk, deadline)
+	if err != nil {
+		logger.Debugf("failed to write body of request %d: %s", reqID, err)
+	}
+
+	enc, err := encodeOffchainHTTPResult(nil, err)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the result: %s", err)
+		return uint64(0)
+	}
+
+	ptr, err := write(m, rtCtx.Allocator, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return uint64(0)
+	}
+
+	return ptr
+}
+
+func ext_offchain_http_response_wait_version_1(
+	ctx context.Context, m api.Module, idsSpan, deadlineSpan uint64) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	var ids []int16
+	err := scale.Unmarshal(read(m, idsSpan), &ids)
+	if err != nil {
+		logger.Errorf("failed to decode request ids: %s", err)
+		return uint64(0)
+	}
+
+	deadline, err := readOffchainDeadline(m, deadlineSpan)
+	if err != nil {
+		logger.Errorf("failed to decode deadline: %s", err)
+		return uint64(0)
+	}
+
+	statuses := rtCtx.OffchainHTTPSet.Wait(ids, deadline)
+
+	enc, err := scale.Marshal(statuses)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the request statuses: %s", err)
+		return uint64(0)
+	}
+
+	ptr, err := write(m, rtCtx.Allocator, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return uint64(0)
+	}
+
+	return ptr
+}
+
+func ext_offchain_http_response_headers_version_1(
+	ctx context.Context, m api.Module, reqID uint32) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	headers := rtCtx.OffchainHTTPSet.ResponseHeaders(int16(reqID))
+
+	enc, err := scale.Marshal(headers)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the response headers: %s", err)
+		return uint64(0)
+	}
+
+	ptr, err := write(m, rtCtx.Allocator, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return uint64(0)
+	}
+
+	return ptr
+}
+
+func ext_offchain_http_response_read_body_version_1(
+	ctx context.Context, m api.Module, reqID uint32, bufferSpan, deadlineSpan uint64) (pointerSize uint64) {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	deadline, err := readOffchainDeadline(m, deadlineSpan)
+	if err != nil {
+		logger.Errorf("failed to decode deadline: %s", err)
+		return uint64(0)
+	}
+
+	bufferPtr, bufferSize := splitPointerSize(bufferSpan)
+	buffer := make([]byte, bufferSize)
+	n, err := rtCtx.OffchainHTTPSet.ReadBody(int16(reqID), buffer, deadline)
+	if err != nil {
+		logger.Debugf("failed to read body of request %d: %s", reqID, err)
+	} else if !m.Memory().Write(bufferPtr, buffer[:n]) {
+		panic("write overflow")
+	}
+
+	enc, err := encodeOffchainHTTPResult(uint32(n), err)
+	if err != nil {
+		logger.Errorf("failed to scale marshal the result: %s", err)
+		return uint64(0)
+	}
+
+	ptr, err := write(m, rtCtx.Allocator, enc)
+	if err != nil {
+		logger.Errorf("failed to allocate result on memory: %s", err)
+		return uint64(0)
+	}
+
+	return ptr
+}
+
+// readOffchainDeadline reads the optional deadline of an offchain http operation,
+// encoded as a timestamp in milliseconds
+func readOffchainDeadline(m api.Module, deadlineSpan uint64) (*time.Time, error) {
+	var timestamp *uint64
+	err := scale.Unmarshal(read(m, deadlineSpan), &timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if timestamp == nil {
+		return nil, nil
+	}
+
+	deadline := time.UnixMilli(int64(*timestamp))
+	return &deadline, nil
+}
+
+// encodeOffchainHTTPResult encodes the Result<ok, HttpError> of an offchain http operation,
+// where a nil ok value is the unit type
+func encodeOffchainHTTPResult(ok any, opErr error) ([]byte, error) {
+	result := scale.NewResult(ok, offchain.HTTPError(0))
+	if opErr == nil {
+		err := result.Set(scale.OK, ok)
+		if err != nil {
+			return nil, err
+		}
+		return scale.Marshal(result)
+	}
+
+	var httpErr offchain.HTTPError
+	if !errors.As(opErr, &httpErr) {
+		httpErr = offchain.HTTPErrorIO
+	}
+	err := result.Set(scale.Err, httpErr)
+	if err != nil {
+		return nil, err
+	}
+	return scale.Marshal(result)
+}
+
+func ext_transaction_index_index_version_1(ctx context.Context, m api.Module, extrinsic, size,
+	contextHashPtr uint32) {
+	// Indexes the last size bytes of the extrinsic of the current block
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	contextHash, ok := m.Memory().Read(contextHashPtr, 32)
+	if !ok {
+		panic("read overflow")
+	}
+
+	rtCtx.Storage.IndexTransaction(extrinsic, size, common.NewHash(contextHash))
+}
+
+func ext_transaction_index_renew_version_1(ctx context.Context, m api.Module, extrinsic, contextHashPtr uint32) {
+	// Renews the data indexed under the context hash
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	contextHash, ok := m.Memory().Read(contextHashPtr, 32)
+	if !ok {
+		panic("read overflow")
+	}
+
+	rtCtx.Storage.RenewTransaction(extrinsic, common.NewHash(contextHash))
+}
+
+func ext_sandbox_instantiate_version_1(ctx context.Context, m api.Module, dispatchThunk uint32,
+	wasmCodeSpan, envDefSpan uint64, statePtr uint32) uint32 {
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	// the code is copied since the supervisor memory can be modified during the instantiation
+	wasmCode := bytes.Clone(read(m, wasmCodeSpan))
+
+	var envDef sandboxEnvironmentDefinition
+	err := scale.Unmarshal(read(m, envDefSpan), &envDef)
+	if err != nil {
+		logger.Debugf("failed to decode sandbox environment definition: %s", err)
+		return sandboxErrModule
+	}
+
+	ctx = context.WithValue(ctx, sandboxStateKey, statePtr)
+	instanceIdx, err := store.instantiate(ctx, m, dispatchThunk, wasmCode, envDef)
+	if errors.Is(err, errSandboxStartTrapped) {
+		logger.Debugf("failed to instantiate sandbox module: %s", err)
+		return sandboxErrExecution
+	} else if err != nil {
+		logger.Debugf("failed to instantiate sandbox module: %s", err)
+		return sandboxErrModule
+	}
+
+	return instanceIdx
+}
+
+func ext_sandbox_invoke_version_1(ctx context.Context, m api.Module, instanceIdx uint32,
+	functionSpan, argsSpan uint64, returnValPtr, returnValLen, statePtr uint32) uint32 {
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	function := string(read(m, functionSpan))
+
+	var args []sandboxValue
+	err := scale.Unmarshal(read(m, argsSpan), &args)
+	if err != nil {
+		panic(fmt.Sprintf("decoding sandbox invoke arguments: %s", err))
+	}
+
+	ctx = context.WithValue(ctx, sandboxStateKey, statePtr)
+	result, err := store.invoke(ctx, instanceIdx, function, args)
+	if errors.Is(err, errSandboxInstanceNotFound) {
+		panic(err)
+	} else if err != nil {
+		logger.Debugf("failed to invoke sandbox function %s: %s", function, err)
+		return sandboxErrExecution
+	}
+
+	if result == nil {
+		return sandboxErrOK
+	}
+
+	encoded, err := encodeSandboxReturnValue(result)
+	if err != nil {
+		panic(fmt.Sprintf("encoding sandbox return value: %s", err))
+	}
+
+	if uint32(len(encoded)) > returnValLen {
+		panic("sandbox return value buffer is too small")
+	}
+
+	ok := m.Memory().Write(returnValPtr, encoded)
+	if !ok {
+		panic("write overflow")
+	}
+
+	return sandboxErrOK
+}
+
+func ext_sandbox_instance_teardown_version_1(ctx context.Context, _ api.Module, instanceIdx uint32) {
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	err := store.teardownInstance(ctx, instanceIdx)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func ext_sandbox_get_global_val_version_1(ctx context.Context, m api.Module, instanceIdx uint32,
+	nameSpan uint64) uint64 {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	value, err := store.globalValue(instanceIdx, string(read(m, nameSpan)))
+	if err != nil {
+		panic(err)
+	}
+
+	encoded, err := scale.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return mustWrite(m, rtCtx.Allocator, encoded)
+}
+
+func ext_sandbox_memory_new_version_1(ctx context.Context, _ api.Module, initial, maximum uint32) uint32 {
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	memoryIdx, err := store.newMemory(ctx, initial, maximum)
+	if err != nil {
+		logger.Debugf("failed to create sandbox memory: %s", err)
+		return sandboxErrModule
+	}
+
+	return memoryIdx
+}
+
+func ext_sandbox_memory_get_version_1(ctx context.Context, m api.Module, memoryIdx, offset,
+	bufPtr, bufLen uint32) uint32 {
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	memory, err := store.memory(memoryIdx)
+	if err != nil {
+		panic(err)
+	}
+
+	data, ok := memory.Read(offset, uint64(bufLen))
+	if !ok {
+		return sandboxErrOutOfBounds
+	}
+
+	ok = m.Memory().Write(bufPtr, data)
+	if !ok {
+		return sandboxErrOutOfBounds
+	}
+
+	return sandboxErrOK
+}
+
+func ext_sandbox_memory_set_version_1(ctx context.Context, m api.Module, memoryIdx, offset,
+	valPtr, valLen uint32) uint32 {
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	memory, err := store.memory(memoryIdx)
+	if err != nil {
+		panic(err)
+	}
+
+	data, ok := m.Memory().Read(valPtr, uint64(valLen))
+	if !ok {
+		return sandboxErrOutOfBounds
+	}
+
+	ok = memory.Write(offset, data)
+	if !ok {
+		return sandboxErrOutOfBounds
+	}
+
+	return sandboxErrOK
+}
+
+func ext_sandbox_memory_teardown_version_1(ctx context.Context, _ api.Module, memoryIdx uint32) {
+	store := ctx.Value(sandboxContextKey).(*sandboxStore)
+	if store == nil {
+		panic("nil sandbox store")
+	}
+
+	err := store.teardownMemory(ctx, memoryIdx)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func storageAppend(storage runtime.Storage, key, valueToAppend []byte) (err error) {
 	// this function assumes the item in storage is a SCALE encoded array of items
 	// the valueToAppend is a new item, so it appends the item and increases the length prefix by 1
@@ -7,6 +7,7 @@
 	"bytes"
 	"context"
 	"encoding/binary"
+	"errors"
 	"net/http"
 	"os"
 	"sort"
@@ -15,6 +16,7 @@
 	"time"
 
 	"github.com/ChainSafe/gossamer/internal/database"
+	"github.com/ChainSafe/gossamer/internal/log"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/lib/common/types"
 	"github.com/ChainSafe/gossamer/lib/crypto"
@@ -24,6 +26,8 @@
 	"github.com/ChainSafe/gossamer/lib/keystore"
 	"github.com/ChainSafe/gossamer/lib/runtime"
 	"github.com/ChainSafe/gossamer/lib/runtime/allocator"
+	"github.com/ChainSafe/gossamer/lib/runtime/mocks"
+	"github.com/ChainSafe/gossamer/lib/runtime/offchain"
 	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/pkg/scale"
 	"github.com/ChainSafe/gossamer/pkg/trie"
@@ -32,6 +36,7 @@
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/tetratelabs/wazero"
+	"go.uber.org/mock/gomock"
 )
 
 var DefaultVersion = &runtime.Version{
@@ -65,6 +70,44 @@ func Test_ext_offchain_index_clear_version_1(t *testing.T) {
 	require.ErrorIs(t, err, database.ErrNotFound)
 }
 
+func Test_ext_logging_max_level_version_1(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		logFilter *runtime.LogFilter
+		maxLevel  int32
+	}{
+		"no_log_filter": {
+			maxLevel: 4,
+		},
+		"critical_level": {
+			logFilter: runtime.NewLogFilter(log.Critical, nil),
+			maxLevel:  1,
+		},
+		"info_level": {
+			logFilter: runtime.NewLogFilter(log.Info, nil),
+			maxLevel:  3,
+		},
+		"target_trace_level": {
+			logFilter: runtime.NewLogFilter(log.Info, map[string]log.Level{"runtime::staking": log.Trace}),
+			maxLevel:  5,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rtCtx := &runtime.Context{LogFilter: testCase.logFilter}
+			ctx := context.WithValue(context.Background(), runtimeContextKey, rtCtx)
+
+			maxLevel := ext_logging_max_level_version_1(ctx)
+			assert.Equal(t, testCase.maxLevel, maxLevel)
+		})
+	}
+}
+
 func Test_ext_crypto_ed25519_generate_version_1(t *testing.T) {
 	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
 
@@ -876,7 +919,11 @@ func Test_ext_misc_runtime_version_version_1(t *testing.T) {
 		}
 	}
 
-	mod, err := inst.Runtime.InstantiateModule(context.Background(), inst.metadata.guestModule, wazero.NewModuleConfig())
+	rt, guestModule, err := newRuntime(context.Background(), inst.wasmByteCode, inst.metadata.config)
+	require.NoError(t, err)
+	defer rt.Close(context.Background())
+
+	mod, err := rt.InstantiateModule(context.Background(), guestModule, wazero.NewModuleConfig())
 	require.NoError(t, err)
 
 	allocator := allocator.NewFreeingBumpHeapAllocator(0)
@@ -1654,7 +1701,7 @@ func Test_ext_offchain_timestamp_version_1(t *testing.T) {
 	err = scale.Unmarshal(ret, &timestamp)
 	require.NoError(t, err)
 
-	expected := time.Now().Unix()
+	expected := time.Now().UnixMilli()
 	require.GreaterOrEqual(t, expected, timestamp)
 }
 
@@ -1669,6 +1716,23 @@ func Test_ext_offchain_sleep_until_version_1(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_ext_offchain_submit_transaction_version_1(t *testing.T) {
+	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
+
+	// the transaction is not added to the pool since it
+	// is not submitted from an offchain worker call
+	ctrl := gomock.NewController(t)
+	transactionState := mocks.NewMockTransactionState(ctrl)
+	inst.Context.Transaction = transactionState
+
+	extrinsic := []byte{4, 1, 2, 3}
+	enc, err := scale.Marshal(extrinsic)
+	require.NoError(t, err)
+
+	_, err = inst.Exec("rtm_ext_offchain_submit_transaction_version_1", enc)
+	require.NoError(t, err)
+}
+
 func Test_ext_default_child_storage_clear_prefix_version_2(t *testing.T) {
 	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
 
@@ -1880,6 +1944,48 @@ func Test_ext_offchain_http_request_add_header(t *testing.T) {
 	}
 }
 
+func Test_encodeOffchainHTTPResult(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ok       any
+		err      error
+		expected []byte
+	}{
+		"unit_ok": {
+			expected: []byte{0},
+		},
+		"read_bytes_ok": {
+			ok:       uint32(5),
+			expected: []byte{0, 5, 0, 0, 0},
+		},
+		"deadline_reached": {
+			err:      offchain.HTTPErrorDeadlineReached,
+			expected: []byte{1, 0},
+		},
+		"invalid_request": {
+			ok:       uint32(0),
+			err:      offchain.HTTPErrorInvalid,
+			expected: []byte{1, 2},
+		},
+		"other_error": {
+			err:      errors.New("test error"),
+			expected: []byte{1, 1},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := encodeOffchainHTTPResult(testCase.ok, testCase.err)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, encoded)
+		})
+	}
+}
+
 func Test_ext_storage_clear_version_1(t *testing.T) {
 	inst := NewTestInstance(t, runtime.HOST_API_TEST_RUNTIME, TestWithVersion(DefaultVersion))
 
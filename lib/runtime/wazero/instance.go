@@ -39,23 +39,60 @@ type runtimeContextKeyType struct{
 
 var _ runtime.Instance = (*Instance)(nil)
 
+var _ runtime.Pooled = (*Instance)(nil)
+
+var _ runtime.Limited = (*Instance)(nil)
+
 type wazeroMeta struct {
-	config      wazero.RuntimeConfig
-	cache       wazero.CompilationCache
-	guestModule wazero.CompiledModule
+	config wazero.RuntimeConfig
+	// closeCache closes the compilation cache of the runtime code
+	closeCache func() error
+	limits     map[runtime.CallClass]runtime.Limits
 }
 
-// Instance backed by wazero.Runtime
+// Instance backed by a pool of wazero runtimes, executing its calls concurrently
+// with the instances sharing its executor.
 type Instance struct {
-	Runtime      wazero.Runtime
-	Module       api.Module
 	Context      *runtime.Context
 	wasmByteCode []byte
 	codeHash     common.Hash
 	metadata     wazeroMeta
+	compiled     *compilation
+	// parent is the instance the handle was created from, nil if the instance is not a handle
+	parent *Instance
+	// ctx cancels the calls of the instance once done, it is nil if the calls cannot be canceled
+	ctx context.Context
+	// class is the call class whose execution limits are enforced on the calls of the instance
+	class runtime.CallClass
+	// storageErr is the error setting the context storage, returned by the calls of the instance
+	storageErr error
 	sync.Mutex
 }
 
+// module is a wazero runtime with the host functions instantiated, on which
+// the guest module is instantiated for each call.
+type module struct {
+	runtime     wazero.Runtime
+	guestModule wazero.CompiledModule
+}
+
+var _ runtime.Module = (*module)(nil)
+
+// compilation is the result of compiling the runtime code of an instance, shared with its handles.
+// Its fields are set once done is closed, and are guarded by mutex since a failed compilation
+// is compiled again on the next call to the instance.
+type compilation struct {
+	done chan struct{}
+	// cfg is the configuration the runtime code is compiled with
+	cfg      Config
+	mutex    sync.Mutex
+	executor *runtime.Executor
+	version  *runtime.Version
+	err      error
+	// stopped is true once the instance is stopped, its failed compilation is no longer retried
+	stopped bool
+}
+
 // Config is the configuration used to create a Wasmer runtime instance.
 type Config struct {
 	Storage        runtime.Storage
@@ -67,6 +104,19 @@ type Config struct {
 	Transaction    runtime.TransactionState
 	CodeHash       common.Hash
 	DefaultVersion *runtime.Version
+	// MaxModules is the maximum number of wazero runtimes executing calls
+	// concurrently, runtime.DefaultMaxModules is used if it is zero.
+	MaxModules uint
+	// CompilationCache is the compilation cache shared by the instances of the node,
+	// the runtime code is compiled in memory if it is nil.
+	CompilationCache *CompilationCache
+	// Limits are the execution limits of each call class, runtime.DefaultLimits is used if
+	// it is nil. The calls are executed with the block import limits unless the instance is
+	// returned by WithLimits.
+	Limits map[runtime.CallClass]runtime.Limits
+	// LogFilter filters the messages logged by the runtime, the messages
+	// above LogLvl are filtered out if it is nil.
+	LogFilter *runtime.LogFilter
 }
 
 func decompressWasm(code []byte) ([]byte, error) {
@@ -128,49 +178,67 @@ func newRuntime(ctx context.Context,
 		).
 		Export("ext_logging_log_version_1").
 		NewFunctionBuilder().
-		WithFunc(func() int32 {
-			return 4
-		}).
+		WithFunc(ext_logging_max_level_version_1).
 		Export("ext_logging_max_level_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32, b int32, c int32) {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			tripleArgFn(ext_transaction_index_index_version_1),
+			[]api.ValueType{i32, i32, i32}, []api.ValueType{},
+		).
 		Export("ext_transaction_index_index_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32, b int32) {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			doubleArgFn(ext_transaction_index_renew_version_1),
+			[]api.ValueType{i32, i32}, []api.ValueType{},
+		).
 		Export("ext_transaction_index_renew_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32) {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			singleArgFn(ext_sandbox_instance_teardown_version_1),
+			[]api.ValueType{i32}, []api.ValueType{},
+		).
 		Export("ext_sandbox_instance_teardown_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32, b int64, c int64, d int32) int32 {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			quadArgWithReturnFn(ext_sandbox_instantiate_version_1),
+			[]api.ValueType{i32, i64, i64, i32}, []api.ValueType{i32},
+		).
 		Export("ext_sandbox_instantiate_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32, b int64, c int64, d int32, e int32, f int32) int32 {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			sextArgWithReturnFn(ext_sandbox_invoke_version_1),
+			[]api.ValueType{i32, i64, i64, i32, i32, i32}, []api.ValueType{i32},
+		).
 		Export("ext_sandbox_invoke_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32, b int32, c int32, d int32) int32 {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			doubleArgWithReturnFn(ext_sandbox_get_global_val_version_1),
+			[]api.ValueType{i32, i64}, []api.ValueType{i64},
+		).
+		Export("ext_sandbox_get_global_val_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			doubleArgWithReturnFn(ext_sandbox_memory_new_version_1),
+			[]api.ValueType{i32, i32}, []api.ValueType{i32},
+		).
+		Export("ext_sandbox_memory_new_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			quadArgWithReturnFn(ext_sandbox_memory_get_version_1),
+			[]api.ValueType{i32, i32, i32, i32}, []api.ValueType{i32},
+		).
 		Export("ext_sandbox_memory_get_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32, b int32, c int32, d int32) int32 {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			quadArgWithReturnFn(ext_sandbox_memory_set_version_1),
+			[]api.ValueType{i32, i32, i32, i32}, []api.ValueType{i32},
+		).
 		Export("ext_sandbox_memory_set_version_1").
 		NewFunctionBuilder().
-		WithFunc(func(a int32) {
-			panic("unimplemented")
-		}).
+		WithGoModuleFunction(
+			singleArgFn(ext_sandbox_memory_teardown_version_1),
+			[]api.ValueType{i32}, []api.ValueType{},
+		).
 		Export("ext_sandbox_memory_teardown_version_1").
 		NewFunctionBuilder().
 		WithGoModuleFunction(
@@ -545,6 +613,30 @@ func newRuntime(ctx context.Context,
 		).
 		Export("ext_offchain_http_request_add_header_version_1").
 		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_offchain_http_request_write_body_version_1),
+			[]api.ValueType{i32, i64, i64}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_request_write_body_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			doubleArgWithReturnFn(ext_offchain_http_response_wait_version_1),
+			[]api.ValueType{i64, i64}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_response_wait_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			singleArgWithReturnFn(ext_offchain_http_response_headers_version_1),
+			[]api.ValueType{i32}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_response_headers_version_1").
+		NewFunctionBuilder().
+		WithGoModuleFunction(
+			tripleArgWithReturnFn(ext_offchain_http_response_read_body_version_1),
+			[]api.ValueType{i32, i64, i64}, []api.ValueType{i64},
+		).
+		Export("ext_offchain_http_response_read_body_version_1").
+		NewFunctionBuilder().
 		WithGoModuleFunction(
 			doubleArgFn(ext_storage_append_version_1),
 			[]api.ValueType{i64, i64}, []api.ValueType{},
@@ -666,22 +758,56 @@ func newRuntime(ctx context.Context,
 
 // NewInstance instantiates a runtime from raw wasm bytecode
 func NewInstance(code []byte, cfg Config) (instance *Instance, err error) {
+	instance = newInstance(code, cfg)
+	instance.compile()
+	if instance.compiled.err != nil {
+		_ = instance.metadata.closeCache()
+		return nil, instance.compiled.err
+	}
+	return instance, nil
+}
+
+// NewInstanceInBackground returns a runtime instance compiling the raw wasm bytecode in the
+// background. The calls to the instance wait for the compilation to finish. If the compilation
+// fails, the next call compiles the runtime code again and fails with the compilation error if
+// the runtime still cannot be instantiated.
+func NewInstanceInBackground(code []byte, cfg Config) (instance *Instance) {
+	instance = newInstance(code, cfg)
+	go instance.compile()
+	return instance
+}
+
+func newInstance(code []byte, cfg Config) (instance *Instance) {
 	logger.Debug("instantiating a runtime!")
 	logger.Patch(log.SetLevel(cfg.LogLvl), log.SetCallerFunc(true))
 
-	// Prepare a cache directory.
-	ctx := context.Background()
-	cache := wazero.NewCompilationCache()
-	config := wazero.NewRuntimeConfig().WithCompilationCache(cache)
-	rt, guestCompiledModule, err := newRuntime(ctx, code, config)
-	if err != nil {
-		return nil, fmt.Errorf("creating runtime instance: %w", err)
+	// the modules of the executor share the compilation cache, so the
+	// runtime code is only compiled once.
+	cache, closeCache := newWazeroCompilationCache(code, cfg)
+	limits := cfg.Limits
+	if limits == nil {
+		limits = runtime.DefaultLimits
+	}
+	// the calls are closed once their context is done, and the memory cannot grow
+	// beyond the largest memory limit of the call classes.
+	config := wazero.NewRuntimeConfig().
+		WithCompilationCache(cache).
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(maxMemoryPages(limits))
+
+	logFilter := cfg.LogFilter
+	if logFilter == nil {
+		logLevel := cfg.LogLvl
+		if logLevel == log.DoNotChange {
+			logLevel = log.Info
+		}
+		logFilter = runtime.NewLogFilter(logLevel, nil)
 	}
 
-	instance = &Instance{
+	return &Instance{
 		wasmByteCode: code,
-		Runtime:      rt,
 		Context: &runtime.Context{
+			Storage:         cfg.Storage,
 			Keystore:        cfg.Keystore,
 			Validator:       cfg.Role == common.AuthorityRole,
 			NodeStorage:     cfg.NodeStorage,
@@ -689,38 +815,278 @@ func NewInstance(code []byte, cfg Config) (instance *Instance, err error) {
 			Transaction:     cfg.Transaction,
 			SigVerifier:     crypto.NewSignatureVerifier(logger),
 			OffchainHTTPSet: offchain.NewHTTPSet(),
+			Version:         cfg.DefaultVersion,
+			LogFilter:       logFilter,
 		},
 		codeHash: cfg.CodeHash,
 		metadata: wazeroMeta{
-			config:      config,
-			cache:       cache,
-			guestModule: guestCompiledModule,
+			config:     config,
+			closeCache: closeCache,
+			limits:     limits,
+		},
+		compiled: &compilation{
+			done: make(chan struct{}),
+			cfg:  cfg,
 		},
 	}
+}
+
+// maxMemoryPages returns the largest memory limit of the call classes, which is at
+// least the number of pages of the memory exported to the runtime.
+func maxMemoryPages(limits map[runtime.CallClass]runtime.Limits) uint32 {
+	pages := MemoryMinPages
+	for _, limit := range limits {
+		if limit.MaxMemoryPages == 0 {
+			return runtime.MaxMemoryPages
+		}
+		pages = max(pages, limit.MaxMemoryPages)
+	}
+	return min(pages, runtime.MaxMemoryPages)
+}
+
+// newWazeroCompilationCache returns the wazero compilation cache of the runtime code, stored
+// in the compilation cache of the configuration if any, and in memory otherwise. It also
+// returns the function closing the wazero compilation cache.
+func newWazeroCompilationCache(code []byte, cfg Config) (cache wazero.CompilationCache, closeCache func() error) {
+	if cfg.CompilationCache != nil {
+		codeHash, err := cfg.CompilationCache.codeHash(code, cfg.CodeHash)
+		if err == nil {
+			cache, err = cfg.CompilationCache.open(codeHash)
+			if err == nil {
+				return cache, func() error {
+					return cfg.CompilationCache.close(codeHash, cache)
+				}
+			}
+		}
+		logger.Warnf("compiling runtime code in memory: %s", err)
+	}
+
+	cache = wazero.NewCompilationCache()
+	return cache, func() error {
+		return cache.Close(context.Background())
+	}
+}
+
+// compile compiles the runtime code of the instance and signals the calls waiting for it.
+func (in *Instance) compile() {
+	defer close(in.compiled.done)
 
-	if cfg.DefaultVersion == nil {
-		err = instance.version()
+	in.compiled.mutex.Lock()
+	defer in.compiled.mutex.Unlock()
+	in.compiled.err = in.compileExecutor()
+}
+
+// compileExecutor creates the executor of the instance, compiling the runtime code, and gets the
+// runtime version if it is not set in the configuration. The compilation cache is kept opened on
+// failure such that the runtime code can be compiled again, and is closed when the instance stops.
+// The compiled mutex must be held.
+func (in *Instance) compileExecutor() error {
+	code, cfg := in.wasmByteCode, in.compiled.cfg
+	executor, err := runtime.NewExecutor(cfg.MaxModules, func() (runtime.Module, error) {
+		rt, guestCompiledModule, err := newRuntime(context.Background(), code, in.metadata.config)
 		if err != nil {
-			return nil, fmt.Errorf("while getting runtime version: %w", err)
+			return nil, err
+		}
+		return &module{
+			runtime:     rt,
+			guestModule: guestCompiledModule,
+		}, nil
+	})
+	if err != nil {
+		return fmt.Errorf("creating runtime instance: %w", err)
+	}
+
+	version := cfg.DefaultVersion
+	if version == nil {
+		version, err = in.version(executor)
+		if err != nil {
+			_ = executor.Close()
+			return fmt.Errorf("while getting runtime version: %w", err)
 		}
-	} else {
-		instance.Context.Version = cfg.DefaultVersion
 	}
 
 	if cfg.Storage != nil {
-		instance.SetContextStorage(cfg.Storage)
+		err = setStorageVersion(cfg.Storage, version)
+		if err != nil {
+			_ = executor.Close()
+			return fmt.Errorf("setting storage version: %w", err)
+		}
 	}
 
-	return instance, nil
+	in.Lock()
+	in.Context.Version = version
+	in.Unlock()
+	in.compiled.executor = executor
+	in.compiled.version = version
+
+	if cfg.CompilationCache != nil {
+		codeHash, err := cfg.CompilationCache.codeHash(code, cfg.CodeHash)
+		if err == nil {
+			err = cfg.CompilationCache.evict(codeHash)
+		}
+		if err != nil {
+			logger.Warnf("evicting compiled codes from the compilation cache: %s", err)
+		}
+	}
+	return nil
+}
+
+// wait waits for the runtime code to be compiled and returns the executor of the instance.
+// A failed compilation is not kept, the runtime code is compiled again such that a transient
+// failure does not fail every call of the instance.
+func (in *Instance) wait() (executor *runtime.Executor, err error) {
+	<-in.compiled.done
+
+	in.compiled.mutex.Lock()
+	defer in.compiled.mutex.Unlock()
+	if in.compiled.err != nil && in.compiled.stopped {
+		return nil, in.compiled.err
+	} else if in.compiled.err != nil {
+		logger.Warnf("compiling runtime code again after failed compilation: %s", in.compiled.err)
+		parent := in
+		if in.parent != nil {
+			parent = in.parent
+		}
+		in.compiled.err = parent.compileExecutor()
+		if in.compiled.err != nil {
+			return nil, in.compiled.err
+		}
+	}
+
+	in.Lock()
+	defer in.Unlock()
+	if in.Context.Version == nil {
+		// the handle was created before the version was known
+		in.Context.Version = in.compiled.version
+	}
+	return in.compiled.executor, nil
 }
 
 var ErrExportFunctionNotFound = errors.New("export function not found")
 
-func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
-	i.Lock()
-	defer i.Unlock()
+// Handle returns an instance sharing the executor of the instance, with its own context
+// storage such that both instances can execute calls concurrently. The handle starts without
+// context storage, which must be set with SetContextStorage before executing calls.
+func (in *Instance) Handle() runtime.Instance {
+	parent := in
+	if in.parent != nil {
+		parent = in.parent
+	}
+
+	parent.Lock()
+	defer parent.Unlock()
+
+	handleContext := *parent.Context
+	handleContext.Storage = nil
+	return &Instance{
+		Context:      &handleContext,
+		wasmByteCode: parent.wasmByteCode,
+		codeHash:     parent.codeHash,
+		metadata:     parent.metadata,
+		compiled:     parent.compiled,
+		parent:       parent,
+	}
+}
+
+// WithLimits returns a handle of the instance executing its calls with the execution limits of
+// the call class, the calls are canceled once ctx is done. The handle starts with the context
+// storage of the instance.
+func (in *Instance) WithLimits(ctx context.Context, class runtime.CallClass) runtime.Instance {
+	parent := in
+	if in.parent != nil {
+		parent = in.parent
+	}
+
+	in.Lock()
+	defer in.Unlock()
+
+	handleContext := *in.Context
+	return &Instance{
+		Context:      &handleContext,
+		wasmByteCode: in.wasmByteCode,
+		codeHash:     in.codeHash,
+		metadata:     in.metadata,
+		compiled:     in.compiled,
+		parent:       parent,
+		ctx:          ctx,
+		class:        class,
+		storageErr:   in.storageErr,
+	}
+}
+
+// Parent returns the instance the handle was created from, or the instance itself if it is not a handle.
+func (in *Instance) Parent() runtime.Instance {
+	if in.parent != nil {
+		return in.parent
+	}
+	return in
+}
+
+// Exec executes the runtime function on a module of the instance executor, with a copy
+// of the instance context having its own allocator and signature verifier.
+func (in *Instance) Exec(function string, data []byte) ([]byte, error) {
+	executor, err := in.wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return in.exec(executor, function, data)
+}
+
+func (in *Instance) exec(executor *runtime.Executor, function string, data []byte) ([]byte, error) {
+	in.Lock()
+	callContext := *in.Context
+	storageErr := in.storageErr
+	in.Unlock()
+
+	if storageErr != nil {
+		return nil, fmt.Errorf("calling %s: %w", function, storageErr)
+	}
+
+	if in.parent != nil && callContext.Storage == nil {
+		return nil, fmt.Errorf("%w: calling %s", runtime.ErrNoContextStorage, function)
+	}
+
+	limits := in.metadata.limits[in.class]
+	callContext.SigVerifier = crypto.NewSignatureVerifier(logger)
+	callContext.MaxMemoryPages = limits.MaxMemoryPages
 
-	mod, err := i.Runtime.InstantiateModule(context.Background(), i.metadata.guestModule, wazero.NewModuleConfig())
+	ctx := in.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	result, err := executor.Exec(ctx, &callContext, function, data)
+	if err != nil {
+		return nil, limitError(ctx, in.class, function, err)
+	}
+	return result, nil
+}
+
+// limitError returns the typed error of a call failing with err
+// if it failed because of its execution limits or its context.
+func limitError(ctx context.Context, class runtime.CallClass, function string, err error) error {
+	switch {
+	case errors.Is(err, allocator.ErrAllocatorOutOfSpace), errors.Is(err, allocator.ErrCannotGrowLinearMemory):
+		return fmt.Errorf("%w: %s call %s: %w", runtime.ErrMemoryLimitExceeded, class, function, err)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%w: %s call %s: %w", runtime.ErrCallTimeout, class, function, err)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%w: %s call %s: %w", runtime.ErrCallCanceled, class, function, err)
+	default:
+		return err
+	}
+}
+
+// Exec instantiates the guest module and executes the runtime function with the given runtime
+// context, the wazero runtime closes the guest module once ctx is done.
+func (m *module) Exec(ctx context.Context, rtCtx *runtime.Context, function string, data []byte) ([]byte, error) {
+	mod, err := m.runtime.InstantiateModule(ctx, m.guestModule, wazero.NewModuleConfig())
 	if mod == nil {
 		return nil, fmt.Errorf("instantiate guest module: nil")
 	}
@@ -741,7 +1107,11 @@ func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
 	}
 
 	heapBase := api.DecodeU32(encodedHeapBase.Get())
-	i.Context.Allocator = allocator.NewFreeingBumpHeapAllocator(heapBase)
+	maxPages := rtCtx.MaxMemoryPages
+	if maxPages == 0 {
+		maxPages = runtime.MaxMemoryPages
+	}
+	rtCtx.Allocator = allocator.NewFreeingBumpHeapAllocatorWithMaxPages(heapBase, maxPages)
 
 	memory := mod.Memory()
 	if memory == nil {
@@ -749,7 +1119,7 @@ func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
 	}
 
 	dataLength := uint32(len(data))
-	inputPtr, err := i.Context.Allocator.Allocate(memory, dataLength)
+	inputPtr, err := rtCtx.Allocator.Allocate(memory, dataLength)
 	if err != nil {
 		return nil, fmt.Errorf("allocating input memory: %w", err)
 	}
@@ -764,7 +1134,19 @@ func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
 		return nil, fmt.Errorf("%w: %s", ErrExportFunctionNotFound, function)
 	}
 
-	ctx := context.WithValue(context.Background(), runtimeContextKey, i.Context)
+	sandbox := newSandboxStore(maxPages)
+	defer func() {
+		err := sandbox.close(context.Background())
+		if err != nil {
+			logger.Errorf("closing sandbox store: %s", err)
+		}
+	}()
+
+	// a call trapping before finishing its signature batch leaves the batch started
+	defer rtCtx.SigVerifier.Reset()
+
+	ctx = context.WithValue(ctx, runtimeContextKey, rtCtx)
+	ctx = context.WithValue(ctx, sandboxContextKey, sandbox)
 	values, err := runtimeFunc.Call(ctx, api.EncodeU32(inputPtr), api.EncodeU32(dataLength))
 	if err != nil {
 		return nil, fmt.Errorf("running runtime function: %w", err)
@@ -782,37 +1164,46 @@ func (i *Instance) Exec(function string, data []byte) ([]byte, error) {
 	return result, nil
 }
 
+// Close closes the wazero runtime of the module.
+func (m *module) Close() error {
+	return m.runtime.Close(context.Background())
+}
+
 // Version returns the instance version.
 // This is cheap to call since the instance version is cached.
 // Note the instance version is set at creation and on code update.
 func (in *Instance) Version() (runtime.Version, error) {
-	if in.Context.Version != nil {
-		return *in.Context.Version, nil
+	in.Lock()
+	version := in.Context.Version
+	in.Unlock()
+	if version != nil {
+		return *version, nil
 	}
 
-	err := in.version()
+	_, err := in.wait()
 	if err != nil {
 		return runtime.Version{}, err
 	}
 
+	in.Lock()
+	defer in.Unlock()
 	return *in.Context.Version, nil
 }
 
-// version calls runtime function Core_Version and returns the
+// version calls runtime function Core_Version with the executor and returns the
 // decoded version structure.
-func (in *Instance) version() error { //skipcq: RVV-B0001
-	res, err := in.Exec(runtime.CoreVersion, []byte{})
+func (in *Instance) version(executor *runtime.Executor) (*runtime.Version, error) {
+	res, err := in.exec(executor, runtime.CoreVersion, []byte{})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	version, err := runtime.DecodeVersion(res)
 	if err != nil {
-		return fmt.Errorf("decoding version: %w", err)
+		return nil, fmt.Errorf("decoding version: %w", err)
 	}
 
-	in.Context.Version = &version
-	return nil
+	return &version, nil
 }
 
 // ValidateTransaction runs the extrinsic through the runtime function
@@ -1120,11 +1511,120 @@ func (in *Instance) GrandpaSubmitReportEquivocationUnsignedExtrinsic(
 func (*Instance) RandomSeed() {
 	panic("unimplemented")
 }
-func (*Instance) OffchainWorker() {
-	panic("unimplemented")
+
+// OffchainWorker runs the offchain worker of the runtime for the block with the given header.
+// Version 1 of the OffchainWorkerApi expects the block number instead of the block header.
+func (in *Instance) OffchainWorker(header *types.Header) error {
+	version, err := in.Version()
+	if err != nil {
+		return fmt.Errorf("getting runtime version: %w", err)
+	}
+
+	apiVersion, err := version.OffchainWorkerAPIVersion()
+	if err != nil {
+		return fmt.Errorf("getting offchain worker api version: %w", err)
+	}
+
+	var encoded []byte
+	if apiVersion < 2 {
+		encoded, err = scale.Marshal(uint32(header.Number))
+	} else {
+		encoded, err = scale.Marshal(*header)
+	}
+	if err != nil {
+		return fmt.Errorf("encoding offchain worker argument: %w", err)
+	}
+
+	ctx := in.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	pool := &offchainTransactionPool{
+		instance:  in,
+		blockHash: header.Hash(),
+	}
+	ctx = context.WithValue(ctx, offchainTransactionPoolKey, pool)
+
+	_, err = in.WithLimits(ctx, in.class).Exec(runtime.OffchainWorkerAPIOffchainWorker, encoded)
+	return err
 }
-func (*Instance) GenerateSessionKeys() {
-	panic("unimplemented")
+
+type offchainTransactionPoolKeyType struct{}
+
+// offchainTransactionPoolKey is the context key of the transaction pool of an offchain worker call.
+var offchainTransactionPoolKey = offchainTransactionPoolKeyType{}
+
+// offchainTransactionPool submits the transactions of the offchain worker of a block to the
+// transaction pool, once validated by the runtime as local transactions at the block.
+type offchainTransactionPool struct {
+	instance  *Instance
+	blockHash common.Hash
+}
+
+// submit validates the extrinsic and adds it to the transaction pool of the runtime context.
+// The extrinsic is validated on another module of the instance, with the storage of the
+// offchain worker, and is canceled once ctx is done.
+func (p *offchainTransactionPool) submit(ctx context.Context, rtCtx *runtime.Context,
+	extrinsic types.Extrinsic) error {
+	version, err := p.instance.Version()
+	if err != nil {
+		return fmt.Errorf("getting runtime version: %w", err)
+	}
+
+	txQueueVersion, err := version.TaggedTransactionQueueVersion()
+	if err != nil {
+		return fmt.Errorf("getting tagged transaction queue version: %w", err)
+	}
+
+	var localExtrinsic []byte
+	switch txQueueVersion {
+	case 3:
+		localExtrinsic = bytes.Join([][]byte{{byte(types.TxnLocal)}, extrinsic, p.blockHash.ToBytes()}, nil)
+	case 2:
+		localExtrinsic = bytes.Join([][]byte{{byte(types.TxnLocal)}, extrinsic}, nil)
+	default:
+		return fmt.Errorf("unsupported tagged transaction queue version: %d", txQueueVersion)
+	}
+
+	// the changes made by the validation are discarded
+	// such that the offchain worker storage is unchanged
+	rtCtx.Storage.StartTransaction()
+	defer rtCtx.Storage.RollbackTransaction()
+
+	validator := p.instance.WithLimits(ctx, runtime.ValidationCall)
+	validator.SetContextStorage(rtCtx.Storage)
+	validity, err := validator.ValidateTransaction(localExtrinsic)
+	if err != nil {
+		return fmt.Errorf("validating transaction: %w", err)
+	}
+
+	_, err = rtCtx.Transaction.AddToPool(transaction.NewValidTransaction(extrinsic, validity))
+	if err != nil {
+		return fmt.Errorf("adding transaction to pool: %w", err)
+	}
+	return nil
+}
+
+// GenerateSessionKeys generates a set of session keys with an optional seed. The private keys
+// are stored in the keystores of the runtime context and the SCALE encoded public keys are returned.
+func (in *Instance) GenerateSessionKeys(seed *[]byte) ([]byte, error) {
+	encodedSeed, err := scale.Marshal(seed)
+	if err != nil {
+		return nil, fmt.Errorf("encoding seed: %w", err)
+	}
+
+	encodedKeys, err := in.Exec(runtime.GenerateSessionKeys, encodedSeed)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []byte
+	err = scale.Unmarshal(encodedKeys, &keys)
+	if err != nil {
+		return nil, fmt.Errorf("decoding session keys: %w", err)
+	}
+
+	return keys, nil
 }
 
 // GetCodeHash returns the code of the instance
@@ -1152,35 +1652,66 @@ func (in *Instance) Validator() bool {
 	return in.Context.Validator
 }
 
-// SetContextStorage sets the runtime's storage.
+// SetContextStorage sets the runtime's storage. The storage set on a handle is
+// private to the handle. If the state version of the storage cannot be set, the
+// storage is not set and the calls of the instance fail with the error.
 func (in *Instance) SetContextStorage(s runtime.Storage) {
+	version, err := in.Version()
+	if err != nil {
+		logger.Errorf("setting runtime storage without runtime version: %s", err)
+	} else {
+		err = setStorageVersion(s, &version)
+		if err != nil {
+			in.Lock()
+			defer in.Unlock()
+			in.storageErr = fmt.Errorf("setting context storage: %w", err)
+			return
+		}
+	}
+
 	in.Lock()
 	defer in.Unlock()
+	in.Context.Storage = s
+	in.storageErr = nil
+}
 
-	if in.Context.Version == nil {
-		panic("expected runtime version got nil")
-	}
-
-	runtimeStateVersion, err := trie.ParseVersion(in.Context.Version.StateVersion)
+// setStorageVersion sets the state trie version of the storage to the state version of the runtime.
+func setStorageVersion(s runtime.Storage, version *runtime.Version) error {
+	stateVersion, err := trie.ParseVersion(version.StateVersion)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("parsing runtime state version: %w", err)
 	}
-
-	s.SetVersion(runtimeStateVersion)
-	in.Context.Storage = s
+	s.SetVersion(stateVersion)
+	return nil
 }
 
-// Stop closes the WASM instance, its imports and clears
-// the context allocator in a thread-safe way.
+// Stop waits for the calls being executed and closes the wazero runtimes of the instance
+// executor, which is shared with the instances returned by Handle. Stopping a handle does
+// nothing since the executor is owned by its parent instance.
 func (in *Instance) Stop() {
-	in.Lock()
-	defer in.Unlock()
-	err := in.Runtime.Close(context.Background())
-	if err != nil {
-		log.Errorf("runtime failed to close: %v", err)
+	if in.parent != nil {
+		return
+	}
+
+	<-in.compiled.done
+	in.compiled.mutex.Lock()
+	defer in.compiled.mutex.Unlock()
+	if in.compiled.stopped {
+		return
+	}
+	in.compiled.stopped = true
+
+	// the wazero runtimes are closed when the compilation fails
+	if in.compiled.err == nil {
+		err := in.compiled.executor.Close()
+		if errors.Is(err, runtime.ErrExecutorClosed) {
+			return
+		} else if err != nil {
+			log.Errorf("runtime failed to close: %v", err)
+		}
 	}
 
-	err = in.metadata.cache.Close(context.Background())
+	err := in.metadata.closeCache()
 	if err != nil {
 		log.Errorf("closing the wazero compilation cache: %v", err)
 	}
@@ -5,11 +5,16 @@
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ChainSafe/gossamer/dot/network/messages"
 	"github.com/ChainSafe/gossamer/dot/types"
@@ -19,6 +24,7 @@
 	"github.com/ChainSafe/gossamer/lib/crypto/ed25519"
 	"github.com/ChainSafe/gossamer/lib/genesis"
 	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/lib/runtime/allocator"
 	"github.com/ChainSafe/gossamer/lib/runtime/storage"
 	"github.com/ChainSafe/gossamer/lib/runtime/wazero/testdata"
 	"github.com/ChainSafe/gossamer/lib/utils"
@@ -1085,6 +1091,35 @@ func TestInstance_DecodeSessionKeys(t *testing.T) {
 	require.Len(t, *decodedKeys, 6)
 }
 
+func TestInstance_GenerateSessionKeys(t *testing.T) {
+	instance := NewTestInstance(t, runtime.WESTEND_RUNTIME_v0929)
+
+	keys, err := instance.GenerateSessionKeys(nil)
+	require.NoError(t, err)
+
+	encodedKeys, err := scale.Marshal(keys)
+	require.NoError(t, err)
+
+	decoded, err := instance.DecodeSessionKeys(encodedKeys)
+	require.NoError(t, err)
+
+	var decodedKeys *[]struct {
+		Data []uint8
+		Type [4]uint8
+	}
+	err = scale.Unmarshal(decoded, &decodedKeys)
+	require.NoError(t, err)
+	require.NotNil(t, decodedKeys)
+	require.Len(t, *decodedKeys, 6)
+
+	for _, key := range *decodedKeys {
+		ks, err := instance.Keystore().GetKeystore(key.Type[:])
+		require.NoError(t, err)
+		require.Len(t, ks.Keypairs(), 1)
+		assert.Equal(t, key.Data, ks.Keypairs()[0].Public().Encode())
+	}
+}
+
 func TestInstance_PaymentQueryInfo(t *testing.T) {
 	tests := []struct {
 		extB       []byte
@@ -1444,3 +1479,229 @@ func TestInstance_ExecuteBlock_PaseoRuntime_PaseoBlock1789153(t *testing.T) {
 	expectedRootNew := common.MustHexToHash("0xc29a9d4465400c980cca388963461755040f2ba4c5ed722afc204014426e9080")
 	require.Equal(t, expectedRootNew, state.Trie().MustHash())
 }
+
+func TestInstance_Handle(t *testing.T) {
+	genesisPath := utils.GetKusamaGenesisPath(t)
+	kusamaGenesis := genesisFromRawJSON(t, genesisPath)
+	genesisTrie, err := runtime.NewTrieFromGenesis(kusamaGenesis)
+	require.NoError(t, err)
+
+	genesisState := storage.NewTrieState(genesisTrie)
+	cfg := Config{
+		Storage:    genesisState,
+		LogLvl:     log.Critical,
+		MaxModules: 2,
+	}
+	instance, err := NewRuntimeFromGenesis(cfg)
+	require.NoError(t, err)
+	t.Cleanup(instance.Stop)
+
+	expected, err := instance.Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	const calls = 4
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		handle := instance.Handle()
+		handle.SetContextStorage(storage.NewTrieState(genesisTrie))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := handle.Exec(runtime.CoreVersion, []byte{})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, result)
+		}()
+	}
+	wg.Wait()
+
+	// the storage set on a handle is private to the handle
+	handle := instance.Handle()
+	_, err = handle.Exec(runtime.CoreVersion, []byte{})
+	assert.ErrorIs(t, err, runtime.ErrNoContextStorage)
+	otherState := storage.NewTrieState(inmemory_trie.NewEmptyTrie())
+	handle.SetContextStorage(otherState)
+	assert.Equal(t, otherState, handle.(*Instance).Context.Storage)
+	assert.Equal(t, genesisState, instance.Context.Storage)
+	assert.Nil(t, instance.Handle().(*Instance).Context.Storage)
+
+	// stopping a handle leaves the executor of its parent open
+	handle.Stop()
+	_, err = instance.Exec(runtime.CoreVersion, []byte{})
+	assert.NoError(t, err)
+
+	instance.Stop()
+	_, err = handle.Exec(runtime.CoreVersion, []byte{})
+	assert.ErrorIs(t, err, runtime.ErrExecutorClosed)
+}
+
+func TestNewInstanceInBackground(t *testing.T) {
+	genesisPath := utils.GetKusamaGenesisPath(t)
+	kusamaGenesis := genesisFromRawJSON(t, genesisPath)
+	genesisTrie, err := runtime.NewTrieFromGenesis(kusamaGenesis)
+	require.NoError(t, err)
+
+	cacheDirectory := t.TempDir()
+	cfg := Config{
+		Storage:          storage.NewTrieState(genesisTrie),
+		LogLvl:           log.Critical,
+		CompilationCache: NewCompilationCache(cacheDirectory, DefaultCompilationCacheSize),
+	}
+	code := cfg.Storage.LoadCode()
+
+	instance := NewInstanceInBackground(code, cfg)
+	t.Cleanup(instance.Stop)
+
+	// the handles created while compiling wait for the compilation as well
+	handle := instance.Handle()
+	handle.SetContextStorage(cfg.Storage)
+	version, err := handle.Version()
+	require.NoError(t, err)
+	assert.Equal(t, "kusama", string(version.SpecName))
+
+	_, err = handle.Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	codeHash, err := common.Blake2bHash(code)
+	require.NoError(t, err)
+	compiled, err := os.ReadDir(cfg.CompilationCache.codeDirectory(codeHash))
+	require.NoError(t, err)
+	assert.NotEmpty(t, compiled)
+
+	// the next instances of the code load the compiled code from the cache
+	next, err := NewInstance(code, cfg)
+	require.NoError(t, err)
+	t.Cleanup(next.Stop)
+	nextVersion, err := next.Version()
+	require.NoError(t, err)
+	assert.Equal(t, version, nextVersion)
+}
+
+func TestNewInstanceInBackground_invalidCode(t *testing.T) {
+	t.Parallel()
+
+	instance := NewInstanceInBackground([]byte{1, 2, 3}, Config{LogLvl: log.Critical})
+
+	_, err := instance.Exec(runtime.CoreVersion, []byte{})
+	assert.ErrorContains(t, err, "creating runtime instance")
+	_, err = instance.Version()
+	assert.ErrorContains(t, err, "creating runtime instance")
+	instance.Stop()
+}
+
+func TestNewInstanceInBackground_compilationFailure(t *testing.T) {
+	genesisPath := utils.GetKusamaGenesisPath(t)
+	kusamaGenesis := genesisFromRawJSON(t, genesisPath)
+	genesisTrie, err := runtime.NewTrieFromGenesis(kusamaGenesis)
+	require.NoError(t, err)
+
+	cfg := Config{
+		Storage: storage.NewTrieState(genesisTrie),
+		LogLvl:  log.Critical,
+	}
+	code := cfg.Storage.LoadCode()
+
+	// the background compilation failed
+	instance := newInstance(code, cfg)
+	instance.compiled.err = errors.New("test error")
+	close(instance.compiled.done)
+	t.Cleanup(instance.Stop)
+
+	// the calls compile the runtime code again instead of failing with the compilation error
+	handle := instance.Handle()
+	handle.SetContextStorage(cfg.Storage)
+	_, err = handle.Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	version, err := instance.Version()
+	require.NoError(t, err)
+	assert.Equal(t, "kusama", string(version.SpecName))
+}
+
+func TestInstance_WithLimits(t *testing.T) {
+	genesisPath := utils.GetKusamaGenesisPath(t)
+	kusamaGenesis := genesisFromRawJSON(t, genesisPath)
+	genesisTrie, err := runtime.NewTrieFromGenesis(kusamaGenesis)
+	require.NoError(t, err)
+
+	limits := map[runtime.CallClass]runtime.Limits{
+		runtime.BlockImportCall: {},
+		runtime.RPCCall:         {},
+		runtime.ValidationCall:  {Timeout: time.Nanosecond},
+	}
+	cfg := Config{
+		Storage: storage.NewTrieState(genesisTrie),
+		LogLvl:  log.Critical,
+		Limits:  limits,
+	}
+	instance, err := NewRuntimeFromGenesis(cfg)
+	require.NoError(t, err)
+	t.Cleanup(instance.Stop)
+
+	_, err = instance.WithLimits(context.Background(), runtime.RPCCall).Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+
+	_, err = instance.WithLimits(context.Background(), runtime.ValidationCall).Exec(runtime.CoreVersion, []byte{})
+	assert.ErrorIs(t, err, runtime.ErrCallTimeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = instance.WithLimits(ctx, runtime.RPCCall).Exec(runtime.CoreVersion, []byte{})
+	assert.ErrorIs(t, err, runtime.ErrCallCanceled)
+
+	// the instance is still usable after its calls got aborted
+	_, err = instance.Exec(runtime.CoreVersion, []byte{})
+	require.NoError(t, err)
+}
+
+func Test_limitError(t *testing.T) {
+	t.Parallel()
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	expiredCtx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		ctx         context.Context
+		err         error
+		errSentinel error
+	}{
+		"other_error": {
+			ctx:         context.Background(),
+			err:         errTest,
+			errSentinel: errTest,
+		},
+		"allocator_out_of_space": {
+			ctx:         context.Background(),
+			err:         fmt.Errorf("running runtime function: %w", allocator.ErrAllocatorOutOfSpace),
+			errSentinel: runtime.ErrMemoryLimitExceeded,
+		},
+		"memory_cannot_grow": {
+			ctx:         context.Background(),
+			err:         allocator.ErrCannotGrowLinearMemory,
+			errSentinel: runtime.ErrMemoryLimitExceeded,
+		},
+		"deadline_exceeded": {
+			ctx:         expiredCtx,
+			err:         errTest,
+			errSentinel: runtime.ErrCallTimeout,
+		},
+		"canceled": {
+			ctx:         canceledCtx,
+			err:         errTest,
+			errSentinel: runtime.ErrCallCanceled,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := limitError(testCase.ctx, runtime.RPCCall, runtime.CoreVersion, testCase.err)
+			assert.ErrorIs(t, err, testCase.errSentinel)
+			assert.ErrorIs(t, err, testCase.err)
+		})
+	}
+}
@@ -0,0 +1,827 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero_runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental/table"
+)
+
+// Error codes returned to the runtime by the sandbox host functions, as defined in `sp_sandbox::env`.
+const (
+	sandboxErrOK          uint32 = 0
+	sandboxErrModule      uint32 = math.MaxUint32
+	sandboxErrOutOfBounds uint32 = math.MaxUint32 - 1
+	sandboxErrExecution   uint32 = math.MaxUint32 - 2
+
+	// sandboxMemoryUnlimited is the maximum number of pages of a sandbox memory without maximum.
+	sandboxMemoryUnlimited uint32 = math.MaxUint32
+)
+
+// wasm import kinds, see https://webassembly.github.io/spec/core/binary/modules.html#import-section
+const (
+	importKindFunction byte = 0x00
+	importKindTable    byte = 0x01
+	importKindMemory   byte = 0x02
+	importKindGlobal   byte = 0x03
+)
+
+const (
+	wasmImportSectionID byte = 2
+	wasmStartSectionID  byte = 8
+)
+
+var (
+	errSandboxInstanceNotFound = errors.New("sandbox instance not found")
+	errSandboxMemoryNotFound   = errors.New("sandbox memory not found")
+	errSandboxImportNotFound   = errors.New("sandbox import not found in environment definition")
+	errSandboxUnsupportedKind  = errors.New("unsupported sandbox import kind")
+	errSandboxStartTrapped     = errors.New("sandbox start function trapped")
+	errSandboxHostError        = errors.New("sandbox host function returned an error")
+	errInvalidWasmModule       = errors.New("invalid wasm module")
+)
+
+type sandboxContextKeyType struct{}
+
+// sandboxContextKey is the context key of the sandbox store of a runtime call.
+var sandboxContextKey = sandboxContextKeyType{}
+
+type sandboxStateKeyType struct{}
+
+// sandboxStateKey is the context key of the supervisor state pointer given
+// to the sandbox instantiation or invocation being executed.
+var sandboxStateKey = sandboxStateKeyType{}
+
+type sandboxValue struct {
+	inner any
+}
+
+type sandboxValueValues interface {
+	sandboxI32 | sandboxI64 | sandboxF32 | sandboxF64
+}
+
+func setSandboxValue[Value sandboxValueValues](mvdt *sandboxValue, value Value) {
+	mvdt.inner = value
+}
+
+func (mvdt *sandboxValue) SetValue(value any) (err error) {
+	switch value := value.(type) {
+	case sandboxI32:
+		setSandboxValue(mvdt, value)
+		return
+	case sandboxI64:
+		setSandboxValue(mvdt, value)
+		return
+	case sandboxF32:
+		setSandboxValue(mvdt, value)
+		return
+	case sandboxF64:
+		setSandboxValue(mvdt, value)
+		return
+	default:
+		return fmt.Errorf("unsupported type")
+	}
+}
+
+func (mvdt sandboxValue) IndexValue() (index uint, value any, err error) {
+	switch mvdt.inner.(type) {
+	case sandboxI32:
+		return 0, mvdt.inner, nil
+	case sandboxI64:
+		return 1, mvdt.inner, nil
+	case sandboxF32:
+		return 2, mvdt.inner, nil
+	case sandboxF64:
+		return 3, mvdt.inner, nil
+	}
+	return 0, nil, scale.ErrUnsupportedVaryingDataTypeValue
+}
+
+func (mvdt sandboxValue) Value() (value any, err error) {
+	_, value, err = mvdt.IndexValue()
+	return
+}
+
+func (mvdt sandboxValue) ValueAt(index uint) (value any, err error) {
+	switch index {
+	case 0:
+		return sandboxI32(0), nil
+	case 1:
+		return sandboxI64(0), nil
+	case 2:
+		return sandboxF32(0), nil
+	case 3:
+		return sandboxF64(0), nil
+	}
+	return nil, scale.ErrUnknownVaryingDataTypeValue
+}
+
+type sandboxI32 int32
+
+type sandboxI64 int64
+
+// sandboxF32 holds the bits of a float32
+type sandboxF32 uint32
+
+// sandboxF64 holds the bits of a float64
+type sandboxF64 uint64
+
+// newSandboxValue creates a sandbox value from a raw wasm value of the given type.
+func newSandboxValue(valueType api.ValueType, raw uint64) (value sandboxValue, err error) {
+	switch valueType {
+	case api.ValueTypeI32:
+		err = value.SetValue(sandboxI32(api.DecodeI32(raw)))
+	case api.ValueTypeI64:
+		err = value.SetValue(sandboxI64(raw))
+	case api.ValueTypeF32:
+		err = value.SetValue(sandboxF32(api.DecodeU32(raw)))
+	case api.ValueTypeF64:
+		err = value.SetValue(sandboxF64(raw))
+	default:
+		err = fmt.Errorf("unsupported value type %s", api.ValueTypeName(valueType))
+	}
+	return value, err
+}
+
+// raw returns the raw wasm value and its type.
+func (mvdt sandboxValue) raw() (valueType api.ValueType, raw uint64, err error) {
+	switch value := mvdt.inner.(type) {
+	case sandboxI32:
+		return api.ValueTypeI32, api.EncodeI32(int32(value)), nil
+	case sandboxI64:
+		return api.ValueTypeI64, api.EncodeI64(int64(value)), nil
+	case sandboxF32:
+		return api.ValueTypeF32, api.EncodeU32(uint32(value)), nil
+	case sandboxF64:
+		return api.ValueTypeF64, uint64(value), nil
+	}
+	return 0, 0, scale.ErrUnsupportedVaryingDataTypeValue
+}
+
+// encodeSandboxReturnValue encodes the `sp_wasm_interface::ReturnValue` of the given
+// results, which is `Unit` if there are no results or `Value` otherwise.
+func encodeSandboxReturnValue(value *sandboxValue) ([]byte, error) {
+	if value == nil {
+		return []byte{0}, nil
+	}
+
+	encoded, err := scale.Marshal(*value)
+	if err != nil {
+		return nil, fmt.Errorf("encoding value: %w", err)
+	}
+	return append([]byte{1}, encoded...), nil
+}
+
+// decodeSandboxHostResult decodes the `Result<ReturnValue, HostError>` returned by the
+// dispatch thunk of the supervisor, the value is nil if the return value is `Unit`.
+func decodeSandboxHostResult(encoded []byte) (value *sandboxValue, err error) {
+	if len(encoded) < 2 {
+		return nil, fmt.Errorf("%w: %d bytes", io.ErrUnexpectedEOF, len(encoded))
+	}
+
+	switch encoded[0] {
+	case 0:
+	case 1:
+		return nil, errSandboxHostError
+	default:
+		return nil, fmt.Errorf("invalid result index %d", encoded[0])
+	}
+
+	switch encoded[1] {
+	case 0:
+		return nil, nil
+	case 1:
+		value = new(sandboxValue)
+		err = scale.Unmarshal(encoded[2:], value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding value: %w", err)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("invalid return value index %d", encoded[1])
+	}
+}
+
+type sandboxExternEntity struct {
+	inner any
+}
+
+type sandboxExternEntityValues interface {
+	sandboxFunction | sandboxMemory
+}
+
+func setSandboxExternEntity[Value sandboxExternEntityValues](mvdt *sandboxExternEntity, value Value) {
+	mvdt.inner = value
+}
+
+func (mvdt *sandboxExternEntity) SetValue(value any) (err error) {
+	switch value := value.(type) {
+	case sandboxFunction:
+		setSandboxExternEntity(mvdt, value)
+		return
+	case sandboxMemory:
+		setSandboxExternEntity(mvdt, value)
+		return
+	default:
+		return fmt.Errorf("unsupported type")
+	}
+}
+
+func (mvdt sandboxExternEntity) IndexValue() (index uint, value any, err error) {
+	switch mvdt.inner.(type) {
+	case sandboxFunction:
+		return 1, mvdt.inner, nil
+	case sandboxMemory:
+		return 2, mvdt.inner, nil
+	}
+	return 0, nil, scale.ErrUnsupportedVaryingDataTypeValue
+}
+
+func (mvdt sandboxExternEntity) Value() (value any, err error) {
+	_, value, err = mvdt.IndexValue()
+	return
+}
+
+func (mvdt sandboxExternEntity) ValueAt(index uint) (value any, err error) {
+	switch index {
+	case 1:
+		return sandboxFunction(0), nil
+	case 2:
+		return sandboxMemory(0), nil
+	}
+	return nil, scale.ErrUnknownVaryingDataTypeValue
+}
+
+// sandboxFunction is the index of a supervisor function given to the dispatch thunk.
+type sandboxFunction uint32
+
+// sandboxMemory is the index of a sandbox memory.
+type sandboxMemory uint32
+
+// sandboxEnvironmentEntry is an entry of the environment definition of a sandbox instance.
+type sandboxEnvironmentEntry struct {
+	ModuleName []byte
+	FieldName  []byte
+	Entity     sandboxExternEntity
+}
+
+// sandboxEnvironmentDefinition defines the imports available to a sandbox instance,
+// it is the Go equivalent of `sp_sandbox::env::EnvironmentDefinition`.
+type sandboxEnvironmentDefinition struct {
+	Entries []sandboxEnvironmentEntry
+}
+
+func (e sandboxEnvironmentDefinition) lookup(moduleName, fieldName string) (entity any, ok bool) {
+	for _, entry := range e.Entries {
+		if string(entry.ModuleName) == moduleName && string(entry.FieldName) == fieldName {
+			return entry.Entity.inner, true
+		}
+	}
+	return nil, false
+}
+
+type sandboxInstance struct {
+	module api.Module
+	env    api.Module
+}
+
+// sandboxStore holds the sandbox instances and memories created by the runtime during a call.
+// The sandboxed modules are run by a separate wazero interpreter runtime, created on first use,
+// whose memories are bounded by the memory limit of the call.
+type sandboxStore struct {
+	runtime         wazero.Runtime
+	maxMemoryPages  uint32
+	instances       map[uint32]*sandboxInstance
+	memories        map[uint32]api.Module
+	nextInstanceIdx uint32
+	nextMemoryIdx   uint32
+}
+
+func newSandboxStore(maxMemoryPages uint32) *sandboxStore {
+	return &sandboxStore{
+		maxMemoryPages: maxMemoryPages,
+		instances:      make(map[uint32]*sandboxInstance),
+		memories:       make(map[uint32]api.Module),
+	}
+}
+
+func (s *sandboxStore) getRuntime(ctx context.Context) wazero.Runtime {
+	if s.runtime == nil {
+		// the sandboxed calls are closed with the runtime call executing them
+		config := wazero.NewRuntimeConfigInterpreter().
+			WithCloseOnContextDone(true).
+			WithMemoryLimitPages(s.maxMemoryPages)
+		s.runtime = wazero.NewRuntimeWithConfig(ctx, config)
+	}
+	return s.runtime
+}
+
+// close releases all the sandbox instances and memories.
+func (s *sandboxStore) close(ctx context.Context) error {
+	if s.runtime == nil {
+		return nil
+	}
+
+	err := s.runtime.Close(ctx)
+	s.runtime = nil
+	clear(s.instances)
+	clear(s.memories)
+	return err
+}
+
+func sandboxMemoryModuleName(memoryIdx uint32) string {
+	return "sandbox_memory_" + strconv.FormatUint(uint64(memoryIdx), 10)
+}
+
+// newMemory creates a sandbox memory and returns its index.
+func (s *sandboxStore) newMemory(ctx context.Context, initial, maximum uint32) (memoryIdx uint32, err error) {
+	memoryIdx = s.nextMemoryIdx
+	builder := s.getRuntime(ctx).NewHostModuleBuilder(sandboxMemoryModuleName(memoryIdx))
+	if maximum == sandboxMemoryUnlimited {
+		builder = builder.ExportMemory("memory", initial)
+	} else {
+		builder = builder.ExportMemoryWithMax("memory", initial, maximum)
+	}
+
+	module, err := builder.Instantiate(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("instantiating memory module: %w", err)
+	}
+
+	s.nextMemoryIdx++
+	s.memories[memoryIdx] = module
+	return memoryIdx, nil
+}
+
+func (s *sandboxStore) memory(memoryIdx uint32) (api.Memory, error) {
+	module, ok := s.memories[memoryIdx]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errSandboxMemoryNotFound, memoryIdx)
+	}
+	return module.ExportedMemory("memory"), nil
+}
+
+func (s *sandboxStore) teardownMemory(ctx context.Context, memoryIdx uint32) error {
+	module, ok := s.memories[memoryIdx]
+	if !ok {
+		return fmt.Errorf("%w: %d", errSandboxMemoryNotFound, memoryIdx)
+	}
+
+	delete(s.memories, memoryIdx)
+	return module.Close(ctx)
+}
+
+func (s *sandboxStore) instance(instanceIdx uint32) (*sandboxInstance, error) {
+	instance, ok := s.instances[instanceIdx]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", errSandboxInstanceNotFound, instanceIdx)
+	}
+	return instance, nil
+}
+
+func (s *sandboxStore) teardownInstance(ctx context.Context, instanceIdx uint32) error {
+	instance, ok := s.instances[instanceIdx]
+	if !ok {
+		return fmt.Errorf("%w: %d", errSandboxInstanceNotFound, instanceIdx)
+	}
+
+	delete(s.instances, instanceIdx)
+	err := instance.module.Close(ctx)
+	if err != nil {
+		return fmt.Errorf("closing instance module: %w", err)
+	}
+	return instance.env.Close(ctx)
+}
+
+// instantiate instantiates the given wasm code as a sandbox instance, resolving its imports
+// with the given environment definition, and returns the instance index. Imported functions
+// are dispatched to the supervisor through the function at index dispatchThunk of its table.
+// The error wraps errSandboxStartTrapped if the start function of the module failed.
+func (s *sandboxStore) instantiate(ctx context.Context, supervisor api.Module, dispatchThunk uint32,
+	code []byte, envDef sandboxEnvironmentDefinition) (instanceIdx uint32, err error) {
+	rt := s.getRuntime(ctx)
+	instanceIdx = s.nextInstanceIdx
+	envModuleName := "sandbox_env_" + strconv.FormatUint(uint64(instanceIdx), 10)
+
+	// wazero resolves imports by module name, so the imports of the code are renamed
+	// to point to the host module of this instance or to the imported sandbox memory.
+	var guestFunctions []sandboxFunction
+	code, hasStart, err := rewriteWasmImports(code, func(kind byte, moduleName, fieldName string) (string, string, error) {
+		entity, ok := envDef.lookup(moduleName, fieldName)
+		if !ok {
+			return "", "", fmt.Errorf("%w: %s:%s", errSandboxImportNotFound, moduleName, fieldName)
+		}
+
+		switch {
+		case kind == importKindFunction:
+			guestFunction, ok := entity.(sandboxFunction)
+			if !ok {
+				return "", "", fmt.Errorf("%w: %s:%s is not a function", errSandboxImportNotFound, moduleName, fieldName)
+			}
+			guestFunctions = append(guestFunctions, guestFunction)
+			return envModuleName, strconv.Itoa(len(guestFunctions) - 1), nil
+		case kind == importKindMemory:
+			memoryIdx, ok := entity.(sandboxMemory)
+			if !ok {
+				return "", "", fmt.Errorf("%w: %s:%s is not a memory", errSandboxImportNotFound, moduleName, fieldName)
+			}
+			if _, ok := s.memories[uint32(memoryIdx)]; !ok {
+				return "", "", fmt.Errorf("%w: %d", errSandboxMemoryNotFound, memoryIdx)
+			}
+			return sandboxMemoryModuleName(uint32(memoryIdx)), "memory", nil
+		default:
+			return "", "", fmt.Errorf("%w: %d", errSandboxUnsupportedKind, kind)
+		}
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rewriting imports: %w", err)
+	}
+
+	compiled, err := rt.CompileModule(ctx, code)
+	if err != nil {
+		return 0, fmt.Errorf("compiling module: %w", err)
+	}
+	defer compiled.Close(ctx)
+
+	builder := rt.NewHostModuleBuilder(envModuleName)
+	for _, definition := range compiled.ImportedFunctions() {
+		moduleName, fieldName, _ := definition.Import()
+		if moduleName != envModuleName {
+			continue
+		}
+
+		index, err := strconv.Atoi(fieldName)
+		if err != nil {
+			return 0, fmt.Errorf("parsing imported function index: %w", err)
+		}
+
+		guestFunction := guestFunctions[index]
+		params, results := definition.ParamTypes(), definition.ResultTypes()
+		builder.NewFunctionBuilder().
+			WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, _ api.Module, stack []uint64) {
+				err := dispatchToSupervisor(ctx, supervisor, dispatchThunk, guestFunction, params, results, stack)
+				if err != nil {
+					panic(err)
+				}
+			}), params, results).
+			Export(fieldName)
+	}
+
+	env, err := builder.Instantiate(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("instantiating environment module: %w", err)
+	}
+
+	moduleName := "sandbox_instance_" + strconv.FormatUint(uint64(instanceIdx), 10)
+	module, err := rt.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithName(moduleName))
+	if err != nil {
+		closeErr := env.Close(ctx)
+		if closeErr != nil {
+			logger.Warnf("closing sandbox environment module: %s", closeErr)
+		}
+
+		if hasStart {
+			return 0, fmt.Errorf("%w: %w", errSandboxStartTrapped, err)
+		}
+		return 0, fmt.Errorf("instantiating module: %w", err)
+	}
+
+	s.nextInstanceIdx++
+	s.instances[instanceIdx] = &sandboxInstance{
+		module: module,
+		env:    env,
+	}
+	return instanceIdx, nil
+}
+
+// invoke calls the exported function of the sandbox instance with the given arguments
+// and returns its result, which is nil if the function has no result.
+func (s *sandboxStore) invoke(ctx context.Context, instanceIdx uint32, name string, args []sandboxValue) (
+	result *sandboxValue, err error) {
+	instance, err := s.instance(instanceIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	function := instance.module.ExportedFunction(name)
+	if function == nil {
+		return nil, fmt.Errorf("%w: %s", ErrExportFunctionNotFound, name)
+	}
+
+	paramTypes := function.Definition().ParamTypes()
+	if len(paramTypes) != len(args) {
+		return nil, fmt.Errorf("expected %d arguments but got %d", len(paramTypes), len(args))
+	}
+
+	params := make([]uint64, len(args))
+	for i, arg := range args {
+		valueType, raw, err := arg.raw()
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", i, err)
+		}
+		if valueType != paramTypes[i] {
+			return nil, fmt.Errorf("argument %d: expected type %s but got %s",
+				i, api.ValueTypeName(paramTypes[i]), api.ValueTypeName(valueType))
+		}
+		params[i] = raw
+	}
+
+	results, err := function.Call(ctx, params...)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s: %w", name, err)
+	}
+
+	resultTypes := function.Definition().ResultTypes()
+	if len(resultTypes) == 0 {
+		return nil, nil
+	}
+
+	value, err := newSandboxValue(resultTypes[0], results[0])
+	if err != nil {
+		return nil, fmt.Errorf("converting result: %w", err)
+	}
+	return &value, nil
+}
+
+// globalValue returns the value of the global exported by the sandbox instance,
+// or nil if there is no such global.
+func (s *sandboxStore) globalValue(instanceIdx uint32, name string) (*sandboxValue, error) {
+	instance, err := s.instance(instanceIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	global := instance.module.ExportedGlobal(name)
+	if global == nil {
+		return nil, nil
+	}
+
+	value, err := newSandboxValue(global.Type(), global.Get())
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// dispatchToSupervisor calls the supervisor function with the given index through the dispatch
+// thunk of the supervisor, passing it the arguments from the stack and the state of the sandbox
+// call. The result of the function is written back to the stack.
+func dispatchToSupervisor(ctx context.Context, supervisor api.Module, dispatchThunk uint32,
+	guestFunction sandboxFunction, params, results []api.ValueType, stack []uint64) error {
+	rtCtx := ctx.Value(runtimeContextKey).(*runtime.Context)
+	if rtCtx == nil {
+		panic("nil runtime context")
+	}
+	state, _ := ctx.Value(sandboxStateKey).(uint32)
+
+	args := make([]sandboxValue, len(params))
+	for i, param := range params {
+		arg, err := newSandboxValue(param, stack[i])
+		if err != nil {
+			return fmt.Errorf("argument %d: %w", i, err)
+		}
+		args[i] = arg
+	}
+
+	encodedArgs, err := scale.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("encoding arguments: %w", err)
+	}
+
+	argsPointerSize, err := write(supervisor, rtCtx.Allocator, encodedArgs)
+	if err != nil {
+		return fmt.Errorf("writing arguments: %w", err)
+	}
+	argsPtr, argsSize := splitPointerSize(argsPointerSize)
+
+	const i32, i64 = api.ValueTypeI32, api.ValueTypeI64
+	thunk := table.LookupFunction(supervisor, 0, dispatchThunk, []api.ValueType{i32, i32, i32, i32}, []api.ValueType{i64})
+	values, err := thunk.Call(ctx, api.EncodeU32(argsPtr), argsSize, api.EncodeU32(state), uint64(guestFunction))
+	if err != nil {
+		return fmt.Errorf("calling dispatch thunk: %w", err)
+	}
+
+	err = rtCtx.Allocator.Deallocate(supervisor.Memory(), argsPtr)
+	if err != nil {
+		return fmt.Errorf("deallocating arguments: %w", err)
+	}
+
+	resultPtr, _ := splitPointerSize(values[0])
+	encodedResult := bytes.Clone(read(supervisor, values[0]))
+	err = rtCtx.Allocator.Deallocate(supervisor.Memory(), resultPtr)
+	if err != nil {
+		return fmt.Errorf("deallocating result: %w", err)
+	}
+
+	result, err := decodeSandboxHostResult(encodedResult)
+	if err != nil {
+		return fmt.Errorf("decoding result: %w", err)
+	}
+
+	if len(results) == 0 {
+		return nil
+	}
+
+	if result == nil {
+		return fmt.Errorf("expected a result of type %s but got none", api.ValueTypeName(results[0]))
+	}
+
+	valueType, raw, err := result.raw()
+	if err != nil {
+		return fmt.Errorf("converting result: %w", err)
+	}
+	if valueType != results[0] {
+		return fmt.Errorf("expected a result of type %s but got %s",
+			api.ValueTypeName(results[0]), api.ValueTypeName(valueType))
+	}
+	stack[0] = raw
+	return nil
+}
+
+// rewriteWasmImports rewrites the module and field names of the imports of the given
+// wasm module with the names returned by rename. It also returns whether the module
+// has a start function.
+func rewriteWasmImports(code []byte, rename func(kind byte, moduleName, fieldName string) (
+	newModuleName, newFieldName string, err error)) (rewritten []byte, hasStart bool, err error) {
+	header := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+	if !bytes.HasPrefix(code, header) {
+		return nil, false, fmt.Errorf("%w: invalid header", errInvalidWasmModule)
+	}
+
+	rewritten = append(rewritten, header...)
+	reader := bytes.NewReader(code[len(header):])
+	for reader.Len() > 0 {
+		sectionID, err := reader.ReadByte()
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: reading section id: %w", errInvalidWasmModule, err)
+		}
+
+		sectionSize, err := readULEB128(reader)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: reading section size: %w", errInvalidWasmModule, err)
+		}
+
+		section := make([]byte, sectionSize)
+		_, err = io.ReadFull(reader, section)
+		if err != nil {
+			return nil, false, fmt.Errorf("%w: reading section %d: %w", errInvalidWasmModule, sectionID, err)
+		}
+
+		switch sectionID {
+		case wasmImportSectionID:
+			section, err = rewriteImportSection(section, rename)
+			if err != nil {
+				return nil, false, err
+			}
+		case wasmStartSectionID:
+			hasStart = true
+		}
+
+		rewritten = append(rewritten, sectionID)
+		rewritten = appendULEB128(rewritten, uint32(len(section)))
+		rewritten = append(rewritten, section...)
+	}
+
+	return rewritten, hasStart, nil
+}
+
+func rewriteImportSection(section []byte, rename func(kind byte, moduleName, fieldName string) (
+	newModuleName, newFieldName string, err error)) (rewritten []byte, err error) {
+	reader := bytes.NewReader(section)
+	count, err := readULEB128(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading imports count: %w", errInvalidWasmModule, err)
+	}
+
+	rewritten = appendULEB128(rewritten, count)
+	for i := uint32(0); i < count; i++ {
+		moduleName, err := readWasmName(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading import %d module name: %w", errInvalidWasmModule, i, err)
+		}
+
+		fieldName, err := readWasmName(reader)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading import %d field name: %w", errInvalidWasmModule, i, err)
+		}
+
+		kind, err := reader.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading import %d kind: %w", errInvalidWasmModule, i, err)
+		}
+
+		descriptionStart := len(section) - reader.Len()
+		err = skipImportDescription(reader, kind)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading import %d description: %w", errInvalidWasmModule, i, err)
+		}
+		description := section[descriptionStart : len(section)-reader.Len()]
+
+		newModuleName, newFieldName, err := rename(kind, moduleName, fieldName)
+		if err != nil {
+			return nil, err
+		}
+
+		rewritten = appendULEB128(rewritten, uint32(len(newModuleName)))
+		rewritten = append(rewritten, newModuleName...)
+		rewritten = appendULEB128(rewritten, uint32(len(newFieldName)))
+		rewritten = append(rewritten, newFieldName...)
+		rewritten = append(rewritten, kind)
+		rewritten = append(rewritten, description...)
+	}
+
+	if reader.Len() != 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes in import section", errInvalidWasmModule, reader.Len())
+	}
+	return rewritten, nil
+}
+
+func skipImportDescription(reader *bytes.Reader, kind byte) (err error) {
+	switch kind {
+	case importKindFunction:
+		_, err = readULEB128(reader)
+		return err
+	case importKindTable:
+		_, err = reader.ReadByte() // reference type
+		if err != nil {
+			return err
+		}
+		return skipWasmLimits(reader)
+	case importKindMemory:
+		return skipWasmLimits(reader)
+	case importKindGlobal:
+		_, err = reader.Seek(2, io.SeekCurrent) // value type and mutability
+		return err
+	default:
+		return fmt.Errorf("unknown import kind %d", kind)
+	}
+}
+
+func skipWasmLimits(reader *bytes.Reader) error {
+	flags, err := reader.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	_, err = readULEB128(reader)
+	if err != nil {
+		return err
+	}
+
+	if flags&0x01 != 0 {
+		_, err = readULEB128(reader)
+	}
+	return err
+}
+
+func readWasmName(reader *bytes.Reader) (string, error) {
+	length, err := readULEB128(reader)
+	if err != nil {
+		return "", err
+	}
+
+	name := make([]byte, length)
+	_, err = io.ReadFull(reader, name)
+	if err != nil {
+		return "", err
+	}
+	return string(name), nil
+}
+
+func readULEB128(reader io.ByteReader) (value uint32, err error) {
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		value |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+	return 0, errors.New("leb128 value overflows uint32")
+}
+
+func appendULEB128(b []byte, value uint32) []byte {
+	for {
+		c := byte(value & 0x7f)
+		value >>= 7
+		if value == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
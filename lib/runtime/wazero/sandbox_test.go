@@ -0,0 +1,310 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package wazero_runtime
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/lib/runtime"
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var wasmHeader = []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+// sandboxTestModule is a wasm module importing env:memory, exporting the
+// function add(i32, i32) i32 and the immutable i32 global counter = 42.
+var sandboxTestModule = concatBytes(
+	wasmHeader,
+	// type section: (i32, i32) -> i32
+	[]byte{0x01, 0x07, 0x01, 0x60, 0x02, 0x7f, 0x7f, 0x01, 0x7f},
+	// import section: env:memory with 1 initial page
+	[]byte{0x02, 0x0f, 0x01, 0x03, 'e', 'n', 'v', 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00, 0x01},
+	// function section
+	[]byte{0x03, 0x02, 0x01, 0x00},
+	// global section: i32 const 42
+	[]byte{0x06, 0x06, 0x01, 0x7f, 0x00, 0x41, 0x2a, 0x0b},
+	// export section: add and counter
+	[]byte{0x07, 0x11, 0x02,
+		0x03, 'a', 'd', 'd', 0x00, 0x00,
+		0x07, 'c', 'o', 'u', 'n', 't', 'e', 'r', 0x03, 0x00},
+	// code section: local.get 0 local.get 1 i32.add
+	[]byte{0x0a, 0x09, 0x01, 0x07, 0x00, 0x20, 0x00, 0x20, 0x01, 0x6a, 0x0b},
+)
+
+// sandboxTrappingStartModule is a wasm module whose start function is unreachable.
+var sandboxTrappingStartModule = concatBytes(
+	wasmHeader,
+	// type section: () -> ()
+	[]byte{0x01, 0x04, 0x01, 0x60, 0x00, 0x00},
+	// function section
+	[]byte{0x03, 0x02, 0x01, 0x00},
+	// start section
+	[]byte{0x08, 0x01, 0x00},
+	// code section: unreachable
+	[]byte{0x0a, 0x05, 0x01, 0x03, 0x00, 0x00, 0x0b},
+)
+
+func concatBytes(slices ...[]byte) (b []byte) {
+	for _, slice := range slices {
+		b = append(b, slice...)
+	}
+	return b
+}
+
+func newTestSandboxEnvironmentEntry(t *testing.T, moduleName, fieldName string,
+	entity any) sandboxEnvironmentEntry {
+	t.Helper()
+	entry := sandboxEnvironmentEntry{
+		ModuleName: []byte(moduleName),
+		FieldName:  []byte(fieldName),
+	}
+	err := entry.Entity.SetValue(entity)
+	require.NoError(t, err)
+	return entry
+}
+
+func Test_rewriteWasmImports(t *testing.T) {
+	t.Parallel()
+
+	rename := func(kind byte, moduleName, fieldName string) (string, string, error) {
+		assert.Equal(t, importKindMemory, kind)
+		assert.Equal(t, "env", moduleName)
+		assert.Equal(t, "memory", fieldName)
+		return "sandbox_memory_0", "memory", nil
+	}
+
+	rewritten, hasStart, err := rewriteWasmImports(sandboxTestModule, rename)
+	require.NoError(t, err)
+	assert.False(t, hasStart)
+
+	expected := concatBytes(
+		sandboxTestModule[:17],
+		[]byte{0x02, 0x1c, 0x01,
+			0x10, 's', 'a', 'n', 'd', 'b', 'o', 'x', '_', 'm', 'e', 'm', 'o', 'r', 'y', '_', '0',
+			0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00, 0x01},
+		sandboxTestModule[34:],
+	)
+	assert.Equal(t, expected, rewritten)
+
+	_, hasStart, err = rewriteWasmImports(sandboxTrappingStartModule, rename)
+	require.NoError(t, err)
+	assert.True(t, hasStart)
+
+	_, _, err = rewriteWasmImports([]byte{0x00, 0x61}, rename)
+	assert.ErrorIs(t, err, errInvalidWasmModule)
+
+	_, _, err = rewriteWasmImports(sandboxTestModule[:20], rename)
+	assert.ErrorIs(t, err, errInvalidWasmModule)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func Test_decodeSandboxHostResult(t *testing.T) {
+	t.Parallel()
+
+	i32Value := func() *sandboxValue {
+		value := new(sandboxValue)
+		err := value.SetValue(sandboxI32(-2))
+		require.NoError(t, err)
+		return value
+	}
+
+	testCases := map[string]struct {
+		encoded    []byte
+		value      *sandboxValue
+		errWrapped error
+		errMessage string
+	}{
+		"too_short": {
+			encoded:    []byte{0},
+			errWrapped: io.ErrUnexpectedEOF,
+			errMessage: "unexpected EOF: 1 bytes",
+		},
+		"host_error": {
+			encoded:    []byte{1, 0},
+			errWrapped: errSandboxHostError,
+			errMessage: "sandbox host function returned an error",
+		},
+		"invalid_result_index": {
+			encoded:    []byte{2, 0},
+			errMessage: "invalid result index 2",
+		},
+		"unit": {
+			encoded: []byte{0, 0},
+		},
+		"i32_value": {
+			encoded: []byte{0, 1, 0, 0xfe, 0xff, 0xff, 0xff},
+			value:   i32Value(),
+		},
+		"invalid_return_value_index": {
+			encoded:    []byte{0, 2},
+			errMessage: "invalid return value index 2",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			value, err := decodeSandboxHostResult(testCase.encoded)
+
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			}
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.value, value)
+		})
+	}
+}
+
+func Test_sandboxEnvironmentDefinition_decode(t *testing.T) {
+	t.Parallel()
+
+	encoded := []byte{
+		8, // two entries
+		12, 'e', 'n', 'v', 12, 'a', 'd', 'd', 1, 5, 0, 0, 0,
+		12, 'e', 'n', 'v', 24, 'm', 'e', 'm', 'o', 'r', 'y', 2, 1, 0, 0, 0,
+	}
+
+	var envDef sandboxEnvironmentDefinition
+	err := scale.Unmarshal(encoded, &envDef)
+	require.NoError(t, err)
+
+	entity, ok := envDef.lookup("env", "add")
+	require.True(t, ok)
+	assert.Equal(t, sandboxFunction(5), entity)
+
+	entity, ok = envDef.lookup("env", "memory")
+	require.True(t, ok)
+	assert.Equal(t, sandboxMemory(1), entity)
+
+	_, ok = envDef.lookup("env", "missing")
+	assert.False(t, ok)
+}
+
+func Test_sandboxStore_memory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newSandboxStore(runtime.MaxMemoryPages)
+	t.Cleanup(func() {
+		err := store.close(ctx)
+		assert.NoError(t, err)
+	})
+
+	memoryIdx, err := store.newMemory(ctx, 1, sandboxMemoryUnlimited)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), memoryIdx)
+
+	memory, err := store.memory(memoryIdx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(65536), memory.Size())
+	ok := memory.Write(10, []byte{1, 2, 3})
+	require.True(t, ok)
+
+	memory, err = store.memory(memoryIdx)
+	require.NoError(t, err)
+	data, ok := memory.Read(10, 3)
+	require.True(t, ok)
+	assert.Equal(t, []byte{1, 2, 3}, data)
+
+	err = store.teardownMemory(ctx, memoryIdx)
+	require.NoError(t, err)
+
+	_, err = store.memory(memoryIdx)
+	assert.ErrorIs(t, err, errSandboxMemoryNotFound)
+	err = store.teardownMemory(ctx, memoryIdx)
+	assert.ErrorIs(t, err, errSandboxMemoryNotFound)
+}
+
+func Test_sandboxStore_memoryLimit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	const maxMemoryPages = 2
+	store := newSandboxStore(maxMemoryPages)
+	t.Cleanup(func() {
+		err := store.close(ctx)
+		assert.NoError(t, err)
+	})
+
+	// a memory without maximum grows up to the memory limit of the call
+	memoryIdx, err := store.newMemory(ctx, 1, sandboxMemoryUnlimited)
+	require.NoError(t, err)
+	memory, err := store.memory(memoryIdx)
+	require.NoError(t, err)
+	_, ok := memory.Grow(1)
+	require.True(t, ok)
+	_, ok = memory.Grow(1)
+	assert.False(t, ok)
+	assert.Equal(t, uint64(maxMemoryPages*65536), memory.Size())
+
+	_, err = store.newMemory(ctx, maxMemoryPages+1, sandboxMemoryUnlimited)
+	assert.Error(t, err)
+}
+
+func Test_sandboxStore_instantiate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := newSandboxStore(runtime.MaxMemoryPages)
+	t.Cleanup(func() {
+		err := store.close(ctx)
+		assert.NoError(t, err)
+	})
+
+	_, err := store.instantiate(ctx, nil, 0, sandboxTestModule, sandboxEnvironmentDefinition{})
+	assert.ErrorIs(t, err, errSandboxImportNotFound)
+
+	_, err = store.instantiate(ctx, nil, 0, sandboxTrappingStartModule, sandboxEnvironmentDefinition{})
+	assert.ErrorIs(t, err, errSandboxStartTrapped)
+
+	memoryIdx, err := store.newMemory(ctx, 1, 2)
+	require.NoError(t, err)
+
+	envDef := sandboxEnvironmentDefinition{
+		Entries: []sandboxEnvironmentEntry{
+			newTestSandboxEnvironmentEntry(t, "env", "memory", sandboxMemory(memoryIdx)),
+		},
+	}
+	instanceIdx, err := store.instantiate(ctx, nil, 0, sandboxTestModule, envDef)
+	require.NoError(t, err)
+
+	var a, b sandboxValue
+	require.NoError(t, a.SetValue(sandboxI32(40)))
+	require.NoError(t, b.SetValue(sandboxI32(2)))
+	result, err := store.invoke(ctx, instanceIdx, "add", []sandboxValue{a, b})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, sandboxI32(42), result.inner)
+
+	_, err = store.invoke(ctx, instanceIdx, "add", []sandboxValue{a})
+	assert.EqualError(t, err, "expected 2 arguments but got 1")
+
+	_, err = store.invoke(ctx, instanceIdx, "missing", nil)
+	assert.ErrorIs(t, err, ErrExportFunctionNotFound)
+
+	global, err := store.globalValue(instanceIdx, "counter")
+	require.NoError(t, err)
+	require.NotNil(t, global)
+	assert.Equal(t, sandboxI32(42), global.inner)
+
+	global, err = store.globalValue(instanceIdx, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, global)
+
+	err = store.teardownInstance(ctx, instanceIdx)
+	require.NoError(t, err)
+
+	_, err = store.invoke(ctx, instanceIdx, "add", []sandboxValue{a, b})
+	assert.ErrorIs(t, err, errSandboxInstanceNotFound)
+}
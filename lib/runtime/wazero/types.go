@@ -34,6 +34,10 @@ type FnParamResultType interface {
 type quintArgWithRet[T FnParamResultType, U FnParamResultType, V FnParamResultType, W FnParamResultType,
 	X FnParamResultType, R FnParamResultType] func(ctx context.Context, m api.Module, a T, b U, c V, d W, e X) R
 
+type sextArgWithRet[T FnParamResultType, U FnParamResultType, V FnParamResultType, W FnParamResultType,
+	X FnParamResultType, Y FnParamResultType, R FnParamResultType] func(ctx context.Context, m api.Module,
+	a T, b U, c V, d W, e X, f Y) R
+
 func noArgFn(f noArg) api.GoModuleFunc {
 	return func(ctx context.Context, m api.Module, _ []uint64) {
 		f(ctx, m)
@@ -98,3 +102,11 @@ func quintArgWithReturnFn[T FnParamResultType, U FnParamResultType, V FnParamRes
 		stack[0] = uint64(f(ctx, m, T(stack[0]), U(stack[1]), V(stack[2]), W(stack[3]), X(stack[4])))
 	}
 }
+
+func sextArgWithReturnFn[T FnParamResultType, U FnParamResultType, V FnParamResultType, W FnParamResultType,
+	X FnParamResultType, Y FnParamResultType, R FnParamResultType](
+	f sextArgWithRet[T, U, V, W, X, Y, R]) api.GoModuleFunc {
+	return func(ctx context.Context, m api.Module, stack []uint64) {
+		stack[0] = uint64(f(ctx, m, T(stack[0]), U(stack[1]), V(stack[2]), W(stack[3]), X(stack[4]), Y(stack[5])))
+	}
+}
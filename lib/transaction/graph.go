@@ -0,0 +1,520 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package transaction
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/ChainSafe/gossamer/lib/common"
+)
+
+// ErrTooLowPriority is returned when trying to add a transaction providing a tag already
+// provided by a transaction of the pool having an equal or higher priority
+var ErrTooLowPriority = errors.New("transaction priority is too low to replace another transaction")
+
+// Notifier is called with the status updates of the transactions of a Graph
+type Notifier func(ext types.Extrinsic, notification StatusNotification)
+
+// graphTransaction is a transaction of the Graph along with its position in the graph
+type graphTransaction struct {
+	*ValidTransaction
+	hash common.Hash
+
+	// validatedAt is the number of the block the validity of the transaction was
+	// computed for, used to drop the transaction once its longevity is exceeded.
+	validatedAt uint
+
+	// ready is true if every tag required by the transaction is provided, either by
+	// a block or by a ready transaction of the graph.
+	ready bool
+}
+
+// Graph is a transaction pool ordering transactions using the tags they require and provide.
+//
+// A transaction is ready once all the tags it requires are provided, either by a block or by
+// other ready transactions of the graph, and it is part of the future set otherwise. Only
+// one transaction can provide a tag, a transaction providing the tag of another transaction
+// replaces it if it has a higher priority.
+//
+// Ready transactions are popped by order of priority, and a transaction is only popped
+// once the transactions providing the tags it requires have been popped.
+type Graph struct {
+	transactions map[common.Hash]*graphTransaction
+
+	// providers maps a tag to the transaction providing it
+	providers map[string]common.Hash
+	// dependants maps a tag to the transactions requiring it
+	dependants map[string]map[common.Hash]struct{}
+	// provided maps the tags provided by transactions which left the graph to be
+	// included in a block, to the number of the block. The tags are kept until
+	// all the transactions of the graph are validated after the block.
+	provided map[string]uint
+
+	// ready holds the ready transactions which can be popped, that is the ready
+	// transactions whose required tags are all provided by blocks.
+	ready *PriorityQueue
+	// future holds the transactions which are not ready.
+	future *Pool
+
+	// blockNumber is the number of the best block, which the validity of
+	// imported transactions is computed for.
+	blockNumber uint
+
+	notify       Notifier
+	pollInterval time.Duration
+	mu           sync.Mutex
+}
+
+// NewGraph creates a new empty Graph. The notifier is called with the status updates of the
+// transactions while the graph is locked, so it must not call the graph.
+func NewGraph(notify Notifier) *Graph {
+	if notify == nil {
+		notify = func(types.Extrinsic, StatusNotification) {}
+	}
+
+	return &Graph{
+		transactions: make(map[common.Hash]*graphTransaction),
+		providers:    make(map[string]common.Hash),
+		dependants:   make(map[string]map[common.Hash]struct{}),
+		provided:     make(map[string]uint),
+		ready:        NewPriorityQueue(),
+		future:       NewPool(),
+		notify:       notify,
+		pollInterval: 10 * time.Millisecond,
+	}
+}
+
+// Import adds a transaction validated for the current block to the graph, as ready if all the
+// tags it requires are provided and as future otherwise. The transactions providing the same
+// tags as the transaction are replaced if they have a lower priority, else ErrTooLowPriority
+// is returned. The tags the transaction provides are no longer considered provided by a block.
+func (g *Graph) Import(vt *ValidTransaction) (common.Hash, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	hash := vt.Extrinsic.Hash()
+	if g.transactions[hash] != nil {
+		return hash, ErrTransactionExists
+	}
+
+	usurped := make(map[common.Hash]struct{})
+	for _, tag := range vt.Validity.Provides {
+		providerHash, ok := g.providers[string(tag)]
+		if !ok {
+			continue
+		}
+
+		if g.transactions[providerHash].Validity.Priority >= vt.Validity.Priority {
+			return hash, ErrTooLowPriority
+		}
+		usurped[providerHash] = struct{}{}
+	}
+
+	for usurpedHash := range usurped {
+		removed := g.remove(usurpedHash)
+		g.notify(removed.Extrinsic, StatusNotification{Status: Usurped, Hash: hash})
+	}
+
+	// the tags of a popped transaction pushed back to the graph are no longer provided
+	for _, tag := range vt.Validity.Provides {
+		delete(g.provided, string(tag))
+	}
+
+	tx := &graphTransaction{
+		ValidTransaction: vt,
+		hash:             hash,
+		validatedAt:      g.blockNumber,
+	}
+	g.transactions[hash] = tx
+	g.index(tx)
+
+	tx.ready = g.isReady(tx)
+	g.place(tx)
+	if tx.ready {
+		g.notify(vt.Extrinsic, StatusNotification{Status: Ready})
+	} else {
+		g.notify(vt.Extrinsic, StatusNotification{Status: Future})
+	}
+
+	g.refresh(g.dependantsOf(tx)...)
+	return hash, nil
+}
+
+// Update sets the validity of the transaction with the given hash, revalidated
+// for the current block. It returns false if the transaction is not in the graph.
+func (g *Graph) Update(hash common.Hash, validity *Validity) (updated bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx, ok := g.transactions[hash]
+	if !ok {
+		return false
+	}
+
+	previousDependants := g.dependantsOf(tx)
+	g.unindex(tx)
+	g.ready.Remove(hash)
+
+	tx.ValidTransaction = NewValidTransaction(tx.Extrinsic, validity)
+	tx.validatedAt = g.blockNumber
+	g.index(tx)
+
+	g.refresh(append([]common.Hash{hash}, append(previousDependants, g.dependantsOf(tx)...)...)...)
+	return true
+}
+
+// Remove removes the transaction with the given hash from the graph and returns it,
+// or returns nil if it is not in the graph. The transactions requiring the tags it
+// provides are moved to the future set.
+func (g *Graph) Remove(hash common.Hash) *ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx := g.remove(hash)
+	if tx == nil {
+		return nil
+	}
+	return tx.ValidTransaction
+}
+
+// Prune removes the transaction with the given hash from the graph once it is included in
+// a block and returns it, or returns nil if it is not in the graph. The tags it provides
+// are considered provided by the block until the graph is revalidated.
+func (g *Graph) Prune(hash common.Hash) *ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx := g.prune(hash)
+	if tx == nil {
+		return nil
+	}
+	return tx.ValidTransaction
+}
+
+// Pop removes the ready transaction with the highest priority whose required tags are
+// all provided by blocks and returns it, or returns nil if there is no such transaction.
+// The transaction is expected to be included in the next block, so the tags it provides
+// are considered provided, making its dependant transactions available.
+func (g *Graph) Pop() *ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	next := g.ready.Peek()
+	if next == nil {
+		return nil
+	}
+
+	tx := g.prune(next.Extrinsic.Hash())
+	return tx.ValidTransaction
+}
+
+// PopWithTimer returns the next transaction from the graph, see Pop.
+// When the timer expires, it returns `nil`.
+func (g *Graph) PopWithTimer(timerCh <-chan time.Time) (transaction *ValidTransaction) {
+	transaction = g.Pop()
+	if transaction != nil {
+		return transaction
+	}
+
+	pollTicker := time.NewTicker(g.pollInterval)
+	defer pollTicker.Stop()
+
+	for {
+		select {
+		case <-timerCh:
+			return nil
+		case <-pollTicker.C:
+		}
+
+		transaction := g.Pop()
+		if transaction != nil {
+			return transaction
+		}
+	}
+}
+
+// Peek returns the next transaction to be popped without removing it from the graph
+func (g *Graph) Peek() *ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.ready.Peek()
+}
+
+// Get returns the transaction with the given hash, or nil if it is not in the graph
+func (g *Graph) Get(hash common.Hash) *ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	tx, ok := g.transactions[hash]
+	if !ok {
+		return nil
+	}
+	return tx.ValidTransaction
+}
+
+// Pending returns all the transactions of the graph, ready and future
+func (g *Graph) Pending() []*ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	txs := make([]*ValidTransaction, 0, len(g.transactions))
+	for _, tx := range g.transactions {
+		txs = append(txs, tx.ValidTransaction)
+	}
+	return txs
+}
+
+// Ready returns the ready transactions of the graph
+func (g *Graph) Ready() []*ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var txs []*ValidTransaction
+	for _, tx := range g.transactions {
+		if tx.ready {
+			txs = append(txs, tx.ValidTransaction)
+		}
+	}
+	return txs
+}
+
+// Future returns the transactions of the graph which are not ready
+func (g *Graph) Future() []*ValidTransaction {
+	return g.future.Transactions()
+}
+
+// Len returns the number of transactions in the graph
+func (g *Graph) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.transactions)
+}
+
+// Oldest returns at most limit transactions of the graph, ordered by the block number their
+// validity was computed for, and the future transactions first for the same block number.
+func (g *Graph) Oldest(limit int) []*ValidTransaction {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	txs := make([]*graphTransaction, 0, len(g.transactions))
+	for _, tx := range g.transactions {
+		txs = append(txs, tx)
+	}
+
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].validatedAt == txs[j].validatedAt {
+			return !txs[i].ready && txs[j].ready
+		}
+		return txs[i].validatedAt < txs[j].validatedAt
+	})
+
+	if len(txs) > limit {
+		txs = txs[:limit]
+	}
+
+	oldest := make([]*ValidTransaction, len(txs))
+	for i, tx := range txs {
+		oldest[i] = tx.ValidTransaction
+	}
+	return oldest
+}
+
+// SetBlockNumber sets the number of the best block, which the validity of the imported
+// transactions is computed for. The transactions whose longevity is exceeded are dropped.
+func (g *Graph) SetBlockNumber(blockNumber uint) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.blockNumber = blockNumber
+
+	for hash, tx := range g.transactions {
+		if blockNumber <= tx.validatedAt || uint64(blockNumber-tx.validatedAt) <= tx.Validity.Longevity {
+			continue
+		}
+
+		dropped := g.remove(hash)
+		if dropped != nil {
+			g.notify(dropped.Extrinsic, StatusNotification{Status: Dropped})
+		}
+	}
+
+	g.pruneProvided()
+}
+
+// pruneProvided deletes the tags provided by blocks every transaction is validated after.
+func (g *Graph) pruneProvided() {
+	oldestValidation := g.blockNumber
+	for _, tx := range g.transactions {
+		if tx.validatedAt < oldestValidation {
+			oldestValidation = tx.validatedAt
+		}
+	}
+
+	for tag, blockNumber := range g.provided {
+		if blockNumber <= oldestValidation {
+			delete(g.provided, tag)
+		}
+	}
+}
+
+// prune removes the transaction with the given hash, considering the tags it provides as provided
+// by the next block.
+func (g *Graph) prune(hash common.Hash) *graphTransaction {
+	tx, ok := g.transactions[hash]
+	if !ok {
+		return nil
+	}
+
+	for _, tag := range tx.Validity.Provides {
+		g.provided[string(tag)] = g.blockNumber + 1
+	}
+	return g.remove(hash)
+}
+
+// remove removes the transaction with the given hash from the graph and refreshes the
+// transactions requiring the tags it provides.
+func (g *Graph) remove(hash common.Hash) *graphTransaction {
+	tx, ok := g.transactions[hash]
+	if !ok {
+		return nil
+	}
+
+	dependants := g.dependantsOf(tx)
+	g.unindex(tx)
+	delete(g.transactions, hash)
+	g.ready.Remove(hash)
+	g.future.Remove(hash)
+
+	g.refresh(dependants...)
+	return tx
+}
+
+// index adds the tags of the transaction to the providers and dependants maps
+func (g *Graph) index(tx *graphTransaction) {
+	for _, tag := range tx.Validity.Provides {
+		if _, ok := g.providers[string(tag)]; !ok {
+			g.providers[string(tag)] = tx.hash
+		}
+	}
+
+	for _, tag := range tx.Validity.Requires {
+		dependants, ok := g.dependants[string(tag)]
+		if !ok {
+			dependants = make(map[common.Hash]struct{})
+			g.dependants[string(tag)] = dependants
+		}
+		dependants[tx.hash] = struct{}{}
+	}
+}
+
+// unindex removes the tags of the transaction from the providers and dependants maps
+func (g *Graph) unindex(tx *graphTransaction) {
+	for _, tag := range tx.Validity.Provides {
+		if g.providers[string(tag)] == tx.hash {
+			delete(g.providers, string(tag))
+		}
+	}
+
+	for _, tag := range tx.Validity.Requires {
+		dependants := g.dependants[string(tag)]
+		delete(dependants, tx.hash)
+		if len(dependants) == 0 {
+			delete(g.dependants, string(tag))
+		}
+	}
+}
+
+// dependantsOf returns the hashes of the transactions requiring a tag provided by the transaction
+func (g *Graph) dependantsOf(tx *graphTransaction) (hashes []common.Hash) {
+	for _, tag := range tx.Validity.Provides {
+		for hash := range g.dependants[string(tag)] {
+			if hash != tx.hash {
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+	return hashes
+}
+
+// isReady returns true if every tag required by the transaction is provided by a block or by
+// a ready transaction of the graph.
+func (g *Graph) isReady(tx *graphTransaction) bool {
+	for _, tag := range tx.Validity.Requires {
+		if _, ok := g.provided[string(tag)]; ok {
+			continue
+		}
+
+		providerHash, ok := g.providers[string(tag)]
+		if !ok || providerHash == tx.hash || !g.transactions[providerHash].ready {
+			return false
+		}
+	}
+	return true
+}
+
+// isAvailable returns true if every tag required by the transaction is provided by a block,
+// meaning the transaction can be popped.
+func (g *Graph) isAvailable(tx *graphTransaction) bool {
+	for _, tag := range tx.Validity.Requires {
+		if _, ok := g.provided[string(tag)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// place adds the transaction to the ready queue or the future pool depending on its state
+func (g *Graph) place(tx *graphTransaction) {
+	if !tx.ready {
+		g.ready.Remove(tx.hash)
+		g.future.Insert(tx.ValidTransaction)
+		return
+	}
+
+	g.future.Remove(tx.hash)
+	if !g.isAvailable(tx) {
+		g.ready.Remove(tx.hash)
+		return
+	}
+
+	if !g.ready.Exists(tx.hash) {
+		_, _ = g.ready.Push(tx.ValidTransaction)
+	}
+}
+
+// refresh updates the state of the transactions with the given hashes, and of the
+// transactions depending on them if their readiness changes.
+func (g *Graph) refresh(hashes ...common.Hash) {
+	for len(hashes) > 0 {
+		hash := hashes[0]
+		hashes = hashes[1:]
+
+		tx, ok := g.transactions[hash]
+		if !ok {
+			continue
+		}
+
+		ready := g.isReady(tx)
+		readinessChanged := ready != tx.ready
+		tx.ready = ready
+		g.place(tx)
+
+		if !readinessChanged {
+			continue
+		}
+
+		if ready {
+			g.notify(tx.Extrinsic, StatusNotification{Status: Ready})
+		} else {
+			g.notify(tx.Extrinsic, StatusNotification{Status: Future})
+		}
+		hashes = append(hashes, g.dependantsOf(tx)...)
+	}
+}
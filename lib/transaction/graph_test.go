@@ -0,0 +1,282 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package transaction
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ChainSafe/gossamer/dot/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedNotification struct {
+	extrinsic    string
+	notification StatusNotification
+}
+
+// newRecordingGraph returns a graph recording its notifications in the returned slice
+func newRecordingGraph() (*Graph, *[]recordedNotification) {
+	notifications := new([]recordedNotification)
+	graph := NewGraph(func(ext types.Extrinsic, notification StatusNotification) {
+		*notifications = append(*notifications, recordedNotification{
+			extrinsic:    string(ext),
+			notification: notification,
+		})
+	})
+	return graph, notifications
+}
+
+func newTestTransaction(extrinsic string, priority uint64, requires, provides []string) *ValidTransaction {
+	toTags := func(tags []string) [][]byte {
+		bytes := make([][]byte, len(tags))
+		for i, tag := range tags {
+			bytes[i] = []byte(tag)
+		}
+		return bytes
+	}
+
+	return NewValidTransaction(types.Extrinsic(extrinsic),
+		NewValidity(priority, toTags(requires), toTags(provides), math.MaxUint64, true))
+}
+
+func TestGraph_Import(t *testing.T) {
+	t.Parallel()
+
+	graph, notifications := newRecordingGraph()
+
+	second := newTestTransaction("second", 30, []string{"nonce1"}, []string{"nonce2"})
+	first := newTestTransaction("first", 20, []string{"nonce0"}, []string{"nonce1"})
+	zeroth := newTestTransaction("zeroth", 10, nil, []string{"nonce0"})
+	other := newTestTransaction("other", 15, nil, []string{"other0"})
+
+	for _, tx := range []*ValidTransaction{second, first} {
+		_, err := graph.Import(tx)
+		require.NoError(t, err)
+	}
+	assert.Nil(t, graph.Peek())
+	assert.ElementsMatch(t, []*ValidTransaction{second, first}, graph.Future())
+
+	for _, tx := range []*ValidTransaction{zeroth, other} {
+		_, err := graph.Import(tx)
+		require.NoError(t, err)
+	}
+	assert.Empty(t, graph.Future())
+	assert.ElementsMatch(t, []*ValidTransaction{zeroth, first, second, other}, graph.Ready())
+
+	_, err := graph.Import(first)
+	assert.ErrorIs(t, err, ErrTransactionExists)
+
+	expectedNotifications := []recordedNotification{
+		{extrinsic: "second", notification: StatusNotification{Status: Future}},
+		{extrinsic: "first", notification: StatusNotification{Status: Future}},
+		{extrinsic: "zeroth", notification: StatusNotification{Status: Ready}},
+		{extrinsic: "first", notification: StatusNotification{Status: Ready}},
+		{extrinsic: "second", notification: StatusNotification{Status: Ready}},
+		{extrinsic: "other", notification: StatusNotification{Status: Ready}},
+	}
+	assert.Equal(t, expectedNotifications, *notifications)
+
+	// transactions are popped by priority once their required tags are provided
+	expectedOrder := []*ValidTransaction{other, zeroth, first, second}
+	for _, expected := range expectedOrder {
+		assert.Equal(t, expected, graph.Pop())
+	}
+	assert.Nil(t, graph.Pop())
+	assert.Zero(t, graph.Len())
+}
+
+func TestGraph_Import_replacement(t *testing.T) {
+	t.Parallel()
+
+	graph, notifications := newRecordingGraph()
+
+	original := newTestTransaction("original", 10, nil, []string{"nonce0"})
+	dependant := newTestTransaction("dependant", 10, []string{"nonce0"}, []string{"nonce1"})
+	for _, tx := range []*ValidTransaction{original, dependant} {
+		_, err := graph.Import(tx)
+		require.NoError(t, err)
+	}
+
+	lower := newTestTransaction("lower", 10, nil, []string{"nonce0"})
+	_, err := graph.Import(lower)
+	assert.ErrorIs(t, err, ErrTooLowPriority)
+
+	higher := newTestTransaction("higher", 11, nil, []string{"nonce0"})
+	higherHash, err := graph.Import(higher)
+	require.NoError(t, err)
+
+	assert.Nil(t, graph.Get(original.Extrinsic.Hash()))
+	assert.ElementsMatch(t, []*ValidTransaction{higher, dependant}, graph.Ready())
+
+	expectedNotifications := []recordedNotification{
+		{extrinsic: "original", notification: StatusNotification{Status: Ready}},
+		{extrinsic: "dependant", notification: StatusNotification{Status: Ready}},
+		{extrinsic: "dependant", notification: StatusNotification{Status: Future}},
+		{extrinsic: "original", notification: StatusNotification{Status: Usurped, Hash: higherHash}},
+		{extrinsic: "higher", notification: StatusNotification{Status: Ready}},
+		{extrinsic: "dependant", notification: StatusNotification{Status: Ready}},
+	}
+	assert.Equal(t, expectedNotifications, *notifications)
+}
+
+func TestGraph_Import_popped(t *testing.T) {
+	t.Parallel()
+
+	graph, _ := newRecordingGraph()
+
+	zeroth := newTestTransaction("zeroth", 10, nil, []string{"nonce0"})
+	first := newTestTransaction("first", 20, []string{"nonce0"}, []string{"nonce1"})
+	for _, tx := range []*ValidTransaction{zeroth, first} {
+		_, err := graph.Import(tx)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, zeroth, graph.Pop())
+	assert.Equal(t, first, graph.Peek())
+
+	// the popped transaction is pushed back since it was not included in a block,
+	// so its dependant waits for it again.
+	_, err := graph.Import(zeroth)
+	require.NoError(t, err)
+	assert.NotContains(t, graph.provided, "nonce0")
+	assert.ElementsMatch(t, []*ValidTransaction{zeroth, first}, graph.Ready())
+	assert.Equal(t, zeroth, graph.Pop())
+	assert.Equal(t, first, graph.Pop())
+}
+
+func TestGraph_Remove(t *testing.T) {
+	t.Parallel()
+
+	graph, _ := newRecordingGraph()
+
+	zeroth := newTestTransaction("zeroth", 10, nil, []string{"nonce0"})
+	first := newTestTransaction("first", 10, []string{"nonce0"}, []string{"nonce1"})
+	for _, tx := range []*ValidTransaction{zeroth, first} {
+		_, err := graph.Import(tx)
+		require.NoError(t, err)
+	}
+
+	removed := graph.Remove(zeroth.Extrinsic.Hash())
+	assert.Equal(t, zeroth, removed)
+	assert.Nil(t, graph.Remove(zeroth.Extrinsic.Hash()))
+	assert.Equal(t, []*ValidTransaction{first}, graph.Future())
+	assert.Nil(t, graph.Pop())
+
+	_, err := graph.Import(zeroth)
+	require.NoError(t, err)
+
+	// once pruned, the tags of the transaction are provided by the block
+	pruned := graph.Prune(zeroth.Extrinsic.Hash())
+	assert.Equal(t, zeroth, pruned)
+	assert.Empty(t, graph.Future())
+	assert.Equal(t, first, graph.Peek())
+}
+
+func TestGraph_Update(t *testing.T) {
+	t.Parallel()
+
+	graph, _ := newRecordingGraph()
+
+	first := newTestTransaction("first", 10, []string{"nonce0"}, []string{"nonce1"})
+	_, err := graph.Import(first)
+	require.NoError(t, err)
+	assert.Equal(t, []*ValidTransaction{first}, graph.Future())
+
+	// the transaction providing nonce0 got included in a block of another node
+	graph.SetBlockNumber(1)
+	updated := graph.Update(first.Extrinsic.Hash(),
+		NewValidity(20, nil, [][]byte{[]byte("nonce1")}, math.MaxUint64, true))
+	assert.True(t, updated)
+	assert.Empty(t, graph.Future())
+
+	popped := graph.Pop()
+	require.NotNil(t, popped)
+	assert.Equal(t, uint64(20), popped.Validity.Priority)
+
+	assert.False(t, graph.Update(first.Extrinsic.Hash(), first.Validity))
+}
+
+func TestGraph_SetBlockNumber(t *testing.T) {
+	t.Parallel()
+
+	graph, notifications := newRecordingGraph()
+
+	shortLived := newTestTransaction("short", 10, nil, []string{"short0"})
+	shortLived.Validity.Longevity = 2
+	longLived := newTestTransaction("long", 10, nil, []string{"long0"})
+	for _, tx := range []*ValidTransaction{shortLived, longLived} {
+		_, err := graph.Import(tx)
+		require.NoError(t, err)
+	}
+
+	graph.SetBlockNumber(2)
+	assert.Equal(t, 2, graph.Len())
+
+	graph.SetBlockNumber(3)
+	assert.Equal(t, []*ValidTransaction{longLived}, graph.Pending())
+	assert.Equal(t, recordedNotification{
+		extrinsic:    "short",
+		notification: StatusNotification{Status: Dropped},
+	}, (*notifications)[len(*notifications)-1])
+
+	// the tags provided by popped transactions are kept until the
+	// transactions of the graph are validated after their block.
+	popped := graph.Pop()
+	assert.Equal(t, longLived, popped)
+	dependant := newTestTransaction("dependant", 10, []string{"long0"}, nil)
+	_, err := graph.Import(dependant)
+	require.NoError(t, err)
+	assert.Equal(t, dependant, graph.Peek())
+
+	graph.SetBlockNumber(4)
+	assert.Equal(t, dependant, graph.Peek())
+	graph.Update(dependant.Extrinsic.Hash(), NewValidity(10, nil, nil, math.MaxUint64, true))
+	graph.SetBlockNumber(4)
+	assert.Empty(t, graph.provided)
+}
+
+func TestGraph_Oldest(t *testing.T) {
+	t.Parallel()
+
+	graph, _ := newRecordingGraph()
+
+	old := newTestTransaction("old", 10, nil, nil)
+	_, err := graph.Import(old)
+	require.NoError(t, err)
+
+	graph.SetBlockNumber(1)
+	ready := newTestTransaction("ready", 10, nil, nil)
+	future := newTestTransaction("future", 10, []string{"missing"}, nil)
+	for _, tx := range []*ValidTransaction{ready, future} {
+		_, err := graph.Import(tx)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []*ValidTransaction{old, future}, graph.Oldest(2))
+	assert.Equal(t, []*ValidTransaction{old, future, ready}, graph.Oldest(10))
+}
+
+func TestGraph_PopWithTimer(t *testing.T) {
+	t.Parallel()
+
+	graph, _ := newRecordingGraph()
+
+	timer := time.NewTimer(50 * time.Millisecond)
+	defer timer.Stop()
+	assert.Nil(t, graph.PopWithTimer(timer.C))
+
+	tx := newTestTransaction("tx", 10, nil, nil)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, err := graph.Import(tx)
+		assert.NoError(t, err)
+	}()
+
+	timer = time.NewTimer(time.Second)
+	defer timer.Stop()
+	assert.Equal(t, tx, graph.PopWithTimer(timer.C))
+}
@@ -98,17 +98,23 @@ func NewPriorityQueue() *PriorityQueue {
 
 // RemoveExtrinsic removes an extrinsic from the queue
 func (spq *PriorityQueue) RemoveExtrinsic(ext types.Extrinsic) {
+	spq.Remove(ext.Hash())
+}
+
+// Remove removes the transaction with the given extrinsic hash from the queue
+// and returns it, or returns nil if it is not in the queue.
+func (spq *PriorityQueue) Remove(extHash common.Hash) (transaction *ValidTransaction) {
 	spq.Lock()
 	defer spq.Unlock()
 
-	hash := ext.Hash()
-	item, ok := spq.txs[hash]
+	item, ok := spq.txs[extHash]
 	if !ok {
-		return
+		return nil
 	}
 
 	heap.Remove(&spq.pq, item.index)
-	delete(spq.txs, hash)
+	delete(spq.txs, extHash)
+	return item.data
 }
 
 // Exists returns true if a hash is in the txs map, false otherwise
@@ -273,6 +273,28 @@ func TestRemoveExtrinsic(t *testing.T) {
 	}
 }
 
+func TestPriorityQueue_Remove(t *testing.T) {
+	pq := NewPriorityQueue()
+
+	first := &ValidTransaction{
+		Extrinsic: []byte("rats"),
+		Validity:  &Validity{Priority: 5},
+	}
+	second := &ValidTransaction{
+		Extrinsic: []byte("arecool"),
+		Validity:  &Validity{Priority: 4},
+	}
+	pq.Push(first)
+	pq.Push(second)
+
+	removed := pq.Remove(first.Extrinsic.Hash())
+	assert.Equal(t, first, removed)
+	assert.Nil(t, pq.Remove(first.Extrinsic.Hash()))
+
+	assert.Equal(t, second, pq.Pop())
+	assert.Nil(t, pq.Pop())
+}
+
 func Test_PriorityQueue_PopWithTimer(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,47 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package grandpa
+
+import (
+	"sync"
+	"time"
+)
+
+type timer struct {
+	waker   *waker
+	expired bool
+	mtx     sync.Mutex
+}
+
+// NewTimer returns a `Timer` which elapses once a value is received from the given channel,
+// usually the channel of a `time.Timer`.
+func NewTimer(in <-chan time.Time) Timer {
+	t := &timer{}
+	go func() {
+		<-in
+		t.mtx.Lock()
+		t.expired = true
+		waker := t.waker
+		t.mtx.Unlock()
+		// the timer must be expired before waking up the poller
+		if waker != nil {
+			waker.wake()
+		}
+	}()
+	return t
+}
+
+// SetWaker sets the waker woken up when the timer elapses.
+func (t *timer) SetWaker(waker *waker) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.waker = waker
+}
+
+// Elapsed returns true once the timer has elapsed.
+func (t *timer) Elapsed() (bool, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.expired, nil
+}
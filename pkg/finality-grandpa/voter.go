@@ -201,6 +201,19 @@ type finalizedNotification[Hash, Number, Signature, ID any] struct {
 	// Incoming messages.
 	// Incoming chan SignedMessageError
 	Incoming Input[Hash, Number, Signature, ID]
+	// Votes cast by the local voter in the round before a restart. The round
+	// resumes after the last vote cast instead of casting new votes, the
+	// environment is responsible for broadcasting them again and for
+	// returning them in the incoming messages.
+	HasVoted HasVoted[Hash, Number]
+}
+
+// HasVoted holds the votes cast by the local voter in a round,
+// a nil vote means the voter did not vote in the subround.
+type HasVoted[Hash, Number any] struct {
+	Propose   *PrimaryPropose[Hash, Number]
+	Prevote   *Prevote[Hash, Number]
+	Precommit *Precommit[Hash, Number]
 }
 
 type buffered[I any] struct {
@@ -339,6 +352,11 @@ type CommunicationOut struct {
 	CommunicationOutCommit[Hash, Number, Signature, ID]
 }
 
+// Value returns the variant of the `CommunicationOut`, currently always a `CommunicationOutCommit`.
+func (co CommunicationOut) Value() any {
+	return co.variant
+}
+
 func newCommunicationOut[
 	Hash constraints.Ordered,
 	Number constraints.Unsigned,
@@ -374,6 +392,11 @@ type CommitProcessingOutcome struct {
 	variant any
 }
 
+// Value returns the variant of the `CommitProcessingOutcome`.
+func (cpo CommitProcessingOutcome) Value() any {
+	return cpo.variant
+}
+
 // CommitProcessingOutcomeGood means it was beneficial to process this commit.
 type CommitProcessingOutcomeGood GoodCommit
 
@@ -425,6 +448,11 @@ type CatchUpProcessingOutcome struct {
 	variant any
 }
 
+// Value returns the variant of the `CatchUpProcessingOutcome`.
+func (cpo CatchUpProcessingOutcome) Value() any {
+	return cpo.variant
+}
+
 func newCatchUpProcessingOutcome[T CatchUpProcessingOutcomes](variant T) CatchUpProcessingOutcome {
 	return CatchUpProcessingOutcome{
 		variant: variant,
@@ -453,6 +481,7 @@ type GoodCatchUp struct{
 // BadCatchUp is the result of processing for a bad catch up.
 type BadCatchUp struct{}
 
+// CommunicationIn is communication between nodes that is not round-localised.
 type CommunicationIn struct {
 	variant any
 }
@@ -464,7 +493,8 @@ func setCommunicationIn[
 	ci.variant = variant
 }
 
-func newCommunicationIn[
+// NewCommunicationIn creates a new `CommunicationIn` holding the given variant.
+func NewCommunicationIn[
 	Hash constraints.Ordered, Number constraints.Unsigned, Signature comparable, ID constraints.Ordered,
 	T CommunicationInVariants[Hash, Number, Signature, ID],
 ](variant T) CommunicationIn {
@@ -473,6 +503,7 @@ func newCommunicationIn[
 	return ci
 }
 
+// CommunicationInVariants is interface constraint of `CommunicationIn`
 type CommunicationInVariants[
 	Hash constraints.Ordered,
 	Number constraints.Unsigned,
@@ -481,6 +512,8 @@ func newCommunicationIn[
 ] interface {
 	CommunicationInCommit[Hash, Number, Signature, ID] | CommunicationInCatchUp[Hash, Number, Signature, ID]
 }
+
+// CommunicationInCommit is a commit message for the given round number.
 type CommunicationInCommit[
 	Hash constraints.Ordered,
 	Number constraints.Unsigned,
@@ -492,6 +525,7 @@ func newCommunicationIn[
 	Callback      func(CommitProcessingOutcome)
 }
 
+// CommunicationInCatchUp is a catch-up message.
 type CommunicationInCatchUp[
 	Hash constraints.Ordered,
 	Number constraints.Unsigned,
@@ -502,7 +536,9 @@ func newCommunicationIn[
 	Callback func(CatchUpProcessingOutcome)
 }
 
-type globalInItem struct {
+// GlobalInItem is the item type of the input stream of global messages given to `NewVoter`.
+// A non-nil error stops the voter.
+type GlobalInItem struct {
 	CommunicationIn
 	Error error
 }
@@ -531,7 +567,7 @@ type Voter[Hash constraints.Ordered, Number constraints.Unsigned, Signature comp
 	inner                  *innerVoterState[Hash, Number, Signature, ID, Environment[Hash, Number, Signature, ID]]
 	finalizedNotifications *wakerChan[finalizedNotification[Hash, Number, Signature, ID]]
 	lastFinalizedNumber    Number
-	globalIn               *wakerChan[globalInItem]
+	globalIn               *wakerChan[GlobalInItem]
 	globalOut              *buffered[CommunicationOut]
 	// the commit protocol might finalize further than the current round (if we're
 	// behind), we keep track of last finalized in round so we don't violate any
@@ -557,7 +593,7 @@ type Voter[Hash constraints.Ordered, Number constraints.Unsigned, Signature comp
 func NewVoter[Hash constraints.Ordered, Number constraints.Unsigned, Signature comparable, ID constraints.Ordered](
 	env Environment[Hash, Number, Signature, ID],
 	voters VoterSet[ID],
-	globalIn chan globalInItem,
+	globalIn chan GlobalInItem,
 	lastRoundNumber uint64,
 	lastRoundVotes []SignedMessage[Hash, Number, Signature, ID],
 	lastRoundBase HashNumber[Hash, Number],
@@ -706,6 +742,7 @@ func (v *Voter[Hash, Number, Signature, ID]) processIncoming(waker *waker) error
 					// commit to the environment (if valid and higher than current finalized)
 					validationResult, err := ValidateCommit(commit, v.voters, v.env.(Chain[Hash, Number]))
 					if err != nil {
+						v.inner.Unlock()
 						return err
 					}
 					if validationResult.Valid() {
@@ -755,6 +792,7 @@ func (v *Voter[Hash, Number, Signature, ID]) processIncoming(waker *waker) error
 					if processCatchUpOutcome != nil {
 						processCatchUpOutcome(newCatchUpProcessingOutcome(CatchUpProcessingOutcomeBad{}))
 					}
+					v.inner.Unlock()
 					return nil
 				}
 
@@ -957,7 +995,6 @@ func (v *Voter[Hash, Number, Signature, ID]) poll(waker *waker) (bool, error) {
 	E Environment[Hash, Number, Signature, ID],
 ] struct {
 	inner *innerVoterState[Hash, Number, Signature, ID, E]
-	mtx   sync.Mutex
 }
 
 func (svs *sharedVoteState[Hash, Number, Signature, ID, E]) Get() VoterStateReport[ID] {
@@ -972,8 +1009,8 @@ func (svs *sharedVoteState[Hash, Number, Signature, ID, E]) Get() VoterStateRepo
 		}
 	}
 
-	svs.mtx.Lock()
-	defer svs.mtx.Unlock()
+	svs.inner.Lock()
+	defer svs.inner.Unlock()
 
 	bestRoundNum, bestRound := toRoundState(svs.inner.bestRound)
 	backgroundRounds := svs.inner.pastRounds.votingRounds()
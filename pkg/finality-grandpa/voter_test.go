@@ -33,7 +33,7 @@ func TestVoter_TalkingToMyself(t *testing.T) {
 	voter, globalOut := NewVoter[string, uint32, Signature, ID](
 		&env,
 		*voters,
-		make(chan globalInItem),
+		make(chan GlobalInItem),
 		0,
 		nil,
 		lastFinalized,
@@ -84,7 +84,7 @@ func TestVoter_FinalizingAtFaultThreshold(t *testing.T) {
 		voter, globalOut := NewVoter[string, uint32, Signature, ID](
 			&env,
 			*voters,
-			make(chan globalInItem),
+			make(chan GlobalInItem),
 			0,
 			nil,
 			lastFinalized,
@@ -138,7 +138,7 @@ func TestVoter_ExposingVoterState(t *testing.T) {
 		voter, globalOut := NewVoter[string, uint32, Signature, ID](
 			&env,
 			*voterSet,
-			make(chan globalInItem),
+			make(chan GlobalInItem),
 			0,
 			nil,
 			lastFinalized,
@@ -222,7 +222,7 @@ func TestVoter_BroadcastCommit(t *testing.T) {
 	voter, globalOut := NewVoter[string, uint32, Signature, ID](
 		&env,
 		*voterSet,
-		make(chan globalInItem),
+		make(chan GlobalInItem),
 		0,
 		nil,
 		lastFinalized,
@@ -399,9 +399,11 @@ func TestVoter_ImportCommitForAnyRound(t *testing.T) {
 		Number: 0,
 		Commit: commit,
 	})
+	// listen for finalisations before sending the commit to not miss it
+	finalizedStream := env.FinalizedStream()
 	commitsOut <- co
 
-	finalized := <-env.FinalizedStream()
+	finalized := <-finalizedStream
 	assert.Equal(t, finalized.Commit, commit)
 
 	err := voter.Stop()
@@ -462,7 +464,7 @@ func TestVoter_SkipsToLatestRoundAfterCatchUp(t *testing.T) {
 	}
 
 	// send in a catch-up message for round 5.
-	ci := newCommunicationIn[string, uint32, Signature, ID](CommunicationInCatchUp[string, uint32, Signature, ID]{
+	ci := NewCommunicationIn[string, uint32, Signature, ID](CommunicationInCatchUp[string, uint32, Signature, ID]{
 		CatchUp: CatchUp[string, uint32, Signature, ID]{
 			BaseNumber:  1,
 			BaseHash:    GenesisHash,
@@ -477,11 +479,12 @@ func TestVoter_SkipsToLatestRoundAfterCatchUp(t *testing.T) {
 	_, ok := voterState.Get().BackgroundRounds[5]
 	assert.False(t, ok)
 
+	// listen for finalisations before spawning the voter to not miss it
+	finalized := env.FinalizedStream()
+
 	// spawn the voter in the background
 	go unsyncedVoter.Start()
 
-	finalized := env.FinalizedStream()
-
 	// wait until it's caught up, it should skip to round 6 and send a
 	// finality notification for the block that was finalized by catching
 	// up.
@@ -497,8 +500,8 @@ func TestVoter_SkipsToLatestRoundAfterCatchUp(t *testing.T) {
 		}
 	}()
 
-	<-caughtUp
 	<-finalized
+	<-caughtUp
 	assert.Equal(t,
 		struct {
 			Number     uint64
@@ -674,6 +677,65 @@ func TestVoter_PickUpFromPriorWithGrandparentStatus(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestVotingRound_ResumesAfterVotesCast(t *testing.T) {
+	var localID ID = 5
+	voters := NewVoterSet([]IDWeight[ID]{
+		{localID, 100},
+	})
+
+	network := NewNetwork()
+	defer network.Stop()
+
+	vote := targetHashTargetNumber[string, uint32]{"A", 1}
+	testCases := map[string]struct {
+		localID  ID
+		hasVoted HasVoted[string, uint32]
+		state    state
+	}{
+		"not_voted": {
+			localID: localID,
+			state:   stateStart[Timer]{},
+		},
+		"proposed": {
+			localID:  localID,
+			hasVoted: HasVoted[string, uint32]{Propose: (*PrimaryPropose[string, uint32])(&vote)},
+			state:    stateProposed[Timer]{},
+		},
+		"prevoted": {
+			localID: localID,
+			hasVoted: HasVoted[string, uint32]{
+				Propose: (*PrimaryPropose[string, uint32])(&vote),
+				Prevote: (*Prevote[string, uint32])(&vote),
+			},
+			state: statePrevoted[Timer]{},
+		},
+		"precommitted": {
+			localID: localID,
+			hasVoted: HasVoted[string, uint32]{
+				Prevote:   (*Prevote[string, uint32])(&vote),
+				Precommit: (*Precommit[string, uint32])(&vote),
+			},
+			state: statePrecommitted{},
+		},
+		"not_a_voter": {
+			localID:  localID + 1,
+			hasVoted: HasVoted[string, uint32]{Precommit: (*Precommit[string, uint32])(&vote)},
+			state:    stateStart[Timer]{},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			env := newEnvironment(network, testCase.localID)
+			env.hasVoted = testCase.hasVoted
+
+			round := newVotingRound[string, uint32, Signature, ID](
+				1, *voters, HashNumber[string, uint32]{GenesisHash, 0}, nil, nil, &env)
+			assert.IsType(t, testCase.state, round.state)
+		})
+	}
+}
+
 func TestBuffered(_ *testing.T) {
 	in := make(chan int32)
 	buffered := newBuffered(in)
@@ -117,13 +117,27 @@ func newVotingRound[
 		voting = votingNo
 	}
 
+	roundState := newState[Timer, hashBestChain[Hash, Number]](
+		stateStart[Timer]{roundData.PrevoteTimer, roundData.PrecommitTimer})
+	// resume the round after the votes cast before a restart to not equivocate
+	if hasVoted := roundData.HasVoted; voting.isActive() {
+		switch {
+		case hasVoted.Precommit != nil:
+			roundState = newState[Timer, hashBestChain[Hash, Number]](statePrecommitted{})
+		case hasVoted.Prevote != nil:
+			roundState = newState[Timer, hashBestChain[Hash, Number]](statePrevoted[Timer]{roundData.PrecommitTimer})
+		case hasVoted.Propose != nil:
+			roundState = newState[Timer, hashBestChain[Hash, Number]](
+				stateProposed[Timer]{roundData.PrevoteTimer, roundData.PrecommitTimer})
+		}
+	}
+
 	return votingRound[Hash, Number, Signature, ID, E]{
-		votes:    votes,
-		voting:   voting,
-		incoming: newWakerChan(roundData.Incoming),
-		outgoing: newBuffered(outgoing),
-		state: newState[Timer, hashBestChain[Hash, Number]](
-			stateStart[Timer]{roundData.PrevoteTimer, roundData.PrecommitTimer}),
+		votes:             votes,
+		voting:            voting,
+		incoming:          newWakerChan(roundData.Incoming),
+		outgoing:          newBuffered(outgoing),
+		state:             roundState,
 		bridgedRoundState: nil,
 		primaryBlock:      nil,
 		bestFinalized:     nil,
@@ -7,6 +7,7 @@
 	"bytes"
 	"fmt"
 
+	"github.com/ChainSafe/gossamer/internal/database"
 	"github.com/ChainSafe/gossamer/lib/common"
 	"github.com/ChainSafe/gossamer/pkg/trie"
 	"github.com/ChainSafe/gossamer/pkg/trie/codec"
@@ -133,8 +134,7 @@ func loadStorageValue(db db.DBGetter, node *node.Node) error {
 		return nil
 	}
 
-	prefixedKey := bytes.Join([][]byte{node.PartialKey, node.StorageValue[:]}, nil)
-	rawStorageValue, err := db.Get(prefixedKey)
+	rawStorageValue, err := db.Get(node.StorageValue)
 	if err != nil {
 		return err
 	}
@@ -326,8 +326,7 @@ func (t *InMemoryTrie) writeDirtyNode(db db.DBPutter, n *node.Node) (err error)
 			return err
 		}
 
-		prefixedKey := bytes.Join([][]byte{n.PartialKey, hashedValue[:]}, nil)
-		err = db.Put(prefixedKey, n.StorageValue)
+		err = db.Put(hashedValue[:], n.StorageValue)
 	}
 
 	if err != nil {
@@ -381,22 +380,26 @@ func (t *InMemoryTrie) writeDirtyNode(db db.DBPutter, n *node.Node) (err error)
 	return nil
 }
 
-// GetChangedNodeHashes returns the two sets of hashes for all nodes
-// inserted and deleted in the state trie since the last snapshot.
-// Returned inserted map is safe for mutation, but deleted is not safe for mutation.
-func (t *InMemoryTrie) GetChangedNodeHashes() (inserted, deleted map[common.Hash]struct{}, err error) {
-	inserted = make(map[common.Hash]struct{})
+// GetChangedNodeHashes returns the hashes of all nodes inserted and deleted in the state trie
+// since the last snapshot, with the number of times each of them was inserted or deleted.
+// The deleted nodes are only tracked once each.
+func (t *InMemoryTrie) GetChangedNodeHashes() (inserted, deleted map[common.Hash]uint32, err error) {
+	inserted = make(map[common.Hash]uint32)
 	err = t.getInsertedNodeHashesAtNode(t.root, inserted)
 	if err != nil {
 		return nil, nil, fmt.Errorf("getting inserted node hashes: %w", err)
 	}
 
-	deleted = t.deltas.Deleted()
+	deletedHashes := t.deltas.Deleted()
+	deleted = make(map[common.Hash]uint32, len(deletedHashes))
+	for nodeHash := range deletedHashes {
+		deleted[nodeHash] = 1
+	}
 
 	return inserted, deleted, nil
 }
 
-func (t *InMemoryTrie) getInsertedNodeHashesAtNode(n *node.Node, nodeHashes map[common.Hash]struct{}) (err error) {
+func (t *InMemoryTrie) getInsertedNodeHashesAtNode(n *node.Node, nodeHashes map[common.Hash]uint32) (err error) {
 	if n == nil || !n.Dirty {
 		return nil
 	}
@@ -420,7 +423,7 @@ func (t *InMemoryTrie) getInsertedNodeHashesAtNode(n *node.Node, nodeHashes map[
 	}
 
 	nodeHash := common.NewHash(merkleValue)
-	nodeHashes[nodeHash] = struct{}{}
+	nodeHashes[nodeHash]++
 
 	if n.Kind() != node.Branch {
 		return nil
@@ -440,3 +443,105 @@ func (t *InMemoryTrie) getInsertedNodeHashesAtNode(n *node.Node, nodeHashes map[
 
 	return nil
 }
+
+// MigrateHashedValueKeys moves the hashed storage values of the trie with the given root hash,
+// and of its child tries, from the key made of the partial key of their node followed by the
+// value hash they used to be stored at, to the key made of the value hash only.
+// The hashes of the nodes already visited are added to the visited set, so the nodes shared
+// by the tries of successive blocks are only migrated once. It returns the number of values moved.
+func MigrateHashedValueKeys(db db.RWDatabase, rootHash common.Hash,
+	visited map[common.Hash]struct{}) (moved uint, err error) {
+	type pendingNode struct {
+		hash common.Hash
+		// keyNibbles are the nibbles of the key up to the node partial key
+		keyNibbles []byte
+	}
+
+	batch := db.NewBatch()
+	defer batch.Reset()
+
+	childStorageKeyNibbles := codec.KeyLEToNibbles(ChildStorageKeyPrefix)
+	pending := []pendingNode{{hash: rootHash}}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		_, seen := visited[current.hash]
+		if seen || current.hash == trie.EmptyHash {
+			continue
+		}
+		visited[current.hash] = struct{}{}
+
+		encodedNode, err := db.Get(current.hash[:])
+		if err != nil {
+			return moved, fmt.Errorf("getting node with hash %s: %w", current.hash, err)
+		}
+
+		decodedNode, err := node.Decode(bytes.NewReader(encodedNode))
+		if err != nil {
+			return moved, fmt.Errorf("decoding node with hash %s: %w", current.hash, err)
+		}
+
+		keyNibbles := append(bytes.Clone(current.keyNibbles), decodedNode.PartialKey...)
+		if decodedNode.IsHashedValue {
+			migrated, err := migrateHashedValue(db, batch, decodedNode)
+			if err != nil {
+				return moved, fmt.Errorf("migrating value of node with hash %s: %w", current.hash, err)
+			}
+			if migrated {
+				moved++
+			}
+		} else if len(decodedNode.StorageValue) == common.HashLength &&
+			bytes.HasPrefix(keyNibbles, childStorageKeyNibbles) {
+			pending = append(pending, pendingNode{hash: common.BytesToHash(decodedNode.StorageValue)})
+		}
+
+		if decodedNode.Kind() == node.Leaf {
+			continue
+		}
+
+		for i, child := range decodedNode.Children {
+			if child == nil || len(child.MerkleValue) < common.HashLength {
+				continue
+			}
+
+			childKeyNibbles := append(bytes.Clone(keyNibbles), byte(i))
+			pending = append(pending, pendingNode{
+				hash:       common.BytesToHash(child.MerkleValue),
+				keyNibbles: childKeyNibbles,
+			})
+		}
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return moved, fmt.Errorf("flushing migrated values: %w", err)
+	}
+	return moved, nil
+}
+
+// migrateHashedValue moves the value of the node to the key made of its hash, and deletes the
+// value at the key prefixed with the partial key of the node in any case.
+func migrateHashedValue(db db.DBGetter, batch database.Batch, n *node.Node) (migrated bool, err error) {
+	prefixedKey := bytes.Join([][]byte{n.PartialKey, n.StorageValue}, nil)
+	err = batch.Del(prefixedKey)
+	if err != nil {
+		return false, fmt.Errorf("deleting value with hash 0x%x: %w", n.StorageValue, err)
+	}
+
+	value, err := db.Get(n.StorageValue)
+	if err == nil && value != nil {
+		return false, nil
+	}
+
+	value, err = db.Get(prefixedKey)
+	if err != nil {
+		return false, fmt.Errorf("getting value with hash 0x%x: %w", n.StorageValue, err)
+	}
+
+	err = batch.Put(n.StorageValue, value)
+	if err != nil {
+		return false, fmt.Errorf("putting value with hash 0x%x: %w", n.StorageValue, err)
+	}
+	return true, nil
+}
@@ -4,6 +4,7 @@
 package inmemory
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ChainSafe/gossamer/internal/database"
@@ -333,3 +334,89 @@ func Test_Trie_PutChild_Store_Load(t *testing.T) {
 		assert.Equal(t, trie.String(), trieFromDB.String())
 	}
 }
+
+func Test_MigrateHashedValueKeys(t *testing.T) {
+	t.Parallel()
+
+	largeValue := bytes.Repeat([]byte{1}, 40)
+	childLargeValue := bytes.Repeat([]byte{2}, 40)
+
+	tr := NewEmptyTrie()
+	tr.SetVersion(trie.V1)
+	err := tr.Put([]byte("alpha"), largeValue)
+	require.NoError(t, err)
+	err = tr.Put([]byte("beta"), []byte{1})
+	require.NoError(t, err)
+	err = tr.PutIntoChild([]byte("child"), []byte("gamma"), childLargeValue)
+	require.NoError(t, err)
+
+	db := newTestDB(t)
+	err = tr.WriteDirty(db)
+	require.NoError(t, err)
+	rootHash := tr.MustHash()
+
+	childTrie, err := tr.GetChild([]byte("child"))
+	require.NoError(t, err)
+
+	// store the hashed values at the keys prefixed with the node partial key used before
+	valueNodes := []*node.Node{
+		nodeWithValue(tr.root, largeValue),
+		nodeWithValue(childTrie.(*InMemoryTrie).root, childLargeValue),
+	}
+	prefixedKeys := make([][]byte, len(valueNodes))
+	for i, valueNode := range valueNodes {
+		valueHash := common.MustBlake2bHash(valueNode.StorageValue)
+		err = db.Del(valueHash[:])
+		require.NoError(t, err)
+		prefixedKeys[i] = bytes.Join([][]byte{valueNode.PartialKey, valueHash[:]}, nil)
+		err = db.Put(prefixedKeys[i], valueNode.StorageValue)
+		require.NoError(t, err)
+	}
+
+	visited := make(map[common.Hash]struct{})
+	moved, err := MigrateHashedValueKeys(db, rootHash, visited)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), moved)
+
+	trieFromDB := NewEmptyTrie()
+	err = trieFromDB.Load(db, rootHash)
+	require.NoError(t, err)
+	assert.Equal(t, largeValue, trieFromDB.Get([]byte("alpha")))
+	childValue, err := trieFromDB.GetFromChild([]byte("child"), []byte("gamma"))
+	require.NoError(t, err)
+	assert.Equal(t, childLargeValue, childValue)
+	for _, prefixedKey := range prefixedKeys {
+		_, err = db.Get(prefixedKey)
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	}
+
+	// the nodes already visited are not migrated again
+	moved, err = MigrateHashedValueKeys(db, rootHash, visited)
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+
+	// the prefixed key of a value already stored at its hash is deleted
+	err = db.Put(prefixedKeys[0], largeValue)
+	require.NoError(t, err)
+	moved, err = MigrateHashedValueKeys(db, rootHash, make(map[common.Hash]struct{}))
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+	_, err = db.Get(prefixedKeys[0])
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func nodeWithValue(n *node.Node, value []byte) *node.Node {
+	if n == nil {
+		return nil
+	}
+	if bytes.Equal(n.StorageValue, value) {
+		return n
+	}
+	for _, child := range n.Children {
+		found := nodeWithValue(child, value)
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -310,14 +310,14 @@ func TestTrieDiff(t *testing.T) {
 
 	_, deletedNodeHashes, err := newTrie.GetChangedNodeHashes()
 	assert.NoError(t, err)
-	expectedDeletedNodeHashes := map[common.Hash]struct{}{
+	expectedDeletedNodeHashes := map[common.Hash]uint32{
 		// root branch hash which was modified (by its descendants).
 		// Other nodes result in an encoding of less than 32B so they are not
 		// tracked since they are inlined in the branch.
 		{0xa9, 0x76, 0xfa, 0x55, 0x6d, 0x65, 0x24, 0x3c,
 			0x3, 0x80, 0x89, 0xd4, 0x15, 0xd, 0xb1, 0x9a,
 			0xe4, 0xb6, 0x8a, 0x60, 0xe5, 0x4d, 0xea, 0x68,
-			0x9c, 0xab, 0xbf, 0xbb, 0xc0, 0xfc, 0x72, 0x48}: {},
+			0x9c, 0xab, 0xbf, 0xbb, 0xc0, 0xfc, 0x72, 0x48}: 1,
 	}
 	assert.Equal(t, expectedDeletedNodeHashes, deletedNodeHashes)
 
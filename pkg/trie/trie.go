@@ -48,7 +48,7 @@ type PrefixTrieWrite interface {
 }
 
 type TrieDeltas interface {
-	GetChangedNodeHashes() (inserted, deleted map[common.Hash]struct{}, err error)
+	GetChangedNodeHashes() (inserted, deleted map[common.Hash]uint32, err error)
 	HandleTrackedDeltas(success bool, pendingDeltas tracking.Getter)
 }
 
@@ -6,6 +6,7 @@
 import (
 	"bytes"
 	"fmt"
+	"iter"
 
 	"github.com/ChainSafe/gossamer/pkg/trie/triedb/codec"
 	"github.com/ChainSafe/gossamer/pkg/trie/triedb/hash"
@@ -432,6 +433,12 @@ func (ri *rawIterator[H, Hasher]) nextRawItem(fwd bool) (*rawItem[H], error) {
 //
 // Must be called with the same db as when the iterator was created.
 func (ri *rawIterator[H, Hasher]) NextItem() (*TrieItem, error) {
+	return ri.nextItemAfter(nil)
+}
+
+// nextItemAfter fetches the next trie item whose key is strictly greater than the
+// given key, if not nil, without loading the values of the keys skipped.
+func (ri *rawIterator[H, Hasher]) nextItemAfter(after []byte) (*TrieItem, error) {
 	for {
 		rawItem, err := ri.nextRawItem(true)
 		if err != nil {
@@ -451,6 +458,9 @@ func (ri *rawIterator[H, Hasher]) NextItem() (*TrieItem, error) {
 		if maybeExtraNibble != nil {
 			return nil, fmt.Errorf("ValueAtIncompleteKey: %v %v", key, *maybeExtraNibble)
 		}
+		if after != nil && bytes.Compare(key, after) <= 0 {
+			continue
+		}
 
 		switch value := value.(type) {
 		case codec.HashedValue[H]:
@@ -471,3 +481,188 @@ type TrieItem struct {
 	Key   []byte
 	Value []byte
 }
+
+// Fetches the next trie key without loading its value.
+//
+// Must be called with the same db as when the iterator was created.
+func (ri *rawIterator[H, Hasher]) NextKey() ([]byte, error) {
+	for {
+		rawItem, err := ri.nextRawItem(true)
+		if err != nil {
+			return nil, err
+		}
+		if rawItem == nil {
+			return nil, nil
+		}
+		extracted := rawItem.extractKey()
+		if extracted == nil {
+			continue
+		}
+		if extracted.Padding != nil {
+			return nil, fmt.Errorf("ValueAtIncompleteKey: %v %v", extracted.Key, *extracted.Padding)
+		}
+		return bytes.Clone(extracted.Key), nil
+	}
+}
+
+// newIterator commits the pending changes, since the iterator only walks
+// persisted nodes, and returns an iterator over the keys with the given prefix
+// placed at the given seek key.
+func (t *TrieDB[H, Hasher]) newIterator(prefix, seek []byte) (*rawIterator[H, Hasher], error) {
+	err := t.commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing trie changes: %w", err)
+	}
+
+	if len(prefix) == 0 {
+		iter, err := newRawIterator(t)
+		if err != nil {
+			return nil, err
+		}
+		if len(seek) > 0 {
+			_, err = iter.seek(seek, true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return iter, nil
+	}
+
+	if len(seek) == 0 {
+		return newPrefixedRawIterator(t, prefix)
+	}
+	return newPrefixedRawIteratorThenSeek(t, prefix, seek)
+}
+
+// keys returns an iterator over the keys with the given prefix which are
+// strictly greater than the given key.
+func (t *TrieDB[H, Hasher]) keys(prefix, after []byte) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		iter, err := t.newIterator(prefix, after)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for {
+			key, err := iter.NextKey()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if key == nil {
+				return
+			}
+			if after != nil && bytes.Compare(key, after) <= 0 {
+				continue
+			}
+			if !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+// EntriesFrom returns an iterator over the key-value pairs of the trie whose key is strictly
+// greater than the given key. The iteration stops at the first error, which wraps
+// [ErrIncompleteDB] when the nodes or the value of the next entry are missing from
+// a partial database such as a proof.
+// Note pending changes are committed to the database first.
+func (t *TrieDB[H, Hasher]) EntriesFrom(key []byte) iter.Seq2[TrieItem, error] {
+	return func(yield func(TrieItem, error) bool) {
+		iter, err := t.newIterator(nil, key)
+		if err != nil {
+			yield(TrieItem{}, err)
+			return
+		}
+
+		for {
+			item, err := iter.nextItemAfter(key)
+			if err != nil {
+				yield(TrieItem{}, err)
+				return
+			}
+			if item == nil {
+				return
+			}
+			if !yield(TrieItem{Key: bytes.Clone(item.Key), Value: item.Value}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// keysOrLog drops the iteration error, logging it, for the methods
+// of the [trie.TrieRead] interface which cannot return errors.
+func keysOrLog(keys iter.Seq2[[]byte, error]) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for key, err := range keys {
+			if err != nil {
+				logger.Errorf("iterating over trie keys: %s", err)
+				return
+			}
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Entries returns all the key-value pairs in the trie.
+// Note pending changes are committed to the database first.
+func (t *TrieDB[H, Hasher]) Entries() (keyValueMap map[string][]byte) {
+	keyValueMap = make(map[string][]byte)
+
+	iter, err := t.newIterator(nil, nil)
+	if err != nil {
+		logger.Errorf("iterating over trie entries: %s", err)
+		return keyValueMap
+	}
+
+	for {
+		item, err := iter.NextItem()
+		if err != nil {
+			logger.Errorf("iterating over trie entries: %s", err)
+			return keyValueMap
+		}
+		if item == nil {
+			return keyValueMap
+		}
+		keyValueMap[string(item.Key)] = item.Value
+	}
+}
+
+// NextKey returns the next key in the trie in lexicographic order.
+// It returns nil if no next key is found.
+// Note pending changes are committed to the database first.
+func (t *TrieDB[H, Hasher]) NextKey(key []byte) []byte {
+	for nextKey := range t.KeysFrom(key) {
+		return nextKey
+	}
+	return nil
+}
+
+// KeysFrom returns an iterator over all keys in the trie that are greater than the given key.
+// Note pending changes are committed to the database first.
+func (t *TrieDB[H, Hasher]) KeysFrom(key []byte) iter.Seq[[]byte] {
+	if key == nil {
+		key = []byte{}
+	}
+	return keysOrLog(t.keys(nil, key))
+}
+
+// PrefixedKeys returns an iterator over all keys in the trie that have the given prefix.
+// Note pending changes are committed to the database first.
+func (t *TrieDB[H, Hasher]) PrefixedKeys(prefix []byte) iter.Seq[[]byte] {
+	return keysOrLog(t.keys(prefix, nil))
+}
+
+// GetKeysWithPrefix returns all keys in the trie that have the given prefix
+// in lexicographic order.
+// Note pending changes are committed to the database first.
+func (t *TrieDB[H, Hasher]) GetKeysWithPrefix(prefix []byte) (keysLE [][]byte) {
+	for key := range t.PrefixedKeys(prefix) {
+		keysLE = append(keysLE, key)
+	}
+	return keysLE
+}
@@ -63,7 +63,12 @@ func (l *TrieLookup[H, Hasher, QueryItem]) recordAccess(access TrieAccess) {
 // The given fullKey should be the full key to the data that is requested. This will
 // be used when there is a cache to potentially speed up the lookup.
 func (l *TrieLookup[H, Hasher, QueryItem]) Lookup(fullKey []byte) (*QueryItem, error) {
-	nibbleKey := nibbles.NewNibbles(slices.Clone(fullKey))
+	return l.lookup(fullKey, nibbles.NewNibbles(slices.Clone(fullKey)))
+}
+
+// lookup looks up the given nibbleKey, which is the remaining part of the
+// fullKey to look up from the node referenced by the [TrieLookup] hash.
+func (l *TrieLookup[H, Hasher, QueryItem]) lookup(fullKey []byte, nibbleKey nibbles.Nibbles) (*QueryItem, error) {
 	if l.cache != nil {
 		return l.lookupWithCache(fullKey, nibbleKey)
 	}
@@ -145,8 +150,8 @@ func (l *TrieLookup[H, Hasher, QueryItem]) lookupWithCache(
 			hash := cachedVal.Hash
 			if data != nil {
 				// inline is either when no limit defined or when content
-				// is less than the limit.
-				isInline := l.layout.MaxInlineValue() > len(data)
+				// does not exceed the limit.
+				isInline := len(data) <= l.layout.MaxInlineValue()
 				if valueRecordingRequired && !isInline {
 					// As a value is only raw data, we can directly record it.
 					l.recordAccess(ValueAccess[H]{
@@ -221,6 +226,10 @@ func lookupWithCacheInternal[H hash.Hash, Hasher hash.Hasher[H], R, QueryItem an
 					return nil, ErrIncompleteDB
 				}
 			}
+			// nodes can be missing from partial databases such as proofs
+			if nodeData == nil && depth > 0 {
+				return nil, ErrIncompleteDB
+			}
 			reader := bytes.NewReader(nodeData)
 			decoded, err := codec.Decode[H](reader)
 			if err != nil {
@@ -338,6 +347,10 @@ func lookupWithoutCache[H hash.Hash, Hasher hash.Hasher[H], QueryItem, R any](
 				return nil, ErrIncompleteDB
 			}
 		}
+		// nodes can be missing from partial databases such as proofs
+		if nodeData == nil && depth > 0 {
+			return nil, ErrIncompleteDB
+		}
 
 		l.recordAccess(EncodedNodeAccess[H]{Hash: hash, EncodedNode: nodeData})
 
@@ -408,6 +421,7 @@ func lookupWithoutCache[H hash.Hash, Hasher hash.Hasher[H], QueryItem, R any](
 				}
 			case codec.Empty:
 				l.recordAccess(NonExistingNodeAccess{FullKey: fullKey})
+				return nil, nil
 			default:
 				panic("unreachable")
 			}
@@ -108,7 +108,7 @@ func (vr newValueRef[H]) equal(other nodeValue) bool {
 }
 
 func NewValue[H hash.Hash](data []byte, threshold int) nodeValue {
-	if len(data) >= threshold {
+	if len(data) > threshold {
 		return newValueRef[H]{
 			hash: *new(H),
 			data: data,
@@ -0,0 +1,60 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package triedb
+
+import (
+	"sync"
+
+	lrucache "github.com/ChainSafe/gossamer/lib/utils/lru-cache"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/hash"
+)
+
+// DefaultNodeCacheCapacity is the default number of trie nodes kept by a [NodeCache].
+const DefaultNodeCacheCapacity = 100_000
+
+// NodeCache is a [TrieCache] only caching trie nodes, which are referenced by their
+// hash and can then be shared by tries with different roots. Values are not cached
+// since they depend on the trie root. It is safe for concurrent use.
+type NodeCache[H hash.Hash] struct {
+	mtx   sync.Mutex
+	nodes *lrucache.LRUCache[H, CachedNode[H]]
+}
+
+// NewNodeCache creates a new [NodeCache] holding at most capacity nodes.
+func NewNodeCache[H hash.Hash](capacity uint) *NodeCache[H] {
+	return &NodeCache[H]{
+		nodes: lrucache.NewLRUCache[H, CachedNode[H]](capacity),
+	}
+}
+
+// GetValue always returns nil since values are not cached.
+func (*NodeCache[H]) GetValue([]byte) CachedValue[H] { return nil }
+
+// SetValue does nothing since values are not cached.
+func (*NodeCache[H]) SetValue([]byte, CachedValue[H]) {}
+
+// GetOrInsertNode returns the node with the given hash, fetching
+// and caching it if it is not in the cache yet.
+func (nc *NodeCache[H]) GetOrInsertNode(hash H, fetchNode func() (CachedNode[H], error)) (CachedNode[H], error) {
+	if node := nc.GetNode(hash); node != nil {
+		return node, nil
+	}
+
+	node, err := fetchNode()
+	if err != nil {
+		return nil, err
+	}
+
+	nc.mtx.Lock()
+	defer nc.mtx.Unlock()
+	nc.nodes.Put(hash, node)
+	return node, nil
+}
+
+// GetNode returns the node with the given hash or nil if it is not cached.
+func (nc *NodeCache[H]) GetNode(hash H) CachedNode[H] {
+	nc.mtx.Lock()
+	defer nc.mtx.Unlock()
+	return nc.nodes.Get(hash)
+}
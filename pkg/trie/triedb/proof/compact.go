@@ -0,0 +1,405 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package proof
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/pkg/scale"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/codec"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb/hash"
+)
+
+// escapeCompactHeader prefixes the nodes of a compact proof whose hashed value is
+// omitted, the value being the item following the node in the proof.
+const escapeCompactHeader byte = 0x01
+
+var (
+	ErrIncompleteCompactProof = errors.New("incomplete compact proof")
+	ErrInvalidChildRoot       = errors.New("invalid child trie root")
+	ErrExtraneousChildProof   = errors.New("child trie proof without child trie root")
+	ErrExtraneousChildNode    = errors.New("extraneous node in compact proof")
+)
+
+// childStorageKeyPrefix is the prefix of the keys holding the child trie roots
+var childStorageKeyPrefix = []byte(":child_storage:default:")
+
+// EncodeCompact encodes the nodes and values of the partial database, such as the ones recorded
+// while reading a state, into the compact proof of the trie with the given root. The child tries
+// whose root is found in the partial database follow the main trie in the proof.
+//
+// The nodes are listed in pre-order, the references to the children part of the proof are
+// replaced with empty inline references and the hashed values part of the proof are attached
+// after their node, since they can all be recomputed while decoding the proof.
+func EncodeCompact[H hash.Hash, Hasher hash.Hasher[H]](partialDB db.DBGetter, root H) ([][]byte, error) {
+	encoder := &compactEncoder[H]{db: partialDB}
+	err := encoder.encodeTrie(root)
+	if err != nil {
+		return nil, fmt.Errorf("encoding main trie: %w", err)
+	}
+
+	childRoots, err := childTrieRoots(partialDB, root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, childRoot := range childRoots {
+		err = encoder.encodeTrie(childRoot)
+		if err != nil {
+			return nil, fmt.Errorf("encoding child trie %x: %w", childRoot.Bytes(), err)
+		}
+	}
+
+	return encoder.output, nil
+}
+
+// DecodeCompact decodes the compact proof encoded with [EncodeCompact], inserting the nodes
+// and values of the main trie and of the child tries in the database, and returns the main
+// trie root.
+func DecodeCompact[H hash.Hash, Hasher hash.Hasher[H]](database db.Database, encoded [][]byte) (root H, err error) {
+	decoder := &compactDecoder[H, Hasher]{db: database, encoded: encoded}
+	root, err = decoder.decodeNode()
+	if err != nil {
+		return root, fmt.Errorf("decoding main trie: %w", err)
+	}
+
+	childRoots, err := childTrieRoots(database, root)
+	if err != nil {
+		return root, err
+	}
+
+	// the child tries follow in the order of their root in the main trie, but
+	// child roots can be part of the proof without their child trie.
+	var decodedChildRoot *H
+	for _, childRoot := range childRoots {
+		if decodedChildRoot == nil && decoder.index < len(encoded) {
+			decoded, err := decoder.decodeNode()
+			if err != nil {
+				return root, fmt.Errorf("decoding child trie: %w", err)
+			}
+			decodedChildRoot = &decoded
+		}
+
+		if decodedChildRoot != nil && *decodedChildRoot == childRoot {
+			decodedChildRoot = nil
+		}
+	}
+
+	if decodedChildRoot != nil {
+		return root, fmt.Errorf("%w: %x", ErrExtraneousChildProof, (*decodedChildRoot).Bytes())
+	}
+	if decoder.index < len(encoded) {
+		return root, fmt.Errorf("%w: %d nodes left", ErrExtraneousChildNode, len(encoded)-decoder.index)
+	}
+
+	return root, nil
+}
+
+// getFromPartialDB returns the node or value with the given hash, or nil if it is missing
+func getFromPartialDB[H hash.Hash](partialDB db.DBGetter, hash H) ([]byte, error) {
+	data, err := partialDB.Get(hash.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("getting %x: %w", hash.Bytes(), err)
+	}
+	return data, nil
+}
+
+type compactEncoder[H hash.Hash] struct {
+	db     db.DBGetter
+	output [][]byte
+}
+
+// encodeTrie encodes the trie with the given root, which is skipped if it is not part of the
+// partial database, since the child tries only read by their root are not part of the proof.
+func (e *compactEncoder[H]) encodeTrie(root H) error {
+	data, err := getFromPartialDB(e.db, root)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	return e.encodeNode(data)
+}
+
+func (e *compactEncoder[H]) encodeNode(data []byte) error {
+	node, err := codec.Decode[H](bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decoding node: %w", err)
+	}
+
+	// the node is encoded once its children are, so its position is reserved
+	index := len(e.output)
+	e.output = append(e.output, nil)
+
+	value := node.GetValue()
+	omitValue := false
+	if hashedValue, ok := value.(codec.HashedValue[H]); ok {
+		valueData, err := getFromPartialDB(e.db, hashedValue.Hash)
+		if err != nil {
+			return err
+		}
+		if valueData != nil {
+			omitValue = true
+			value = codec.InlineValue{}
+			e.output = append(e.output, valueData)
+		}
+	}
+
+	encoded := bytes.NewBuffer(nil)
+	if omitValue {
+		encoded.WriteByte(escapeCompactHeader)
+	}
+
+	switch node := node.(type) {
+	case codec.Empty:
+		e.output[index] = data
+		return nil
+	case codec.Leaf:
+		err = triedb.NewEncodedLeaf(node.PartialKey.Right(), node.PartialKey.Len(), value, encoded)
+	case codec.Branch:
+		var children triedb.ChildReferences
+		for i, child := range node.Children {
+			switch child := child.(type) {
+			case codec.InlineNode:
+				children[i] = triedb.InlineChildReference(child)
+			case codec.HashedNode[H]:
+				childData, err := getFromPartialDB(e.db, child.Hash)
+				if err != nil {
+					return err
+				}
+				if childData == nil {
+					children[i] = triedb.HashChildReference[H](child)
+					continue
+				}
+
+				children[i] = triedb.InlineChildReference{}
+				err = e.encodeNode(childData)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		err = triedb.NewEncodedBranch(node.PartialKey.Right(), node.PartialKey.Len(), children, value, encoded)
+	}
+	if err != nil {
+		return fmt.Errorf("encoding node: %w", err)
+	}
+
+	e.output[index] = encoded.Bytes()
+	return nil
+}
+
+type compactDecoder[H hash.Hash, Hasher hash.Hasher[H]] struct {
+	db      db.DBPutter
+	encoded [][]byte
+	index   int
+}
+
+// decodeNode decodes the next node of the proof along with its omitted descendants and value,
+// inserts them in the database and returns the node hash.
+func (d *compactDecoder[H, Hasher]) decodeNode() (nodeHash H, err error) {
+	data, err := d.next()
+	if err != nil {
+		return nodeHash, err
+	}
+
+	attachedValue := len(data) > 0 && data[0] == escapeCompactHeader
+	if attachedValue {
+		data = data[1:]
+	}
+
+	node, err := codec.Decode[H](bytes.NewReader(data))
+	if err != nil {
+		return nodeHash, fmt.Errorf("decoding node: %w", err)
+	}
+
+	hasher := *new(Hasher)
+	value := node.GetValue()
+	if attachedValue {
+		valueData, err := d.next()
+		if err != nil {
+			return nodeHash, err
+		}
+
+		valueHash := hasher.Hash(valueData)
+		err = d.db.Put(valueHash.Bytes(), valueData)
+		if err != nil {
+			return nodeHash, fmt.Errorf("inserting value: %w", err)
+		}
+		value = codec.HashedValue[H]{Hash: valueHash}
+	}
+
+	encoded := bytes.NewBuffer(nil)
+	switch node := node.(type) {
+	case codec.Empty:
+		encoded.Write(data)
+	case codec.Leaf:
+		err = triedb.NewEncodedLeaf(node.PartialKey.Right(), node.PartialKey.Len(), value, encoded)
+	case codec.Branch:
+		var children triedb.ChildReferences
+		for i, child := range node.Children {
+			switch child := child.(type) {
+			case codec.InlineNode:
+				if len(child) > 0 {
+					children[i] = triedb.InlineChildReference(child)
+					continue
+				}
+
+				childHash, err := d.decodeNode()
+				if err != nil {
+					return nodeHash, err
+				}
+				children[i] = triedb.HashChildReference[H]{Hash: childHash}
+			case codec.HashedNode[H]:
+				children[i] = triedb.HashChildReference[H](child)
+			}
+		}
+		err = triedb.NewEncodedBranch(node.PartialKey.Right(), node.PartialKey.Len(), children, value, encoded)
+	}
+	if err != nil {
+		return nodeHash, fmt.Errorf("encoding node: %w", err)
+	}
+
+	nodeHash = hasher.Hash(encoded.Bytes())
+	err = d.db.Put(nodeHash.Bytes(), encoded.Bytes())
+	if err != nil {
+		return nodeHash, fmt.Errorf("inserting node: %w", err)
+	}
+	return nodeHash, nil
+}
+
+func (d *compactDecoder[H, Hasher]) next() ([]byte, error) {
+	if d.index >= len(d.encoded) {
+		return nil, ErrIncompleteCompactProof
+	}
+
+	data := d.encoded[d.index]
+	d.index++
+	return data, nil
+}
+
+// childTrieRoots returns the roots of the child tries found in the partial database of the
+// trie with the given root, in the order of their keys. Missing nodes and values are skipped.
+func childTrieRoots[H hash.Hash](partialDB db.DBGetter, root H) ([]H, error) {
+	data, err := getFromPartialDB(partialDB, root)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	prefix := make([]uint8, 0, 2*len(childStorageKeyPrefix))
+	for _, b := range childStorageKeyPrefix {
+		prefix = append(prefix, b>>4, b&0x0f)
+	}
+
+	walker := &childRootsWalker[H]{db: partialDB, prefix: prefix}
+	err = walker.walk(data, nil)
+	if err != nil {
+		return nil, err
+	}
+	return walker.roots, nil
+}
+
+// childRootsWalker walks the nodes of a partial database down the paths of the child storage
+// keys to collect the child trie roots.
+type childRootsWalker[H hash.Hash] struct {
+	db     db.DBGetter
+	prefix []uint8
+	roots  []H
+}
+
+// sharesPrefix returns true if the nibble path is on the way to the child storage keys
+func (w *childRootsWalker[H]) sharesPrefix(path []uint8) bool {
+	length := min(len(path), len(w.prefix))
+	return bytes.Equal(path[:length], w.prefix[:length])
+}
+
+func (w *childRootsWalker[H]) walk(data []byte, path []uint8) error {
+	node, err := codec.Decode[H](bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("decoding node: %w", err)
+	}
+
+	partialKey := node.GetPartialKey()
+	if partialKey == nil {
+		return nil
+	}
+	for i := uint(0); i < partialKey.Len(); i++ {
+		path = append(path, partialKey.At(i))
+	}
+	if !w.sharesPrefix(path) {
+		return nil
+	}
+
+	if len(path) >= len(w.prefix) && len(path)%2 == 0 {
+		err = w.collectRoot(node.GetValue())
+		if err != nil {
+			return err
+		}
+	}
+
+	branch, ok := node.(codec.Branch)
+	if !ok {
+		return nil
+	}
+
+	for i, child := range branch.Children {
+		childPath := append(bytes.Clone(path), uint8(i))
+		if child == nil || !w.sharesPrefix(childPath) {
+			continue
+		}
+
+		var childData []byte
+		switch child := child.(type) {
+		case codec.InlineNode:
+			childData = child
+		case codec.HashedNode[H]:
+			childData, err = getFromPartialDB(w.db, child.Hash)
+			if err != nil {
+				return err
+			}
+		}
+		if childData == nil {
+			continue
+		}
+
+		err = w.walk(childData, childPath)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *childRootsWalker[H]) collectRoot(value codec.EncodedValue) (err error) {
+	var rootData []byte
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case codec.InlineValue:
+		rootData = value
+	case codec.HashedValue[H]:
+		rootData, err = getFromPartialDB(w.db, value.Hash)
+		if err != nil || rootData == nil {
+			return err
+		}
+	}
+
+	var root H
+	if len(rootData) != root.Length() {
+		return fmt.Errorf("%w: 0x%x", ErrInvalidChildRoot, rootData)
+	}
+	err = scale.Unmarshal(rootData, &root)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidChildRoot, err)
+	}
+
+	w.roots = append(w.roots, root)
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package proof
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/triedb"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EncodeDecodeCompact(t *testing.T) {
+	t.Parallel()
+
+	trieDB := NewMemoryDB(triedb.EmptyNode)
+	stateTrie := triedb.NewEmptyStateTrie(trieDB, nil)
+	stateTrie.SetVersion(trie.V1)
+	for i := 0; i < 30; i++ {
+		// every other value is long enough to be hashed
+		value := bytes.Repeat([]byte{byte(i)}, 10+30*(i%2))
+		require.NoError(t, stateTrie.Put([]byte(fmt.Sprintf("key-%02d", i)), value))
+	}
+	for i := 0; i < 10; i++ {
+		key := []byte(fmt.Sprintf("child-key-%02d", i))
+		require.NoError(t, stateTrie.PutIntoChild([]byte("child"), key, bytes.Repeat([]byte{byte(i)}, 40)))
+		require.NoError(t, stateTrie.PutIntoChild([]byte("unread"), key, []byte{byte(i)}))
+	}
+	require.NoError(t, stateTrie.WriteDirty(trieDB))
+	root := stateTrie.MustHash()
+
+	recorder := triedb.NewRecorder[hash.H256]()
+	recordingTrie := triedb.NewRecordingStateTrie(root, trieDB, nil, recorder)
+	recordingTrie.SetVersion(trie.V1)
+	expected := map[string][]byte{
+		"key-02":      recordingTrie.Get([]byte("key-02")),
+		"key-07":      recordingTrie.Get([]byte("key-07")),
+		"key-missing": recordingTrie.Get([]byte("key-missing")),
+	}
+	require.NotNil(t, expected["key-07"])
+	childValue, err := recordingTrie.GetFromChild([]byte("child"), []byte("child-key-03"))
+	require.NoError(t, err)
+	// the unread child trie root is part of the proof without its child trie
+	require.NotNil(t, recordingTrie.Get([]byte(":child_storage:default:unread")))
+
+	var recorded [][]byte
+	recordedSize := 0
+	for _, record := range recorder.Drain() {
+		recorded = append(recorded, record.Data)
+		recordedSize += len(record.Data)
+	}
+	partialDB, err := db.NewMemoryDBFromProof(recorded)
+	require.NoError(t, err)
+
+	encoded, err := EncodeCompact[hash.H256, runtime.BlakeTwo256](partialDB, hash.H256(root[:]))
+	require.NoError(t, err)
+	encodedSize := 0
+	for _, node := range encoded {
+		encodedSize += len(node)
+	}
+	require.Less(t, encodedSize, recordedSize)
+
+	decodedDB := db.NewEmptyMemoryDB()
+	decodedRoot, err := DecodeCompact[hash.H256, runtime.BlakeTwo256](decodedDB, encoded)
+	require.NoError(t, err)
+	require.Equal(t, hash.H256(root[:]), decodedRoot)
+
+	proofTrie := triedb.NewStateTrie(root, decodedDB, nil)
+	for key, value := range expected {
+		proven, err := triedb.GetWith(proofTrie.TrieDB, []byte(key), func(data []byte) []byte { return data })
+		require.NoError(t, err)
+		if value == nil {
+			require.Nil(t, proven)
+			continue
+		}
+		require.Equal(t, value, *proven)
+	}
+	provenChildValue, err := proofTrie.GetFromChild([]byte("child"), []byte("child-key-03"))
+	require.NoError(t, err)
+	require.Equal(t, childValue, provenChildValue)
+
+	t.Run("incomplete_proof", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := DecodeCompact[hash.H256, runtime.BlakeTwo256](db.NewEmptyMemoryDB(), encoded[:1])
+		require.ErrorIs(t, err, ErrIncompleteCompactProof)
+	})
+
+	t.Run("extraneous_node", func(t *testing.T) {
+		t.Parallel()
+
+		// leaf node with key 'a' and value 'a'
+		extraneous := append(append([][]byte{}, encoded...), []byte{66, 97, 4, 97})
+		_, err := DecodeCompact[hash.H256, runtime.BlakeTwo256](db.NewEmptyMemoryDB(), extraneous)
+		require.ErrorIs(t, err, ErrExtraneousChildProof)
+	})
+
+	t.Run("missing_root", func(t *testing.T) {
+		t.Parallel()
+
+		encoded, err := EncodeCompact[hash.H256, runtime.BlakeTwo256](partialDB, hash.H256(common.Hash{1}.ToBytes()))
+		require.NoError(t, err)
+		require.Empty(t, encoded)
+	})
+}
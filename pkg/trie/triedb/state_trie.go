@@ -0,0 +1,449 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package triedb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/tracking"
+)
+
+// childStorageKeyPrefix is the prefix of the keys holding the child trie roots
+var childStorageKeyPrefix = []byte(":child_storage:default:")
+
+// StateTrie is a [trie.Trie] backed by a [TrieDB] which lazily loads its nodes
+// from the given database. Nodes and values are read from and written to the
+// database using their hash as key.
+// Changes are kept in memory until they are written with [StateTrie.WriteDirty].
+// A StateTrie is not safe for concurrent use, even for reads, since reading commits
+// pending changes and caches the child tries loaded.
+type StateTrie struct {
+	*TrieDB[chash.H256, runtime.BlakeTwo256]
+
+	db    *overlayDB
+	cache TrieCache[chash.H256]
+	// dirty is true if the trie has changes which are not committed
+	// yet, in which case values cannot be looked up with the cache.
+	dirty      bool
+	childTries map[common.Hash]*StateTrie
+	recorder   TrieRecorder
+}
+
+// NewStateTrie creates a new [StateTrie] at the given root. The optional
+// cache is used to speed up the database lookups.
+func NewStateTrie(root common.Hash, db db.DBGetter, cache TrieCache[chash.H256]) *StateTrie {
+	return newStateTrie(root, newOverlayDB(db), cache, nil)
+}
+
+// NewRecordingStateTrie creates a new [StateTrie] at the given root which records
+// the nodes and values it accesses, including the ones of its child tries, with
+// the given recorder. The recorded nodes prove the accessed keys, whether they
+// exist or not.
+func NewRecordingStateTrie(root common.Hash, db db.DBGetter, cache TrieCache[chash.H256],
+	recorder TrieRecorder) *StateTrie {
+	return newStateTrie(root, newOverlayDB(db), cache, recorder)
+}
+
+// NewEmptyStateTrie creates a new empty [StateTrie].
+func NewEmptyStateTrie(db db.DBGetter, cache TrieCache[chash.H256]) *StateTrie {
+	return NewStateTrie(trie.EmptyHash, db, cache)
+}
+
+func newStateTrie(root common.Hash, overlay *overlayDB, cache TrieCache[chash.H256],
+	recorder TrieRecorder) *StateTrie {
+	var opts []TrieDBOpts[chash.H256, runtime.BlakeTwo256]
+	if cache != nil {
+		opts = append(opts, WithCache[chash.H256, runtime.BlakeTwo256](cache))
+	}
+	if recorder != nil {
+		opts = append(opts, WithRecorder[chash.H256, runtime.BlakeTwo256](recorder))
+	}
+
+	return &StateTrie{
+		TrieDB:     NewTrieDB(chash.H256(root[:]), overlay, opts...),
+		db:         overlay,
+		cache:      cache,
+		childTries: make(map[common.Hash]*StateTrie),
+		recorder:   recorder,
+	}
+}
+
+// Hash commits the trie changes and returns the trie root hash.
+func (t *StateTrie) Hash() (common.Hash, error) {
+	root, err := t.TrieDB.Hash()
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	t.dirty = false
+	return common.NewHash(root.Bytes()), nil
+}
+
+// MustHash returns the trie root hash and panics on error.
+func (t *StateTrie) MustHash() common.Hash {
+	root, err := t.Hash()
+	if err != nil {
+		panic(err)
+	}
+
+	return root
+}
+
+// Get returns the value at the given key or nil if it does not exist.
+func (t *StateTrie) Get(key []byte) []byte {
+	if t.dirty {
+		return t.TrieDB.Get(key)
+	}
+
+	value, err := GetWith(t.TrieDB, key, func(data []byte) []byte { return data })
+	if err != nil {
+		logger.Errorf("getting value at key 0x%x: %s", key, err)
+		return nil
+	}
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// Put inserts the given key value pair in the trie.
+func (t *StateTrie) Put(key, value []byte) error {
+	t.dirty = true
+	return t.TrieDB.Put(key, value)
+}
+
+// Delete removes the given key from the trie.
+func (t *StateTrie) Delete(key []byte) error {
+	t.dirty = true
+	return t.TrieDB.Delete(key)
+}
+
+// ClearPrefix deletes all the keys with the given prefix from the trie.
+func (t *StateTrie) ClearPrefix(prefix []byte) error {
+	t.dirty = true
+	return t.TrieDB.ClearPrefix(prefix)
+}
+
+// ClearPrefixLimit deletes at most limit keys with the given prefix from the trie.
+func (t *StateTrie) ClearPrefixLimit(prefix []byte, limit uint32) (
+	deleted uint32, allDeleted bool, err error) {
+	t.dirty = true
+	return t.TrieDB.ClearPrefixLimit(prefix, limit)
+}
+
+func childStorageKey(keyToChild []byte) []byte {
+	key := make([]byte, len(childStorageKeyPrefix)+len(keyToChild))
+	copy(key, childStorageKeyPrefix)
+	copy(key[len(childStorageKeyPrefix):], keyToChild)
+	return key
+}
+
+func (t *StateTrie) getChild(keyToChild []byte) (*StateTrie, error) {
+	childRoot := t.Get(childStorageKey(keyToChild))
+	if childRoot == nil {
+		return nil, fmt.Errorf("%w at key 0x%x%x", trie.ErrChildTrieDoesNotExist, childStorageKeyPrefix, keyToChild)
+	}
+
+	root := common.BytesToHash(childRoot)
+	child, ok := t.childTries[root]
+	if !ok {
+		child = newStateTrie(root, t.db, t.cache, t.recorder)
+		t.childTries[root] = child
+	}
+	child.SetVersion(t.version)
+	return child, nil
+}
+
+func (t *StateTrie) setChild(keyToChild []byte, previousRoot common.Hash, child *StateTrie) error {
+	root, err := child.Hash()
+	if err != nil {
+		return fmt.Errorf("hashing child trie located at key 0x%x: %w", keyToChild, err)
+	}
+
+	delete(t.childTries, previousRoot)
+	if root == trie.EmptyHash {
+		return t.DeleteChild(keyToChild)
+	}
+
+	t.childTries[root] = child
+	err = t.Put(childStorageKey(keyToChild), root.ToBytes())
+	if err != nil {
+		return fmt.Errorf("putting child trie root hash %s in trie: %w", root, err)
+	}
+	return nil
+}
+
+// GetChild returns the child trie at key :child_storage:default:[keyToChild]
+func (t *StateTrie) GetChild(keyToChild []byte) (trie.Trie, error) {
+	child, err := t.getChild(keyToChild)
+	if err != nil {
+		return nil, err
+	}
+	return child, nil
+}
+
+// GetFromChild retrieves the value at the given key from the child trie
+// located at key :child_storage:default:[keyToChild]
+func (t *StateTrie) GetFromChild(keyToChild, key []byte) ([]byte, error) {
+	child, err := t.getChild(keyToChild)
+	if err != nil {
+		return nil, err
+	}
+	return child.Get(key), nil
+}
+
+// GetChildTries returns the child tries loaded by this trie
+func (t *StateTrie) GetChildTries() map[common.Hash]trie.Trie {
+	children := make(map[common.Hash]trie.Trie, len(t.childTries))
+	for root, child := range t.childTries {
+		children[root] = child
+	}
+	return children
+}
+
+// PutIntoChild puts a key-value pair into the child trie located at key
+// :child_storage:default:[keyToChild], creating the child trie if needed.
+func (t *StateTrie) PutIntoChild(keyToChild, key, value []byte) error {
+	child, err := t.getChild(keyToChild)
+	if err != nil {
+		if !errors.Is(err, trie.ErrChildTrieDoesNotExist) {
+			return fmt.Errorf("getting child: %w", err)
+		}
+		child = newStateTrie(trie.EmptyHash, t.db, t.cache, t.recorder)
+		child.SetVersion(t.version)
+	}
+
+	previousRoot, err := child.Hash()
+	if err != nil {
+		return fmt.Errorf("hashing child trie located at key 0x%x: %w", keyToChild, err)
+	}
+
+	err = child.Put(key, value)
+	if err != nil {
+		return fmt.Errorf("putting into child trie located at key 0x%x: %w", keyToChild, err)
+	}
+
+	return t.setChild(keyToChild, previousRoot, child)
+}
+
+// DeleteChild deletes the child trie located at key :child_storage:default:[keyToChild]
+func (t *StateTrie) DeleteChild(keyToChild []byte) error {
+	err := t.Delete(childStorageKey(keyToChild))
+	if err != nil {
+		return fmt.Errorf("deleting child trie located at key 0x%x: %w", keyToChild, err)
+	}
+	return nil
+}
+
+// ClearFromChild removes the given key from the child trie located at
+// key :child_storage:default:[keyToChild]
+func (t *StateTrie) ClearFromChild(keyToChild, key []byte) error {
+	child, err := t.getChild(keyToChild)
+	if err != nil {
+		return err
+	}
+
+	previousRoot, err := child.Hash()
+	if err != nil {
+		return fmt.Errorf("hashing child trie located at key 0x%x: %w", keyToChild, err)
+	}
+
+	err = child.Delete(key)
+	if err != nil {
+		return fmt.Errorf("deleting from child trie located at key 0x%x: %w", keyToChild, err)
+	}
+
+	return t.setChild(keyToChild, previousRoot, child)
+}
+
+// GetChangedNodeHashes returns the hashes of the nodes and values inserted
+// and deleted in the trie and its child tries since the trie was created or
+// last written, with the number of positions each of them was inserted at or
+// deleted from. Inserted nodes which are deleted again before being written
+// are not returned.
+func (t *StateTrie) GetChangedNodeHashes() (inserted, deleted map[common.Hash]uint32, err error) {
+	err = t.commitAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inserted, deleted = t.db.changes()
+	return inserted, deleted, nil
+}
+
+// HandleTrackedDeltas does nothing since the deleted nodes are tracked
+// when the trie changes are committed.
+func (*StateTrie) HandleTrackedDeltas(bool, tracking.Getter) {}
+
+// WriteDirty commits the trie changes and writes the inserted nodes and
+// values, including the ones of the child tries, to the given database.
+func (t *StateTrie) WriteDirty(db db.NewBatcher) error {
+	err := t.commitAll()
+	if err != nil {
+		return err
+	}
+
+	return t.db.write(db)
+}
+
+func (t *StateTrie) commitAll() error {
+	_, err := t.Hash()
+	if err != nil {
+		return fmt.Errorf("committing trie changes: %w", err)
+	}
+	for _, child := range t.childTries {
+		_, err = child.Hash()
+		if err != nil {
+			return fmt.Errorf("committing child trie changes: %w", err)
+		}
+	}
+	return nil
+}
+
+var _ trie.Trie = (*StateTrie)(nil)
+var _ TrieCache[chash.H256] = (*NodeCache[chash.H256])(nil)
+
+// overlayDB holds the nodes and values written and deleted by the tries
+// on top of a read only database. Keys are normalised to the node or value
+// hash, dropping the nibble prefix used by [TrieDB]. Since identical subtries
+// at different positions share the same hash, the nodes are reference counted:
+// each insertion at a position adds a reference and each deletion from a position
+// removes one, such that the net count of each node is known.
+type overlayDB struct {
+	base     db.DBGetter
+	inserted map[common.Hash][]byte
+	// refs is the net count of insertions minus deletions of each node
+	refs map[common.Hash]int
+}
+
+func newOverlayDB(base db.DBGetter) *overlayDB {
+	return &overlayDB{
+		base:     base,
+		inserted: make(map[common.Hash][]byte),
+		refs:     make(map[common.Hash]int),
+	}
+}
+
+func hashFromKey(key []byte) common.Hash {
+	if len(key) < common.HashLength {
+		return common.BytesToHash(key)
+	}
+	return common.BytesToHash(key[len(key)-common.HashLength:])
+}
+
+func (o *overlayDB) Get(key []byte) ([]byte, error) {
+	hash := hashFromKey(key)
+	if hash == trie.EmptyHash {
+		return EmptyNode, nil
+	}
+
+	value, ok := o.inserted[hash]
+	if ok {
+		return value, nil
+	}
+
+	value, err := o.base.Get(hash[:])
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	return value, err
+}
+
+func (o *overlayDB) Put(key, value []byte) error {
+	hash := hashFromKey(key)
+	if hash == trie.EmptyHash {
+		return nil
+	}
+
+	o.inserted[hash] = value
+	o.addRefs(hash, 1)
+	return nil
+}
+
+// Del removes a reference to the given node, which is forgotten once deleted from
+// all the positions it was inserted at in the overlay. Note the base database is
+// never modified.
+func (o *overlayDB) Del(key []byte) error {
+	hash := hashFromKey(key)
+	if hash == trie.EmptyHash {
+		return nil
+	}
+
+	o.addRefs(hash, -1)
+	return nil
+}
+
+func (o *overlayDB) addRefs(hash common.Hash, delta int) {
+	refs := o.refs[hash] + delta
+	if refs != 0 {
+		o.refs[hash] = refs
+		return
+	}
+
+	// the node is either back in the base database or not referenced anymore
+	delete(o.refs, hash)
+	delete(o.inserted, hash)
+}
+
+func (*overlayDB) Flush() error { return nil }
+
+func (o *overlayDB) NewBatch() database.Batch {
+	return &overlayBatch{o}
+}
+
+// changes returns the number of references inserted and deleted for each node.
+func (o *overlayDB) changes() (inserted, deleted map[common.Hash]uint32) {
+	inserted = make(map[common.Hash]uint32)
+	deleted = make(map[common.Hash]uint32)
+	for hash, refs := range o.refs {
+		if refs > 0 {
+			inserted[hash] = uint32(refs)
+		} else {
+			deleted[hash] = uint32(-refs)
+		}
+	}
+	return inserted, deleted
+}
+
+func (o *overlayDB) write(db db.NewBatcher) error {
+	batch := db.NewBatch()
+	for hash, value := range o.inserted {
+		if o.refs[hash] <= 0 {
+			// the node is still referenced by the base database
+			continue
+		}
+
+		err := batch.Put(hash[:], value)
+		if err != nil {
+			batch.Reset()
+			return fmt.Errorf("writing node %s: %w", hash, err)
+		}
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("flushing nodes: %w", err)
+	}
+
+	// the nodes can now be read from the base database
+	o.inserted = make(map[common.Hash][]byte)
+	o.refs = make(map[common.Hash]int)
+	return nil
+}
+
+// overlayBatch applies the changes directly to the overlay
+type overlayBatch struct {
+	*overlayDB
+}
+
+func (*overlayBatch) Close() error   { return nil }
+func (*overlayBatch) Reset()         {}
+func (*overlayBatch) ValueSize() int { return 0 }
@@ -0,0 +1,303 @@
+// Copyright 2024 ChainSafe Systems (ON)
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package triedb
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ChainSafe/gossamer/internal/database"
+	chash "github.com/ChainSafe/gossamer/internal/primitives/core/hash"
+	"github.com/ChainSafe/gossamer/lib/common"
+	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/db"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
+	"github.com/stretchr/testify/require"
+)
+
+func requireSameTries(t *testing.T, expected, actual trie.Trie) {
+	t.Helper()
+
+	require.Equal(t, expected.MustHash(), actual.MustHash())
+	require.Equal(t, expected.Entries(), actual.Entries())
+
+	for key, value := range expected.Entries() {
+		require.Equal(t, value, actual.Get([]byte(key)))
+		require.Equal(t, expected.NextKey([]byte(key)), actual.NextKey([]byte(key)))
+	}
+	require.Equal(t, expected.NextKey(nil), actual.NextKey(nil))
+	require.Equal(t, expected.GetKeysWithPrefix([]byte("key-1")), actual.GetKeysWithPrefix([]byte("key-1")))
+}
+
+func TestStateTrie(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []trie.TrieLayout{trie.V0, trie.V1} {
+		version := version
+		t.Run(version.String(), func(t *testing.T) {
+			t.Parallel()
+
+			rng := rand.New(rand.NewSource(int64(version))) //nolint:gosec
+			value := func() []byte {
+				value := make([]byte, rng.Intn(64))
+				rng.Read(value)
+				return value
+			}
+
+			db := newTestDB(t)
+			cache := NewNodeCache[chash.H256](DefaultNodeCacheCapacity)
+			expected := inmemory.NewEmptyTrie()
+			expected.SetVersion(version)
+			actual := NewEmptyStateTrie(db, cache)
+			actual.SetVersion(version)
+
+			for i := 0; i < 500; i++ {
+				// keys have the same length since the in memory trie wrongly
+				// deletes the keys having the deleted key as prefix.
+				key := []byte(fmt.Sprintf("key-%03d", rng.Intn(200)))
+				keyToChild := []byte(fmt.Sprintf("child-%d", rng.Intn(3)))
+				switch rng.Intn(6) {
+				case 0, 1:
+					value := value()
+					require.NoError(t, expected.Put(key, value))
+					require.NoError(t, actual.Put(key, value))
+				case 2:
+					require.NoError(t, expected.Delete(key))
+					require.NoError(t, actual.Delete(key))
+				case 3:
+					value := value()
+					require.NoError(t, expected.PutIntoChild(keyToChild, key, value))
+					require.NoError(t, actual.PutIntoChild(keyToChild, key, value))
+				case 4:
+					expectedErr := expected.ClearFromChild(keyToChild, key)
+					actualErr := actual.ClearFromChild(keyToChild, key)
+					require.Equal(t, expectedErr == nil, actualErr == nil)
+				case 5:
+					expectedValue, expectedErr := expected.GetFromChild(keyToChild, key)
+					actualValue, actualErr := actual.GetFromChild(keyToChild, key)
+					require.Equal(t, expectedValue, actualValue)
+					require.Equal(t, expectedErr == nil, actualErr == nil)
+				}
+
+				if i%50 == 0 {
+					requireSameTries(t, expected, actual)
+				}
+			}
+			requireSameTries(t, expected, actual)
+
+			deleted, allDeleted, err := expected.ClearPrefixLimit([]byte("key-1"), 5)
+			require.NoError(t, err)
+			actualDeleted, actualAllDeleted, err := actual.ClearPrefixLimit([]byte("key-1"), 5)
+			require.NoError(t, err)
+			require.Equal(t, deleted, actualDeleted)
+			require.Equal(t, allDeleted, actualAllDeleted)
+			requireSameTries(t, expected, actual)
+
+			require.NoError(t, expected.ClearPrefix([]byte("key-1")))
+			require.NoError(t, actual.ClearPrefix([]byte("key-1")))
+			requireSameTries(t, expected, actual)
+
+			// the trie nodes are only written to the database with WriteDirty
+			root := actual.MustHash()
+			_, err = db.Get(root[:])
+			require.ErrorIs(t, err, database.ErrNotFound)
+
+			require.NoError(t, actual.WriteDirty(db))
+			loaded := NewStateTrie(root, db, cache)
+			loaded.SetVersion(version)
+			requireSameTries(t, expected, loaded)
+
+			for keyToChild := range []int{0, 1, 2} {
+				keyToChild := []byte(fmt.Sprintf("child-%d", keyToChild))
+				expectedChild, expectedErr := expected.GetChild(keyToChild)
+				actualChild, actualErr := loaded.GetChild(keyToChild)
+				require.Equal(t, expectedErr == nil, actualErr == nil)
+				if expectedErr == nil {
+					requireSameTries(t, expectedChild, actualChild)
+				}
+			}
+		})
+	}
+}
+
+func TestStateTrie_ReadsInMemoryTrie(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	inMemoryTrie := inmemory.NewEmptyTrie()
+	inMemoryTrie.SetVersion(trie.V1)
+	require.NoError(t, inMemoryTrie.Put([]byte("short"), []byte("value")))
+	require.NoError(t, inMemoryTrie.Put([]byte("long"), make([]byte, 100)))
+	require.NoError(t, inMemoryTrie.PutIntoChild([]byte("child"), []byte("key"), []byte("child value")))
+	require.NoError(t, inMemoryTrie.WriteDirty(db))
+
+	stateTrie := NewStateTrie(inMemoryTrie.MustHash(), db, nil)
+	requireSameTries(t, inMemoryTrie, stateTrie)
+
+	value, err := stateTrie.GetFromChild([]byte("child"), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("child value"), value)
+}
+
+func TestStateTrie_GetChangedNodeHashes(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	stateTrie := NewEmptyStateTrie(db, nil)
+	require.NoError(t, stateTrie.Put([]byte("alpha"), make([]byte, 40)))
+	require.NoError(t, stateTrie.Put([]byte("bravo"), make([]byte, 40)))
+	require.NoError(t, stateTrie.WriteDirty(db))
+	root := stateTrie.MustHash()
+
+	inserted, deleted, err := stateTrie.GetChangedNodeHashes()
+	require.NoError(t, err)
+	require.Empty(t, inserted)
+	require.Empty(t, deleted)
+
+	next := NewStateTrie(root, db, nil)
+	require.NoError(t, next.Put([]byte("alpha"), make([]byte, 41)))
+	inserted, deleted, err = next.GetChangedNodeHashes()
+	require.NoError(t, err)
+
+	newRoot := next.MustHash()
+	require.Contains(t, inserted, newRoot)
+	require.Contains(t, deleted, root)
+	for hash := range inserted {
+		_, err := db.Get(hash[:])
+		require.ErrorIs(t, err, database.ErrNotFound)
+	}
+	for hash := range deleted {
+		_, err := db.Get(hash[:])
+		require.NoError(t, err)
+	}
+}
+
+func TestStateTrie_GetChangedNodeHashes_sharedValue(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	value := bytes.Repeat([]byte{1}, 40)
+	valueHash := common.MustBlake2bHash(value)
+
+	stateTrie := NewEmptyStateTrie(db, nil)
+	stateTrie.SetVersion(trie.V1)
+	require.NoError(t, stateTrie.Put([]byte("alpha"), value))
+	require.NoError(t, stateTrie.Put([]byte("bravo"), value))
+
+	// the value is stored once but referenced by both keys
+	inserted, _, err := stateTrie.GetChangedNodeHashes()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), inserted[valueHash])
+	require.NoError(t, stateTrie.WriteDirty(db))
+
+	next := NewStateTrie(stateTrie.MustHash(), db, nil)
+	next.SetVersion(trie.V1)
+	require.NoError(t, next.Delete([]byte("alpha")))
+
+	inserted, deleted, err := next.GetChangedNodeHashes()
+	require.NoError(t, err)
+	require.NotContains(t, inserted, valueHash)
+	require.Equal(t, uint32(1), deleted[valueHash])
+	require.Equal(t, value, next.Get([]byte("bravo")))
+}
+
+func TestNewRecordingStateTrie(t *testing.T) {
+	t.Parallel()
+
+	testDB := newTestDB(t)
+	stateTrie := NewEmptyStateTrie(testDB, nil)
+	stateTrie.SetVersion(trie.V1)
+	for i := 0; i < 20; i++ {
+		require.NoError(t, stateTrie.Put([]byte(fmt.Sprintf("key-%02d", i)), bytes.Repeat([]byte{byte(i)}, 40)))
+	}
+	require.NoError(t, stateTrie.PutIntoChild([]byte("child"), []byte("key"), []byte("child value")))
+	require.NoError(t, stateTrie.WriteDirty(testDB))
+	root := stateTrie.MustHash()
+
+	recorder := NewRecorder[chash.H256]()
+	recordingTrie := NewRecordingStateTrie(root, testDB, nil, recorder)
+	require.Equal(t, bytes.Repeat([]byte{5}, 40), recordingTrie.Get([]byte("key-05")))
+	require.Nil(t, recordingTrie.Get([]byte("key-missing")))
+	value, err := recordingTrie.GetFromChild([]byte("child"), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("child value"), value)
+
+	var proofNodes [][]byte
+	for _, record := range recorder.Drain() {
+		proofNodes = append(proofNodes, record.Data)
+	}
+	proofDB, err := db.NewMemoryDBFromProof(proofNodes)
+	require.NoError(t, err)
+
+	// the recorded nodes prove the existing and the missing keys
+	proofTrie := NewStateTrie(root, proofDB, nil)
+	for key, expected := range map[string][]byte{
+		"key-05":      bytes.Repeat([]byte{5}, 40),
+		"key-missing": nil,
+	} {
+		value, err := GetWith(proofTrie.TrieDB, []byte(key), func(data []byte) []byte { return data })
+		require.NoError(t, err)
+		if expected == nil {
+			require.Nil(t, value)
+			continue
+		}
+		require.Equal(t, expected, *value)
+	}
+	value, err = proofTrie.GetFromChild([]byte("child"), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("child value"), value)
+
+	_, err = GetWith(proofTrie.TrieDB, []byte("key-10"), func(data []byte) []byte { return data })
+	require.ErrorIs(t, err, ErrIncompleteDB)
+}
+
+func TestStateTrie_EntriesFrom(t *testing.T) {
+	t.Parallel()
+
+	testDB := newTestDB(t)
+	stateTrie := NewEmptyStateTrie(testDB, nil)
+	stateTrie.SetVersion(trie.V1)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, stateTrie.Put([]byte(fmt.Sprintf("key-%02d", i)), bytes.Repeat([]byte{byte(i)}, 40)))
+	}
+	require.NoError(t, stateTrie.WriteDirty(testDB))
+	root := stateTrie.MustHash()
+
+	var keys []string
+	for item, err := range stateTrie.EntriesFrom([]byte("key-06")) {
+		require.NoError(t, err)
+		require.Equal(t, stateTrie.Get(item.Key), item.Value)
+		keys = append(keys, string(item.Key))
+	}
+	require.Equal(t, []string{"key-07", "key-08", "key-09"}, keys)
+
+	// iterating over a partial database stops at the first missing entry
+	recorder := NewRecorder[chash.H256]()
+	recordingTrie := NewRecordingStateTrie(root, testDB, nil, recorder)
+	for key := range recordingTrie.KeysFrom([]byte("key-01")) {
+		require.NotNil(t, recordingTrie.Get(key))
+		if string(key) == "key-03" {
+			break
+		}
+	}
+	var proofNodes [][]byte
+	for _, record := range recorder.Drain() {
+		proofNodes = append(proofNodes, record.Data)
+	}
+	proofDB, err := db.NewMemoryDBFromProof(proofNodes)
+	require.NoError(t, err)
+
+	keys = nil
+	proofTrie := NewStateTrie(root, proofDB, nil)
+	for item, err := range proofTrie.EntriesFrom([]byte("key-01")) {
+		if err != nil {
+			require.ErrorIs(t, err, ErrIncompleteDB)
+			break
+		}
+		keys = append(keys, string(item.Key))
+	}
+	require.Equal(t, []string{"key-02", "key-03"}, keys)
+}
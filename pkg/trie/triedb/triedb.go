@@ -132,6 +132,70 @@ func (t *TrieDB[H, Hasher]) Get(key []byte) []byte {
 	return val
 }
 
+// ClosestDescendantMerkleValue returns the Merkle value of the closest node whose key
+// starts with the given key, or nil if there is no such node.
+// Note pending changes are committed to the database first.
+func (t *TrieDB[H, Hasher]) ClosestDescendantMerkleValue(key []byte) ([]byte, error) {
+	err := t.commit()
+	if err != nil {
+		return nil, fmt.Errorf("committing trie changes: %w", err)
+	}
+
+	fullKey := nibbles.NewNibbles(key)
+	partial := fullKey
+	var handle codec.MerkleValue = codec.HashedNode[H]{Hash: t.rootHash}
+	prefix := nibbles.Prefix{}
+	var keyNibbles uint
+	for {
+		node, _, err := t.getNodeOrLookup(handle, prefix, true)
+		if err != nil {
+			return nil, err
+		}
+
+		var partialKey nibbles.Nibbles
+		switch node := node.(type) {
+		case codec.Empty:
+			return nil, nil
+		case codec.Leaf:
+			partialKey = node.PartialKey
+		case codec.Branch:
+			partialKey = node.PartialKey
+		}
+
+		if partialKey.StartsWith(partial) {
+			return merkleValueBytes[H](handle), nil
+		}
+
+		branch, ok := node.(codec.Branch)
+		if !ok || !partial.StartsWith(partialKey) {
+			return nil, nil
+		}
+
+		keyNibbles += partialKey.Len()
+		partial = partial.Mid(partialKey.Len())
+		child := branch.Children[partial.At(0)]
+		if child == nil {
+			return nil, nil
+		}
+
+		keyNibbles++
+		partial = partial.Mid(1)
+		prefix = fullKey.Back(keyNibbles).Left()
+		handle = child
+	}
+}
+
+// merkleValueBytes returns the hash of a hashed node or the encoding of an inline node.
+func merkleValueBytes[H hash.Hash](handle codec.MerkleValue) []byte {
+	switch handle := handle.(type) {
+	case codec.InlineNode:
+		return []byte(handle)
+	case codec.HashedNode[H]:
+		return handle.Hash.Bytes()
+	}
+	return nil
+}
+
 func (t *TrieDB[H, Hasher]) lookup(fullKey []byte, handle NodeHandle) ([]byte, error) {
 	prefix := fullKey
 	partialKey := nibbles.NewNibbles(fullKey)
@@ -148,7 +212,7 @@ func(data []byte) []byte {
 					return data
 				},
 			)
-			qi, err := lookup.Lookup(fullKey)
+			qi, err := lookup.lookup(fullKey, partialKey)
 			if err != nil {
 				return nil, err
 			}
@@ -219,8 +283,9 @@ func (t *TrieDB[H, Hasher]) getNodeOrLookup(
 		return nil, nil, err
 	}
 
-	if recordAccess {
-		t.recordAccess(EncodedNodeAccess[H]{Hash: t.rootHash, EncodedNode: nodeData})
+	// inline nodes are part of their parent node so only hashed nodes are recorded
+	if recordAccess && nodeHash != nil {
+		t.recordAccess(EncodedNodeAccess[H]{Hash: *nodeHash, EncodedNode: nodeData})
 	}
 	return decoded, nodeHash, nil
 }
@@ -234,7 +299,7 @@ func (t *TrieDB[H, Hasher]) fetchValue(hash H, prefix nibbles.Prefix) ([]byte, e
 	if value == nil {
 		return nil, fmt.Errorf("%w: %v", ErrIncompleteDB, hash)
 	}
-	t.recordAccess(ValueAccess[H]{Hash: t.rootHash, Value: value, FullKey: prefix.Key})
+	t.recordAccess(ValueAccess[H]{Hash: hash, Value: value, FullKey: prefix.Key})
 	return value, nil
 }
 
@@ -263,6 +328,46 @@ func (t *TrieDB[H, Hasher]) Delete(key []byte) error {
 	return t.remove(nibbles.NewNibbles(key))
 }
 
+// ClearPrefix deletes all the keys with the given prefix from the trie
+func (t *TrieDB[H, Hasher]) ClearPrefix(prefix []byte) error {
+	_, _, err := t.clearPrefix(prefix, nil)
+	return err
+}
+
+// ClearPrefixLimit deletes at most limit keys with the given prefix from the
+// trie. It returns the number of deleted keys and whether all the keys
+// with the prefix were deleted.
+func (t *TrieDB[H, Hasher]) ClearPrefixLimit(prefix []byte, limit uint32) (
+	deleted uint32, allDeleted bool, err error) {
+	return t.clearPrefix(prefix, &limit)
+}
+
+func (t *TrieDB[H, Hasher]) clearPrefix(prefix []byte, limit *uint32) (
+	deleted uint32, allDeleted bool, err error) {
+	var keys [][]byte
+	allDeleted = true
+	for key, err := range t.keys(prefix, nil) {
+		if err != nil {
+			return 0, false, fmt.Errorf("iterating over keys with prefix 0x%x: %w", prefix, err)
+		}
+		if limit != nil && uint32(len(keys)) == *limit {
+			allDeleted = false
+			break
+		}
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		err = t.Delete(key)
+		if err != nil {
+			return deleted, false, fmt.Errorf("deleting key 0x%x: %w", key, err)
+		}
+		deleted++
+	}
+
+	return deleted, allDeleted, nil
+}
+
 // insert inserts the node and update the rootHandle
 func (t *TrieDB[H, Hasher]) insert(keyNibbles nibbles.Nibbles, value []byte) error {
 	var oldValue nodeValue
@@ -828,7 +933,7 @@ func (t *TrieDB[H, Hasher]) lookupNode(hash H, key nibbles.Prefix) (storageHandl
 			return nil, ErrIncompleteDB
 		}
 
-		t.recordAccess(EncodedNodeAccess[H]{Hash: t.rootHash, EncodedNode: encodedNode})
+		t.recordAccess(EncodedNodeAccess[H]{Hash: hash, EncodedNode: encodedNode})
 
 		return newNodeFromEncoded[H](hash, encodedNode, &t.storage)
 	}
@@ -11,6 +11,7 @@
 	"github.com/ChainSafe/gossamer/internal/primitives/core/hash"
 	"github.com/ChainSafe/gossamer/internal/primitives/runtime"
 	"github.com/ChainSafe/gossamer/pkg/trie"
+	"github.com/ChainSafe/gossamer/pkg/trie/inmemory"
 	"github.com/ChainSafe/gossamer/pkg/trie/triedb/codec"
 	"github.com/ChainSafe/gossamer/pkg/trie/triedb/nibbles"
 	"github.com/stretchr/testify/assert"
@@ -1531,3 +1532,53 @@ func Test_TrieDB(t *testing.T) {
 	})
 
 }
+
+func TestTrieDB_ClosestDescendantMerkleValue(t *testing.T) {
+	entries := map[string][]byte{
+		"no":        bytes.Repeat([]byte{1}, 40),
+		"noot":      {2},
+		"not":       {3},
+		"dimartiro": {4},
+	}
+
+	inMemoryTrie := inmemory.NewEmptyTrie()
+	inMemoryTrie.SetVersion(trie.V1)
+	db := NewMemoryDB[hash.H256, runtime.BlakeTwo256](EmptyNode)
+	trieDB := NewEmptyTrieDB[hash.H256, runtime.BlakeTwo256](db)
+	trieDB.SetVersion(trie.V1)
+	for k, v := range entries {
+		require.NoError(t, inMemoryTrie.Put([]byte(k), v))
+		require.NoError(t, trieDB.Put([]byte(k), v))
+	}
+
+	// 'd' is 0x64 and 'n' is 0x6e so the root branch has the partial key 6,
+	// with the "dimartiro" leaf at index 4 and the "no" branch at index 0xe.
+	rootNode := inMemoryTrie.RootNode()
+	rootMerkleValue, err := rootNode.CalculateRootMerkleValue()
+	require.NoError(t, err)
+	dimartiroMerkleValue, err := rootNode.Children[4].CalculateMerkleValue()
+	require.NoError(t, err)
+	noMerkleValue, err := rootNode.Children[0xe].CalculateMerkleValue()
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		key         []byte
+		merkleValue []byte
+	}{
+		"empty_key":     {key: []byte{}, merkleValue: rootMerkleValue},
+		"leaf_prefix":   {key: []byte("d"), merkleValue: dimartiroMerkleValue},
+		"inline_leaf":   {key: []byte("dim"), merkleValue: dimartiroMerkleValue},
+		"hashed_branch": {key: []byte("n"), merkleValue: noMerkleValue},
+		"exact_key":     {key: []byte("no"), merkleValue: noMerkleValue},
+		"no_descendant": {key: []byte("x"), merkleValue: nil},
+		"no_child":      {key: []byte("nop"), merkleValue: nil},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			merkleValue, err := trieDB.ClosestDescendantMerkleValue(testCase.key)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.merkleValue, merkleValue)
+		})
+	}
+}
@@ -5,38 +5,41 @@
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	cfg "github.com/ChainSafe/gossamer/config"
+	"github.com/ChainSafe/gossamer/dot/rpc/modules"
+	"github.com/ChainSafe/gossamer/lib/common"
 	libutils "github.com/ChainSafe/gossamer/lib/utils"
 	"github.com/ChainSafe/gossamer/tests/utils/config"
 	"github.com/ChainSafe/gossamer/tests/utils/node"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestEngineRPC(t *testing.T) { //nolint:tparallel
-	t.SkipNow()
-
+func TestEngineRPC(t *testing.T) {
 	genesisPath := libutils.GetWestendDevRawGenesisPath(t)
 	tomlConfig := config.Default()
 	tomlConfig.ChainSpec = genesisPath
+	tomlConfig.Core.GrandpaAuthority = false
+	tomlConfig.Core.Sealing = cfg.ManualSealing
+	tomlConfig.RPC.Modules = append(tomlConfig.RPC.Modules, "engine")
 	node := node.New(t, tomlConfig)
 	ctx, cancel := context.WithCancel(context.Background())
 	node.InitAndStartTest(ctx, t, cancel)
 
-	t.Run("engine_createBlock", func(t *testing.T) {
-		t.Parallel()
-
-		var response struct{} // TODO
-		fetchWithTimeout(ctx, t, "engine_createBlock", "", &response)
-
-		// TODO assert response
-	})
-
-	t.Run("engine_finalizeBlock", func(t *testing.T) {
-		t.Parallel()
+	var created modules.EngineCreateBlockResponse
+	fetchWithTimeout(ctx, t, "engine_createBlock", "[true, false]", &created)
+	assert.NotEqual(t, common.Hash{}, created.Hash)
+	assert.True(t, created.Aux.IsNewBest)
 
-		var response struct{} // TODO
-		fetchWithTimeout(ctx, t, "engine_finalizeBlock", "", &response)
+	var finalised bool
+	params := fmt.Sprintf(`["%s"]`, created.Hash)
+	fetchWithTimeout(ctx, t, "engine_finalizeBlock", params, &finalised)
+	require.True(t, finalised)
 
-		// TODO assert response
-	})
+	var finalisedHead common.Hash
+	fetchWithTimeout(ctx, t, "chain_getFinalizedHead", "[]", &finalisedHead)
+	assert.Equal(t, created.Hash, finalisedHead)
 }
@@ -7,17 +7,20 @@
 	"context"
 	"testing"
 
+	"github.com/ChainSafe/gossamer/lib/babe"
+	"github.com/ChainSafe/gossamer/lib/keystore"
 	libutils "github.com/ChainSafe/gossamer/lib/utils"
 	"github.com/ChainSafe/gossamer/tests/utils/config"
 	"github.com/ChainSafe/gossamer/tests/utils/node"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBabeRPC(t *testing.T) { //nolint:tparallel
-	t.SkipNow() // TODO
-
 	genesisPath := libutils.GetWestendDevRawGenesisPath(t)
 	tomlConfig := config.Default()
 	tomlConfig.ChainSpec = genesisPath
+	tomlConfig.Account.Key = config.AliceKey
+	tomlConfig.RPC.Modules = append(tomlConfig.RPC.Modules, "babe")
 	node := node.New(t, tomlConfig)
 	ctx, cancel := context.WithCancel(context.Background())
 	node.InitAndStartTest(ctx, t, cancel)
@@ -25,10 +28,15 @@ func TestBabeRPC(t *testing.T) { //nolint:tparallel
 	t.Run("babe_epochAuthorship", func(t *testing.T) {
 		t.Parallel()
 
-		var response struct{} // TODO
+		kr, err := keystore.NewSr25519Keyring()
+		require.NoError(t, err)
 
+		var response map[string]*babe.EpochAuthorship
 		fetchWithTimeout(ctx, t, "babe_epochAuthorship", "", &response)
 
-		// TODO assert response
+		authorship, ok := response[string(kr.Alice().Public().Address())]
+		require.True(t, ok)
+		slots := len(authorship.Primary) + len(authorship.Secondary) + len(authorship.SecondaryVRF)
+		require.NotZero(t, slots)
 	})
 }